@@ -0,0 +1,303 @@
+// Copyright 2024 KusionStack Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v1
+
+import "reflect"
+
+// DeepCopy returns a copy of the workspace, which shares no maps, slices or pointers with the original,
+// so that the copy can be mutated in merges and caches safely.
+func (w *Workspace) DeepCopy() *Workspace {
+	if w == nil {
+		return nil
+	}
+	out := &Workspace{
+		Name:        w.Name,
+		Modules:     w.Modules.DeepCopy(),
+		SecretStore: w.SecretStore.DeepCopy(),
+		Context:     w.Context.DeepCopy(),
+	}
+	if w.Backends != nil {
+		out.Backends = make(map[string]*BackendConfig, len(w.Backends))
+		for name, backend := range w.Backends {
+			out.Backends[name] = backend.DeepCopy()
+		}
+	}
+	if w.Profiles != nil {
+		out.Profiles = make(map[string]*Profile, len(w.Profiles))
+		for name, profile := range w.Profiles {
+			out.Profiles[name] = profile.DeepCopy()
+		}
+	}
+	if w.Quotas != nil {
+		quotas := *w.Quotas
+		out.Quotas = &quotas
+	}
+	return out
+}
+
+// DeepCopy returns a copy of the profile, which shares no mutable data with the original.
+func (p *Profile) DeepCopy() *Profile {
+	if p == nil {
+		return nil
+	}
+	return &Profile{
+		Modules: p.Modules.DeepCopy(),
+		Context: p.Context.DeepCopy(),
+	}
+}
+
+// DeepCopy returns a copy of the backend config, which shares no mutable data with the original.
+func (b *BackendConfig) DeepCopy() *BackendConfig {
+	if b == nil {
+		return nil
+	}
+	return &BackendConfig{
+		Type:    b.Type,
+		Configs: deepCopyMap(b.Configs),
+	}
+}
+
+// DeepCopy returns a copy of the module configs, which shares no mutable data with the original.
+func (m ModuleConfigs) DeepCopy() ModuleConfigs {
+	if m == nil {
+		return nil
+	}
+	out := make(ModuleConfigs, len(m))
+	for name, cfg := range m {
+		out[name] = cfg.DeepCopy()
+	}
+	return out
+}
+
+// DeepCopy returns a copy of the module config, where the default and patcher blocks are copied deeply.
+func (m *ModuleConfig) DeepCopy() *ModuleConfig {
+	if m == nil {
+		return nil
+	}
+	out := &ModuleConfig{
+		Path:    m.Path,
+		Version: m.Version,
+		Configs: Configs{
+			Default: m.Configs.Default.DeepCopy(),
+		},
+	}
+	if m.Configs.ModulePatcherConfigs != nil {
+		out.Configs.ModulePatcherConfigs = make(ModulePatcherConfigs, len(m.Configs.ModulePatcherConfigs))
+		for name, patcher := range m.Configs.ModulePatcherConfigs {
+			out.Configs.ModulePatcherConfigs[name] = patcher.DeepCopy()
+		}
+	}
+	return out
+}
+
+// DeepCopy returns a copy of the patcher block, which shares no mutable data with the original.
+func (m *ModulePatcherConfig) DeepCopy() *ModulePatcherConfig {
+	if m == nil {
+		return nil
+	}
+	out := &ModulePatcherConfig{
+		GenericConfig: m.GenericConfig.DeepCopy(),
+	}
+	if m.ProjectSelector != nil {
+		out.ProjectSelector = append([]string{}, m.ProjectSelector...)
+	}
+	return out
+}
+
+// DeepCopy returns a copy of the generic config, where the nested maps and slices are copied recursively.
+func (c GenericConfig) DeepCopy() GenericConfig {
+	if c == nil {
+		return nil
+	}
+	return GenericConfig(deepCopyMap(c))
+}
+
+// DeepCopy returns a copy of the spec, which shares no mutable data with the original.
+func (s *Spec) DeepCopy() *Spec {
+	if s == nil {
+		return nil
+	}
+	return &Spec{
+		Resources:   s.Resources.DeepCopy(),
+		SecretStore: s.SecretStore.DeepCopy(),
+		Context:     s.Context.DeepCopy(),
+	}
+}
+
+// DeepCopy returns a copy of the state, which shares no mutable data with the original.
+func (s *State) DeepCopy() *State {
+	if s == nil {
+		return nil
+	}
+	return &State{
+		Resources: s.Resources.DeepCopy(),
+	}
+}
+
+// DeepCopy returns a copy of the resources, where each resource is copied deeply. Unlike
+// Resource.DeepCopy, the concrete types of the attributes are kept.
+func (rs Resources) DeepCopy() Resources {
+	if rs == nil {
+		return nil
+	}
+	out := make(Resources, len(rs))
+	for i := range rs {
+		out[i] = Resource{
+			ID:         rs[i].ID,
+			Type:       rs[i].Type,
+			Attributes: deepCopyMap(rs[i].Attributes),
+			Extensions: deepCopyMap(rs[i].Extensions),
+		}
+		if rs[i].DependsOn != nil {
+			out[i].DependsOn = append([]string{}, rs[i].DependsOn...)
+		}
+	}
+	return out
+}
+
+// DeepCopy returns a copy of the release, where the Spec, State and the other maps and pointers are copied
+// deeply.
+func (r *Release) DeepCopy() *Release {
+	if r == nil {
+		return nil
+	}
+	out := *r
+	out.Spec = r.Spec.DeepCopy()
+	out.State = r.State.DeepCopy()
+	if r.Encryption != nil {
+		encryption := *r.Encryption
+		out.Encryption = &encryption
+	}
+	if r.Unlock != nil {
+		unlock := *r.Unlock
+		out.Unlock = &unlock
+	}
+	if r.Holder != nil {
+		holder := *r.Holder
+		out.Holder = &holder
+	}
+	out.Metadata = copyStringMap(r.Metadata)
+	if r.Conditions != nil {
+		out.Conditions = append([]ReleaseCondition{}, r.Conditions...)
+	}
+	if r.ModuleOutputs != nil {
+		out.ModuleOutputs = make(map[string]*ModuleOutput, len(r.ModuleOutputs))
+		for key, output := range r.ModuleOutputs {
+			if output == nil {
+				out.ModuleOutputs[key] = nil
+				continue
+			}
+			copied := *output
+			if output.Resources != nil {
+				copied.Resources = append([]string{}, output.Resources...)
+			}
+			out.ModuleOutputs[key] = &copied
+		}
+	}
+	return &out
+}
+
+func deepCopyMap(m map[string]any) map[string]any {
+	if m == nil {
+		return nil
+	}
+	out := make(map[string]any, len(m))
+	for k, v := range m {
+		out[k] = deepCopyValue(v)
+	}
+	return out
+}
+
+// deepCopyValue returns a copy of the value decoded from yaml or json, or built by the generators, where
+// the maps, slices, arrays and pointers are copied recursively, and the other values are returned as
+// they are.
+func deepCopyValue(v any) any {
+	switch t := v.(type) {
+	case nil, string, bool, int, int32, int64, float32, float64:
+		return t
+	case map[string]any:
+		return deepCopyMap(t)
+	case GenericConfig:
+		return t.DeepCopy()
+	case []any:
+		if t == nil {
+			return t
+		}
+		out := make([]any, len(t))
+		for i, item := range t {
+			out[i] = deepCopyValue(item)
+		}
+		return out
+	default:
+		return deepCopyReflect(reflect.ValueOf(v)).Interface()
+	}
+}
+
+// deepCopyReflect copies the value of the other types by reflection, where the unexported fields of the
+// structs are copied shallowly.
+func deepCopyReflect(v reflect.Value) reflect.Value {
+	switch v.Kind() {
+	case reflect.Map:
+		if v.IsNil() {
+			return v
+		}
+		out := reflect.MakeMapWithSize(v.Type(), v.Len())
+		iter := v.MapRange()
+		for iter.Next() {
+			out.SetMapIndex(iter.Key(), deepCopyReflect(iter.Value()))
+		}
+		return out
+	case reflect.Slice:
+		if v.IsNil() {
+			return v
+		}
+		out := reflect.MakeSlice(v.Type(), v.Len(), v.Len())
+		for i := 0; i < v.Len(); i++ {
+			out.Index(i).Set(deepCopyReflect(v.Index(i)))
+		}
+		return out
+	case reflect.Array:
+		out := reflect.New(v.Type()).Elem()
+		for i := 0; i < v.Len(); i++ {
+			out.Index(i).Set(deepCopyReflect(v.Index(i)))
+		}
+		return out
+	case reflect.Pointer:
+		if v.IsNil() {
+			return v
+		}
+		out := reflect.New(v.Type().Elem())
+		out.Elem().Set(deepCopyReflect(v.Elem()))
+		return out
+	case reflect.Interface:
+		if v.IsNil() {
+			return v
+		}
+		out := reflect.New(v.Type()).Elem()
+		out.Set(deepCopyReflect(v.Elem()))
+		return out
+	case reflect.Struct:
+		out := reflect.New(v.Type()).Elem()
+		out.Set(v)
+		for i := 0; i < v.NumField(); i++ {
+			if out.Field(i).CanSet() {
+				out.Field(i).Set(deepCopyReflect(v.Field(i)))
+			}
+		}
+		return out
+	default:
+		return v
+	}
+}
@@ -0,0 +1,144 @@
+package v1
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func mockDeepCopyWorkspace() *Workspace {
+	ws := mockWorkspaceWithProfiles()
+	ws.Modules["mysql"].Configs.Default["labels"] = map[string]any{"tier": "db"}
+	ws.Modules["mysql"].Configs.Default["zones"] = []any{"a", map[string]any{"name": "b"}}
+	ws.Modules["mysql"].Configs.Default["ports"] = []int{3306}
+	ws.SecretStore = &SecretStore{Provider: &ProviderSpec{AWS: &AWSProvider{Region: "us-east-1"}}}
+	ws.Backends = map[string]*BackendConfig{
+		"oss": {Type: "oss", Configs: map[string]any{"bucket": "kusion", "tags": []string{"a"}}},
+	}
+	ws.Quotas = &Quotas{MaxResources: 10}
+	return ws
+}
+
+func mockDeepCopyResources() Resources {
+	return Resources{
+		{
+			ID:   "apps/v1:Deployment:default:foo",
+			Type: Kubernetes,
+			Attributes: map[string]any{
+				"spec": map[string]any{
+					"replicas": 1,
+					"containers": []any{
+						map[string]any{"name": "foo", "ports": []any{80}},
+					},
+				},
+			},
+			DependsOn:  []string{"v1:Namespace:default"},
+			Extensions: map[string]any{"kusion.io/module": "service"},
+		},
+	}
+}
+
+func mockDeepCopyRelease() *Release {
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	return &Release{
+		Project:   "foo",
+		Workspace: "dev",
+		Revision:  1,
+		Stack:     "dev",
+		Spec: &Spec{
+			Resources:   mockDeepCopyResources(),
+			SecretStore: &SecretStore{Provider: &ProviderSpec{AWS: &AWSProvider{Region: "us-east-1"}}},
+			Context:     GenericConfig{"region": "us-east-1"},
+		},
+		State:         &State{Resources: mockDeepCopyResources()},
+		Phase:         ReleasePhaseSucceeded,
+		CreateTime:    now,
+		ModifiedTime:  now,
+		Encryption:    &EncryptedData{KeyID: "key"},
+		Unlock:        &ReleaseUnlock{Operator: "admin", Time: now},
+		Holder:        &ReleaseHolder{Host: "localhost", PID: 1},
+		Metadata:      map[string]string{ReleaseMetadataGitCommit: "abc"},
+		ModuleOutputs: map[string]*ModuleOutput{"foo/service@v1": {Hash: "hash", Resources: []string{"id: foo"}}},
+	}
+}
+
+func TestWorkspace_DeepCopy(t *testing.T) {
+	original := mockDeepCopyWorkspace()
+	copied := original.DeepCopy()
+	assert.Equal(t, original, copied)
+
+	mysql := copied.Modules["mysql"]
+	mysql.Version = "0.2.0"
+	mysql.Configs.Default["type"] = "alicloud"
+	mysql.Configs.Default["labels"].(map[string]any)["tier"] = "cache"
+	mysql.Configs.Default["zones"].([]any)[1].(map[string]any)["name"] = "c"
+	mysql.Configs.Default["ports"].([]int)[0] = 3307
+	mysql.Configs.ModulePatcherConfigs["smallClass"].GenericConfig["instanceType"] = "db.t3.large"
+	mysql.Configs.ModulePatcherConfigs["smallClass"].ProjectSelector[0] = "bar"
+	copied.Modules["redis"] = &ModuleConfig{}
+	copied.Context["region"] = "us-west-2"
+	copied.SecretStore.Provider.AWS.Region = "us-west-2"
+	copied.Backends["oss"].Configs["bucket"] = "other"
+	copied.Backends["oss"].Configs["tags"].([]string)[0] = "b"
+	copied.Profiles["prod"].Modules["mysql"].Configs.Default["instanceType"] = "db.m5.xlarge"
+	copied.Profiles["prod"].Context["kubeconfig"] = "/etc/other.yaml"
+	copied.Quotas.MaxResources = 20
+
+	assert.Equal(t, mockDeepCopyWorkspace(), original)
+	assert.Nil(t, (*Workspace)(nil).DeepCopy())
+}
+
+func TestModuleConfig_DeepCopy(t *testing.T) {
+	original := mockDeepCopyWorkspace().Modules["mysql"]
+	copied := original.DeepCopy()
+	assert.Equal(t, original, copied)
+
+	copied.Configs.Default["labels"].(map[string]any)["tier"] = "cache"
+	copied.Configs.ModulePatcherConfigs["smallClass"].ProjectSelector = append(copied.Configs.ModulePatcherConfigs["smallClass"].ProjectSelector, "bar")
+	assert.Equal(t, mockDeepCopyWorkspace().Modules["mysql"], original)
+	assert.Nil(t, (*ModuleConfig)(nil).DeepCopy())
+}
+
+func TestSpecAndState_DeepCopy(t *testing.T) {
+	original := mockDeepCopyRelease().Spec
+	copied := original.DeepCopy()
+	assert.Equal(t, original, copied)
+	// the concrete types of the attributes are kept
+	assert.IsType(t, 1, copied.Resources[0].Attributes["spec"].(map[string]any)["replicas"])
+
+	spec := copied.Resources[0].Attributes["spec"].(map[string]any)
+	spec["replicas"] = 2
+	spec["containers"].([]any)[0].(map[string]any)["name"] = "bar"
+	copied.Resources[0].DependsOn[0] = "v1:Namespace:other"
+	copied.Resources[0].Extensions["kusion.io/module"] = "job"
+	copied.Resources = append(copied.Resources, Resource{ID: "bar"})
+	copied.Context["region"] = "us-west-2"
+	assert.Equal(t, mockDeepCopyRelease().Spec, original)
+
+	state := mockDeepCopyRelease().State
+	copiedState := state.DeepCopy()
+	copiedState.Resources[0].Attributes["spec"].(map[string]any)["replicas"] = 3
+	assert.Equal(t, mockDeepCopyRelease().State, state)
+	assert.Nil(t, (*Spec)(nil).DeepCopy())
+	assert.Nil(t, (*State)(nil).DeepCopy())
+}
+
+func TestRelease_DeepCopy(t *testing.T) {
+	original := mockDeepCopyRelease()
+	copied := original.DeepCopy()
+	assert.Equal(t, original, copied)
+
+	copied.Spec.Resources[0].ID = "changed"
+	copied.State.Resources[0].Attributes["spec"].(map[string]any)["replicas"] = 3
+	copied.Encryption.KeyID = "other"
+	copied.Unlock.Operator = "other"
+	copied.Holder.PID = 2
+	copied.Metadata[ReleaseMetadataGitCommit] = "def"
+	copied.ModuleOutputs["foo/service@v1"].Resources[0] = "id: bar"
+	copied.ModuleOutputs["foo/job@v1"] = &ModuleOutput{}
+	copied.ModifiedTime = time.Now()
+
+	assert.Equal(t, mockDeepCopyRelease(), original)
+	assert.Nil(t, (*Release)(nil).DeepCopy())
+}
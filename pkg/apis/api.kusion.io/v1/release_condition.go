@@ -0,0 +1,85 @@
+// Copyright 2024 KusionStack Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v1
+
+import "time"
+
+// ReleaseConditionType is the type of a ReleaseCondition.
+type ReleaseConditionType string
+
+const (
+	// ReleaseConditionApplied indicates whether the resources of the Release are applied.
+	ReleaseConditionApplied ReleaseConditionType = "Applied"
+
+	// ReleaseConditionDestroyed indicates whether the resources of the Release are destroyed.
+	ReleaseConditionDestroyed ReleaseConditionType = "Destroyed"
+)
+
+// ConditionStatus is the status of a ReleaseCondition.
+type ConditionStatus string
+
+const (
+	ConditionTrue    ConditionStatus = "True"
+	ConditionFalse   ConditionStatus = "False"
+	ConditionUnknown ConditionStatus = "Unknown"
+)
+
+// ReleaseCondition is an observation of the progress or a problem of a Release, like the Kubernetes
+// conditions.
+type ReleaseCondition struct {
+	// Type of the condition.
+	Type ReleaseConditionType `yaml:"type" json:"type"`
+
+	// Status of the condition, one of True, False and Unknown.
+	Status ConditionStatus `yaml:"status" json:"status"`
+
+	// Reason is a brief CamelCase reason of the last transition of the condition.
+	Reason string `yaml:"reason,omitempty" json:"reason,omitempty"`
+
+	// Message is a human-readable message of the details of the last transition.
+	Message string `yaml:"message,omitempty" json:"message,omitempty"`
+
+	// LastTransitionTime is the time the condition changed from one status to another.
+	LastTransitionTime time.Time `yaml:"lastTransitionTime" json:"lastTransitionTime"`
+}
+
+// SetCondition adds the condition to the Release, or updates the existing one of the same type. The
+// LastTransitionTime is kept if the status is unchanged, otherwise it is set to the one of the condition,
+// or now if the condition has none.
+func (r *Release) SetCondition(condition ReleaseCondition) {
+	if condition.LastTransitionTime.IsZero() {
+		condition.LastTransitionTime = time.Now()
+	}
+	existing := r.GetCondition(condition.Type)
+	if existing == nil {
+		r.Conditions = append(r.Conditions, condition)
+		return
+	}
+	if existing.Status == condition.Status {
+		condition.LastTransitionTime = existing.LastTransitionTime
+	}
+	*existing = condition
+}
+
+// GetCondition returns the condition of the type, or nil if the Release has no such condition. The
+// returned condition points into the Release.
+func (r *Release) GetCondition(conditionType ReleaseConditionType) *ReleaseCondition {
+	for i := range r.Conditions {
+		if r.Conditions[i].Type == conditionType {
+			return &r.Conditions[i]
+		}
+	}
+	return nil
+}
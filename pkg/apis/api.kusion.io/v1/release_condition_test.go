@@ -0,0 +1,72 @@
+package v1
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
+)
+
+func TestRelease_SetCondition(t *testing.T) {
+	start := time.Date(2024, 5, 10, 16, 48, 0, 0, time.UTC)
+	r := &Release{}
+	assert.Nil(t, r.GetCondition(ReleaseConditionApplied))
+
+	// insert
+	r.SetCondition(ReleaseCondition{
+		Type: ReleaseConditionApplied, Status: ConditionUnknown, Reason: "Applying", LastTransitionTime: start,
+	})
+	require.Len(t, r.Conditions, 1)
+	assert.Equal(t, ReleaseCondition{
+		Type: ReleaseConditionApplied, Status: ConditionUnknown, Reason: "Applying", LastTransitionTime: start,
+	}, *r.GetCondition(ReleaseConditionApplied))
+
+	// update with the same status, the reason and message are updated but the transition time is kept
+	r.SetCondition(ReleaseCondition{
+		Type: ReleaseConditionApplied, Status: ConditionUnknown, Reason: "Applying", Message: "2/3 resources applied",
+	})
+	require.Len(t, r.Conditions, 1)
+	assert.Equal(t, "2/3 resources applied", r.GetCondition(ReleaseConditionApplied).Message)
+	assert.Equal(t, start, r.GetCondition(ReleaseConditionApplied).LastTransitionTime)
+
+	// update with a different status, the transition time changes
+	r.SetCondition(ReleaseCondition{Type: ReleaseConditionApplied, Status: ConditionFalse, Reason: "Failed"})
+	require.Len(t, r.Conditions, 1)
+	applied := r.GetCondition(ReleaseConditionApplied)
+	assert.Equal(t, ConditionFalse, applied.Status)
+	assert.Equal(t, "Failed", applied.Reason)
+	assert.Empty(t, applied.Message)
+	assert.True(t, applied.LastTransitionTime.After(start))
+
+	// another type is appended
+	r.SetCondition(ReleaseCondition{Type: ReleaseConditionDestroyed, Status: ConditionTrue, LastTransitionTime: start})
+	require.Len(t, r.Conditions, 2)
+	assert.Equal(t, ConditionTrue, r.GetCondition(ReleaseConditionDestroyed).Status)
+}
+
+func TestReleaseCondition_Serialization(t *testing.T) {
+	r := &Release{}
+	r.SetCondition(ReleaseCondition{
+		Type:               ReleaseConditionApplied,
+		Status:             ConditionTrue,
+		Reason:             "Succeeded",
+		Message:            "all resources applied",
+		LastTransitionTime: time.Date(2024, 5, 10, 16, 48, 0, 0, time.UTC),
+	})
+
+	data, err := yaml.Marshal(r)
+	require.NoError(t, err)
+	actual := &Release{}
+	require.NoError(t, yaml.Unmarshal(data, actual))
+	assert.Equal(t, r.Conditions, actual.Conditions)
+
+	data, err = json.Marshal(r)
+	require.NoError(t, err)
+	actual = &Release{}
+	require.NoError(t, json.Unmarshal(data, actual))
+	assert.Equal(t, r.Conditions, actual.Conditions)
+	assert.Contains(t, string(data), `"lastTransitionTime":"2024-05-10T16:48:00Z"`)
+}
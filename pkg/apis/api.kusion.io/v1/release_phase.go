@@ -0,0 +1,67 @@
+// Copyright 2024 KusionStack Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v1
+
+// releasePhaseTransitions are the phases each non-terminal phase can transition to. A Release is
+// generated, previewed, and then applied or destroyed; it may succeed directly after previewing if
+// there is nothing to change, and may fail in any non-terminal phase.
+var releasePhaseTransitions = map[ReleasePhase][]ReleasePhase{
+	ReleasePhaseGenerating: {ReleasePhasePreviewing, ReleasePhaseFailed},
+	ReleasePhasePreviewing: {ReleasePhaseApplying, ReleasePhaseDestroying, ReleasePhaseSucceeded, ReleasePhaseFailed},
+	ReleasePhaseApplying:   {ReleasePhaseSucceeded, ReleasePhaseFailed},
+	ReleasePhaseDestroying: {ReleasePhaseSucceeded, ReleasePhaseFailed},
+}
+
+// releasePhaseNames are the human-readable names of the phases.
+var releasePhaseNames = map[ReleasePhase]string{
+	ReleasePhaseGenerating: "Generating",
+	ReleasePhasePreviewing: "Previewing",
+	ReleasePhaseApplying:   "Applying",
+	ReleasePhaseDestroying: "Destroying",
+	ReleasePhaseSucceeded:  "Succeeded",
+	ReleasePhaseFailed:     "Failed",
+}
+
+// IsTerminal returns true if the phase is a final one, i.e. succeeded or failed, after which the Release
+// is never changed.
+func (p ReleasePhase) IsTerminal() bool {
+	return p == ReleasePhaseSucceeded || p == ReleasePhaseFailed
+}
+
+// CanTransitionTo returns true if a Release in the phase can move to the next phase. Staying in the same
+// phase is not a transition, and a terminal phase can transition to nothing.
+func (p ReleasePhase) CanTransitionTo(next ReleasePhase) bool {
+	for _, phase := range releasePhaseTransitions[p] {
+		if phase == next {
+			return true
+		}
+	}
+	return false
+}
+
+// String returns the human-readable name of the phase, e.g. Applying, or the raw value if the phase is
+// unknown.
+func (p ReleasePhase) String() string {
+	if name, ok := releasePhaseNames[p]; ok {
+		return name
+	}
+	return string(p)
+}
+
+// IsActive returns true if the Release is in a non-terminal phase, i.e. its operation is in progress or
+// was left unfinished by a crash.
+func (r *Release) IsActive() bool {
+	return !r.Phase.IsTerminal()
+}
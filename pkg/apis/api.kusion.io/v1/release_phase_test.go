@@ -0,0 +1,64 @@
+package v1
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReleasePhase_IsTerminal(t *testing.T) {
+	assert.True(t, ReleasePhaseSucceeded.IsTerminal())
+	assert.True(t, ReleasePhaseFailed.IsTerminal())
+	assert.False(t, ReleasePhaseGenerating.IsTerminal())
+	assert.False(t, ReleasePhasePreviewing.IsTerminal())
+	assert.False(t, ReleasePhaseApplying.IsTerminal())
+	assert.False(t, ReleasePhaseDestroying.IsTerminal())
+}
+
+func TestReleasePhase_CanTransitionTo(t *testing.T) {
+	testcases := []struct {
+		from, to ReleasePhase
+		legal    bool
+	}{
+		{from: ReleasePhaseGenerating, to: ReleasePhasePreviewing, legal: true},
+		{from: ReleasePhaseGenerating, to: ReleasePhaseFailed, legal: true},
+		{from: ReleasePhasePreviewing, to: ReleasePhaseApplying, legal: true},
+		{from: ReleasePhasePreviewing, to: ReleasePhaseDestroying, legal: true},
+		{from: ReleasePhasePreviewing, to: ReleasePhaseSucceeded, legal: true},
+		{from: ReleasePhaseApplying, to: ReleasePhaseSucceeded, legal: true},
+		{from: ReleasePhaseApplying, to: ReleasePhaseFailed, legal: true},
+		{from: ReleasePhaseDestroying, to: ReleasePhaseSucceeded, legal: true},
+		{from: ReleasePhaseDestroying, to: ReleasePhaseFailed, legal: true},
+
+		{from: ReleasePhaseGenerating, to: ReleasePhaseApplying},
+		{from: ReleasePhaseGenerating, to: ReleasePhaseSucceeded},
+		{from: ReleasePhasePreviewing, to: ReleasePhaseGenerating},
+		{from: ReleasePhaseApplying, to: ReleasePhasePreviewing},
+		{from: ReleasePhaseApplying, to: ReleasePhaseDestroying},
+		{from: ReleasePhaseApplying, to: ReleasePhaseApplying},
+		{from: ReleasePhaseSucceeded, to: ReleasePhaseFailed},
+		{from: ReleasePhaseFailed, to: ReleasePhaseSucceeded},
+		{from: ReleasePhaseFailed, to: ReleasePhaseGenerating},
+		{from: "unknown", to: ReleasePhaseFailed},
+		{from: ReleasePhaseApplying, to: "unknown"},
+	}
+
+	for _, tc := range testcases {
+		t.Run(string(tc.from)+" to "+string(tc.to), func(t *testing.T) {
+			assert.Equal(t, tc.legal, tc.from.CanTransitionTo(tc.to))
+		})
+	}
+}
+
+func TestReleasePhase_String(t *testing.T) {
+	assert.Equal(t, "Applying", ReleasePhaseApplying.String())
+	assert.Equal(t, "Succeeded", ReleasePhaseSucceeded.String())
+	assert.Equal(t, "unknown", ReleasePhase("unknown").String())
+}
+
+func TestRelease_IsActive(t *testing.T) {
+	assert.True(t, (&Release{Phase: ReleasePhaseGenerating}).IsActive())
+	assert.True(t, (&Release{Phase: ReleasePhaseApplying}).IsActive())
+	assert.False(t, (&Release{Phase: ReleasePhaseSucceeded}).IsActive())
+	assert.False(t, (&Release{Phase: ReleasePhaseFailed}).IsActive())
+}
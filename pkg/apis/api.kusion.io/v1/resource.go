@@ -15,15 +15,94 @@
 package v1
 
 import (
+	"bytes"
+	"encoding/json"
 	"fmt"
+	"reflect"
+	"sort"
+	"strings"
 
 	jsoniter "github.com/json-iterator/go"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 )
 
 func (r *Resource) ResourceKey() string {
 	return r.ID
 }
 
+// GVK returns the GVK of the Kubernetes resource in the extension ResourceExtensionGVK, which is in the
+// format of schema.GroupVersionKind.String(), e.g. "apps/v1, Kind=Deployment". It returns false if
+// the extension is absent or invalid.
+func (r *Resource) GVK() (schema.GroupVersionKind, bool) {
+	s, ok := r.Extensions[ResourceExtensionGVK].(string)
+	if !ok {
+		return schema.GroupVersionKind{}, false
+	}
+	gv, kind, found := strings.Cut(s, ", Kind=")
+	if !found || kind == "" {
+		return schema.GroupVersionKind{}, false
+	}
+	group, version, found := strings.Cut(gv, "/")
+	if !found {
+		group, version = "", gv
+	}
+	if version == "" {
+		return schema.GroupVersionKind{}, false
+	}
+	return schema.GroupVersionKind{Group: group, Version: version, Kind: kind}, true
+}
+
+// SetGVK sets the GVK of the Kubernetes resource in the extension ResourceExtensionGVK.
+func (r *Resource) SetGVK(gvk schema.GroupVersionKind) {
+	r.setExtension(ResourceExtensionGVK, gvk.String())
+}
+
+// KubeConfigPath returns the path of the kubeConfig of the Kubernetes resource in the extension
+// ResourceExtensionKubeConfig. It returns false if the extension is absent or empty.
+func (r *Resource) KubeConfigPath() (string, bool) {
+	path, ok := r.Extensions[ResourceExtensionKubeConfig].(string)
+	return path, ok && path != ""
+}
+
+// SetKubeConfigPath sets the path of the kubeConfig of the Kubernetes resource in the extension
+// ResourceExtensionKubeConfig.
+func (r *Resource) SetKubeConfigPath(path string) {
+	r.setExtension(ResourceExtensionKubeConfig, path)
+}
+
+// Module returns the module generating the resource in the extension ResourceExtensionModule, e.g.
+// "kusionstack/mysql@v0.1.0". It returns false if the resource is not generated by a module, such as
+// the ones generated by Kusion itself.
+func (r *Resource) Module() (string, bool) {
+	module, ok := r.Extensions[ResourceExtensionModule].(string)
+	return module, ok && module != ""
+}
+
+// SetModule sets the module generating the resource in the extension ResourceExtensionModule.
+func (r *Resource) SetModule(module string) {
+	r.setExtension(ResourceExtensionModule, module)
+}
+
+// AttributeTemplateEnabled returns whether the templates in the string attribute values of the resource
+// are rendered at generation, which is opted in by the extension ResourceExtensionAttributeTemplate.
+func (r *Resource) AttributeTemplateEnabled() bool {
+	enabled, ok := r.Extensions[ResourceExtensionAttributeTemplate].(bool)
+	return ok && enabled
+}
+
+// EnableAttributeTemplate opts in rendering the templates in the string attribute values of the resource
+// at generation by the extension ResourceExtensionAttributeTemplate.
+func (r *Resource) EnableAttributeTemplate() {
+	r.setExtension(ResourceExtensionAttributeTemplate, true)
+}
+
+func (r *Resource) setExtension(key string, value interface{}) {
+	if r.Extensions == nil {
+		r.Extensions = make(map[string]interface{})
+	}
+	r.Extensions[key] = value
+}
+
 func (rs Resources) Index() map[string]*Resource {
 	m := make(map[string]*Resource)
 	for i := range rs {
@@ -32,7 +111,8 @@ func (rs Resources) Index() map[string]*Resource {
 	return m
 }
 
-// GVKIndex returns a map of GVK to resources, for now, only Kubernetes resources.
+// GVKIndex returns a map of GVK to resources, for now, only Kubernetes resources. The resources
+// without a valid GVK extension are skipped.
 func (rs Resources) GVKIndex() map[string][]*Resource {
 	m := make(map[string][]*Resource)
 	for i := range rs {
@@ -40,12 +120,54 @@ func (rs Resources) GVKIndex() map[string][]*Resource {
 		if resource.Type != Kubernetes {
 			continue
 		}
-		gvk := resource.Extensions[ResourceExtensionGVK].(string)
-		m[gvk] = append(m[gvk], resource)
+		gvk, ok := resource.GVK()
+		if !ok {
+			continue
+		}
+		m[gvk.String()] = append(m[gvk.String()], resource)
 	}
 	return m
 }
 
+// GroupByModule returns a map of the module to the resources generated by it, where the resources not
+// generated by a module are grouped by the empty string.
+func (rs Resources) GroupByModule() map[string][]*Resource {
+	m := make(map[string][]*Resource)
+	for i := range rs {
+		module, _ := rs[i].Module()
+		m[module] = append(m[module], &rs[i])
+	}
+	return m
+}
+
+// FilterByType returns a new slice of the resources of the type, where the receiver is left unchanged.
+// The resources in the new slice are copied shallowly, which share the attributes and extensions.
+func (rs Resources) FilterByType(t Type) Resources {
+	return rs.filter(func(r *Resource) bool {
+		return r.Type == t
+	})
+}
+
+// FilterByKind returns a new slice of the resources whose kind in the GVK extension is the kind, where the
+// receiver is left unchanged. The resources without the GVK extension are skipped. The resources in the
+// new slice are copied shallowly, which share the attributes and extensions.
+func (rs Resources) FilterByKind(kind string) Resources {
+	return rs.filter(func(r *Resource) bool {
+		gvk, ok := r.GVK()
+		return ok && gvk.Kind == kind
+	})
+}
+
+func (rs Resources) filter(match func(*Resource) bool) Resources {
+	result := Resources{}
+	for i := range rs {
+		if match(&rs[i]) {
+			result = append(result, rs[i])
+		}
+	}
+	return result
+}
+
 func (rs Resources) Len() int      { return len(rs) }
 func (rs Resources) Swap(i, j int) { rs[i], rs[j] = rs[j], rs[i] }
 func (rs Resources) Less(i, j int) bool {
@@ -72,3 +194,89 @@ func (r *Resource) DeepCopy() (*Resource, error) {
 	}
 	return &res, nil
 }
+
+// Equal returns whether the resource is equal to the other one, where the ID, Type, DependsOn and
+// Attributes are compared. The order of DependsOn is insensitive, and the Attributes are compared
+// deeply regardless of the order of the map keys and the concrete type of the numbers, e.g. int 1
+// and float64 1 are equal, which are usually caused by the yaml or json decoding.
+func (r *Resource) Equal(other *Resource) bool {
+	if r == nil || other == nil {
+		return r == other
+	}
+	if r.ID != other.ID || r.Type != other.Type {
+		return false
+	}
+	if !equalDependsOn(r.DependsOn, other.DependsOn) {
+		return false
+	}
+	return equalAttributes(r.Attributes, other.Attributes)
+}
+
+func equalDependsOn(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	sortedA := append([]string(nil), a...)
+	sortedB := append([]string(nil), b...)
+	sort.Strings(sortedA)
+	sort.Strings(sortedB)
+	for i := range sortedA {
+		if sortedA[i] != sortedB[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func equalAttributes(a, b map[string]interface{}) bool {
+	if len(a) == 0 || len(b) == 0 {
+		return len(a) == len(b)
+	}
+	// the json encoding sorts the map keys and unifies the number types
+	dataA, errA := json.Marshal(a)
+	dataB, errB := json.Marshal(b)
+	if errA != nil || errB != nil {
+		return reflect.DeepEqual(a, b)
+	}
+	return bytes.Equal(dataA, dataB)
+}
+
+// ResourcesDiff is the difference between two Resources, which is grouped by the resource ID.
+type ResourcesDiff struct {
+	// Added are the resources which only exist in the new Resources.
+	Added Resources
+	// Removed are the resources which only exist in the old Resources.
+	Removed Resources
+	// Changed are the resources of the new Resources, which exist in both but are not equal.
+	Changed Resources
+}
+
+// Empty returns whether there is no difference.
+func (d *ResourcesDiff) Empty() bool {
+	return len(d.Added) == 0 && len(d.Removed) == 0 && len(d.Changed) == 0
+}
+
+// Diff returns the difference from rs to the new Resources, the resources are compared by Equal. The
+// resources in Added and Changed keep the order of the new Resources, and the ones in Removed keep the
+// order of rs.
+func (rs Resources) Diff(newResources Resources) *ResourcesDiff {
+	diff := &ResourcesDiff{}
+	oldIndex := rs.Index()
+	newIndex := newResources.Index()
+	for i := range newResources {
+		res := &newResources[i]
+		old, ok := oldIndex[res.ResourceKey()]
+		switch {
+		case !ok:
+			diff.Added = append(diff.Added, *res)
+		case !old.Equal(res):
+			diff.Changed = append(diff.Changed, *res)
+		}
+	}
+	for i := range rs {
+		if _, ok := newIndex[rs[i].ResourceKey()]; !ok {
+			diff.Removed = append(diff.Removed, rs[i])
+		}
+	}
+	return diff
+}
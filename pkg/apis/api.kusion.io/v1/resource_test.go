@@ -0,0 +1,318 @@
+package v1
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+func mockDeploymentResource() Resource {
+	return Resource{
+		ID:   "apps/v1:Deployment:default:foo",
+		Type: Kubernetes,
+		Attributes: map[string]interface{}{
+			"apiVersion": "apps/v1",
+			"kind":       "Deployment",
+			"metadata": map[string]interface{}{
+				"name":      "foo",
+				"namespace": "default",
+			},
+			"spec": map[string]interface{}{
+				"replicas": 1,
+			},
+		},
+		DependsOn: []string{"v1:Namespace:default", "v1:Secret:default:foo"},
+		Extensions: map[string]interface{}{
+			ResourceExtensionGVK: "apps/v1, Kind=Deployment",
+		},
+	}
+}
+
+func TestResource_Equal(t *testing.T) {
+	testcases := []struct {
+		name     string
+		equal    bool
+		modifier func(r *Resource)
+	}{
+		{
+			name:     "equal resource",
+			equal:    true,
+			modifier: func(r *Resource) {},
+		},
+		{
+			name:  "equal resource with reordered dependsOn",
+			equal: true,
+			modifier: func(r *Resource) {
+				r.DependsOn = []string{"v1:Secret:default:foo", "v1:Namespace:default"}
+			},
+		},
+		{
+			name:  "equal resource with decoded number",
+			equal: true,
+			modifier: func(r *Resource) {
+				r.Attributes["spec"] = map[string]interface{}{"replicas": float64(1)}
+			},
+		},
+		{
+			name:  "equal resource with different extensions",
+			equal: true,
+			modifier: func(r *Resource) {
+				r.Extensions = nil
+			},
+		},
+		{
+			name:  "not equal resource with different id",
+			equal: false,
+			modifier: func(r *Resource) {
+				r.ID = "apps/v1:Deployment:default:bar"
+			},
+		},
+		{
+			name:  "not equal resource with different type",
+			equal: false,
+			modifier: func(r *Resource) {
+				r.Type = Terraform
+			},
+		},
+		{
+			name:  "not equal resource with different dependsOn",
+			equal: false,
+			modifier: func(r *Resource) {
+				r.DependsOn = []string{"v1:Namespace:default"}
+			},
+		},
+		{
+			name:  "not equal resource with different nested attribute",
+			equal: false,
+			modifier: func(r *Resource) {
+				r.Attributes["spec"] = map[string]interface{}{"replicas": 2}
+			},
+		},
+		{
+			name:  "not equal resource with missing attribute",
+			equal: false,
+			modifier: func(r *Resource) {
+				delete(r.Attributes, "spec")
+			},
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			r := mockDeploymentResource()
+			other := mockDeploymentResource()
+			tc.modifier(&other)
+			assert.Equal(t, tc.equal, r.Equal(&other))
+			assert.Equal(t, tc.equal, other.Equal(&r))
+		})
+	}
+}
+
+func TestResources_Diff(t *testing.T) {
+	unchanged := mockDeploymentResource()
+	changed := mockDeploymentResource()
+	changed.ID = "apps/v1:Deployment:default:bar"
+	changedNew := changed
+	changedNew.Attributes = map[string]interface{}{"kind": "Deployment"}
+	removed := mockDeploymentResource()
+	removed.ID = "apps/v1:Deployment:default:removed"
+	added := mockDeploymentResource()
+	added.ID = "apps/v1:Deployment:default:added"
+
+	oldResources := Resources{removed, changed, unchanged}
+	newResources := Resources{unchanged, added, changedNew}
+
+	diff := oldResources.Diff(newResources)
+	assert.Equal(t, Resources{added}, diff.Added)
+	assert.Equal(t, Resources{removed}, diff.Removed)
+	assert.Equal(t, Resources{changedNew}, diff.Changed)
+	assert.False(t, diff.Empty())
+
+	// the order of the resources is insensitive
+	assert.True(t, oldResources.Diff(Resources{unchanged, changed, removed}).Empty())
+}
+
+func TestResource_GVK(t *testing.T) {
+	testcases := []struct {
+		name       string
+		extensions map[string]interface{}
+		expected   schema.GroupVersionKind
+		found      bool
+	}{
+		{
+			name:       "apps group",
+			extensions: map[string]interface{}{ResourceExtensionGVK: "apps/v1, Kind=Deployment"},
+			expected:   schema.GroupVersionKind{Group: "apps", Version: "v1", Kind: "Deployment"},
+			found:      true,
+		},
+		{
+			name:       "core group",
+			extensions: map[string]interface{}{ResourceExtensionGVK: "/v1, Kind=Namespace"},
+			expected:   schema.GroupVersionKind{Version: "v1", Kind: "Namespace"},
+			found:      true,
+		},
+		{
+			name:       "core group without slash",
+			extensions: map[string]interface{}{ResourceExtensionGVK: "v1, Kind=Namespace"},
+			expected:   schema.GroupVersionKind{Version: "v1", Kind: "Namespace"},
+			found:      true,
+		},
+		{
+			name:  "absent extensions",
+			found: false,
+		},
+		{
+			name:       "absent gvk",
+			extensions: map[string]interface{}{ResourceExtensionKubeConfig: "/path/to/kubeconfig"},
+			found:      false,
+		},
+		{
+			name:       "invalid gvk",
+			extensions: map[string]interface{}{ResourceExtensionGVK: "apps/v1"},
+			found:      false,
+		},
+		{
+			name:       "not string",
+			extensions: map[string]interface{}{ResourceExtensionGVK: map[string]interface{}{"kind": "Deployment"}},
+			found:      false,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			r := &Resource{Extensions: tc.extensions}
+			gvk, found := r.GVK()
+			assert.Equal(t, tc.found, found)
+			assert.Equal(t, tc.expected, gvk)
+		})
+	}
+}
+
+func TestResource_SetGVK(t *testing.T) {
+	r := &Resource{}
+	gvk := schema.GroupVersionKind{Group: "apps", Version: "v1", Kind: "Deployment"}
+	r.SetGVK(gvk)
+	assert.Equal(t, "apps/v1, Kind=Deployment", r.Extensions[ResourceExtensionGVK])
+
+	actual, found := r.GVK()
+	assert.True(t, found)
+	assert.Equal(t, gvk, actual)
+}
+
+func TestResource_KubeConfigPath(t *testing.T) {
+	r := &Resource{}
+	_, found := r.KubeConfigPath()
+	assert.False(t, found)
+
+	r.Extensions = map[string]interface{}{ResourceExtensionKubeConfig: ""}
+	_, found = r.KubeConfigPath()
+	assert.False(t, found)
+
+	r.SetKubeConfigPath("/path/to/kubeconfig")
+	assert.Equal(t, "/path/to/kubeconfig", r.Extensions[ResourceExtensionKubeConfig])
+	path, found := r.KubeConfigPath()
+	assert.True(t, found)
+	assert.Equal(t, "/path/to/kubeconfig", path)
+}
+
+func TestResource_Module(t *testing.T) {
+	r := &Resource{}
+	_, found := r.Module()
+	assert.False(t, found)
+
+	r.SetModule("kusionstack/mysql@v0.1.0")
+	assert.Equal(t, "kusionstack/mysql@v0.1.0", r.Extensions[ResourceExtensionModule])
+	module, found := r.Module()
+	assert.True(t, found)
+	assert.Equal(t, "kusionstack/mysql@v0.1.0", module)
+}
+
+func TestResource_AttributeTemplateEnabled(t *testing.T) {
+	r := &Resource{}
+	assert.False(t, r.AttributeTemplateEnabled())
+
+	r.Extensions = map[string]interface{}{ResourceExtensionAttributeTemplate: "true"}
+	assert.False(t, r.AttributeTemplateEnabled())
+
+	r.EnableAttributeTemplate()
+	assert.Equal(t, true, r.Extensions[ResourceExtensionAttributeTemplate])
+	assert.True(t, r.AttributeTemplateEnabled())
+}
+
+func TestResources_GroupByModule(t *testing.T) {
+	rs := Resources{
+		{ID: "v1:Namespace:default", Type: Kubernetes},
+		{ID: "apps/v1:Deployment:default:foo", Type: Kubernetes},
+		{ID: "v1:Service:default:foo", Type: Kubernetes},
+		{ID: "hashicorp:aws:aws_db_instance:foo", Type: Terraform},
+	}
+	rs[1].SetModule("kusionstack/service@v0.2.0")
+	rs[2].SetModule("kusionstack/service@v0.2.0")
+	rs[3].SetModule("kusionstack/mysql@v0.1.0")
+
+	groups := rs.GroupByModule()
+	assert.Equal(t, map[string][]*Resource{
+		"":                           {&rs[0]},
+		"kusionstack/service@v0.2.0": {&rs[1], &rs[2]},
+		"kusionstack/mysql@v0.1.0":   {&rs[3]},
+	}, groups)
+}
+
+func TestResources_GVKIndex(t *testing.T) {
+	deployment := mockDeploymentResource()
+	deployment.SetGVK(schema.GroupVersionKind{Group: "apps", Version: "v1", Kind: "Deployment"})
+	rs := Resources{
+		deployment,
+		{ID: "v1:Namespace:default", Type: Kubernetes},
+		{ID: "hashicorp:random:random_password:foo", Type: Terraform},
+	}
+
+	index := rs.GVKIndex()
+	assert.Len(t, index, 1)
+	assert.Equal(t, []*Resource{&rs[0]}, index["apps/v1, Kind=Deployment"])
+}
+
+func mockMixedResources() Resources {
+	rs := Resources{
+		{ID: "v1:Namespace:default", Type: Kubernetes},
+		{ID: "apps/v1:Deployment:default:foo", Type: Kubernetes},
+		{ID: "apps/v1:Deployment:default:bar", Type: Kubernetes},
+		{ID: "v1:Service:default:foo", Type: Kubernetes},
+		{ID: "hashicorp:aws:aws_db_instance:foo", Type: Terraform},
+		{ID: "hashicorp:random:random_password:foo", Type: Terraform},
+	}
+	rs[0].SetGVK(schema.GroupVersionKind{Version: "v1", Kind: "Namespace"})
+	rs[1].SetGVK(schema.GroupVersionKind{Group: "apps", Version: "v1", Kind: "Deployment"})
+	rs[2].SetGVK(schema.GroupVersionKind{Group: "apps", Version: "v1", Kind: "Deployment"})
+	return rs
+}
+
+func TestResources_FilterByType(t *testing.T) {
+	rs := mockMixedResources()
+
+	kubernetes := rs.FilterByType(Kubernetes)
+	assert.Equal(t, Resources{rs[0], rs[1], rs[2], rs[3]}, kubernetes)
+	terraform := rs.FilterByType(Terraform)
+	assert.Equal(t, Resources{rs[4], rs[5]}, terraform)
+	assert.Equal(t, Resources{}, rs.FilterByType("Unknown"))
+	assert.Equal(t, Resources{}, Resources(nil).FilterByType(Kubernetes))
+
+	// the receiver is left unchanged
+	terraform[0].ID = "changed"
+	_ = append(kubernetes[:1], Resource{ID: "appended"})
+	assert.Equal(t, mockMixedResources(), rs)
+}
+
+func TestResources_FilterByKind(t *testing.T) {
+	rs := mockMixedResources()
+
+	deployments := rs.FilterByKind("Deployment")
+	assert.Equal(t, Resources{rs[1], rs[2]}, deployments)
+	assert.Equal(t, Resources{rs[0]}, rs.FilterByKind("Namespace"))
+	// the resources without the GVK extension are skipped
+	assert.Equal(t, Resources{}, rs.FilterByKind("Service"))
+
+	deployments[0].ID = "changed"
+	assert.Equal(t, mockMixedResources(), rs)
+}
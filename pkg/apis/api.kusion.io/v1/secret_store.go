@@ -0,0 +1,125 @@
+// Copyright 2024 KusionStack Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v1
+
+import "reflect"
+
+// DeepCopy returns a copy of the secret store, which shares no mutable data with the original.
+func (s *SecretStore) DeepCopy() *SecretStore {
+	if s == nil {
+		return nil
+	}
+	return &SecretStore{
+		Provider:  s.Provider.DeepCopy(),
+		AllowPush: s.AllowPush,
+	}
+}
+
+// Equal returns whether the secret store is equal to the other one.
+func (s *SecretStore) Equal(other *SecretStore) bool {
+	if s == nil || other == nil {
+		return s == other
+	}
+	return s.AllowPush == other.AllowPush && s.Provider.Equal(other.Provider)
+}
+
+// DeepCopy returns a copy of the provider spec, where every provider is copied, so that the copy shares
+// no mutable data with the original.
+func (p *ProviderSpec) DeepCopy() *ProviderSpec {
+	if p == nil {
+		return nil
+	}
+	out := &ProviderSpec{}
+	if p.Alicloud != nil {
+		alicloud := *p.Alicloud
+		out.Alicloud = &alicloud
+	}
+	if p.AWS != nil {
+		aws := *p.AWS
+		out.AWS = &aws
+	}
+	if p.Vault != nil {
+		vault := *p.Vault
+		vault.Path = copyStringPtr(p.Vault.Path)
+		out.Vault = &vault
+	}
+	if p.Azure != nil {
+		azure := *p.Azure
+		azure.VaultURL = copyStringPtr(p.Azure.VaultURL)
+		azure.TenantID = copyStringPtr(p.Azure.TenantID)
+		if p.Azure.Auth != nil {
+			auth := *p.Azure.Auth
+			azure.Auth = &auth
+		}
+		out.Azure = &azure
+	}
+	if p.ViettelCloud != nil {
+		viettelCloud := *p.ViettelCloud
+		out.ViettelCloud = &viettelCloud
+	}
+	if p.Kubernetes != nil {
+		kubernetes := *p.Kubernetes
+		out.Kubernetes = &kubernetes
+	}
+	if p.Local != nil {
+		local := *p.Local
+		out.Local = &local
+	}
+	if p.Fake != nil {
+		out.Fake = &FakeProvider{}
+		if p.Fake.Data != nil {
+			out.Fake.Data = make([]FakeProviderData, len(p.Fake.Data))
+			for i, data := range p.Fake.Data {
+				data.ValueMap = copyStringMap(data.ValueMap)
+				out.Fake.Data[i] = data
+			}
+		}
+	}
+	if p.OnPremises != nil {
+		out.OnPremises = &OnPremisesProvider{
+			Name:       p.OnPremises.Name,
+			Attributes: copyStringMap(p.OnPremises.Attributes),
+		}
+	}
+	return out
+}
+
+// Equal returns whether the provider spec is equal to the other one, where every provider is compared
+// by value rather than by pointer.
+func (p *ProviderSpec) Equal(other *ProviderSpec) bool {
+	if p == nil || other == nil {
+		return p == other
+	}
+	return reflect.DeepEqual(p, other)
+}
+
+func copyStringPtr(s *string) *string {
+	if s == nil {
+		return nil
+	}
+	out := *s
+	return &out
+}
+
+func copyStringMap(m map[string]string) map[string]string {
+	if m == nil {
+		return nil
+	}
+	out := make(map[string]string, len(m))
+	for k, v := range m {
+		out[k] = v
+	}
+	return out
+}
@@ -0,0 +1,117 @@
+package v1
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func mockFullProviderSpec() *ProviderSpec {
+	path, vaultURL, tenantID := "secret", "https://kusion.vault.azure.net", "tenant"
+	return &ProviderSpec{
+		Alicloud: &AlicloudProvider{Region: "cn-beijing", RoleARN: "acs:ram::123456789012:role/kusion"},
+		AWS:      &AWSProvider{Region: "us-east-1", Profile: "default"},
+		Vault:    &VaultProvider{Server: "https://vault.example.com:8200", Path: &path, Version: VaultKVStoreV2},
+		Azure: &AzureKVProvider{
+			VaultURL: &vaultURL,
+			TenantID: &tenantID,
+			Auth:     &AzureKVAuth{Type: AzureAuthWorkloadIdentity, ClientID: "client"},
+		},
+		ViettelCloud: &ViettelCloudProvider{CmpURL: "https://console.viettelcloud.vn/api/"},
+		Kubernetes:   &KubernetesProvider{Namespace: "secrets"},
+		Local:        &LocalProvider{Dir: ".secrets"},
+		Fake: &FakeProvider{Data: []FakeProviderData{
+			{Key: "mysql", ValueMap: map[string]string{"password": "t0p-Secret"}},
+		}},
+		OnPremises: &OnPremisesProvider{Name: "vault-onprem", Attributes: map[string]string{"server": "localhost"}},
+	}
+}
+
+func TestProviderSpec_DeepCopy(t *testing.T) {
+	original := mockFullProviderSpec()
+
+	// every provider is set in the mock, so that a new provider missing in DeepCopy is caught
+	v := reflect.ValueOf(original).Elem()
+	for i := 0; i < v.NumField(); i++ {
+		assert.False(t, v.Field(i).IsNil(), "provider %s is not set in the mock", v.Type().Field(i).Name)
+	}
+
+	copied := original.DeepCopy()
+	assert.Equal(t, original, copied)
+	assert.True(t, original.Equal(copied))
+
+	copied.Alicloud.Region = "cn-shanghai"
+	copied.AWS.Region = "us-west-2"
+	*copied.Vault.Path = "kv"
+	*copied.Azure.VaultURL = "https://other.vault.azure.net"
+	*copied.Azure.TenantID = "other"
+	copied.Azure.Auth.ClientID = "other"
+	copied.ViettelCloud.CmpURL = "https://other/api/"
+	copied.Kubernetes.Namespace = "other"
+	copied.Local.Dir = "other"
+	copied.Fake.Data[0].ValueMap["password"] = "changed"
+	copied.Fake.Data = append(copied.Fake.Data, FakeProviderData{Key: "redis"})
+	copied.OnPremises.Attributes["server"] = "remote"
+
+	assert.Equal(t, mockFullProviderSpec(), original)
+	assert.Nil(t, (*ProviderSpec)(nil).DeepCopy())
+}
+
+func TestProviderSpec_Equal(t *testing.T) {
+	testcases := []struct {
+		name     string
+		mutate   func(*ProviderSpec)
+		expected bool
+	}{
+		{
+			name:     "equal",
+			mutate:   func(*ProviderSpec) {},
+			expected: true,
+		},
+		{
+			name: "equal with different pointers",
+			mutate: func(p *ProviderSpec) {
+				path := *p.Vault.Path
+				p.Vault.Path = &path
+			},
+			expected: true,
+		},
+		{
+			name:     "different provider",
+			mutate:   func(p *ProviderSpec) { p.AWS = nil },
+			expected: false,
+		},
+		{
+			name:     "different provider field",
+			mutate:   func(p *ProviderSpec) { p.Azure.Auth.Type = AzureAuthManagedIdentity },
+			expected: false,
+		},
+		{
+			name:     "different fake data",
+			mutate:   func(p *ProviderSpec) { p.Fake.Data[0].ValueMap["password"] = "changed" },
+			expected: false,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			other := mockFullProviderSpec()
+			tc.mutate(other)
+			assert.Equal(t, tc.expected, mockFullProviderSpec().Equal(other))
+		})
+	}
+
+	assert.True(t, (*ProviderSpec)(nil).Equal(nil))
+	assert.False(t, mockFullProviderSpec().Equal(nil))
+}
+
+func TestSecretStore_DeepCopy(t *testing.T) {
+	original := &SecretStore{Provider: mockFullProviderSpec(), AllowPush: true}
+	copied := original.DeepCopy()
+	assert.True(t, original.Equal(copied))
+
+	copied.Provider.AWS.Region = "us-west-2"
+	assert.False(t, original.Equal(copied))
+	assert.Equal(t, "us-east-1", original.Provider.AWS.Region)
+}
@@ -0,0 +1,242 @@
+// Copyright 2024 KusionStack Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v1
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"unicode"
+
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
+
+	"kusionstack.io/kusion/pkg/util/kerrors"
+)
+
+var (
+	ErrConflictingResource    = errors.New("conflicting resources with the same ID")
+	ErrConflictingSecretStore = errors.New("conflicting secret stores")
+	ErrConflictingContext     = errors.New("conflicting context items with the same key")
+
+	ErrEmptySpec            = kerrors.New(kerrors.ErrValidation, "empty spec")
+	ErrDuplicateResourceKey = kerrors.New(kerrors.ErrValidation, "duplicate resource key")
+	ErrMissingDependency    = kerrors.New(kerrors.ErrValidation, "dependency not found")
+	ErrDependencyCycle      = kerrors.New(kerrors.ErrValidation, "dependency cycle")
+	ErrInvalidResourceType  = kerrors.New(kerrors.ErrValidation, "invalid resource type")
+	ErrMissingResourceGVK   = kerrors.New(kerrors.ErrValidation, "missing resource gvk extension")
+	ErrInvalidResourceID    = kerrors.New(kerrors.ErrValidation, "invalid resource id")
+)
+
+// Validate runs all the structural checks of the Spec, and returns an aggregated error of all the
+// violations rather than the first one. The checks are:
+//   - the resource IDs are unique, non-empty and contain no whitespace;
+//   - the resource types are supported, see Resource.ValidateType;
+//   - every DependsOn entry refers to an existing resource;
+//   - the DependsOn have no cycle.
+func (s *Spec) Validate() error {
+	if s == nil {
+		return ErrEmptySpec
+	}
+
+	var allErrs []error
+	index := make(map[string]*Resource, len(s.Resources))
+	for i := range s.Resources {
+		res := &s.Resources[i]
+		if _, ok := index[res.ID]; ok {
+			allErrs = append(allErrs, fmt.Errorf("%w: %s", ErrDuplicateResourceKey, res.ID))
+			continue
+		}
+		index[res.ID] = res
+		if err := res.validateID(); err != nil {
+			allErrs = append(allErrs, err)
+		}
+		if err := res.ValidateType(); err != nil {
+			allErrs = append(allErrs, err)
+		}
+	}
+	for i := range s.Resources {
+		res := &s.Resources[i]
+		for _, dependency := range res.DependsOn {
+			if _, ok := index[dependency]; !ok {
+				allErrs = append(allErrs, fmt.Errorf("%w: resource %s depends on %s", ErrMissingDependency, res.ID, dependency))
+			}
+		}
+	}
+	for _, cycle := range findDependencyCycles(s.Resources, index) {
+		allErrs = append(allErrs, fmt.Errorf("%w: %s", ErrDependencyCycle, strings.Join(cycle, " -> ")))
+	}
+	return utilerrors.NewAggregate(allErrs)
+}
+
+// ValidateType validates that the resource type is one of the supported types, and the resource
+// satisfies the requirement of the type: a Kubernetes resource must have the GVK extension, and the id of
+// a Terraform resource must be in the format of providerNamespace:providerName:resourceType:resourceName.
+func (r *Resource) ValidateType() error {
+	switch r.Type {
+	case Kubernetes:
+		if _, ok := r.GVK(); !ok {
+			return fmt.Errorf("%w: resource %s", ErrMissingResourceGVK, r.ID)
+		}
+	case Terraform:
+		idParts := strings.Split(r.ID, ":")
+		if len(idParts) != 4 {
+			return fmt.Errorf("%w: resource %s of type %s", ErrInvalidResourceID, r.ID, r.Type)
+		}
+		for _, part := range idParts {
+			if part == "" {
+				return fmt.Errorf("%w: resource %s of type %s", ErrInvalidResourceID, r.ID, r.Type)
+			}
+		}
+	default:
+		return fmt.Errorf("%w: resource %s has type %q, expected %s or %s", ErrInvalidResourceType, r.ID, r.Type, Kubernetes, Terraform)
+	}
+	return nil
+}
+
+// validateID validates that the id is not empty and contains no whitespace. The format of the id of a
+// Terraform resource is validated by ValidateType.
+func (r *Resource) validateID() error {
+	if r.ID == "" {
+		return fmt.Errorf("%w: empty id of the resource of type %s", ErrInvalidResourceID, r.Type)
+	}
+	if strings.IndexFunc(r.ID, unicode.IsSpace) >= 0 {
+		return fmt.Errorf("%w: resource %q contains whitespace", ErrInvalidResourceID, r.ID)
+	}
+	return nil
+}
+
+// findDependencyCycles returns the cycles of the DependsOn of the resources, each of which starts and ends
+// with the same resource, e.g. [a b a]. The missing dependencies are skipped.
+func findDependencyCycles(resources Resources, index map[string]*Resource) [][]string {
+	const (
+		unvisited = iota
+		visiting
+		visited
+	)
+	state := make(map[string]int, len(resources))
+	var cycles [][]string
+	var path []string
+	var visit func(id string)
+	visit = func(id string) {
+		state[id] = visiting
+		path = append(path, id)
+		for _, dependency := range index[id].DependsOn {
+			if _, ok := index[dependency]; !ok {
+				continue
+			}
+			switch state[dependency] {
+			case unvisited:
+				visit(dependency)
+			case visiting:
+				start := len(path) - 1
+				for path[start] != dependency {
+					start--
+				}
+				cycle := append(append([]string{}, path[start:]...), dependency)
+				cycles = append(cycles, cycle)
+			}
+		}
+		path = path[:len(path)-1]
+		state[id] = visited
+	}
+	for i := range resources {
+		if id := resources[i].ID; state[id] == unvisited {
+			visit(id)
+		}
+	}
+	return cycles
+}
+
+// Merge merges the other Spec generated by another generation pass into the Spec, where the resources of
+// the other Spec are appended. The resources with the same ID must have the same type and attributes,
+// whose DependsOn are unioned and missing extensions are added. The secret store and the context items
+// are adopted if absent, and must be equal otherwise. The Spec is left unchanged if an error is returned,
+// and the other Spec is never changed.
+func (s *Spec) Merge(other *Spec) error {
+	if other == nil {
+		return nil
+	}
+
+	// check all the conflicts before changing the Spec, including the ones in the other Spec
+	merged := s.Resources.Index()
+	for i := range other.Resources {
+		res := &other.Resources[i]
+		existing, ok := merged[res.ID]
+		if !ok {
+			merged[res.ID] = res
+			continue
+		}
+		if existing.Type != res.Type || !equalAttributes(existing.Attributes, res.Attributes) {
+			return fmt.Errorf("%w: %s", ErrConflictingResource, res.ID)
+		}
+	}
+	if s.SecretStore != nil && other.SecretStore != nil && !s.SecretStore.Equal(other.SecretStore) {
+		return ErrConflictingSecretStore
+	}
+	for k, v := range other.Context {
+		if existing, ok := s.Context[k]; ok && !reflect.DeepEqual(existing, v) {
+			return fmt.Errorf("%w: %s", ErrConflictingContext, k)
+		}
+	}
+
+	positions := make(map[string]int, len(s.Resources))
+	for i := range s.Resources {
+		positions[s.Resources[i].ID] = i
+	}
+	for _, res := range other.Resources.DeepCopy() {
+		pos, ok := positions[res.ID]
+		if !ok {
+			positions[res.ID] = len(s.Resources)
+			s.Resources = append(s.Resources, res)
+			continue
+		}
+		existing := &s.Resources[pos]
+		existing.DependsOn = unionDependsOn(existing.DependsOn, res.DependsOn)
+		for k, v := range res.Extensions {
+			if _, ok := existing.Extensions[k]; !ok {
+				existing.setExtension(k, v)
+			}
+		}
+	}
+	if s.SecretStore == nil {
+		s.SecretStore = other.SecretStore.DeepCopy()
+	}
+	if len(other.Context) > 0 {
+		if s.Context == nil {
+			s.Context = make(GenericConfig, len(other.Context))
+		}
+		for k, v := range other.Context.DeepCopy() {
+			s.Context[k] = v
+		}
+	}
+	return nil
+}
+
+// unionDependsOn returns the DependsOn with the ones of the other appended in order, skipping the
+// duplicate ones.
+func unionDependsOn(dependsOn, other []string) []string {
+	seen := make(map[string]bool, len(dependsOn))
+	for _, d := range dependsOn {
+		seen[d] = true
+	}
+	for _, d := range other {
+		if !seen[d] {
+			seen[d] = true
+			dependsOn = append(dependsOn, d)
+		}
+	}
+	return dependsOn
+}
@@ -0,0 +1,215 @@
+package v1
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func mockMergeSpec() *Spec {
+	return &Spec{
+		Resources: Resources{
+			{
+				ID:         "v1:Namespace:default",
+				Type:       Kubernetes,
+				Attributes: map[string]any{"metadata": map[string]any{"name": "default"}},
+			},
+			{
+				ID:         "apps/v1:Deployment:default:foo",
+				Type:       Kubernetes,
+				Attributes: map[string]any{"spec": map[string]any{"replicas": 1}},
+				DependsOn:  []string{"v1:Namespace:default"},
+				Extensions: map[string]any{ResourceExtensionGVK: "apps/v1, Kind=Deployment"},
+			},
+		},
+		Context: GenericConfig{"region": "us-east-1"},
+	}
+}
+
+func TestSpec_Merge(t *testing.T) {
+	testcases := []struct {
+		name     string
+		other    *Spec
+		expected *Spec
+		errMsg   string
+	}{
+		{
+			name:     "nil spec",
+			other:    nil,
+			expected: mockMergeSpec(),
+		},
+		{
+			name: "clean merge",
+			other: &Spec{
+				Resources: Resources{
+					{ID: "hashicorp:aws:aws_db_instance:foo", Type: Terraform, Attributes: map[string]any{"engine": "mysql"}},
+				},
+				SecretStore: &SecretStore{Provider: &ProviderSpec{AWS: &AWSProvider{Region: "us-east-1"}}},
+				Context:     GenericConfig{"kubeconfig": "/etc/kubeconfig.yaml"},
+			},
+			expected: func() *Spec {
+				spec := mockMergeSpec()
+				spec.Resources = append(spec.Resources, Resource{
+					ID: "hashicorp:aws:aws_db_instance:foo", Type: Terraform, Attributes: map[string]any{"engine": "mysql"},
+				})
+				spec.SecretStore = &SecretStore{Provider: &ProviderSpec{AWS: &AWSProvider{Region: "us-east-1"}}}
+				spec.Context["kubeconfig"] = "/etc/kubeconfig.yaml"
+				return spec
+			}(),
+		},
+		{
+			name: "duplicate identical resources",
+			other: &Spec{
+				Resources: Resources{
+					{
+						ID:         "apps/v1:Deployment:default:foo",
+						Type:       Kubernetes,
+						Attributes: map[string]any{"spec": map[string]any{"replicas": float64(1)}},
+						DependsOn:  []string{"v1:Namespace:default", "v1:Secret:default:foo"},
+						Extensions: map[string]any{ResourceExtensionModule: "service"},
+					},
+				},
+				Context: GenericConfig{"region": "us-east-1"},
+			},
+			expected: func() *Spec {
+				spec := mockMergeSpec()
+				spec.Resources[1].DependsOn = []string{"v1:Namespace:default", "v1:Secret:default:foo"}
+				spec.Resources[1].Extensions[ResourceExtensionModule] = "service"
+				return spec
+			}(),
+		},
+		{
+			name: "duplicate conflicting resources",
+			other: &Spec{
+				Resources: Resources{
+					{ID: "hashicorp:aws:aws_db_instance:foo", Type: Terraform},
+					{ID: "apps/v1:Deployment:default:foo", Type: Kubernetes, Attributes: map[string]any{"spec": map[string]any{"replicas": 2}}},
+				},
+			},
+			expected: mockMergeSpec(),
+			errMsg:   "conflicting resources with the same ID: apps/v1:Deployment:default:foo",
+		},
+		{
+			name: "duplicate conflicting resources in the other spec",
+			other: &Spec{
+				Resources: Resources{
+					{ID: "hashicorp:aws:aws_db_instance:foo", Type: Terraform, Attributes: map[string]any{"engine": "mysql"}},
+					{ID: "hashicorp:aws:aws_db_instance:foo", Type: Terraform, Attributes: map[string]any{"engine": "postgres"}},
+				},
+			},
+			expected: mockMergeSpec(),
+			errMsg:   "conflicting resources with the same ID: hashicorp:aws:aws_db_instance:foo",
+		},
+		{
+			name:     "conflicting context",
+			other:    &Spec{Context: GenericConfig{"region": "us-west-2"}},
+			expected: mockMergeSpec(),
+			errMsg:   "conflicting context items with the same key: region",
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			spec := mockMergeSpec()
+			err := spec.Merge(tc.other)
+			if tc.errMsg == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.EqualError(t, err, tc.errMsg)
+			}
+			assert.Equal(t, tc.expected, spec)
+		})
+	}
+}
+
+func TestSpec_MergeConflictingSecretStore(t *testing.T) {
+	spec := mockMergeSpec()
+	spec.SecretStore = &SecretStore{Provider: &ProviderSpec{AWS: &AWSProvider{Region: "us-east-1"}}}
+	other := &Spec{SecretStore: &SecretStore{Provider: &ProviderSpec{AWS: &AWSProvider{Region: "us-west-2"}}}}
+	assert.ErrorIs(t, spec.Merge(other), ErrConflictingSecretStore)
+}
+
+func TestSpec_Validate(t *testing.T) {
+	gvk := map[string]any{ResourceExtensionGVK: "v1, Kind=ConfigMap"}
+	testcases := []struct {
+		name     string
+		spec     *Spec
+		expected []error
+		errMsgs  []string
+	}{
+		{
+			name: "valid spec",
+			spec: &Spec{
+				Resources: Resources{
+					{ID: "v1:ConfigMap:default:foo", Type: Kubernetes, Extensions: gvk},
+					{ID: "hashicorp:aws:aws_s3_bucket:foo", Type: Terraform, DependsOn: []string{"v1:ConfigMap:default:foo"}},
+				},
+			},
+		},
+		{
+			name:     "nil spec",
+			spec:     nil,
+			expected: []error{ErrEmptySpec},
+		},
+		{
+			name: "multiple violations",
+			spec: &Spec{
+				Resources: Resources{
+					{ID: "v1:ConfigMap:default:foo", Type: Kubernetes, Extensions: gvk, DependsOn: []string{"v1:ConfigMap:default:bar"}},
+					{ID: "v1:ConfigMap:default:foo", Type: Kubernetes, Extensions: gvk},
+					{ID: "v1:ConfigMap:default:bar", Type: Kubernetes, DependsOn: []string{"v1:ConfigMap:default:foo", "v1:ConfigMap:default:baz"}},
+					{ID: "aws_s3_bucket:foo", Type: Terraform},
+					{ID: "helm:foo", Type: "Helm"},
+					{ID: "v1:ConfigMap:default: qux", Type: Kubernetes, Extensions: gvk},
+				},
+			},
+			expected: []error{
+				ErrDuplicateResourceKey,
+				ErrMissingResourceGVK,
+				ErrInvalidResourceID,
+				ErrInvalidResourceType,
+				ErrMissingDependency,
+				ErrDependencyCycle,
+			},
+			errMsgs: []string{
+				"duplicate resource key: v1:ConfigMap:default:foo",
+				"missing resource gvk extension: resource v1:ConfigMap:default:bar",
+				"invalid resource id: resource aws_s3_bucket:foo of type Terraform",
+				`invalid resource type: resource helm:foo has type "Helm", expected Kubernetes or Terraform`,
+				`invalid resource id: resource "v1:ConfigMap:default: qux" contains whitespace`,
+				"dependency not found: resource v1:ConfigMap:default:bar depends on v1:ConfigMap:default:baz",
+				"dependency cycle: v1:ConfigMap:default:foo -> v1:ConfigMap:default:bar -> v1:ConfigMap:default:foo",
+			},
+		},
+		{
+			name: "self dependency and empty id",
+			spec: &Spec{
+				Resources: Resources{
+					{ID: "v1:ConfigMap:default:foo", Type: Kubernetes, Extensions: gvk, DependsOn: []string{"v1:ConfigMap:default:foo"}},
+					{ID: "", Type: Kubernetes, Extensions: gvk},
+				},
+			},
+			expected: []error{ErrInvalidResourceID, ErrDependencyCycle},
+			errMsgs: []string{
+				"invalid resource id: empty id of the resource of type Kubernetes",
+				"dependency cycle: v1:ConfigMap:default:foo -> v1:ConfigMap:default:foo",
+			},
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := tc.spec.Validate()
+			if len(tc.expected) == 0 {
+				assert.NoError(t, err)
+				return
+			}
+			for _, expected := range tc.expected {
+				assert.ErrorIs(t, err, expected)
+			}
+			for _, errMsg := range tc.errMsgs {
+				assert.Contains(t, err.Error(), errMsg)
+			}
+		})
+	}
+}
@@ -97,6 +97,42 @@ type Workspace struct {
 
 	// Context contains workspace-level configurations, such as runtimes, topologies, and metadata, etc.
 	Context GenericConfig `yaml:"context,omitempty" json:"context,omitempty"`
+
+	// Backends are the named backends which can be referred by Stack.Backend, whose key is the backend name.
+	// Besides the types of the backends in the Kusion configuration, the backends only storing releases and
+	// graphs are also supported here, including http, mysql, postgres and mongo.
+	Backends map[string]*BackendConfig `yaml:"backends,omitempty" json:"backends,omitempty"`
+
+	// Profiles are the named overlays of the workspace, such as dev, staging and prod, whose key is
+	// the profile name.
+	Profiles map[string]*Profile `yaml:"profiles,omitempty" json:"profiles,omitempty"`
+
+	// Quotas cap the resources a Stack in the workspace can request, which are enforced after the
+	// Spec is generated. No quota is enforced if not set.
+	Quotas *Quotas `yaml:"quotas,omitempty" json:"quotas,omitempty"`
+}
+
+// Quotas are the limits of the resources generated for a Stack, each of which is optional and not
+// enforced if zero or empty.
+type Quotas struct {
+	// MaxResources is the max number of the resources in the Spec.
+	MaxResources int `yaml:"maxResources,omitempty" json:"maxResources,omitempty"`
+
+	// MaxTotalReplicas is the max sum of the replicas of the Kubernetes workloads in the Spec.
+	MaxTotalReplicas int `yaml:"maxTotalReplicas,omitempty" json:"maxTotalReplicas,omitempty"`
+
+	// MaxPVCSize is the max storage a PersistentVolumeClaim can request, in the Kubernetes quantity
+	// format, e.g. 100Gi, which also applies to the volumeClaimTemplates of the StatefulSets.
+	MaxPVCSize string `yaml:"maxPVCSize,omitempty" json:"maxPVCSize,omitempty"`
+}
+
+// Profile is an overlay of a Workspace, which overrides a subset of the module configs and context.
+type Profile struct {
+	// Modules override the module configs of the workspace with the same module name.
+	Modules ModuleConfigs `yaml:"modules,omitempty" json:"modules,omitempty"`
+
+	// Context overrides the context items of the workspace with the same key.
+	Context GenericConfig `yaml:"context,omitempty" json:"context,omitempty"`
 }
 
 type Accessory map[string]interface{}
@@ -251,11 +287,41 @@ const (
 	BackendS3Region           = "region"
 	BackendS3ForcePathStyle   = "forcePathStyle"
 	BackendGoogleCredentials  = "credentials"
-
-	BackendTypeLocal  = "local"
-	BackendTypeOss    = "oss"
-	BackendTypeS3     = "s3"
-	BackendTypeGoogle = "google"
+	BackendHTTPBaseURL        = "baseURL"
+	BackendHTTPToken          = "token"
+	BackendHTTPTimeout        = "timeout"
+	BackendSQLDSN             = "dsn"
+	BackendMongoURI           = "uri"
+	BackendMongoDatabase      = "database"
+	BackendMongoTimeout       = "timeout"
+	BackendEncryption         = "encryption"
+	BackendEncryptionKeyID    = "encryptionKeyID"
+	BackendEncryptionProvider = "encryptionKeyProvider"
+	BackendEncryptionServer   = "encryptionServer"
+	BackendEncryptionMount    = "encryptionMountPath"
+	BackendEncryptionRegion   = "encryptionRegion"
+	BackendEncryptionProfile  = "encryptionProfile"
+
+	// BackendEncryptionRelease encrypts the Spec and State of the whole Release.
+	BackendEncryptionRelease = "release"
+	// BackendEncryptionSensitive encrypts the sensitive attribute values of the resources in the Release.
+	BackendEncryptionSensitive = "sensitive"
+
+	// BackendEncryptionProviderLocal reads the keys from the environment variable KUSION_BACKEND_ENCRYPTION_KEYS.
+	BackendEncryptionProviderLocal = "local"
+	// BackendEncryptionProviderVault encrypts with the key of the Transit secrets engine of Vault.
+	BackendEncryptionProviderVault = "vault"
+	// BackendEncryptionProviderKMS encrypts with the data keys generated by the key of AWS KMS.
+	BackendEncryptionProviderKMS = "kms"
+
+	BackendTypeLocal    = "local"
+	BackendTypeOss      = "oss"
+	BackendTypeS3       = "s3"
+	BackendTypeGoogle   = "google"
+	BackendTypeHTTP     = "http"
+	BackendTypeMysql    = "mysql"
+	BackendTypePostgres = "postgres"
+	BackendTypeMongo    = "mongo"
 
 	EnvOssAccessKeyID             = "OSS_ACCESS_KEY_ID"
 	EnvOssAccessKeySecret         = "OSS_ACCESS_KEY_SECRET"
@@ -271,6 +337,10 @@ const (
 	EnvViettelCloudProjectID      = "VIETTEL_CLOUD_PROJECT_ID"
 	EnvGoogleCloudCredentials     = "GOOGLE_CLOUD_CREDENTIALS"
 	EnvGoogleCloudCredentialsPath = "GOOGLE_CLOUD_CREDENTIALS_PATH"
+	EnvHTTPBackendToken           = "KUSION_HTTP_BACKEND_TOKEN"
+	EnvSQLBackendDSN              = "KUSION_SQL_BACKEND_DSN"
+	EnvMongoBackendURI            = "KUSION_MONGO_BACKEND_URI"
+	EnvBackendEncryptionKeys      = "KUSION_BACKEND_ENCRYPTION_KEYS"
 
 	FieldImportedResources = "importedResources"
 	FieldHealthPolicy      = "healthPolicy"
@@ -332,6 +402,74 @@ type BackendGoogleConfig struct {
 	Region string `yaml:"region,omitempty" json:"region,omitempty"`
 }
 
+// BackendHTTPConfig contains the config of using a remote HTTP service as backend, which can be converted
+// from BackendConfig if Type is BackendTypeHTTP. The service stores the workspaces, releases and graphs.
+type BackendHTTPConfig struct {
+	// BaseURL of the remote HTTP service, e.g: "https://release.example.com/api/v1".
+	BaseURL string `yaml:"baseURL" json:"baseURL"`
+
+	// Token is the bearer token to access the remote HTTP service.
+	Token string `yaml:"token,omitempty" json:"token,omitempty"`
+
+	// Timeout is the timeout in seconds of each request to the remote HTTP service.
+	Timeout int `yaml:"timeout,omitempty" json:"timeout,omitempty"`
+}
+
+// BackendSQLConfig contains the config of using a relational database as backend, which can be converted
+// from BackendConfig if Type is BackendTypeMysql or BackendTypePostgres. The database stores the workspaces,
+// releases and graphs.
+type BackendSQLConfig struct {
+	// Dialect of the database, which is the same as the backend type.
+	Dialect string `yaml:"dialect" json:"dialect"`
+
+	// DSN is the data source name to connect the database, whose format is decided by the driver.
+	DSN string `yaml:"dsn" json:"dsn"`
+}
+
+// BackendMongoConfig contains the config of using MongoDB as backend, which can be converted from
+// BackendConfig if Type is BackendTypeMongo.
+type BackendMongoConfig struct {
+	// URI is the connection string of MongoDB, e.g: "mongodb://127.0.0.1:27017".
+	URI string `yaml:"uri" json:"uri"`
+
+	// Database is the name of the database to store the workspaces, releases and graphs.
+	Database string `yaml:"database" json:"database"`
+
+	// Timeout is the timeout in seconds of connecting MongoDB and each operation on it.
+	Timeout int `yaml:"timeout,omitempty" json:"timeout,omitempty"`
+}
+
+// BackendEncryptionConfig contains the config of encrypting the Releases at rest, which can be converted
+// from BackendConfig of any Type. The keys are not kept in the config. For the local key provider, they
+// are read from the environment variable KUSION_BACKEND_ENCRYPTION_KEYS in the format of
+// "<keyID>=<base64 encoded key>,...", so that the keys retired by rotation can still be used to decrypt.
+// For the other key providers, they are kept in the key management service.
+type BackendEncryptionConfig struct {
+	// Mode of the encryption, supports BackendEncryptionRelease and BackendEncryptionSensitive.
+	Mode string `yaml:"encryption" json:"encryption"`
+
+	// KeyID is the id of the key used to encrypt, which is the key name for Vault and the key ARN for
+	// AWS KMS.
+	KeyID string `yaml:"encryptionKeyID" json:"encryptionKeyID"`
+
+	// KeyProvider is the type of the key provider, supports BackendEncryptionProviderLocal,
+	// BackendEncryptionProviderVault and BackendEncryptionProviderKMS, and defaults to
+	// BackendEncryptionProviderLocal if empty.
+	KeyProvider string `yaml:"encryptionKeyProvider,omitempty" json:"encryptionKeyProvider,omitempty"`
+
+	// Server is the address of the key management service, e.g. the Vault server.
+	Server string `yaml:"encryptionServer,omitempty" json:"encryptionServer,omitempty"`
+
+	// MountPath is the mount path of the Transit secrets engine of Vault, which defaults to "transit".
+	MountPath string `yaml:"encryptionMountPath,omitempty" json:"encryptionMountPath,omitempty"`
+
+	// Region is the region of AWS KMS, which is read from the AWS shared config if empty.
+	Region string `yaml:"encryptionRegion,omitempty" json:"encryptionRegion,omitempty"`
+
+	// Profile is the profile of the AWS shared config used to access AWS KMS.
+	Profile string `yaml:"encryptionProfile,omitempty" json:"encryptionProfile,omitempty"`
+}
+
 // GenericBackendObjectStorageConfig contains generic configs which can be reused by BackendOssConfig and
 // BackendS3Config.
 type GenericBackendObjectStorageConfig struct {
@@ -443,6 +581,85 @@ func (b *BackendConfig) ToGoogleBackend() *BackendGoogleConfig {
 	}
 }
 
+// ToHTTPBackend converts BackendConfig to structured BackendHTTPConfig, works only when the Type is
+// BackendTypeHTTP, and the Configs are with correct type, or return nil.
+func (b *BackendConfig) ToHTTPBackend() *BackendHTTPConfig {
+	if b.Type != BackendTypeHTTP {
+		return nil
+	}
+	baseURL, _ := b.Configs[BackendHTTPBaseURL].(string)
+	token, _ := b.Configs[BackendHTTPToken].(string)
+	return &BackendHTTPConfig{
+		BaseURL: baseURL,
+		Token:   token,
+		Timeout: intConfig(b.Configs[BackendHTTPTimeout]),
+	}
+}
+
+// ToSQLBackend converts BackendConfig to structured BackendSQLConfig, works only when the Type is
+// BackendTypeMysql or BackendTypePostgres, and the Configs are with correct type, or return nil.
+func (b *BackendConfig) ToSQLBackend() *BackendSQLConfig {
+	if b.Type != BackendTypeMysql && b.Type != BackendTypePostgres {
+		return nil
+	}
+	dsn, _ := b.Configs[BackendSQLDSN].(string)
+	return &BackendSQLConfig{
+		Dialect: b.Type,
+		DSN:     dsn,
+	}
+}
+
+// ToMongoBackend converts BackendConfig to structured BackendMongoConfig, works only when the Type is
+// BackendTypeMongo, and the Configs are with correct type, or return nil.
+func (b *BackendConfig) ToMongoBackend() *BackendMongoConfig {
+	if b.Type != BackendTypeMongo {
+		return nil
+	}
+	uri, _ := b.Configs[BackendMongoURI].(string)
+	database, _ := b.Configs[BackendMongoDatabase].(string)
+	return &BackendMongoConfig{
+		URI:      uri,
+		Database: database,
+		Timeout:  intConfig(b.Configs[BackendMongoTimeout]),
+	}
+}
+
+// ToEncryptionConfig converts BackendConfig to structured BackendEncryptionConfig, works for any Type,
+// and returns nil if the encryption is not configured.
+func (b *BackendConfig) ToEncryptionConfig() *BackendEncryptionConfig {
+	mode, _ := b.Configs[BackendEncryption].(string)
+	if mode == "" {
+		return nil
+	}
+	keyID, _ := b.Configs[BackendEncryptionKeyID].(string)
+	keyProvider, _ := b.Configs[BackendEncryptionProvider].(string)
+	server, _ := b.Configs[BackendEncryptionServer].(string)
+	mountPath, _ := b.Configs[BackendEncryptionMount].(string)
+	region, _ := b.Configs[BackendEncryptionRegion].(string)
+	profile, _ := b.Configs[BackendEncryptionProfile].(string)
+	return &BackendEncryptionConfig{
+		Mode:        mode,
+		KeyID:       keyID,
+		KeyProvider: keyProvider,
+		Server:      server,
+		MountPath:   mountPath,
+		Region:      region,
+		Profile:     profile,
+	}
+}
+
+// intConfig converts the config item to int, where the item is decoded as int from yaml and as float64
+// from json, or return 0.
+func intConfig(value any) int {
+	switch v := value.(type) {
+	case int:
+		return v
+	case float64:
+		return int(v)
+	}
+	return 0
+}
+
 // ModuleConfigs is a set of multiple ModuleConfig, whose key is the module name.
 type ModuleConfigs map[string]*ModuleConfig
 
@@ -507,8 +724,13 @@ type ModulePatcherConfig struct {
 type ExtensionKind string
 
 const (
-	KubernetesMetadata  ExtensionKind = "kubernetesMetadata"
-	KubernetesNamespace ExtensionKind = "kubernetesNamespace"
+	KubernetesMetadata   ExtensionKind = "kubernetesMetadata"
+	KubernetesNamespace  ExtensionKind = "kubernetesNamespace"
+	KubernetesNaming     ExtensionKind = "kubernetesNaming"
+	KubernetesImage      ExtensionKind = "kubernetesImage"
+	KubernetesService    ExtensionKind = "kubernetesService"
+	KubernetesPatch      ExtensionKind = "patch"
+	KubernetesMergePatch ExtensionKind = "mergePatch"
 )
 
 // Extension allows you to customize how resources are generated or customized as part of deployment.
@@ -521,6 +743,21 @@ type Extension struct {
 
 	// The KubeMetadataExtension
 	KubeMetadata KubeMetadataExtension `yaml:"kubernetesMetadata,omitempty" json:"kubernetesMetadata,omitempty"`
+
+	// The KubeNamingExtension
+	KubeNaming KubeNamingExtension `yaml:"kubernetesNaming,omitempty" json:"kubernetesNaming,omitempty"`
+
+	// The KubeImageExtension
+	KubeImage KubeImageExtension `yaml:"kubernetesImage,omitempty" json:"kubernetesImage,omitempty"`
+
+	// The KubeServiceExtension
+	KubeService KubeServiceExtension `yaml:"kubernetesService,omitempty" json:"kubernetesService,omitempty"`
+
+	// The KubePatchExtension
+	KubePatch KubePatchExtension `yaml:"patch,omitempty" json:"patch,omitempty"`
+
+	// The KubeMergePatchExtension
+	KubeMergePatch KubeMergePatchExtension `yaml:"mergePatch,omitempty" json:"mergePatch,omitempty"`
 }
 
 // KubeNamespaceExtension allows you to override kubernetes namespace.
@@ -538,6 +775,109 @@ type KubeMetadataExtension struct {
 	Annotations map[string]string `yaml:"annotations,omitempty" json:"annotations,omitempty"`
 }
 
+// KubeNamingExtension allows you to customize the names of kubernetes resources generated by Kusion.
+type KubeNamingExtension struct {
+	// Prefix to prepend to the generated names.
+	Prefix string `yaml:"prefix,omitempty" json:"prefix,omitempty"`
+
+	// Suffix to append to the generated names.
+	Suffix string `yaml:"suffix,omitempty" json:"suffix,omitempty"`
+
+	// Overrides specifies the explicit names of the generated resources, whose key is the resource role.
+	// An override takes precedence over Prefix and Suffix.
+	Overrides map[string]string `yaml:"overrides,omitempty" json:"overrides,omitempty"`
+}
+
+// KubeImageExtension allows you to customize the container images of kubernetes resources generated by
+// Kusion and the modules, e.g. pulling the images from a registry mirror in the air-gapped clusters.
+type KubeImageExtension struct {
+	// Registry is the registry mirror, with an optional path prefix, which replaces the registry of the
+	// images, e.g. "mirror.example.com/dockerhub" turns "mysql:8.0" into
+	// "mirror.example.com/dockerhub/library/mysql:8.0".
+	Registry string `yaml:"registry,omitempty" json:"registry,omitempty"`
+
+	// Overrides specifies the explicit images replacing the original ones, whose key is the image repository
+	// as written or fully qualified, e.g. "mysql" or "index.docker.io/library/mysql". The value is a full image
+	// reference, which can be pinned by digest. An override takes precedence over Registry.
+	Overrides map[string]string `yaml:"overrides,omitempty" json:"overrides,omitempty"`
+}
+
+// KubeServiceExtension allows you to customize the kubernetes Services generated by Kusion and the modules,
+// e.g. exposing them with the load balancers of the cloud provider.
+type KubeServiceExtension struct {
+	// Type of the Services, one of ClusterIP, NodePort and LoadBalancer. The generated type is kept if empty.
+	Type string `yaml:"type,omitempty" json:"type,omitempty"`
+
+	// Annotations to add to the Services, e.g. to configure the internal load balancer or SSL certificate
+	// of the cloud LB controllers.
+	Annotations map[string]string `yaml:"annotations,omitempty" json:"annotations,omitempty"`
+
+	// Headless turns the Services into headless ClusterIP Services whose cluster IP is None, which are
+	// required by the StatefulSets. It conflicts with the NodePort and LoadBalancer type.
+	Headless bool `yaml:"headless,omitempty" json:"headless,omitempty"`
+}
+
+// KubePatchExtension allows you to tweak the kubernetes resources generated by Kusion and the modules with
+// the JSON Patch (RFC 6902) operations, in case no other extension covers the customization.
+type KubePatchExtension struct {
+	// Patches are applied in order after all the other extensions.
+	Patches []KubePatch `yaml:"patches,omitempty" json:"patches,omitempty"`
+}
+
+// KubePatch is a list of JSON Patch operations applied to the generated resources matching the target.
+type KubePatch struct {
+	// Target selects the resources to patch.
+	Target KubePatchTarget `yaml:"target" json:"target"`
+
+	// Operations are the JSON Patch operations, e.g. {op: replace, path: /spec/replicas, value: 3}.
+	Operations []JSONPatchOperation `yaml:"operations" json:"operations"`
+}
+
+// KubePatchTarget selects the generated resources by the apiVersion, kind and name.
+type KubePatchTarget struct {
+	// APIVersion of the resources, e.g. "apps/v1". All versions are matched if empty.
+	APIVersion string `yaml:"apiVersion,omitempty" json:"apiVersion,omitempty"`
+
+	// Kind of the resources, e.g. "Deployment".
+	Kind string `yaml:"kind" json:"kind"`
+
+	// Name of the resource. All the resources of the kind are matched if empty.
+	Name string `yaml:"name,omitempty" json:"name,omitempty"`
+}
+
+// KubeMergePatchExtension allows you to override arbitrary fields of the kubernetes resources generated
+// by Kusion and the modules declaratively with the strategic merge patches.
+type KubeMergePatchExtension struct {
+	// Patches are applied in order after all the other extensions except the patch extension.
+	Patches []KubeMergePatch `yaml:"patches,omitempty" json:"patches,omitempty"`
+}
+
+// KubeMergePatch is a merge patch document applied to the generated resources matching the target.
+type KubeMergePatch struct {
+	// Target selects the resources to patch.
+	Target KubePatchTarget `yaml:"target" json:"target"`
+
+	// Patch is the strategic merge patch document of the built-in kubernetes types, where the lists with
+	// merge keys, e.g. the containers, are merged by key. It is applied as a JSON merge patch to the
+	// other types, such as the custom resources. A null value removes the field.
+	Patch map[string]interface{} `yaml:"patch" json:"patch"`
+}
+
+// JSONPatchOperation is an operation of the JSON Patch, see https://datatracker.ietf.org/doc/html/rfc6902.
+type JSONPatchOperation struct {
+	// Op is one of add, remove, replace, move, copy and test.
+	Op string `yaml:"op" json:"op"`
+
+	// Path is the JSON Pointer of the target location, e.g. "/metadata/labels/app".
+	Path string `yaml:"path" json:"path"`
+
+	// From is the JSON Pointer of the source location of the move and copy operations.
+	From string `yaml:"from,omitempty" json:"from,omitempty"`
+
+	// Value is the value of the add, replace and test operations.
+	Value interface{} `yaml:"value,omitempty" json:"value,omitempty"`
+}
+
 // ExternalSecretRef contains information that points to the secret store data location.
 type ExternalSecretRef struct {
 	// Specifies the name of the secret in Provider to read, mandatory.
@@ -548,11 +888,32 @@ type ExternalSecretRef struct {
 
 	// Used to select a specific property of the secret data (if a map), if supported.
 	Property string `yaml:"property,omitempty" json:"property,omitempty"`
+
+	// Transform is the pipeline of transforms applied to the fetched secret data in the declared order,
+	// separated by "|", e.g. base64decode|jsonUnmarshal:db.password|trim. Supported transforms are
+	// base64decode, trim, and jsonUnmarshal with an optional property to extract.
+	Transform string `yaml:"transform,omitempty" json:"transform,omitempty"`
+}
+
+// TemplatedSecret assembles a single secret value from multiple secrets, e.g. a DSN from the host, user
+// and password stored separately.
+type TemplatedSecret struct {
+	// Template is the Go template rendered into the secret value, where the resolved secrets are
+	// referenced by their names, e.g. {{ .user }}:{{ .password }}@tcp({{ .host }}).
+	Template string `yaml:"template" json:"template"`
+
+	// Data are the secret refs resolved before rendering the template, keyed by the names referenced in
+	// the template.
+	Data map[string]ExternalSecretRef `yaml:"data,omitempty" json:"data,omitempty"`
 }
 
 // SecretStore contains configuration to describe target secret store.
 type SecretStore struct {
 	Provider *ProviderSpec `yaml:"provider" json:"provider"`
+
+	// AllowPush opts in to writing the secrets back to the secret store, e.g. the generated passwords
+	// when bootstrapping, if supported by the provider. The secrets are never written if not set.
+	AllowPush bool `yaml:"allowPush,omitempty" json:"allowPush,omitempty"`
 }
 
 // ProviderSpec contains provider-specific configuration.
@@ -572,6 +933,13 @@ type ProviderSpec struct {
 	// ViettelCloud configures a store to retrieve secrets from ViettelCloud Secrets Manager.
 	ViettelCloud *ViettelCloudProvider `yaml:"viettelcloud,omitempty" json:"viettelcloud,omitempty"`
 
+	// Kubernetes configures a store to retrieve secrets from Kubernetes Secrets.
+	Kubernetes *KubernetesProvider `yaml:"kubernetes,omitempty" json:"kubernetes,omitempty"`
+
+	// Local configures a store to retrieve secrets from local files or environment variables, which is
+	// for local development only.
+	Local *LocalProvider `yaml:"local,omitempty" json:"local,omitempty"`
+
 	// Fake configures a store with static key/value pairs
 	Fake *FakeProvider `yaml:"fake,omitempty" json:"fake,omitempty"`
 
@@ -584,6 +952,17 @@ type AlicloudProvider struct {
 	// Alicloud Region to be used to interact with Alicloud Secrets Manager.
 	// Examples are cn-beijing, cn-shanghai, etc.
 	Region string `yaml:"region" json:"region"`
+
+	// Endpoint overrides the endpoint derived from the Region, e.g. a VPC endpoint such as
+	// kms-vpc.cn-beijing.aliyuncs.com, which is a host called in https, or a URL in http or https.
+	Endpoint string `yaml:"endpoint,omitempty" json:"endpoint,omitempty"`
+
+	// RoleARN is the ARN of the RAM role to assume for the cross-account access, e.g.
+	// acs:ram::123456789012:role/kusion. The credentials from the environment are used directly if not set.
+	RoleARN string `yaml:"roleArn,omitempty" json:"roleArn,omitempty"`
+
+	// RoleSessionName is the session name of the assumed RAM role, defaults to kusion.
+	RoleSessionName string `yaml:"roleSessionName,omitempty" json:"roleSessionName,omitempty"`
 }
 
 // AWSProvider configures a store to retrieve secrets from AWS Secrets Manager.
@@ -640,6 +1019,53 @@ type AzureKVProvider struct {
 	// PublicCloud, USGovernmentCloud, ChinaCloud, GermanCloud
 	// Ref: https://github.com/Azure/go-autorest/blob/main/autorest/azure/environments.go#L152
 	EnvironmentType AzureEnvironmentType `yaml:"environmentType,omitempty" json:"environmentType,omitempty"`
+
+	// Auth selects how to authenticate with Azure. By-default it uses the client secret of the service
+	// principal read from the environment variables AZURE_CLIENT_ID and AZURE_CLIENT_SECRET.
+	Auth *AzureKVAuth `yaml:"auth,omitempty" json:"auth,omitempty"`
+
+	// ObjectType is the default type of the KeyVault objects the secret refs read, one of secret,
+	// certificate and key, defaults to secret. A ref can override it by prefixing the type to the
+	// name, e.g. certificate/my-cert.
+	ObjectType AzureKVObjectType `yaml:"objectType,omitempty" json:"objectType,omitempty"`
+}
+
+type AzureKVObjectType string
+
+const (
+	// AzureKVObjectSecret reads the value of the secret.
+	AzureKVObjectSecret AzureKVObjectType = "secret"
+	// AzureKVObjectCertificate reads the x509 certificate in the PEM format.
+	AzureKVObjectCertificate AzureKVObjectType = "certificate"
+	// AzureKVObjectKey reads the public key in the JSON Web Key format.
+	AzureKVObjectKey AzureKVObjectType = "key"
+)
+
+type AzureAuthType string
+
+const (
+	// AzureAuthClientSecret authenticates as a service principal with the client secret.
+	AzureAuthClientSecret AzureAuthType = "ClientSecret"
+	// AzureAuthManagedIdentity authenticates with the managed identity assigned to the VM or node.
+	AzureAuthManagedIdentity AzureAuthType = "ManagedIdentity"
+	// AzureAuthWorkloadIdentity authenticates with the federated token projected into the pod, e.g. by
+	// the workload identity webhook of AKS.
+	AzureAuthWorkloadIdentity AzureAuthType = "WorkloadIdentity"
+)
+
+// AzureKVAuth configures how to authenticate with Azure when accessing the Azure KeyVault.
+type AzureKVAuth struct {
+	// Type of the auth, one of ClientSecret, ManagedIdentity and WorkloadIdentity, defaults to ClientSecret.
+	Type AzureAuthType `yaml:"type,omitempty" json:"type,omitempty"`
+
+	// ClientID is the client id of the service principal, the user-assigned managed identity, or the
+	// application federated with the workload identity. It is read from the environment variable
+	// AZURE_CLIENT_ID if not set, and the system-assigned managed identity is used if both are empty.
+	ClientID string `yaml:"clientId,omitempty" json:"clientId,omitempty"`
+
+	// TokenFile is the path of the federated token file of the workload identity, which is read from the
+	// environment variable AZURE_FEDERATED_TOKEN_FILE if not set.
+	TokenFile string `yaml:"tokenFile,omitempty" json:"tokenFile,omitempty"`
 }
 
 // ViettelCloudProvider configures a store to retrieve secrets from ViettelCloud Secrets Manager.
@@ -652,6 +1078,31 @@ type ViettelCloudProvider struct {
 	ProjectID string `yaml:"projectID" json:"projectID"`
 }
 
+// KubernetesProvider configures a store to retrieve secrets from the Secrets of a Kubernetes cluster,
+// where ExternalSecretRef.Name is the name of the Secret and ExternalSecretRef.Property is the data key.
+type KubernetesProvider struct {
+	// KubeConfig is the path of the kubeconfig file of the cluster. The in-cluster config or the default
+	// kubeconfig is used if empty.
+	KubeConfig string `yaml:"kubeConfig,omitempty" json:"kubeConfig,omitempty"`
+
+	// Namespace is the namespace of the Secrets to read.
+	Namespace string `yaml:"namespace" json:"namespace"`
+}
+
+// LocalProvider configures a store to retrieve secrets from a directory of files or the environment
+// variables for local development, where ExternalSecretRef.Property is the key in the JSON or dotenv
+// content of the secret. It is refused in the Kusion server, unless KUSION_ALLOW_LOCAL_SECRETS is true,
+// or if KUSION_ENV is set to a non-dev environment.
+type LocalProvider struct {
+	// Dir is the directory of the secret files, whose file name is the secret name.
+	Dir string `yaml:"dir,omitempty" json:"dir,omitempty"`
+
+	// EnvPrefix is the prefix of the environment variables of the secrets, whose name is the prefix
+	// followed by the secret name in upper case, with the characters other than letters and digits
+	// replaced by underscores, e.g. the secret db-password with the prefix SECRET_ is SECRET_DB_PASSWORD.
+	EnvPrefix string `yaml:"envPrefix,omitempty" json:"envPrefix,omitempty"`
+}
+
 // FakeProvider configures a fake provider that returns static values.
 type FakeProvider struct {
 	Data []FakeProviderData `json:"data"`
@@ -686,8 +1137,74 @@ const (
 	// ResourceExtensionKubeConfig is the key for resource extension, which is used
 	// to indicate the path of kubeConfig for Kubernetes type resource.
 	ResourceExtensionKubeConfig = "kubeConfig"
+	// ResourceExtensionLastApplied is the key for resource extension in the State, which is used
+	// to store the attributes last applied by Kusion, while the attributes of the State resource
+	// are the live object returned by the server.
+	ResourceExtensionLastApplied = "kusion.io/last-applied"
+	// ResourceExtensionModule is the key for resource extension, which is used to indicate the
+	// module generating the resource, in the format of "<module>@<version>".
+	ResourceExtensionModule = "module"
+	// ResourceExtensionAttributeTemplate is the key for resource extension, which is used to opt in
+	// rendering the templates in the string attribute values of the resource at generation.
+	ResourceExtensionAttributeTemplate = "attributeTemplate"
 )
 
+const (
+	// ContextKeyTerraformBackend is the key of the workspace context, whose value is a TerraformBackend
+	// describing where to store the state of the Terraform resources.
+	ContextKeyTerraformBackend = "terraformBackend"
+	// ContextKeyNamespaceStrategy is the key of the workspace context, whose value is a NamespaceStrategy
+	// deriving the default namespace of the generated resources.
+	ContextKeyNamespaceStrategy = "namespaceStrategy"
+	// ContextKeyNamePolicy is the key of the workspace context, whose value is a NamePolicyConfig
+	// constructing the names of the resources generated by the built-in generators.
+	ContextKeyNamePolicy = "namePolicy"
+)
+
+// NamePolicyConfig configures the names of the resources generated by the built-in generators, i.e. the
+// namespace and the secrets, by a Go template. The names of the resources generated by the modules are
+// constructed by the modules.
+type NamePolicyConfig struct {
+	// Template is the Go template of the names, which can refer to .Role, the role of the resource such as
+	// "namespace" and "secret:<name>", .Name, the default name of the resource, .Project, .Stack and .App,
+	// e.g. "{{ .Project }}-{{ .Stack }}-{{ .Name }}".
+	Template string `yaml:"template" json:"template"`
+}
+
+type NamespaceStrategyType string
+
+const (
+	// NamespaceStrategyProject names the namespace after the project, which is the default.
+	NamespaceStrategyProject NamespaceStrategyType = "project"
+	// NamespaceStrategyProjectStack names the namespace as <project>-<stack>.
+	NamespaceStrategyProjectStack NamespaceStrategyType = "project-stack"
+	// NamespaceStrategyWorkspace names the namespace after the workspace.
+	NamespaceStrategyWorkspace NamespaceStrategyType = "workspace"
+	// NamespaceStrategyTemplate names the namespace by rendering the Go template of the strategy.
+	NamespaceStrategyTemplate NamespaceStrategyType = "template"
+)
+
+// NamespaceStrategy describes how the default namespace of the generated resources is derived when
+// no KubeNamespaceExtension is specified. The derived namespace must be a valid DNS-1123 label.
+type NamespaceStrategy struct {
+	// Type of the strategy, one of project, project-stack, workspace and template.
+	Type NamespaceStrategyType `yaml:"type" json:"type"`
+
+	// Template is the Go template of the namespace for the template strategy, which can refer to
+	// .Project, .Stack, .Workspace, .App and the project labels in .Labels, e.g.
+	// "{{ .Labels.team }}-{{ .Stack }}".
+	Template string `yaml:"template,omitempty" json:"template,omitempty"`
+}
+
+// TerraformBackend describes the Terraform state backend used when Kusion drives Terraform, which is
+// rendered into the backend block of the generated terraform block.
+type TerraformBackend struct {
+	// Type is the type of the backend, such as s3, gcs and azurerm.
+	Type string `yaml:"type" json:"type"`
+	// Config is the configuration of the backend, such as the bucket and region of s3.
+	Config GenericConfig `yaml:"config,omitempty" json:"config,omitempty"`
+}
+
 type Resources []Resource
 
 // Resource is the representation of a resource in the state.
@@ -714,7 +1231,9 @@ type Resource struct {
 // the load-balancer setup, the location of the database schema, and so on. Based on that information,
 // the Kusion engine takes care of updating the production state to match the Intent.
 type Spec struct {
-	// Resources is the list of Resource this Spec contains.
+	// Resources is the list of Resource this Spec contains. The order of Resources is not semantically
+	// meaningful, which is sorted by ID when generating the Spec; the order to apply the Resources is
+	// derived from their DependsOn.
 	Resources Resources `yaml:"resources" json:"resources"`
 	// SecretSore represents a external secret store location for storing secrets.
 	SecretStore *SecretStore `yaml:"secretStore" json:"secretStore"`
@@ -778,11 +1297,103 @@ type Release struct {
 	// Phase is the current phase of the Release.
 	Phase ReleasePhase `yaml:"phase" json:"phase"`
 
+	// Message is the reason why the Release failed, e.g. the error failing the apply, which is empty
+	// unless the phase is failed.
+	Message string `yaml:"message,omitempty" json:"message,omitempty"`
+
+	// FailedResource is the ID of the Resource whose failure failed the Release, which is empty if the
+	// Release failed for a reason other than a Resource.
+	FailedResource string `yaml:"failedResource,omitempty" json:"failedResource,omitempty"`
+
+	// Conditions are the latest observations of the progress and problems of the Release, at most one
+	// for each type, see SetCondition.
+	Conditions []ReleaseCondition `yaml:"conditions,omitempty" json:"conditions,omitempty"`
+
 	// CreateTime is the time that the Release is created.
 	CreateTime time.Time `yaml:"createTime" json:"createTime"`
 
 	// ModifiedTime is the time that the Release is modified.
 	ModifiedTime time.Time `yaml:"modifiedTime" json:"modifiedTime"`
+
+	// Encryption is the envelope of the encrypted Spec and State, which is set only when the Release is
+	// persisted with encryption at rest, and the Spec and State are left empty at this time.
+	Encryption *EncryptedData `yaml:"encryption,omitempty" json:"encryption,omitempty"`
+
+	// Unlock records who force unlocked the Release, which is set only when the Release in progress is
+	// unlocked by the administrative operation rather than finished by its own operation.
+	Unlock *ReleaseUnlock `yaml:"unlock,omitempty" json:"unlock,omitempty"`
+
+	// Operator is who created the Release, e.g. the user running the apply or destroy, which defaults to
+	// the OS user if unset.
+	Operator string `yaml:"operator,omitempty" json:"operator,omitempty"`
+
+	// Holder is the process running the operation of the Release, which is used to determine whether the
+	// Release in progress is still held by a live operation before force unlocking it.
+	Holder *ReleaseHolder `yaml:"holder,omitempty" json:"holder,omitempty"`
+
+	// Metadata is the annotations of the Release for traceability, such as the Git commit, pull request
+	// and user triggering the Release in CI, see the ReleaseMetadata keys for the well-known ones.
+	Metadata map[string]string `yaml:"metadata,omitempty" json:"metadata,omitempty"`
+
+	// ModuleOutputs are the outputs of the modules generating the Spec, whose key is the app name and the
+	// module key in the form of "app/repo@version", which are reused by the incremental generation of the
+	// next Release if the inputs of the modules are unchanged.
+	ModuleOutputs map[string]*ModuleOutput `yaml:"moduleOutputs,omitempty" json:"moduleOutputs,omitempty"`
+}
+
+// ModuleOutput is the output of a module generated with the inputs of the hash.
+type ModuleOutput struct {
+	// Hash is the hash of the inputs of the module, i.e. the module key and the request to the module.
+	Hash string `yaml:"hash" json:"hash"`
+	// Resources are the resources generated by the module in YAML.
+	Resources []string `yaml:"resources,omitempty" json:"resources,omitempty"`
+	// Patcher is the patcher generated by the module in YAML.
+	Patcher string `yaml:"patcher,omitempty" json:"patcher,omitempty"`
+}
+
+// The well-known keys of the Release Metadata.
+const (
+	ReleaseMetadataGitCommit   = "git-commit"
+	ReleaseMetadataPullRequest = "pull-request"
+	ReleaseMetadataTriggeredBy = "triggered-by"
+)
+
+// ReleaseUnlock is the record of force unlocking a Release in progress.
+type ReleaseUnlock struct {
+	// Operator is who unlocked the Release.
+	Operator string `yaml:"operator" json:"operator"`
+
+	// Time is the time that the Release is unlocked.
+	Time time.Time `yaml:"time" json:"time"`
+
+	// Force indicates whether the lock id is ignored when unlocking.
+	Force bool `yaml:"force,omitempty" json:"force,omitempty"`
+
+	// PreviousPhase is the phase of the Release before unlocked.
+	PreviousPhase ReleasePhase `yaml:"previousPhase" json:"previousPhase"`
+}
+
+// ReleaseHolder is the process running the operation of a Release.
+type ReleaseHolder struct {
+	// Host is the hostname of the machine running the operation.
+	Host string `yaml:"host" json:"host"`
+
+	// PID is the process id of the operation on the Host.
+	PID int `yaml:"pid" json:"pid"`
+}
+
+// EncryptedData is the envelope of the data encrypted by a data key, where the data key itself is
+// encrypted by the key identified by KeyID.
+type EncryptedData struct {
+	// KeyID is the id of the key which encrypts the data key, used to pick the key when decrypting
+	// and to support key rotation.
+	KeyID string `yaml:"keyID" json:"keyID"`
+
+	// EncryptedKey is the base64 encoded encrypted data key.
+	EncryptedKey string `yaml:"encryptedKey" json:"encryptedKey"`
+
+	// Ciphertext is the base64 encoded data encrypted by the data key.
+	Ciphertext string `yaml:"ciphertext" json:"ciphertext"`
 }
 
 const (
@@ -0,0 +1,147 @@
+// Copyright 2024 KusionStack Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v1
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// GetName returns the name of the workspace. The name is not serialized, and is set from the identifier
+// of the workspace in the storage when loaded.
+func (w *Workspace) GetName() string {
+	if w == nil {
+		return ""
+	}
+	return w.Name
+}
+
+// WithName sets the name of the workspace and returns the workspace itself.
+func (w *Workspace) WithName(name string) *Workspace {
+	w.Name = name
+	return w
+}
+
+// ResolveProfile returns a new Workspace by applying the profile with the specified name on top of
+// the workspace, which is left unchanged. The module configs in the profile override the ones with
+// the same module name: the non-empty path and version are replaced, the default block is merged, and
+// the patcher blocks are replaced by name. The context items in the profile override the ones with
+// the same key. The returned Workspace contains no profiles.
+func (w *Workspace) ResolveProfile(name string) (*Workspace, error) {
+	profile, ok := w.Profiles[name]
+	if !ok {
+		names := make([]string, 0, len(w.Profiles))
+		for n := range w.Profiles {
+			names = append(names, n)
+		}
+		sort.Strings(names)
+		return nil, fmt.Errorf("profile %s not found in workspace %s, available profiles: [%s]",
+			name, w.Name, strings.Join(names, ", "))
+	}
+
+	var overlay Profile
+	if profile != nil {
+		overlay = *profile
+	}
+	return &Workspace{
+		Name:        w.Name,
+		Modules:     overlayModuleConfigs(w.Modules, overlay.Modules),
+		SecretStore: w.SecretStore.DeepCopy(),
+		Context:     overlayGenericConfig(w.Context, overlay.Context),
+		Backends:    w.Backends,
+		Quotas:      w.Quotas,
+	}, nil
+}
+
+// overlayModuleConfigs returns new module configs by applying the overlay to the base, both of
+// which are left unchanged.
+func overlayModuleConfigs(base, overlay ModuleConfigs) ModuleConfigs {
+	if base == nil && overlay == nil {
+		return nil
+	}
+
+	result := make(ModuleConfigs, len(base))
+	for name, cfg := range base {
+		if cfg == nil {
+			result[name] = nil
+			continue
+		}
+		result[name] = &ModuleConfig{
+			Path:    cfg.Path,
+			Version: cfg.Version,
+			Configs: Configs{
+				Default:              overlayGenericConfig(cfg.Configs.Default, nil),
+				ModulePatcherConfigs: overlayModulePatcherConfigs(cfg.Configs.ModulePatcherConfigs, nil),
+			},
+		}
+	}
+
+	for name, cfg := range overlay {
+		if cfg == nil {
+			continue
+		}
+		target, ok := result[name]
+		if !ok || target == nil {
+			target = &ModuleConfig{}
+			result[name] = target
+		}
+		if cfg.Path != "" {
+			target.Path = cfg.Path
+		}
+		if cfg.Version != "" {
+			target.Version = cfg.Version
+		}
+		target.Configs.Default = overlayGenericConfig(target.Configs.Default, cfg.Configs.Default)
+		target.Configs.ModulePatcherConfigs = overlayModulePatcherConfigs(
+			target.Configs.ModulePatcherConfigs, cfg.Configs.ModulePatcherConfigs)
+	}
+
+	return result
+}
+
+// overlayModulePatcherConfigs returns new patcher blocks by replacing the blocks of the base with the
+// ones of the overlay by name, both of which are left unchanged.
+func overlayModulePatcherConfigs(base, overlay ModulePatcherConfigs) ModulePatcherConfigs {
+	if base == nil && overlay == nil {
+		return nil
+	}
+
+	result := make(ModulePatcherConfigs, len(base)+len(overlay))
+	for name, cfg := range base {
+		result[name] = cfg
+	}
+	for name, cfg := range overlay {
+		result[name] = cfg
+	}
+	return result
+}
+
+// overlayGenericConfig returns a new GenericConfig by overriding the items of the base with the ones
+// of the overlay by key, both of which are left unchanged.
+func overlayGenericConfig(base, overlay GenericConfig) GenericConfig {
+	if base == nil && overlay == nil {
+		return nil
+	}
+
+	result := make(GenericConfig, len(base)+len(overlay))
+	for k, v := range base {
+		result[k] = v
+	}
+	for k, v := range overlay {
+		result[k] = v
+	}
+	return result
+}
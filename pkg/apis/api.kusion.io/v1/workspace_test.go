@@ -0,0 +1,108 @@
+package v1
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func mockWorkspaceWithProfiles() *Workspace {
+	return &Workspace{
+		Name: "base",
+		Modules: ModuleConfigs{
+			"mysql": &ModuleConfig{
+				Path:    "ghcr.io/kusionstack/mysql",
+				Version: "0.1.0",
+				Configs: Configs{
+					Default: GenericConfig{
+						"type":         "aws",
+						"version":      "5.7",
+						"instanceType": "db.t3.micro",
+					},
+					ModulePatcherConfigs: ModulePatcherConfigs{
+						"smallClass": {
+							GenericConfig:   GenericConfig{"instanceType": "db.t3.small"},
+							ProjectSelector: []string{"foo"},
+						},
+					},
+				},
+			},
+			"network": &ModuleConfig{
+				Path:    "ghcr.io/kusionstack/network",
+				Version: "0.1.0",
+			},
+		},
+		Context: GenericConfig{
+			"kubeconfig": "/etc/kubeconfig-dev.yaml",
+			"region":     "us-east-1",
+		},
+		Profiles: map[string]*Profile{
+			"prod": {
+				Modules: ModuleConfigs{
+					"mysql": &ModuleConfig{
+						Version: "0.2.0",
+						Configs: Configs{
+							Default: GenericConfig{"instanceType": "db.m5.large"},
+						},
+					},
+				},
+				Context: GenericConfig{
+					"kubeconfig": "/etc/kubeconfig-prod.yaml",
+				},
+			},
+			"staging": nil,
+		},
+	}
+}
+
+func TestWorkspace_ResolveProfile(t *testing.T) {
+	ws := mockWorkspaceWithProfiles()
+
+	resolved, err := ws.ResolveProfile("prod")
+	assert.NoError(t, err)
+	assert.Equal(t, "base", resolved.Name)
+	assert.Nil(t, resolved.Profiles)
+
+	mysql := resolved.Modules["mysql"]
+	assert.Equal(t, "ghcr.io/kusionstack/mysql", mysql.Path)
+	assert.Equal(t, "0.2.0", mysql.Version)
+	assert.Equal(t, GenericConfig{
+		"type":         "aws",
+		"version":      "5.7",
+		"instanceType": "db.m5.large",
+	}, mysql.Configs.Default)
+	assert.Contains(t, mysql.Configs.ModulePatcherConfigs, "smallClass")
+	assert.Equal(t, ws.Modules["network"], resolved.Modules["network"])
+	assert.Equal(t, GenericConfig{
+		"kubeconfig": "/etc/kubeconfig-prod.yaml",
+		"region":     "us-east-1",
+	}, resolved.Context)
+
+	// the base workspace is left unchanged
+	assert.Equal(t, mockWorkspaceWithProfiles(), ws)
+
+	resolved, err = ws.ResolveProfile("staging")
+	assert.NoError(t, err)
+	assert.Equal(t, ws.Modules, resolved.Modules)
+	assert.Equal(t, ws.Context, resolved.Context)
+}
+
+func TestWorkspace_ResolveProfileUnknown(t *testing.T) {
+	ws := mockWorkspaceWithProfiles()
+
+	_, err := ws.ResolveProfile("dev")
+	assert.EqualError(t, err, "profile dev not found in workspace base, available profiles: [prod, staging]")
+}
+
+func TestWorkspace_Name(t *testing.T) {
+	var nilWorkspace *Workspace
+	assert.Equal(t, "", nilWorkspace.GetName())
+
+	ws := (&Workspace{}).WithName("dev")
+	assert.Equal(t, "dev", ws.Name)
+	assert.Equal(t, "dev", ws.GetName())
+
+	resolved, err := mockWorkspaceWithProfiles().WithName("prod").ResolveProfile("prod")
+	assert.NoError(t, err)
+	assert.Equal(t, "prod", resolved.GetName())
+}
@@ -48,14 +48,42 @@ func NewBackend(name string) (Backend, error) {
 		return nil, fmt.Errorf("config of backend %s does not exist", name)
 	}
 
+	return newBackendWithConfig(name, bkCfg)
+}
+
+// NewStackBackend creates the Backend of the stack, whose config is resolved from the backends registered
+// in the workspace by the name of Stack.Backend. If the named backend is not registered in the workspace,
+// NewStackBackend will get failed. If Stack.Backend is empty, use the current backend by calling NewBackend.
+func NewStackBackend(stack *v1.Stack, ws *v1.Workspace) (Backend, error) {
+	if stack == nil {
+		return nil, fmt.Errorf("stack must not be nil")
+	}
+	if stack.Backend == "" {
+		return NewBackend("")
+	}
+	if ws == nil {
+		return nil, fmt.Errorf("workspace must not be nil to resolve backend %s of stack %s", stack.Backend, stack.Name)
+	}
+
+	bkCfg := ws.Backends[stack.Backend]
+	if bkCfg == nil {
+		return nil, fmt.Errorf("backend %s of stack %s is not defined in workspace %s", stack.Backend, stack.Name, ws.Name)
+	}
+	return newBackendWithConfig(stack.Backend, bkCfg)
+}
+
+// newBackendWithConfig creates the Backend with the specified backend name and config. If the encryption is
+// configured, the release storages of the Backend are wrapped with the encryption at rest.
+func newBackendWithConfig(name string, bkCfg *v1.BackendConfig) (Backend, error) {
 	var storage Backend
+	var err error
 	switch bkCfg.Type {
 	case v1.BackendTypeLocal:
 		bkConfig := bkCfg.ToLocalBackend()
 		if err = storages.CompleteLocalConfig(bkConfig); err != nil {
 			return nil, fmt.Errorf("complete local config failed, %w", err)
 		}
-		return storages.NewLocalStorage(bkConfig), nil
+		storage = storages.NewLocalStorage(bkConfig)
 	case v1.BackendTypeOss:
 		bkConfig := bkCfg.ToOssBackend()
 		storages.CompleteOssConfig(bkConfig)
@@ -83,9 +111,46 @@ func NewBackend(name string) (Backend, error) {
 		if err != nil {
 			return nil, fmt.Errorf("new google storage of backend %s failed, %w", name, err)
 		}
+	case v1.BackendTypeHTTP:
+		bkConfig := bkCfg.ToHTTPBackend()
+		storages.CompleteHTTPConfig(bkConfig)
+		if err = storages.ValidateHTTPConfig(bkConfig); err != nil {
+			return nil, fmt.Errorf("invalid config of backend %s: %w", name, err)
+		}
+		storage = storages.NewHTTPStorage(bkConfig)
+	case v1.BackendTypeMysql, v1.BackendTypePostgres:
+		bkConfig := bkCfg.ToSQLBackend()
+		storages.CompleteSQLConfig(bkConfig)
+		if err = storages.ValidateSQLConfig(bkConfig); err != nil {
+			return nil, fmt.Errorf("invalid config of backend %s: %w", name, err)
+		}
+		storage, err = storages.NewSQLStorage(bkConfig)
+		if err != nil {
+			return nil, fmt.Errorf("new %s storage of backend %s failed, %w", bkConfig.Dialect, name, err)
+		}
+	case v1.BackendTypeMongo:
+		bkConfig := bkCfg.ToMongoBackend()
+		storages.CompleteMongoConfig(bkConfig)
+		if err = storages.ValidateMongoConfig(bkConfig); err != nil {
+			return nil, fmt.Errorf("invalid config of backend %s: %w", name, err)
+		}
+		storage, err = storages.NewMongoStorage(bkConfig)
+		if err != nil {
+			return nil, fmt.Errorf("new mongo storage of backend %s failed, %w", name, err)
+		}
 	default:
 		return nil, fmt.Errorf("invalid type %s of backend %s", bkCfg.Type, name)
 	}
+
+	if encryption := bkCfg.ToEncryptionConfig(); encryption != nil {
+		if err = storages.ValidateEncryptionConfig(encryption); err != nil {
+			return nil, fmt.Errorf("invalid encryption config of backend %s: %w", name, err)
+		}
+		storage, err = newEncryptedBackend(storage, encryption)
+		if err != nil {
+			return nil, fmt.Errorf("new encrypted storage of backend %s failed, %w", name, err)
+		}
+	}
 	return storage, nil
 }
 
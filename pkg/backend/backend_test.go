@@ -40,6 +40,25 @@ func mockConfig() *v1.Config {
 						v1.BackendGenericOssBucket: "kusion",
 					},
 				},
+				"remote": {
+					Type: v1.BackendTypeHTTP,
+					Configs: map[string]any{
+						v1.BackendHTTPBaseURL: "https://release.kusion.io",
+					},
+				},
+				"database": {
+					Type: v1.BackendTypeMysql,
+					Configs: map[string]any{
+						v1.BackendSQLDSN: "user:password@tcp(127.0.0.1:3306)/kusion",
+					},
+				},
+				"document": {
+					Type: v1.BackendTypeMongo,
+					Configs: map[string]any{
+						v1.BackendMongoURI:      "mongodb://127.0.0.1:27017",
+						v1.BackendMongoDatabase: "kusion",
+					},
+				},
 			},
 		},
 	}
@@ -53,6 +72,7 @@ func mockNewStorage() {
 	mockey.Mock(storages.NewLocalStorage).Return(&storages.LocalStorage{}).Build()
 	mockey.Mock(storages.NewOssStorage).Return(&storages.OssStorage{}, nil).Build()
 	mockey.Mock(storages.NewS3Storage).Return(&storages.S3Storage{}, nil).Build()
+	mockey.Mock(storages.NewMongoStorage).Return(&storages.MongoStorage{}, nil).Build()
 }
 
 func TestNewBackend(t *testing.T) {
@@ -115,6 +135,30 @@ func TestNewBackend(t *testing.T) {
 			bkName:  "prod",
 			storage: &storages.S3Storage{},
 		},
+		{
+			name:    "new http backend",
+			success: true,
+			cfg:     mockConfig(),
+			envs:    nil,
+			bkName:  "remote",
+			storage: &storages.HTTPStorage{},
+		},
+		{
+			name:    "new mysql backend",
+			success: true,
+			cfg:     mockConfig(),
+			envs:    nil,
+			bkName:  "database",
+			storage: &storages.SQLStorage{},
+		},
+		{
+			name:    "new mongo backend",
+			success: true,
+			cfg:     mockConfig(),
+			envs:    nil,
+			bkName:  "document",
+			storage: &storages.MongoStorage{},
+		},
 	}
 
 	for _, tc := range testcases {
@@ -136,3 +180,49 @@ func TestNewBackend(t *testing.T) {
 		})
 	}
 }
+
+func TestNewStackBackend(t *testing.T) {
+	ws := &v1.Workspace{
+		Name: "dev",
+		Backends: map[string]*v1.BackendConfig{
+			"local": {
+				Type: v1.BackendTypeLocal,
+				Configs: map[string]any{
+					v1.BackendLocalPath: t.TempDir(),
+				},
+			},
+		},
+	}
+
+	testcases := []struct {
+		name    string
+		success bool
+		stack   *v1.Stack
+		storage Backend
+	}{
+		{
+			name:    "resolved backend",
+			success: true,
+			stack:   &v1.Stack{Name: "dev", Backend: "local"},
+			storage: &storages.LocalStorage{},
+		},
+		{
+			name:    "missing backend",
+			success: false,
+			stack:   &v1.Stack{Name: "dev", Backend: "remote"},
+			storage: nil,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			storage, err := NewStackBackend(tc.stack, ws)
+			assert.Equal(t, tc.success, err == nil)
+			if tc.success {
+				assert.Equal(t, reflect.TypeOf(tc.storage), reflect.TypeOf(storage))
+			} else {
+				assert.EqualError(t, err, "backend remote of stack dev is not defined in workspace dev")
+			}
+		})
+	}
+}
@@ -0,0 +1,77 @@
+package backend
+
+import (
+	"fmt"
+	"os"
+
+	v1 "kusionstack.io/kusion/pkg/apis/api.kusion.io/v1"
+	"kusionstack.io/kusion/pkg/engine/release"
+	"kusionstack.io/kusion/pkg/secrets/keyprovider"
+	"kusionstack.io/kusion/pkg/secrets/providers/aws/kms"
+	"kusionstack.io/kusion/pkg/secrets/providers/hashivault"
+)
+
+// encryptedBackend is a Backend whose release storages are wrapped with the encryption at rest, while
+// the other storages are kept as they are.
+type encryptedBackend struct {
+	Backend
+
+	wrap func(release.Storage) release.Storage
+}
+
+// newEncryptedBackend wraps the release storages of the backend with the encryption configured by the
+// backend config, where the keys are provided by the key provider of the config.
+func newEncryptedBackend(backend Backend, config *v1.BackendEncryptionConfig) (Backend, error) {
+	provider, err := newKeyProvider(config)
+	if err != nil {
+		return nil, fmt.Errorf("new encryption key provider failed: %w", err)
+	}
+
+	wrap := func(storage release.Storage) release.Storage {
+		return release.NewEncryptedStorage(storage, provider)
+	}
+	if config.Mode == v1.BackendEncryptionSensitive {
+		wrap = func(storage release.Storage) release.Storage {
+			return release.NewSensitiveEncryptedStorage(storage, provider)
+		}
+	}
+	return &encryptedBackend{
+		Backend: backend,
+		wrap:    wrap,
+	}, nil
+}
+
+// newKeyProvider creates the key provider of the encryption config by its type. The local key provider
+// reads the keys from the environment variable KUSION_BACKEND_ENCRYPTION_KEYS.
+func newKeyProvider(config *v1.BackendEncryptionConfig) (keyprovider.KeyProvider, error) {
+	switch config.KeyProvider {
+	case "", v1.BackendEncryptionProviderLocal:
+		keys, err := keyprovider.ParseLocalKeys(os.Getenv(v1.EnvBackendEncryptionKeys))
+		if err != nil {
+			return nil, fmt.Errorf("parse encryption keys from %s failed: %w", v1.EnvBackendEncryptionKeys, err)
+		}
+		return keyprovider.NewLocalProvider(config.KeyID, keys)
+	case v1.BackendEncryptionProviderVault:
+		return hashivault.NewTransitKeyProvider(config.Server, config.MountPath, config.KeyID)
+	case v1.BackendEncryptionProviderKMS:
+		return kms.NewKeyProvider(&v1.AWSProvider{Region: config.Region, Profile: config.Profile}, config.KeyID)
+	default:
+		return nil, fmt.Errorf("unsupported encryption key provider %s", config.KeyProvider)
+	}
+}
+
+func (b *encryptedBackend) ReleaseStorage(project, workspace string) (release.Storage, error) {
+	storage, err := b.Backend.ReleaseStorage(project, workspace)
+	if err != nil {
+		return nil, err
+	}
+	return b.wrap(storage), nil
+}
+
+func (b *encryptedBackend) StateStorageWithPath(path string) (release.Storage, error) {
+	storage, err := b.Backend.StateStorageWithPath(path)
+	if err != nil {
+		return nil, err
+	}
+	return b.wrap(storage), nil
+}
@@ -0,0 +1,187 @@
+package backend
+
+import (
+	"encoding/base64"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	v1 "kusionstack.io/kusion/pkg/apis/api.kusion.io/v1"
+	"kusionstack.io/kusion/pkg/engine/release"
+	"kusionstack.io/kusion/pkg/secrets/keyprovider"
+	"kusionstack.io/kusion/pkg/secrets/providers/aws/kms"
+	"kusionstack.io/kusion/pkg/secrets/providers/hashivault"
+)
+
+func mockSecret(password string) v1.Resource {
+	return v1.Resource{
+		ID:   "v1:Secret:default:foo",
+		Type: v1.Kubernetes,
+		Attributes: map[string]interface{}{
+			"apiVersion": "v1",
+			"kind":       "Secret",
+			"stringData": map[string]interface{}{"password": password},
+		},
+	}
+}
+
+func TestNewBackendWithEncryption(t *testing.T) {
+	key := base64.StdEncoding.EncodeToString(make([]byte, 32))
+	testcases := []struct {
+		name    string
+		success bool
+		configs map[string]any
+		keys    string
+		storage release.Storage
+	}{
+		{
+			name:    "new backend with release encryption successfully",
+			success: true,
+			configs: map[string]any{
+				v1.BackendEncryption:      v1.BackendEncryptionRelease,
+				v1.BackendEncryptionKeyID: "key-1",
+			},
+			keys:    "key-1=" + key,
+			storage: &release.EncryptedStorage{},
+		},
+		{
+			name:    "new backend with sensitive encryption successfully",
+			success: true,
+			configs: map[string]any{
+				v1.BackendEncryption:      v1.BackendEncryptionSensitive,
+				v1.BackendEncryptionKeyID: "key-1",
+			},
+			keys:    "key-1=" + key,
+			storage: &release.SensitiveEncryptedStorage{},
+		},
+		{
+			name:    "failed to new backend with unsupported encryption",
+			success: false,
+			configs: map[string]any{
+				v1.BackendEncryption:      "all",
+				v1.BackendEncryptionKeyID: "key-1",
+			},
+			keys: "key-1=" + key,
+		},
+		{
+			name:    "failed to new backend with encryption key not found",
+			success: false,
+			configs: map[string]any{
+				v1.BackendEncryption:      v1.BackendEncryptionRelease,
+				v1.BackendEncryptionKeyID: "key-2",
+			},
+			keys: "key-1=" + key,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Setenv(v1.EnvBackendEncryptionKeys, tc.keys)
+			dir := t.TempDir()
+			configs := map[string]any{v1.BackendLocalPath: dir}
+			for k, v := range tc.configs {
+				configs[k] = v
+			}
+
+			bk, err := newBackendWithConfig("dev", &v1.BackendConfig{Type: v1.BackendTypeLocal, Configs: configs})
+			assert.Equal(t, tc.success, err == nil)
+			if !tc.success {
+				return
+			}
+
+			storage, err := bk.ReleaseStorage("test_project", "test_ws")
+			require.NoError(t, err)
+			assert.IsType(t, tc.storage, storage)
+			r := &v1.Release{
+				Project:   "test_project",
+				Workspace: "test_ws",
+				Revision:  1,
+				Stack:     "test_stack",
+				Spec:      &v1.Spec{Resources: v1.Resources{mockSecret("plaintext-password")}},
+				State:     &v1.State{},
+				Phase:     v1.ReleasePhaseSucceeded,
+			}
+			require.NoError(t, storage.Create(r))
+
+			// the password is encrypted on disk
+			content, err := os.ReadFile(filepath.Join(dir, "releases", "test_project", "test_ws", "1.yaml"))
+			require.NoError(t, err)
+			assert.NotContains(t, string(content), "plaintext-password")
+			got, err := storage.Get(1)
+			require.NoError(t, err)
+			assert.Equal(t, r.Spec.Resources[0].Attributes["stringData"], got.Spec.Resources[0].Attributes["stringData"])
+		})
+	}
+}
+
+func TestNewKeyProvider(t *testing.T) {
+	t.Setenv(v1.EnvBackendEncryptionKeys, "key-1="+base64.StdEncoding.EncodeToString(make([]byte, 32)))
+	testcases := []struct {
+		name     string
+		success  bool
+		config   *v1.BackendEncryptionConfig
+		provider keyprovider.KeyProvider
+	}{
+		{
+			name:     "default key provider",
+			success:  true,
+			config:   &v1.BackendEncryptionConfig{Mode: v1.BackendEncryptionRelease, KeyID: "key-1"},
+			provider: &keyprovider.LocalProvider{},
+		},
+		{
+			name:    "local key provider",
+			success: true,
+			config: &v1.BackendEncryptionConfig{
+				Mode:        v1.BackendEncryptionRelease,
+				KeyID:       "key-1",
+				KeyProvider: v1.BackendEncryptionProviderLocal,
+			},
+			provider: &keyprovider.LocalProvider{},
+		},
+		{
+			name:    "vault key provider",
+			success: true,
+			config: &v1.BackendEncryptionConfig{
+				Mode:        v1.BackendEncryptionRelease,
+				KeyID:       "kusion",
+				KeyProvider: v1.BackendEncryptionProviderVault,
+				Server:      "https://vault.example.com",
+			},
+			provider: &hashivault.TransitKeyProvider{},
+		},
+		{
+			name:    "kms key provider",
+			success: true,
+			config: &v1.BackendEncryptionConfig{
+				Mode:        v1.BackendEncryptionRelease,
+				KeyID:       "arn:aws:kms:us-east-1:123456789012:key/kusion",
+				KeyProvider: v1.BackendEncryptionProviderKMS,
+				Region:      "us-east-1",
+			},
+			provider: &kms.KeyProvider{},
+		},
+		{
+			name:    "unsupported key provider",
+			success: false,
+			config: &v1.BackendEncryptionConfig{
+				Mode:        v1.BackendEncryptionRelease,
+				KeyID:       "kusion",
+				KeyProvider: "gcpkms",
+			},
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			provider, err := newKeyProvider(tc.config)
+			assert.Equal(t, tc.success, err == nil)
+			if tc.success {
+				assert.IsType(t, tc.provider, provider)
+				assert.Equal(t, tc.config.KeyID, provider.KeyID())
+			}
+		})
+	}
+}
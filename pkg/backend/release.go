@@ -0,0 +1,21 @@
+package backend
+
+import (
+	"fmt"
+
+	"kusionstack.io/kusion/pkg/engine/release"
+)
+
+// DiffReleases returns the difference between the Releases of revision a and b of the project and workspace
+// stored in the backend.
+func DiffReleases(bk Backend, project, workspace string, a, b uint64) (*release.ReleaseDiff, error) {
+	storage, err := bk.ReleaseStorage(project, workspace)
+	if err != nil {
+		return nil, err
+	}
+	diff, err := release.DiffReleases(storage, a, b)
+	if err != nil {
+		return nil, fmt.Errorf("diff releases of project %s, workspace %s failed: %w", project, workspace, err)
+	}
+	return diff, nil
+}
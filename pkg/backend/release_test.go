@@ -0,0 +1,44 @@
+package backend
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	v1 "kusionstack.io/kusion/pkg/apis/api.kusion.io/v1"
+	"kusionstack.io/kusion/pkg/backend/storages"
+	"kusionstack.io/kusion/pkg/util/kerrors"
+)
+
+func TestDiffReleases(t *testing.T) {
+	bk := storages.NewLocalStorage(&v1.BackendLocalConfig{Path: t.TempDir()})
+	s, err := bk.ReleaseStorage("test_project", "test_ws")
+	require.NoError(t, err)
+	for revision, replicas := range []int64{1, 3} {
+		require.NoError(t, s.Create(&v1.Release{
+			Project:   "test_project",
+			Workspace: "test_ws",
+			Revision:  uint64(revision + 1),
+			Stack:     "test_stack",
+			Spec: &v1.Spec{Resources: v1.Resources{{
+				ID:         "apps/v1:Deployment:default:foo",
+				Type:       v1.Kubernetes,
+				Attributes: map[string]interface{}{"spec": map[string]interface{}{"replicas": replicas}},
+			}}},
+			State: &v1.State{},
+			Phase: v1.ReleasePhaseSucceeded,
+		}))
+	}
+
+	diff, err := DiffReleases(bk, "test_project", "test_ws", 1, 2)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(1), diff.From.Revision)
+	assert.Equal(t, uint64(2), diff.To.Revision)
+	require.Len(t, diff.Resources.Changed, 1)
+	assert.EqualValues(t, 3, diff.Resources.Changed[0].Attributes["spec"].(map[string]interface{})["replicas"])
+
+	_, err = DiffReleases(bk, "test_project", "test_ws", 1, 5)
+	assert.True(t, kerrors.IsNotFound(err))
+	assert.ErrorContains(t, err, "diff releases of project test_project, workspace test_ws failed")
+}
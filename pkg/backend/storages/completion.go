@@ -51,3 +51,35 @@ func CompleteS3Config(config *v1.BackendS3Config) {
 		config.Region = region
 	}
 }
+
+// CompleteHTTPConfig fulfills the whole http config from environment variables if set, and sets default
+// value of timeout if not set.
+func CompleteHTTPConfig(config *v1.BackendHTTPConfig) {
+	token := os.Getenv(v1.EnvHTTPBackendToken)
+	if token != "" {
+		config.Token = token
+	}
+	if config.Timeout == 0 {
+		config.Timeout = DefaultHTTPTimeout
+	}
+}
+
+// CompleteSQLConfig fulfills the whole sql config from environment variables if set.
+func CompleteSQLConfig(config *v1.BackendSQLConfig) {
+	dsn := os.Getenv(v1.EnvSQLBackendDSN)
+	if dsn != "" {
+		config.DSN = dsn
+	}
+}
+
+// CompleteMongoConfig fulfills the whole mongo config from environment variables if set, and sets default
+// value of timeout if not set.
+func CompleteMongoConfig(config *v1.BackendMongoConfig) {
+	uri := os.Getenv(v1.EnvMongoBackendURI)
+	if uri != "" {
+		config.URI = uri
+	}
+	if config.Timeout == 0 {
+		config.Timeout = DefaultMongoTimeout
+	}
+}
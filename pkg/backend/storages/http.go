@@ -0,0 +1,54 @@
+package storages
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	v1 "kusionstack.io/kusion/pkg/apis/api.kusion.io/v1"
+	"kusionstack.io/kusion/pkg/engine/release"
+	releasestorages "kusionstack.io/kusion/pkg/engine/release/storages"
+	"kusionstack.io/kusion/pkg/engine/resource/graph"
+	graphstorages "kusionstack.io/kusion/pkg/engine/resource/graph/storages"
+	"kusionstack.io/kusion/pkg/util/kerrors"
+	"kusionstack.io/kusion/pkg/workspace"
+	workspacestorages "kusionstack.io/kusion/pkg/workspace/storages"
+)
+
+// DefaultHTTPTimeout is the default timeout in seconds of the requests to the remote HTTP service.
+const DefaultHTTPTimeout = 30
+
+// HTTPStorage is an implementation of backend.Backend which uses a remote HTTP service as storage.
+type HTTPStorage struct {
+	client  *http.Client
+	baseURL string
+	token   string
+}
+
+func NewHTTPStorage(config *v1.BackendHTTPConfig) *HTTPStorage {
+	return &HTTPStorage{
+		client:  &http.Client{Timeout: time.Duration(config.Timeout) * time.Second},
+		baseURL: config.BaseURL,
+		token:   config.Token,
+	}
+}
+
+func (s *HTTPStorage) WorkspaceStorage() (workspace.Storage, error) {
+	return workspacestorages.NewHTTPStorage(s.client, workspacestorages.GenHTTPWorkspaceURL(s.baseURL), s.token)
+}
+
+func (s *HTTPStorage) ReleaseStorage(project, workspace string) (release.Storage, error) {
+	return releasestorages.NewHTTPStorage(s.client, releasestorages.GenHTTPReleaseURL(s.baseURL, project, workspace), s.token)
+}
+
+func (s *HTTPStorage) StateStorageWithPath(path string) (release.Storage, error) {
+	return releasestorages.NewHTTPStorage(s.client, releasestorages.GenHTTPReleaseURLWithPath(s.baseURL, path), s.token)
+}
+
+func (s *HTTPStorage) GraphStorage(project, workspace string) (graph.Storage, error) {
+	return graphstorages.NewHTTPStorage(s.client, graphstorages.GenHTTPGraphURL(s.baseURL, project, workspace), s.token)
+}
+
+func (s *HTTPStorage) ProjectStorage() (map[string][]string, error) {
+	return nil, fmt.Errorf("project storage is %w by %s backend", kerrors.ErrNotSupported, v1.BackendTypeHTTP)
+}
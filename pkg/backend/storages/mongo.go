@@ -0,0 +1,69 @@
+package storages
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	v1 "kusionstack.io/kusion/pkg/apis/api.kusion.io/v1"
+	"kusionstack.io/kusion/pkg/engine/release"
+	releasestorages "kusionstack.io/kusion/pkg/engine/release/storages"
+	"kusionstack.io/kusion/pkg/engine/resource/graph"
+	graphstorages "kusionstack.io/kusion/pkg/engine/resource/graph/storages"
+	"kusionstack.io/kusion/pkg/util/kerrors"
+	"kusionstack.io/kusion/pkg/workspace"
+	workspacestorages "kusionstack.io/kusion/pkg/workspace/storages"
+)
+
+// DefaultMongoTimeout is the default timeout in seconds of connecting MongoDB and each operation on it.
+const DefaultMongoTimeout = 30
+
+// MongoStorage is an implementation of backend.Backend which uses MongoDB as storage.
+type MongoStorage struct {
+	db      *mongo.Database
+	timeout time.Duration
+}
+
+// NewMongoStorage connects MongoDB and pings it to make sure the deployment is available, both of which
+// are bounded by the timeout of the config.
+func NewMongoStorage(config *v1.BackendMongoConfig) (*MongoStorage, error) {
+	timeout := time.Duration(config.Timeout) * time.Second
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	client, err := mongo.Connect(ctx, options.Client().ApplyURI(config.URI).SetTimeout(timeout))
+	if err != nil {
+		return nil, err
+	}
+	if err = client.Ping(ctx, nil); err != nil {
+		_ = client.Disconnect(context.Background())
+		return nil, fmt.Errorf("ping mongo failed: %w", err)
+	}
+	return &MongoStorage{
+		db:      client.Database(config.Database),
+		timeout: timeout,
+	}, nil
+}
+
+func (s *MongoStorage) WorkspaceStorage() (workspace.Storage, error) {
+	return workspacestorages.NewMongoStorage(s.db, s.timeout)
+}
+
+func (s *MongoStorage) ReleaseStorage(project, workspace string) (release.Storage, error) {
+	return releasestorages.NewMongoStorage(s.db, s.timeout, project, workspace)
+}
+
+func (s *MongoStorage) StateStorageWithPath(path string) (release.Storage, error) {
+	return nil, fmt.Errorf("state storage with path is %w by %s backend", kerrors.ErrNotSupported, v1.BackendTypeMongo)
+}
+
+func (s *MongoStorage) GraphStorage(project, workspace string) (graph.Storage, error) {
+	return graphstorages.NewMongoStorage(s.db, s.timeout, project, workspace)
+}
+
+func (s *MongoStorage) ProjectStorage() (map[string][]string, error) {
+	return nil, fmt.Errorf("project storage is %w by %s backend", kerrors.ErrNotSupported, v1.BackendTypeMongo)
+}
@@ -0,0 +1,23 @@
+package storages
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	v1 "kusionstack.io/kusion/pkg/apis/api.kusion.io/v1"
+)
+
+func TestNewMongoStorage(t *testing.T) {
+	t.Run("failed to new mongo storage unreachable in timeout", func(t *testing.T) {
+		start := time.Now()
+		_, err := NewMongoStorage(&v1.BackendMongoConfig{
+			URI:      "mongodb://127.0.0.1:1",
+			Database: "kusion",
+			Timeout:  1,
+		})
+		assert.Error(t, err)
+		assert.Less(t, time.Since(start), 10*time.Second)
+	})
+}
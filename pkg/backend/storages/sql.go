@@ -0,0 +1,56 @@
+package storages
+
+import (
+	"database/sql"
+	"fmt"
+
+	// register the drivers of mysql and postgres, whose driver names are the same as the backend types.
+	_ "github.com/go-sql-driver/mysql"
+	_ "github.com/lib/pq"
+
+	v1 "kusionstack.io/kusion/pkg/apis/api.kusion.io/v1"
+	"kusionstack.io/kusion/pkg/engine/release"
+	releasestorages "kusionstack.io/kusion/pkg/engine/release/storages"
+	"kusionstack.io/kusion/pkg/engine/resource/graph"
+	graphstorages "kusionstack.io/kusion/pkg/engine/resource/graph/storages"
+	"kusionstack.io/kusion/pkg/util/kerrors"
+	"kusionstack.io/kusion/pkg/workspace"
+	workspacestorages "kusionstack.io/kusion/pkg/workspace/storages"
+)
+
+// SQLStorage is an implementation of backend.Backend which uses a relational database as storage.
+type SQLStorage struct {
+	db      *sql.DB
+	dialect string
+}
+
+func NewSQLStorage(config *v1.BackendSQLConfig) (*SQLStorage, error) {
+	db, err := sql.Open(config.Dialect, config.DSN)
+	if err != nil {
+		return nil, err
+	}
+	return &SQLStorage{
+		db:      db,
+		dialect: config.Dialect,
+	}, nil
+}
+
+func (s *SQLStorage) WorkspaceStorage() (workspace.Storage, error) {
+	return workspacestorages.NewSQLStorage(s.db, s.dialect)
+}
+
+func (s *SQLStorage) ReleaseStorage(project, workspace string) (release.Storage, error) {
+	return releasestorages.NewSQLStorage(s.db, s.dialect, project, workspace)
+}
+
+func (s *SQLStorage) StateStorageWithPath(path string) (release.Storage, error) {
+	return nil, fmt.Errorf("state storage with path is %w by %s backend", kerrors.ErrNotSupported, s.dialect)
+}
+
+func (s *SQLStorage) GraphStorage(project, workspace string) (graph.Storage, error) {
+	return graphstorages.NewSQLStorage(s.db, s.dialect, project, workspace)
+}
+
+func (s *SQLStorage) ProjectStorage() (map[string][]string, error) {
+	return nil, fmt.Errorf("project storage is %w by %s backend", kerrors.ErrNotSupported, s.dialect)
+}
@@ -1,18 +1,28 @@
 package storages
 
 import (
-	"errors"
 	"fmt"
 
 	v1 "kusionstack.io/kusion/pkg/apis/api.kusion.io/v1"
+	"kusionstack.io/kusion/pkg/util/kerrors"
 )
 
 var (
-	ErrEmptyBucket          = errors.New("empty bucket")
-	ErrEmptyAccessKeyID     = errors.New("empty access key id")
-	ErrEmptyAccessKeySecret = errors.New("empty access key secret")
-	ErrEmptyOssEndpoint     = errors.New("empty oss endpoint")
-	ErrEmptyS3Region        = errors.New("empty s3 region")
+	ErrEmptyBucket          = kerrors.New(kerrors.ErrValidation, "empty bucket")
+	ErrEmptyAccessKeyID     = kerrors.New(kerrors.ErrValidation, "empty access key id")
+	ErrEmptyAccessKeySecret = kerrors.New(kerrors.ErrValidation, "empty access key secret")
+	ErrEmptyOssEndpoint     = kerrors.New(kerrors.ErrValidation, "empty oss endpoint")
+	ErrEmptyS3Region        = kerrors.New(kerrors.ErrValidation, "empty s3 region")
+	ErrEmptyHTTPBaseURL     = kerrors.New(kerrors.ErrValidation, "empty http base url")
+	ErrNegativeHTTPTimeout  = kerrors.New(kerrors.ErrValidation, "negative http timeout")
+	ErrEmptySQLDSN          = kerrors.New(kerrors.ErrValidation, "empty sql dsn")
+	ErrEmptyMongoURI        = kerrors.New(kerrors.ErrValidation, "empty mongo uri")
+	ErrEmptyMongoDatabase   = kerrors.New(kerrors.ErrValidation, "empty mongo database")
+	ErrNegativeMongoTimeout = kerrors.New(kerrors.ErrValidation, "negative mongo timeout")
+	ErrInvalidEncryption    = kerrors.New(kerrors.ErrValidation, "invalid encryption")
+	ErrEmptyEncryptionKeyID = kerrors.New(kerrors.ErrValidation, "empty encryption key id")
+	ErrInvalidKeyProvider   = kerrors.New(kerrors.ErrValidation, "invalid encryption key provider")
+	ErrEmptyKeyServer       = kerrors.New(kerrors.ErrValidation, "empty encryption key server")
 )
 
 // ValidateOssConfig is used to validate v1.BackendOssConfig is valid or not, where all the items are included.
@@ -79,3 +89,57 @@ func validateGenericObjectStorageSecret(ak, sk string) error {
 	}
 	return nil
 }
+
+// ValidateHTTPConfig is used to validate v1.BackendHTTPConfig is valid or not.
+func ValidateHTTPConfig(config *v1.BackendHTTPConfig) error {
+	if config.BaseURL == "" {
+		return ErrEmptyHTTPBaseURL
+	}
+	if config.Timeout < 0 {
+		return ErrNegativeHTTPTimeout
+	}
+	return nil
+}
+
+// ValidateSQLConfig is used to validate v1.BackendSQLConfig is valid or not.
+func ValidateSQLConfig(config *v1.BackendSQLConfig) error {
+	if config.DSN == "" {
+		return fmt.Errorf("%w of %s", ErrEmptySQLDSN, config.Dialect)
+	}
+	return nil
+}
+
+// ValidateMongoConfig is used to validate v1.BackendMongoConfig is valid or not.
+func ValidateMongoConfig(config *v1.BackendMongoConfig) error {
+	if config.URI == "" {
+		return ErrEmptyMongoURI
+	}
+	if config.Database == "" {
+		return ErrEmptyMongoDatabase
+	}
+	if config.Timeout < 0 {
+		return ErrNegativeMongoTimeout
+	}
+	return nil
+}
+
+// ValidateEncryptionConfig is used to validate v1.BackendEncryptionConfig is valid or not.
+func ValidateEncryptionConfig(config *v1.BackendEncryptionConfig) error {
+	if config.Mode != v1.BackendEncryptionRelease && config.Mode != v1.BackendEncryptionSensitive {
+		return fmt.Errorf("%w %s, only %s and %s are supported", ErrInvalidEncryption, config.Mode, v1.BackendEncryptionRelease, v1.BackendEncryptionSensitive)
+	}
+	if config.KeyID == "" {
+		return ErrEmptyEncryptionKeyID
+	}
+	switch config.KeyProvider {
+	case "", v1.BackendEncryptionProviderLocal, v1.BackendEncryptionProviderKMS:
+	case v1.BackendEncryptionProviderVault:
+		if config.Server == "" {
+			return fmt.Errorf("%w of %s", ErrEmptyKeyServer, config.KeyProvider)
+		}
+	default:
+		return fmt.Errorf("%w %s, only %s, %s and %s are supported", ErrInvalidKeyProvider, config.KeyProvider,
+			v1.BackendEncryptionProviderLocal, v1.BackendEncryptionProviderVault, v1.BackendEncryptionProviderKMS)
+	}
+	return nil
+}
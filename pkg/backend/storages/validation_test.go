@@ -162,3 +162,189 @@ func TestValidateS3ConfigFromFile(t *testing.T) {
 		})
 	}
 }
+
+func TestValidateHTTPConfig(t *testing.T) {
+	testcases := []struct {
+		name    string
+		success bool
+		config  *v1.BackendHTTPConfig
+	}{
+		{
+			name:    "valid http config",
+			success: true,
+			config: &v1.BackendHTTPConfig{
+				BaseURL: "https://release.kusion.io",
+				Token:   "fake-token",
+			},
+		},
+		{
+			name:    "invalid http config empty base url",
+			success: false,
+			config: &v1.BackendHTTPConfig{
+				Token: "fake-token",
+			},
+		},
+		{
+			name:    "invalid http config negative timeout",
+			success: false,
+			config: &v1.BackendHTTPConfig{
+				BaseURL: "https://release.kusion.io",
+				Timeout: -1,
+			},
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := ValidateHTTPConfig(tc.config)
+			assert.Equal(t, tc.success, err == nil)
+		})
+	}
+}
+
+func TestValidateSQLConfig(t *testing.T) {
+	testcases := []struct {
+		name    string
+		success bool
+		config  *v1.BackendSQLConfig
+	}{
+		{
+			name:    "valid sql config",
+			success: true,
+			config: &v1.BackendSQLConfig{
+				Dialect: v1.BackendTypeMysql,
+				DSN:     "kusion:kusion@tcp(127.0.0.1:3306)/kusion",
+			},
+		},
+		{
+			name:    "invalid sql config empty dsn",
+			success: false,
+			config: &v1.BackendSQLConfig{
+				Dialect: v1.BackendTypePostgres,
+			},
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := ValidateSQLConfig(tc.config)
+			assert.Equal(t, tc.success, err == nil)
+		})
+	}
+}
+
+func TestValidateMongoConfig(t *testing.T) {
+	testcases := []struct {
+		name    string
+		success bool
+		config  *v1.BackendMongoConfig
+	}{
+		{
+			name:    "valid mongo config",
+			success: true,
+			config: &v1.BackendMongoConfig{
+				URI:      "mongodb://127.0.0.1:27017",
+				Database: "kusion",
+			},
+		},
+		{
+			name:    "invalid mongo config empty uri",
+			success: false,
+			config: &v1.BackendMongoConfig{
+				Database: "kusion",
+			},
+		},
+		{
+			name:    "invalid mongo config empty database",
+			success: false,
+			config: &v1.BackendMongoConfig{
+				URI: "mongodb://127.0.0.1:27017",
+			},
+		},
+		{
+			name:    "invalid mongo config negative timeout",
+			success: false,
+			config: &v1.BackendMongoConfig{
+				URI:      "mongodb://127.0.0.1:27017",
+				Database: "kusion",
+				Timeout:  -1,
+			},
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := ValidateMongoConfig(tc.config)
+			assert.Equal(t, tc.success, err == nil)
+		})
+	}
+}
+
+func TestValidateEncryptionConfig(t *testing.T) {
+	testcases := []struct {
+		name    string
+		success bool
+		config  *v1.BackendEncryptionConfig
+	}{
+		{
+			name:    "valid encryption config with default key provider",
+			success: true,
+			config: &v1.BackendEncryptionConfig{
+				Mode:  v1.BackendEncryptionRelease,
+				KeyID: "key-1",
+			},
+		},
+		{
+			name:    "valid encryption config with vault key provider",
+			success: true,
+			config: &v1.BackendEncryptionConfig{
+				Mode:        v1.BackendEncryptionSensitive,
+				KeyID:       "kusion",
+				KeyProvider: v1.BackendEncryptionProviderVault,
+				Server:      "https://vault.example.com",
+			},
+		},
+		{
+			name:    "valid encryption config with kms key provider",
+			success: true,
+			config: &v1.BackendEncryptionConfig{
+				Mode:        v1.BackendEncryptionSensitive,
+				KeyID:       "arn:aws:kms:us-east-1:123456789012:key/kusion",
+				KeyProvider: v1.BackendEncryptionProviderKMS,
+				Region:      "us-east-1",
+			},
+		},
+		{
+			name:    "invalid encryption config empty key id",
+			success: false,
+			config: &v1.BackendEncryptionConfig{
+				Mode: v1.BackendEncryptionRelease,
+			},
+		},
+		{
+			name:    "invalid encryption config vault key provider without server",
+			success: false,
+			config: &v1.BackendEncryptionConfig{
+				Mode:        v1.BackendEncryptionRelease,
+				KeyID:       "kusion",
+				KeyProvider: v1.BackendEncryptionProviderVault,
+			},
+		},
+		{
+			name:    "invalid encryption config unsupported key provider",
+			success: false,
+			config: &v1.BackendEncryptionConfig{
+				Mode:        v1.BackendEncryptionRelease,
+				KeyID:       "kusion",
+				KeyProvider: "gcpkms",
+			},
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := ValidateEncryptionConfig(tc.config)
+			assert.Equal(t, tc.success, err == nil)
+		})
+	}
+}
@@ -42,14 +42,20 @@ import (
 	"kusionstack.io/kusion/pkg/cmd/preview"
 	cmdutil "kusionstack.io/kusion/pkg/cmd/util"
 	"kusionstack.io/kusion/pkg/engine"
+	"kusionstack.io/kusion/pkg/engine/lint"
+	"kusionstack.io/kusion/pkg/engine/mutation"
 	"kusionstack.io/kusion/pkg/engine/operation"
+	opgraph "kusionstack.io/kusion/pkg/engine/operation/graph"
 	"kusionstack.io/kusion/pkg/engine/operation/models"
+	"kusionstack.io/kusion/pkg/engine/policy"
 	"kusionstack.io/kusion/pkg/engine/printers"
 	"kusionstack.io/kusion/pkg/engine/release"
 	"kusionstack.io/kusion/pkg/engine/resource/graph"
 	"kusionstack.io/kusion/pkg/engine/runtime"
 	runtimeinit "kusionstack.io/kusion/pkg/engine/runtime/init"
+	"kusionstack.io/kusion/pkg/generators"
 	"kusionstack.io/kusion/pkg/log"
+	"kusionstack.io/kusion/pkg/secrets"
 	"kusionstack.io/kusion/pkg/util/i18n"
 	"kusionstack.io/kusion/pkg/util/kcl"
 	"kusionstack.io/kusion/pkg/util/pretty"
@@ -88,7 +94,22 @@ var (
 		kusion apply --timeout=120
 
 		# Apply with localhost port forwarding
-		kusion apply --port-forward=8080`)
+		kusion apply --port-forward=8080
+
+		# Resume the apply interrupted midway with the spec of the release in applying phase
+		kusion apply --resume
+
+		# Apply with the outputs of the modules with unchanged inputs reused from the last release
+		kusion apply --incremental
+
+		# Apply without the lint warnings of the latest image tags and missing probes
+		kusion apply --disable-lint-rules=latest-image-tag,missing-probes
+
+		# Apply only if the spec satisfies the Rego policies in the directory
+		kusion apply --policy-dir=/path/to/policies
+
+		# Apply only if the resources satisfy the CEL rules in the file
+		kusion apply --cel-rules=/path/to/rules.yaml`)
 )
 
 // To handle the release phase update when panic occurs.
@@ -112,11 +133,17 @@ var errExit = errors.New("receive SIGTERM or SIGINT, exit cmd")
 type ApplyFlags struct {
 	*preview.PreviewFlags
 
-	Yes         bool
-	DryRun      bool
-	Watch       bool
-	Timeout     int
-	PortForward int
+	Yes              bool
+	DryRun           bool
+	Watch            bool
+	Timeout          int
+	PortForward      int
+	Resume           bool
+	DisableLintRules []string
+	PolicyDir        string
+	CELRules         string
+	ReleaseMetadata  map[string]string
+	Incremental      bool
 
 	genericiooptions.IOStreams
 }
@@ -125,12 +152,18 @@ type ApplyFlags struct {
 type ApplyOptions struct {
 	*preview.PreviewOptions
 
-	SpecFile    string
-	Yes         bool
-	DryRun      bool
-	Watch       bool
-	Timeout     int
-	PortForward int
+	SpecFile         string
+	Yes              bool
+	DryRun           bool
+	Watch            bool
+	Timeout          int
+	PortForward      int
+	Resume           bool
+	DisableLintRules []string
+	PolicyDir        string
+	CELRules         string
+	ReleaseMetadata  map[string]string
+	Incremental      bool
 
 	genericiooptions.IOStreams
 }
@@ -177,6 +210,12 @@ func (f *ApplyFlags) AddFlags(cmd *cobra.Command) {
 	cmd.Flags().BoolVarP(&f.Watch, "watch", "", true, i18n.T("After creating/updating/deleting the requested object, watch for changes"))
 	cmd.Flags().IntVarP(&f.Timeout, "timeout", "", 0, i18n.T("The timeout duration for kusion apply command, measured in second(s)"))
 	cmd.Flags().IntVarP(&f.PortForward, "port-forward", "", 0, i18n.T("Forward the specified port from local to service"))
+	cmd.Flags().BoolVarP(&f.Resume, "resume", "", false, i18n.T("Resume the apply of the latest release left in applying phase"))
+	cmd.Flags().StringVarP(&f.PolicyDir, "policy-dir", "", "", i18n.T("The directory of the Rego policies and data, which reject the spec violating any policy"))
+	cmd.Flags().StringVarP(&f.CELRules, "cel-rules", "", "", i18n.T("The YAML file of the CEL rules validating each resource, which reject the spec violating any rule"))
+	cmd.Flags().StringSliceVarP(&f.DisableLintRules, "disable-lint-rules", "", nil, i18n.T("The lint rules to silence, such as missing-resource-limits, latest-image-tag and missing-probes"))
+	cmd.Flags().BoolVarP(&f.Incremental, "incremental", "", false, i18n.T("Reuse the outputs of the modules with unchanged inputs in the last release instead of regenerating them"))
+	cmd.Flags().StringToStringVarP(&f.ReleaseMetadata, "release-metadata", "", nil, i18n.T("The metadata of the release for traceability, such as git-commit=<sha>,pull-request=<number>,triggered-by=<user>"))
 }
 
 // ToOptions converts from CLI inputs to runtime inputs.
@@ -188,14 +227,20 @@ func (f *ApplyFlags) ToOptions() (*ApplyOptions, error) {
 	}
 
 	o := &ApplyOptions{
-		PreviewOptions: previewOptions,
-		SpecFile:       f.SpecFile,
-		Yes:            f.Yes,
-		DryRun:         f.DryRun,
-		Watch:          f.Watch,
-		Timeout:        f.Timeout,
-		PortForward:    f.PortForward,
-		IOStreams:      f.IOStreams,
+		PreviewOptions:   previewOptions,
+		SpecFile:         f.SpecFile,
+		Yes:              f.Yes,
+		DryRun:           f.DryRun,
+		Watch:            f.Watch,
+		Timeout:          f.Timeout,
+		PortForward:      f.PortForward,
+		Resume:           f.Resume,
+		DisableLintRules: f.DisableLintRules,
+		PolicyDir:        f.PolicyDir,
+		CELRules:         f.CELRules,
+		ReleaseMetadata:  f.ReleaseMetadata,
+		Incremental:      f.Incremental,
+		IOStreams:        f.IOStreams,
 	}
 
 	return o, nil
@@ -211,6 +256,14 @@ func (o *ApplyOptions) Validate(cmd *cobra.Command, args []string) error {
 		return cmdutil.UsageErrorf(cmd, "Invalid port number to forward: %d, must be between 1 and 65535", o.PortForward)
 	}
 
+	if o.Resume && o.SpecFile != "" {
+		return cmdutil.UsageErrorf(cmd, "The --resume and --spec-file flags cannot be specified at the same time")
+	}
+
+	if o.Incremental && (o.Resume || o.SpecFile != "") {
+		return cmdutil.UsageErrorf(cmd, "The --incremental flag cannot be specified with the --resume or --spec-file flag")
+	}
+
 	if o.SpecFile != "" {
 		absSF, _ := filepath.Abs(o.SpecFile)
 		fi, err := os.Stat(absSF)
@@ -248,7 +301,7 @@ func (o *ApplyOptions) Run() (err error) {
 			return
 		}
 		if err != nil {
-			release.UpdateReleasePhase(rel, apiv1.ReleasePhaseFailed, relLock)
+			release.UpdateReleaseFailed(rel, err.Error(), relLock)
 			// Join the errors if update apply release failed.
 			err = errors.Join([]error{err, release.UpdateApplyRelease(releaseStorage, rel, o.DryRun, relLock)}...)
 		} else {
@@ -267,12 +320,21 @@ func (o *ApplyOptions) Run() (err error) {
 	if err != nil {
 		return
 	}
-	rel, err = release.NewApplyRelease(releaseStorage, o.RefProject.Name, o.RefStack.Name, o.RefWorkspace.Name)
-	if err != nil {
-		return
+	if o.Resume {
+		// resume the release left in applying phase, which has been created in the storage
+		rel, err = release.GetResumableRelease(releaseStorage)
+		if err != nil {
+			return
+		}
+		releaseCreated = !o.DryRun
+	} else {
+		rel, err = release.NewApplyRelease(releaseStorage, o.RefProject.Name, o.RefStack.Name, o.RefWorkspace.Name, release.WithMetadata(o.ReleaseMetadata))
+		if err != nil {
+			return
+		}
 	}
-	if !o.DryRun {
-		if err = releaseStorage.Create(rel); err != nil {
+	if !o.DryRun && !o.Resume {
+		if err = release.CreateRelease(releaseStorage, rel); err != nil {
 			return
 		}
 		releaseCreated = true
@@ -310,7 +372,7 @@ func (o *ApplyOptions) Run() (err error) {
 			if !releaseCreated {
 				return
 			}
-			release.UpdateReleasePhase(rel, apiv1.ReleasePhaseFailed, relLock)
+			release.UpdateReleaseFailed(rel, err.Error(), relLock)
 			err = errors.Join([]error{err, release.UpdateApplyRelease(releaseStorage, rel, o.DryRun, relLock)}...)
 			return err
 		}
@@ -331,7 +393,7 @@ func (o *ApplyOptions) run(rel *apiv1.Release, releaseStorage release.Storage) (
 			return
 		}
 		if err != nil {
-			release.UpdateReleasePhase(rel, apiv1.ReleasePhaseFailed, relLock)
+			release.UpdateReleaseFailed(rel, err.Error(), relLock)
 			err = errors.Join([]error{err, release.UpdateApplyRelease(releaseStorage, rel, o.DryRun, relLock)}...)
 		}
 	}()
@@ -345,28 +407,79 @@ func (o *ApplyOptions) run(rel *apiv1.Release, releaseStorage release.Storage) (
 
 	// generate Spec
 	var spec *apiv1.Spec
-	if o.SpecFile != "" {
+	if o.Resume {
+		// use the spec of the resumed release, so that the apply continues with the same spec
+		spec = rel.Spec
+	} else if o.SpecFile != "" {
 		spec, err = generate.SpecFromFile(o.SpecFile)
+	} else if o.Incremental || o.Timings {
+		opts := generate.GenerateSpecOptions{}
+		if o.Incremental {
+			// reuse the module outputs carried over from the last release, and persist the ones of this
+			// generation in the release for the next one
+			opts.ModuleOutputs = generators.NewModuleOutputCache(rel.ModuleOutputs)
+		}
+		if o.Timings {
+			opts.Timings = generators.NewTimingReport(nil)
+		}
+		spec, err = generate.GenerateSpecWithOptions(o.RefProject, o.RefStack, o.RefWorkspace, parameters, o.UI, o.NoStyle, opts)
+		rel.ModuleOutputs = opts.ModuleOutputs.Outputs()
+		if o.Timings {
+			generate.PrintTimings(o.IOStreams.Out, opts.Timings)
+		}
 	} else {
 		spec, err = generate.GenerateSpecWithSpinner(o.RefProject, o.RefStack, o.RefWorkspace, parameters, o.UI, o.NoStyle)
+		// the module outputs are only persisted by the incremental generation
+		rel.ModuleOutputs = nil
 	}
 	if err != nil {
 		return
 	}
 
+	// mutate the spec with the mutation webhook if configured, except the resumed one mutated before
+	if !o.Resume {
+		if spec, err = mutation.MutateWithEnvWebhook(context.Background(), spec); err != nil {
+			return
+		}
+	}
+
 	// return immediately if no resource found in stack
 	if spec == nil || len(spec.Resources) == 0 {
 		fmt.Println(pretty.GreenBold("\nNo resource found in this stack."))
 		return nil
 	}
 
-	// update release phase to previewing
-	rel.Spec = spec
-	release.UpdateReleasePhase(rel, apiv1.ReleasePhasePreviewing, relLock)
-	if err = release.UpdateApplyRelease(releaseStorage, rel, o.DryRun, relLock); err != nil {
+	// check the secrets required by the spec exist before applying
+	if err = preflightSecrets(spec); err != nil {
 		return
 	}
 
+	// reject the spec violating any policy or rule
+	if o.PolicyDir != "" {
+		if err = checkPolicies(o.PolicyDir, spec); err != nil {
+			return
+		}
+	}
+	if o.CELRules != "" {
+		if err = checkCELRules(o.CELRules, spec); err != nil {
+			return
+		}
+	}
+
+	// warn the common misconfigurations without blocking the apply
+	for _, finding := range lint.Lint(spec, lint.WithDisabledRules(o.DisableLintRules...)) {
+		pretty.WarningT.Println(finding.String())
+	}
+
+	// update release phase to previewing, except the resumed one which is already in applying phase
+	rel.Spec = spec
+	if !o.Resume {
+		release.UpdateReleasePhase(rel, apiv1.ReleasePhasePreviewing, relLock)
+		if err = release.UpdateApplyRelease(releaseStorage, rel, o.DryRun, relLock); err != nil {
+			return
+		}
+	}
+
 	// compute changes for preview
 	changes, err := preview.Preview(o.PreviewOptions, releaseStorage, rel.Spec, rel.State, o.RefProject, o.RefStack)
 	if err != nil {
@@ -476,6 +589,51 @@ func (o *ApplyOptions) run(rel *apiv1.Release, releaseStorage release.Storage) (
 	return
 }
 
+// preflightSecrets verifies that all the secrets referred in the spec resolve in the secret store of the
+// workspace, and returns the consolidated list of the missing secrets if not.
+func preflightSecrets(spec *apiv1.Spec) error {
+	refs := opgraph.CollectSecretRefs(spec)
+	if len(refs) == 0 {
+		return nil
+	}
+	if spec.SecretStore == nil {
+		return fmt.Errorf("%d secret ref(s) found in spec, but no secret store configured in workspace", len(refs))
+	}
+	provider, exist := secrets.GetProvider(spec.SecretStore.Provider)
+	if !exist {
+		return errors.New("no matched secret store found, please check workspace yaml")
+	}
+	store, err := provider.NewSecretStore(spec.SecretStore)
+	if err != nil {
+		return err
+	}
+	return secrets.PreflightSecrets(context.Background(), store, refs)
+}
+
+// checkPolicies evaluates the spec against the Rego policies in the directory with OPA, and returns the
+// deny messages if any policy denies.
+func checkPolicies(dir string, spec *apiv1.Spec) error {
+	evaluator, err := policy.NewOPAEvaluator(dir, "")
+	if err != nil {
+		return err
+	}
+	return policy.Check(context.Background(), evaluator, spec)
+}
+
+// checkCELRules evaluates the CEL rules in the file against each resource of the spec, and returns the
+// messages of the violated rules if any.
+func checkCELRules(path string, spec *apiv1.Spec) error {
+	rules, err := policy.LoadCELRules(path)
+	if err != nil {
+		return err
+	}
+	evaluator, err := policy.NewCELEvaluator(rules)
+	if err != nil {
+		return err
+	}
+	return policy.Check(context.Background(), evaluator, spec)
+}
+
 // The Apply function will apply the resources changes through the execution kusion engine.
 // You can customize the runtime of engine and the release releaseStorage through `runtime` and `releaseStorage` parameters.
 func Apply(
@@ -496,7 +654,7 @@ func Apply(
 			if !releaseCreated {
 				return
 			}
-			release.UpdateReleasePhase(rel, apiv1.ReleasePhaseFailed, relLock)
+			release.UpdateReleaseFailed(rel, err.Error(), relLock)
 			err = errors.Join([]error{err, release.UpdateApplyRelease(releaseStorage, rel, o.DryRun, relLock)}...)
 		}
 
@@ -632,32 +790,25 @@ func Apply(
 		)
 	}
 
+	// In the dry-run mode, the whole apply process runs against the recording runtimes, which perform
+	// nothing in the actual infra.
 	var updatedRel *apiv1.Release
-	if o.DryRun {
-		for _, r := range rel.Spec.Resources {
-			ac.MsgCh <- models.Message{
-				ResourceID: r.ResourceKey(),
-				OpResult:   models.Success,
-				OpErr:      nil,
-			}
-		}
-		close(ac.MsgCh)
-	} else {
-		// parse cluster in arguments
-		rsp, st := ac.Apply(&operation.ApplyRequest{
-			Request: models.Request{
-				Project: changes.Project(),
-				Stack:   changes.Stack(),
-			},
-			Release: rel,
-			Graph:   gph,
-		})
-		if v1.IsErr(st) {
-			errWriter.(*bytes.Buffer).Reset()
-			err = fmt.Errorf("apply failed, status:\n%v", st)
-			return nil, err
-		}
-		// Update the release with that in the apply response if not dryrun.
+	rsp, st := ac.Apply(&operation.ApplyRequest{
+		Request: models.Request{
+			Project: changes.Project(),
+			Stack:   changes.Stack(),
+		},
+		Release: rel,
+		Graph:   gph,
+		DryRun:  o.DryRun,
+	})
+	if v1.IsErr(st) {
+		errWriter.(*bytes.Buffer).Reset()
+		err = fmt.Errorf("apply failed, status:\n%v", st)
+		return nil, err
+	}
+	// Update the release with that in the apply response if not dryrun.
+	if !o.DryRun {
 		updatedRel = rsp.Release
 		*rel = *updatedRel
 		gph = rsp.Graph
@@ -710,7 +861,7 @@ func PrintApplyDetails(
 			if !releaseCreated {
 				return
 			}
-			release.UpdateReleasePhase(rel, apiv1.ReleasePhaseFailed, relLock)
+			release.UpdateReleaseFailed(rel, (*err).Error(), relLock)
 			*err = errors.Join([]error{*err, release.UpdateApplyRelease(releaseStorage, rel, dryRun, relLock)}...)
 		}
 		(*errWriter).(*bytes.Buffer).Reset()
@@ -818,7 +969,7 @@ func Watch(
 				if !releaseCreated {
 					return
 				}
-				release.UpdateReleasePhase(rel, apiv1.ReleasePhaseFailed, relLock)
+				release.UpdateReleaseFailed(rel, (*err).Error(), relLock)
 				_ = release.UpdateApplyRelease(releaseStorage, rel, dryRun, relLock)
 			}
 
@@ -1015,7 +1166,7 @@ func watchK8sResources(
 			if !releaseCreated {
 				return
 			}
-			release.UpdateReleasePhase(rel, apiv1.ReleasePhaseFailed, relLock)
+			release.UpdateReleaseFailed(rel, err.Error(), relLock)
 			_ = release.UpdateApplyRelease(releaseStorage, rel, dryRun, relLock)
 		}
 	}()
@@ -1106,7 +1257,7 @@ func watchTFResources(
 			if !releaseCreated {
 				return
 			}
-			release.UpdateReleasePhase(rel, apiv1.ReleasePhaseFailed, relLock)
+			release.UpdateReleaseFailed(rel, err.Error(), relLock)
 			_ = release.UpdateApplyRelease(releaseStorage, rel, dryRun, relLock)
 		}
 	}()
@@ -17,6 +17,7 @@ package apply
 import (
 	"bytes"
 	"context"
+	"encoding/base64"
 	"errors"
 	"io"
 	"testing"
@@ -39,11 +40,14 @@ import (
 	"kusionstack.io/kusion/pkg/engine/operation"
 	"kusionstack.io/kusion/pkg/engine/operation/models"
 	"kusionstack.io/kusion/pkg/engine/printers"
+	"kusionstack.io/kusion/pkg/engine/release"
 	releasestorages "kusionstack.io/kusion/pkg/engine/release/storages"
 	"kusionstack.io/kusion/pkg/engine/resource/graph"
 	graphstorages "kusionstack.io/kusion/pkg/engine/resource/graph/storages"
 	"kusionstack.io/kusion/pkg/engine/runtime"
 	"kusionstack.io/kusion/pkg/engine/runtime/kubernetes"
+	"kusionstack.io/kusion/pkg/secrets"
+	_ "kusionstack.io/kusion/pkg/secrets/providers/fake"
 	"kusionstack.io/kusion/pkg/util/terminal"
 	workspacestorages "kusionstack.io/kusion/pkg/workspace/storages"
 )
@@ -210,6 +214,75 @@ func TestApplyOptions_Run(t *testing.T) {
 	})
 }
 
+func TestApplyOptions_Run_Failed(t *testing.T) {
+	mockey.PatchConvey("the failure message is stored on the release", t, func() {
+		mockey.Mock(generate.GenerateSpecWithSpinner).Return(nil, errors.New("generate spec failed")).Build()
+		mockWorkspaceStorage()
+		mockey.Mock((*storages.LocalStorage).ReleaseStorage).Return(&releasestorages.LocalStorage{}, nil).Build()
+		mockey.Mock((*releasestorages.LocalStorage).Create).Return(nil).Build()
+		mockey.Mock((*releasestorages.LocalStorage).GetLatestRevision).Return(0).Build()
+		mockey.Mock((*releasestorages.LocalStorage).Get).Return(&apiv1.Release{State: &apiv1.State{}, Phase: apiv1.ReleasePhaseSucceeded}, nil).Build()
+		var stored *apiv1.Release
+		mockey.Mock((*releasestorages.LocalStorage).Update).To(func(_ *releasestorages.LocalStorage, r *apiv1.Release) error {
+			stored = r
+			return nil
+		}).Build()
+
+		o := newApplyOptions()
+		err := o.Run()
+		assert.ErrorContains(t, err, "generate spec failed")
+		assert.NotNil(t, stored)
+		assert.Equal(t, apiv1.ReleasePhaseFailed, stored.Phase)
+		assert.Contains(t, stored.Message, "generate spec failed")
+		assert.Empty(t, stored.FailedResource)
+	})
+}
+
+func TestApplyOptions_Run_Resume(t *testing.T) {
+	mockey.PatchConvey("resume the release in applying phase", t, func() {
+		mockPatchNewKubernetesRuntime()
+		mockPatchOperationPreview()
+		mockWorkspaceStorage()
+		mockGraphStorage()
+		mockOperationApply(models.Success)
+		mockey.Mock((*storages.LocalStorage).ReleaseStorage).Return(&releasestorages.LocalStorage{}, nil).Build()
+		mockey.Mock(release.GetResumableRelease).Return(&apiv1.Release{
+			Project:   proj.Name,
+			Workspace: workspace.Name,
+			Revision:  1,
+			Stack:     stack.Name,
+			Spec:      &apiv1.Spec{Resources: []apiv1.Resource{sa1, sa2, sa3}},
+			State:     &apiv1.State{},
+			Phase:     apiv1.ReleasePhaseApplying,
+		}, nil).Build()
+
+		// record the phases persisted and the transitions rejected by the real SetPhase
+		var phases []apiv1.ReleasePhase
+		mockey.Mock((*releasestorages.LocalStorage).Update).To(func(_ *releasestorages.LocalStorage, r *apiv1.Release) error {
+			phases = append(phases, r.Phase)
+			return nil
+		}).Build()
+		var setPhase func(*apiv1.Release, apiv1.ReleasePhase) error
+		var setPhaseErrs []error
+		mockey.Mock(release.SetPhase).Origin(&setPhase).To(func(r *apiv1.Release, next apiv1.ReleasePhase) error {
+			err := setPhase(r, next)
+			if err != nil {
+				setPhaseErrs = append(setPhaseErrs, err)
+			}
+			return err
+		}).Build()
+
+		o := newApplyOptions()
+		o.Resume = true
+		o.Yes = true
+		err := o.Run()
+		assert.Nil(t, err)
+		assert.Empty(t, setPhaseErrs)
+		assert.NotContains(t, phases, apiv1.ReleasePhasePreviewing)
+		assert.Equal(t, apiv1.ReleasePhaseSucceeded, phases[len(phases)-1])
+	})
+}
+
 const (
 	apiVersion = "v1"
 	kind       = "ServiceAccount"
@@ -264,11 +337,14 @@ func TestApply(t *testing.T) {
 		}
 
 		changes := models.NewChanges(proj, stack, order)
-		graph := &apiv1.Graph{}
+		gph := &apiv1.Graph{Project: rel.Project, Workspace: rel.Workspace}
+		graph.GenerateGraph(rel.Spec.Resources, gph)
 		o := newApplyOptions()
 		o.DryRun = true
-		_, err := Apply(o, &releasestorages.LocalStorage{}, rel, graph, changes)
+		_, err := Apply(o, &releasestorages.LocalStorage{}, rel, gph, changes)
 		assert.Nil(t, err)
+		// the release is not applied in the dry-run mode
+		assert.Empty(t, rel.State.Resources)
 	})
 	mockey.PatchConvey("apply success", t, func() {
 		mockOperationApply(models.Success)
@@ -646,3 +722,70 @@ func TestGetResourceInfo(t *testing.T) {
 		})
 	}
 }
+
+func TestPreflightSecrets(t *testing.T) {
+	secretStore := &apiv1.SecretStore{
+		Provider: &apiv1.ProviderSpec{
+			Fake: &apiv1.FakeProvider{
+				Data: []apiv1.FakeProviderData{
+					{Key: "db-credentials", Value: `{"username":"admin"}`},
+				},
+			},
+		},
+	}
+	newSpec := func(refs ...string) *apiv1.Spec {
+		data := map[string]interface{}{}
+		for i, ref := range refs {
+			data[string(rune('a'+i))] = base64.StdEncoding.EncodeToString([]byte(ref))
+		}
+		return &apiv1.Spec{
+			Resources: apiv1.Resources{
+				{
+					ID:         "v1:Secret:default:db",
+					Type:       apiv1.Kubernetes,
+					Attributes: map[string]interface{}{"apiVersion": "v1", "kind": "Secret", "data": data},
+				},
+			},
+			SecretStore: secretStore,
+		}
+	}
+
+	t.Run("all present", func(t *testing.T) {
+		assert.NoError(t, preflightSecrets(newSpec("ref://db-credentials/username")))
+	})
+
+	t.Run("some missing", func(t *testing.T) {
+		err := preflightSecrets(newSpec("ref://db-credentials/username", "ref://db-credentials/password", "ref://api-token"))
+		assert.ErrorIs(t, err, secrets.ErrSecretPreflightFailed)
+		assert.ErrorContains(t, err, "not found: api-token")
+		assert.ErrorContains(t, err, "not found: db-credentials/password")
+	})
+
+	t.Run("no secret store", func(t *testing.T) {
+		spec := newSpec("ref://api-token")
+		spec.SecretStore = nil
+		assert.Error(t, preflightSecrets(spec))
+	})
+
+	t.Run("refs not resolved when applying", func(t *testing.T) {
+		// the ref:// strings in the labels and the module extensions are kept as they are when applying
+		spec := &apiv1.Spec{
+			Resources: apiv1.Resources{
+				{
+					ID:   "apps/v1:Deployment:default:db",
+					Type: apiv1.Kubernetes,
+					Attributes: map[string]interface{}{
+						"apiVersion": "apps/v1",
+						"kind":       "Deployment",
+						"metadata": map[string]interface{}{
+							"name":   "db",
+							"labels": map[string]interface{}{"docs": "ref://db-runbook"},
+						},
+					},
+					Extensions: map[string]interface{}{"kusionstack.io/source": "ref://modules/mysql"},
+				},
+			},
+		}
+		assert.NoError(t, preflightSecrets(spec))
+	})
+}
@@ -62,10 +62,11 @@ var (
 type DeleteFlags struct {
 	MetaFlags *meta.MetaFlags
 
-	Operator string
-	Yes      bool
-	Detail   bool
-	NoStyle  bool
+	Operator        string
+	Yes             bool
+	Detail          bool
+	NoStyle         bool
+	ReleaseMetadata map[string]string
 
 	UI *terminal.UI
 
@@ -76,9 +77,10 @@ type DeleteFlags struct {
 type DestroyOptions struct {
 	*meta.MetaOptions
 
-	Yes     bool
-	Detail  bool
-	NoStyle bool
+	Yes             bool
+	Detail          bool
+	NoStyle         bool
+	ReleaseMetadata map[string]string
 
 	UI *terminal.UI
 
@@ -126,6 +128,7 @@ func (flags *DeleteFlags) AddFlags(cmd *cobra.Command) {
 	cmd.Flags().BoolVarP(&flags.Yes, "yes", "y", false, i18n.T("Automatically approve and perform the update after previewing it"))
 	cmd.Flags().BoolVarP(&flags.Detail, "detail", "d", false, i18n.T("Automatically show preview details after previewing it"))
 	cmd.Flags().BoolVarP(&flags.NoStyle, "no-style", "", false, i18n.T("no-style sets to RawOutput mode and disables all of styling"))
+	cmd.Flags().StringToStringVarP(&flags.ReleaseMetadata, "release-metadata", "", nil, i18n.T("The metadata of the release for traceability, such as git-commit=<sha>,pull-request=<number>,triggered-by=<user>"))
 }
 
 // ToOptions converts from CLI inputs to runtime inputs.
@@ -137,12 +140,13 @@ func (flags *DeleteFlags) ToOptions() (*DestroyOptions, error) {
 	}
 
 	o := &DestroyOptions{
-		MetaOptions: metaOptions,
-		Detail:      flags.Detail,
-		Yes:         flags.Yes,
-		NoStyle:     flags.NoStyle,
-		UI:          flags.UI,
-		IOStreams:   flags.IOStreams,
+		MetaOptions:     metaOptions,
+		Detail:          flags.Detail,
+		Yes:             flags.Yes,
+		NoStyle:         flags.NoStyle,
+		ReleaseMetadata: flags.ReleaseMetadata,
+		UI:              flags.UI,
+		IOStreams:       flags.IOStreams,
 	}
 
 	return o, nil
@@ -168,11 +172,12 @@ func (o *DestroyOptions) Run() (err error) {
 			return
 		}
 		if err != nil {
-			rel.Phase = apiv1.ReleasePhaseFailed
+			_ = release.SetPhase(rel, apiv1.ReleasePhaseFailed)
 			_ = release.UpdateDestroyRelease(storage, rel)
 		} else {
-			rel.Phase = apiv1.ReleasePhaseSucceeded
-			err = release.UpdateDestroyRelease(storage, rel)
+			if err = release.SetPhase(rel, apiv1.ReleasePhaseSucceeded); err == nil {
+				err = release.UpdateDestroyRelease(storage, rel)
+			}
 		}
 	}()
 
@@ -181,7 +186,7 @@ func (o *DestroyOptions) Run() (err error) {
 	if err != nil {
 		return
 	}
-	rel, err = release.CreateDestroyRelease(storage, o.RefProject.Name, o.RefStack.Name, o.RefWorkspace.Name)
+	rel, err = release.CreateDestroyRelease(storage, o.RefProject.Name, o.RefStack.Name, o.RefWorkspace.Name, release.WithMetadata(o.ReleaseMetadata))
 	if err != nil {
 		return
 	}
@@ -207,10 +212,10 @@ func (o *DestroyOptions) Run() (err error) {
 	}()
 
 	if err = <-errCh; err != nil {
-		rel.Phase = apiv1.ReleasePhaseFailed
+		_ = release.SetPhase(rel, apiv1.ReleasePhaseFailed)
 		release.UpdateDestroyRelease(storage, rel)
 	} else {
-		rel.Phase = apiv1.ReleasePhaseSucceeded
+		_ = release.SetPhase(rel, apiv1.ReleasePhaseSucceeded)
 		release.UpdateDestroyRelease(storage, rel)
 		graphStorage, _ := o.Backend.GraphStorage(o.RefProject.Name, o.RefWorkspace.Name)
 		// Remove resource graph if resources are destroyed
@@ -228,11 +233,12 @@ func (o *DestroyOptions) run(rel *apiv1.Release, storage release.Storage) (err e
 	// update release to succeeded or failed
 	defer func() {
 		if err != nil {
-			rel.Phase = apiv1.ReleasePhaseFailed
+			_ = release.SetPhase(rel, apiv1.ReleasePhaseFailed)
 			release.UpdateDestroyRelease(storage, rel)
 		} else {
-			rel.Phase = apiv1.ReleasePhaseSucceeded
-			err = release.UpdateDestroyRelease(storage, rel)
+			if err = release.SetPhase(rel, apiv1.ReleasePhaseSucceeded); err == nil {
+				err = release.UpdateDestroyRelease(storage, rel)
+			}
 		}
 	}()
 
@@ -291,7 +297,9 @@ func (o *DestroyOptions) run(rel *apiv1.Release, storage release.Storage) (err e
 	}
 
 	// update release phase to destroying
-	rel.Phase = apiv1.ReleasePhaseDestroying
+	if err = release.SetPhase(rel, apiv1.ReleasePhaseDestroying); err != nil {
+		return
+	}
 	if err = release.UpdateDestroyRelease(storage, rel); err != nil {
 		return
 	}
@@ -465,7 +473,7 @@ func prompt(ui *terminal.UI, rel *apiv1.Release, storage release.Storage) (strin
 		WithDefaultOption("details").
 		// To gracefully exit if interrupted by SIGINT or SIGTERM.
 		WithOnInterruptFunc(func() {
-			rel.Phase = apiv1.ReleasePhaseFailed
+			_ = release.SetPhase(rel, apiv1.ReleasePhaseFailed)
 			release.UpdateDestroyRelease(storage, rel)
 			os.Exit(1)
 		}).
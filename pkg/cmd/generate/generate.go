@@ -19,7 +19,9 @@ import (
 	"fmt"
 	"io"
 	"os"
+	"sort"
 	"strings"
+	"time"
 
 	"github.com/liu-hm19/pterm"
 	"github.com/spf13/cobra"
@@ -32,6 +34,7 @@ import (
 	cmdutil "kusionstack.io/kusion/pkg/cmd/util"
 	"kusionstack.io/kusion/pkg/engine/api/generate/generator"
 	"kusionstack.io/kusion/pkg/engine/api/generate/run"
+	"kusionstack.io/kusion/pkg/generators"
 	"kusionstack.io/kusion/pkg/util/i18n"
 	"kusionstack.io/kusion/pkg/util/terminal"
 )
@@ -207,6 +210,28 @@ func GenerateSpecWithSpinner(
 	parameters map[string]string,
 	ui *terminal.UI,
 	noStyle bool,
+) (*v1.Spec, error) {
+	return GenerateSpecWithOptions(project, stack, workspace, parameters, ui, noStyle, GenerateSpecOptions{})
+}
+
+// GenerateSpecOptions are the optional caches and reports of the generation.
+type GenerateSpecOptions struct {
+	// ModuleOutputs reuses the outputs of the modules with unchanged inputs in the cache, and records the
+	// module outputs of this generation in it.
+	ModuleOutputs *generators.ModuleOutputCache
+	// Timings records the time spent by each app, module and built-in generator.
+	Timings *generators.TimingReport
+}
+
+// GenerateSpecWithOptions calls generator to generate versioned Spec with the options.
+func GenerateSpecWithOptions(
+	project *v1.Project,
+	stack *v1.Stack,
+	workspace *v1.Workspace,
+	parameters map[string]string,
+	ui *terminal.UI,
+	noStyle bool,
+	opts GenerateSpecOptions,
 ) (*v1.Spec, error) {
 	// Construct generator instance
 	defaultGenerator := &generator.DefaultGenerator{
@@ -218,6 +243,8 @@ func GenerateSpecWithSpinner(
 			Username: os.Getenv("KUSION_MODULE_REGISTRY_USERNAME"),
 			Password: os.Getenv("KUSION_MODULE_REGISTRY_PASSWORD"),
 		},
+		Timings:       opts.Timings,
+		ModuleOutputs: opts.ModuleOutputs,
 	}
 
 	if noStyle {
@@ -250,6 +277,31 @@ func GenerateSpecWithSpinner(
 	return versionedSpec, nil
 }
 
+// PrintTimings prints the time spent by each app, module and built-in generator in the report, the
+// slowest first.
+func PrintTimings(w io.Writer, report *generators.TimingReport) {
+	timings := report.Timings()
+	sort.SliceStable(timings, func(i, j int) bool {
+		return timings[i].Duration > timings[j].Duration
+	})
+
+	tableData := pterm.TableData{{"Name", "Duration", "Status"}}
+	for _, timing := range timings {
+		status := "Succeeded"
+		if timing.Failed {
+			status = "Failed"
+		}
+		tableData = append(tableData, []string{timing.Name, timing.Duration.Round(time.Millisecond).String(), status})
+	}
+	_ = pterm.DefaultTable.WithHasHeader().
+		WithHeaderStyle(&pterm.ThemeDefault.TableHeaderStyle).
+		WithLeftAlignment(true).
+		WithSeparator("  ").
+		WithData(tableData).
+		WithWriter(w).
+		Render()
+}
+
 func SpecFromFile(filePath string) (*v1.Spec, error) {
 	b, err := os.ReadFile(filePath)
 	if err != nil {
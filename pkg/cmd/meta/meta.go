@@ -21,6 +21,7 @@ import (
 	"kusionstack.io/kusion/pkg/backend"
 	"kusionstack.io/kusion/pkg/project"
 	"kusionstack.io/kusion/pkg/util/i18n"
+	"kusionstack.io/kusion/pkg/workspace"
 )
 
 // MetaFlags directly reflect the information that CLI is gathering via flags. They will be converted to
@@ -103,12 +104,33 @@ func (f *MetaFlags) ToOptions() (*MetaOptions, error) {
 	}
 	opts.Backend = storageBackend
 
+	// Validate the workspace referenced by the stack exists
+	if refStack != nil && refStack.Workspace != "" && storageBackend != nil {
+		workspaceStorage, err := storageBackend.WorkspaceStorage()
+		if err != nil {
+			return nil, err
+		}
+		if err = workspace.ValidateStackWorkspace(refStack, workspaceStorage); err != nil {
+			return nil, err
+		}
+	}
+
 	// Get current workspace from backend
-	workspace, err := f.ParseWorkspace(storageBackend)
+	refWorkspace, err := f.ParseWorkspace(storageBackend)
 	if err != nil {
 		return nil, err
 	}
-	opts.RefWorkspace = workspace
+	opts.RefWorkspace = refWorkspace
+
+	// Store the releases and graphs of the stack in its own backend if it declares one,
+	// the workspace itself is still read from the backend given by the flag
+	if refStack != nil && refStack.Backend != "" {
+		stackBackend, err := backend.NewStackBackend(refStack, refWorkspace)
+		if err != nil {
+			return nil, err
+		}
+		opts.Backend = stackBackend
+	}
 
 	return opts, nil
 }
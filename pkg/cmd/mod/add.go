@@ -3,6 +3,7 @@ package mod
 import (
 	"fmt"
 	"net/url"
+	"os"
 	"runtime"
 	"strings"
 
@@ -17,6 +18,7 @@ import (
 	"kusionstack.io/kusion/pkg/cmd/meta"
 	"kusionstack.io/kusion/pkg/cmd/util"
 	"kusionstack.io/kusion/pkg/util/i18n"
+	wsutil "kusionstack.io/kusion/pkg/workspace"
 )
 
 var addExample = i18n.T(`# Add a kusion module to the kcl.mod from the current workspace to use it in AppConfiguration 
@@ -83,8 +85,15 @@ func (o *AddOptions) Run() error {
 		dependencies = orderedmap.NewOrderedMap[string, pkg.Dependency]()
 	}
 
-	// path example: oci://ghcr.io/kusionstack/service
-	u, err := url.Parse(m.Path)
+	// path example: oci://ghcr.io/kusionstack/service, or service in the default module registry
+	path, err := wsutil.ResolveModulePath(m.Path, os.Getenv("KUSION_MODULE_REGISTRY_HOST"))
+	if err != nil {
+		return fmt.Errorf("invalid module path: %s, %w", m.Path, err)
+	}
+	if scheme := wsutil.DetectPathScheme(path); scheme != wsutil.PathSchemeOCI {
+		return fmt.Errorf("unsupported %s module path: %s, only the OCI module can be added", scheme, m.Path)
+	}
+	u, err := url.Parse(path)
 	if err != nil {
 		// at least two parts: host and module name are required
 		return fmt.Errorf("invalid module path: %s", m.Path)
@@ -36,6 +36,7 @@ func TestAddOptions_Run(t *testing.T) {
 	})
 
 	t.Run("InvalidModulePath", func(t *testing.T) {
+		t.Setenv("KUSION_MODULE_REGISTRY_HOST", "")
 		o := &AddOptions{
 			MetaOptions: meta.MetaOptions{
 				RefStack: &v1.Stack{
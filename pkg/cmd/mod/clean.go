@@ -0,0 +1,154 @@
+package mod
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/spf13/cobra"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/cli-runtime/pkg/genericiooptions"
+	"k8s.io/kubectl/pkg/util/templates"
+	"kcl-lang.io/kpm/pkg/env"
+
+	cmdutil "kusionstack.io/kusion/pkg/cmd/util"
+	"kusionstack.io/kusion/pkg/util/i18n"
+	wsutil "kusionstack.io/kusion/pkg/workspace"
+)
+
+var (
+	cleanLong = i18n.T(`
+	The clean command evicts the cached kusion modules, including the ones pulled from the OCI registries
+	into the kpm package cache, and the ones fetched from the Git repos.
+
+	The modules unused for longer than the max age are evicted first, and then the least recently used
+	ones until the total size of the cache is within the max size. The modules in use by the running
+	operations are never evicted.`)
+
+	cleanExample = i18n.T(`
+	# Evict the cached modules unused for 30 days
+	kusion mod clean --max-age 720h
+
+	# Evict the least recently used modules to keep the cache within 1Gi
+	kusion mod clean --max-size 1Gi`)
+)
+
+// CleanModFlags directly reflects the information that CLI is gathering via flags. They will be converted to
+// CleanModOptions, which reflects the runtime requirements for the command.
+type CleanModFlags struct {
+	MaxSize string
+	MaxAge  time.Duration
+
+	genericiooptions.IOStreams
+}
+
+// CleanModOptions is a set of options that allows you to evict the cached kusion modules.
+// This is the object reflects the runtime needs of a `mod clean` command, making the logic itself easy to unit test.
+type CleanModOptions struct {
+	// CacheDirs are the directories of the module caches, where the max size applies to each cache.
+	CacheDirs []string
+	MaxSize   int64
+	MaxAge    time.Duration
+
+	genericiooptions.IOStreams
+}
+
+// NewCleanModFlags returns a default CleanModFlags.
+func NewCleanModFlags(ioStreams genericiooptions.IOStreams) *CleanModFlags {
+	return &CleanModFlags{
+		IOStreams: ioStreams,
+	}
+}
+
+// NewCmdClean returns an initialized Command instance for the `mod clean` sub command.
+func NewCmdClean(ioStreams genericiooptions.IOStreams) *cobra.Command {
+	flags := NewCleanModFlags(ioStreams)
+
+	cmd := &cobra.Command{
+		Use:                   "clean [--max-size size] [--max-age duration]",
+		DisableFlagsInUseLine: true,
+		Short:                 "Evict the cached kusion modules",
+		Long:                  templates.LongDesc(cleanLong),
+		Example:               templates.Examples(cleanExample),
+		RunE: func(cmd *cobra.Command, args []string) (err error) {
+			o, err := flags.ToOptions()
+			defer cmdutil.RecoverErr(&err)
+			cmdutil.CheckErr(err)
+			cmdutil.CheckErr(o.Validate())
+			cmdutil.CheckErr(o.Run())
+			return
+		},
+	}
+
+	flags.AddFlags(cmd)
+
+	return cmd
+}
+
+// AddFlags registers flags for a cli.
+func (flags *CleanModFlags) AddFlags(cmd *cobra.Command) {
+	cmd.Flags().StringVar(&flags.MaxSize, "max-size", "", "The max total size of the cached modules, e.g. 500Mi or 1Gi.")
+	cmd.Flags().DurationVar(&flags.MaxAge, "max-age", 0, "The max duration since the cached modules were last used, e.g. 720h.")
+}
+
+// ToOptions converts from CLI inputs to runtime inputs.
+func (flags *CleanModFlags) ToOptions() (*CleanModOptions, error) {
+	gitCacheDir, err := wsutil.DefaultGitModuleCacheDir()
+	if err != nil {
+		return nil, err
+	}
+	// the kusion modules in the OCI registries are pulled into the kpm package cache
+	kpmCacheDir, err := env.GetAbsPkgPath()
+	if err != nil {
+		return nil, err
+	}
+
+	var maxSize int64
+	if flags.MaxSize != "" {
+		quantity, err := resource.ParseQuantity(flags.MaxSize)
+		if err != nil {
+			return nil, fmt.Errorf("invalid max size %s: %w", flags.MaxSize, err)
+		}
+		maxSize = quantity.Value()
+	}
+
+	return &CleanModOptions{
+		CacheDirs: []string{kpmCacheDir, gitCacheDir},
+		MaxSize:   maxSize,
+		MaxAge:    flags.MaxAge,
+		IOStreams: flags.IOStreams,
+	}, nil
+}
+
+// Validate verifies if CleanModOptions is valid and without conflicts.
+func (o *CleanModOptions) Validate() error {
+	if o.MaxSize < 0 || o.MaxAge < 0 {
+		return errors.New("the max size and max age must not be negative")
+	}
+	if o.MaxSize == 0 && o.MaxAge == 0 {
+		return errors.New("at least one of the max size and max age is required")
+	}
+	return nil
+}
+
+// Run executes the `mod clean` command.
+func (o *CleanModOptions) Run() error {
+	total := &wsutil.CleanCacheResult{}
+	for _, cacheDir := range o.CacheDirs {
+		result, err := wsutil.CleanCache(cacheDir, wsutil.CleanCacheOptions{
+			MaxSize: o.MaxSize,
+			MaxAge:  o.MaxAge,
+		})
+		if result != nil {
+			total.Entries += result.Entries
+			total.Bytes += result.Bytes
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	_, err := fmt.Fprintf(o.Out, "Evicted %d cached modules, reclaimed %s\n",
+		total.Entries, resource.NewQuantity(total.Bytes, resource.BinarySI).String())
+	return err
+}
@@ -0,0 +1,62 @@
+package mod
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/cli-runtime/pkg/genericiooptions"
+)
+
+func TestCleanModFlags_ToOptions(t *testing.T) {
+	flags := NewCleanModFlags(genericiooptions.IOStreams{})
+	flags.MaxSize = "1Ki"
+	flags.MaxAge = time.Hour
+
+	o, err := flags.ToOptions()
+	assert.NoError(t, err)
+	assert.Len(t, o.CacheDirs, 2)
+	assert.Equal(t, int64(1024), o.MaxSize)
+	assert.Equal(t, time.Hour, o.MaxAge)
+	assert.NoError(t, o.Validate())
+
+	flags.MaxSize = "invalid"
+	_, err = flags.ToOptions()
+	assert.Error(t, err)
+}
+
+func TestCleanModOptions_Validate(t *testing.T) {
+	assert.Error(t, (&CleanModOptions{}).Validate())
+	assert.Error(t, (&CleanModOptions{MaxAge: -time.Hour}).Validate())
+	assert.NoError(t, (&CleanModOptions{MaxAge: time.Hour}).Validate())
+}
+
+func TestCleanModOptions_Run(t *testing.T) {
+	kpmCacheDir, gitCacheDir := t.TempDir(), t.TempDir()
+	for _, cacheDir := range []string{kpmCacheDir, gitCacheDir} {
+		for name, age := range map[string]time.Duration{"old": 2 * time.Hour, "new": time.Minute} {
+			dir := filepath.Join(cacheDir, name)
+			require.NoError(t, os.MkdirAll(dir, 0o755))
+			require.NoError(t, os.WriteFile(filepath.Join(dir, "main.k"), make([]byte, 1024), 0o644))
+			accessTime := time.Now().Add(-age)
+			require.NoError(t, os.Chtimes(dir, accessTime, accessTime))
+		}
+	}
+
+	out := &bytes.Buffer{}
+	o := &CleanModOptions{
+		CacheDirs: []string{kpmCacheDir, gitCacheDir},
+		MaxAge:    time.Hour,
+		IOStreams: genericiooptions.IOStreams{Out: out},
+	}
+	assert.NoError(t, o.Run())
+	assert.Equal(t, "Evicted 2 cached modules, reclaimed 2Ki\n", out.String())
+	for _, cacheDir := range []string{kpmCacheDir, gitCacheDir} {
+		assert.NoDirExists(t, filepath.Join(cacheDir, "old"))
+		assert.DirExists(t, filepath.Join(cacheDir, "new"))
+	}
+}
@@ -31,6 +31,7 @@ func NewCmdMod(streams genericiooptions.IOStreams) *cobra.Command {
 	cmd.AddCommand(NewCmdAdd(streams))
 	cmd.AddCommand(NewCmdLogin(streams))
 	cmd.AddCommand(NewCmdPull(streams))
+	cmd.AddCommand(NewCmdClean(streams))
 
 	return cmd
 }
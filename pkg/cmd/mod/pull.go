@@ -1,6 +1,7 @@
 package mod
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"os"
@@ -9,14 +10,20 @@ import (
 	"github.com/spf13/cobra"
 	"k8s.io/cli-runtime/pkg/genericiooptions"
 	"k8s.io/kubectl/pkg/util/templates"
+	"kcl-lang.io/kpm/pkg/env"
+	pkg "kcl-lang.io/kpm/pkg/package"
 	"kusionstack.io/kusion-module-framework/pkg/module/registry"
 	cmdutil "kusionstack.io/kusion/pkg/cmd/util"
 	"kusionstack.io/kusion/pkg/util/i18n"
+	wsutil "kusionstack.io/kusion/pkg/workspace"
 )
 
 var (
 	pullLong = i18n.T(`
-	The pull command downloads the kusion modules declared in the kcl.mod file.`)
+	The pull command downloads the kusion modules declared in the kcl.mod file.
+
+	The kusion modules in the Git repos are cloned into the module cache, and the token to access the
+	private repos can be specified by the environment variable KUSION_GIT_MODULE_TOKEN.`)
 
 	pullExample = i18n.T(`
 	# Pull the kusion modules declared in the kcl.mod file under current directory
@@ -28,6 +35,9 @@ var (
 	# Pull the kusion modules with private oci registry
 	kusion mod pull --host ghcr.io/kusion-module-registry --username username --password password
 	
+	# Verify the kusion modules are all in the cache without accessing the network
+	kusion mod pull --offline
+
 	# Or users can also pull the kusion modules in private oci registry with environment variables
 	export KUSION_MODULE_REGISTRY_HOST=ghcr.io/kusion-module-registry
 	export KUSION_MODULE_REGISTRY_USERNAME=username
@@ -43,6 +53,7 @@ type PullModFlags struct {
 	Host     string
 	Username string
 	Password string
+	Offline  bool
 
 	genericiooptions.IOStreams
 }
@@ -54,6 +65,8 @@ type PullModOptions struct {
 	Host     string
 	Username string
 	Password string
+	// Offline verifies the modules are in the cache rather than pulling them from the network.
+	Offline bool
 
 	genericiooptions.IOStreams
 }
@@ -95,6 +108,7 @@ func (flags *PullModFlags) AddFlags(cmd *cobra.Command) {
 	cmd.Flags().StringVar(&flags.Host, "host", "", "The host of kusion module oci registry.")
 	cmd.Flags().StringVar(&flags.Username, "username", "", "The username of kusion module oci registry.")
 	cmd.Flags().StringVar(&flags.Password, "password", "", "The password of kusion module oci registry.")
+	cmd.Flags().BoolVar(&flags.Offline, "offline", false, "Never access the network, and fail if the modules are not in the cache.")
 }
 
 // ToOptions converts from CLI inputs to runtime inputs.
@@ -138,6 +152,7 @@ func (flags *PullModFlags) ToOptions(args []string, ioStreams genericiooptions.I
 		Host:     host,
 		Username: username,
 		Password: password,
+		Offline:  flags.Offline || wsutil.ModuleOfflineFromEnv(),
 	}, nil
 }
 
@@ -160,6 +175,15 @@ func (o *PullModOptions) Run() (err error) {
 		err = os.RemoveAll(path)
 	}(cacheDir)
 
+	// Pull the Kusion Modules from the Git repos in `kcl.mod` file into the module cache.
+	if err = o.pullGitModules(); err != nil {
+		return
+	}
+	if o.Offline {
+		err = o.checkOCIModules()
+		return
+	}
+
 	// Get a client for private Kusion Module Registry.
 	kusionModRegCli, err := registry.NewKusionModuleClientWithCredentials(o.Host, o.Username, o.Password)
 	if err != nil {
@@ -171,3 +195,57 @@ func (o *PullModOptions) Run() (err error) {
 
 	return
 }
+
+// checkOCIModules checks the Kusion Modules from the OCI registries in `kcl.mod` file are all in the kpm
+// package cache, which is used in the offline mode instead of downloading them.
+func (o *PullModOptions) checkOCIModules() error {
+	modFile := &pkg.ModFile{}
+	if err := modFile.LoadModFile(filepath.Join(o.Dir, pkg.MOD_FILE)); err != nil {
+		return fmt.Errorf("load kcl.mod failed: %v", err)
+	}
+
+	absPkgPath, err := env.GetAbsPkgPath()
+	if err != nil {
+		return err
+	}
+	for _, name := range modFile.Deps.Keys() {
+		dep, _ := modFile.Deps.Get(name)
+		if dep.Source.Oci == nil {
+			continue
+		}
+		if _, err = os.Stat(filepath.Join(absPkgPath, dep.FullName)); os.IsNotExist(err) {
+			return fmt.Errorf("%w: module %s is not cached in %s and can't be pulled in offline mode",
+				wsutil.ErrModuleCacheMiss, name, absPkgPath)
+		} else if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// pullGitModules resolves the Kusion Modules from the Git repos in `kcl.mod` file into the module cache,
+// where the token to access the repos is read from the environment variable KUSION_GIT_MODULE_TOKEN.
+func (o *PullModOptions) pullGitModules() error {
+	modFile := &pkg.ModFile{}
+	if err := modFile.LoadModFile(filepath.Join(o.Dir, pkg.MOD_FILE)); err != nil {
+		return fmt.Errorf("load kcl.mod failed: %v", err)
+	}
+
+	resolver, err := wsutil.NewGitModuleResolver("", os.Getenv(wsutil.EnvGitModuleToken), wsutil.WithOffline(o.Offline))
+	if err != nil {
+		return err
+	}
+	defer resolver.Close()
+
+	resolvers := map[wsutil.PathScheme]wsutil.ModuleResolver{wsutil.PathSchemeGit: resolver}
+	for _, name := range modFile.Deps.Keys() {
+		dep, _ := modFile.Deps.Get(name)
+		if dep.Source.Git == nil {
+			continue
+		}
+		if _, err = wsutil.ResolveModule(context.Background(), wsutil.GitModulePath(dep.Source.Git.Url, dep.Source.Git.Tag), resolvers); err != nil {
+			return fmt.Errorf("pull module %s failed: %w", name, err)
+		}
+	}
+	return nil
+}
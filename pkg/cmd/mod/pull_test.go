@@ -0,0 +1,33 @@
+package mod
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"k8s.io/cli-runtime/pkg/genericiooptions"
+
+	"kusionstack.io/kusion/pkg/clipath"
+	wsutil "kusionstack.io/kusion/pkg/workspace"
+)
+
+func TestPullModFlags_ToOptions(t *testing.T) {
+	flags := NewPullModFlags(genericiooptions.IOStreams{})
+	o, err := flags.ToOptions([]string{"testdata/dev"}, flags.IOStreams)
+	assert.NoError(t, err)
+	assert.False(t, o.Offline)
+
+	t.Setenv(wsutil.EnvModuleOffline, "true")
+	o, err = flags.ToOptions([]string{"testdata/dev"}, flags.IOStreams)
+	assert.NoError(t, err)
+	assert.True(t, o.Offline)
+}
+
+func TestPullModOptions_RunOffline(t *testing.T) {
+	t.Setenv(clipath.CacheHomeEnvVar, t.TempDir())
+	t.Setenv("KCL_PKG_PATH", t.TempDir())
+
+	// the modules not in the cache are never fetched from the network in offline mode
+	o := &PullModOptions{Dir: "testdata/dev", Offline: true}
+	err := o.Run()
+	assert.ErrorIs(t, err, wsutil.ErrModuleCacheMiss)
+}
@@ -15,6 +15,7 @@
 package preview
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"os"
@@ -31,10 +32,12 @@ import (
 	"kusionstack.io/kusion/pkg/cmd/generate"
 	"kusionstack.io/kusion/pkg/cmd/meta"
 	cmdutil "kusionstack.io/kusion/pkg/cmd/util"
+	"kusionstack.io/kusion/pkg/engine/mutation"
 	"kusionstack.io/kusion/pkg/engine/operation"
 	"kusionstack.io/kusion/pkg/engine/operation/models"
 	"kusionstack.io/kusion/pkg/engine/release"
 	"kusionstack.io/kusion/pkg/engine/runtime/terraform"
+	"kusionstack.io/kusion/pkg/generators"
 	"kusionstack.io/kusion/pkg/log"
 	"kusionstack.io/kusion/pkg/util/diff"
 	"kusionstack.io/kusion/pkg/util/i18n"
@@ -85,6 +88,7 @@ type PreviewFlags struct {
 	SpecFile     string
 	IgnoreFields []string
 	Values       []string
+	Timings      bool
 
 	UI *terminal.UI
 
@@ -102,6 +106,7 @@ type PreviewOptions struct {
 	SpecFile     string
 	IgnoreFields []string
 	Values       []string
+	Timings      bool
 
 	UI *terminal.UI
 
@@ -153,6 +158,7 @@ func (f *PreviewFlags) AddFlags(cmd *cobra.Command) {
 	cmd.Flags().StringVarP(&f.Output, "output", "o", f.Output, i18n.T("Specify the output format"))
 	cmd.Flags().StringArrayVarP(&f.Values, "argument", "D", []string{}, i18n.T("Specify arguments on the command line"))
 	cmd.Flags().StringVarP(&f.SpecFile, "spec-file", "", "", i18n.T("Specify the spec file path as input, and the spec file must be located in the working directory or its subdirectories"))
+	cmd.Flags().BoolVarP(&f.Timings, "timings", "", false, i18n.T("Print the time spent by each app, module and built-in generator in the generation"))
 }
 
 // ToOptions converts from CLI inputs to runtime inputs.
@@ -174,6 +180,7 @@ func (f *PreviewFlags) ToOptions() (*PreviewOptions, error) {
 		UI:           f.UI,
 		IOStreams:    f.IOStreams,
 		Values:       f.Values,
+		Timings:      f.Timings,
 	}
 
 	return o, nil
@@ -233,6 +240,10 @@ func (o *PreviewOptions) Run() error {
 	var err error
 	if o.SpecFile != "" {
 		spec, err = generate.SpecFromFile(o.SpecFile)
+	} else if o.Timings {
+		timings := generators.NewTimingReport(nil)
+		spec, err = generate.GenerateSpecWithOptions(o.RefProject, o.RefStack, o.RefWorkspace, parameters, o.UI, o.NoStyle, generate.GenerateSpecOptions{Timings: timings})
+		o.printTimings(timings)
 	} else {
 		spec, err = generate.GenerateSpecWithSpinner(o.RefProject, o.RefStack, o.RefWorkspace, parameters, o.UI, o.NoStyle)
 	}
@@ -240,6 +251,11 @@ func (o *PreviewOptions) Run() error {
 		return err
 	}
 
+	// mutate the spec with the mutation webhook if configured
+	if spec, err = mutation.MutateWithEnvWebhook(context.Background(), spec); err != nil {
+		return err
+	}
+
 	// return immediately if no resource found in stack
 	if spec == nil || len(spec.Resources) == 0 {
 		if o.Output != jsonOutput {
@@ -382,3 +398,13 @@ func Preview(
 
 	return models.NewChanges(project, stack, rsp.Order), nil
 }
+
+// printTimings prints the timings of the generation, to the error output if the result is printed in json
+// so that the json result is kept parsable.
+func (o *PreviewOptions) printTimings(timings *generators.TimingReport) {
+	out := o.IOStreams.Out
+	if o.Output == jsonOutput {
+		out = o.IOStreams.ErrOut
+	}
+	generate.PrintTimings(out, timings)
+}
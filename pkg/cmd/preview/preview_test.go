@@ -16,10 +16,12 @@ package preview
 
 import (
 	"context"
+	"errors"
 	"testing"
 
 	"github.com/bytedance/mockey"
 	"github.com/stretchr/testify/assert"
+	"k8s.io/cli-runtime/pkg/genericiooptions"
 
 	apiv1 "kusionstack.io/kusion/pkg/apis/api.kusion.io/v1"
 	v1 "kusionstack.io/kusion/pkg/apis/status/v1"
@@ -33,6 +35,7 @@ import (
 	releasestorages "kusionstack.io/kusion/pkg/engine/release/storages"
 	"kusionstack.io/kusion/pkg/engine/runtime"
 	"kusionstack.io/kusion/pkg/engine/runtime/kubernetes"
+	"kusionstack.io/kusion/pkg/generators"
 	"kusionstack.io/kusion/pkg/util/terminal"
 	workspacestorages "kusionstack.io/kusion/pkg/workspace/storages"
 )
@@ -239,3 +242,42 @@ func TestPreviewOptions_Run(t *testing.T) {
 		})
 	})
 }
+
+func TestPreviewOptions_PrintTimings(t *testing.T) {
+	timings := generators.NewTimingReport(nil)
+	_ = timings.Time("kusionstack/service@v0.1.0", func() error { return nil })
+	_ = timings.Time("kusionstack/mysql@v0.1.0", func() error { return errors.New("failed") })
+
+	testcases := []struct {
+		name     string
+		output   string
+		toErrOut bool
+	}{
+		{
+			name:     "print timings to output",
+			output:   "",
+			toErrOut: false,
+		},
+		{
+			name:     "print timings to error output in json",
+			output:   jsonOutput,
+			toErrOut: true,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			streams, _, out, errOut := genericiooptions.NewTestIOStreams()
+			o := &PreviewOptions{Output: tc.output, IOStreams: streams}
+			o.printTimings(timings)
+
+			printed, other := out, errOut
+			if tc.toErrOut {
+				printed, other = errOut, out
+			}
+			assert.Contains(t, printed.String(), "kusionstack/service@v0.1.0")
+			assert.Contains(t, printed.String(), "Failed")
+			assert.Empty(t, other.String())
+		})
+	}
+}
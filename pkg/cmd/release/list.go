@@ -2,12 +2,15 @@ package rel
 
 import (
 	"fmt"
+	"sort"
+	"strings"
 
 	"github.com/spf13/cobra"
 	"k8s.io/cli-runtime/pkg/genericiooptions"
 	"k8s.io/kubectl/pkg/util/templates"
 	"kusionstack.io/kusion/pkg/cmd/meta"
 	cmdutil "kusionstack.io/kusion/pkg/cmd/util"
+	"kusionstack.io/kusion/pkg/engine/release"
 	"kusionstack.io/kusion/pkg/util/i18n"
 )
 
@@ -110,7 +113,10 @@ func (o *ListOptions) Run() error {
 	}
 
 	// Get all releases.
-	releases := storage.GetRevisions()
+	releases, err := release.ListReleases(storage)
+	if err != nil {
+		return err
+	}
 	if len(releases) == 0 {
 		fmt.Printf("No releases found for project: %s, workspace: %s\n",
 			o.RefProject.Name, o.RefWorkspace.Name)
@@ -119,15 +125,25 @@ func (o *ListOptions) Run() error {
 
 	// Print the releases
 	fmt.Printf("Releases for project: %s, workspace: %s\n\n", o.RefProject.Name, o.RefWorkspace.Name)
-	fmt.Printf("%-10s %-15s %-30s\n", "Revision", "Phase", "Creation Time")
-	fmt.Println("------------------------------------------------------")
-	for _, revision := range releases {
-		r, err := storage.Get(revision)
-		if err != nil {
-			return err
-		}
-		fmt.Printf("%-10d %-15s %-30s\n", r.Revision, string(r.Phase), r.CreateTime.Format("2006-01-02 15:04:05"))
+	fmt.Printf("%-10s %-15s %-30s %-15s %s\n", "Revision", "Phase", "Creation Time", "Operator", "Metadata")
+	fmt.Println("--------------------------------------------------------------------------------------")
+	for _, r := range releases {
+		fmt.Printf("%-10d %-15s %-30s %-15s %s\n", r.Revision, string(r.Phase), r.CreateTime.Format("2006-01-02 15:04:05"), r.Operator, formatMetadata(r.Metadata))
 	}
 
 	return nil
 }
+
+// formatMetadata formats the release metadata as the comma-separated key=value pairs sorted by key.
+func formatMetadata(metadata map[string]string) string {
+	keys := make([]string, 0, len(metadata))
+	for k := range metadata {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	pairs := make([]string, 0, len(keys))
+	for _, k := range keys {
+		pairs = append(pairs, k+"="+metadata[k])
+	}
+	return strings.Join(pairs, ",")
+}
@@ -1,12 +1,12 @@
 package rel
 
 import (
+	"errors"
 	"fmt"
 
 	"github.com/spf13/cobra"
 	"k8s.io/cli-runtime/pkg/genericiooptions"
 	"k8s.io/kubectl/pkg/util/templates"
-	v1 "kusionstack.io/kusion/pkg/apis/api.kusion.io/v1"
 	"kusionstack.io/kusion/pkg/cmd/meta"
 	cmdutil "kusionstack.io/kusion/pkg/cmd/util"
 	"kusionstack.io/kusion/pkg/engine/release"
@@ -22,6 +22,11 @@ var (
 	The phase of the latest release file of the current stack in the current or a specified workspace
 	will be set to 'failed' if it was in the stages of 'generating', 'previewing', 'applying' or 'destroying'. 
 
+	The lock id, which is the revision of the latest release, can be specified to avoid unlocking a release
+	other than the expected one, and it is ignored if the '--force' flag is set. The release is not unlocked
+	if it is held by an operation still running on the current host. The operator who unlocks the release
+	is recorded in the release file.
+
 	Please note that using the 'kusion release unlock' command may cause unexpected concurrent read-write
 	issues with release files, so please use it with caution. 
 	`)
@@ -31,6 +36,12 @@ var (
 
 	# Unlock the latest release file of the current stack in a specified workspace. 
 	kusion release unlock --workspace=dev
+
+	# Unlock the latest release file of the current stack only if its lock id is 3. 
+	kusion release unlock --lock-id=3
+
+	# Unlock the latest release file of the current stack ignoring the lock id. 
+	kusion release unlock --force
 `)
 )
 
@@ -38,11 +49,17 @@ var (
 // which will be converted into UnlockOptions.
 type UnlockFlags struct {
 	MetaFlags *meta.MetaFlags
+
+	LockID string
+	Force  bool
 }
 
 // UnlockOptions defines the configuration parameters for the `kusion release unlock` command.
 type UnlockOptions struct {
 	*meta.MetaOptions
+
+	LockID string
+	Force  bool
 }
 
 // NewUnlockFlags returns a default UnlockFlags.
@@ -80,6 +97,9 @@ func NewCmdUnlock(streams genericiooptions.IOStreams) *cobra.Command {
 // AddFlags registers flags for the CLI.
 func (f *UnlockFlags) AddFlags(cmd *cobra.Command) {
 	f.MetaFlags.AddFlags(cmd)
+
+	cmd.Flags().StringVarP(&f.LockID, "lock-id", "", "", i18n.T("The lock id to unlock, which is the revision of the latest release, optional"))
+	cmd.Flags().BoolVarP(&f.Force, "force", "", false, i18n.T("Unlock the latest release ignoring the lock id"))
 }
 
 // ToOptions converts from CLI inputs to runtime inputs.
@@ -91,6 +111,8 @@ func (f *UnlockFlags) ToOptions() (*UnlockOptions, error) {
 
 	o := &UnlockOptions{
 		MetaOptions: metaOpts,
+		LockID:      f.LockID,
+		Force:       f.Force,
 	}
 
 	return o, nil
@@ -113,31 +135,25 @@ func (o *UnlockOptions) Run() error {
 		return err
 	}
 
-	// Get the latest release.
-	r, err := release.GetLatestRelease(storage)
-	if err != nil {
-		return err
-	}
-	if r == nil {
-		fmt.Printf("No release file found for project: %s, workspace: %s\n",
-			o.RefProject.Name, o.RefWorkspace.Name)
-		return nil
-	}
+	operator := release.CurrentOperator()
 
 	// Update the phase to 'failed', if it was not succeeded or failed.
-	if r.Phase != v1.ReleasePhaseSucceeded && r.Phase != v1.ReleasePhaseFailed {
-		r.Phase = v1.ReleasePhaseFailed
-
-		if err := storage.Update(r); err != nil {
-			return err
-		}
-
-		fmt.Printf("Successfully update release phase to Failed, project: %s, workspace: %s, revision: %d\n",
-			r.Project, r.Workspace, r.Revision)
-
+	r, err := release.ForceUnlock(storage, &release.UnlockOptions{
+		LockID:   o.LockID,
+		Force:    o.Force,
+		Operator: operator,
+		Liveness: release.ProcessLiveness,
+	})
+	if errors.Is(err, release.ErrReleaseNotLocked) {
+		fmt.Printf("No release in progress to unlock for project: %s, workspace: %s\n",
+			o.RefProject.Name, o.RefWorkspace.Name)
 		return nil
 	}
+	if err != nil {
+		return err
+	}
 
-	fmt.Printf("No need to update the release phase, current phase: %s\n", r.Phase)
+	fmt.Printf("Successfully update release phase to Failed, project: %s, workspace: %s, revision: %d, operator: %s\n",
+		r.Project, r.Workspace, r.Revision, operator)
 	return nil
 }
@@ -42,7 +42,7 @@ func TestUnlockFlags_ToOptions(t *testing.T) {
 }
 
 func TestUnlockOptions_Validate(t *testing.T) {
-	opts := &UnlockOptions{}
+	opts := &UnlockOptions{LockID: "1"}
 	streams := genericiooptions.IOStreams{}
 	cmd := NewCmdUnlock(streams)
 
@@ -51,6 +51,16 @@ func TestUnlockOptions_Validate(t *testing.T) {
 		assert.NoError(t, err)
 	})
 
+	t.Run("Force Without Lock ID", func(t *testing.T) {
+		err := (&UnlockOptions{Force: true}).Validate(cmd, []string{})
+		assert.NoError(t, err)
+	})
+
+	t.Run("Without Lock ID", func(t *testing.T) {
+		err := (&UnlockOptions{}).Validate(cmd, []string{})
+		assert.NoError(t, err)
+	})
+
 	t.Run("Invalid Args", func(t *testing.T) {
 		err := opts.Validate(cmd, []string{"invalid-args"})
 		assert.Error(t, err)
@@ -71,6 +81,7 @@ func TestUnlockOptions_Run(t *testing.T) {
 			},
 			Backend: &fakeBackend{},
 		},
+		Force: true,
 	}
 
 	t.Run("Failed to Get Latest Storage Backend", func(t *testing.T) {
@@ -137,6 +148,61 @@ func TestUnlockOptions_Run(t *testing.T) {
 		})
 	})
 
+	t.Run("Mismatched Lock ID", func(t *testing.T) {
+		mockey.PatchConvey("mock release storage and release getter", t, func() {
+			mockey.Mock((*fakeBackend).ReleaseStorage).
+				Return(&fakeStorage{}, nil).Build()
+			mockey.Mock(release.GetLatestRelease).
+				Return(&v1.Release{
+					Revision: 2,
+					Phase:    v1.ReleasePhaseApplying,
+				}, nil).Build()
+
+			mismatchedOpts := *opts
+			mismatchedOpts.Force = false
+			mismatchedOpts.LockID = "1"
+			err := mismatchedOpts.Run()
+			assert.ErrorIs(t, err, release.ErrLockIDMismatch)
+
+			mismatchedOpts.LockID = "2"
+			err = mismatchedOpts.Run()
+			assert.NoError(t, err)
+		})
+	})
+
+	t.Run("Without Lock ID", func(t *testing.T) {
+		mockey.PatchConvey("mock release storage and release getter", t, func() {
+			mockey.Mock((*fakeBackend).ReleaseStorage).
+				Return(&fakeStorage{}, nil).Build()
+			mockey.Mock(release.GetLatestRelease).
+				Return(&v1.Release{
+					Revision: 2,
+					Phase:    v1.ReleasePhaseApplying,
+				}, nil).Build()
+
+			noLockIDOpts := *opts
+			noLockIDOpts.Force = false
+			err := noLockIDOpts.Run()
+			assert.NoError(t, err)
+		})
+	})
+
+	t.Run("Held by Live Operation", func(t *testing.T) {
+		mockey.PatchConvey("mock release storage and release getter", t, func() {
+			mockey.Mock((*fakeBackend).ReleaseStorage).
+				Return(&fakeStorage{}, nil).Build()
+			mockey.Mock(release.GetLatestRelease).
+				Return(&v1.Release{
+					Revision: 2,
+					Phase:    v1.ReleasePhaseApplying,
+					Holder:   release.CurrentHolder(),
+				}, nil).Build()
+
+			err := opts.Run()
+			assert.ErrorIs(t, err, release.ErrLockHeldByLiveOp)
+		})
+	})
+
 	t.Run("No Need to Update Release", func(t *testing.T) {
 		mockey.PatchConvey("mock release storage and release getter", t, func() {
 			mockey.Mock((*fakeBackend).ReleaseStorage).
@@ -2,6 +2,7 @@ package server
 
 import (
 	"kusionstack.io/kusion/pkg/domain/constant"
+	"kusionstack.io/kusion/pkg/secrets/providers/local"
 	"kusionstack.io/kusion/pkg/server"
 	"kusionstack.io/kusion/pkg/server/route"
 )
@@ -50,6 +51,8 @@ func (o *ServerOptions) Run() error {
 	if err != nil {
 		return err
 	}
+	// the secrets of the server must not be read from the files or environment variables of the host
+	local.SetServerMode()
 	if _, err := route.NewCoreRoute(config); err == nil {
 		return nil
 	}
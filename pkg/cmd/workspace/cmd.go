@@ -10,6 +10,7 @@ import (
 	"kusionstack.io/kusion/pkg/cmd/workspace/show"
 	cmdswitch "kusionstack.io/kusion/pkg/cmd/workspace/switch"
 	"kusionstack.io/kusion/pkg/cmd/workspace/update"
+	"kusionstack.io/kusion/pkg/cmd/workspace/validate"
 	"kusionstack.io/kusion/pkg/util/i18n"
 )
 
@@ -39,7 +40,8 @@ func NewCmd() *cobra.Command {
 	listCmd := list.NewCmd()
 	delCmd := del.NewCmd()
 	switchCmd := cmdswitch.NewCmd()
-	cmd.AddCommand(createCmd, updateCmd, showCmd, listCmd, delCmd, switchCmd)
+	validateCmd := validate.NewCmd()
+	cmd.AddCommand(createCmd, updateCmd, showCmd, listCmd, delCmd, switchCmd, validateCmd)
 
 	return cmd
 }
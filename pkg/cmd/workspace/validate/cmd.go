@@ -0,0 +1,41 @@
+package validate
+
+import (
+	"github.com/spf13/cobra"
+	"k8s.io/kubectl/pkg/util/templates"
+
+	"kusionstack.io/kusion/pkg/cmd/util"
+	"kusionstack.io/kusion/pkg/util/i18n"
+)
+
+func NewCmd() *cobra.Command {
+	var (
+		short = i18n.T(`Validate the workspace files in a directory`)
+
+		long = i18n.T(`
+		This command validates each workspace file in a directory and its subdirectories, and reports whether each one passes.
+
+		The files without the .yaml or .yml extension and the hidden files are skipped. The command fails if any workspace file is invalid, which makes it suitable for CI.`)
+
+		example = i18n.T(`
+		# Validate the workspace files in the workspaces directory
+		kusion workspace validate ./workspaces`)
+	)
+
+	o := NewOptions()
+	cmd := &cobra.Command{
+		Use:                   "validate DIR",
+		Short:                 short,
+		Long:                  templates.LongDesc(long),
+		Example:               templates.Examples(example),
+		DisableFlagsInUseLine: true,
+		RunE: func(cmd *cobra.Command, args []string) (err error) {
+			defer util.RecoverErr(&err)
+			util.CheckErr(o.Complete(args))
+			util.CheckErr(o.Run())
+			return
+		},
+	}
+
+	return cmd
+}
@@ -0,0 +1,21 @@
+package validate
+
+import (
+	"testing"
+
+	"github.com/bytedance/mockey"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewCmd(t *testing.T) {
+	t.Run("successfully validate workspaces", func(t *testing.T) {
+		mockey.PatchConvey("mock cmd", t, func() {
+			mockey.Mock((*Options).Run).Return(nil).Build()
+
+			cmd := NewCmd()
+			cmd.SetArgs([]string{"./workspaces"})
+			err := cmd.Execute()
+			assert.Nil(t, err)
+		})
+	})
+}
@@ -0,0 +1,51 @@
+package validate
+
+import (
+	"errors"
+	"fmt"
+
+	"kusionstack.io/kusion/pkg/workspace"
+)
+
+var (
+	ErrNotOneArg         = errors.New("only one arg accepted")
+	ErrInvalidWorkspaces = errors.New("invalid workspace files found")
+)
+
+type Options struct {
+	Dir string
+}
+
+func NewOptions() *Options {
+	return &Options{}
+}
+
+func (o *Options) Complete(args []string) error {
+	if len(args) != 1 {
+		return ErrNotOneArg
+	}
+	o.Dir = args[0]
+	return nil
+}
+
+func (o *Options) Run() error {
+	results, err := workspace.ValidateWorkspaceDir(o.Dir)
+	if err != nil {
+		return err
+	}
+
+	var invalid int
+	for _, result := range results {
+		if result.Valid {
+			fmt.Printf("PASS %s\n", result.File)
+			continue
+		}
+		invalid++
+		fmt.Printf("FAIL %s: %s\n", result.File, result.Message)
+	}
+	if invalid > 0 {
+		return fmt.Errorf("%w: %d of %d", ErrInvalidWorkspaces, invalid, len(results))
+	}
+	fmt.Printf("all %d workspace files are valid\n", len(results))
+	return nil
+}
@@ -0,0 +1,53 @@
+package validate
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOptions_Complete(t *testing.T) {
+	testcases := []struct {
+		name    string
+		args    []string
+		success bool
+	}{
+		{
+			name:    "valid args",
+			args:    []string{"./workspaces"},
+			success: true,
+		},
+		{
+			name:    "no args",
+			args:    nil,
+			success: false,
+		},
+		{
+			name:    "too many args",
+			args:    []string{"./dev", "./prod"},
+			success: false,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			opts := NewOptions()
+			err := opts.Complete(tc.args)
+			assert.Equal(t, tc.success, err == nil)
+		})
+	}
+}
+
+func TestOptions_Run(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "dev.yaml"), []byte("context:\n  region: us-east-1\n"), 0o600))
+
+	opts := &Options{Dir: dir}
+	assert.NoError(t, opts.Run())
+
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "prod.yaml"), []byte("modules:\n  mysql: [\n"), 0o600))
+	assert.ErrorIs(t, opts.Run(), ErrInvalidWorkspaces)
+}
@@ -16,6 +16,13 @@ const (
 	backendGenericOssBucket   = backendConfigItems + "." + v1.BackendGenericOssBucket
 	backendGenericOssPrefix   = backendConfigItems + "." + v1.BackendGenericOssPrefix
 	backendS3Region           = backendConfigItems + "." + v1.BackendS3Region
+	backendEncryption         = backendConfigItems + "." + v1.BackendEncryption
+	backendEncryptionKeyID    = backendConfigItems + "." + v1.BackendEncryptionKeyID
+	backendEncryptionProvider = backendConfigItems + "." + v1.BackendEncryptionProvider
+	backendEncryptionServer   = backendConfigItems + "." + v1.BackendEncryptionServer
+	backendEncryptionMount    = backendConfigItems + "." + v1.BackendEncryptionMount
+	backendEncryptionRegion   = backendConfigItems + "." + v1.BackendEncryptionRegion
+	backendEncryptionProfile  = backendConfigItems + "." + v1.BackendEncryptionProfile
 )
 
 func newRegisteredItems() map[string]*itemInfo {
@@ -31,6 +38,13 @@ func newRegisteredItems() map[string]*itemInfo {
 		backendGenericOssBucket:   {"", validateSetGenericOssBackendItem, nil},
 		backendGenericOssPrefix:   {"", validateSetGenericOssBackendItem, nil},
 		backendS3Region:           {"", validateSetS3BackendItem, nil},
+		backendEncryption:         {"", validateSetBackendEncryption, nil},
+		backendEncryptionKeyID:    {"", validateSetBackendEncryptionItem, nil},
+		backendEncryptionProvider: {"", validateSetBackendEncryptionItem, nil},
+		backendEncryptionServer:   {"", validateSetBackendEncryptionItem, nil},
+		backendEncryptionMount:    {"", validateSetBackendEncryptionItem, nil},
+		backendEncryptionRegion:   {"", validateSetBackendEncryptionItem, nil},
+		backendEncryptionProfile:  {"", validateSetBackendEncryptionItem, nil},
 	}
 }
 
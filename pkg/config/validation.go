@@ -15,14 +15,15 @@ type (
 )
 
 var (
-	ErrNotExistCurrentBackend     = errors.New("cannot assign current to not exist backend")
-	ErrUnsetDefaultCurrentBackend = errors.New("cannot unset default current backend")
-	ErrInUseCurrentBackend        = errors.New("unset in-use current backend")
-	ErrUnsupportedBackendType     = errors.New("unsupported backend type")
-	ErrNonEmptyBackendConfigItems = errors.New("non-empty backend config items")
-	ErrEmptyBackendType           = errors.New("empty backend type")
-	ErrConflictBackendType        = errors.New("conflict backend type")
-	ErrInvalidBackNameDefault     = errors.New("backend name should not be default")
+	ErrNotExistCurrentBackend       = errors.New("cannot assign current to not exist backend")
+	ErrUnsetDefaultCurrentBackend   = errors.New("cannot unset default current backend")
+	ErrInUseCurrentBackend          = errors.New("unset in-use current backend")
+	ErrUnsupportedBackendType       = errors.New("unsupported backend type")
+	ErrNonEmptyBackendConfigItems   = errors.New("non-empty backend config items")
+	ErrEmptyBackendType             = errors.New("empty backend type")
+	ErrConflictBackendType          = errors.New("conflict backend type")
+	ErrInvalidBackNameDefault       = errors.New("backend name should not be default")
+	ErrUnsupportedBackendEncryption = errors.New("unsupported backend encryption")
 )
 
 // validateSetCurrentBackend is used to check that setting the current backend is valid or not.
@@ -147,6 +148,23 @@ func validateSetS3BackendItem(config *v1.Config, key string, _ any) error {
 	return checkBackendTypeForBackendItem(config, key, v1.BackendTypeS3)
 }
 
+// validateSetBackendEncryption is used to check that setting the encryption of backend is valid or not.
+func validateSetBackendEncryption(config *v1.Config, key string, val any) error {
+	if err := validateSetBackendEncryptionItem(config, key, val); err != nil {
+		return err
+	}
+	return checkBackendEncryption(val)
+}
+
+// validateSetBackendEncryptionItem is used to check that setting the encryption config item of backend is
+// valid or not, which is supported by the backends of all types.
+func validateSetBackendEncryptionItem(config *v1.Config, key string, _ any) error {
+	if err := checkNotDefaultBackendName(parseBackendName(key)); err != nil {
+		return err
+	}
+	return checkBackendTypeForBackendItem(config, key, v1.BackendTypeLocal, v1.BackendTypeOss, v1.BackendTypeS3)
+}
+
 // checkBackendConfig is used to check that setting the backend config is valid or not, which is called
 // validateSetBackendConfig and validateSetBackendConfigItems.
 func checkBackendConfig(config *v1.BackendConfig) error {
@@ -154,6 +172,12 @@ func checkBackendConfig(config *v1.BackendConfig) error {
 		return err
 	}
 
+	if mode, ok := config.Configs[v1.BackendEncryption]; ok {
+		if err := checkBackendEncryption(mode); err != nil {
+			return err
+		}
+	}
+
 	switch config.Type {
 	case v1.BackendTypeOss:
 		ossBackend := config.ToOssBackend()
@@ -210,10 +234,25 @@ func checkBasalBackendConfig(config *v1.BackendConfig) error {
 	return nil
 }
 
+// backendEncryptionItems are the config items of the encryption, which are supported by the backends of
+// all types.
+var backendEncryptionItems = map[string]checkTypeFunc{
+	v1.BackendEncryption:         checkString,
+	v1.BackendEncryptionKeyID:    checkString,
+	v1.BackendEncryptionProvider: checkString,
+	v1.BackendEncryptionServer:   checkString,
+	v1.BackendEncryptionMount:    checkString,
+	v1.BackendEncryptionRegion:   checkString,
+	v1.BackendEncryptionProfile:  checkString,
+}
+
 // checkBasalBackendConfigItems is used to check type of the backend config and whether it's the supported item.
 func checkBasalBackendConfigItems(backend *v1.BackendConfig, items map[string]checkTypeFunc) error {
 	for configItem, configValue := range backend.Configs {
 		checkType, ok := items[configItem]
+		if !ok {
+			checkType, ok = backendEncryptionItems[configItem]
+		}
 		if !ok {
 			return fmt.Errorf("do not support %s for backend with type %s", configItem, backend.Type)
 		}
@@ -245,6 +284,15 @@ func checkBackendTypeForBackendItem(config *v1.Config, key string, backendTypes
 	return nil
 }
 
+// checkBackendEncryption checks the encryption mode of the backend is supported.
+func checkBackendEncryption(val any) error {
+	mode, _ := val.(string)
+	if mode != v1.BackendEncryptionRelease && mode != v1.BackendEncryptionSensitive {
+		return fmt.Errorf("%w %v, only %s and %s are supported", ErrUnsupportedBackendEncryption, val, v1.BackendEncryptionRelease, v1.BackendEncryptionSensitive)
+	}
+	return nil
+}
+
 // checkNotDefaultBackendName returns error if the backend name is default.
 func checkNotDefaultBackendName(name string) error {
 	if name == v1.DefaultBackendName {
@@ -337,6 +337,37 @@ func TestValidateSetBackendConfigItems(t *testing.T) {
 				v1.BackendGenericOssBucket: "kusion",
 			},
 		},
+		{
+			name:    "valid backend config items with encryption",
+			success: true,
+			config: &v1.Config{
+				Backends: &v1.BackendConfigs{
+					Backends: map[string]*v1.BackendConfig{
+						"dev": {Type: v1.BackendTypeLocal},
+					},
+				},
+			},
+			key: "backends.dev.configs",
+			val: map[string]any{
+				v1.BackendEncryption:      v1.BackendEncryptionRelease,
+				v1.BackendEncryptionKeyID: "key-1",
+			},
+		},
+		{
+			name:    "invalid backend config items unsupported encryption",
+			success: false,
+			config: &v1.Config{
+				Backends: &v1.BackendConfigs{
+					Backends: map[string]*v1.BackendConfig{
+						"dev": {Type: v1.BackendTypeLocal},
+					},
+				},
+			},
+			key: "backends.dev.configs",
+			val: map[string]any{
+				v1.BackendEncryption: "all",
+			},
+		},
 	}
 
 	for _, tc := range testcases {
@@ -119,29 +119,22 @@ func Apply(
 		logutil.LogToAll(sysLogger, runLogger, "Info", "Watch started ...")
 	}
 
+	// In the dry-run mode, the whole apply process runs against the recording runtimes, which perform
+	// nothing in the actual infra.
 	var upRel *apiv1.Release
-	if o.DryRun {
-		for _, r := range rel.Spec.Resources {
-			ac.MsgCh <- models.Message{
-				ResourceID: r.ResourceKey(),
-				OpResult:   models.Success,
-				OpErr:      nil,
-			}
-		}
-		close(ac.MsgCh)
-	} else {
-		// parse cluster in arguments
-		rsp, st := ac.Apply(&operation.ApplyRequest{
-			Request: models.Request{
-				Project: changes.Project(),
-				Stack:   changes.Stack(),
-			},
-			Release: rel,
-			Graph:   gph,
-		})
-		if v1.IsErr(st) {
-			return nil, fmt.Errorf("apply failed, status:\n%v", st)
-		}
+	rsp, st := ac.Apply(&operation.ApplyRequest{
+		Request: models.Request{
+			Project: changes.Project(),
+			Stack:   changes.Stack(),
+		},
+		Release: rel,
+		Graph:   gph,
+		DryRun:  o.DryRun,
+	})
+	if v1.IsErr(st) {
+		return nil, fmt.Errorf("apply failed, status:\n%v", st)
+	}
+	if !o.DryRun {
 		upRel = rsp.Release
 	}
 
@@ -218,7 +211,7 @@ func Watch(
 				if !releaseCreated {
 					return
 				}
-				release.UpdateReleasePhase(rel, apiv1.ReleasePhaseFailed, relLock)
+				release.UpdateReleaseFailed(rel, (*err).Error(), relLock)
 				_ = release.UpdateApplyRelease(releaseStorage, rel, dryRun, relLock)
 			}
 			watchErrCh <- *err
@@ -335,7 +328,7 @@ func ProcessApplyDetails(
 			if !releaseCreated {
 				return
 			}
-			release.UpdateReleasePhase(rel, apiv1.ReleasePhaseFailed, relLock)
+			release.UpdateReleaseFailed(rel, (*err).Error(), relLock)
 			*err = errors.Join([]error{*err, release.UpdateApplyRelease(releaseStorage, rel, dryRun, relLock)}...)
 		}
 	}()
@@ -434,7 +427,7 @@ func watchK8sResources(
 			if !releaseCreated {
 				return
 			}
-			release.UpdateReleasePhase(rel, apiv1.ReleasePhaseFailed, relLock)
+			release.UpdateReleaseFailed(rel, err.Error(), relLock)
 			_ = release.UpdateApplyRelease(releaseStorage, rel, dryRun, relLock)
 		}
 	}()
@@ -545,7 +538,7 @@ func watchTFResources(
 			if !releaseCreated {
 				return
 			}
-			release.UpdateReleasePhase(rel, apiv1.ReleasePhaseFailed, relLock)
+			release.UpdateReleaseFailed(rel, err.Error(), relLock)
 			_ = release.UpdateApplyRelease(releaseStorage, rel, dryRun, relLock)
 		}
 	}()
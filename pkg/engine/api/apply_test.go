@@ -29,6 +29,7 @@ import (
 	"kusionstack.io/kusion/pkg/engine/operation"
 	"kusionstack.io/kusion/pkg/engine/operation/models"
 	releasestorages "kusionstack.io/kusion/pkg/engine/release/storages"
+	resourcegraph "kusionstack.io/kusion/pkg/engine/resource/graph"
 )
 
 func mockApplyRelease(resources apiv1.Resources) *apiv1.Release {
@@ -60,9 +61,11 @@ func TestApply(t *testing.T) {
 			},
 		}
 		changes := models.NewChanges(proj, stack, order)
+		gph := &apiv1.Graph{Project: rel.Project, Workspace: rel.Workspace}
+		resourcegraph.GenerateGraph(rel.Spec.Resources, gph)
 		o := &APIOptions{}
 		o.DryRun = true
-		_, err := Apply(context.TODO(), o, &releasestorages.LocalStorage{}, rel, &apiv1.Graph{}, changes, os.Stdout)
+		_, err := Apply(context.TODO(), o, &releasestorages.LocalStorage{}, rel, gph, changes, os.Stdout)
 		assert.Nil(t, err)
 	})
 	mockey.PatchConvey("apply success", t, func() {
@@ -22,11 +22,21 @@ import (
 	v1 "kusionstack.io/kusion/pkg/apis/api.kusion.io/v1"
 	"kusionstack.io/kusion/pkg/generators"
 	"kusionstack.io/kusion/pkg/generators/appconfiguration"
+	"kusionstack.io/kusion/pkg/workspace"
 )
 
 type AppsConfigBuilder struct {
 	Apps      map[string]v1.AppConfiguration
 	Workspace *v1.Workspace
+	// NamePolicy constructs the names of the built-in generated resources, defaults to the name policy
+	// configured in the workspace context, or generators.DefaultNamePolicy if neither is set.
+	NamePolicy generators.NamePolicy
+	// Timings records the time spent by each app, and by the modules and built-in generators of the apps,
+	// no timing is recorded if not set.
+	Timings *generators.TimingReport
+	// ModuleOutputs reuses the outputs of the modules with unchanged inputs for the incremental generation,
+	// all the modules are called if not set.
+	ModuleOutputs *generators.ModuleOutputCache
 }
 
 func (acg *AppsConfigBuilder) Build(kclPackage *api.KclPackage, project *v1.Project, stack *v1.Stack) (*v1.Spec, error) {
@@ -34,13 +44,24 @@ func (acg *AppsConfigBuilder) Build(kclPackage *api.KclPackage, project *v1.Proj
 		Resources: []v1.Resource{},
 	}
 
+	namePolicy, err := acg.namePolicy()
+	if err != nil {
+		return nil, err
+	}
+
+	// the module configs are resolved once for all the apps of the project in this build
+	moduleConfigs := workspace.NewModuleConfigCache(workspace.DefaultModuleConfigCacheSize)
 	var gfs []generators.NewSpecGeneratorFunc
-	err := generators.ForeachOrdered(acg.Apps, func(appName string, app v1.AppConfiguration) error {
+	err = generators.ForeachOrdered(acg.Apps, func(appName string, app v1.AppConfiguration) error {
 		if kclPackage == nil {
 			return fmt.Errorf("kcl package is nil when generating app configuration for %s", appName)
 		}
 		dependencies := kclPackage.GetDependenciesInModFile()
-		gfs = append(gfs, appconfiguration.NewAppConfigurationGeneratorFunc(project, stack, appName, &app, acg.Workspace, dependencies))
+		gf := appconfiguration.NewAppConfigurationGeneratorFunc(project, stack, appName, &app, acg.Workspace, dependencies)
+		gf = generators.WithNamePolicy(namePolicy, gf)
+		gf = generators.WithModuleOutputCache(acg.ModuleOutputs, gf)
+		gf = appconfiguration.WithModuleConfigCache(moduleConfigs, gf)
+		gfs = append(gfs, generators.Timed(acg.Timings, appName, gf))
 		return nil
 	})
 	if err != nil {
@@ -49,6 +70,29 @@ func (acg *AppsConfigBuilder) Build(kclPackage *api.KclPackage, project *v1.Proj
 	if err = generators.CallGenerators(i, gfs...); err != nil {
 		return nil, err
 	}
+	// Validate the wiring between Services and workloads once all the apps are generated, for a Service
+	// may select the pods of another app.
+	if err = generators.ValidateServiceSelectors(i.Resources); err != nil {
+		return nil, err
+	}
+	generators.NormalizeSpec(i)
+	if acg.Workspace != nil {
+		if err = workspace.CheckQuotas(acg.Workspace.Quotas, i); err != nil {
+			return nil, err
+		}
+	}
 
 	return i, nil
 }
+
+// namePolicy returns the NamePolicy of the builder, or the one configured in the workspace context.
+func (acg *AppsConfigBuilder) namePolicy() (generators.NamePolicy, error) {
+	if acg.NamePolicy != nil || acg.Workspace == nil {
+		return acg.NamePolicy, nil
+	}
+	config, err := workspace.GetNamePolicyConfig(acg.Workspace.Context)
+	if err != nil || config == nil {
+		return nil, err
+	}
+	return generators.NewTemplateNamePolicy(config.Template)
+}
@@ -52,6 +52,67 @@ func TestBuild(t *testing.T) {
 	assert.NotNil(t, intent)
 }
 
+func TestBuild_ValidateServiceSelectors(t *testing.T) {
+	p, s := buildMockProjectAndStack()
+	appName, app := buildMockApp()
+	acg := &AppsConfigBuilder{
+		Apps: map[string]v1.AppConfiguration{
+			appName: *app,
+		},
+		Workspace: buildMockWorkspace(),
+	}
+	cwd, _ := os.Getwd()
+	kclPkg, err := api.GetKclPackage(filepath.Join(cwd, "testdata"))
+	assert.NoError(t, err)
+
+	service := v1.Resource{
+		ID:   "v1:Service:default:web",
+		Type: v1.Kubernetes,
+		Attributes: map[string]interface{}{
+			"apiVersion": "v1",
+			"kind":       "Service",
+			"metadata":   map[string]interface{}{"name": "web", "namespace": "default"},
+			"spec":       map[string]interface{}{"selector": map[string]interface{}{"app": "web"}},
+		},
+	}
+	// the pod selected by the service is generated by another app after the service
+	pod := v1.Resource{
+		ID:   "v1:Pod:default:web",
+		Type: v1.Kubernetes,
+		Attributes: map[string]interface{}{
+			"apiVersion": "v1",
+			"kind":       "Pod",
+			"metadata": map[string]interface{}{
+				"name":      "web",
+				"namespace": "default",
+				"labels":    map[string]interface{}{"app": "web"},
+			},
+		},
+	}
+
+	t.Run("service selecting the pod of another app", func(t *testing.T) {
+		callMock := mockey.Mock(generators.CallGenerators).To(func(i *v1.Spec, _ ...generators.NewSpecGeneratorFunc) error {
+			i.Resources = append(i.Resources, service, pod)
+			return nil
+		}).Build()
+		defer callMock.UnPatch()
+
+		_, err = acg.Build(kclPkg, p, s)
+		assert.NoError(t, err)
+	})
+
+	t.Run("service selecting no pod", func(t *testing.T) {
+		callMock := mockey.Mock(generators.CallGenerators).To(func(i *v1.Spec, _ ...generators.NewSpecGeneratorFunc) error {
+			i.Resources = append(i.Resources, service)
+			return nil
+		}).Build()
+		defer callMock.UnPatch()
+
+		_, err = acg.Build(kclPkg, p, s)
+		assert.ErrorIs(t, err, generators.ErrServiceSelectorMismatch)
+	})
+}
+
 func buildMockApp() (string, *v1.AppConfiguration) {
 	return "app1", &v1.AppConfiguration{
 		Workload: map[string]interface{}{
@@ -18,6 +18,7 @@ import (
 	// "kusionstack.io/kusion/pkg/engine/api/builders/kcl"
 
 	"kusionstack.io/kusion/pkg/util/pretty"
+	wsutil "kusionstack.io/kusion/pkg/workspace"
 )
 
 const JSONOutput = "json"
@@ -62,6 +63,11 @@ const JSONOutput = "json"
 
 // GenerateSpecWithSpinner calls generator to generate versioned Spec. Add a method wrapper for testing purposes.
 func GenerateSpecWithSpinner(project *v1.Project, stack *v1.Stack, workspace *v1.Workspace, noStyle bool) (*v1.Spec, error) {
+	// The workspace name is not serialized, reject the workspace whose name is not set when loaded
+	if workspace != nil && workspace.GetName() == "" {
+		return nil, wsutil.ErrEmptyWorkspaceName
+	}
+
 	// Construct generator instance
 	defaultGenerator := &generator.DefaultGenerator{
 		Project:   project,
@@ -15,10 +15,13 @@
 package generator
 
 import (
+	"context"
 	"fmt"
 	"os"
 	"path/filepath"
 	"runtime"
+	"strings"
+	"time"
 
 	"gopkg.in/yaml.v2"
 	utilerrors "k8s.io/apimachinery/pkg/util/errors"
@@ -29,8 +32,10 @@ import (
 	v1 "kusionstack.io/kusion/pkg/apis/api.kusion.io/v1"
 	"kusionstack.io/kusion/pkg/engine/api/builders"
 	"kusionstack.io/kusion/pkg/engine/api/generate/run"
+	"kusionstack.io/kusion/pkg/generators"
 	"kusionstack.io/kusion/pkg/util/io"
 	"kusionstack.io/kusion/pkg/util/kfile"
+	"kusionstack.io/kusion/pkg/workspace"
 )
 
 // Generator is an interface for things that can generate versioned Spec from
@@ -46,10 +51,25 @@ type DefaultGenerator struct {
 	Stack     *v1.Stack
 	Workspace *v1.Workspace
 	Runner    run.CodeRunner
+	// Timings is the report of the time spent by each app, module and built-in generator, which is
+	// filled after Generate if set.
+	Timings *generators.TimingReport
+	// ModuleOutputs is the cache of the module outputs for the incremental generation, which records the
+	// module outputs of this generation after Generate if set.
+	ModuleOutputs *generators.ModuleOutputCache
 }
 
 // Generate versioned Spec with target code runner.
 func (g *DefaultGenerator) Generate(workDir string, params map[string]string) (*v1.Spec, error) {
+	// Mark the dependent modules in the kpm package cache in use, so that they are not evicted
+	// by `kusion mod clean` during the generation
+	inUse := &workspace.ModuleCacheLocks{}
+	defer inUse.Close()
+	offline := workspace.ModuleOfflineFromEnv()
+	if err := AcquireDependentModules(workDir, inUse, offline); err != nil {
+		return nil, err
+	}
+
 	// Call code runner to generate raw data
 	if params == nil {
 		params = make(map[string]string, 1)
@@ -60,7 +80,16 @@ func (g *DefaultGenerator) Generate(workDir string, params map[string]string) (*
 	}
 
 	// Copy dependent modules before call builder
-	err = CopyDependentModules(workDir)
+	gitResolver, err := workspace.NewGitModuleResolver("", os.Getenv(workspace.EnvGitModuleToken), workspace.WithOffline(offline))
+	if err != nil {
+		return nil, err
+	}
+	defer gitResolver.Close()
+	var modules v1.ModuleConfigs
+	if g.Workspace != nil {
+		modules = g.Workspace.Modules
+	}
+	err = CopyDependentModules(workDir, modules, gitResolver)
 	if err != nil {
 		return nil, err
 	}
@@ -79,14 +108,53 @@ func (g *DefaultGenerator) Generate(workDir string, params map[string]string) (*
 	}
 
 	builder := &builders.AppsConfigBuilder{
-		Workspace: g.Workspace,
-		Apps:      apps,
+		Workspace:     g.Workspace,
+		Apps:          apps,
+		Timings:       g.Timings,
+		ModuleOutputs: g.ModuleOutputs,
 	}
 	return builder.Build(kclPkg, g.Project, g.Stack)
 }
 
-// CopyDependentModules copies dependent Kusion modules' generators to destination.
-func CopyDependentModules(workDir string) error {
+// AcquireDependentModules marks the dependent Kusion modules in the kpm package cache in use until the
+// locks are closed, and refreshes their access time for the LRU eviction of the cache. In the offline
+// mode, the modules not in the cache fail with workspace.ErrModuleCacheMiss rather than being fetched.
+func AcquireDependentModules(workDir string, inUse *workspace.ModuleCacheLocks, offline bool) error {
+	modFile := &pkg.ModFile{}
+	err := modFile.LoadModFile(filepath.Join(workDir, pkg.MOD_FILE))
+	if err != nil {
+		return fmt.Errorf("load kcl.mod failed: %v", err)
+	}
+
+	absPkgPath, err := env.GetAbsPkgPath()
+	if err != nil {
+		return err
+	}
+	now := time.Now()
+	for _, name := range modFile.Deps.Keys() {
+		dep, _ := modFile.Deps.Get(name)
+		if dep.Source.Oci == nil {
+			continue
+		}
+		pkgDir := filepath.Join(absPkgPath, dep.FullName)
+		if _, err = os.Stat(pkgDir); offline && os.IsNotExist(err) {
+			return fmt.Errorf("%w: module %s is not cached in %s and can't be pulled in offline mode",
+				workspace.ErrModuleCacheMiss, name, absPkgPath)
+		}
+		if err = inUse.Acquire(pkgDir); err != nil {
+			return err
+		}
+		if err = os.Chtimes(pkgDir, now, now); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+	}
+	return nil
+}
+
+// CopyDependentModules copies dependent Kusion modules' generators to destination. The dependencies from
+// the Git repos are Kusion modules only if declared in the modules of the workspace, e.g. not the KCL
+// packages such as kam, which are resolved by the gitResolver.
+func CopyDependentModules(workDir string, modules v1.ModuleConfigs, gitResolver workspace.ModuleResolver) error {
 	modFile := &pkg.ModFile{}
 	err := modFile.LoadModFile(filepath.Join(workDir, pkg.MOD_FILE))
 	if err != nil {
@@ -117,6 +185,8 @@ func CopyDependentModules(workDir string) error {
 				err = os.Chmod(dest, 0o755)
 			}
 			allErrs = append(allErrs, err)
+		} else if dep.Source.Git != nil && modules[name] != nil {
+			allErrs = append(allErrs, copyGitModule(dep, kusionHomePath, gitResolver))
 		}
 	}
 
@@ -126,3 +196,32 @@ func CopyDependentModules(workDir string) error {
 
 	return nil
 }
+
+// copyGitModule resolves the module from the Git repo, and copies its generator to the $KUSION_HOME
+// modules directory of the module key, e.g. "kusionstack/mysql@v0.1.0" for the repo
+// "https://github.com/kusionstack/mysql.git" at tag "v0.1.0".
+func copyGitModule(dep pkg.Dependency, kusionHomePath string, gitResolver workspace.ModuleResolver) error {
+	info := dep.Source.Git
+	dir, err := workspace.ResolveModule(context.Background(), workspace.GitModulePath(info.Url, info.Tag),
+		map[workspace.PathScheme]workspace.ModuleResolver{workspace.PathSchemeGit: gitResolver})
+	if err != nil {
+		return err
+	}
+
+	splits := strings.Split(strings.TrimSuffix(info.Url, ".git"), "/")
+	if len(splits) < 2 {
+		return fmt.Errorf("invalid git url %s of module %s", info.Url, dep.Name)
+	}
+	repo := splits[len(splits)-2] + "/" + splits[len(splits)-1]
+	source := filepath.Join(dir, "kusion-module-"+dep.FullName)
+	moduleDir := filepath.Join(kusionHomePath, "modules", repo, info.Tag, runtime.GOOS, runtime.GOARCH)
+	dest := filepath.Join(moduleDir, fmt.Sprintf("kusion-module-%s", dep.FullName))
+	if runtime.GOOS == "windows" {
+		source = fmt.Sprintf("%s.exe", source)
+		dest = fmt.Sprintf("%s.exe", dest)
+	}
+	if err = io.CopyFile(source, dest); err != nil {
+		return err
+	}
+	return os.Chmod(dest, 0o755)
+}
@@ -0,0 +1,227 @@
+// Package lint checks the Spec for the common misconfigurations before apply. The findings are
+// warnings for the users, which never block the apply.
+package lint
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/google/go-containerregistry/pkg/name"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	v1 "kusionstack.io/kusion/pkg/apis/api.kusion.io/v1"
+)
+
+// Severity is the severity of the LintFinding.
+type Severity string
+
+const (
+	SeverityWarning Severity = "Warning"
+	SeverityInfo    Severity = "Info"
+)
+
+// Category is the category of the misconfiguration found by the rule.
+type Category string
+
+const (
+	CategoryReliability     Category = "Reliability"
+	CategoryResources       Category = "Resources"
+	CategoryReproducibility Category = "Reproducibility"
+)
+
+// The names of the built-in rules.
+const (
+	RuleMissingResourceLimits = "missing-resource-limits"
+	RuleLatestImageTag        = "latest-image-tag"
+	RuleMissingProbes         = "missing-probes"
+)
+
+// LintFinding is a misconfiguration of the resource found by the rule.
+type LintFinding struct {
+	Rule       string
+	Category   Category
+	Severity   Severity
+	ResourceID string
+	Message    string
+}
+
+func (f LintFinding) String() string {
+	return fmt.Sprintf("[%s] %s: %s (%s)", f.Severity, f.ResourceID, f.Message, f.Rule)
+}
+
+// Rule checks the resource, and returns the messages of the misconfigurations found.
+type Rule struct {
+	Name     string
+	Category Category
+	Severity Severity
+	Check    func(resource *v1.Resource) []string
+}
+
+// Rules are the built-in rules.
+var Rules = []Rule{
+	{
+		Name:     RuleMissingResourceLimits,
+		Category: CategoryResources,
+		Severity: SeverityWarning,
+		Check:    checkResourceLimits,
+	},
+	{
+		Name:     RuleLatestImageTag,
+		Category: CategoryReproducibility,
+		Severity: SeverityWarning,
+		Check:    checkImageTag,
+	},
+	{
+		Name:     RuleMissingProbes,
+		Category: CategoryReliability,
+		Severity: SeverityWarning,
+		Check:    checkProbes,
+	},
+}
+
+type options struct {
+	disabled map[string]bool
+}
+
+// Option customizes the rules run by Lint.
+type Option func(*options)
+
+// WithDisabledRules silences the rules of the given names.
+func WithDisabledRules(names ...string) Option {
+	return func(o *options) {
+		for _, n := range names {
+			o.disabled[n] = true
+		}
+	}
+}
+
+// Lint runs the enabled built-in rules against the resources of the Spec, and returns the findings
+// ordered by the resources and rules.
+func Lint(spec *v1.Spec, opts ...Option) []LintFinding {
+	if spec == nil {
+		return nil
+	}
+	o := &options{disabled: map[string]bool{}}
+	for _, opt := range opts {
+		opt(o)
+	}
+
+	var findings []LintFinding
+	for i := range spec.Resources {
+		resource := &spec.Resources[i]
+		for _, rule := range Rules {
+			if o.disabled[rule.Name] {
+				continue
+			}
+			for _, msg := range rule.Check(resource) {
+				findings = append(findings, LintFinding{
+					Rule:       rule.Name,
+					Category:   rule.Category,
+					Severity:   rule.Severity,
+					ResourceID: resource.ResourceKey(),
+					Message:    msg,
+				})
+			}
+		}
+	}
+	return findings
+}
+
+// podSpecPaths are the paths of the pod specs of the Kubernetes workloads by kind.
+var podSpecPaths = map[string][]string{
+	"Pod":         {"spec"},
+	"Deployment":  {"spec", "template", "spec"},
+	"StatefulSet": {"spec", "template", "spec"},
+	"DaemonSet":   {"spec", "template", "spec"},
+	"ReplicaSet":  {"spec", "template", "spec"},
+	"Job":         {"spec", "template", "spec"},
+	"CronJob":     {"spec", "jobTemplate", "spec", "template", "spec"},
+}
+
+// containers returns the containers of the Kubernetes workload, along with the init containers if
+// includeInit is true.
+func containers(resource *v1.Resource, includeInit bool) []map[string]interface{} {
+	if resource.Type != v1.Kubernetes {
+		return nil
+	}
+	path, ok := podSpecPaths[kind(resource)]
+	if !ok {
+		return nil
+	}
+
+	fields := []string{"containers"}
+	if includeInit {
+		fields = append([]string{"initContainers"}, fields...)
+	}
+	var result []map[string]interface{}
+	for _, field := range fields {
+		value, found, err := unstructured.NestedFieldNoCopy(resource.Attributes, append(path, field)...)
+		if err != nil || !found {
+			continue
+		}
+		list, ok := value.([]interface{})
+		if !ok {
+			continue
+		}
+		for _, c := range list {
+			if container, ok := c.(map[string]interface{}); ok {
+				result = append(result, container)
+			}
+		}
+	}
+	return result
+}
+
+func kind(resource *v1.Resource) string {
+	k, _ := resource.Attributes["kind"].(string)
+	return k
+}
+
+func checkResourceLimits(resource *v1.Resource) []string {
+	var msgs []string
+	for _, c := range containers(resource, true) {
+		limits, _, _ := unstructured.NestedFieldNoCopy(c, "resources", "limits")
+		if m, ok := limits.(map[string]interface{}); ok && len(m) > 0 {
+			continue
+		}
+		msgs = append(msgs, fmt.Sprintf("container %v has no resource limits", c["name"]))
+	}
+	return msgs
+}
+
+func checkImageTag(resource *v1.Resource) []string {
+	var msgs []string
+	for _, c := range containers(resource, true) {
+		image, ok := c["image"].(string)
+		if !ok || image == "" {
+			continue
+		}
+		ref, err := name.ParseReference(image)
+		if err != nil {
+			continue
+		}
+		if tag, ok := ref.(name.Tag); ok && tag.TagStr() == name.DefaultTag {
+			msgs = append(msgs, fmt.Sprintf("container %v uses image %s with the latest tag, pin it to a specific tag or digest", c["name"], image))
+		}
+	}
+	return msgs
+}
+
+func checkProbes(resource *v1.Resource) []string {
+	if kind(resource) != "Deployment" {
+		return nil
+	}
+	var msgs []string
+	for _, c := range containers(resource, false) {
+		var missing []string
+		for _, probe := range []string{"livenessProbe", "readinessProbe"} {
+			if _, ok := c[probe]; !ok {
+				missing = append(missing, probe)
+			}
+		}
+		if len(missing) > 0 {
+			msgs = append(msgs, fmt.Sprintf("container %v has no %s", c["name"], strings.Join(missing, " and ")))
+		}
+	}
+	return msgs
+}
@@ -0,0 +1,182 @@
+package lint
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	v1 "kusionstack.io/kusion/pkg/apis/api.kusion.io/v1"
+)
+
+func newDeployment(container map[string]interface{}) v1.Resource {
+	return v1.Resource{
+		ID:   "apps/v1:Deployment:default:foo",
+		Type: v1.Kubernetes,
+		Attributes: map[string]interface{}{
+			"apiVersion": "apps/v1",
+			"kind":       "Deployment",
+			"spec": map[string]interface{}{
+				"template": map[string]interface{}{
+					"spec": map[string]interface{}{
+						"containers": []interface{}{container},
+					},
+				},
+			},
+		},
+	}
+}
+
+func newCompliantContainer() map[string]interface{} {
+	return map[string]interface{}{
+		"name":           "foo",
+		"image":          "nginx:1.25",
+		"resources":      map[string]interface{}{"limits": map[string]interface{}{"cpu": "500m", "memory": "512Mi"}},
+		"livenessProbe":  map[string]interface{}{"httpGet": map[string]interface{}{"path": "/healthz", "port": int64(80)}},
+		"readinessProbe": map[string]interface{}{"httpGet": map[string]interface{}{"path": "/readyz", "port": int64(80)}},
+	}
+}
+
+func TestLint(t *testing.T) {
+	testcases := []struct {
+		name     string
+		resource func() v1.Resource
+		opts     []Option
+		expected []LintFinding
+	}{
+		{
+			name:     "compliant deployment",
+			resource: func() v1.Resource { return newDeployment(newCompliantContainer()) },
+		},
+		{
+			name: "missing resource limits",
+			resource: func() v1.Resource {
+				c := newCompliantContainer()
+				delete(c, "resources")
+				return newDeployment(c)
+			},
+			expected: []LintFinding{
+				{
+					Rule:       RuleMissingResourceLimits,
+					Category:   CategoryResources,
+					Severity:   SeverityWarning,
+					ResourceID: "apps/v1:Deployment:default:foo",
+					Message:    "container foo has no resource limits",
+				},
+			},
+		},
+		{
+			name: "missing resource limits silenced",
+			resource: func() v1.Resource {
+				c := newCompliantContainer()
+				delete(c, "resources")
+				return newDeployment(c)
+			},
+			opts: []Option{WithDisabledRules(RuleMissingResourceLimits)},
+		},
+		{
+			name: "latest image tag",
+			resource: func() v1.Resource {
+				c := newCompliantContainer()
+				c["image"] = "nginx"
+				return newDeployment(c)
+			},
+			expected: []LintFinding{
+				{
+					Rule:       RuleLatestImageTag,
+					Category:   CategoryReproducibility,
+					Severity:   SeverityWarning,
+					ResourceID: "apps/v1:Deployment:default:foo",
+					Message:    "container foo uses image nginx with the latest tag, pin it to a specific tag or digest",
+				},
+			},
+		},
+		{
+			name: "latest image tag silenced",
+			resource: func() v1.Resource {
+				c := newCompliantContainer()
+				c["image"] = "nginx:latest"
+				return newDeployment(c)
+			},
+			opts: []Option{WithDisabledRules(RuleLatestImageTag)},
+		},
+		{
+			name: "image digest",
+			resource: func() v1.Resource {
+				c := newCompliantContainer()
+				c["image"] = "nginx@sha256:0000000000000000000000000000000000000000000000000000000000000000"
+				return newDeployment(c)
+			},
+		},
+		{
+			name: "missing probes",
+			resource: func() v1.Resource {
+				c := newCompliantContainer()
+				delete(c, "livenessProbe")
+				delete(c, "readinessProbe")
+				return newDeployment(c)
+			},
+			expected: []LintFinding{
+				{
+					Rule:       RuleMissingProbes,
+					Category:   CategoryReliability,
+					Severity:   SeverityWarning,
+					ResourceID: "apps/v1:Deployment:default:foo",
+					Message:    "container foo has no livenessProbe and readinessProbe",
+				},
+			},
+		},
+		{
+			name: "missing probes silenced",
+			resource: func() v1.Resource {
+				c := newCompliantContainer()
+				delete(c, "readinessProbe")
+				return newDeployment(c)
+			},
+			opts: []Option{WithDisabledRules(RuleMissingProbes)},
+		},
+		{
+			name: "probes not required for job",
+			resource: func() v1.Resource {
+				c := newCompliantContainer()
+				delete(c, "livenessProbe")
+				delete(c, "readinessProbe")
+				r := newDeployment(c)
+				r.ID = "batch/v1:Job:default:foo"
+				r.Attributes["kind"] = "Job"
+				return r
+			},
+		},
+		{
+			name: "non kubernetes resource",
+			resource: func() v1.Resource {
+				return v1.Resource{
+					ID:         "hashicorp:aws:aws_db_instance:foo",
+					Type:       v1.Terraform,
+					Attributes: map[string]interface{}{"image": "nginx"},
+				}
+			},
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			spec := &v1.Spec{Resources: v1.Resources{tc.resource()}}
+			assert.Equal(t, tc.expected, Lint(spec, tc.opts...))
+		})
+	}
+}
+
+func TestLintAllRules(t *testing.T) {
+	spec := &v1.Spec{Resources: v1.Resources{
+		newDeployment(map[string]interface{}{"name": "foo", "image": "nginx:latest"}),
+	}}
+
+	var rules []string
+	for _, f := range Lint(spec) {
+		rules = append(rules, f.Rule)
+	}
+	assert.Equal(t, []string{RuleMissingResourceLimits, RuleLatestImageTag, RuleMissingProbes}, rules)
+
+	findings := Lint(spec, WithDisabledRules(RuleMissingResourceLimits, RuleLatestImageTag, RuleMissingProbes))
+	assert.Empty(t, findings)
+}
@@ -0,0 +1,160 @@
+// Package mutation mutates the generated Spec with the central mutation service of the organization
+// before preview and apply.
+package mutation
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"time"
+
+	"gopkg.in/yaml.v3"
+
+	v1 "kusionstack.io/kusion/pkg/apis/api.kusion.io/v1"
+	"kusionstack.io/kusion/pkg/engine/release"
+	"kusionstack.io/kusion/pkg/log"
+)
+
+// FailurePolicy defines how the failure of the mutation is handled.
+type FailurePolicy string
+
+const (
+	// FailurePolicyFail fails the operation if the mutation fails, i.e. fail-closed.
+	FailurePolicyFail FailurePolicy = "Fail"
+	// FailurePolicyIgnore ignores the failure of the mutation and goes on with the original Spec,
+	// i.e. fail-open.
+	FailurePolicyIgnore FailurePolicy = "Ignore"
+)
+
+// DefaultTimeout is the default timeout of the mutation webhook.
+const DefaultTimeout = 10 * time.Second
+
+// The environment variables to configure the mutation webhook.
+const (
+	EnvWebhookURL           = "KUSION_MUTATION_WEBHOOK_URL"
+	EnvWebhookTimeout       = "KUSION_MUTATION_WEBHOOK_TIMEOUT"
+	EnvWebhookFailurePolicy = "KUSION_MUTATION_WEBHOOK_FAILURE_POLICY"
+)
+
+var (
+	ErrInvalidFailurePolicy = errors.New("invalid failure policy")
+	ErrMutationFailed       = errors.New("spec mutation failed")
+)
+
+// Mutator mutates the Spec, and returns the mutated one.
+type Mutator interface {
+	Mutate(ctx context.Context, spec *v1.Spec) (*v1.Spec, error)
+}
+
+var _ Mutator = (*Webhook)(nil)
+
+// Webhook is the Mutator which posts the Spec in JSON to the HTTP endpoint, which responds with the
+// possibly mutated Spec in JSON with the status 200.
+type Webhook struct {
+	url           string
+	timeout       time.Duration
+	failurePolicy FailurePolicy
+	client        *http.Client
+}
+
+// NewWebhook returns the Webhook of the URL. The timeout defaults to DefaultTimeout, and the failure
+// policy defaults to FailurePolicyFail.
+func NewWebhook(url string, timeout time.Duration, failurePolicy FailurePolicy) (*Webhook, error) {
+	if timeout <= 0 {
+		timeout = DefaultTimeout
+	}
+	switch failurePolicy {
+	case "":
+		failurePolicy = FailurePolicyFail
+	case FailurePolicyFail, FailurePolicyIgnore:
+	default:
+		return nil, fmt.Errorf("%w: %s, expected %s or %s", ErrInvalidFailurePolicy, failurePolicy, FailurePolicyFail, FailurePolicyIgnore)
+	}
+	return &Webhook{url: url, timeout: timeout, failurePolicy: failurePolicy, client: &http.Client{}}, nil
+}
+
+// NewWebhookFromEnv returns the Webhook configured by the environment variables, or nil if the URL is
+// not configured. The timeout is in the format of time.ParseDuration, e.g. "30s".
+func NewWebhookFromEnv() (*Webhook, error) {
+	url := os.Getenv(EnvWebhookURL)
+	if url == "" {
+		return nil, nil
+	}
+	var timeout time.Duration
+	if t := os.Getenv(EnvWebhookTimeout); t != "" {
+		var err error
+		if timeout, err = time.ParseDuration(t); err != nil {
+			return nil, fmt.Errorf("invalid %s: %w", EnvWebhookTimeout, err)
+		}
+	}
+	return NewWebhook(url, timeout, FailurePolicy(os.Getenv(EnvWebhookFailurePolicy)))
+}
+
+// MutateWithEnvWebhook mutates the Spec with the Webhook configured by the environment variables, and
+// returns the Spec unchanged if not configured.
+func MutateWithEnvWebhook(ctx context.Context, spec *v1.Spec) (*v1.Spec, error) {
+	webhook, err := NewWebhookFromEnv()
+	if err != nil || webhook == nil {
+		return spec, err
+	}
+	return webhook.Mutate(ctx, spec)
+}
+
+// Mutate posts the Spec to the webhook, and returns the mutated Spec after validating it. The original
+// Spec is returned if the mutation fails, including timing out and responding with an invalid Spec,
+// and the failure policy is FailurePolicyIgnore.
+func (w *Webhook) Mutate(ctx context.Context, spec *v1.Spec) (*v1.Spec, error) {
+	mutated, err := w.mutate(ctx, spec)
+	if err != nil {
+		if w.failurePolicy == FailurePolicyIgnore {
+			log.Warnf("ignore the failure of mutation webhook %s: %v", w.url, err)
+			return spec, nil
+		}
+		return nil, fmt.Errorf("%w: webhook %s, %v", ErrMutationFailed, w.url, err)
+	}
+	return mutated, nil
+}
+
+func (w *Webhook) mutate(ctx context.Context, spec *v1.Spec) (*v1.Spec, error) {
+	ctx, cancel := context.WithTimeout(ctx, w.timeout)
+	defer cancel()
+
+	body, err := json.Marshal(spec)
+	if err != nil {
+		return nil, err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, w.url, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := w.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status %s, %s", resp.Status, bytes.TrimSpace(data))
+	}
+
+	// decode the JSON with yaml.v3 the same as the Spec file, which keeps the integers as int instead
+	// of float64
+	mutated := &v1.Spec{}
+	if err = yaml.Unmarshal(data, mutated); err != nil {
+		return nil, fmt.Errorf("invalid spec in response: %w", err)
+	}
+	if err = release.ValidateSpec(mutated); err != nil {
+		return nil, fmt.Errorf("invalid spec in response: %w", err)
+	}
+	return mutated, nil
+}
@@ -0,0 +1,159 @@
+package mutation
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	v1 "kusionstack.io/kusion/pkg/apis/api.kusion.io/v1"
+)
+
+func newSpec() *v1.Spec {
+	return &v1.Spec{
+		Resources: v1.Resources{
+			{
+				ID:   "apps/v1:Deployment:foo:bar",
+				Type: v1.Kubernetes,
+				Attributes: map[string]interface{}{
+					"apiVersion": "apps/v1",
+					"kind":       "Deployment",
+					"metadata":   map[string]interface{}{"name": "bar", "namespace": "foo"},
+					"spec":       map[string]interface{}{"replicas": 1},
+				},
+				Extensions: map[string]interface{}{v1.ResourceExtensionGVK: "apps/v1, Kind=Deployment"},
+			},
+		},
+	}
+}
+
+// newMutatingServer returns the server which sets the label of the resources.
+func newMutatingServer(t *testing.T) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		spec := &v1.Spec{}
+		require.NoError(t, json.NewDecoder(r.Body).Decode(spec))
+		for _, res := range spec.Resources {
+			metadata := res.Attributes["metadata"].(map[string]interface{})
+			metadata["labels"] = map[string]interface{}{"team": "platform"}
+		}
+		require.NoError(t, json.NewEncoder(w).Encode(spec))
+	}))
+}
+
+func TestWebhook_Mutate(t *testing.T) {
+	mutating := newMutatingServer(t)
+	defer mutating.Close()
+
+	slow := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-r.Context().Done():
+		case <-time.After(time.Second):
+		}
+	}))
+	defer slow.Close()
+
+	invalid := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// the resource misses the gvk extension
+		_, _ = w.Write([]byte(`{"resources":[{"id":"v1:Namespace:foo","type":"Kubernetes","attributes":{}}]}`))
+	}))
+	defer invalid.Close()
+
+	failed := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "internal error", http.StatusInternalServerError)
+	}))
+	defer failed.Close()
+
+	mutatedSpec := newSpec()
+	mutatedSpec.Resources[0].Attributes["metadata"].(map[string]interface{})["labels"] = map[string]interface{}{"team": "platform"}
+
+	testcases := []struct {
+		name          string
+		url           string
+		failurePolicy FailurePolicy
+		expected      *v1.Spec
+		success       bool
+	}{
+		{
+			name:     "mutate resource",
+			url:      mutating.URL,
+			expected: mutatedSpec,
+			success:  true,
+		},
+		{
+			name:    "timeout with fail policy",
+			url:     slow.URL,
+			success: false,
+		},
+		{
+			name:          "timeout with ignore policy",
+			url:           slow.URL,
+			failurePolicy: FailurePolicyIgnore,
+			expected:      newSpec(),
+			success:       true,
+		},
+		{
+			name:    "invalid spec",
+			url:     invalid.URL,
+			success: false,
+		},
+		{
+			name:          "invalid spec with ignore policy",
+			url:           invalid.URL,
+			failurePolicy: FailurePolicyIgnore,
+			expected:      newSpec(),
+			success:       true,
+		},
+		{
+			name:    "error status",
+			url:     failed.URL,
+			success: false,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			webhook, err := NewWebhook(tc.url, 100*time.Millisecond, tc.failurePolicy)
+			require.NoError(t, err)
+			spec, err := webhook.Mutate(context.Background(), newSpec())
+			assert.Equal(t, tc.success, err == nil)
+			if tc.success {
+				assert.Equal(t, tc.expected, spec)
+			} else {
+				assert.True(t, errors.Is(err, ErrMutationFailed), "unexpected error: %v", err)
+			}
+		})
+	}
+}
+
+func TestNewWebhook_InvalidFailurePolicy(t *testing.T) {
+	_, err := NewWebhook("http://localhost", 0, "Retry")
+	assert.True(t, errors.Is(err, ErrInvalidFailurePolicy))
+}
+
+func TestMutateWithEnvWebhook(t *testing.T) {
+	server := newMutatingServer(t)
+	defer server.Close()
+
+	t.Setenv(EnvWebhookURL, "")
+	spec := newSpec()
+	actual, err := MutateWithEnvWebhook(context.Background(), spec)
+	assert.NoError(t, err)
+	assert.Same(t, spec, actual)
+
+	t.Setenv(EnvWebhookURL, server.URL)
+	t.Setenv(EnvWebhookTimeout, "5s")
+	t.Setenv(EnvWebhookFailurePolicy, string(FailurePolicyFail))
+	actual, err = MutateWithEnvWebhook(context.Background(), newSpec())
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{"team": "platform"}, actual.Resources[0].Attributes["metadata"].(map[string]interface{})["labels"])
+
+	t.Setenv(EnvWebhookTimeout, "5")
+	_, err = MutateWithEnvWebhook(context.Background(), newSpec())
+	assert.Error(t, err)
+}
@@ -4,6 +4,7 @@ import (
 	"errors"
 	"fmt"
 	"sync"
+	"time"
 
 	"github.com/jinzhu/copier"
 
@@ -14,7 +15,9 @@ import (
 	"kusionstack.io/kusion/pkg/engine/operation/parser"
 	"kusionstack.io/kusion/pkg/engine/release"
 	resourcegraph "kusionstack.io/kusion/pkg/engine/resource/graph"
+	"kusionstack.io/kusion/pkg/engine/runtime"
 	runtimeinit "kusionstack.io/kusion/pkg/engine/runtime/init"
+	"kusionstack.io/kusion/pkg/engine/runtime/recording"
 	"kusionstack.io/kusion/pkg/log"
 	"kusionstack.io/kusion/third_party/terraform/dag"
 	"kusionstack.io/kusion/third_party/terraform/tfdiags"
@@ -28,11 +31,16 @@ type ApplyRequest struct {
 	models.Request
 	Release *apiv1.Release
 	Graph   *apiv1.Graph
+	// DryRun means running the whole apply process against the recording runtimes, which record the
+	// intended operations without performing them, and the release is not saved.
+	DryRun bool
 }
 
 type ApplyResponse struct {
 	Release *apiv1.Release
 	Graph   *apiv1.Graph
+	// DryRunOperations are the intended operations recorded in the dry-run apply in execution order.
+	DryRunOperations []recording.Operation
 }
 
 // Apply means turn all actual infra resources into the desired state described in the request by invoking a specified Runtime.
@@ -44,8 +52,13 @@ func (ao *ApplyOperation) Apply(req *ApplyRequest) (rsp *ApplyResponse, s v1.Sta
 	log.Infof("engine: Apply start!")
 	o := ao.Operation
 
+	// applyOperation walks the graph, which is nil until the apply starts walking
+	var applyOperation *ApplyOperation
 	defer func() {
 		close(o.MsgCh)
+		if o.EventCh != nil {
+			close(o.EventCh)
+		}
 
 		if e := recover(); e != nil {
 			log.Error("apply panic:%v", e)
@@ -59,6 +72,17 @@ func (ao *ApplyOperation) Apply(req *ApplyRequest) (rsp *ApplyResponse, s v1.Sta
 				s = v1.NewErrorStatusWithCode(v1.Unknown, errors.New("unknown panic"))
 			}
 		}
+
+		// make sure the release is never left in applying when the apply fails
+		if v1.IsErr(s) && req != nil {
+			var updated *apiv1.Release
+			var failedResource string
+			if applyOperation != nil {
+				updated = applyOperation.Release
+				failedResource = applyOperation.FailedResource()
+			}
+			markReleaseFailed(o.ReleaseStorage, req.DryRun, s, failedResource, req.Release, updated)
+		}
 	}()
 
 	if s = validateApplyRequest(req); v1.IsErr(s) {
@@ -79,11 +103,18 @@ func (ao *ApplyOperation) Apply(req *ApplyRequest) (rsp *ApplyResponse, s v1.Sta
 		stateResourceIndex[k] = v
 	}
 
-	runtimesMap, s := runtimeinit.Runtimes(*req.Release.Spec, *req.Release.State)
-	if v1.IsErr(s) {
-		return nil, s
+	var recorder *recording.RecordingRuntime
+	if req.DryRun {
+		recorder = recording.NewRecordingRuntime()
+		o.RuntimeMap = recordingRuntimes(recorder, req.Release.Spec, priorState)
+	} else {
+		var runtimesMap map[apiv1.Type]runtime.Runtime
+		runtimesMap, s = runtimeinit.Runtimes(*req.Release.Spec, *req.Release.State)
+		if v1.IsErr(s) {
+			return nil, s
+		}
+		o.RuntimeMap = runtimesMap
 	}
-	o.RuntimeMap = runtimesMap
 
 	// 2. build & walk DAG
 	applyGraph, s := newApplyGraph(req.Release.Spec, priorState)
@@ -98,7 +129,7 @@ func (ao *ApplyOperation) Apply(req *ApplyRequest) (rsp *ApplyResponse, s v1.Sta
 	if v1.IsErr(s) {
 		return nil, s
 	}
-	applyOperation := &ApplyOperation{
+	applyOperation = &ApplyOperation{
 		Operation: models.Operation{
 			OperationType:           models.Apply,
 			ReleaseStorage:          o.ReleaseStorage,
@@ -111,21 +142,49 @@ func (ao *ApplyOperation) Apply(req *ApplyRequest) (rsp *ApplyResponse, s v1.Sta
 			IgnoreFields:            o.IgnoreFields,
 			MsgCh:                   o.MsgCh,
 			WatchCh:                 o.WatchCh,
+			EventCh:                 o.EventCh,
 			Lock:                    &sync.Mutex{},
 			Release:                 rel,
 			Sem:                     o.Sem,
+			DryRun:                  req.DryRun,
 		},
 	}
 
+	applyOperation.Release.SetCondition(apiv1.ReleaseCondition{
+		Type: apiv1.ReleaseConditionApplied, Status: apiv1.ConditionUnknown, Reason: "Applying",
+	})
+	applyOperation.SendEvent(models.Event{Type: models.PhaseChanged, Phase: apiv1.ReleasePhaseApplying})
 	w := &dag.Walker{Callback: applyOperation.walkFun}
 	w.Update(applyGraph)
 	// Wait
 	if diags := w.Wait(); diags.HasErrors() {
+		applyOperation.SendEvent(models.Event{Type: models.PhaseChanged, Phase: apiv1.ReleasePhaseFailed})
 		s = v1.NewErrorStatus(diags.Err())
 		return nil, s
 	}
+	applyOperation.Release.SetCondition(apiv1.ReleaseCondition{
+		Type: apiv1.ReleaseConditionApplied, Status: apiv1.ConditionTrue, Reason: "Succeeded",
+	})
+	applyOperation.SendEvent(models.Event{Type: models.PhaseChanged, Phase: apiv1.ReleasePhaseSucceeded})
+
+	rsp = &ApplyResponse{Release: applyOperation.Release, Graph: resourceGraph}
+	if recorder != nil {
+		rsp.DryRunOperations = recorder.Operations()
+	}
+	return rsp, nil
+}
 
-	return &ApplyResponse{Release: applyOperation.Release, Graph: resourceGraph}, nil
+// recordingRuntimes returns the runtime map whose runtimes of all the resource types in the spec and
+// prior state are the recorder.
+func recordingRuntimes(recorder runtime.Runtime, spec *apiv1.Spec, priorState *apiv1.State) map[apiv1.Type]runtime.Runtime {
+	runtimesMap := map[apiv1.Type]runtime.Runtime{}
+	for _, r := range spec.Resources {
+		runtimesMap[r.Type] = recorder
+	}
+	for _, r := range priorState.Resources {
+		runtimesMap[r.Type] = recorder
+	}
+	return runtimesMap
 }
 
 func (ao *ApplyOperation) walkFun(v dag.Vertex) (diags tfdiags.Diagnostics) {
@@ -139,6 +198,9 @@ func validateApplyRequest(req *ApplyRequest) v1.Status {
 	if err := release.ValidateRelease(req.Release); err != nil {
 		return v1.NewErrorStatusWithMsg(v1.InvalidArgument, err.Error())
 	}
+	if err := req.Release.Spec.Validate(); err != nil {
+		return v1.NewErrorStatusWithMsg(v1.InvalidArgument, err.Error())
+	}
 	if err := resourcegraph.ValidateGraph(req.Graph); err != nil {
 		return v1.NewErrorStatusWithMsg(v1.InvalidArgument, err.Error())
 	}
@@ -174,6 +236,32 @@ func copyRelease(r *apiv1.Release) (*apiv1.Release, v1.Status) {
 	return rel, nil
 }
 
+// markReleaseFailed marks the requested release and its copy updated in the operation failed with the
+// message of the status and the failed resource, where the copy is also saved in the storage unless dryRun,
+// as the requested one is saved by the caller. The release already in a terminal phase is left unchanged.
+func markReleaseFailed(storage release.Storage, dryRun bool, s v1.Status, failedResource string, requested, updated *apiv1.Release) {
+	for _, rel := range []*apiv1.Release{requested, updated} {
+		if rel == nil {
+			continue
+		}
+		if err := release.SetFailed(rel, s.Message(), failedResource); err != nil {
+			log.Errorf("failed to mark release failed, project %s, workspace %s, revision %d: %v",
+				rel.Project, rel.Workspace, rel.Revision, err)
+			continue
+		}
+		rel.SetCondition(apiv1.ReleaseCondition{
+			Type: apiv1.ReleaseConditionApplied, Status: apiv1.ConditionFalse, Reason: "Failed", Message: s.Message(),
+		})
+	}
+	if updated == nil || updated.Phase != apiv1.ReleasePhaseFailed || dryRun || storage == nil {
+		return
+	}
+	if err := storage.Update(updated); err != nil {
+		log.Errorf("failed to update failed release, project %s, workspace %s, revision %d: %v",
+			updated.Project, updated.Workspace, updated.Revision, err)
+	}
+}
+
 func applyWalkFun(o *models.Operation, v dag.Vertex) (diags tfdiags.Diagnostics) {
 	var s v1.Status
 	if v == nil {
@@ -185,17 +273,33 @@ func applyWalkFun(o *models.Operation, v dag.Vertex) (diags tfdiags.Diagnostics)
 		defer o.Sem.Release()
 	}
 
+	// Stop scheduling the resources once any resource failed, while the resources in flight are
+	// not interrupted and will finish their executions.
+	if o.IsFailed() {
+		if rn, ok := v.(*graph.ResourceNode); ok {
+			log.Infof("skip resource %s as the operation has failed", rn.Hashcode())
+		}
+		return nil
+	}
+
 	if node, ok := v.(graph.ExecutableNode); ok {
 		if rn, ok2 := v.(*graph.ResourceNode); ok2 {
 			o.MsgCh <- models.Message{ResourceID: rn.Hashcode().(string)}
+			start := time.Now()
+			o.SendEvent(models.Event{Type: models.ResourceStarted, ResourceID: rn.Hashcode().(string), Timestamp: start})
 
 			s = node.Execute(o)
 			if v1.IsErr(s) {
+				o.SendEvent(models.Event{
+					Type: models.ResourceFailed, ResourceID: rn.Hashcode().(string),
+					Duration: time.Since(start), Err: fmt.Errorf("%v", s),
+				})
 				o.MsgCh <- models.Message{
 					ResourceID: rn.Hashcode().(string), OpResult: models.Failed,
 					OpErr: fmt.Errorf("node execte failed, status:\n%v", s),
 				}
 			} else {
+				o.SendEvent(models.Event{Type: models.ResourceSucceeded, ResourceID: rn.Hashcode().(string), Duration: time.Since(start)})
 				o.MsgCh <- models.Message{ResourceID: rn.Hashcode().(string), OpResult: models.Success}
 			}
 		} else {
@@ -203,6 +307,11 @@ func applyWalkFun(o *models.Operation, v dag.Vertex) (diags tfdiags.Diagnostics)
 		}
 	}
 	if s != nil {
+		if rn, ok := v.(*graph.ResourceNode); ok {
+			o.MarkResourceFailed(rn.Hashcode().(string))
+		} else {
+			o.MarkFailed()
+		}
 		diags = diags.Append(fmt.Errorf("apply failed, status:\n%v", s))
 	}
 	return diags
@@ -1,8 +1,10 @@
 package operation
 
 import (
+	"errors"
 	"reflect"
 	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 
@@ -18,7 +20,10 @@ import (
 	"kusionstack.io/kusion/pkg/engine/runtime"
 	runtimeinit "kusionstack.io/kusion/pkg/engine/runtime/init"
 	"kusionstack.io/kusion/pkg/engine/runtime/kubernetes"
+	"kusionstack.io/kusion/pkg/engine/runtime/recording"
+	"kusionstack.io/kusion/pkg/infra/util/semaphore"
 	"kusionstack.io/kusion/third_party/terraform/dag"
+	"kusionstack.io/kusion/third_party/terraform/tfdiags"
 )
 
 func TestApplyOperation_Apply(t *testing.T) {
@@ -32,6 +37,7 @@ func TestApplyOperation_Apply(t *testing.T) {
 		runtimeMap              map[apiv1.Type]runtime.Runtime
 		stack                   *apiv1.Stack
 		msgCh                   chan models.Message
+		eventCh                 chan models.Event
 		release                 *apiv1.Release
 		lock                    *sync.Mutex
 	}
@@ -47,7 +53,8 @@ func TestApplyOperation_Apply(t *testing.T) {
 				Attributes: map[string]interface{}{
 					"a": "b",
 				},
-				DependsOn: nil,
+				DependsOn:  nil,
+				Extensions: map[string]interface{}{apiv1.ResourceExtensionGVK: "/v1, Kind=Service"},
 			},
 		},
 	}
@@ -106,6 +113,7 @@ func TestApplyOperation_Apply(t *testing.T) {
 		args             args
 		expectedResponse *ApplyResponse
 		expectedStatus   v1.Status
+		expectedEvents   []models.Event
 	}{
 		{
 			name: "apply test",
@@ -114,6 +122,7 @@ func TestApplyOperation_Apply(t *testing.T) {
 				releaseStorage: &storages.LocalStorage{},
 				runtimeMap:     map[apiv1.Type]runtime.Runtime{runtime.Kubernetes: &kubernetes.KubernetesRuntime{}},
 				msgCh:          make(chan models.Message, 5),
+				eventCh:        make(chan models.Event, 10),
 			},
 			args: args{applyRequest: &ApplyRequest{
 				Request: models.Request{
@@ -125,6 +134,12 @@ func TestApplyOperation_Apply(t *testing.T) {
 			}},
 			expectedResponse: &ApplyResponse{Release: fakeUpdateRelease, Graph: fakeGraph},
 			expectedStatus:   nil,
+			expectedEvents: []models.Event{
+				{Type: models.PhaseChanged, Phase: apiv1.ReleasePhaseApplying},
+				{Type: models.ResourceStarted, ResourceID: "mock-id"},
+				{Type: models.ResourceSucceeded, ResourceID: "mock-id"},
+				{Type: models.PhaseChanged, Phase: apiv1.ReleasePhaseSucceeded},
+			},
 		},
 	}
 
@@ -140,6 +155,7 @@ func TestApplyOperation_Apply(t *testing.T) {
 				RuntimeMap:              tc.fields.runtimeMap,
 				Stack:                   tc.fields.stack,
 				MsgCh:                   tc.fields.msgCh,
+				EventCh:                 tc.fields.eventCh,
 				Release:                 tc.fields.release,
 				Lock:                    tc.fields.lock,
 			}
@@ -160,6 +176,13 @@ func TestApplyOperation_Apply(t *testing.T) {
 			rsp, status := ao.Apply(tc.args.applyRequest)
 			assert.Equal(t, tc.expectedResponse, rsp)
 			assert.Equal(t, tc.expectedStatus, status)
+
+			var events []models.Event
+			for e := range tc.fields.eventCh {
+				assert.False(t, e.Timestamp.IsZero())
+				events = append(events, models.Event{Type: e.Type, ResourceID: e.ResourceID, Phase: e.Phase})
+			}
+			assert.Equal(t, tc.expectedEvents, events)
 		})
 	}
 }
@@ -275,3 +298,397 @@ func Test_populateResourceGraph(t *testing.T) {
 		})
 	}
 }
+
+type mockExecutableNode struct {
+	id      string
+	execute func() v1.Status
+}
+
+func (n *mockExecutableNode) Hashcode() interface{} {
+	return n.id
+}
+
+func (n *mockExecutableNode) Name() string {
+	return n.id
+}
+
+func (n *mockExecutableNode) Execute(_ *models.Operation) v1.Status {
+	return n.execute()
+}
+
+// newMockDiamondGraph builds the graph top -> (left, right) -> bottom.
+func newMockDiamondGraph(top, left, right, bottom dag.Vertex) *dag.AcyclicGraph {
+	g := &dag.AcyclicGraph{}
+	for _, v := range []dag.Vertex{top, left, right, bottom} {
+		g.Add(v)
+	}
+	g.Connect(dag.BasicEdge(top, left))
+	g.Connect(dag.BasicEdge(top, right))
+	g.Connect(dag.BasicEdge(left, bottom))
+	g.Connect(dag.BasicEdge(right, bottom))
+	return g
+}
+
+func Test_applyWalkFunConcurrency(t *testing.T) {
+	var mu sync.Mutex
+	var executed []string
+	record := func(id string) {
+		mu.Lock()
+		defer mu.Unlock()
+		executed = append(executed, id)
+	}
+
+	// the middle layer only succeeds if both left and right are running at the same time
+	var middle sync.WaitGroup
+	middle.Add(2)
+	middleFunc := func(id string) func() v1.Status {
+		return func() v1.Status {
+			middle.Done()
+			done := make(chan struct{})
+			go func() {
+				middle.Wait()
+				close(done)
+			}()
+			select {
+			case <-done:
+				record(id)
+				return nil
+			case <-time.After(5 * time.Second):
+				return v1.NewErrorStatus(errors.New("middle layer is not running concurrently"))
+			}
+		}
+	}
+	top := &mockExecutableNode{id: "top", execute: func() v1.Status { record("top"); return nil }}
+	left := &mockExecutableNode{id: "left", execute: middleFunc("left")}
+	right := &mockExecutableNode{id: "right", execute: middleFunc("right")}
+	bottom := &mockExecutableNode{id: "bottom", execute: func() v1.Status { record("bottom"); return nil }}
+
+	o := &models.Operation{Sem: semaphore.New(2)}
+	w := &dag.Walker{Callback: func(v dag.Vertex) tfdiags.Diagnostics { return applyWalkFun(o, v) }}
+	w.Update(newMockDiamondGraph(top, left, right, bottom))
+	diags := w.Wait()
+
+	assert.False(t, diags.HasErrors())
+	assert.False(t, o.IsFailed())
+	assert.Len(t, executed, 4)
+	assert.Equal(t, "top", executed[0])
+	assert.ElementsMatch(t, []string{"left", "right"}, executed[1:3])
+	assert.Equal(t, "bottom", executed[3])
+}
+
+func Test_applyWalkFunStopOnFailure(t *testing.T) {
+	var rightFinished, bottomExecuted, afterRightExecuted atomic.Bool
+	rightStarted, leftFailed := make(chan struct{}), make(chan struct{})
+
+	top := &mockExecutableNode{id: "top", execute: func() v1.Status { return nil }}
+	left := &mockExecutableNode{id: "left", execute: func() v1.Status {
+		defer close(leftFailed)
+		<-rightStarted
+		return v1.NewErrorStatus(errors.New("left failed"))
+	}}
+	// right is in flight when left fails, and should finish its execution
+	right := &mockExecutableNode{id: "right", execute: func() v1.Status {
+		close(rightStarted)
+		<-leftFailed
+		time.Sleep(100 * time.Millisecond)
+		rightFinished.Store(true)
+		return nil
+	}}
+	bottom := &mockExecutableNode{id: "bottom", execute: func() v1.Status { bottomExecuted.Store(true); return nil }}
+	afterRight := &mockExecutableNode{id: "after-right", execute: func() v1.Status { afterRightExecuted.Store(true); return nil }}
+
+	g := newMockDiamondGraph(top, left, right, bottom)
+	g.Add(afterRight)
+	g.Connect(dag.BasicEdge(right, afterRight))
+
+	o := &models.Operation{Sem: semaphore.New(4)}
+	w := &dag.Walker{Callback: func(v dag.Vertex) tfdiags.Diagnostics { return applyWalkFun(o, v) }}
+	w.Update(g)
+	diags := w.Wait()
+
+	assert.True(t, diags.HasErrors())
+	assert.True(t, o.IsFailed())
+	assert.True(t, rightFinished.Load())
+	assert.False(t, bottomExecuted.Load())
+	assert.False(t, afterRightExecuted.Load())
+}
+
+func TestApplyOperation_ApplyResume(t *testing.T) {
+	mockey.PatchConvey("resume apply after failed midway", t, func() {
+		storage, err := storages.NewLocalStorage(t.TempDir())
+		assert.Nil(t, err)
+
+		fakeSpec := &apiv1.Spec{
+			Resources: []apiv1.Resource{
+				{
+					ID:         "v1:Namespace:default",
+					Type:       runtime.Kubernetes,
+					Attributes: map[string]interface{}{"a": "b"},
+					Extensions: map[string]interface{}{apiv1.ResourceExtensionGVK: "/v1, Kind=Namespace"},
+				},
+				{
+					ID:         "v1:Service:default:svc",
+					Type:       runtime.Kubernetes,
+					Attributes: map[string]interface{}{"c": "d"},
+					DependsOn:  []string{"v1:Namespace:default"},
+					Extensions: map[string]interface{}{apiv1.ResourceExtensionGVK: "/v1, Kind=Service"},
+				},
+			},
+		}
+		fakeRelease := &apiv1.Release{
+			Project:   "fake-project",
+			Workspace: "fake-workspace",
+			Revision:  1,
+			Stack:     "fake-stack",
+			Spec:      fakeSpec,
+			State:     &apiv1.State{},
+			Phase:     apiv1.ReleasePhaseApplying,
+		}
+		assert.Nil(t, storage.Create(fakeRelease))
+		fakeGraph := &apiv1.Graph{Project: fakeRelease.Project, Workspace: fakeRelease.Workspace}
+		resourcegraph.GenerateGraph(fakeSpec.Resources, fakeGraph)
+
+		// the service fails in the first apply, and succeeds after resuming
+		var executed []string
+		failedID := "v1:Service:default:svc"
+		mockey.Mock((*graph.ResourceNode).Execute).To(func(rn *graph.ResourceNode, operation *models.Operation) v1.Status {
+			id := rn.Hashcode().(string)
+			executed = append(executed, id)
+			if id == failedID {
+				return v1.NewErrorStatus(errors.New("mock apply failed"))
+			}
+			if e := operation.RefreshResourceIndex(id, rn.State(), models.Create); e != nil {
+				return v1.NewErrorStatus(e)
+			}
+			if e := operation.UpdateReleaseState(); e != nil {
+				return v1.NewErrorStatus(e)
+			}
+			return nil
+		}).Build()
+		mockey.Mock(runtimeinit.Runtimes).Return(
+			map[apiv1.Type]runtime.Runtime{runtime.Kubernetes: &kubernetes.KubernetesRuntime{}}, nil).Build()
+		mockey.Mock(populateResourceGraph).Return(fakeGraph).Build()
+		mockey.Mock(validateApplyRequest).Return(nil).Build()
+		// the process crashes rather than fails, leaving no chance to mark the release failed
+		mockey.Mock(markReleaseFailed).Return().Build()
+
+		apply := func(rel *apiv1.Release) (*ApplyResponse, v1.Status) {
+			ao := &ApplyOperation{Operation: models.Operation{
+				OperationType:  models.Apply,
+				ReleaseStorage: storage,
+				MsgCh:          make(chan models.Message, 10),
+			}}
+			return ao.Apply(&ApplyRequest{Release: rel, Graph: fakeGraph})
+		}
+
+		// failed midway, the release is left in applying phase with the namespace applied
+		_, s := apply(fakeRelease)
+		assert.True(t, v1.IsErr(s))
+		assert.Equal(t, []string{"v1:Namespace:default", failedID}, executed)
+
+		resumed, err := release.GetResumableRelease(storage)
+		assert.Nil(t, err)
+		assert.Len(t, resumed.State.Resources, 1)
+		assert.Equal(t, "v1:Namespace:default", resumed.State.Resources[0].ID)
+
+		// resume, the applied namespace is re-verified rather than skipped, and the service is applied
+		executed = nil
+		failedID = ""
+		rsp, s := apply(resumed)
+		assert.Nil(t, s)
+		assert.ElementsMatch(t, []string{"v1:Namespace:default", "v1:Service:default:svc"}, executed)
+		assert.Len(t, rsp.Release.State.Resources, 2)
+	})
+}
+
+func TestApplyOperation_ApplyMarkFailed(t *testing.T) {
+	fakeSpec := &apiv1.Spec{
+		Resources: []apiv1.Resource{
+			{
+				ID:         "v1:Namespace:default",
+				Type:       runtime.Kubernetes,
+				Attributes: map[string]interface{}{"a": "b"},
+				Extensions: map[string]interface{}{apiv1.ResourceExtensionGVK: "/v1, Kind=Namespace"},
+			},
+		},
+	}
+	newRelease := func() *apiv1.Release {
+		return &apiv1.Release{
+			Project:      "fake-project",
+			Workspace:    "fake-workspace",
+			Revision:     1,
+			Stack:        "fake-stack",
+			Spec:         fakeSpec,
+			State:        &apiv1.State{},
+			Phase:        apiv1.ReleasePhaseApplying,
+			CreateTime:   time.Now(),
+			ModifiedTime: time.Now(),
+		}
+	}
+	fakeGraph := &apiv1.Graph{Project: "fake-project", Workspace: "fake-workspace"}
+	resourcegraph.GenerateGraph(fakeSpec.Resources, fakeGraph)
+
+	testcases := []struct {
+		name          string
+		execute       func(operation *models.Operation) v1.Status
+		runtimesPanic bool
+		expectedErr   string
+		failedID      string
+		persisted     bool
+	}{
+		{
+			name: "apply error",
+			execute: func(operation *models.Operation) v1.Status {
+				return v1.NewErrorStatus(errors.New("mock apply failed"))
+			},
+			expectedErr: "mock apply failed",
+			failedID:    "v1:Namespace:default",
+			persisted:   true,
+		},
+		{
+			name:          "panic before walking",
+			runtimesPanic: true,
+			expectedErr:   "apply panic:mock panic",
+		},
+	}
+
+	for _, tc := range testcases {
+		mockey.PatchConvey(tc.name, t, func() {
+			storage, err := storages.NewLocalStorage(t.TempDir())
+			assert.Nil(t, err)
+			rel := newRelease()
+			assert.Nil(t, storage.Create(rel))
+
+			if tc.execute != nil {
+				mockey.Mock((*graph.ResourceNode).Execute).To(tc.execute).Build()
+			}
+			if tc.runtimesPanic {
+				mockey.Mock(runtimeinit.Runtimes).To(func(spec apiv1.Spec, state apiv1.State) (map[apiv1.Type]runtime.Runtime, v1.Status) {
+					panic("mock panic")
+				}).Build()
+			} else {
+				mockey.Mock(runtimeinit.Runtimes).Return(
+					map[apiv1.Type]runtime.Runtime{runtime.Kubernetes: &kubernetes.KubernetesRuntime{}}, nil).Build()
+			}
+			mockey.Mock(populateResourceGraph).Return(fakeGraph).Build()
+
+			ao := &ApplyOperation{Operation: models.Operation{
+				OperationType:  models.Apply,
+				ReleaseStorage: storage,
+				MsgCh:          make(chan models.Message, 10),
+			}}
+			rsp, s := ao.Apply(&ApplyRequest{Release: rel, Graph: fakeGraph})
+			assert.Nil(t, rsp)
+			assert.True(t, v1.IsErr(s))
+
+			// the requested release is marked failed with the error
+			assert.Equal(t, apiv1.ReleasePhaseFailed, rel.Phase)
+			assert.Contains(t, rel.Message, tc.expectedErr)
+			assert.Equal(t, tc.failedID, rel.FailedResource)
+
+			// the release updated in the apply is saved as failed
+			stored, err := storage.Get(1)
+			assert.Nil(t, err)
+			if tc.persisted {
+				assert.Equal(t, apiv1.ReleasePhaseFailed, stored.Phase)
+				assert.Contains(t, stored.Message, tc.expectedErr)
+				assert.Equal(t, tc.failedID, stored.FailedResource)
+				applied := stored.GetCondition(apiv1.ReleaseConditionApplied)
+				if assert.NotNil(t, applied) {
+					assert.Equal(t, apiv1.ConditionFalse, applied.Status)
+					assert.Equal(t, "Failed", applied.Reason)
+				}
+			} else {
+				assert.Equal(t, apiv1.ReleasePhaseApplying, stored.Phase)
+			}
+		})
+	}
+}
+
+func TestApplyOperation_ApplyDryRun(t *testing.T) {
+	storage, err := storages.NewLocalStorage(t.TempDir())
+	assert.Nil(t, err)
+
+	gvk := func(kind string) map[string]interface{} {
+		return map[string]interface{}{apiv1.ResourceExtensionGVK: "/v1, Kind=" + kind}
+	}
+	nsID, svcID, cmID, oldID := "v1:Namespace:default", "v1:Service:default:svc", "v1:ConfigMap:default:cm", "v1:Secret:default:old"
+	fakeSpec := &apiv1.Spec{
+		Resources: []apiv1.Resource{
+			{ID: nsID, Type: runtime.Kubernetes, Attributes: map[string]interface{}{"a": "b"}, Extensions: gvk("Namespace")},
+			{ID: svcID, Type: runtime.Kubernetes, Attributes: map[string]interface{}{"c": "new"}, DependsOn: []string{nsID}, Extensions: gvk("Service")},
+			{ID: cmID, Type: runtime.Kubernetes, Attributes: map[string]interface{}{"e": "f"}, Extensions: gvk("ConfigMap")},
+		},
+	}
+	fakeState := &apiv1.State{
+		Resources: []apiv1.Resource{
+			{ID: svcID, Type: runtime.Kubernetes, Attributes: map[string]interface{}{"c": "old"}, Extensions: gvk("Service")},
+			{ID: cmID, Type: runtime.Kubernetes, Attributes: map[string]interface{}{"e": "f"}, Extensions: gvk("ConfigMap")},
+			{ID: oldID, Type: runtime.Kubernetes, Attributes: map[string]interface{}{"g": "h"}, Extensions: gvk("Secret")},
+		},
+	}
+	loc, _ := time.LoadLocation("Asia/Shanghai")
+	fakeTime := time.Date(2024, 5, 10, 16, 48, 0, 0, loc)
+	fakeRelease := &apiv1.Release{
+		Project:      "fake-project",
+		Workspace:    "fake-workspace",
+		Revision:     1,
+		Stack:        "fake-stack",
+		Spec:         fakeSpec,
+		State:        fakeState,
+		Phase:        apiv1.ReleasePhaseApplying,
+		CreateTime:   fakeTime,
+		ModifiedTime: fakeTime,
+	}
+	assert.Nil(t, storage.Create(fakeRelease))
+	fakeGraph := &apiv1.Graph{Project: fakeRelease.Project, Workspace: fakeRelease.Workspace}
+	resourcegraph.GenerateGraph(fakeSpec.Resources, fakeGraph)
+
+	ao := &ApplyOperation{Operation: models.Operation{
+		OperationType:  models.Apply,
+		ReleaseStorage: storage,
+		MsgCh:          make(chan models.Message, 20),
+		EventCh:        make(chan models.Event, 20),
+	}}
+	rsp, s := ao.Apply(&ApplyRequest{Release: fakeRelease, Graph: fakeGraph, DryRun: true})
+	if !assert.Nil(t, s) {
+		return
+	}
+
+	// the recorded operations match the plan: the namespace is created, the service is updated after the
+	// namespace, the configmap is unchanged, and the secret removed from the spec is deleted.
+	ops := rsp.DryRunOperations
+	assert.ElementsMatch(t, []recording.Operation{
+		{Type: recording.Apply, ResourceID: nsID, ResourceType: runtime.Kubernetes},
+		{Type: recording.Apply, ResourceID: svcID, ResourceType: runtime.Kubernetes},
+		{Type: recording.Delete, ResourceID: oldID, ResourceType: runtime.Kubernetes},
+	}, ops)
+	indexOf := func(id string) int {
+		for i, op := range ops {
+			if op.ResourceID == id {
+				return i
+			}
+		}
+		return -1
+	}
+	assert.Less(t, indexOf(nsID), indexOf(svcID))
+
+	// the same event stream as a real apply is produced
+	var phases []apiv1.ReleasePhase
+	succeeded := map[string]bool{}
+	for e := range ao.EventCh {
+		switch e.Type {
+		case models.PhaseChanged:
+			phases = append(phases, e.Phase)
+		case models.ResourceSucceeded:
+			succeeded[e.ResourceID] = true
+		}
+	}
+	assert.Equal(t, []apiv1.ReleasePhase{apiv1.ReleasePhaseApplying, apiv1.ReleasePhaseSucceeded}, phases)
+	assert.Equal(t, map[string]bool{nsID: true, svcID: true, cmID: true, oldID: true}, succeeded)
+
+	// the release in the storage is not updated
+	stored, err := storage.Get(1)
+	assert.Nil(t, err)
+	assert.Equal(t, fakeState.Resources, stored.State.Resources)
+}
@@ -92,6 +92,9 @@ func (do *DestroyOperation) Destroy(req *DestroyRequest) (rsp *DestroyResponse,
 		s = v1.NewErrorStatus(diags.Err())
 		return nil, s
 	}
+	destroyOperation.Release.SetCondition(apiv1.ReleaseCondition{
+		Type: apiv1.ReleaseConditionDestroyed, Status: apiv1.ConditionTrue, Reason: "Succeeded",
+	})
 
 	return &DestroyResponse{Release: destroyOperation.Release}, nil
 }
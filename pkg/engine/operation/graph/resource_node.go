@@ -119,7 +119,7 @@ func (rn *ResourceNode) replaceK8sSecretRefs(o *models.Operation) v1.Status {
 		if err != nil {
 			return v1.NewErrorStatus(err)
 		}
-		secretData, err := secretStore.GetSecret(context.Background(), *externalSecretRef)
+		secretData, err := secrets.GetSecret(context.Background(), secretStore, *externalSecretRef)
 		if err != nil {
 			return v1.NewErrorStatus(err)
 		}
@@ -236,6 +236,10 @@ func (rn *ResourceNode) computeActionType(
 				removeNestedField(liveResource.Attributes, splits...)
 				removeNestedField(dryRunResource.Attributes, splits...)
 			}
+			// Coerce the integers decoded as float64 to avoid the spurious diffs, where the integer fields
+			// are told by the other side
+			json.NormalizeNumbersAs(liveResource.Attributes, dryRunResource.Attributes)
+			json.NormalizeNumbersAs(dryRunResource.Attributes, liveResource.Attributes)
 			report, err := diff.ToReport(liveResource, dryRunResource)
 			if err != nil {
 				return nil, v1.NewErrorStatus(err)
@@ -561,6 +565,9 @@ func ParseExternalSecretDataRef(dataRefStr string) (*apiv1.ExternalSecretRef, er
 	if len(query) > 0 && len(query.Get("version")) > 0 {
 		ref.Version = query.Get("version")
 	}
+	if len(query) > 0 && len(query.Get("transform")) > 0 {
+		ref.Transform = query.Get("transform")
+	}
 
 	return ref, nil
 }
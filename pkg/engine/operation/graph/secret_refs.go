@@ -0,0 +1,115 @@
+package graph
+
+import (
+	"encoding/base64"
+	"sort"
+	"strings"
+
+	apiv1 "kusionstack.io/kusion/pkg/apis/api.kusion.io/v1"
+	"kusionstack.io/kusion/pkg/engine/runtime/kubernetes/kubeops"
+	"kusionstack.io/kusion/pkg/engine/runtime/terraform/tfops"
+	"kusionstack.io/kusion/pkg/log"
+)
+
+// ContextSecretRefKeys are the keys of the credentials in the Context of the Spec, whose values can
+// be the ref://name/property refs resolved by the runtimes.
+var ContextSecretRefKeys = []string{
+	kubeops.KubeConfigContentKey,
+	apiv1.EnvAwsAccessKeyID,
+	apiv1.EnvAwsSecretAccessKey,
+	apiv1.EnvAlicloudAccessKey,
+	apiv1.EnvAlicloudSecretKey,
+}
+
+// CollectSecretRefs returns the deduplicated external secret refs which the Spec requires from the
+// SecretStore, so that the existence of the secrets can be verified before applying. The refs are
+// collected only where they are resolved when applying, i.e. the ref://name/property refs in the data
+// of the Kubernetes Secrets and the credentials of the Context, and the ${secret:name.property} refs in
+// the providerMeta of the Terraform resources. The returned refs are sorted.
+func CollectSecretRefs(spec *apiv1.Spec) []apiv1.ExternalSecretRef {
+	if spec == nil {
+		return nil
+	}
+
+	collected := map[apiv1.ExternalSecretRef]struct{}{}
+	collectRef := func(value string) {
+		if !strings.HasPrefix(value, SecretRefPrefix) {
+			return
+		}
+		ref, err := ParseExternalSecretDataRef(value)
+		if err != nil {
+			log.Warnf("skip invalid secret ref %s: %v", value, err)
+			return
+		}
+		collected[*ref] = struct{}{}
+	}
+	collectProviderRefs := func(value string) {
+		for _, ref := range tfops.FindProviderSecretRefs(value) {
+			collected[tfops.ParseProviderSecretRef(ref)] = struct{}{}
+		}
+	}
+
+	for _, res := range spec.Resources {
+		switch res.Type {
+		case apiv1.Kubernetes:
+			if kind, _ := res.Attributes["kind"].(string); kind != "Secret" {
+				continue
+			}
+			// the values of the Kubernetes Secret data are base64 encoded
+			data, _ := res.Attributes["data"].(map[string]interface{})
+			for _, v := range data {
+				encoded, ok := v.(string)
+				if !ok {
+					continue
+				}
+				if decoded, err := base64.StdEncoding.DecodeString(encoded); err == nil {
+					collectRef(string(decoded))
+				}
+			}
+		case apiv1.Terraform:
+			walkStrings(res.Extensions[tfops.ProviderMetaKey], collectProviderRefs)
+		}
+	}
+	for _, key := range ContextSecretRefKeys {
+		if value, ok := spec.Context[key].(string); ok {
+			collectRef(value)
+		}
+	}
+
+	refs := make([]apiv1.ExternalSecretRef, 0, len(collected))
+	for ref := range collected {
+		refs = append(refs, ref)
+	}
+	sort.Slice(refs, func(i, j int) bool {
+		if refs[i].Name != refs[j].Name {
+			return refs[i].Name < refs[j].Name
+		}
+		if refs[i].Property != refs[j].Property {
+			return refs[i].Property < refs[j].Property
+		}
+		return refs[i].Version < refs[j].Version
+	})
+	return refs
+}
+
+// walkStrings calls the fn with each string value nested in the value.
+func walkStrings(value interface{}, fn func(string)) {
+	switch v := value.(type) {
+	case string:
+		fn(v)
+	case map[string]interface{}:
+		for _, item := range v {
+			walkStrings(item, fn)
+		}
+	case apiv1.GenericConfig:
+		walkStrings(map[string]interface{}(v), fn)
+	case []interface{}:
+		for _, item := range v {
+			walkStrings(item, fn)
+		}
+	case []string:
+		for _, item := range v {
+			fn(item)
+		}
+	}
+}
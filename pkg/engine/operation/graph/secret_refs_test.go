@@ -0,0 +1,84 @@
+package graph
+
+import (
+	"encoding/base64"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	apiv1 "kusionstack.io/kusion/pkg/apis/api.kusion.io/v1"
+)
+
+func TestCollectSecretRefs(t *testing.T) {
+	encode := func(s string) string {
+		return base64.StdEncoding.EncodeToString([]byte(s))
+	}
+	spec := &apiv1.Spec{
+		Resources: apiv1.Resources{
+			{
+				ID:   "v1:Secret:default:db",
+				Type: apiv1.Kubernetes,
+				Attributes: map[string]interface{}{
+					"apiVersion": "v1",
+					"kind":       "Secret",
+					"data": map[string]interface{}{
+						"username": encode("ref://db-credentials/username"),
+						"password": encode("ref://db-credentials/password?version=2"),
+						"plain":    encode("plain-value"),
+					},
+					// the refs out of the data are not resolved
+					"stringData": map[string]interface{}{
+						"token": "ref://api-token",
+					},
+					"metadata": map[string]interface{}{
+						"name":   "db",
+						"labels": map[string]interface{}{"source": "ref://labels"},
+					},
+				},
+			},
+			{
+				ID:   "v1:Secret:default:db-copy",
+				Type: apiv1.Kubernetes,
+				Attributes: map[string]interface{}{
+					"apiVersion": "v1",
+					"kind":       "Secret",
+					"data": map[string]interface{}{
+						// duplicated with the above
+						"username": encode("ref://db-credentials/username"),
+					},
+				},
+			},
+			{
+				ID:         "hashicorp:aws:aws_s3_bucket:bucket",
+				Type:       apiv1.Terraform,
+				Attributes: map[string]interface{}{"bucket": "${secret:bucket.name}"},
+				Extensions: map[string]interface{}{
+					"kusion.io/source": "ref://extensions",
+					"providerMeta": map[string]interface{}{
+						"region":    "us-east-1",
+						"accessKey": "${secret:aws-credentials.accessKey}",
+						"secretKey": "${secret:aws-credentials.secretKey}",
+					},
+				},
+			},
+		},
+		Context: apiv1.GenericConfig{
+			"KUBECONFIG_CONTENT":       "ref://kubeconfig",
+			apiv1.EnvAwsAccessKeyID:    "ref://aws-credentials/accessKey",
+			"kubeconfigPath":           "/etc/kubeconfig",
+			"annotation":               "ref://context",
+			apiv1.EnvAlicloudSecretKey: "plain-value",
+		},
+	}
+
+	expected := []apiv1.ExternalSecretRef{
+		{Name: "aws-credentials", Property: "accessKey"},
+		{Name: "aws-credentials", Property: "secretKey"},
+		{Name: "db-credentials", Property: "password", Version: "2"},
+		{Name: "db-credentials", Property: "username"},
+		{Name: "kubeconfig"},
+	}
+	assert.Equal(t, expected, CollectSecretRefs(spec))
+	assert.Nil(t, CollectSecretRefs(nil))
+	assert.Empty(t, CollectSecretRefs(&apiv1.Spec{}))
+}
@@ -0,0 +1,48 @@
+package models
+
+import (
+	"time"
+
+	apiv1 "kusionstack.io/kusion/pkg/apis/api.kusion.io/v1"
+)
+
+// EventType is the type of the Event emitted during an operation.
+type EventType string
+
+// EventType values
+const (
+	ResourceStarted   EventType = "ResourceStarted"
+	ResourceSucceeded EventType = "ResourceSucceeded"
+	ResourceFailed    EventType = "ResourceFailed"
+	PhaseChanged      EventType = "PhaseChanged"
+)
+
+// Event is the progress of an operation, which is emitted to the EventCh of the Operation as the
+// operation proceeds.
+type Event struct {
+	// Type is the type of the event.
+	Type EventType `json:"type" yaml:"type"`
+	// ResourceID is the id of the resource, which is empty for PhaseChanged.
+	ResourceID string `json:"resourceID,omitempty" yaml:"resourceID,omitempty"`
+	// Phase is the phase of the release changed to, which is only set for PhaseChanged.
+	Phase apiv1.ReleasePhase `json:"phase,omitempty" yaml:"phase,omitempty"`
+	// Timestamp is the time when the event happened.
+	Timestamp time.Time `json:"timestamp" yaml:"timestamp"`
+	// Duration is the execution duration of the resource, which is only set for ResourceSucceeded and
+	// ResourceFailed.
+	Duration time.Duration `json:"duration,omitempty" yaml:"duration,omitempty"`
+	// Err is the error of the failed resource, which is only set for ResourceFailed.
+	Err error `json:"-" yaml:"-"`
+}
+
+// SendEvent sends the event to the EventCh, and does nothing if the EventCh is nil. The Timestamp is
+// set to now if not set. It is safe to be called concurrently by the resources executed in parallel.
+func (o *Operation) SendEvent(e Event) {
+	if o.EventCh == nil {
+		return
+	}
+	if e.Timestamp.IsZero() {
+		e.Timestamp = time.Now()
+	}
+	o.EventCh <- e
+}
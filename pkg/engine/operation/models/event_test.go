@@ -0,0 +1,50 @@
+package models
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOperation_SendEvent(t *testing.T) {
+	t.Run("nil event channel", func(t *testing.T) {
+		op := &Operation{}
+		assert.NotPanics(t, func() {
+			op.SendEvent(Event{Type: ResourceStarted, ResourceID: "mock-id"})
+		})
+	})
+
+	t.Run("set timestamp if not set", func(t *testing.T) {
+		op := &Operation{EventCh: make(chan Event, 2)}
+		fakeTime := time.Date(2024, 5, 10, 16, 48, 0, 0, time.UTC)
+		op.SendEvent(Event{Type: ResourceStarted, ResourceID: "mock-id", Timestamp: fakeTime})
+		op.SendEvent(Event{Type: ResourceSucceeded, ResourceID: "mock-id"})
+
+		assert.Equal(t, fakeTime, (<-op.EventCh).Timestamp)
+		assert.False(t, (<-op.EventCh).Timestamp.IsZero())
+	})
+
+	t.Run("send concurrently", func(t *testing.T) {
+		count := 20
+		op := &Operation{EventCh: make(chan Event, count)}
+		wg := sync.WaitGroup{}
+		for i := 0; i < count; i++ {
+			wg.Add(1)
+			go func(i int) {
+				defer wg.Done()
+				op.SendEvent(Event{Type: ResourceStarted, ResourceID: fmt.Sprintf("mock-id-%d", i)})
+			}(i)
+		}
+		wg.Wait()
+		close(op.EventCh)
+
+		ids := map[string]bool{}
+		for e := range op.EventCh {
+			ids[e.ResourceID] = true
+		}
+		assert.Len(t, ids, count)
+	})
+}
@@ -5,6 +5,7 @@ import (
 	"os"
 	"strconv"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	apiv1 "kusionstack.io/kusion/pkg/apis/api.kusion.io/v1"
@@ -55,6 +56,10 @@ type Operation struct {
 	// Fixme: try to merge the WatchCh with the MsgCh.
 	WatchCh chan string
 
+	// EventCh is used to send the typed progress events, such as the start and finish of each resource,
+	// as the operation proceeds. It is optional, and no event is sent if nil.
+	EventCh chan Event
+
 	// Lock is the operation-wide mutex
 	Lock *sync.Mutex
 
@@ -63,6 +68,16 @@ type Operation struct {
 
 	// Release is the release updated in this operation, and saved in the ReleaseStorage
 	Release *apiv1.Release
+
+	// DryRun means the operation runs against the recording runtimes which perform nothing in the
+	// actual infra, and the release is not saved in the ReleaseStorage
+	DryRun bool
+
+	// failed is set to 1 once a resource failed in this operation, accessed atomically
+	failed int32
+
+	// failedResource is the ID of the first resource failed in this operation
+	failedResource atomic.Value
 }
 
 type Message struct {
@@ -119,6 +134,9 @@ func (o *Operation) UpdateReleaseState() error {
 	o.Release.State.Resources = res
 	o.Release.ModifiedTime = time.Now()
 
+	if o.DryRun {
+		return nil
+	}
 	err := o.ReleaseStorage.Update(o.Release)
 	if err != nil {
 		return fmt.Errorf("udpate release failed, %w", err)
@@ -127,6 +145,29 @@ func (o *Operation) UpdateReleaseState() error {
 	return nil
 }
 
+// MarkFailed marks the operation as failed, so that no more resources will be scheduled.
+func (o *Operation) MarkFailed() {
+	atomic.StoreInt32(&o.failed, 1)
+}
+
+// MarkResourceFailed marks the operation as failed by the resource, where only the first failed resource
+// is recorded.
+func (o *Operation) MarkResourceFailed(id string) {
+	o.failedResource.CompareAndSwap(nil, id)
+	o.MarkFailed()
+}
+
+// FailedResource returns the ID of the first resource failed in this operation, or empty if none.
+func (o *Operation) FailedResource() string {
+	id, _ := o.failedResource.Load().(string)
+	return id
+}
+
+// IsFailed returns whether any resource failed in this operation.
+func (o *Operation) IsFailed() bool {
+	return atomic.LoadInt32(&o.failed) == 1
+}
+
 // Update the operation semaphore with the maximum number of concurrent resource executions.
 func (o *Operation) UpdateSemaphore() error {
 	v := os.Getenv(apiv1.MaxConcurrentEnvVar)
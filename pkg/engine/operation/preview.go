@@ -146,7 +146,10 @@ func validatePreviewRequest(req *PreviewRequest) v1.Status {
 	if req == nil {
 		return v1.NewErrorStatusWithMsg(v1.InvalidArgument, "request is nil")
 	}
-	if err := release.ValidateSpec(req.Spec); err != nil {
+	if err := req.Spec.Validate(); err != nil {
+		return v1.NewErrorStatusWithMsg(v1.InvalidArgument, err.Error())
+	}
+	if err := release.ValidateTerraformProviders(req.Spec.Resources); err != nil {
 		return v1.NewErrorStatusWithMsg(v1.InvalidArgument, err.Error())
 	}
 	return nil
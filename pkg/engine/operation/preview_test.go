@@ -99,11 +99,17 @@ func TestPreviewOperation_Preview(t *testing.T) {
 		ID:         "fake-id",
 		Type:       runtime.Kubernetes,
 		Attributes: fakeService,
+		Extensions: map[string]interface{}{
+			apiv1.ResourceExtensionGVK: "/v1, Kind=Service",
+		},
 	}
 	fakeResource2 := apiv1.Resource{
 		ID:         "fake-id-2",
 		Type:       runtime.Kubernetes,
 		Attributes: fakeService,
+		Extensions: map[string]interface{}{
+			apiv1.ResourceExtensionGVK: "/v1, Kind=Service",
+		},
 	}
 
 	testcases := []struct {
@@ -89,6 +89,9 @@ func TestWatchOperation_Watch(t *testing.T) {
 						ID:         "apps/v1:Deployment:foo:bar",
 						Type:       runtime.Kubernetes,
 						Attributes: barDeployment,
+						Extensions: map[string]interface{}{
+							apiv1.ResourceExtensionGVK: "apps/v1, Kind=Deployment",
+						},
 					},
 				},
 			},
@@ -0,0 +1,151 @@
+package policy
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/google/cel-go/cel"
+	"gopkg.in/yaml.v3"
+
+	v1 "kusionstack.io/kusion/pkg/apis/api.kusion.io/v1"
+)
+
+var ErrInvalidCELRule = errors.New("invalid cel rule")
+
+// CELRule is a validation rule of the resources in CEL, which is lighter-weight than the Rego policies.
+type CELRule struct {
+	// Expression is the CEL expression evaluated per resource, which returns false if the resource is
+	// invalid, e.g. `resource.attributes.spec.replicas <= 10`. The resource is the variable "resource"
+	// with the fields id, type, attributes, dependsOn and extensions.
+	Expression string `yaml:"expression" json:"expression"`
+	// Message is the message reported if the resource is invalid.
+	Message string `yaml:"message" json:"message"`
+}
+
+// LoadCELRules loads the list of the CELRule from the YAML file.
+func LoadCELRules(path string) ([]CELRule, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var rules []CELRule
+	if err = yaml.Unmarshal(data, &rules); err != nil {
+		return nil, fmt.Errorf("%w: failed to parse %s, %v", ErrInvalidCELRule, path, err)
+	}
+	return rules, nil
+}
+
+type compiledCELRule struct {
+	CELRule
+	program cel.Program
+}
+
+var _ Evaluator = (*CELEvaluator)(nil)
+
+// CELEvaluator evaluates the CEL rules against each resource of the Spec.
+type CELEvaluator struct {
+	rules []compiledCELRule
+}
+
+// NewCELEvaluator compiles the CEL rules, and returns ErrInvalidCELRule if any rule fails to compile
+// or doesn't return a bool.
+func NewCELEvaluator(rules []CELRule) (*CELEvaluator, error) {
+	env, err := cel.NewEnv(cel.Variable("resource", cel.MapType(cel.StringType, cel.DynType)))
+	if err != nil {
+		return nil, err
+	}
+
+	compiled := make([]compiledCELRule, 0, len(rules))
+	for i, rule := range rules {
+		if rule.Expression == "" {
+			return nil, fmt.Errorf("%w: rule %d has empty expression", ErrInvalidCELRule, i)
+		}
+		ast, issues := env.Compile(rule.Expression)
+		if issues != nil && issues.Err() != nil {
+			return nil, fmt.Errorf("%w: rule %d %q, %v", ErrInvalidCELRule, i, rule.Expression, issues.Err())
+		}
+		if t := ast.OutputType(); !t.IsExactType(cel.BoolType) && !t.IsExactType(cel.DynType) {
+			return nil, fmt.Errorf("%w: rule %d %q returns %s, expected bool", ErrInvalidCELRule, i, rule.Expression, t)
+		}
+		program, err := env.Program(ast)
+		if err != nil {
+			return nil, fmt.Errorf("%w: rule %d %q, %v", ErrInvalidCELRule, i, rule.Expression, err)
+		}
+		compiled = append(compiled, compiledCELRule{CELRule: rule, program: program})
+	}
+	return &CELEvaluator{rules: compiled}, nil
+}
+
+// Evaluate evaluates the rules against each resource of the Spec, and returns the messages of the
+// rules returning false. A rule referring to a field absent in the resource doesn't apply to it, and
+// has() can be used to check the presence of the field explicitly.
+func (e *CELEvaluator) Evaluate(ctx context.Context, spec *v1.Spec) ([]string, error) {
+	var denies []string
+	for i := range spec.Resources {
+		resource, err := toCELValue(&spec.Resources[i])
+		if err != nil {
+			return nil, err
+		}
+		for _, rule := range e.rules {
+			out, _, err := rule.program.ContextEval(ctx, map[string]interface{}{"resource": resource})
+			if err != nil {
+				if strings.HasPrefix(err.Error(), "no such key") {
+					continue
+				}
+				return nil, fmt.Errorf("failed to evaluate rule %q on resource %s: %w", rule.Expression, spec.Resources[i].ID, err)
+			}
+			valid, ok := out.Value().(bool)
+			if !ok {
+				return nil, fmt.Errorf("rule %q returns %v on resource %s, expected bool", rule.Expression, out.Value(), spec.Resources[i].ID)
+			}
+			if !valid {
+				denies = append(denies, fmt.Sprintf("resource %s: %s", spec.Resources[i].ID, rule.Message))
+			}
+		}
+	}
+	return denies, nil
+}
+
+// toCELValue converts the resource to the JSON value, where the integers are int64 instead of float64,
+// so that they are compared with the integer literals in CEL.
+func toCELValue(resource *v1.Resource) (map[string]interface{}, error) {
+	data, err := json.Marshal(resource)
+	if err != nil {
+		return nil, err
+	}
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	var value map[string]interface{}
+	if err = decoder.Decode(&value); err != nil {
+		return nil, err
+	}
+	return convertNumbers(value).(map[string]interface{}), nil
+}
+
+func convertNumbers(v interface{}) interface{} {
+	switch t := v.(type) {
+	case map[string]interface{}:
+		for k, e := range t {
+			t[k] = convertNumbers(e)
+		}
+		return t
+	case []interface{}:
+		for i, e := range t {
+			t[i] = convertNumbers(e)
+		}
+		return t
+	case json.Number:
+		if i, err := t.Int64(); err == nil {
+			return i
+		}
+		f, _ := t.Float64()
+		return f
+	default:
+		return v
+	}
+}
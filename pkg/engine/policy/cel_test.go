@@ -0,0 +1,129 @@
+package policy
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	v1 "kusionstack.io/kusion/pkg/apis/api.kusion.io/v1"
+)
+
+func newReplicasSpec(replicas int) *v1.Spec {
+	return &v1.Spec{
+		Resources: v1.Resources{
+			{
+				ID:   "v1:Namespace:foo",
+				Type: v1.Kubernetes,
+				Attributes: map[string]interface{}{
+					"apiVersion": "v1",
+					"kind":       "Namespace",
+					"metadata":   map[string]interface{}{"name": "foo"},
+				},
+			},
+			{
+				ID:   "apps/v1:Deployment:foo:bar",
+				Type: v1.Kubernetes,
+				Attributes: map[string]interface{}{
+					"apiVersion": "apps/v1",
+					"kind":       "Deployment",
+					"metadata":   map[string]interface{}{"name": "bar", "namespace": "foo"},
+					"spec":       map[string]interface{}{"replicas": replicas},
+				},
+			},
+		},
+	}
+}
+
+func TestCELEvaluator(t *testing.T) {
+	rules := []CELRule{
+		{
+			Expression: "resource.attributes.spec.replicas <= 10",
+			Message:    "replicas must not exceed 10",
+		},
+		{
+			Expression: `resource.type != "Kubernetes" || has(resource.attributes.metadata.name)`,
+			Message:    "kubernetes resource must have a name",
+		},
+	}
+
+	testcases := []struct {
+		name     string
+		replicas int
+		denies   []string
+	}{
+		{
+			name:     "passing expressions",
+			replicas: 3,
+		},
+		{
+			name:     "failing expression",
+			replicas: 20,
+			denies:   []string{"resource apps/v1:Deployment:foo:bar: replicas must not exceed 10"},
+		},
+	}
+
+	evaluator, err := NewCELEvaluator(rules)
+	require.NoError(t, err)
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			denies, err := evaluator.Evaluate(context.Background(), newReplicasSpec(tc.replicas))
+			require.NoError(t, err)
+			assert.Equal(t, tc.denies, denies)
+
+			err = Check(context.Background(), evaluator, newReplicasSpec(tc.replicas))
+			assert.Equal(t, len(tc.denies) > 0, errors.Is(err, ErrPolicyDenied))
+		})
+	}
+}
+
+func TestNewCELEvaluator_InvalidRule(t *testing.T) {
+	testcases := []struct {
+		name string
+		rule CELRule
+	}{
+		{
+			name: "malformed expression",
+			rule: CELRule{Expression: "resource.attributes.spec.replicas <=", Message: "malformed"},
+		},
+		{
+			name: "undeclared variable",
+			rule: CELRule{Expression: "deployment.spec.replicas <= 10", Message: "undeclared"},
+		},
+		{
+			name: "not bool",
+			rule: CELRule{Expression: "resource.id + 'foo'", Message: "not bool"},
+		},
+		{
+			name: "empty expression",
+			rule: CELRule{Message: "empty"},
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := NewCELEvaluator([]CELRule{tc.rule})
+			assert.True(t, errors.Is(err, ErrInvalidCELRule), "unexpected error: %v", err)
+		})
+	}
+}
+
+func TestLoadCELRules(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "rules.yaml")
+	require.NoError(t, os.WriteFile(path, []byte(`
+- expression: resource.attributes.spec.replicas <= 10
+  message: replicas must not exceed 10
+`), 0o600))
+
+	rules, err := LoadCELRules(path)
+	require.NoError(t, err)
+	assert.Equal(t, []CELRule{{Expression: "resource.attributes.spec.replicas <= 10", Message: "replicas must not exceed 10"}}, rules)
+
+	require.NoError(t, os.WriteFile(path, []byte("expression: foo"), 0o600))
+	_, err = LoadCELRules(path)
+	assert.True(t, errors.Is(err, ErrInvalidCELRule))
+}
@@ -0,0 +1,119 @@
+// Package policy evaluates the Spec against the organization policies written in Rego, so that the
+// Spec violating any policy is rejected before apply.
+package policy
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/open-policy-agent/opa/rego"
+
+	v1 "kusionstack.io/kusion/pkg/apis/api.kusion.io/v1"
+)
+
+// DefaultQuery is the default query of the deny messages, which are the values of the "deny" rule in
+// the "kusion" package of the policies.
+const DefaultQuery = "data.kusion.deny"
+
+var ErrPolicyDenied = errors.New("spec denied by policy")
+
+// Evaluator evaluates the Spec against the policies, and returns the deny messages.
+type Evaluator interface {
+	Evaluate(ctx context.Context, spec *v1.Spec) ([]string, error)
+}
+
+// Check evaluates the Spec with the evaluator, and returns ErrPolicyDenied along with all the deny
+// messages if any policy denies.
+func Check(ctx context.Context, evaluator Evaluator, spec *v1.Spec) error {
+	denies, err := evaluator.Evaluate(ctx, spec)
+	if err != nil {
+		return fmt.Errorf("failed to evaluate policies: %w", err)
+	}
+	if len(denies) == 0 {
+		return nil
+	}
+	return fmt.Errorf("%w, %d violation(s):\n  %s", ErrPolicyDenied, len(denies), strings.Join(denies, "\n  "))
+}
+
+var _ Evaluator = (*OPAEvaluator)(nil)
+
+// OPAEvaluator evaluates the Rego policies in a directory with the embedded OPA, where the policies and
+// the data files in JSON or YAML are loaded recursively. The Spec is the input of the policies, e.g. the
+// resources are input.resources, and the deny messages are the results of the query.
+type OPAEvaluator struct {
+	query rego.PreparedEvalQuery
+}
+
+// NewOPAEvaluator returns the OPAEvaluator of the policies in dir, which queries the deny messages
+// with query, defaults to DefaultQuery. The policies are compiled in advance, so that the invalid ones
+// are reported here.
+func NewOPAEvaluator(dir, query string) (*OPAEvaluator, error) {
+	info, err := os.Stat(dir)
+	if err != nil {
+		return nil, fmt.Errorf("invalid policy directory: %w", err)
+	}
+	if !info.IsDir() {
+		return nil, fmt.Errorf("invalid policy directory: %s is not a directory", dir)
+	}
+	if query == "" {
+		query = DefaultQuery
+	}
+	prepared, err := rego.New(
+		rego.Query(query),
+		rego.Load([]string{dir}, nil),
+	).PrepareForEval(context.Background())
+	if err != nil {
+		return nil, fmt.Errorf("failed to load policies in %s: %w", dir, err)
+	}
+	return &OPAEvaluator{query: prepared}, nil
+}
+
+// Evaluate evaluates the Spec against the policies, and returns the deny messages. An undefined query,
+// e.g. no policy has the deny rule, denies nothing.
+func (e *OPAEvaluator) Evaluate(ctx context.Context, spec *v1.Spec) ([]string, error) {
+	// convert the Spec into the generic JSON value, so that the fields are named by the json tags
+	content, err := json.Marshal(spec)
+	if err != nil {
+		return nil, err
+	}
+	var input interface{}
+	if err = json.Unmarshal(content, &input); err != nil {
+		return nil, err
+	}
+
+	results, err := e.query.Eval(ctx, rego.EvalInput(input))
+	if err != nil {
+		return nil, fmt.Errorf("opa eval: %w", err)
+	}
+	return parseDenies(results)
+}
+
+// parseDenies parses the deny messages in the results of the query, where the value of the query is
+// a set or an array of messages, and the messages not in string are formatted in JSON.
+func parseDenies(results rego.ResultSet) ([]string, error) {
+	var denies []string
+	for _, result := range results {
+		for _, expr := range result.Expressions {
+			values, ok := expr.Value.([]interface{})
+			if !ok {
+				return nil, fmt.Errorf("invalid deny value %v, expected a set or an array of messages", expr.Value)
+			}
+			for _, v := range values {
+				if msg, ok := v.(string); ok {
+					denies = append(denies, msg)
+					continue
+				}
+				msg, err := json.Marshal(v)
+				if err != nil {
+					return nil, err
+				}
+				denies = append(denies, string(msg))
+			}
+		}
+	}
+	return denies, nil
+}
@@ -0,0 +1,158 @@
+package policy
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/open-policy-agent/opa/rego"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	v1 "kusionstack.io/kusion/pkg/apis/api.kusion.io/v1"
+)
+
+var sampleSpec = &v1.Spec{
+	Resources: v1.Resources{
+		{
+			ID:   "apps/v1:Deployment:foo:bar",
+			Type: v1.Kubernetes,
+			Attributes: map[string]interface{}{
+				"apiVersion": "apps/v1",
+				"kind":       "Deployment",
+				"metadata":   map[string]interface{}{"name": "bar", "namespace": "foo"},
+				"spec": map[string]interface{}{
+					"template": map[string]interface{}{
+						"spec": map[string]interface{}{
+							"containers": []interface{}{
+								map[string]interface{}{"name": "bar", "image": "docker.io/library/nginx:1.25"},
+							},
+						},
+					},
+				},
+			},
+		},
+	},
+}
+
+func TestOPAEvaluator(t *testing.T) {
+	testcases := []struct {
+		name   string
+		dir    string
+		denies []string
+	}{
+		{
+			name: "allow policy",
+			dir:  "testdata/allow",
+		},
+		{
+			name: "deny policy",
+			dir:  "testdata/deny",
+			denies: []string{
+				"container bar of resource apps/v1:Deployment:foo:bar uses image docker.io/library/nginx:1.25 out of the allowed registries",
+			},
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			evaluator, err := NewOPAEvaluator(tc.dir, "")
+			require.NoError(t, err)
+			denies, err := evaluator.Evaluate(context.Background(), sampleSpec)
+			require.NoError(t, err)
+			assert.Equal(t, tc.denies, denies)
+
+			err = Check(context.Background(), evaluator, sampleSpec)
+			assert.Equal(t, len(tc.denies) > 0, errors.Is(err, ErrPolicyDenied))
+		})
+	}
+}
+
+func TestNewOPAEvaluator_InvalidDir(t *testing.T) {
+	_, err := NewOPAEvaluator("testdata/not-exist", "")
+	assert.Error(t, err)
+	_, err = NewOPAEvaluator("testdata/allow/policy.rego", "")
+	assert.Error(t, err)
+}
+
+func TestNewOPAEvaluator_InvalidPolicy(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "policy.rego"), []byte("package kusion\n\ndeny contains"), 0o600))
+	_, err := NewOPAEvaluator(dir, "")
+	assert.Error(t, err)
+}
+
+func TestOPAEvaluator_UndefinedQuery(t *testing.T) {
+	evaluator, err := NewOPAEvaluator("testdata/allow", "data.kusion.undefined")
+	require.NoError(t, err)
+	denies, err := evaluator.Evaluate(context.Background(), sampleSpec)
+	require.NoError(t, err)
+	assert.Empty(t, denies)
+}
+
+func TestParseDenies(t *testing.T) {
+	resultOf := func(value interface{}) rego.ResultSet {
+		return rego.ResultSet{{Expressions: []*rego.ExpressionValue{{Value: value, Text: DefaultQuery}}}}
+	}
+
+	testcases := []struct {
+		name    string
+		results rego.ResultSet
+		denies  []string
+		success bool
+	}{
+		{
+			name:    "undefined query",
+			results: rego.ResultSet{},
+			success: true,
+		},
+		{
+			name:    "empty set",
+			results: resultOf([]interface{}{}),
+			success: true,
+		},
+		{
+			name:    "messages",
+			results: resultOf([]interface{}{"foo denied", map[string]interface{}{"msg": "bar denied"}}),
+			denies:  []string{"foo denied", `{"msg":"bar denied"}`},
+			success: true,
+		},
+		{
+			name:    "not a set",
+			results: resultOf(true),
+			success: false,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			denies, err := parseDenies(tc.results)
+			assert.Equal(t, tc.success, err == nil)
+			assert.Equal(t, tc.denies, denies)
+		})
+	}
+}
+
+type fakeEvaluator struct {
+	denies []string
+	err    error
+}
+
+func (e *fakeEvaluator) Evaluate(context.Context, *v1.Spec) ([]string, error) {
+	return e.denies, e.err
+}
+
+func TestCheck(t *testing.T) {
+	err := Check(context.Background(), &fakeEvaluator{}, sampleSpec)
+	assert.NoError(t, err)
+
+	err = Check(context.Background(), &fakeEvaluator{denies: []string{"foo denied", "bar denied"}}, sampleSpec)
+	assert.True(t, errors.Is(err, ErrPolicyDenied))
+	assert.Equal(t, "spec denied by policy, 2 violation(s):\n  foo denied\n  bar denied", err.Error())
+
+	err = Check(context.Background(), &fakeEvaluator{err: errors.New("opa not found")}, sampleSpec)
+	assert.Error(t, err)
+	assert.False(t, errors.Is(err, ErrPolicyDenied))
+}
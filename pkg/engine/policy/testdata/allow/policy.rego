@@ -0,0 +1,12 @@
+package kusion
+
+import rego.v1
+
+# deny the workloads out of the allowed namespaces, which never fires on the sample spec
+deny contains msg if {
+	some resource in input.resources
+	resource.type == "Kubernetes"
+	namespace := resource.attributes.metadata.namespace
+	not namespace in data.allowed_namespaces
+	msg := sprintf("resource %s is in namespace %s out of the allowed namespaces", [resource.id, namespace])
+}
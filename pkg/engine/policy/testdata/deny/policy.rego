@@ -0,0 +1,17 @@
+package kusion
+
+import rego.v1
+
+# deny the container images out of the allowed registries
+deny contains msg if {
+	some resource in input.resources
+	resource.type == "Kubernetes"
+	some container in resource.attributes.spec.template.spec.containers
+	not allowed_image(container.image)
+	msg := sprintf("container %s of resource %s uses image %s out of the allowed registries", [container.name, resource.id, container.image])
+}
+
+allowed_image(image) if {
+	some registry in data.allowed_registries
+	startswith(image, registry)
+}
@@ -0,0 +1,65 @@
+package release
+
+import (
+	"fmt"
+	"time"
+
+	v1 "kusionstack.io/kusion/pkg/apis/api.kusion.io/v1"
+)
+
+// ReleaseSummary is the summary of a Release in the ReleaseDiff.
+type ReleaseSummary struct {
+	Revision     uint64
+	Phase        v1.ReleasePhase
+	CreateTime   time.Time
+	ModifiedTime time.Time
+}
+
+// ReleaseDiff is the difference between two Releases of the same project and workspace, which tells what
+// changed between the deploys.
+type ReleaseDiff struct {
+	// From is the summary of the old Release.
+	From ReleaseSummary
+	// To is the summary of the new Release.
+	To ReleaseSummary
+	// Elapsed is the time elapsed from the creation of the old Release to the new one.
+	Elapsed time.Duration
+	// Resources is the difference of the resources in the Spec of the Releases.
+	Resources *v1.ResourcesDiff
+}
+
+// DiffReleases returns the difference from the Release of revision from to the one of revision to, which
+// returns the not found error if any of them doesn't exist.
+func DiffReleases(storage Storage, from, to uint64) (*ReleaseDiff, error) {
+	fromRelease, err := storage.Get(from)
+	if err != nil {
+		return nil, fmt.Errorf("get release of revision %d failed: %w", from, err)
+	}
+	toRelease, err := storage.Get(to)
+	if err != nil {
+		return nil, fmt.Errorf("get release of revision %d failed: %w", to, err)
+	}
+
+	return &ReleaseDiff{
+		From:      summarizeRelease(fromRelease),
+		To:        summarizeRelease(toRelease),
+		Elapsed:   toRelease.CreateTime.Sub(fromRelease.CreateTime),
+		Resources: specResources(fromRelease).Diff(specResources(toRelease)),
+	}, nil
+}
+
+func summarizeRelease(r *v1.Release) ReleaseSummary {
+	return ReleaseSummary{
+		Revision:     r.Revision,
+		Phase:        r.Phase,
+		CreateTime:   r.CreateTime,
+		ModifiedTime: r.ModifiedTime,
+	}
+}
+
+func specResources(r *v1.Release) v1.Resources {
+	if r.Spec == nil {
+		return nil
+	}
+	return r.Spec.Resources
+}
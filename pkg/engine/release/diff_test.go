@@ -0,0 +1,60 @@
+package release
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	v1 "kusionstack.io/kusion/pkg/apis/api.kusion.io/v1"
+	"kusionstack.io/kusion/pkg/engine/release/storages"
+	"kusionstack.io/kusion/pkg/util/kerrors"
+)
+
+func TestDiffReleases(t *testing.T) {
+	s, err := storages.NewLocalStorage(t.TempDir())
+	require.NoError(t, err)
+
+	createTime := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+	r1 := mockSecretRelease(1)
+	r1.CreateTime, r1.ModifiedTime = createTime, createTime.Add(time.Minute)
+	require.NoError(t, s.Create(r1))
+
+	r2 := mockSecretRelease(2)
+	r2.CreateTime, r2.ModifiedTime = createTime.Add(time.Hour), createTime.Add(time.Hour+time.Minute)
+	r2.Phase = v1.ReleasePhaseFailed
+	r2.Spec.Resources[0].Attributes["stringData"] = map[string]interface{}{"password": "changed-password"}
+	require.NoError(t, s.Create(r2))
+
+	diff, err := DiffReleases(s, 1, 2)
+	require.NoError(t, err)
+	assert.Equal(t, ReleaseSummary{
+		Revision:     1,
+		Phase:        v1.ReleasePhaseSucceeded,
+		CreateTime:   r1.CreateTime,
+		ModifiedTime: r1.ModifiedTime,
+	}, diff.From)
+	assert.Equal(t, ReleaseSummary{
+		Revision:     2,
+		Phase:        v1.ReleasePhaseFailed,
+		CreateTime:   r2.CreateTime,
+		ModifiedTime: r2.ModifiedTime,
+	}, diff.To)
+	assert.Equal(t, time.Hour, diff.Elapsed)
+	assert.Empty(t, diff.Resources.Added)
+	assert.Empty(t, diff.Resources.Removed)
+	require.Len(t, diff.Resources.Changed, 1)
+	assert.Equal(t, "v1:Secret:default:db-password", diff.Resources.Changed[0].ID)
+
+	// no difference with itself
+	diff, err = DiffReleases(s, 2, 2)
+	require.NoError(t, err)
+	assert.True(t, diff.Resources.Empty())
+
+	// missing revision
+	_, err = DiffReleases(s, 1, 3)
+	require.Error(t, err)
+	assert.True(t, kerrors.IsNotFound(err))
+	assert.Contains(t, err.Error(), "revision 3")
+}
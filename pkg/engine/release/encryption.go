@@ -0,0 +1,94 @@
+package release
+
+import (
+	"context"
+	"fmt"
+
+	"gopkg.in/yaml.v3"
+
+	v1 "kusionstack.io/kusion/pkg/apis/api.kusion.io/v1"
+	"kusionstack.io/kusion/pkg/secrets/keyprovider"
+)
+
+// EncryptedStorage is a Storage which encrypts the Spec and State of the Releases before writing to the
+// wrapped Storage, and decrypts them after reading. The other fields of the Release are kept in plaintext,
+// so that the wrapped Storage is still able to maintain the metadata.
+type EncryptedStorage struct {
+	Storage
+
+	provider keyprovider.KeyProvider
+}
+
+// encryptedContent is the content of a Release to encrypt.
+type encryptedContent struct {
+	Spec  *v1.Spec  `yaml:"spec,omitempty"`
+	State *v1.State `yaml:"state,omitempty"`
+}
+
+// NewEncryptedStorage wraps the storage with encryption at rest by the key provider.
+func NewEncryptedStorage(storage Storage, provider keyprovider.KeyProvider) *EncryptedStorage {
+	return &EncryptedStorage{
+		Storage:  storage,
+		provider: provider,
+	}
+}
+
+func (s *EncryptedStorage) Get(revision uint64) (*v1.Release, error) {
+	r, err := s.Storage.Get(revision)
+	if err != nil {
+		return nil, err
+	}
+	if r.Encryption == nil {
+		// the release persisted before enabling encryption
+		return r, nil
+	}
+
+	plaintext, err := keyprovider.Open(context.TODO(), s.provider, r.Encryption)
+	if err != nil {
+		return nil, fmt.Errorf("decrypt release %d failed: %w", revision, err)
+	}
+	content := &encryptedContent{}
+	if err = yaml.Unmarshal(plaintext, content); err != nil {
+		return nil, fmt.Errorf("yaml unmarshal decrypted release %d failed: %w", revision, err)
+	}
+
+	decrypted := *r
+	decrypted.Spec = content.Spec
+	decrypted.State = content.State
+	decrypted.Encryption = nil
+	return &decrypted, nil
+}
+
+func (s *EncryptedStorage) Create(r *v1.Release) error {
+	encrypted, err := s.encrypt(r)
+	if err != nil {
+		return err
+	}
+	return s.Storage.Create(encrypted)
+}
+
+func (s *EncryptedStorage) Update(r *v1.Release) error {
+	encrypted, err := s.encrypt(r)
+	if err != nil {
+		return err
+	}
+	return s.Storage.Update(encrypted)
+}
+
+// encrypt returns a copy of the release whose Spec and State are replaced by the encrypted envelope.
+func (s *EncryptedStorage) encrypt(r *v1.Release) (*v1.Release, error) {
+	plaintext, err := yaml.Marshal(&encryptedContent{Spec: r.Spec, State: r.State})
+	if err != nil {
+		return nil, fmt.Errorf("yaml marshal release %d failed: %w", r.Revision, err)
+	}
+	data, err := keyprovider.Seal(context.TODO(), s.provider, plaintext)
+	if err != nil {
+		return nil, fmt.Errorf("encrypt release %d failed: %w", r.Revision, err)
+	}
+
+	encrypted := *r
+	encrypted.Spec = nil
+	encrypted.State = nil
+	encrypted.Encryption = data
+	return &encrypted, nil
+}
@@ -0,0 +1,82 @@
+package release
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	v1 "kusionstack.io/kusion/pkg/apis/api.kusion.io/v1"
+	"kusionstack.io/kusion/pkg/engine/release/storages"
+	"kusionstack.io/kusion/pkg/secrets/keyprovider"
+)
+
+func mockEncryptionKey(b byte) []byte {
+	key := make([]byte, 32)
+	for i := range key {
+		key[i] = b
+	}
+	return key
+}
+
+func mockSecretRelease(revision uint64) *v1.Release {
+	resources := v1.Resources{
+		{
+			ID:   "v1:Secret:default:db-password",
+			Type: v1.Kubernetes,
+			Attributes: map[string]interface{}{
+				"apiVersion": "v1",
+				"kind":       "Secret",
+				"stringData": map[string]interface{}{
+					"password": "plaintext-password",
+				},
+			},
+		},
+	}
+	return &v1.Release{
+		Project:   "test_project",
+		Workspace: "test_ws",
+		Revision:  revision,
+		Stack:     "test_stack",
+		Spec:      &v1.Spec{Resources: resources, Context: v1.GenericConfig{}},
+		State:     &v1.State{Resources: resources},
+		Phase:     v1.ReleasePhaseSucceeded,
+	}
+}
+
+func TestEncryptedStorage(t *testing.T) {
+	dir := t.TempDir()
+	localStorage, err := storages.NewLocalStorage(dir)
+	require.NoError(t, err)
+	provider, err := keyprovider.NewLocalProvider("key-1", map[string][]byte{"key-1": mockEncryptionKey(1)})
+	require.NoError(t, err)
+	s := NewEncryptedStorage(localStorage, provider)
+
+	r := mockSecretRelease(1)
+	require.NoError(t, s.Create(r))
+	r.Phase = v1.ReleasePhaseApplying
+	require.NoError(t, s.Update(r))
+
+	// the release is encrypted on disk
+	content, err := os.ReadFile(filepath.Join(dir, "1.yaml"))
+	require.NoError(t, err)
+	assert.NotContains(t, string(content), "plaintext-password")
+	assert.Contains(t, string(content), "keyID: key-1")
+
+	// the release is decrypted when reading
+	got, err := s.Get(1)
+	require.NoError(t, err)
+	assert.Nil(t, got.Encryption)
+	assert.Equal(t, v1.ReleasePhaseApplying, got.Phase)
+	assert.Equal(t, r.Spec, got.Spec)
+	assert.Equal(t, r.State, got.State)
+	assert.Equal(t, []uint64{1}, s.GetStackBoundRevisions("test_stack"))
+
+	// the release fails to decrypt with a wrong key
+	wrongProvider, err := keyprovider.NewLocalProvider("key-1", map[string][]byte{"key-1": mockEncryptionKey(2)})
+	require.NoError(t, err)
+	_, err = NewEncryptedStorage(localStorage, wrongProvider).Get(1)
+	assert.ErrorIs(t, err, keyprovider.ErrDecryptFailed)
+}
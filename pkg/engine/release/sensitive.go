@@ -0,0 +1,210 @@
+package release
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	v1 "kusionstack.io/kusion/pkg/apis/api.kusion.io/v1"
+	"kusionstack.io/kusion/pkg/secrets/keyprovider"
+)
+
+// EncryptedValuePrefix is the marker of the encrypted sensitive attribute value, whose format is
+// "kusion-encrypted:<keyID>:<encryptedKey>:<ciphertext>". The encrypted key and ciphertext are base64
+// encoded, so that the key id is allowed to contain colons, e.g. the ARN of an AWS KMS key.
+const EncryptedValuePrefix = "kusion-encrypted:"
+
+// sensitiveSecretFields are the attributes of the Kubernetes Secret which contain sensitive values.
+var sensitiveSecretFields = []string{"data", "stringData"}
+
+// SensitiveEncryptedStorage is a Storage which encrypts the sensitive attribute values of the resources
+// in the Spec and State before writing to the wrapped Storage, and decrypts them after reading. The
+// sensitive attribute values are the data and stringData of the Kubernetes Secrets, which are also
+// masked when showing the diff.
+type SensitiveEncryptedStorage struct {
+	Storage
+
+	provider keyprovider.KeyProvider
+}
+
+// NewSensitiveEncryptedStorage wraps the storage with the sensitive attribute values encryption by the
+// key provider.
+func NewSensitiveEncryptedStorage(storage Storage, provider keyprovider.KeyProvider) *SensitiveEncryptedStorage {
+	return &SensitiveEncryptedStorage{
+		Storage:  storage,
+		provider: provider,
+	}
+}
+
+func (s *SensitiveEncryptedStorage) Get(revision uint64) (*v1.Release, error) {
+	r, err := s.Storage.Get(revision)
+	if err != nil {
+		return nil, err
+	}
+
+	decrypted := *r
+	if r.Spec != nil {
+		spec := *r.Spec
+		if spec.Resources, err = DecryptSensitiveAttributes(context.TODO(), s.provider, r.Spec.Resources); err != nil {
+			return nil, fmt.Errorf("decrypt spec of release %d failed: %w", revision, err)
+		}
+		decrypted.Spec = &spec
+	}
+	if r.State != nil {
+		state := *r.State
+		if state.Resources, err = DecryptSensitiveAttributes(context.TODO(), s.provider, r.State.Resources); err != nil {
+			return nil, fmt.Errorf("decrypt state of release %d failed: %w", revision, err)
+		}
+		decrypted.State = &state
+	}
+	return &decrypted, nil
+}
+
+func (s *SensitiveEncryptedStorage) Create(r *v1.Release) error {
+	encrypted, err := s.encrypt(r)
+	if err != nil {
+		return err
+	}
+	return s.Storage.Create(encrypted)
+}
+
+func (s *SensitiveEncryptedStorage) Update(r *v1.Release) error {
+	encrypted, err := s.encrypt(r)
+	if err != nil {
+		return err
+	}
+	return s.Storage.Update(encrypted)
+}
+
+// encrypt returns a copy of the release whose sensitive attribute values are encrypted.
+func (s *SensitiveEncryptedStorage) encrypt(r *v1.Release) (*v1.Release, error) {
+	var err error
+	encrypted := *r
+	if r.Spec != nil {
+		spec := *r.Spec
+		if spec.Resources, err = EncryptSensitiveAttributes(context.TODO(), s.provider, r.Spec.Resources); err != nil {
+			return nil, fmt.Errorf("encrypt spec of release %d failed: %w", r.Revision, err)
+		}
+		encrypted.Spec = &spec
+	}
+	if r.State != nil {
+		state := *r.State
+		if state.Resources, err = EncryptSensitiveAttributes(context.TODO(), s.provider, r.State.Resources); err != nil {
+			return nil, fmt.Errorf("encrypt state of release %d failed: %w", r.Revision, err)
+		}
+		encrypted.State = &state
+	}
+	return &encrypted, nil
+}
+
+// EncryptSensitiveAttributes returns a copy of the resources whose sensitive attribute values are
+// encrypted by the key provider. All the values are taken as plaintext and encrypted, including the
+// ones starting with EncryptedValuePrefix, so that DecryptSensitiveAttributes always restores them.
+func EncryptSensitiveAttributes(ctx context.Context, provider keyprovider.KeyProvider, resources v1.Resources) (v1.Resources, error) {
+	return transformSensitiveAttributes(resources, func(value string) (string, error) {
+		data, err := keyprovider.Seal(ctx, provider, []byte(value))
+		if err != nil {
+			return "", err
+		}
+		return fmt.Sprintf("%s%s:%s:%s", EncryptedValuePrefix, data.KeyID, data.EncryptedKey, data.Ciphertext), nil
+	})
+}
+
+// DecryptSensitiveAttributes returns a copy of the resources whose sensitive attribute values encrypted
+// by EncryptSensitiveAttributes are decrypted by the key provider.
+func DecryptSensitiveAttributes(ctx context.Context, provider keyprovider.KeyProvider, resources v1.Resources) (v1.Resources, error) {
+	return transformSensitiveAttributes(resources, func(value string) (string, error) {
+		encoded, ok := strings.CutPrefix(value, EncryptedValuePrefix)
+		if !ok {
+			return value, nil
+		}
+		// split from the right, as the key id may contain colons
+		parts := strings.Split(encoded, ":")
+		if len(parts) < 3 {
+			return "", fmt.Errorf("%w, invalid format of the encrypted value", keyprovider.ErrDecryptFailed)
+		}
+		data := &v1.EncryptedData{
+			KeyID:        strings.Join(parts[:len(parts)-2], ":"),
+			EncryptedKey: parts[len(parts)-2],
+			Ciphertext:   parts[len(parts)-1],
+		}
+		plaintext, err := keyprovider.Open(ctx, provider, data)
+		if err != nil {
+			return "", err
+		}
+		return string(plaintext), nil
+	})
+}
+
+// transformSensitiveAttributes returns a copy of the resources with the string values of the sensitive
+// attributes transformed, the input resources are not modified.
+func transformSensitiveAttributes(resources v1.Resources, transform func(string) (string, error)) (v1.Resources, error) {
+	if resources == nil {
+		return nil, nil
+	}
+
+	transformed := make(v1.Resources, len(resources))
+	for i, res := range resources {
+		transformed[i] = res
+		if !isSensitiveResource(&res) {
+			continue
+		}
+
+		attributes, err := transformSecretFields(res.Attributes, transform)
+		if err != nil {
+			return nil, fmt.Errorf("resource %s: %w", res.ID, err)
+		}
+		transformed[i].Attributes = attributes
+
+		// the attributes last applied are recorded in the State along with the live object
+		if lastApplied, ok := res.Extensions[v1.ResourceExtensionLastApplied].(map[string]interface{}); ok {
+			transformedLastApplied, err := transformSecretFields(lastApplied, transform)
+			if err != nil {
+				return nil, fmt.Errorf("last applied resource %s: %w", res.ID, err)
+			}
+			extensions := make(map[string]interface{}, len(res.Extensions))
+			for k, v := range res.Extensions {
+				extensions[k] = v
+			}
+			extensions[v1.ResourceExtensionLastApplied] = transformedLastApplied
+			transformed[i].Extensions = extensions
+		}
+	}
+	return transformed, nil
+}
+
+// transformSecretFields returns a copy of the attributes of the Secret with the string values of the
+// sensitive fields transformed.
+func transformSecretFields(attributes map[string]interface{}, transform func(string) (string, error)) (map[string]interface{}, error) {
+	transformed := make(map[string]interface{}, len(attributes))
+	for k, v := range attributes {
+		transformed[k] = v
+	}
+	for _, field := range sensitiveSecretFields {
+		values, ok := attributes[field].(map[string]interface{})
+		if !ok {
+			continue
+		}
+		transformedValues := make(map[string]interface{}, len(values))
+		for k, v := range values {
+			value, ok := v.(string)
+			if !ok {
+				transformedValues[k] = v
+				continue
+			}
+			transformedValue, err := transform(value)
+			if err != nil {
+				return nil, fmt.Errorf("attribute %s.%s: %w", field, k, err)
+			}
+			transformedValues[k] = transformedValue
+		}
+		transformed[field] = transformedValues
+	}
+	return transformed, nil
+}
+
+// isSensitiveResource returns whether the resource is a Kubernetes Secret.
+func isSensitiveResource(res *v1.Resource) bool {
+	kind, _ := res.Attributes["kind"].(string)
+	return res.Type == v1.Kubernetes && kind == "Secret"
+}
@@ -0,0 +1,128 @@
+package release
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	v1 "kusionstack.io/kusion/pkg/apis/api.kusion.io/v1"
+	"kusionstack.io/kusion/pkg/engine/release/storages"
+	"kusionstack.io/kusion/pkg/secrets/keyprovider"
+)
+
+func TestSensitiveEncryptedStorage(t *testing.T) {
+	dir := t.TempDir()
+	localStorage, err := storages.NewLocalStorage(dir)
+	require.NoError(t, err)
+	provider, err := keyprovider.NewLocalProvider("key-1", map[string][]byte{"key-1": mockEncryptionKey(1)})
+	require.NoError(t, err)
+	s := NewSensitiveEncryptedStorage(localStorage, provider)
+
+	r := mockSecretRelease(1)
+	require.NoError(t, s.Create(r))
+
+	// the password is encrypted on disk, while the other attributes are kept
+	content, err := os.ReadFile(filepath.Join(dir, "1.yaml"))
+	require.NoError(t, err)
+	assert.NotContains(t, string(content), "plaintext-password")
+	assert.Contains(t, string(content), "password: kusion-encrypted:key-1:")
+	assert.Contains(t, string(content), "kind: Secret")
+
+	// the input release is not modified
+	assert.Equal(t, "plaintext-password", r.State.Resources[0].Attributes["stringData"].(map[string]interface{})["password"])
+
+	// the password is decrypted when reading
+	got, err := s.Get(1)
+	require.NoError(t, err)
+	assert.Equal(t, r.Spec, got.Spec)
+	assert.Equal(t, r.State, got.State)
+
+	// the password fails to decrypt with a wrong key
+	wrongProvider, err := keyprovider.NewLocalProvider("key-1", map[string][]byte{"key-1": mockEncryptionKey(2)})
+	require.NoError(t, err)
+	_, err = NewSensitiveEncryptedStorage(localStorage, wrongProvider).Get(1)
+	assert.ErrorIs(t, err, keyprovider.ErrDecryptFailed)
+}
+
+func TestEncryptSensitiveAttributes(t *testing.T) {
+	provider, err := keyprovider.NewLocalProvider("arn:aws:kms:us-east-1:123456789012:key/1", map[string][]byte{
+		"arn:aws:kms:us-east-1:123456789012:key/1": mockEncryptionKey(1),
+	})
+	require.NoError(t, err)
+
+	resources := v1.Resources{
+		mockSecretRelease(1).State.Resources[0],
+		{
+			ID:   "apps/v1:Deployment:default:foo",
+			Type: v1.Kubernetes,
+			Attributes: map[string]interface{}{
+				"kind": "Deployment",
+				"data": map[string]interface{}{"password": "not-sensitive"},
+			},
+		},
+	}
+
+	encrypted, err := EncryptSensitiveAttributes(context.Background(), provider, resources)
+	require.NoError(t, err)
+	password := encrypted[0].Attributes["stringData"].(map[string]interface{})["password"].(string)
+	assert.True(t, strings.HasPrefix(password, EncryptedValuePrefix+"arn:aws:kms:us-east-1:123456789012:key/1:"))
+	assert.Equal(t, resources[1], encrypted[1])
+
+	decrypted, err := DecryptSensitiveAttributes(context.Background(), provider, encrypted)
+	require.NoError(t, err)
+	assert.Equal(t, resources, decrypted)
+}
+
+func TestEncryptSensitiveAttributes_PlaintextWithPrefix(t *testing.T) {
+	provider, err := keyprovider.NewLocalProvider("key-1", map[string][]byte{"key-1": mockEncryptionKey(1)})
+	require.NoError(t, err)
+
+	// the plaintext looking like an encrypted value is still encrypted, and restored after decryption
+	res := mockSecretRelease(1).State.Resources[0]
+	res.Attributes = map[string]interface{}{
+		"kind":       "Secret",
+		"stringData": map[string]interface{}{"password": EncryptedValuePrefix + "key-1:plaintext"},
+	}
+	resources := v1.Resources{res}
+
+	encrypted, err := EncryptSensitiveAttributes(context.Background(), provider, resources)
+	require.NoError(t, err)
+	password := encrypted[0].Attributes["stringData"].(map[string]interface{})["password"].(string)
+	assert.NotContains(t, password, "plaintext")
+
+	decrypted, err := DecryptSensitiveAttributes(context.Background(), provider, encrypted)
+	require.NoError(t, err)
+	assert.Equal(t, resources, decrypted)
+}
+
+func TestEncryptSensitiveAttributes_LastApplied(t *testing.T) {
+	provider, err := keyprovider.NewLocalProvider("key-1", map[string][]byte{"key-1": mockEncryptionKey(1)})
+	require.NoError(t, err)
+
+	res := mockSecretRelease(1).State.Resources[0]
+	res.Extensions = map[string]interface{}{
+		v1.ResourceExtensionGVK:         "/v1, Kind=Secret",
+		v1.ResourceExtensionLastApplied: res.Attributes,
+	}
+	resources := v1.Resources{res}
+
+	encrypted, err := EncryptSensitiveAttributes(context.Background(), provider, resources)
+	require.NoError(t, err)
+	lastApplied := encrypted[0].Extensions[v1.ResourceExtensionLastApplied].(map[string]interface{})
+	password := lastApplied["stringData"].(map[string]interface{})["password"].(string)
+	assert.True(t, strings.HasPrefix(password, EncryptedValuePrefix+"key-1:"))
+	assert.Equal(t, "/v1, Kind=Secret", encrypted[0].Extensions[v1.ResourceExtensionGVK])
+
+	// the input resources are not modified
+	assert.Equal(t, "plaintext-password",
+		res.Extensions[v1.ResourceExtensionLastApplied].(map[string]interface{})["stringData"].(map[string]interface{})["password"])
+
+	decrypted, err := DecryptSensitiveAttributes(context.Background(), provider, encrypted)
+	require.NoError(t, err)
+	assert.Equal(t, resources, decrypted)
+}
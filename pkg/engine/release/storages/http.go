@@ -0,0 +1,154 @@
+package storages
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	v1 "kusionstack.io/kusion/pkg/apis/api.kusion.io/v1"
+)
+
+const httpMetadataPath = "metadata"
+
+// defaultHTTPTimeout is the timeout of the requests if the http client is not specified.
+const defaultHTTPTimeout = 30 * time.Second
+
+// HTTPStorage is an implementation of release.Storage which uses a remote HTTP release service as storage.
+// The releases are read and written through the REST APIs shown as below, where the url is generated by
+// GenHTTPReleaseURL:
+//   - GET  {url}/metadata: get the releases metadata.
+//   - GET  {url}/{revision}: get the release of the revision.
+//   - POST {url}: create a release.
+//   - PUT  {url}/{revision}: update the release of the revision.
+type HTTPStorage struct {
+	client *http.Client
+
+	// The url of the releases of a specified project and workspace.
+	url string
+
+	// The bearer token to access the release service.
+	token string
+
+	meta *releasesMetaData
+}
+
+// NewHTTPStorage news http release storage, and derives metadata. If the client is nil, a client with
+// the default timeout is used.
+func NewHTTPStorage(client *http.Client, url, token string) (*HTTPStorage, error) {
+	if client == nil {
+		client = &http.Client{Timeout: defaultHTTPTimeout}
+	}
+	s := &HTTPStorage{
+		client: client,
+		url:    strings.TrimSuffix(url, "/"),
+		token:  token,
+	}
+	if err := s.readMeta(); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+func (s *HTTPStorage) Get(revision uint64) (*v1.Release, error) {
+	r := &v1.Release{}
+	if err := s.do(http.MethodGet, fmt.Sprintf("%s/%d", s.url, revision), nil, r); err != nil {
+		return nil, fmt.Errorf("get release failed: %w", err)
+	}
+	return r, nil
+}
+
+func (s *HTTPStorage) GetRevisions() []uint64 {
+	return getRevisions(s.meta)
+}
+
+func (s *HTTPStorage) GetStackBoundRevisions(stack string) []uint64 {
+	return getStackBoundRevisions(s.meta, stack)
+}
+
+func (s *HTTPStorage) GetLatestRevision() uint64 {
+	return s.meta.LatestRevision
+}
+
+func (s *HTTPStorage) Create(r *v1.Release) error {
+	if err := s.do(http.MethodPost, s.url, r, nil); err != nil {
+		return fmt.Errorf("create release failed: %w", err)
+	}
+
+	addLatestReleaseMetaData(s.meta, r.Revision, r.Stack)
+	return nil
+}
+
+func (s *HTTPStorage) Update(r *v1.Release) error {
+	if err := s.do(http.MethodPut, fmt.Sprintf("%s/%d", s.url, r.Revision), r, nil); err != nil {
+		return fmt.Errorf("update release failed: %w", err)
+	}
+	return nil
+}
+
+func (s *HTTPStorage) readMeta() error {
+	meta := &releasesMetaData{}
+	err := s.do(http.MethodGet, fmt.Sprintf("%s/%s", s.url, httpMetadataPath), nil, meta)
+	if errors.Is(err, ErrReleaseNotExist) {
+		s.meta = &releasesMetaData{}
+		return nil
+	} else if err != nil {
+		return fmt.Errorf("get releases metadata failed: %w", err)
+	}
+	s.meta = meta
+	return nil
+}
+
+// do sends the request with the json encoded body, and decodes the json response into the output if
+// not nil. The status code 404 and 409 are converted to ErrReleaseNotExist and ErrReleaseAlreadyExist.
+func (s *HTTPStorage) do(method, url string, body, output any) error {
+	var reader io.Reader
+	if body != nil {
+		content, err := json.Marshal(body)
+		if err != nil {
+			return fmt.Errorf("json marshal request body failed: %w", err)
+		}
+		reader = bytes.NewReader(content)
+	}
+
+	req, err := http.NewRequest(method, url, reader)
+	if err != nil {
+		return err
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if s.token != "" {
+		req.Header.Set("Authorization", "Bearer "+s.token)
+	}
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+
+	switch {
+	case resp.StatusCode == http.StatusNotFound:
+		return ErrReleaseNotExist
+	case resp.StatusCode == http.StatusConflict:
+		return ErrReleaseAlreadyExist
+	case resp.StatusCode < http.StatusOK || resp.StatusCode >= http.StatusMultipleChoices:
+		content, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("unexpected status code %d: %s", resp.StatusCode, string(content))
+	}
+
+	if output == nil {
+		return nil
+	}
+	if err = json.NewDecoder(resp.Body).Decode(output); err != nil {
+		return fmt.Errorf("json unmarshal response body failed: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,162 @@
+package storages
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	v1 "kusionstack.io/kusion/pkg/apis/api.kusion.io/v1"
+)
+
+const mockHTTPToken = "fake-token"
+
+// mockReleaseServer is an in-memory HTTP release service for testing.
+type mockReleaseServer struct {
+	mu       sync.Mutex
+	releases map[uint64]*v1.Release
+	meta     *releasesMetaData
+}
+
+func (m *mockReleaseServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if r.Header.Get("Authorization") != "Bearer "+mockHTTPToken {
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+
+	prefix := "/releases/test_project/test_ws"
+	if !strings.HasPrefix(r.URL.Path, prefix) {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+	path := strings.TrimPrefix(strings.TrimPrefix(r.URL.Path, prefix), "/")
+
+	switch {
+	case r.Method == http.MethodGet && path == httpMetadataPath:
+		_ = json.NewEncoder(w).Encode(m.meta)
+	case r.Method == http.MethodGet:
+		revision, _ := strconv.ParseUint(path, 10, 64)
+		release, ok := m.releases[revision]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		_ = json.NewEncoder(w).Encode(release)
+	case r.Method == http.MethodPost:
+		release := &v1.Release{}
+		_ = json.NewDecoder(r.Body).Decode(release)
+		if _, ok := m.releases[release.Revision]; ok {
+			w.WriteHeader(http.StatusConflict)
+			return
+		}
+		m.releases[release.Revision] = release
+		addLatestReleaseMetaData(m.meta, release.Revision, release.Stack)
+		w.WriteHeader(http.StatusCreated)
+	case r.Method == http.MethodPut:
+		revision, _ := strconv.ParseUint(path, 10, 64)
+		if _, ok := m.releases[revision]; !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		release := &v1.Release{}
+		_ = json.NewDecoder(r.Body).Decode(release)
+		m.releases[revision] = release
+	default:
+		w.WriteHeader(http.StatusMethodNotAllowed)
+	}
+}
+
+func newMockReleaseServer() *httptest.Server {
+	m := &mockReleaseServer{
+		releases: map[uint64]*v1.Release{1: mockRelease(1)},
+		meta: &releasesMetaData{
+			LatestRevision:   1,
+			ReleaseMetaDatas: []*releaseMetaData{{Revision: 1, Stack: "test_stack"}},
+		},
+	}
+	return httptest.NewServer(m)
+}
+
+func TestNewHTTPStorage(t *testing.T) {
+	server := newMockReleaseServer()
+	defer server.Close()
+
+	testcases := []struct {
+		name    string
+		success bool
+		url     string
+		token   string
+	}{
+		{
+			name:    "new http storage",
+			success: true,
+			url:     GenHTTPReleaseURL(server.URL, "test_project", "test_ws"),
+			token:   mockHTTPToken,
+		},
+		{
+			name:    "new http storage without metadata",
+			success: true,
+			url:     GenHTTPReleaseURL(server.URL, "test_project_2", "test_ws"),
+			token:   mockHTTPToken,
+		},
+		{
+			name:    "failed to new http storage unauthorized",
+			success: false,
+			url:     GenHTTPReleaseURL(server.URL, "test_project", "test_ws"),
+			token:   "invalid-token",
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := NewHTTPStorage(server.Client(), tc.url, tc.token)
+			assert.Equal(t, tc.success, err == nil)
+		})
+	}
+}
+
+func TestHTTPStorage(t *testing.T) {
+	server := newMockReleaseServer()
+	defer server.Close()
+
+	s, err := NewHTTPStorage(server.Client(), GenHTTPReleaseURL(server.URL, "test_project", "test_ws"), mockHTTPToken)
+	assert.NoError(t, err)
+	assert.Equal(t, []uint64{1}, s.GetRevisions())
+	assert.Equal(t, []uint64{1}, s.GetStackBoundRevisions("test_stack"))
+	assert.Equal(t, uint64(1), s.GetLatestRevision())
+
+	r, err := s.Get(1)
+	assert.NoError(t, err)
+	assert.Equal(t, "test_stack", r.Stack)
+
+	_, err = s.Get(2)
+	assert.ErrorIs(t, err, ErrReleaseNotExist)
+
+	assert.NoError(t, s.Create(mockRelease(2)))
+	assert.Equal(t, uint64(2), s.GetLatestRevision())
+	assert.ErrorIs(t, s.Create(mockRelease(2)), ErrReleaseAlreadyExist)
+
+	updated := mockRelease(2)
+	updated.Phase = v1.ReleasePhaseSucceeded
+	assert.NoError(t, s.Update(updated))
+	r, err = s.Get(2)
+	assert.NoError(t, err)
+	assert.Equal(t, v1.ReleasePhaseSucceeded, r.Phase)
+
+	assert.ErrorIs(t, s.Update(mockRelease(3)), ErrReleaseNotExist)
+}
+
+func TestGenHTTPReleaseURL(t *testing.T) {
+	baseURL := "https://release.example.com/api/v1/"
+	expected := fmt.Sprintf("https://release.example.com/api/v1/%s/test_project/test_ws", releasesPrefix)
+	assert.Equal(t, expected, GenHTTPReleaseURL(baseURL, "test_project", "test_ws"))
+}
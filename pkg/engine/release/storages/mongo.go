@@ -0,0 +1,261 @@
+package storages
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	v1 "kusionstack.io/kusion/pkg/apis/api.kusion.io/v1"
+)
+
+const (
+	mongoReleaseCollection = "releases"
+	mongoCounterCollection = "release_counters"
+
+	// DefaultMongoTimeout is the default timeout of each operation on MongoDB.
+	DefaultMongoTimeout = 30 * time.Second
+)
+
+// MongoStorage is an implementation of release.Storage which uses MongoDB as storage. The releases
+// are stored in the collection releases with a unique index on (project, workspace, revision), and
+// the latest revision of each project and workspace is kept in the collection release_counters,
+// which is advanced by findAndModify with $inc when creating a release, so that the concurrent
+// creations of the same revision are rejected.
+type MongoStorage struct {
+	releases *mongo.Collection
+	counters *mongo.Collection
+
+	// The timeout of each operation on MongoDB.
+	timeout time.Duration
+
+	project, workspace string
+
+	meta *releasesMetaData
+}
+
+// mongoRelease is the document of a release stored in MongoDB, where the whole release including
+// the large Spec and State is embedded as a sub-document.
+type mongoRelease struct {
+	Project   string `bson:"project"`
+	Workspace string `bson:"workspace"`
+	Revision  uint64 `bson:"revision"`
+	Stack     string `bson:"stack"`
+	Release   any    `bson:"release"`
+}
+
+// NewMongoStorage news mongo release storage, creates the unique index of the releases collection
+// if not exists, and derives metadata. Each operation on MongoDB is bounded by the timeout, and
+// DefaultMongoTimeout is used if the timeout is not positive.
+func NewMongoStorage(db *mongo.Database, timeout time.Duration, project, workspace string) (*MongoStorage, error) {
+	if timeout <= 0 {
+		timeout = DefaultMongoTimeout
+	}
+	s := &MongoStorage{
+		releases:  db.Collection(mongoReleaseCollection),
+		counters:  db.Collection(mongoCounterCollection),
+		timeout:   timeout,
+		project:   project,
+		workspace: workspace,
+	}
+
+	// create the unique index of the releases
+	ctx, cancel := s.newContext()
+	defer cancel()
+	_, err := s.releases.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys:    bson.D{{Key: "project", Value: 1}, {Key: "workspace", Value: 1}, {Key: "revision", Value: 1}},
+		Options: options.Index().SetUnique(true),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("create releases index failed: %w", err)
+	}
+	// read releases metadata
+	if err = s.readMeta(); err != nil {
+		return nil, err
+	}
+
+	return s, nil
+}
+
+func (s *MongoStorage) Get(revision uint64) (*v1.Release, error) {
+	if !checkRevisionExistence(s.meta, revision) {
+		return nil, ErrReleaseNotExist
+	}
+
+	ctx, cancel := s.newContext()
+	defer cancel()
+	var doc struct {
+		Release bson.Raw `bson:"release"`
+	}
+	err := s.releases.FindOne(ctx, s.filter(revision)).Decode(&doc)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, ErrReleaseNotExist
+	} else if err != nil {
+		return nil, fmt.Errorf("find release failed: %w", err)
+	}
+
+	content, err := bson.MarshalExtJSON(doc.Release, false, false)
+	if err != nil {
+		return nil, fmt.Errorf("convert release document failed: %w", err)
+	}
+	r := &v1.Release{}
+	if err = json.Unmarshal(content, r); err != nil {
+		return nil, fmt.Errorf("json unmarshal release failed: %w", err)
+	}
+	return r, nil
+}
+
+func (s *MongoStorage) GetRevisions() []uint64 {
+	return getRevisions(s.meta)
+}
+
+func (s *MongoStorage) GetStackBoundRevisions(stack string) []uint64 {
+	return getStackBoundRevisions(s.meta, stack)
+}
+
+func (s *MongoStorage) GetLatestRevision() uint64 {
+	return s.meta.LatestRevision
+}
+
+func (s *MongoStorage) Create(r *v1.Release) error {
+	if checkRevisionExistence(s.meta, r.Revision) {
+		return ErrReleaseAlreadyExist
+	}
+
+	doc, err := s.document(r)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := s.newContext()
+	defer cancel()
+
+	// advance the latest revision by one, the filter only matches the counter of the previous revision,
+	// so the upsert conflicts with the existing counter if the revision is not the next one
+	var counter struct {
+		LatestRevision uint64 `bson:"latestRevision"`
+	}
+	err = s.counters.FindOneAndUpdate(
+		ctx,
+		bson.M{"_id": s.counterID(), "latestRevision": r.Revision - 1},
+		bson.M{"$inc": bson.M{"latestRevision": 1}},
+		options.FindOneAndUpdate().SetUpsert(true).SetReturnDocument(options.After),
+	).Decode(&counter)
+	if mongo.IsDuplicateKeyError(err) {
+		return ErrReleaseAlreadyExist
+	} else if err != nil {
+		return fmt.Errorf("update latest revision failed: %w", err)
+	}
+	if counter.LatestRevision != r.Revision {
+		return ErrReleaseAlreadyExist
+	}
+
+	if _, err = s.releases.InsertOne(ctx, doc); err != nil {
+		if mongo.IsDuplicateKeyError(err) {
+			return ErrReleaseAlreadyExist
+		}
+		// roll back the latest revision, so that the creation of the revision can be retried
+		_, _ = s.counters.UpdateOne(
+			ctx,
+			bson.M{"_id": s.counterID(), "latestRevision": r.Revision},
+			bson.M{"$inc": bson.M{"latestRevision": -1}},
+		)
+		return fmt.Errorf("insert release failed: %w", err)
+	}
+
+	addLatestReleaseMetaData(s.meta, r.Revision, r.Stack)
+	return nil
+}
+
+func (s *MongoStorage) Update(r *v1.Release) error {
+	if !checkRevisionExistence(s.meta, r.Revision) {
+		return ErrReleaseNotExist
+	}
+
+	doc, err := s.document(r)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := s.newContext()
+	defer cancel()
+	result, err := s.releases.ReplaceOne(ctx, s.filter(r.Revision), doc)
+	if err != nil {
+		return fmt.Errorf("replace release failed: %w", err)
+	}
+	if result.MatchedCount == 0 {
+		return ErrReleaseNotExist
+	}
+	return nil
+}
+
+func (s *MongoStorage) readMeta() error {
+	ctx, cancel := s.newContext()
+	defer cancel()
+	cursor, err := s.releases.Find(
+		ctx,
+		bson.M{"project": s.project, "workspace": s.workspace},
+		options.Find().
+			SetProjection(bson.M{"revision": 1, "stack": 1}).
+			SetSort(bson.D{{Key: "revision", Value: 1}}),
+	)
+	if err != nil {
+		return fmt.Errorf("find releases metadata failed: %w", err)
+	}
+
+	var docs []mongoRelease
+	if err = cursor.All(ctx, &docs); err != nil {
+		return fmt.Errorf("decode releases metadata failed: %w", err)
+	}
+
+	meta := &releasesMetaData{}
+	for _, doc := range docs {
+		addLatestReleaseMetaData(meta, doc.Revision, doc.Stack)
+	}
+	s.meta = meta
+	return nil
+}
+
+// document converts the release to the mongo document, where the release is embedded through its
+// json representation to keep the same field names as the other storages.
+func (s *MongoStorage) document(r *v1.Release) (*mongoRelease, error) {
+	content, err := json.Marshal(r)
+	if err != nil {
+		return nil, fmt.Errorf("json marshal release failed: %w", err)
+	}
+
+	var release map[string]any
+	decoder := json.NewDecoder(bytes.NewReader(content))
+	// keep the integers from being converted to float
+	decoder.UseNumber()
+	if err = decoder.Decode(&release); err != nil {
+		return nil, fmt.Errorf("json unmarshal release failed: %w", err)
+	}
+
+	return &mongoRelease{
+		Project:   s.project,
+		Workspace: s.workspace,
+		Revision:  r.Revision,
+		Stack:     r.Stack,
+		Release:   release,
+	}, nil
+}
+
+// newContext returns the context of a single operation on MongoDB, which is bounded by the timeout.
+func (s *MongoStorage) newContext() (context.Context, context.CancelFunc) {
+	return context.WithTimeout(context.Background(), s.timeout)
+}
+
+func (s *MongoStorage) filter(revision uint64) bson.M {
+	return bson.M{"project": s.project, "workspace": s.workspace, "revision": revision}
+}
+
+func (s *MongoStorage) counterID() string {
+	return fmt.Sprintf("%s/%s", s.project, s.workspace)
+}
@@ -0,0 +1,176 @@
+package storages
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/integration/mtest"
+	"gopkg.in/yaml.v3"
+)
+
+const mockMongoNamespace = "kusion.releases"
+
+func mockMongoMetaDocs() []bson.D {
+	var docs []bson.D
+	for _, revision := range []uint64{1, 2, 3} {
+		docs = append(docs, bson.D{{Key: "revision", Value: int64(revision)}, {Key: "stack", Value: "test_stack"}})
+	}
+	return docs
+}
+
+// newMockMongoStorage news a mongo storage on the mocked deployment, with the releases metadata of
+// revision 1, 2 and 3.
+func newMockMongoStorage(mt *mtest.T) *MongoStorage {
+	mt.AddMockResponses(
+		mtest.CreateSuccessResponse(),
+		mtest.CreateCursorResponse(0, mockMongoNamespace, mtest.FirstBatch, mockMongoMetaDocs()...),
+	)
+	s, err := NewMongoStorage(mt.DB, time.Second, "test_project", "test_ws")
+	require.NoError(mt, err)
+	return s
+}
+
+// mockMongoCounterResponse mocks the response of findAndModify on the counter, which returns the
+// counter after update.
+func mockMongoCounterResponse(latestRevision uint64) bson.D {
+	return mtest.CreateSuccessResponse(bson.E{Key: "value", Value: bson.D{
+		{Key: "_id", Value: "test_project/test_ws"},
+		{Key: "latestRevision", Value: int64(latestRevision)},
+	}})
+}
+
+func TestNewMongoStorage(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+
+	mt.Run("new mongo storage with empty collection", func(mt *mtest.T) {
+		mt.AddMockResponses(
+			mtest.CreateSuccessResponse(),
+			mtest.CreateCursorResponse(0, mockMongoNamespace, mtest.FirstBatch),
+		)
+		s, err := NewMongoStorage(mt.DB, time.Second, "test_project", "test_ws")
+		assert.NoError(mt, err)
+		assert.Equal(mt, &releasesMetaData{}, s.meta)
+	})
+
+	mt.Run("new mongo storage with exist releases", func(mt *mtest.T) {
+		s := newMockMongoStorage(mt)
+		assert.Equal(mt, mockReleasesMeta(), s.meta)
+	})
+
+	mt.Run("failed to new mongo storage create index failed", func(mt *mtest.T) {
+		mt.AddMockResponses(mtest.CreateCommandErrorResponse(mtest.CommandError{Code: 13, Message: "unauthorized"}))
+		_, err := NewMongoStorage(mt.DB, time.Second, "test_project", "test_ws")
+		assert.Error(mt, err)
+	})
+}
+
+func TestMongoStorage_Get(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+
+	mt.Run("get release successfully", func(mt *mtest.T) {
+		s := newMockMongoStorage(mt)
+		doc, err := s.document(mockRelease(1))
+		require.NoError(mt, err)
+		raw, err := bson.Marshal(doc)
+		require.NoError(mt, err)
+		var d bson.D
+		require.NoError(mt, bson.Unmarshal(raw, &d))
+		mt.AddMockResponses(mtest.CreateCursorResponse(0, mockMongoNamespace, mtest.FirstBatch, d))
+
+		r, err := s.Get(1)
+		assert.NoError(mt, err)
+		expectedReleaseContent, _ := yaml.Marshal(mockRelease(1))
+		releaseContent, _ := yaml.Marshal(r)
+		assert.Equal(mt, string(expectedReleaseContent), string(releaseContent))
+	})
+
+	mt.Run("failed to get release not exist", func(mt *mtest.T) {
+		s := newMockMongoStorage(mt)
+		_, err := s.Get(4)
+		assert.ErrorIs(mt, err, ErrReleaseNotExist)
+	})
+}
+
+func TestMongoStorage_Create(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+
+	mt.Run("create release successfully", func(mt *mtest.T) {
+		s := newMockMongoStorage(mt)
+		mt.AddMockResponses(
+			mockMongoCounterResponse(4),
+			mtest.CreateSuccessResponse(),
+		)
+
+		err := s.Create(mockRelease(4))
+		assert.NoError(mt, err)
+		assert.Equal(mt, uint64(4), s.GetLatestRevision())
+		assert.Equal(mt, []uint64{1, 2, 3, 4}, s.GetRevisions())
+
+		// the counter of the previous revision is increased by one
+		var update bson.Raw
+		for _, event := range mt.GetAllStartedEvents() {
+			if event.CommandName == "findAndModify" {
+				update = event.Command
+			}
+		}
+		require.NotNil(mt, update)
+		assert.Equal(mt, int64(3), update.Lookup("query", "latestRevision").Int64())
+		assert.Equal(mt, int32(1), update.Lookup("update", "$inc", "latestRevision").Int32())
+	})
+
+	mt.Run("failed to create release already exist in metadata", func(mt *mtest.T) {
+		s := newMockMongoStorage(mt)
+		err := s.Create(mockRelease(3))
+		assert.ErrorIs(mt, err, ErrReleaseAlreadyExist)
+	})
+
+	mt.Run("failed to create release latest revision advanced concurrently", func(mt *mtest.T) {
+		s := newMockMongoStorage(mt)
+		mt.AddMockResponses(mtest.CreateCommandErrorResponse(mtest.CommandError{Code: 11000, Message: "duplicate key"}))
+
+		err := s.Create(mockRelease(4))
+		assert.ErrorIs(mt, err, ErrReleaseAlreadyExist)
+		assert.Equal(mt, uint64(3), s.GetLatestRevision())
+	})
+
+	mt.Run("failed to create release counter advanced unexpectedly", func(mt *mtest.T) {
+		s := newMockMongoStorage(mt)
+		mt.AddMockResponses(mockMongoCounterResponse(5))
+
+		err := s.Create(mockRelease(4))
+		assert.ErrorIs(mt, err, ErrReleaseAlreadyExist)
+		assert.Equal(mt, uint64(3), s.GetLatestRevision())
+	})
+
+	mt.Run("failed to create release inserted concurrently", func(mt *mtest.T) {
+		s := newMockMongoStorage(mt)
+		mt.AddMockResponses(
+			mockMongoCounterResponse(4),
+			mtest.CreateWriteErrorsResponse(mtest.WriteError{Index: 0, Code: 11000, Message: "duplicate key"}),
+		)
+
+		err := s.Create(mockRelease(4))
+		assert.ErrorIs(mt, err, ErrReleaseAlreadyExist)
+	})
+}
+
+func TestMongoStorage_Update(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+
+	mt.Run("update release successfully", func(mt *mtest.T) {
+		s := newMockMongoStorage(mt)
+		mt.AddMockResponses(mtest.CreateSuccessResponse(bson.E{Key: "n", Value: 1}, bson.E{Key: "nModified", Value: 1}))
+
+		err := s.Update(mockRelease(3))
+		assert.NoError(mt, err)
+	})
+
+	mt.Run("failed to update release not exist", func(mt *mtest.T) {
+		s := newMockMongoStorage(mt)
+		err := s.Update(mockRelease(4))
+		assert.ErrorIs(mt, err, ErrReleaseNotExist)
+	})
+}
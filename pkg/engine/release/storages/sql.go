@@ -0,0 +1,220 @@
+package storages
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	v1 "kusionstack.io/kusion/pkg/apis/api.kusion.io/v1"
+)
+
+const (
+	// SQLDialectMysql is the dialect of MySQL, whose placeholder of the bind variables is "?".
+	SQLDialectMysql = "mysql"
+
+	// SQLDialectPostgres is the dialect of Postgres, whose placeholder of the bind variables is "$N".
+	SQLDialectPostgres = "postgres"
+
+	sqlReleaseTable = "releases"
+)
+
+// SQLStorage is an implementation of release.Storage which uses a relational database as storage.
+// The releases are stored in the table releases, whose primary key (project, workspace, revision)
+// guarantees the uniqueness of the revision, so that the concurrent creations of the same revision
+// are rejected by the database.
+type SQLStorage struct {
+	db *sql.DB
+
+	// The dialect of the database, which decides the placeholder of the bind variables.
+	dialect string
+
+	project, workspace string
+
+	meta *releasesMetaData
+}
+
+// NewSQLStorage news sql release storage, creates the releases table if not exists, and derives
+// metadata. The db should be opened with a driver of the specified dialect.
+func NewSQLStorage(db *sql.DB, dialect, project, workspace string) (*SQLStorage, error) {
+	if dialect != SQLDialectMysql && dialect != SQLDialectPostgres {
+		return nil, fmt.Errorf("unsupported sql dialect %s, only %s and %s are supported", dialect, SQLDialectMysql, SQLDialectPostgres)
+	}
+	s := &SQLStorage{
+		db:        db,
+		dialect:   dialect,
+		project:   project,
+		workspace: workspace,
+	}
+
+	// create the releases table
+	if _, err := s.db.Exec(s.createTableStatement()); err != nil {
+		return nil, fmt.Errorf("create releases table failed: %w", err)
+	}
+	// read releases metadata
+	if err := s.readMeta(); err != nil {
+		return nil, err
+	}
+
+	return s, nil
+}
+
+func (s *SQLStorage) Get(revision uint64) (*v1.Release, error) {
+	if !checkRevisionExistence(s.meta, revision) {
+		return nil, ErrReleaseNotExist
+	}
+
+	var content string
+	err := s.db.QueryRow(
+		s.rebind(fmt.Sprintf("SELECT content FROM %s WHERE project = ? AND workspace = ? AND revision = ?", sqlReleaseTable)),
+		s.project, s.workspace, revision,
+	).Scan(&content)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrReleaseNotExist
+	} else if err != nil {
+		return nil, fmt.Errorf("query release failed: %w", err)
+	}
+
+	r := &v1.Release{}
+	if err = yaml.Unmarshal([]byte(content), r); err != nil {
+		return nil, fmt.Errorf("yaml unmarshal release failed: %w", err)
+	}
+	return r, nil
+}
+
+func (s *SQLStorage) GetRevisions() []uint64 {
+	return getRevisions(s.meta)
+}
+
+func (s *SQLStorage) GetStackBoundRevisions(stack string) []uint64 {
+	return getStackBoundRevisions(s.meta, stack)
+}
+
+func (s *SQLStorage) GetLatestRevision() uint64 {
+	return s.meta.LatestRevision
+}
+
+func (s *SQLStorage) Create(r *v1.Release) error {
+	if checkRevisionExistence(s.meta, r.Revision) {
+		return ErrReleaseAlreadyExist
+	}
+
+	content, err := yaml.Marshal(r)
+	if err != nil {
+		return fmt.Errorf("yaml marshal release failed: %w", err)
+	}
+
+	_, err = s.db.Exec(
+		s.rebind(fmt.Sprintf("INSERT INTO %s (project, workspace, revision, stack, content) VALUES (?, ?, ?, ?, ?)", sqlReleaseTable)),
+		s.project, s.workspace, r.Revision, r.Stack, string(content),
+	)
+	if err != nil {
+		// the insertion violating the primary key means the release has been created by others
+		exist, existErr := s.exist(r.Revision)
+		if existErr == nil && exist {
+			return ErrReleaseAlreadyExist
+		}
+		return fmt.Errorf("insert release failed: %w", err)
+	}
+
+	addLatestReleaseMetaData(s.meta, r.Revision, r.Stack)
+	return nil
+}
+
+func (s *SQLStorage) Update(r *v1.Release) error {
+	if !checkRevisionExistence(s.meta, r.Revision) {
+		return ErrReleaseNotExist
+	}
+
+	content, err := yaml.Marshal(r)
+	if err != nil {
+		return fmt.Errorf("yaml marshal release failed: %w", err)
+	}
+
+	result, err := s.db.Exec(
+		s.rebind(fmt.Sprintf("UPDATE %s SET content = ? WHERE project = ? AND workspace = ? AND revision = ?", sqlReleaseTable)),
+		string(content), s.project, s.workspace, r.Revision,
+	)
+	if err != nil {
+		return fmt.Errorf("update release failed: %w", err)
+	}
+	if affected, err := result.RowsAffected(); err == nil && affected == 0 {
+		return ErrReleaseNotExist
+	}
+	return nil
+}
+
+func (s *SQLStorage) readMeta() error {
+	rows, err := s.db.Query(
+		s.rebind(fmt.Sprintf("SELECT revision, stack FROM %s WHERE project = ? AND workspace = ? ORDER BY revision", sqlReleaseTable)),
+		s.project, s.workspace,
+	)
+	if err != nil {
+		return fmt.Errorf("query releases metadata failed: %w", err)
+	}
+	defer func() {
+		_ = rows.Close()
+	}()
+
+	meta := &releasesMetaData{}
+	for rows.Next() {
+		var revision uint64
+		var stack string
+		if err = rows.Scan(&revision, &stack); err != nil {
+			return fmt.Errorf("scan releases metadata failed: %w", err)
+		}
+		addLatestReleaseMetaData(meta, revision, stack)
+	}
+	if err = rows.Err(); err != nil {
+		return fmt.Errorf("query releases metadata failed: %w", err)
+	}
+	s.meta = meta
+	return nil
+}
+
+func (s *SQLStorage) exist(revision uint64) (bool, error) {
+	var count int
+	err := s.db.QueryRow(
+		s.rebind(fmt.Sprintf("SELECT COUNT(*) FROM %s WHERE project = ? AND workspace = ? AND revision = ?", sqlReleaseTable)),
+		s.project, s.workspace, revision,
+	).Scan(&count)
+	if err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
+
+func (s *SQLStorage) createTableStatement() string {
+	contentType := "TEXT"
+	if s.dialect == SQLDialectMysql {
+		contentType = "LONGTEXT"
+	}
+	return fmt.Sprintf(`CREATE TABLE IF NOT EXISTS %s (
+	project VARCHAR(255) NOT NULL,
+	workspace VARCHAR(255) NOT NULL,
+	revision BIGINT NOT NULL,
+	stack VARCHAR(255) NOT NULL,
+	content %s NOT NULL,
+	PRIMARY KEY (project, workspace, revision)
+)`, sqlReleaseTable, contentType)
+}
+
+// rebind replaces the placeholders "?" in the query with "$N" for Postgres.
+func (s *SQLStorage) rebind(query string) string {
+	if s.dialect != SQLDialectPostgres {
+		return query
+	}
+	var b strings.Builder
+	n := 0
+	for _, c := range query {
+		if c == '?' {
+			n++
+			fmt.Fprintf(&b, "$%d", n)
+			continue
+		}
+		b.WriteRune(c)
+	}
+	return b.String()
+}
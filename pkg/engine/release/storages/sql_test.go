@@ -0,0 +1,230 @@
+package storages
+
+import (
+	"errors"
+	"regexp"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
+)
+
+func mockSQLMetaRows() *sqlmock.Rows {
+	return sqlmock.NewRows([]string{"revision", "stack"}).
+		AddRow(1, "test_stack").
+		AddRow(2, "test_stack").
+		AddRow(3, "test_stack")
+}
+
+func newMockSQLStorage(t *testing.T, dialect string) (*SQLStorage, sqlmock.Sqlmock) {
+	db, sqlMock, err := sqlmock.New()
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		_ = db.Close()
+	})
+
+	sqlMock.ExpectExec("CREATE TABLE IF NOT EXISTS releases").WillReturnResult(sqlmock.NewResult(0, 0))
+	sqlMock.ExpectQuery("SELECT revision, stack FROM releases").
+		WithArgs("test_project", "test_ws").
+		WillReturnRows(mockSQLMetaRows())
+	s, err := NewSQLStorage(db, dialect, "test_project", "test_ws")
+	require.NoError(t, err)
+	return s, sqlMock
+}
+
+func TestNewSQLStorage(t *testing.T) {
+	testcases := []struct {
+		name         string
+		success      bool
+		dialect      string
+		metaRows     *sqlmock.Rows
+		expectedMeta *releasesMetaData
+	}{
+		{
+			name:         "new sql storage with empty table",
+			success:      true,
+			dialect:      SQLDialectMysql,
+			metaRows:     sqlmock.NewRows([]string{"revision", "stack"}),
+			expectedMeta: &releasesMetaData{},
+		},
+		{
+			name:         "new sql storage with exist releases",
+			success:      true,
+			dialect:      SQLDialectPostgres,
+			metaRows:     mockSQLMetaRows(),
+			expectedMeta: mockReleasesMeta(),
+		},
+		{
+			name:    "failed to new sql storage with unsupported dialect",
+			success: false,
+			dialect: "sqlserver",
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			db, sqlMock, err := sqlmock.New()
+			require.NoError(t, err)
+			defer db.Close()
+			if tc.metaRows != nil {
+				sqlMock.ExpectExec("CREATE TABLE IF NOT EXISTS releases").WillReturnResult(sqlmock.NewResult(0, 0))
+				sqlMock.ExpectQuery("SELECT revision, stack FROM releases").
+					WithArgs("test_project", "test_ws").
+					WillReturnRows(tc.metaRows)
+			}
+
+			s, err := NewSQLStorage(db, tc.dialect, "test_project", "test_ws")
+			assert.Equal(t, tc.success, err == nil)
+			if tc.success {
+				assert.Equal(t, tc.expectedMeta, s.meta)
+			}
+			assert.NoError(t, sqlMock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestSQLStorage_Get(t *testing.T) {
+	testcases := []struct {
+		name     string
+		success  bool
+		revision uint64
+		content  string
+	}{
+		{
+			name:     "get release successfully",
+			success:  true,
+			revision: 1,
+			content:  mockReleaseRevision1Content(),
+		},
+		{
+			name:     "failed to get release not exist",
+			success:  false,
+			revision: 4,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			s, sqlMock := newMockSQLStorage(t, SQLDialectPostgres)
+			if tc.content != "" {
+				sqlMock.ExpectQuery(regexp.QuoteMeta("SELECT content FROM releases WHERE project = $1 AND workspace = $2 AND revision = $3")).
+					WithArgs("test_project", "test_ws", tc.revision).
+					WillReturnRows(sqlmock.NewRows([]string{"content"}).AddRow(tc.content))
+			}
+
+			r, err := s.Get(tc.revision)
+			assert.Equal(t, tc.success, err == nil)
+			if tc.success {
+				expectedReleaseContent, _ := yaml.Marshal(mockRelease(tc.revision))
+				releaseContent, _ := yaml.Marshal(r)
+				assert.Equal(t, string(expectedReleaseContent), string(releaseContent))
+			}
+			assert.NoError(t, sqlMock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestSQLStorage_Create(t *testing.T) {
+	testcases := []struct {
+		name         string
+		success      bool
+		revision     uint64
+		insertErr    error
+		existCount   int
+		expectedErr  error
+		expectedMeta *releasesMetaData
+	}{
+		{
+			name:     "create release successfully",
+			success:  true,
+			revision: 4,
+			expectedMeta: &releasesMetaData{
+				LatestRevision: 4,
+				ReleaseMetaDatas: []*releaseMetaData{
+					mockReleaseMeta(1),
+					mockReleaseMeta(2),
+					mockReleaseMeta(3),
+					mockReleaseMeta(4),
+				},
+			},
+		},
+		{
+			name:        "failed to create release already exist in metadata",
+			success:     false,
+			revision:    3,
+			expectedErr: ErrReleaseAlreadyExist,
+		},
+		{
+			name:        "failed to create release created concurrently",
+			success:     false,
+			revision:    4,
+			insertErr:   errors.New("duplicate key"),
+			existCount:  1,
+			expectedErr: ErrReleaseAlreadyExist,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			s, sqlMock := newMockSQLStorage(t, SQLDialectMysql)
+			if tc.revision > 3 {
+				insert := sqlMock.ExpectExec(regexp.QuoteMeta("INSERT INTO releases (project, workspace, revision, stack, content) VALUES (?, ?, ?, ?, ?)")).
+					WithArgs("test_project", "test_ws", tc.revision, "test_stack", sqlmock.AnyArg())
+				if tc.insertErr != nil {
+					insert.WillReturnError(tc.insertErr)
+					sqlMock.ExpectQuery(regexp.QuoteMeta("SELECT COUNT(*) FROM releases")).
+						WithArgs("test_project", "test_ws", tc.revision).
+						WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(tc.existCount))
+				} else {
+					insert.WillReturnResult(sqlmock.NewResult(0, 1))
+				}
+			}
+
+			err := s.Create(mockRelease(tc.revision))
+			assert.Equal(t, tc.success, err == nil)
+			if tc.expectedErr != nil {
+				assert.ErrorIs(t, err, tc.expectedErr)
+			}
+			if tc.success {
+				assert.Equal(t, tc.expectedMeta, s.meta)
+			}
+			assert.NoError(t, sqlMock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestSQLStorage_Update(t *testing.T) {
+	testcases := []struct {
+		name     string
+		success  bool
+		revision uint64
+	}{
+		{
+			name:     "update release successfully",
+			success:  true,
+			revision: 3,
+		},
+		{
+			name:     "failed to update release not exist",
+			success:  false,
+			revision: 4,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			s, sqlMock := newMockSQLStorage(t, SQLDialectMysql)
+			if tc.success {
+				sqlMock.ExpectExec(regexp.QuoteMeta("UPDATE releases SET content = ? WHERE project = ? AND workspace = ? AND revision = ?")).
+					WithArgs(sqlmock.AnyArg(), "test_project", "test_ws", tc.revision).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+			}
+
+			err := s.Update(mockRelease(tc.revision))
+			assert.Equal(t, tc.success, err == nil)
+			assert.NoError(t, sqlMock.ExpectationsWereMet())
+		})
+	}
+}
@@ -1,10 +1,11 @@
 package storages
 
 import (
-	"errors"
 	"fmt"
 	"path/filepath"
 	"strings"
+
+	"kusionstack.io/kusion/pkg/util/kerrors"
 )
 
 const (
@@ -14,8 +15,8 @@ const (
 )
 
 var (
-	ErrReleaseNotExist     = errors.New("release does not exist")
-	ErrReleaseAlreadyExist = errors.New("release has already existed")
+	ErrReleaseNotExist     = kerrors.New(kerrors.ErrNotFound, "release does not exist")
+	ErrReleaseAlreadyExist = kerrors.New(kerrors.ErrAlreadyExists, "release has already existed")
 )
 
 // GenReleaseDirPath generates the release dir path, which is used for LocalStorage.
@@ -41,6 +42,17 @@ func GenReleasePrefixKeyWithPath(prefix, path string) string {
 	return fmt.Sprintf("%s%s/%s", prefix, releasesPrefix, path)
 }
 
+// GenHTTPReleaseURL generates the url of the releases of a specified project and workspace, which is used for HTTPStorage.
+func GenHTTPReleaseURL(baseURL, project, workspace string) string {
+	return fmt.Sprintf("%s/%s/%s/%s", strings.TrimSuffix(baseURL, "/"), releasesPrefix, project, workspace)
+}
+
+// GenHTTPReleaseURLWithPath generates the url of the releases with the specified path instead of project and
+// workspace, which is used for HTTPStorage.
+func GenHTTPReleaseURLWithPath(baseURL, path string) string {
+	return fmt.Sprintf("%s/%s/%s", strings.TrimSuffix(baseURL, "/"), releasesPrefix, path)
+}
+
 // releasesMetaData contains mata data of the releases of a specified project and workspace. The mata data
 // includes the latest revision, and synopsis of the releases.
 type releasesMetaData struct {
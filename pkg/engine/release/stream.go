@@ -0,0 +1,122 @@
+package release
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+
+	v1 "kusionstack.io/kusion/pkg/apis/api.kusion.io/v1"
+	jsonutil "kusionstack.io/kusion/pkg/util/json"
+)
+
+// stateResourcesField is the JSON field of the resources of the State.
+const stateResourcesField = "resources"
+
+var (
+	ErrInvalidStateStream = errors.New("invalid state stream")
+
+	// ErrStopDecoding is returned by the callback of DecodeStateResources to stop decoding the remaining
+	// resources, which is not returned by DecodeStateResources.
+	ErrStopDecoding = errors.New("stop decoding")
+)
+
+// DecodeStateResources decodes the State in JSON from r, and calls f with the resources one at a time
+// in order, instead of decoding all of them into a slice, so that the State with thousands of resources
+// is iterated without holding all of them in memory. The resource passed to f is not reused, and the
+// integers in its attributes are normalized to int64 as GetLatestState does.
+func DecodeStateResources(r io.Reader, f func(resource *v1.Resource) error) error {
+	dec := json.NewDecoder(r)
+	if err := expectDelim(dec, '{'); err != nil {
+		return err
+	}
+	for dec.More() {
+		token, err := dec.Token()
+		if err != nil {
+			return fmt.Errorf("%w: %v", ErrInvalidStateStream, err)
+		}
+		if token != stateResourcesField {
+			var skipped json.RawMessage
+			if err = dec.Decode(&skipped); err != nil {
+				return fmt.Errorf("%w: %v", ErrInvalidStateStream, err)
+			}
+			continue
+		}
+		if err = decodeResources(dec, f); err != nil {
+			if errors.Is(err, ErrStopDecoding) {
+				return nil
+			}
+			return err
+		}
+	}
+	return expectDelim(dec, '}')
+}
+
+// decodeResources decodes the array of the resources, where null is an empty array.
+func decodeResources(dec *json.Decoder, f func(resource *v1.Resource) error) error {
+	token, err := dec.Token()
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidStateStream, err)
+	}
+	if token == nil {
+		return nil
+	}
+	if token != json.Delim('[') {
+		return fmt.Errorf("%w: expected array of resources, got %v", ErrInvalidStateStream, token)
+	}
+	for dec.More() {
+		resource := &v1.Resource{}
+		if err = dec.Decode(resource); err != nil {
+			return fmt.Errorf("%w: %v", ErrInvalidStateStream, err)
+		}
+		jsonutil.NormalizeNumbers(resource.Attributes)
+		if err = f(resource); err != nil {
+			return err
+		}
+	}
+	return expectDelim(dec, ']')
+}
+
+func expectDelim(dec *json.Decoder, delim json.Delim) error {
+	token, err := dec.Token()
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidStateStream, err)
+	}
+	if token != delim {
+		return fmt.Errorf("%w: expected %v, got %v", ErrInvalidStateStream, delim, token)
+	}
+	return nil
+}
+
+// DecodeState decodes the whole State in JSON from r, for the callers requiring all the resources at
+// once, e.g. the apply.
+func DecodeState(r io.Reader) (*v1.State, error) {
+	state := &v1.State{Resources: v1.Resources{}}
+	err := DecodeStateResources(r, func(resource *v1.Resource) error {
+		state.Resources = append(state.Resources, *resource)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return state, nil
+}
+
+// DecodeStateGVKIndex decodes the State in JSON from r, and returns the same index as
+// v1.Resources.GVKIndex, where only the indexed Kubernetes resources are kept in memory.
+func DecodeStateGVKIndex(r io.Reader) (map[string][]*v1.Resource, error) {
+	m := make(map[string][]*v1.Resource)
+	err := DecodeStateResources(r, func(resource *v1.Resource) error {
+		if resource.Type != v1.Kubernetes {
+			return nil
+		}
+		if gvk, ok := resource.GVK(); ok {
+			m[gvk.String()] = append(m[gvk.String()], resource)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return m, nil
+}
@@ -0,0 +1,148 @@
+package release
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	v1 "kusionstack.io/kusion/pkg/apis/api.kusion.io/v1"
+)
+
+func mockLargeState(n int) []byte {
+	state := &v1.State{}
+	for i := 0; i < n; i++ {
+		resource := v1.Resource{
+			ID:   fmt.Sprintf("v1:ConfigMap:default:cm-%d", i),
+			Type: v1.Kubernetes,
+			Attributes: map[string]interface{}{
+				"apiVersion": "v1",
+				"kind":       "ConfigMap",
+				"metadata":   map[string]interface{}{"name": fmt.Sprintf("cm-%d", i), "namespace": "default"},
+				"data":       map[string]interface{}{"replicas": int64(i)},
+			},
+			Extensions: map[string]interface{}{},
+		}
+		if i%2 == 1 {
+			resource.ID = fmt.Sprintf("hashicorp:random:random_password:pw-%d", i)
+			resource.Type = v1.Terraform
+		} else {
+			resource.SetGVK(schema.GroupVersionKind{Version: "v1", Kind: "ConfigMap"})
+		}
+		state.Resources = append(state.Resources, resource)
+	}
+	data, _ := json.Marshal(state)
+	return data
+}
+
+func TestDecodeStateResources(t *testing.T) {
+	data := mockLargeState(4)
+
+	var ids []string
+	err := DecodeStateResources(bytes.NewReader(data), func(resource *v1.Resource) error {
+		ids = append(ids, resource.ID)
+		return nil
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{
+		"v1:ConfigMap:default:cm-0",
+		"hashicorp:random:random_password:pw-1",
+		"v1:ConfigMap:default:cm-2",
+		"hashicorp:random:random_password:pw-3",
+	}, ids)
+
+	state, err := DecodeState(bytes.NewReader(data))
+	assert.NoError(t, err)
+	assert.Len(t, state.Resources, 4)
+	assert.Equal(t, int64(2), state.Resources[2].Attributes["data"].(map[string]interface{})["replicas"])
+
+	index, err := DecodeStateGVKIndex(bytes.NewReader(data))
+	assert.NoError(t, err)
+	assert.Len(t, index["/v1, Kind=ConfigMap"], 2)
+	assert.Equal(t, len(state.Resources.GVKIndex()["/v1, Kind=ConfigMap"]), len(index["/v1, Kind=ConfigMap"]))
+}
+
+func TestDecodeStateResourcesStop(t *testing.T) {
+	count := 0
+	err := DecodeStateResources(bytes.NewReader(mockLargeState(4)), func(resource *v1.Resource) error {
+		count++
+		if count == 2 {
+			return ErrStopDecoding
+		}
+		return nil
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, 2, count)
+
+	err = DecodeStateResources(bytes.NewReader(mockLargeState(4)), func(resource *v1.Resource) error {
+		return assert.AnError
+	})
+	assert.ErrorIs(t, err, assert.AnError)
+}
+
+func TestDecodeStateResourcesInvalid(t *testing.T) {
+	testcases := []struct {
+		name    string
+		content string
+		count   int
+		err     bool
+	}{
+		{name: "empty state", content: `{}`},
+		{name: "null resources", content: `{"resources": null}`},
+		{name: "unknown fields", content: `{"foo": {"bar": [1]}, "resources": [{"id": "a"}], "baz": 1}`, count: 1},
+		{name: "not an object", content: `[]`, err: true},
+		{name: "resources not an array", content: `{"resources": {}}`, err: true},
+		{name: "truncated", content: `{"resources": [{"id": "a"}`, count: 1, err: true},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			count := 0
+			err := DecodeStateResources(strings.NewReader(tc.content), func(resource *v1.Resource) error {
+				count++
+				return nil
+			})
+			assert.Equal(t, tc.count, count)
+			if tc.err {
+				assert.True(t, errors.Is(err, ErrInvalidStateStream), "unexpected error: %v", err)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func BenchmarkDecodeLargeState(b *testing.B) {
+	data := mockLargeState(5000)
+
+	// unmarshal reads and decodes the whole State as the storages do, before indexing it.
+	b.Run("unmarshal", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			content, err := io.ReadAll(bytes.NewReader(data))
+			if err != nil {
+				b.Fatal(err)
+			}
+			state := &v1.State{}
+			if err = json.Unmarshal(content, state); err != nil {
+				b.Fatal(err)
+			}
+			normalizeState(state)
+			_ = state.Resources.GVKIndex()
+		}
+	})
+	b.Run("stream", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			if _, err := DecodeStateGVKIndex(bytes.NewReader(data)); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+}
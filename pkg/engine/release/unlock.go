@@ -0,0 +1,142 @@
+package release
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"syscall"
+	"time"
+
+	v1 "kusionstack.io/kusion/pkg/apis/api.kusion.io/v1"
+	"kusionstack.io/kusion/pkg/util/kerrors"
+)
+
+var (
+	ErrReleaseNotLocked  = kerrors.New(kerrors.ErrNotFound, "no release in progress to unlock")
+	ErrLockIDMismatch    = kerrors.New(kerrors.ErrConflict, "lock id mismatch")
+	ErrLockHeldByLiveOp  = kerrors.New(kerrors.ErrConflict, "lock is held by a live operation")
+	ErrEmptyLockOperator = kerrors.New(kerrors.ErrValidation, "empty operator to unlock")
+	ErrInvalidThreshold  = kerrors.New(kerrors.ErrValidation, "stale threshold must be positive")
+)
+
+// LivenessChecker reports whether the operation of the Release in progress is still live. The known
+// is false if the liveness cannot be determined, e.g. the operation runs on another host.
+type LivenessChecker func(r *v1.Release) (live bool, known bool)
+
+// UnlockOptions is the options to force unlock a Release.
+type UnlockOptions struct {
+	// LockID is the id of the lock to unlock, optional. If specified, it must be the same as the LockID
+	// of the latest Release unless Force is true, which avoids unlocking a Release other than the
+	// expected one.
+	LockID string
+
+	// Force ignores the LockID.
+	Force bool
+
+	// Operator is who unlocks the Release, which is recorded in the Release.
+	Operator string
+
+	// Liveness is used to refuse unlocking the Release whose operation is still live, optional.
+	Liveness LivenessChecker
+}
+
+// IsLocked returns whether the Release is in progress, which blocks creating new Releases of the same
+// project and workspace.
+func IsLocked(r *v1.Release) bool {
+	return r != nil && r.IsActive()
+}
+
+// DetectStaleReleases returns the active Releases in the storage whose ModifiedTime is older than the
+// threshold, which are likely left by crashed operations, in the order of the revisions. They are the
+// candidates to clean up by ForceUnlock, or to alert on.
+func DetectStaleReleases(storage Storage, threshold time.Duration) ([]*v1.Release, error) {
+	if threshold <= 0 {
+		return nil, fmt.Errorf("%w, got %s", ErrInvalidThreshold, threshold)
+	}
+
+	deadline := time.Now().Add(-threshold)
+	var stale []*v1.Release
+	for _, revision := range storage.GetRevisions() {
+		r, err := storage.Get(revision)
+		if err != nil {
+			return nil, fmt.Errorf("get release of revision %d failed: %w", revision, err)
+		}
+		if r.IsActive() && r.ModifiedTime.Before(deadline) {
+			stale = append(stale, r)
+		}
+	}
+	return stale, nil
+}
+
+// LockID returns the id of the lock held by the Release, which is the revision.
+func LockID(r *v1.Release) string {
+	return strconv.FormatUint(r.Revision, 10)
+}
+
+// ProcessLiveness is a LivenessChecker which checks whether the process holding the Release is still
+// running. The liveness is known only if the Release is held by a process on the current host.
+func ProcessLiveness(r *v1.Release) (live bool, known bool) {
+	if r.Holder == nil || r.Holder.PID <= 0 {
+		return false, false
+	}
+	if host, err := os.Hostname(); err != nil || host != r.Holder.Host {
+		return false, false
+	}
+
+	process, err := os.FindProcess(r.Holder.PID)
+	if err != nil {
+		return false, true
+	}
+	err = process.Signal(syscall.Signal(0))
+	switch {
+	case err == nil, errors.Is(err, syscall.EPERM):
+		// the process exists, though it may be owned by another user
+		return true, true
+	case errors.Is(err, os.ErrProcessDone), errors.Is(err, syscall.ESRCH):
+		return false, true
+	default:
+		// e.g. the signal is not supported on the platform
+		return false, false
+	}
+}
+
+// ForceUnlock unlocks the latest Release in progress left by a crashed operation, by setting the phase
+// to failed and recording the operator. It refuses to unlock if the specified lock id mismatches without
+// force, or the operation holding the lock is determined to be still live.
+func ForceUnlock(storage Storage, opts *UnlockOptions) (*v1.Release, error) {
+	if opts.Operator == "" {
+		return nil, ErrEmptyLockOperator
+	}
+
+	r, err := GetLatestRelease(storage)
+	if err != nil {
+		return nil, err
+	}
+	if !IsLocked(r) {
+		return nil, ErrReleaseNotLocked
+	}
+	if !opts.Force && opts.LockID != "" && opts.LockID != LockID(r) {
+		return nil, fmt.Errorf("%w, expected %s, got %s", ErrLockIDMismatch, LockID(r), opts.LockID)
+	}
+	if opts.Liveness != nil {
+		if live, known := opts.Liveness(r); known && live {
+			return nil, fmt.Errorf("%w, project %s, workspace %s, revision %d", ErrLockHeldByLiveOp, r.Project, r.Workspace, r.Revision)
+		}
+	}
+
+	previousPhase := r.Phase
+	if err = SetPhase(r, v1.ReleasePhaseFailed); err != nil {
+		return nil, err
+	}
+	r.Unlock = &v1.ReleaseUnlock{
+		Operator:      opts.Operator,
+		Time:          r.ModifiedTime,
+		Force:         opts.Force,
+		PreviousPhase: previousPhase,
+	}
+	if err = storage.Update(r); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
@@ -0,0 +1,173 @@
+package release
+
+import (
+	"os"
+	"os/exec"
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	v1 "kusionstack.io/kusion/pkg/apis/api.kusion.io/v1"
+	"kusionstack.io/kusion/pkg/engine/release/storages"
+	"kusionstack.io/kusion/pkg/util/kerrors"
+)
+
+func TestForceUnlock(t *testing.T) {
+	testcases := []struct {
+		name        string
+		phase       v1.ReleasePhase
+		opts        *UnlockOptions
+		expectedErr error
+	}{
+		{
+			name:  "unlock with matching lock id",
+			phase: v1.ReleasePhaseApplying,
+			opts:  &UnlockOptions{LockID: "1", Operator: "admin"},
+		},
+		{
+			name:  "unlock without lock id",
+			phase: v1.ReleasePhaseApplying,
+			opts:  &UnlockOptions{Operator: "admin"},
+		},
+		{
+			name:  "force unlock ignoring lock id",
+			phase: v1.ReleasePhasePreviewing,
+			opts:  &UnlockOptions{LockID: "2", Force: true, Operator: "admin"},
+		},
+		{
+			name:        "mismatched lock id",
+			phase:       v1.ReleasePhaseApplying,
+			opts:        &UnlockOptions{LockID: "2", Operator: "admin"},
+			expectedErr: ErrLockIDMismatch,
+		},
+		{
+			name:        "empty operator",
+			phase:       v1.ReleasePhaseApplying,
+			opts:        &UnlockOptions{LockID: "1"},
+			expectedErr: ErrEmptyLockOperator,
+		},
+		{
+			name:        "release not locked",
+			phase:       v1.ReleasePhaseSucceeded,
+			opts:        &UnlockOptions{Force: true, Operator: "admin"},
+			expectedErr: ErrReleaseNotLocked,
+		},
+		{
+			name:  "lock held by live operation",
+			phase: v1.ReleasePhaseApplying,
+			opts: &UnlockOptions{Force: true, Operator: "admin", Liveness: func(*v1.Release) (bool, bool) {
+				return true, true
+			}},
+			expectedErr: ErrLockHeldByLiveOp,
+		},
+		{
+			name:  "liveness unknown",
+			phase: v1.ReleasePhaseApplying,
+			opts: &UnlockOptions{LockID: "1", Operator: "admin", Liveness: func(*v1.Release) (bool, bool) {
+				return true, false
+			}},
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			s, err := storages.NewLocalStorage(t.TempDir())
+			require.NoError(t, err)
+			r := mockSecretRelease(1)
+			r.Phase = tc.phase
+			require.NoError(t, s.Create(r))
+
+			unlocked, err := ForceUnlock(s, tc.opts)
+			if tc.expectedErr != nil {
+				assert.ErrorIs(t, err, tc.expectedErr)
+				persisted, err := s.Get(1)
+				require.NoError(t, err)
+				assert.Equal(t, tc.phase, persisted.Phase)
+				assert.Nil(t, persisted.Unlock)
+				return
+			}
+
+			require.NoError(t, err)
+			persisted, err := s.Get(1)
+			require.NoError(t, err)
+			assert.Equal(t, unlocked.Phase, persisted.Phase)
+			assert.Equal(t, v1.ReleasePhaseFailed, persisted.Phase)
+			require.NotNil(t, persisted.Unlock)
+			assert.Equal(t, tc.opts.Operator, persisted.Unlock.Operator)
+			assert.Equal(t, tc.opts.Force, persisted.Unlock.Force)
+			assert.Equal(t, tc.phase, persisted.Unlock.PreviousPhase)
+			assert.False(t, persisted.Unlock.Time.IsZero())
+		})
+	}
+
+	// the kinds of the errors
+	assert.True(t, kerrors.IsConflict(ErrLockIDMismatch))
+	assert.True(t, kerrors.IsConflict(ErrLockHeldByLiveOp))
+	assert.True(t, kerrors.IsNotFound(ErrReleaseNotLocked))
+}
+
+func TestProcessLiveness(t *testing.T) {
+	host, err := os.Hostname()
+	require.NoError(t, err)
+
+	// the current process is live
+	live, known := ProcessLiveness(&v1.Release{Holder: CurrentHolder()})
+	assert.True(t, known)
+	assert.True(t, live)
+
+	// the exited process is not live
+	cmd := exec.Command(os.Args[0], "-test.run=^$")
+	require.NoError(t, cmd.Run())
+	live, known = ProcessLiveness(&v1.Release{Holder: &v1.ReleaseHolder{Host: host, PID: cmd.Process.Pid}})
+	if runtime.GOOS != "windows" {
+		assert.True(t, known)
+	}
+	assert.False(t, live)
+
+	// unknown if the holder is not recorded or on another host
+	_, known = ProcessLiveness(&v1.Release{})
+	assert.False(t, known)
+	_, known = ProcessLiveness(&v1.Release{Holder: &v1.ReleaseHolder{Host: host + "-other", PID: os.Getpid()}})
+	assert.False(t, known)
+}
+
+func TestDetectStaleReleases(t *testing.T) {
+	s, err := storages.NewLocalStorage(t.TempDir())
+	require.NoError(t, err)
+	now := time.Now()
+	releases := []struct {
+		phase    v1.ReleasePhase
+		modified time.Time
+	}{
+		{phase: v1.ReleasePhaseSucceeded, modified: now.Add(-2 * time.Hour)},
+		{phase: v1.ReleasePhaseApplying, modified: now.Add(-2 * time.Hour)},
+		{phase: v1.ReleasePhaseFailed, modified: now.Add(-2 * time.Hour)},
+		{phase: v1.ReleasePhasePreviewing, modified: now.Add(-90 * time.Minute)},
+		{phase: v1.ReleasePhaseGenerating, modified: now.Add(-time.Minute)},
+	}
+	for i, rel := range releases {
+		r := mockSecretRelease(uint64(i + 1))
+		r.Phase = rel.phase
+		r.CreateTime = rel.modified
+		r.ModifiedTime = rel.modified
+		require.NoError(t, s.Create(r))
+	}
+
+	stale, err := DetectStaleReleases(s, time.Hour)
+	require.NoError(t, err)
+	var revisions []uint64
+	for _, r := range stale {
+		revisions = append(revisions, r.Revision)
+	}
+	assert.Equal(t, []uint64{2, 4}, revisions)
+
+	stale, err = DetectStaleReleases(s, 3*time.Hour)
+	require.NoError(t, err)
+	assert.Empty(t, stale)
+
+	_, err = DetectStaleReleases(s, 0)
+	assert.ErrorIs(t, err, ErrInvalidThreshold)
+}
@@ -1,12 +1,17 @@
 package release
 
 import (
+	"errors"
 	"fmt"
+	"os"
+	"os/user"
 	"sync"
 	"time"
 
 	v1 "kusionstack.io/kusion/pkg/apis/api.kusion.io/v1"
 	"kusionstack.io/kusion/pkg/log"
+	jsonutil "kusionstack.io/kusion/pkg/util/json"
+	"kusionstack.io/kusion/pkg/util/kerrors"
 )
 
 // GetLatestRelease returns the latest release. If no release exists, return nil.
@@ -20,6 +25,7 @@ func GetLatestRelease(storage Storage) (*v1.Release, error) {
 	if err != nil {
 		return nil, err
 	}
+	normalizeState(r.State)
 
 	return r, err
 }
@@ -34,11 +40,85 @@ func GetLatestState(storage Storage) (*v1.State, error) {
 	if err != nil {
 		return nil, err
 	}
+	normalizeState(r.State)
 	return r.State, err
 }
 
+// normalizeState coerces the integers in the attributes of the Kubernetes resources, which may be
+// decoded as float64 by the storage, back to int64 to avoid the spurious diffs against the live
+// resources. The other resources are kept as they are, since their floating fields can't be told from
+// the integer ones without the live resources.
+func normalizeState(state *v1.State) {
+	if state == nil {
+		return
+	}
+	for i := range state.Resources {
+		if state.Resources[i].Type == v1.Kubernetes {
+			jsonutil.NormalizeNumbers(state.Resources[i].Attributes)
+		}
+	}
+}
+
+// Option sets the optional fields of the release when it is created.
+type Option func(*v1.Release)
+
+// WithMetadata sets the metadata of the release, such as the Git commit and the triggering user.
+func WithMetadata(metadata map[string]string) Option {
+	return func(rel *v1.Release) {
+		if len(metadata) == 0 {
+			return
+		}
+		rel.Metadata = make(map[string]string, len(metadata))
+		for k, v := range metadata {
+			rel.Metadata[k] = v
+		}
+	}
+}
+
+// WithOperator sets who creates the release, which defaults to the OS user if empty.
+func WithOperator(operator string) Option {
+	return func(rel *v1.Release) {
+		if operator != "" {
+			rel.Operator = operator
+		}
+	}
+}
+
+// CurrentOperator returns the name of the OS user running Kusion, or "unknown" if it can't be got.
+func CurrentOperator() string {
+	if u, err := user.Current(); err == nil && u.Username != "" {
+		return u.Username
+	}
+	return "unknown"
+}
+
+// WithHolder sets the process running the operation of the release, which defaults to the current
+// process if nil.
+func WithHolder(holder *v1.ReleaseHolder) Option {
+	return func(rel *v1.Release) {
+		if holder != nil {
+			rel.Holder = holder
+		}
+	}
+}
+
+// CurrentHolder returns the current process as the holder of the release.
+func CurrentHolder() *v1.ReleaseHolder {
+	host, _ := os.Hostname()
+	return &v1.ReleaseHolder{
+		Host: host,
+		PID:  os.Getpid(),
+	}
+}
+
+// newReleaseOptions returns the options of a new release, where the operator defaults to the OS user
+// and the holder defaults to the current process, which can be overridden by the opts.
+func newReleaseOptions(opts []Option) []Option {
+	return append([]Option{WithOperator(CurrentOperator()), WithHolder(CurrentHolder())}, opts...)
+}
+
 // NewApplyRelease news a release object for apply operation, but no creation in the storage.
-func NewApplyRelease(storage Storage, project, stack, workspace string) (*v1.Release, error) {
+func NewApplyRelease(storage Storage, project, stack, workspace string, opts ...Option) (*v1.Release, error) {
 	revision := storage.GetLatestRevision()
 
 	var rel *v1.Release
@@ -59,6 +139,7 @@ func NewApplyRelease(storage Storage, project, stack, workspace string) (*v1.Rel
 		if err != nil {
 			return nil, err
 		}
+		normalizeState(lastRelease.State)
 		if lastRelease.Phase != v1.ReleasePhaseSucceeded && lastRelease.Phase != v1.ReleasePhaseFailed {
 			return nil, fmt.Errorf("cannot create a new release of project: %s, workspace: %s. There is a release:%v in progress",
 				project, workspace, lastRelease.Revision)
@@ -73,12 +154,67 @@ func NewApplyRelease(storage Storage, project, stack, workspace string) (*v1.Rel
 			Phase:        v1.ReleasePhaseGenerating,
 			CreateTime:   currentTime,
 			ModifiedTime: currentTime,
+			// carry the module outputs over for the incremental generation
+			ModuleOutputs: lastRelease.ModuleOutputs,
 		}
 	}
+	for _, opt := range newReleaseOptions(opts) {
+		opt(rel)
+	}
 
 	return rel, nil
 }
 
+// ErrNoResumableRelease is returned by GetResumableRelease if the latest release is not left in the
+// applying phase.
+var ErrNoResumableRelease = kerrors.New(kerrors.ErrNotFound, "no resumable release in applying phase")
+
+// GetResumableRelease returns the latest release if it is left in the applying phase, e.g. the apply is
+// interrupted midway. The State of the release records the resources which have been applied, as the
+// State is updated once a resource is applied successfully. The apply can be resumed with the Spec and
+// State of the release, where the applied resources are re-verified against the live resources rather
+// than skipped, and the remaining resources will be applied.
+func GetResumableRelease(storage Storage) (*v1.Release, error) {
+	r, err := GetLatestRelease(storage)
+	if err != nil {
+		return nil, err
+	}
+	if r == nil || r.Phase != v1.ReleasePhaseApplying || r.Spec == nil {
+		return nil, ErrNoResumableRelease
+	}
+	if r.State == nil {
+		r.State = &v1.State{}
+	}
+	// the resumed release is held by the current process from now on
+	r.Holder = CurrentHolder()
+	return r, nil
+}
+
+// ListReleases returns all the releases in the storage, in the order of the revisions returned by the storage.
+func ListReleases(storage Storage) ([]*v1.Release, error) {
+	revisions := storage.GetRevisions()
+	releases := make([]*v1.Release, 0, len(revisions))
+	for _, revision := range revisions {
+		r, err := storage.Get(revision)
+		if err != nil {
+			return nil, err
+		}
+		releases = append(releases, r)
+	}
+	return releases, nil
+}
+
+// CreateRelease creates the release in the storage. If the revision has already existed, which means
+// another operation has created the release concurrently, the returned error is of kerrors.ErrConflict,
+// and the operation can be retried with a new revision.
+func CreateRelease(storage Storage, rel *v1.Release) error {
+	err := storage.Create(rel)
+	if errors.Is(err, kerrors.ErrAlreadyExists) {
+		return kerrors.Wrap(kerrors.ErrConflict, fmt.Errorf("release of project %s workspace %s revision %d has been created by another operation, please retry: %w", rel.Project, rel.Workspace, rel.Revision, err))
+	}
+	return err
+}
+
 // UpdateApplyRelease updates the release in the storage if dryRun is false. If release phase is failed,
 // only logging with no error return.
 func UpdateApplyRelease(storage Storage, rel *v1.Release, dryRun bool, relLock *sync.Mutex) error {
@@ -97,7 +233,7 @@ func UpdateApplyRelease(storage Storage, rel *v1.Release, dryRun bool, relLock *
 }
 
 // CreateDestroyRelease creates a release object in the storage for destroy operation.
-func CreateDestroyRelease(storage Storage, project, stack, workspace string) (*v1.Release, error) {
+func CreateDestroyRelease(storage Storage, project, stack, workspace string, opts ...Option) (*v1.Release, error) {
 	revision := storage.GetLatestRevision()
 	if revision == 0 {
 		return nil, fmt.Errorf("cannot find release of project %s, workspace %s", project, workspace)
@@ -107,6 +243,7 @@ func CreateDestroyRelease(storage Storage, project, stack, workspace string) (*v
 	if err != nil {
 		return nil, err
 	}
+	normalizeState(lastRelease.State)
 	if lastRelease.Phase != v1.ReleasePhaseSucceeded && lastRelease.Phase != v1.ReleasePhaseFailed {
 		return nil, fmt.Errorf("cannot create release of project %s, workspace %s cause there is release in progress", project, workspace)
 	}
@@ -143,9 +280,15 @@ func CreateDestroyRelease(storage Storage, project, stack, workspace string) (*v
 		CreateTime:   currentTime,
 		ModifiedTime: currentTime,
 	}
+	for _, opt := range newReleaseOptions(opts) {
+		opt(rel)
+	}
 
-	if err = storage.Create(rel); err != nil {
-		return nil, fmt.Errorf("create release of project %s workspace %s revision %d failed", project, workspace, rel.Revision)
+	if err = CreateRelease(storage, rel); err != nil {
+		if kerrors.IsConflict(err) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("create release of project %s workspace %s revision %d failed: %w", project, workspace, rel.Revision, err)
 	}
 
 	return rel, nil
@@ -163,9 +306,61 @@ func UpdateDestroyRelease(storage Storage, rel *v1.Release) error {
 	return err
 }
 
-// UpdateReleasePhase updates the release with the specified phase.
+// UpdateReleasePhase updates the release with the specified phase by SetPhase, where the illegal
+// transition is logged, leaving the release unchanged.
 func UpdateReleasePhase(rel *v1.Release, phase v1.ReleasePhase, relLock *sync.Mutex) {
 	relLock.Lock()
 	defer relLock.Unlock()
-	rel.Phase = phase
+	if err := SetPhase(rel, phase); err != nil {
+		log.Errorf("failed to update release phase, project %s, workspace %s, revision %d: %v",
+			rel.Project, rel.Workspace, rel.Revision, err)
+	}
+}
+
+// UpdateReleaseFailed moves the release to the failed phase with the reason by SetFailed, where the illegal
+// transition is logged, leaving the release unchanged. The failure already recorded on the release, e.g. by
+// the engine with the failed resource, is kept.
+func UpdateReleaseFailed(rel *v1.Release, reason string, relLock *sync.Mutex) {
+	relLock.Lock()
+	defer relLock.Unlock()
+	if rel != nil && rel.Phase == v1.ReleasePhaseFailed && rel.Message != "" {
+		return
+	}
+	if err := SetFailed(rel, reason, ""); err != nil {
+		log.Errorf("failed to update release phase, project %s, workspace %s, revision %d: %v",
+			rel.Project, rel.Workspace, rel.Revision, err)
+	}
+}
+
+// SetPhase moves the release to the next phase and stamps the ModifiedTime, if the transition is allowed
+// by the release phase state machine, see v1.ReleasePhase.CanTransitionTo. Setting the current phase again
+// is a no-op, while any transition out of a terminal phase is rejected.
+func SetPhase(rel *v1.Release, next v1.ReleasePhase) error {
+	if rel == nil {
+		return ErrEmptyRelease
+	}
+	if rel.Phase == next {
+		return nil
+	}
+	if !rel.Phase.CanTransitionTo(next) {
+		return fmt.Errorf("%w: from %s to %s", ErrIllegalPhase, rel.Phase, next)
+	}
+	rel.Phase = next
+	rel.ModifiedTime = time.Now()
+	if next == v1.ReleasePhaseSucceeded {
+		rel.Message = ""
+		rel.FailedResource = ""
+	}
+	return nil
+}
+
+// SetFailed moves the release to the failed phase by SetPhase, and records the reason of the failure and
+// the ID of the failing resource if any, to be persisted with the release.
+func SetFailed(rel *v1.Release, reason string, resourceID string) error {
+	if err := SetPhase(rel, v1.ReleasePhaseFailed); err != nil {
+		return err
+	}
+	rel.Message = reason
+	rel.FailedResource = resourceID
+	return nil
 }
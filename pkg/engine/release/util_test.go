@@ -1,13 +1,20 @@
 package release
 
 import (
+	"encoding/json"
+	"errors"
+	"os"
+	"sync"
 	"testing"
 
 	"github.com/bytedance/mockey"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 
 	v1 "kusionstack.io/kusion/pkg/apis/api.kusion.io/v1"
 	"kusionstack.io/kusion/pkg/engine/release/storages"
+	"kusionstack.io/kusion/pkg/util/diff"
+	"kusionstack.io/kusion/pkg/util/kerrors"
 )
 
 func mockReleaseStorageOperation(revision uint64) {
@@ -47,3 +54,322 @@ func TestGetLatestState(t *testing.T) {
 		})
 	}
 }
+
+func TestGetLatestStateNormalizesNumbers(t *testing.T) {
+	live := v1.Resource{
+		ID:   "apps/v1:Deployment:default:foo",
+		Type: v1.Kubernetes,
+		Attributes: map[string]interface{}{
+			"apiVersion": "apps/v1",
+			"kind":       "Deployment",
+			"spec":       map[string]interface{}{"replicas": int64(3), "progressDeadlineSeconds": int64(600)},
+		},
+	}
+	// the integers become float64 after round-tripping through JSON
+	data, err := json.Marshal(live)
+	require.NoError(t, err)
+	stored := v1.Resource{}
+	require.NoError(t, json.Unmarshal(data, &stored))
+	require.Equal(t, float64(3), stored.Attributes["spec"].(map[string]interface{})["replicas"])
+
+	mockey.PatchConvey("mock json storage", t, func() {
+		mockey.Mock((*storages.LocalStorage).GetLatestRevision).Return(uint64(1)).Build()
+		mockey.Mock((*storages.LocalStorage).Get).Return(&v1.Release{State: &v1.State{Resources: v1.Resources{stored}}}, nil).Build()
+		state, err := GetLatestState(&storages.LocalStorage{})
+		require.NoError(t, err)
+		assert.Equal(t, live.Attributes, state.Resources[0].Attributes)
+
+		report, err := diff.ToReport(live, state.Resources[0])
+		require.NoError(t, err)
+		assert.Empty(t, report.Diffs)
+	})
+
+	// the floating fields of the other resources are kept
+	mockey.PatchConvey("mock json storage with terraform resource", t, func() {
+		terraform := v1.Resource{
+			ID:         "hashicorp:aws:aws_instance:foo",
+			Type:       v1.Terraform,
+			Attributes: map[string]interface{}{"cpu_core_count": float64(2), "cpu_ratio": float64(1)},
+		}
+		mockey.Mock((*storages.LocalStorage).GetLatestRevision).Return(uint64(1)).Build()
+		mockey.Mock((*storages.LocalStorage).Get).Return(&v1.Release{State: &v1.State{Resources: v1.Resources{terraform}}}, nil).Build()
+		state, err := GetLatestState(&storages.LocalStorage{})
+		require.NoError(t, err)
+		assert.Equal(t, float64(1), state.Resources[0].Attributes["cpu_ratio"])
+	})
+}
+
+func TestCreateRelease(t *testing.T) {
+	s, err := storages.NewLocalStorage(t.TempDir())
+	require.NoError(t, err)
+	require.NoError(t, CreateRelease(s, mockSecretRelease(1)))
+
+	err = CreateRelease(s, mockSecretRelease(1))
+	assert.True(t, kerrors.IsConflict(err))
+	assert.True(t, kerrors.IsAlreadyExists(err))
+	assert.True(t, errors.Is(err, storages.ErrReleaseAlreadyExist))
+	assert.False(t, kerrors.IsNotFound(err))
+
+	_, err = s.Get(2)
+	assert.True(t, kerrors.IsNotFound(err))
+}
+
+func TestGetResumableRelease(t *testing.T) {
+	s, err := storages.NewLocalStorage(t.TempDir())
+	require.NoError(t, err)
+
+	// no release
+	_, err = GetResumableRelease(s)
+	assert.ErrorIs(t, err, ErrNoResumableRelease)
+	assert.True(t, kerrors.IsNotFound(err))
+
+	// the latest release has succeeded
+	succeeded := mockSecretRelease(1)
+	require.NoError(t, s.Create(succeeded))
+	_, err = GetResumableRelease(s)
+	assert.ErrorIs(t, err, ErrNoResumableRelease)
+
+	// the apply failed midway, where only the first resource has been applied and recorded in the state
+	applying := mockSecretRelease(2)
+	applying.Spec.Resources = append(applying.Spec.Resources, v1.Resource{
+		ID:         "v1:ConfigMap:default:config",
+		Type:       v1.Kubernetes,
+		Attributes: map[string]interface{}{"apiVersion": "v1", "kind": "ConfigMap"},
+	})
+	applying.Phase = v1.ReleasePhaseApplying
+	applying.Holder = &v1.ReleaseHolder{Host: "crashed-host", PID: 1}
+	require.NoError(t, s.Create(applying))
+
+	resumed, err := GetResumableRelease(s)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(2), resumed.Revision)
+	assert.Equal(t, v1.ReleasePhaseApplying, resumed.Phase)
+	assert.Len(t, resumed.Spec.Resources, 2)
+	assert.Equal(t, "v1:Secret:default:db-password", resumed.State.Resources[0].ID)
+	assert.Len(t, resumed.State.Resources, 1)
+	// the resumed release is taken over by the current process
+	assert.Equal(t, CurrentHolder(), resumed.Holder)
+}
+
+func TestReleaseMetadata(t *testing.T) {
+	s, err := storages.NewLocalStorage(t.TempDir())
+	require.NoError(t, err)
+	metadata := map[string]string{
+		v1.ReleaseMetadataGitCommit:   "3f2a9c1",
+		v1.ReleaseMetadataPullRequest: "42",
+		v1.ReleaseMetadataTriggeredBy: "ci-bot",
+	}
+
+	rel, err := NewApplyRelease(s, "test_project", "test_stack", "test_ws", WithMetadata(metadata))
+	require.NoError(t, err)
+	rel.Phase = v1.ReleasePhaseSucceeded
+	require.NoError(t, CreateRelease(s, rel))
+
+	// the metadata is copied
+	metadata[v1.ReleaseMetadataTriggeredBy] = "someone-else"
+
+	destroyRel, err := CreateDestroyRelease(s, "test_project", "test_stack", "test_ws", WithMetadata(map[string]string{v1.ReleaseMetadataTriggeredBy: "admin"}))
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{v1.ReleaseMetadataTriggeredBy: "admin"}, destroyRel.Metadata)
+
+	releases, err := ListReleases(s)
+	require.NoError(t, err)
+	require.Len(t, releases, 2)
+	assert.Equal(t, map[string]string{
+		v1.ReleaseMetadataGitCommit:   "3f2a9c1",
+		v1.ReleaseMetadataPullRequest: "42",
+		v1.ReleaseMetadataTriggeredBy: "ci-bot",
+	}, releases[0].Metadata)
+	assert.Equal(t, map[string]string{v1.ReleaseMetadataTriggeredBy: "admin"}, releases[1].Metadata)
+
+	// round-trip through JSON
+	data, err := json.Marshal(releases[0])
+	require.NoError(t, err)
+	assert.Contains(t, string(data), `"metadata":{"git-commit":"3f2a9c1","pull-request":"42","triggered-by":"ci-bot"}`)
+	decoded := &v1.Release{}
+	require.NoError(t, json.Unmarshal(data, decoded))
+	assert.Equal(t, releases[0].Metadata, decoded.Metadata)
+
+	// no metadata is omitted
+	rel, err = NewApplyRelease(s, "test_project", "test_stack", "test_ws", WithMetadata(nil))
+	require.NoError(t, err)
+	assert.Nil(t, rel.Metadata)
+}
+
+func TestReleaseOperator(t *testing.T) {
+	s, err := storages.NewLocalStorage(t.TempDir())
+	require.NoError(t, err)
+
+	// defaults to the OS user
+	rel, err := NewApplyRelease(s, "test_project", "test_stack", "test_ws")
+	require.NoError(t, err)
+	assert.Equal(t, CurrentOperator(), rel.Operator)
+	assert.NotEmpty(t, rel.Operator)
+	assert.Equal(t, CurrentHolder(), rel.Holder)
+
+	rel, err = NewApplyRelease(s, "test_project", "test_stack", "test_ws", WithOperator(""))
+	require.NoError(t, err)
+	assert.Equal(t, CurrentOperator(), rel.Operator)
+
+	rel, err = NewApplyRelease(s, "test_project", "test_stack", "test_ws", WithOperator("alice"))
+	require.NoError(t, err)
+	rel.Phase = v1.ReleasePhaseSucceeded
+	require.NoError(t, CreateRelease(s, rel))
+
+	destroyRel, err := CreateDestroyRelease(s, "test_project", "test_stack", "test_ws", WithOperator("bob"))
+	require.NoError(t, err)
+	assert.Equal(t, "bob", destroyRel.Operator)
+
+	// the operator is persisted
+	r, err := s.Get(1)
+	require.NoError(t, err)
+	assert.Equal(t, "alice", r.Operator)
+	assert.Equal(t, os.Getpid(), r.Holder.PID)
+	r, err = s.Get(2)
+	require.NoError(t, err)
+	assert.Equal(t, "bob", r.Operator)
+}
+
+func TestUpdateReleasePhase(t *testing.T) {
+	testcases := []struct {
+		name     string
+		phase    v1.ReleasePhase
+		next     v1.ReleasePhase
+		expected v1.ReleasePhase
+	}{
+		{
+			name:     "legal transition",
+			phase:    v1.ReleasePhasePreviewing,
+			next:     v1.ReleasePhaseApplying,
+			expected: v1.ReleasePhaseApplying,
+		},
+		{
+			name:     "same phase",
+			phase:    v1.ReleasePhaseFailed,
+			next:     v1.ReleasePhaseFailed,
+			expected: v1.ReleasePhaseFailed,
+		},
+		{
+			name:     "illegal transition from terminal phase",
+			phase:    v1.ReleasePhaseSucceeded,
+			next:     v1.ReleasePhaseFailed,
+			expected: v1.ReleasePhaseSucceeded,
+		},
+		{
+			name:     "illegal transition skipping previewing",
+			phase:    v1.ReleasePhaseGenerating,
+			next:     v1.ReleasePhaseApplying,
+			expected: v1.ReleasePhaseGenerating,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			rel := &v1.Release{Phase: tc.phase}
+			UpdateReleasePhase(rel, tc.next, &sync.Mutex{})
+			assert.Equal(t, tc.expected, rel.Phase)
+		})
+	}
+}
+
+func TestSetPhase(t *testing.T) {
+	testcases := []struct {
+		name    string
+		phase   v1.ReleasePhase
+		next    v1.ReleasePhase
+		success bool
+	}{
+		{name: "generating to previewing", phase: v1.ReleasePhaseGenerating, next: v1.ReleasePhasePreviewing, success: true},
+		{name: "generating to failed", phase: v1.ReleasePhaseGenerating, next: v1.ReleasePhaseFailed, success: true},
+		{name: "previewing to applying", phase: v1.ReleasePhasePreviewing, next: v1.ReleasePhaseApplying, success: true},
+		{name: "previewing to destroying", phase: v1.ReleasePhasePreviewing, next: v1.ReleasePhaseDestroying, success: true},
+		{name: "previewing to succeeded", phase: v1.ReleasePhasePreviewing, next: v1.ReleasePhaseSucceeded, success: true},
+		{name: "previewing to failed", phase: v1.ReleasePhasePreviewing, next: v1.ReleasePhaseFailed, success: true},
+		{name: "applying to succeeded", phase: v1.ReleasePhaseApplying, next: v1.ReleasePhaseSucceeded, success: true},
+		{name: "applying to failed", phase: v1.ReleasePhaseApplying, next: v1.ReleasePhaseFailed, success: true},
+		{name: "destroying to succeeded", phase: v1.ReleasePhaseDestroying, next: v1.ReleasePhaseSucceeded, success: true},
+		{name: "destroying to failed", phase: v1.ReleasePhaseDestroying, next: v1.ReleasePhaseFailed, success: true},
+		{name: "generating to succeeded", phase: v1.ReleasePhaseGenerating, next: v1.ReleasePhaseSucceeded},
+		{name: "generating to applying", phase: v1.ReleasePhaseGenerating, next: v1.ReleasePhaseApplying},
+		{name: "applying to destroying", phase: v1.ReleasePhaseApplying, next: v1.ReleasePhaseDestroying},
+		{name: "succeeded to failed", phase: v1.ReleasePhaseSucceeded, next: v1.ReleasePhaseFailed},
+		{name: "failed to previewing", phase: v1.ReleasePhaseFailed, next: v1.ReleasePhasePreviewing},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			rel := &v1.Release{Phase: tc.phase}
+			err := SetPhase(rel, tc.next)
+			if tc.success {
+				assert.NoError(t, err)
+				assert.Equal(t, tc.next, rel.Phase)
+				assert.False(t, rel.ModifiedTime.IsZero())
+			} else {
+				assert.ErrorIs(t, err, ErrIllegalPhase)
+				assert.Equal(t, tc.phase, rel.Phase)
+				assert.True(t, rel.ModifiedTime.IsZero())
+			}
+		})
+	}
+
+	// setting the current phase again is a no-op
+	rel := &v1.Release{Phase: v1.ReleasePhaseSucceeded}
+	assert.NoError(t, SetPhase(rel, v1.ReleasePhaseSucceeded))
+	assert.True(t, rel.ModifiedTime.IsZero())
+
+	assert.ErrorIs(t, SetPhase(nil, v1.ReleasePhaseFailed), ErrEmptyRelease)
+}
+
+func TestSetFailed(t *testing.T) {
+	s, err := storages.NewLocalStorage(t.TempDir())
+	require.NoError(t, err)
+
+	failed := &v1.Release{Project: "foo", Workspace: "dev", Revision: 1, Stack: "dev", Phase: v1.ReleasePhaseApplying}
+	require.NoError(t, s.Create(failed))
+	require.NoError(t, SetFailed(failed, "apply failed: connection refused", "v1:Service:default:foo"))
+	require.NoError(t, s.Update(failed))
+
+	succeeded := &v1.Release{Project: "foo", Workspace: "dev", Revision: 2, Stack: "dev", Phase: v1.ReleasePhaseApplying, Message: "stale"}
+	require.NoError(t, s.Create(succeeded))
+	require.NoError(t, SetPhase(succeeded, v1.ReleasePhaseSucceeded))
+	require.NoError(t, s.Update(succeeded))
+
+	// the failure is persisted and surfaced by listing, while the succeeded release has no message
+	releases, err := ListReleases(s)
+	require.NoError(t, err)
+	require.Len(t, releases, 2)
+	assert.Equal(t, v1.ReleasePhaseFailed, releases[0].Phase)
+	assert.Equal(t, "apply failed: connection refused", releases[0].Message)
+	assert.Equal(t, "v1:Service:default:foo", releases[0].FailedResource)
+	assert.Equal(t, v1.ReleasePhaseSucceeded, releases[1].Phase)
+	assert.Empty(t, releases[1].Message)
+	assert.Empty(t, releases[1].FailedResource)
+
+	// a terminal release cannot be failed again
+	assert.ErrorIs(t, SetFailed(succeeded, "late failure", ""), ErrIllegalPhase)
+	assert.Empty(t, succeeded.Message)
+}
+
+func TestUpdateReleaseFailed(t *testing.T) {
+	relLock := &sync.Mutex{}
+
+	// the reason is recorded without a failed resource
+	rel := &v1.Release{Project: "foo", Workspace: "dev", Revision: 1, Stack: "dev", Phase: v1.ReleasePhaseApplying}
+	UpdateReleaseFailed(rel, "generate spec failed", relLock)
+	assert.Equal(t, v1.ReleasePhaseFailed, rel.Phase)
+	assert.Equal(t, "generate spec failed", rel.Message)
+	assert.Empty(t, rel.FailedResource)
+
+	// the failure recorded by the engine is kept
+	rel = &v1.Release{Project: "foo", Workspace: "dev", Revision: 2, Stack: "dev", Phase: v1.ReleasePhaseApplying}
+	require.NoError(t, SetFailed(rel, "apply failed: connection refused", "v1:Service:default:foo"))
+	UpdateReleaseFailed(rel, "apply failed", relLock)
+	assert.Equal(t, "apply failed: connection refused", rel.Message)
+	assert.Equal(t, "v1:Service:default:foo", rel.FailedResource)
+
+	// the terminal release is left unchanged
+	rel = &v1.Release{Project: "foo", Workspace: "dev", Revision: 3, Stack: "dev", Phase: v1.ReleasePhaseSucceeded}
+	UpdateReleaseFailed(rel, "late failure", relLock)
+	assert.Equal(t, v1.ReleasePhaseSucceeded, rel.Phase)
+	assert.Empty(t, rel.Message)
+}
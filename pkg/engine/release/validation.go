@@ -1,24 +1,40 @@
 package release
 
 import (
-	"errors"
 	"fmt"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/go-version"
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
 
 	v1 "kusionstack.io/kusion/pkg/apis/api.kusion.io/v1"
+	"kusionstack.io/kusion/pkg/util/kerrors"
 )
 
 var (
-	ErrEmptyRelease         = errors.New("empty release")
-	ErrEmptyProject         = errors.New("empty project")
-	ErrEmptyWorkspace       = errors.New("empty workspace")
-	ErrEmptyRevision        = errors.New("empty revision")
-	ErrEmptyStack           = errors.New("empty stack")
-	ErrEmptySpec            = errors.New("empty spec")
-	ErrEmptyState           = errors.New("empty state")
-	ErrEmptyPhase           = errors.New("empty phase")
-	ErrEmptyCreateTime      = errors.New("empty create time")
-	ErrEmptyModifiedTime    = errors.New("empty modified time")
-	ErrDuplicateResourceKey = errors.New("duplicate resource key")
+	ErrEmptyRelease         = kerrors.New(kerrors.ErrValidation, "empty release")
+	ErrEmptyProject         = kerrors.New(kerrors.ErrValidation, "empty project")
+	ErrEmptyWorkspace       = kerrors.New(kerrors.ErrValidation, "empty workspace")
+	ErrEmptyRevision        = kerrors.New(kerrors.ErrValidation, "empty revision")
+	ErrEmptyStack           = kerrors.New(kerrors.ErrValidation, "empty stack")
+	ErrEmptySpec            = v1.ErrEmptySpec
+	ErrEmptyState           = kerrors.New(kerrors.ErrValidation, "empty state")
+	ErrEmptyPhase           = kerrors.New(kerrors.ErrValidation, "empty phase")
+	ErrEmptyCreateTime      = kerrors.New(kerrors.ErrValidation, "empty create time")
+	ErrEmptyModifiedTime    = kerrors.New(kerrors.ErrValidation, "empty modified time")
+	ErrIllegalPhase         = kerrors.New(kerrors.ErrValidation, "illegal release phase transition")
+	ErrDuplicateResourceKey = v1.ErrDuplicateResourceKey
+	ErrMissingDependency    = v1.ErrMissingDependency
+	ErrInvalidResourceType  = v1.ErrInvalidResourceType
+	ErrMissingResourceGVK   = v1.ErrMissingResourceGVK
+	ErrInvalidResourceID    = v1.ErrInvalidResourceID
+
+	ErrMissingProviderSource  = kerrors.New(kerrors.ErrValidation, "missing terraform provider source")
+	ErrInvalidProviderSource  = kerrors.New(kerrors.ErrValidation, "invalid terraform provider source")
+	ErrMissingProviderVersion = kerrors.New(kerrors.ErrValidation, "missing terraform provider version")
+	ErrInvalidProviderVersion = kerrors.New(kerrors.ErrValidation, "invalid terraform provider version")
 )
 
 func ValidateRelease(r *v1.Release) error {
@@ -83,6 +99,93 @@ func validateResources(resources v1.Resources) error {
 			return fmt.Errorf("%w: %s", ErrDuplicateResourceKey, key)
 		}
 		resourceKeyMap[key] = true
+		if err := validateResourceType(&resource); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// validateResourceType validates that the resource type is one of the supported types, and the resource
+// satisfies the requirement of the type, see v1.Resource.ValidateType.
+func validateResourceType(resource *v1.Resource) error {
+	return resource.ValidateType()
+}
+
+// ValidateResourceRefs validates that every DependsOn entry of the resources refers to an existing
+// resource, and returns an aggregated error listing all the missing ones.
+func ValidateResourceRefs(resources v1.Resources) error {
+	index := resources.Index()
+	var allErrs []error
+	for _, resource := range resources {
+		for _, dependency := range resource.DependsOn {
+			if _, ok := index[dependency]; !ok {
+				allErrs = append(allErrs, fmt.Errorf("%w: resource %s depends on %s", ErrMissingDependency, resource.ID, dependency))
+			}
+		}
+	}
+	return utilerrors.NewAggregate(allErrs)
+}
+
+// ValidateTerraformProviders validates the providers declared by the Terraform resources, each of which is
+// in the provider extension in the format of <source>/<version>, e.g. registry.terraform.io/hashicorp/aws/5.0.1.
+// The source must be present with at least the namespace and name, and the version must be a parseable
+// version constraint, otherwise Terraform fails with a confusing error. The errors are reported once per
+// provider, naming the resources declaring it, and aggregated across the providers.
+func ValidateTerraformProviders(resources v1.Resources) error {
+	var allErrs []error
+	// the resources declaring each provider, whose key is the provider extension
+	providers := make(map[string][]string)
+	for _, resource := range resources {
+		if resource.Type != v1.Terraform {
+			continue
+		}
+		provider, _ := resource.Extensions["provider"].(string)
+		if provider == "" {
+			allErrs = append(allErrs, fmt.Errorf("%w: resource %s declares no provider", ErrMissingProviderSource, resource.ID))
+			continue
+		}
+		providers[provider] = append(providers[provider], resource.ID)
+	}
+
+	names := make([]string, 0, len(providers))
+	for provider := range providers {
+		names = append(names, provider)
+	}
+	sort.Strings(names)
+	for _, provider := range names {
+		if err := validateTerraformProvider(provider); err != nil {
+			allErrs = append(allErrs, fmt.Errorf("%w, declared by resources: %s", err, strings.Join(providers[provider], ", ")))
+		}
+	}
+	return utilerrors.NewAggregate(allErrs)
+}
+
+// validateTerraformProvider validates the provider extension in the format of <source>/<version>.
+func validateTerraformProvider(provider string) error {
+	parts := strings.Split(provider, "/")
+	source, constraint := parts[:len(parts)-1], parts[len(parts)-1]
+	if constraint == "" {
+		return fmt.Errorf("%w: provider %s", ErrMissingProviderVersion, provider)
+	}
+	if _, err := version.NewConstraint(constraint); err != nil {
+		// a provider consisting of a valid source only misses the version rather than has an invalid one
+		if isProviderSource(parts) {
+			return fmt.Errorf("%w: provider %s", ErrMissingProviderVersion, provider)
+		}
+		return fmt.Errorf("%w: %s of provider %s: %v", ErrInvalidProviderVersion, constraint, provider, err)
+	}
+	if len(source) == 0 {
+		return fmt.Errorf("%w: provider %s", ErrMissingProviderSource, provider)
+	}
+	if !isProviderSource(source) {
+		return fmt.Errorf("%w: %s of provider %s, expected [hostname/]namespace/name",
+			ErrInvalidProviderSource, strings.Join(source, "/"), provider)
 	}
 	return nil
 }
+
+// isProviderSource returns whether the parts make up a provider source of [hostname/]namespace/name.
+func isProviderSource(parts []string) bool {
+	return len(parts) >= 2 && len(parts) <= 3 && !slices.Contains(parts, "")
+}
@@ -288,3 +288,180 @@ func TestValidateResources(t *testing.T) {
 		})
 	}
 }
+
+func TestValidateResourceRefs(t *testing.T) {
+	dependent := mockResource()
+	dependent.ID = "v1:Service:fakeNs:default-dev-foo"
+
+	testcases := []struct {
+		name      string
+		success   bool
+		resources func() v1.Resources
+	}{
+		{
+			name:    "valid resource refs",
+			success: true,
+			resources: func() v1.Resources {
+				res := dependent
+				res.DependsOn = []string{mockResource().ID}
+				return v1.Resources{mockResource(), res}
+			},
+		},
+		{
+			name:    "invalid resource refs missing dependency",
+			success: false,
+			resources: func() v1.Resources {
+				res := dependent
+				res.DependsOn = []string{mockResource().ID, "v1:Secret:fakeNs:not-exist"}
+				return v1.Resources{mockResource(), res}
+			},
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := ValidateResourceRefs(tc.resources())
+			assert.Equal(t, tc.success, err == nil)
+			if !tc.success {
+				assert.ErrorIs(t, err, ErrMissingDependency)
+				assert.Contains(t, err.Error(), "v1:Secret:fakeNs:not-exist")
+			}
+		})
+	}
+}
+
+func TestValidateResourceType(t *testing.T) {
+	testcases := []struct {
+		name     string
+		success  bool
+		resource func() v1.Resource
+		err      error
+	}{
+		{
+			name:     "valid kubernetes resource",
+			success:  true,
+			resource: mockResource,
+		},
+		{
+			name:    "valid terraform resource",
+			success: true,
+			resource: func() v1.Resource {
+				return v1.Resource{
+					ID:   "hashicorp:aws:aws_db_instance:wordpress",
+					Type: v1.Terraform,
+				}
+			},
+		},
+		{
+			name:    "invalid unknown type",
+			success: false,
+			resource: func() v1.Resource {
+				res := mockResource()
+				res.Type = "Kubernetess"
+				return res
+			},
+			err: ErrInvalidResourceType,
+		},
+		{
+			name:    "invalid kubernetes resource missing gvk",
+			success: false,
+			resource: func() v1.Resource {
+				res := mockResource()
+				res.Extensions = nil
+				return res
+			},
+			err: ErrMissingResourceGVK,
+		},
+		{
+			name:    "invalid terraform resource id",
+			success: false,
+			resource: func() v1.Resource {
+				return v1.Resource{
+					ID:   "hashicorp:aws:aws_db_instance",
+					Type: v1.Terraform,
+				}
+			},
+			err: ErrInvalidResourceID,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			resource := tc.resource()
+			err := ValidateSpec(&v1.Spec{Resources: v1.Resources{resource}})
+			assert.Equal(t, tc.success, err == nil)
+			if !tc.success {
+				assert.ErrorIs(t, err, tc.err)
+				assert.Contains(t, err.Error(), resource.ID)
+			}
+		})
+	}
+}
+
+func TestValidateTerraformProviders(t *testing.T) {
+	mockTerraformResource := func(id, provider string) v1.Resource {
+		res := v1.Resource{ID: id, Type: v1.Terraform}
+		if provider != "" {
+			res.Extensions = map[string]any{"provider": provider}
+		}
+		return res
+	}
+
+	testcases := []struct {
+		name      string
+		resources v1.Resources
+		errs      []error
+		errMsg    string
+	}{
+		{
+			name: "well-formed providers",
+			resources: v1.Resources{
+				mockResource(),
+				mockTerraformResource("hashicorp:aws:aws_db_instance:wordpress", "registry.terraform.io/hashicorp/aws/5.0.1"),
+				mockTerraformResource("hashicorp:aws:aws_security_group:wordpress", "registry.terraform.io/hashicorp/aws/5.0.1"),
+				mockTerraformResource("hashicorp:random:random_password:wordpress", "hashicorp/random/>= 3.0"),
+			},
+		},
+		{
+			name: "missing source",
+			resources: v1.Resources{
+				mockTerraformResource("hashicorp:aws:aws_db_instance:wordpress", "5.0.1"),
+				mockTerraformResource("hashicorp:aws:aws_security_group:wordpress", ""),
+			},
+			errs:   []error{ErrMissingProviderSource},
+			errMsg: "resource hashicorp:aws:aws_security_group:wordpress declares no provider",
+		},
+		{
+			name: "missing version",
+			resources: v1.Resources{
+				mockTerraformResource("hashicorp:aws:aws_db_instance:wordpress", "registry.terraform.io/hashicorp/aws"),
+				mockTerraformResource("hashicorp:random:random_password:wordpress", "hashicorp/random/"),
+			},
+			errs:   []error{ErrMissingProviderVersion},
+			errMsg: "provider registry.terraform.io/hashicorp/aws, declared by resources: hashicorp:aws:aws_db_instance:wordpress",
+		},
+		{
+			name: "invalid version and source",
+			resources: v1.Resources{
+				mockTerraformResource("hashicorp:aws:aws_db_instance:wordpress", "a/b/c/d/5.0.1"),
+				mockTerraformResource("hashicorp:random:random_password:wordpress", "registry.terraform.io/hashicorp/random/latest"),
+			},
+			errs:   []error{ErrInvalidProviderSource, ErrInvalidProviderVersion},
+			errMsg: "latest of provider registry.terraform.io/hashicorp/random/latest",
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := ValidateTerraformProviders(tc.resources)
+			if len(tc.errs) == 0 {
+				assert.NoError(t, err)
+				return
+			}
+			for _, e := range tc.errs {
+				assert.ErrorIs(t, err, e)
+			}
+			assert.Contains(t, err.Error(), tc.errMsg)
+		})
+	}
+}
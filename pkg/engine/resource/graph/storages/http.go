@@ -0,0 +1,142 @@
+package storages
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	v1 "kusionstack.io/kusion/pkg/apis/api.kusion.io/v1"
+	"kusionstack.io/kusion/pkg/engine/resource/graph"
+)
+
+// defaultHTTPTimeout is the timeout of the requests if the http client is not specified.
+const defaultHTTPTimeout = 30 * time.Second
+
+// HTTPStorage is an implementation of graph.Storage which uses a remote HTTP release service as storage.
+// The graph is read and written through the REST APIs shown as below, where the url is generated by
+// GenHTTPGraphURL:
+//   - GET    {url}: get the graph.
+//   - HEAD   {url}: check whether the graph exists.
+//   - POST   {url}: create the graph.
+//   - PUT    {url}: update the graph.
+//   - DELETE {url}: delete the graph.
+type HTTPStorage struct {
+	client *http.Client
+
+	// The url of the graph of a specified project and workspace.
+	url string
+
+	// The bearer token to access the release service.
+	token string
+}
+
+// NewHTTPStorage news http graph storage. If the client is nil, a client with the default timeout is used.
+func NewHTTPStorage(client *http.Client, url, token string) (*HTTPStorage, error) {
+	if client == nil {
+		client = &http.Client{Timeout: defaultHTTPTimeout}
+	}
+	return &HTTPStorage{
+		client: client,
+		url:    strings.TrimSuffix(url, "/"),
+		token:  token,
+	}, nil
+}
+
+// Get gets the graph from the release service.
+func (s *HTTPStorage) Get() (*v1.Graph, error) {
+	r := &v1.Graph{}
+	if err := s.do(http.MethodGet, r, r); err != nil {
+		return nil, fmt.Errorf("get graph failed: %w", err)
+	}
+
+	// Index is not stored in the release service, so we need to rebuild it.
+	// Update resource index to use index in the memory.
+	graph.UpdateResourceIndex(r.Resources)
+
+	return r, nil
+}
+
+// Create creates the graph in the release service.
+func (s *HTTPStorage) Create(r *v1.Graph) error {
+	if err := s.do(http.MethodPost, r, nil); err != nil {
+		return fmt.Errorf("create graph failed: %w", err)
+	}
+	return nil
+}
+
+// Update updates the graph in the release service.
+func (s *HTTPStorage) Update(r *v1.Graph) error {
+	if err := s.do(http.MethodPut, r, nil); err != nil {
+		return fmt.Errorf("update graph failed: %w", err)
+	}
+	return nil
+}
+
+// Delete deletes the graph in the release service.
+func (s *HTTPStorage) Delete() error {
+	if err := s.do(http.MethodDelete, nil, nil); err != nil && !errors.Is(err, ErrGraphNotExist) {
+		return fmt.Errorf("delete graph failed: %w", err)
+	}
+	return nil
+}
+
+// CheckGraphStorageExistence checks whether the graph storage exists.
+func (s *HTTPStorage) CheckGraphStorageExistence() bool {
+	return s.do(http.MethodHead, nil, nil) == nil
+}
+
+// do sends the request with the json encoded body (except for GET), and decodes the json response into
+// the output if not nil. The status code 404 and 409 are converted to ErrGraphNotExist and
+// ErrGraphAlreadyExist.
+func (s *HTTPStorage) do(method string, body, output any) error {
+	var reader io.Reader
+	if body != nil && method != http.MethodGet {
+		content, err := json.Marshal(body)
+		if err != nil {
+			return fmt.Errorf("json marshal request body failed: %w", err)
+		}
+		reader = bytes.NewReader(content)
+	}
+
+	req, err := http.NewRequest(method, s.url, reader)
+	if err != nil {
+		return err
+	}
+	if reader != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if s.token != "" {
+		req.Header.Set("Authorization", "Bearer "+s.token)
+	}
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+
+	switch {
+	case resp.StatusCode == http.StatusNotFound:
+		return ErrGraphNotExist
+	case resp.StatusCode == http.StatusConflict:
+		return ErrGraphAlreadyExist
+	case resp.StatusCode < http.StatusOK || resp.StatusCode >= http.StatusMultipleChoices:
+		content, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("unexpected status code %d: %s", resp.StatusCode, string(content))
+	}
+
+	if output == nil {
+		return nil
+	}
+	if err = json.NewDecoder(resp.Body).Decode(output); err != nil {
+		return fmt.Errorf("json unmarshal response body failed: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,88 @@
+package storages
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	v1 "kusionstack.io/kusion/pkg/apis/api.kusion.io/v1"
+)
+
+const mockHTTPToken = "fake-token"
+
+// mockGraphServer is an in-memory HTTP graph service for testing.
+type mockGraphServer struct {
+	mu    sync.Mutex
+	graph []byte
+}
+
+func (m *mockGraphServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if r.Header.Get("Authorization") != "Bearer "+mockHTTPToken {
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+	if r.URL.Path != "/resources/test_project/test_ws" {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet, http.MethodHead:
+		if m.graph == nil {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		_, _ = w.Write(m.graph)
+	case http.MethodPost:
+		if m.graph != nil {
+			w.WriteHeader(http.StatusConflict)
+			return
+		}
+		m.graph, _ = io.ReadAll(r.Body)
+	case http.MethodPut:
+		if m.graph == nil {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		m.graph, _ = io.ReadAll(r.Body)
+	case http.MethodDelete:
+		m.graph = nil
+	}
+}
+
+func TestHTTPStorage(t *testing.T) {
+	server := httptest.NewServer(&mockGraphServer{})
+	defer server.Close()
+
+	s, err := NewHTTPStorage(server.Client(), GenHTTPGraphURL(server.URL, "test_project", "test_ws"), mockHTTPToken)
+	assert.NoError(t, err)
+	assert.False(t, s.CheckGraphStorageExistence())
+
+	g := &v1.Graph{Project: "test_project", Workspace: "test_ws", Resources: &v1.GraphResources{}}
+	assert.NoError(t, s.Create(g))
+	assert.ErrorIs(t, s.Create(g), ErrGraphAlreadyExist)
+	assert.True(t, s.CheckGraphStorageExistence())
+
+	g.Resources.OtherResources = map[string]*v1.GraphResource{
+		"v1:Namespace:test": {ID: "v1:Namespace:test", Type: "Kubernetes"},
+	}
+	assert.NoError(t, s.Update(g))
+	got, err := s.Get()
+	assert.NoError(t, err)
+	assert.Equal(t, g.Resources.OtherResources, got.Resources.OtherResources)
+
+	assert.NoError(t, s.Delete())
+	assert.False(t, s.CheckGraphStorageExistence())
+	assert.ErrorIs(t, s.Update(g), ErrGraphNotExist)
+
+	unauthorized, err := NewHTTPStorage(server.Client(), GenHTTPGraphURL(server.URL, "test_project", "test_ws"), "")
+	assert.NoError(t, err)
+	assert.Error(t, unauthorized.Create(g))
+}
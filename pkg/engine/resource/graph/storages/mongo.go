@@ -0,0 +1,169 @@
+package storages
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	v1 "kusionstack.io/kusion/pkg/apis/api.kusion.io/v1"
+	"kusionstack.io/kusion/pkg/engine/resource/graph"
+)
+
+const (
+	mongoGraphCollection = "graphs"
+
+	// DefaultMongoTimeout is the default timeout of each operation on MongoDB.
+	DefaultMongoTimeout = 30 * time.Second
+)
+
+// MongoStorage is an implementation of graph.Storage which uses MongoDB as storage. The graphs are
+// stored in the collection graphs with a unique index on (project, workspace).
+type MongoStorage struct {
+	graphs *mongo.Collection
+
+	// The timeout of each operation on MongoDB.
+	timeout time.Duration
+
+	project, workspace string
+}
+
+// mongoGraph is the document of a graph stored in MongoDB, where the graph is kept as its json
+// representation.
+type mongoGraph struct {
+	Project   string `bson:"project"`
+	Workspace string `bson:"workspace"`
+	Content   string `bson:"content"`
+}
+
+// NewMongoStorage news mongo graph storage, and creates the unique index of the graphs collection if
+// not exists. Each operation on MongoDB is bounded by the timeout, and DefaultMongoTimeout is used if
+// the timeout is not positive.
+func NewMongoStorage(db *mongo.Database, timeout time.Duration, project, workspace string) (*MongoStorage, error) {
+	if timeout <= 0 {
+		timeout = DefaultMongoTimeout
+	}
+	s := &MongoStorage{
+		graphs:    db.Collection(mongoGraphCollection),
+		timeout:   timeout,
+		project:   project,
+		workspace: workspace,
+	}
+
+	// create the unique index of the graphs
+	ctx, cancel := s.newContext()
+	defer cancel()
+	_, err := s.graphs.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys:    bson.D{{Key: "project", Value: 1}, {Key: "workspace", Value: 1}},
+		Options: options.Index().SetUnique(true),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("create graphs index failed: %w", err)
+	}
+	return s, nil
+}
+
+// Get gets the graph from MongoDB.
+func (s *MongoStorage) Get() (*v1.Graph, error) {
+	ctx, cancel := s.newContext()
+	defer cancel()
+	doc := &mongoGraph{}
+	err := s.graphs.FindOne(ctx, s.filter()).Decode(doc)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, ErrGraphNotExist
+	} else if err != nil {
+		return nil, fmt.Errorf("find graph failed: %w", err)
+	}
+
+	r := &v1.Graph{}
+	if err = json.Unmarshal([]byte(doc.Content), r); err != nil {
+		return nil, fmt.Errorf("json unmarshal graph failed: %w", err)
+	}
+
+	// Index is not stored in MongoDB, so we need to rebuild it.
+	// Update resource index to use index in the memory.
+	graph.UpdateResourceIndex(r.Resources)
+
+	return r, nil
+}
+
+// Create creates the graph in MongoDB.
+func (s *MongoStorage) Create(r *v1.Graph) error {
+	doc, err := s.document(r)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := s.newContext()
+	defer cancel()
+	if _, err = s.graphs.InsertOne(ctx, doc); err != nil {
+		if mongo.IsDuplicateKeyError(err) {
+			return ErrGraphAlreadyExist
+		}
+		return fmt.Errorf("insert graph failed: %w", err)
+	}
+	return nil
+}
+
+// Update updates the graph in MongoDB.
+func (s *MongoStorage) Update(r *v1.Graph) error {
+	doc, err := s.document(r)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := s.newContext()
+	defer cancel()
+	result, err := s.graphs.ReplaceOne(ctx, s.filter(), doc)
+	if err != nil {
+		return fmt.Errorf("replace graph failed: %w", err)
+	}
+	if result.MatchedCount == 0 {
+		return ErrGraphNotExist
+	}
+	return nil
+}
+
+// Delete deletes the graph in MongoDB.
+func (s *MongoStorage) Delete() error {
+	ctx, cancel := s.newContext()
+	defer cancel()
+	if _, err := s.graphs.DeleteOne(ctx, s.filter()); err != nil {
+		return fmt.Errorf("delete graph failed: %w", err)
+	}
+	return nil
+}
+
+// CheckGraphStorageExistence checks whether the graph exists in MongoDB.
+func (s *MongoStorage) CheckGraphStorageExistence() bool {
+	ctx, cancel := s.newContext()
+	defer cancel()
+	count, err := s.graphs.CountDocuments(ctx, s.filter())
+	return err == nil && count > 0
+}
+
+func (s *MongoStorage) document(r *v1.Graph) (*mongoGraph, error) {
+	content, err := json.Marshal(r)
+	if err != nil {
+		return nil, fmt.Errorf("json marshal graph failed: %w", err)
+	}
+	return &mongoGraph{
+		Project:   s.project,
+		Workspace: s.workspace,
+		Content:   string(content),
+	}, nil
+}
+
+// newContext returns the context of a single operation on MongoDB, which is bounded by the timeout.
+func (s *MongoStorage) newContext() (context.Context, context.CancelFunc) {
+	return context.WithTimeout(context.Background(), s.timeout)
+}
+
+func (s *MongoStorage) filter() bson.M {
+	return bson.M{"project": s.project, "workspace": s.workspace}
+}
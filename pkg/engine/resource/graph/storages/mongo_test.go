@@ -0,0 +1,79 @@
+package storages
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/integration/mtest"
+
+	v1 "kusionstack.io/kusion/pkg/apis/api.kusion.io/v1"
+)
+
+const mockMongoNamespace = "kusion.graphs"
+
+func newMockMongoStorage(mt *mtest.T) *MongoStorage {
+	mt.AddMockResponses(mtest.CreateSuccessResponse())
+	s, err := NewMongoStorage(mt.DB, time.Second, "test_project", "test_ws")
+	require.NoError(mt, err)
+	return s
+}
+
+func TestMongoStorage_Get(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+
+	mt.Run("get graph successfully", func(mt *mtest.T) {
+		s := newMockMongoStorage(mt)
+		mt.AddMockResponses(mtest.CreateCursorResponse(0, mockMongoNamespace, mtest.FirstBatch, bson.D{
+			{Key: "project", Value: "test_project"},
+			{Key: "workspace", Value: "test_ws"},
+			{Key: "content", Value: `{"Project":"test_project","Workspace":"test_ws","Resources":{}}`},
+		}))
+
+		g, err := s.Get()
+		assert.NoError(mt, err)
+		assert.Equal(mt, "test_project", g.Project)
+	})
+
+	mt.Run("failed to get graph not exist", func(mt *mtest.T) {
+		s := newMockMongoStorage(mt)
+		mt.AddMockResponses(mtest.CreateCursorResponse(0, mockMongoNamespace, mtest.FirstBatch))
+
+		_, err := s.Get()
+		assert.ErrorIs(mt, err, ErrGraphNotExist)
+	})
+}
+
+func TestMongoStorage_Create(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+
+	mt.Run("create graph successfully", func(mt *mtest.T) {
+		s := newMockMongoStorage(mt)
+		mt.AddMockResponses(mtest.CreateSuccessResponse())
+
+		err := s.Create(&v1.Graph{Project: "test_project", Workspace: "test_ws"})
+		assert.NoError(mt, err)
+	})
+
+	mt.Run("failed to create graph already exist", func(mt *mtest.T) {
+		s := newMockMongoStorage(mt)
+		mt.AddMockResponses(mtest.CreateWriteErrorsResponse(mtest.WriteError{Index: 0, Code: 11000, Message: "duplicate key"}))
+
+		err := s.Create(&v1.Graph{Project: "test_project", Workspace: "test_ws"})
+		assert.ErrorIs(mt, err, ErrGraphAlreadyExist)
+	})
+}
+
+func TestMongoStorage_Update(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+
+	mt.Run("failed to update graph not exist", func(mt *mtest.T) {
+		s := newMockMongoStorage(mt)
+		mt.AddMockResponses(mtest.CreateSuccessResponse(bson.E{Key: "n", Value: 0}, bson.E{Key: "nModified", Value: 0}))
+
+		err := s.Update(&v1.Graph{Project: "test_project", Workspace: "test_ws"})
+		assert.ErrorIs(mt, err, ErrGraphNotExist)
+	})
+}
@@ -0,0 +1,172 @@
+package storages
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	v1 "kusionstack.io/kusion/pkg/apis/api.kusion.io/v1"
+	"kusionstack.io/kusion/pkg/engine/resource/graph"
+)
+
+const (
+	// SQLDialectMysql is the dialect of MySQL, whose placeholder of the bind variables is "?".
+	SQLDialectMysql = "mysql"
+
+	// SQLDialectPostgres is the dialect of Postgres, whose placeholder of the bind variables is "$N".
+	SQLDialectPostgres = "postgres"
+
+	sqlGraphTable = "graphs"
+)
+
+// SQLStorage is an implementation of graph.Storage which uses a relational database as storage.
+// The graphs are stored in the table graphs, whose primary key is (project, workspace).
+type SQLStorage struct {
+	db *sql.DB
+
+	// The dialect of the database, which decides the placeholder of the bind variables.
+	dialect string
+
+	project, workspace string
+}
+
+// NewSQLStorage news sql graph storage, and creates the graphs table if not exists. The db should be
+// opened with a driver of the specified dialect.
+func NewSQLStorage(db *sql.DB, dialect, project, workspace string) (*SQLStorage, error) {
+	if dialect != SQLDialectMysql && dialect != SQLDialectPostgres {
+		return nil, fmt.Errorf("unsupported sql dialect %s, only %s and %s are supported", dialect, SQLDialectMysql, SQLDialectPostgres)
+	}
+	s := &SQLStorage{
+		db:        db,
+		dialect:   dialect,
+		project:   project,
+		workspace: workspace,
+	}
+
+	// create the graphs table
+	if _, err := s.db.Exec(s.createTableStatement()); err != nil {
+		return nil, fmt.Errorf("create graphs table failed: %w", err)
+	}
+	return s, nil
+}
+
+// Get gets the graph from the database.
+func (s *SQLStorage) Get() (*v1.Graph, error) {
+	var content string
+	err := s.db.QueryRow(
+		s.rebind(fmt.Sprintf("SELECT content FROM %s WHERE project = ? AND workspace = ?", sqlGraphTable)),
+		s.project, s.workspace,
+	).Scan(&content)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrGraphNotExist
+	} else if err != nil {
+		return nil, fmt.Errorf("query graph failed: %w", err)
+	}
+
+	r := &v1.Graph{}
+	if err = json.Unmarshal([]byte(content), r); err != nil {
+		return nil, fmt.Errorf("json unmarshal graph failed: %w", err)
+	}
+
+	// Index is not stored in the database, so we need to rebuild it.
+	// Update resource index to use index in the memory.
+	graph.UpdateResourceIndex(r.Resources)
+
+	return r, nil
+}
+
+// Create creates the graph in the database.
+func (s *SQLStorage) Create(r *v1.Graph) error {
+	content, err := json.Marshal(r)
+	if err != nil {
+		return fmt.Errorf("json marshal graph failed: %w", err)
+	}
+
+	_, err = s.db.Exec(
+		s.rebind(fmt.Sprintf("INSERT INTO %s (project, workspace, content) VALUES (?, ?, ?)", sqlGraphTable)),
+		s.project, s.workspace, string(content),
+	)
+	if err != nil {
+		// the insertion violating the primary key means the graph has been created by others
+		if s.CheckGraphStorageExistence() {
+			return ErrGraphAlreadyExist
+		}
+		return fmt.Errorf("insert graph failed: %w", err)
+	}
+	return nil
+}
+
+// Update updates the graph in the database.
+func (s *SQLStorage) Update(r *v1.Graph) error {
+	content, err := json.Marshal(r)
+	if err != nil {
+		return fmt.Errorf("json marshal graph failed: %w", err)
+	}
+
+	result, err := s.db.Exec(
+		s.rebind(fmt.Sprintf("UPDATE %s SET content = ? WHERE project = ? AND workspace = ?", sqlGraphTable)),
+		string(content), s.project, s.workspace,
+	)
+	if err != nil {
+		return fmt.Errorf("update graph failed: %w", err)
+	}
+	if affected, err := result.RowsAffected(); err == nil && affected == 0 {
+		return ErrGraphNotExist
+	}
+	return nil
+}
+
+// Delete deletes the graph in the database.
+func (s *SQLStorage) Delete() error {
+	_, err := s.db.Exec(
+		s.rebind(fmt.Sprintf("DELETE FROM %s WHERE project = ? AND workspace = ?", sqlGraphTable)),
+		s.project, s.workspace,
+	)
+	if err != nil {
+		return fmt.Errorf("delete graph failed: %w", err)
+	}
+	return nil
+}
+
+// CheckGraphStorageExistence checks whether the graph exists in the database.
+func (s *SQLStorage) CheckGraphStorageExistence() bool {
+	var count int
+	err := s.db.QueryRow(
+		s.rebind(fmt.Sprintf("SELECT COUNT(*) FROM %s WHERE project = ? AND workspace = ?", sqlGraphTable)),
+		s.project, s.workspace,
+	).Scan(&count)
+	return err == nil && count > 0
+}
+
+func (s *SQLStorage) createTableStatement() string {
+	contentType := "TEXT"
+	if s.dialect == SQLDialectMysql {
+		contentType = "LONGTEXT"
+	}
+	return fmt.Sprintf(`CREATE TABLE IF NOT EXISTS %s (
+	project VARCHAR(255) NOT NULL,
+	workspace VARCHAR(255) NOT NULL,
+	content %s NOT NULL,
+	PRIMARY KEY (project, workspace)
+)`, sqlGraphTable, contentType)
+}
+
+// rebind replaces the placeholders "?" in the query with "$N" for Postgres.
+func (s *SQLStorage) rebind(query string) string {
+	if s.dialect != SQLDialectPostgres {
+		return query
+	}
+	var b strings.Builder
+	n := 0
+	for _, c := range query {
+		if c == '?' {
+			n++
+			fmt.Fprintf(&b, "$%d", n)
+			continue
+		}
+		b.WriteRune(c)
+	}
+	return b.String()
+}
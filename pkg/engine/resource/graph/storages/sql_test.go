@@ -0,0 +1,116 @@
+package storages
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	v1 "kusionstack.io/kusion/pkg/apis/api.kusion.io/v1"
+)
+
+func newMockSQLStorage(t *testing.T, dialect string) (*SQLStorage, sqlmock.Sqlmock) {
+	db, sqlMock, err := sqlmock.New()
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		_ = db.Close()
+	})
+
+	sqlMock.ExpectExec("CREATE TABLE IF NOT EXISTS graphs").WillReturnResult(sqlmock.NewResult(0, 0))
+	s, err := NewSQLStorage(db, dialect, "test_project", "test_ws")
+	require.NoError(t, err)
+	return s, sqlMock
+}
+
+func TestSQLStorage_Get(t *testing.T) {
+	testcases := []struct {
+		name    string
+		success bool
+		rows    *sqlmock.Rows
+	}{
+		{
+			name:    "get graph successfully",
+			success: true,
+			rows:    sqlmock.NewRows([]string{"content"}).AddRow(`{"Project":"test_project","Workspace":"test_ws","Resources":{}}`),
+		},
+		{
+			name:    "failed to get not exist graph",
+			success: false,
+			rows:    sqlmock.NewRows([]string{"content"}),
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			s, sqlMock := newMockSQLStorage(t, SQLDialectPostgres)
+			sqlMock.ExpectQuery(`SELECT content FROM graphs WHERE project = \$1 AND workspace = \$2`).
+				WithArgs("test_project", "test_ws").
+				WillReturnRows(tc.rows)
+
+			g, err := s.Get()
+			assert.Equal(t, tc.success, err == nil)
+			if tc.success {
+				assert.Equal(t, "test_project", g.Project)
+			} else {
+				assert.ErrorIs(t, err, ErrGraphNotExist)
+			}
+			assert.NoError(t, sqlMock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestSQLStorage_Create(t *testing.T) {
+	testcases := []struct {
+		name      string
+		insertErr error
+		count     int
+		expected  error
+	}{
+		{
+			name: "create graph successfully",
+		},
+		{
+			name:      "failed to create graph created by others",
+			insertErr: errors.New("duplicate entry"),
+			count:     1,
+			expected:  ErrGraphAlreadyExist,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			s, sqlMock := newMockSQLStorage(t, SQLDialectMysql)
+			insert := sqlMock.ExpectExec(`INSERT INTO graphs \(project, workspace, content\) VALUES \(\?, \?, \?\)`).
+				WithArgs("test_project", "test_ws", sqlmock.AnyArg())
+			if tc.insertErr != nil {
+				insert.WillReturnError(tc.insertErr)
+				sqlMock.ExpectQuery(`SELECT COUNT\(\*\) FROM graphs`).
+					WithArgs("test_project", "test_ws").
+					WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(tc.count))
+			} else {
+				insert.WillReturnResult(sqlmock.NewResult(1, 1))
+			}
+
+			err := s.Create(&v1.Graph{Project: "test_project", Workspace: "test_ws"})
+			if tc.expected == nil {
+				assert.NoError(t, err)
+			} else {
+				assert.ErrorIs(t, err, tc.expected)
+			}
+			assert.NoError(t, sqlMock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestSQLStorage_Update(t *testing.T) {
+	s, sqlMock := newMockSQLStorage(t, SQLDialectMysql)
+	sqlMock.ExpectExec(`UPDATE graphs SET content = \? WHERE project = \? AND workspace = \?`).
+		WithArgs(sqlmock.AnyArg(), "test_project", "test_ws").
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	err := s.Update(&v1.Graph{Project: "test_project", Workspace: "test_ws"})
+	assert.ErrorIs(t, err, ErrGraphNotExist)
+	assert.NoError(t, sqlMock.ExpectationsWereMet())
+}
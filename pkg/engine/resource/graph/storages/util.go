@@ -1,10 +1,11 @@
 package storages
 
 import (
-	"errors"
 	"fmt"
 	"path/filepath"
 	"strings"
+
+	"kusionstack.io/kusion/pkg/util/kerrors"
 )
 
 const (
@@ -13,8 +14,8 @@ const (
 )
 
 var (
-	ErrGraphNotExist     = errors.New("graph does not exist")
-	ErrGraphAlreadyExist = errors.New("graph has already existed")
+	ErrGraphNotExist     = kerrors.New(kerrors.ErrNotFound, "graph does not exist")
+	ErrGraphAlreadyExist = kerrors.New(kerrors.ErrAlreadyExists, "graph has already existed")
 )
 
 // GenResourceDirPath generates the resource dir path, which is used for LocalStorage.
@@ -32,6 +33,12 @@ func GenGenericOssResourcePrefixKey(prefix, project, workspace string) string {
 	return fmt.Sprintf("%s%s/%s/%s", prefix, resourcesPrefix, project, workspace)
 }
 
+// GenHTTPGraphURL generates the url of the graph of the specified project and workspace, which is used
+// for HTTPStorage.
+func GenHTTPGraphURL(baseURL, project, workspace string) string {
+	return fmt.Sprintf("%s/%s/%s/%s", strings.TrimSuffix(baseURL, "/"), resourcesPrefix, project, workspace)
+}
+
 // GenResourcePrefixKeyWithPath generates oss state file key with cloud and env instead of workspace, which is use for OssStorage and S3Storage.
 func GenResourcePrefixKeyWithPath(prefix, path string) string {
 	prefix = strings.TrimPrefix(prefix, "/")
@@ -12,7 +12,6 @@ import (
 	"kusionstack.io/kusion/pkg/engine/operation/graph"
 	"kusionstack.io/kusion/pkg/engine/runtime"
 	"kusionstack.io/kusion/pkg/engine/runtime/kubernetes"
-	"kusionstack.io/kusion/pkg/engine/runtime/kubernetes/kubeops"
 	"kusionstack.io/kusion/pkg/engine/runtime/terraform"
 	"kusionstack.io/kusion/pkg/secrets"
 	"kusionstack.io/kusion/pkg/workspace"
@@ -23,14 +22,6 @@ var SupportRuntimes = map[apiv1.Type]InitFn{
 	runtime.Terraform:  terraform.NewTerraformRuntime,
 }
 
-var contextKeys = []string{
-	kubeops.KubeConfigContentKey,
-	apiv1.EnvAwsAccessKeyID,
-	apiv1.EnvAwsSecretAccessKey,
-	apiv1.EnvAlicloudAccessKey,
-	apiv1.EnvAlicloudSecretKey,
-}
-
 // InitFn runtime init func
 type InitFn func(spec apiv1.Spec) (runtime.Runtime, error)
 
@@ -63,7 +54,6 @@ func Runtimes(spec apiv1.Spec, state apiv1.State) (map[apiv1.Type]runtime.Runtim
 }
 
 func validResources(resources apiv1.Resources) v1.Status {
-	var kubeConfig string
 	for _, resource := range resources {
 		rt := resource.Type
 		if rt == "" {
@@ -73,15 +63,6 @@ func validResources(resources apiv1.Resources) v1.Status {
 			return v1.NewErrorStatusWithCode(v1.IllegalManifest, fmt.Errorf("unknown resource type: %s. Currently supported resource types are: %v",
 				rt, reflect.ValueOf(SupportRuntimes).MapKeys()))
 		}
-		if rt == apiv1.Kubernetes {
-			config := kubeops.GetKubeConfig(&resource)
-			if kubeConfig != "" && kubeConfig != config {
-				return v1.NewErrorStatusWithCode(v1.IllegalManifest, fmt.Errorf("different kubeConfig in different resources"))
-			}
-			if kubeConfig == "" {
-				kubeConfig = config
-			}
-		}
 	}
 	return nil
 }
@@ -96,7 +77,7 @@ func parseContextSecretRef(spec *apiv1.Spec) error {
 	}
 
 	// Retrieve the context with the specified keys from spec and parse the external secret ref.
-	for _, key := range contextKeys {
+	for _, key := range graph.ContextSecretRefKeys {
 		contextStr, err := workspace.GetStringFromGenericConfig(spec.Context, key)
 		if err != nil {
 			return err
@@ -118,7 +99,7 @@ func parseContextSecretRef(spec *apiv1.Spec) error {
 				if err != nil {
 					return err
 				}
-				secretData, err := secretStore.GetSecret(context.Background(), *externalSecretRef)
+				secretData, err := secrets.GetSecret(context.Background(), secretStore, *externalSecretRef)
 				if err != nil {
 					return err
 				}
@@ -63,8 +63,8 @@ func TestValidResources(t *testing.T) {
 			},
 		},
 		{
-			name:    "invalid resources multiple kubeConfig",
-			success: false,
+			name:    "valid resources multiple kubeConfig",
+			success: true,
 			resources: []apiv1.Resource{
 				{
 					ID:   "mock-id",
@@ -88,6 +88,38 @@ func TestValidResources(t *testing.T) {
 				},
 			},
 		},
+		{
+			name:    "valid resources kubeConfig not a path failed by the runtime",
+			success: true,
+			resources: []apiv1.Resource{
+				{
+					ID:   "mock-id",
+					Type: "Kubernetes",
+					Attributes: map[string]any{
+						"mock-key": "mock-value",
+					},
+					Extensions: map[string]any{
+						"kubeConfig": map[string]any{"path": "/etc/kubeConfig.yaml"},
+					},
+				},
+			},
+		},
+		{
+			name:    "valid resources empty kubeConfig failed by the runtime",
+			success: true,
+			resources: []apiv1.Resource{
+				{
+					ID:   "mock-id",
+					Type: "Kubernetes",
+					Attributes: map[string]any{
+						"mock-key": "mock-value",
+					},
+					Extensions: map[string]any{
+						"kubeConfig": "",
+					},
+				},
+			},
+		},
 	}
 
 	for _, tc := range testcases {
@@ -34,8 +34,7 @@ func GetKubeConfig(resource *apiv1.Resource) string {
 		return kubeConfigFile
 	}
 	if resource != nil {
-		kubeConfig, ok := resource.Extensions[apiv1.ResourceExtensionKubeConfig].(string)
-		if ok && kubeConfig != "" {
+		if kubeConfig, ok := resource.KubeConfigPath(); ok {
 			kubeConfigFile, _ := filepath.Abs(kubeConfig)
 			if kubeConfigFile != "" {
 				return kubeConfigFile
@@ -0,0 +1,285 @@
+package kubernetes
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	k8sruntime "k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/strategicpatch"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/dynamic/fake"
+	k8stesting "k8s.io/client-go/testing"
+
+	apiv1 "kusionstack.io/kusion/pkg/apis/api.kusion.io/v1"
+	"kusionstack.io/kusion/pkg/engine/runtime"
+)
+
+func newFakeKubernetesRuntime() *KubernetesRuntime {
+	client := fake.NewSimpleDynamicClient(k8sruntime.NewScheme())
+	// assign the server generated fields on creation
+	client.PrependReactor("create", "configmaps", func(action k8stesting.Action) (bool, k8sruntime.Object, error) {
+		obj := action.(k8stesting.CreateAction).GetObject().(*unstructured.Unstructured)
+		obj.SetUID(types.UID("b4f9c6c2-4f59-4b8b-9d4c-7d0c2a6f0a1e"))
+		obj.SetResourceVersion("1")
+		return false, nil, nil
+	})
+
+	// apply the strategic merge patch with the patch metadata of the typed object like the server, which
+	// is not supported by the fake client for the unstructured objects
+	client.PrependReactor("patch", "*", func(action k8stesting.Action) (bool, k8sruntime.Object, error) {
+		patchAction := action.(k8stesting.PatchAction)
+		if patchAction.GetPatchType() != types.StrategicMergePatchType {
+			return false, nil, nil
+		}
+		obj, err := client.Tracker().Get(patchAction.GetResource(), patchAction.GetNamespace(), patchAction.GetName())
+		if err != nil {
+			return true, nil, err
+		}
+		live := obj.(*unstructured.Unstructured)
+		typed, ok := newTypedObject(live.GroupVersionKind())
+		if !ok {
+			return true, nil, fmt.Errorf("unknown type %s", live.GroupVersionKind())
+		}
+		current, err := live.MarshalJSON()
+		if err != nil {
+			return true, nil, err
+		}
+		merged, err := strategicpatch.StrategicMergePatch(current, patchAction.GetPatch(), typed)
+		if err != nil {
+			return true, nil, err
+		}
+		res := &unstructured.Unstructured{}
+		if err = res.UnmarshalJSON(merged); err != nil {
+			return true, nil, err
+		}
+		return true, res, client.Tracker().Update(patchAction.GetResource(), res, patchAction.GetNamespace())
+	})
+	// default the container fields on creation like the server
+	client.PrependReactor("create", "deployments", func(action k8stesting.Action) (bool, k8sruntime.Object, error) {
+		obj := action.(k8stesting.CreateAction).GetObject().(*unstructured.Unstructured)
+		containers, _, _ := unstructured.NestedSlice(obj.Object, "spec", "template", "spec", "containers")
+		for _, c := range containers {
+			c.(map[string]interface{})["terminationMessagePath"] = "/dev/termination-log"
+		}
+		_ = unstructured.SetNestedSlice(obj.Object, containers, "spec", "template", "spec", "containers")
+		return false, nil, nil
+	})
+
+	mapper := meta.NewDefaultRESTMapper(nil)
+	mapper.Add(schema.GroupVersionKind{Version: "v1", Kind: "ConfigMap"}, meta.RESTScopeNamespace)
+	mapper.Add(schema.GroupVersionKind{Group: "apps", Version: "v1", Kind: "Deployment"}, meta.RESTScopeNamespace)
+	return &KubernetesRuntime{client: client, mapper: mapper}
+}
+
+func newConfigMapResource(data map[string]interface{}) *apiv1.Resource {
+	return &apiv1.Resource{
+		ID:   "v1:ConfigMap:default:foo",
+		Type: apiv1.Kubernetes,
+		Attributes: map[string]interface{}{
+			"apiVersion": "v1",
+			"kind":       "ConfigMap",
+			"metadata": map[string]interface{}{
+				"name":      "foo",
+				"namespace": "default",
+			},
+			"data": data,
+		},
+		Extensions: map[string]interface{}{
+			apiv1.ResourceExtensionGVK: "/v1, Kind=ConfigMap",
+		},
+	}
+}
+
+func TestKubernetesRuntime_ApplySavesLiveObject(t *testing.T) {
+	rt := newFakeKubernetesRuntime()
+
+	// create
+	plan := newConfigMapResource(map[string]interface{}{"key": "v1"})
+	response := rt.Apply(context.Background(), &runtime.ApplyRequest{PlanResource: plan})
+	require.Nil(t, response.Status)
+	state := response.Resource
+	obj := &unstructured.Unstructured{Object: state.Attributes}
+	assert.Equal(t, types.UID("b4f9c6c2-4f59-4b8b-9d4c-7d0c2a6f0a1e"), obj.GetUID())
+	assert.Equal(t, "1", obj.GetResourceVersion())
+	assert.Equal(t, plan.Attributes, state.Extensions[apiv1.ResourceExtensionLastApplied])
+	assert.Equal(t, "/v1, Kind=ConfigMap", state.Extensions[apiv1.ResourceExtensionGVK])
+	assert.NotContains(t, plan.Extensions, apiv1.ResourceExtensionLastApplied)
+
+	// update with the saved State as the prior, whose server generated fields are not patched
+	plan = newConfigMapResource(map[string]interface{}{"key": "v2"})
+	response = rt.Apply(context.Background(), &runtime.ApplyRequest{PriorResource: state, PlanResource: plan})
+	require.Nil(t, response.Status)
+	obj = &unstructured.Unstructured{Object: response.Resource.Attributes}
+	assert.Equal(t, types.UID("b4f9c6c2-4f59-4b8b-9d4c-7d0c2a6f0a1e"), obj.GetUID())
+	assert.Equal(t, map[string]interface{}{"key": "v2"}, obj.Object["data"])
+	assert.Equal(t, plan.Attributes, response.Resource.Extensions[apiv1.ResourceExtensionLastApplied])
+
+	// dry run doesn't save the server generated fields
+	response = rt.Apply(context.Background(), &runtime.ApplyRequest{PriorResource: state, PlanResource: plan, DryRun: true})
+	require.Nil(t, response.Status)
+	obj = &unstructured.Unstructured{Object: response.Resource.Attributes}
+	assert.Empty(t, obj.GetUID())
+	assert.NotContains(t, response.Resource.Extensions, apiv1.ResourceExtensionLastApplied)
+}
+
+func TestKubernetesRuntime_ApplyClientSideDryRun(t *testing.T) {
+	rt := newFakeKubernetesRuntime()
+	plan := newConfigMapResource(map[string]interface{}{"key": "v1", "removed": "v1"})
+	response := rt.Apply(context.Background(), &runtime.ApplyRequest{PlanResource: plan})
+	require.Nil(t, response.Status)
+	state := response.Resource
+
+	// fall back to the client side dry run if the server side one fails
+	rt.client.(*fake.FakeDynamicClient).PrependReactor("patch", "configmaps", func(action k8stesting.Action) (bool, k8sruntime.Object, error) {
+		return true, nil, errors.New("server side dry run is not supported")
+	})
+	plan = newConfigMapResource(map[string]interface{}{"key": "v2"})
+	response = rt.Apply(context.Background(), &runtime.ApplyRequest{PriorResource: state, PlanResource: plan, DryRun: true})
+	require.Nil(t, response.Status)
+	obj := &unstructured.Unstructured{Object: response.Resource.Attributes}
+	assert.Equal(t, map[string]interface{}{"key": "v2"}, obj.Object["data"])
+}
+
+func newDeploymentResource(image string) *apiv1.Resource {
+	return &apiv1.Resource{
+		ID:   "apps/v1:Deployment:default:foo",
+		Type: apiv1.Kubernetes,
+		Attributes: map[string]interface{}{
+			"apiVersion": "apps/v1",
+			"kind":       "Deployment",
+			"metadata": map[string]interface{}{
+				"name":      "foo",
+				"namespace": "default",
+			},
+			"spec": map[string]interface{}{
+				"template": map[string]interface{}{
+					"spec": map[string]interface{}{
+						"containers": []interface{}{
+							map[string]interface{}{"name": "foo", "image": image},
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func TestKubernetesRuntime_ApplyStrategicMergePatch(t *testing.T) {
+	expected := []interface{}{
+		map[string]interface{}{
+			"name":                   "foo",
+			"image":                  "nginx:2",
+			"terminationMessagePath": "/dev/termination-log",
+		},
+	}
+
+	for _, dryRun := range []bool{false, true} {
+		rt := newFakeKubernetesRuntime()
+		response := rt.Apply(context.Background(), &runtime.ApplyRequest{PlanResource: newDeploymentResource("nginx:1")})
+		require.Nil(t, response.Status)
+		state := response.Resource
+
+		if dryRun {
+			// fall back to the client side dry run
+			rt.client.(*fake.FakeDynamicClient).PrependReactor("patch", "deployments", func(action k8stesting.Action) (bool, k8sruntime.Object, error) {
+				return true, nil, errors.New("server side dry run is not supported")
+			})
+		}
+
+		// update the image of the single container, which is merged by name with the live one
+		request := &runtime.ApplyRequest{PriorResource: state, PlanResource: newDeploymentResource("nginx:2"), DryRun: dryRun}
+		response = rt.Apply(context.Background(), request)
+		require.Nil(t, response.Status)
+		containers, _, err := unstructured.NestedSlice(response.Resource.Attributes, "spec", "template", "spec", "containers")
+		require.NoError(t, err)
+		assert.Equal(t, expected, containers, "dry run: %v", dryRun)
+	}
+}
+
+func TestKubernetesRuntime_ApplyMultiCluster(t *testing.T) {
+	// the KUBECONFIG environment variable doesn't override the kubeConfig of the resources
+	t.Setenv("KUBECONFIG", "/clusters/default/kubeconfig")
+	rt := newFakeKubernetesRuntime()
+	other := newFakeKubernetesRuntime()
+	rt.newClusterClient = func(kubeConfig string) (dynamic.Interface, meta.RESTMapper, error) {
+		if kubeConfig == "/clusters/other/kubeconfig" {
+			return other.client, other.mapper, nil
+		}
+		return nil, nil, fmt.Errorf("stat %s: no such file or directory", kubeConfig)
+	}
+	gvr := schema.GroupVersionResource{Version: "v1", Resource: "configmaps"}
+
+	// the resource without the kubeConfig extension is applied to the default cluster
+	response := rt.Apply(context.Background(), &runtime.ApplyRequest{PlanResource: newConfigMapResource(map[string]interface{}{"key": "default"})})
+	require.Nil(t, response.Status)
+
+	// the resource with the kubeConfig extension is applied to its own cluster
+	plan := newConfigMapResource(map[string]interface{}{"key": "other"})
+	plan.SetKubeConfigPath("/clusters/other/kubeconfig")
+	response = rt.Apply(context.Background(), &runtime.ApplyRequest{PlanResource: plan})
+	require.Nil(t, response.Status)
+
+	obj, err := rt.client.(*fake.FakeDynamicClient).Tracker().Get(gvr, "default", "foo")
+	require.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{"key": "default"}, obj.(*unstructured.Unstructured).Object["data"])
+	obj, err = other.client.(*fake.FakeDynamicClient).Tracker().Get(gvr, "default", "foo")
+	require.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{"key": "other"}, obj.(*unstructured.Unstructured).Object["data"])
+
+	readResponse := rt.Read(context.Background(), &runtime.ReadRequest{PlanResource: plan})
+	require.Nil(t, readResponse.Status)
+	assert.Equal(t, map[string]interface{}{"key": "other"}, readResponse.Resource.Attributes["data"])
+
+	// the invalid kubeConfig fails the resource only
+	invalid := newConfigMapResource(map[string]interface{}{"key": "invalid"})
+	invalid.SetKubeConfigPath("/clusters/invalid/kubeconfig")
+	response = rt.Apply(context.Background(), &runtime.ApplyRequest{PlanResource: invalid})
+	require.NotNil(t, response.Status)
+	assert.Contains(t, response.Status.Message(), "/clusters/invalid/kubeconfig")
+	notPath := newConfigMapResource(map[string]interface{}{"key": "not-path"})
+	notPath.Extensions[apiv1.ResourceExtensionKubeConfig] = map[string]interface{}{"path": "/clusters/other/kubeconfig"}
+	response = rt.Apply(context.Background(), &runtime.ApplyRequest{PlanResource: notPath})
+	require.NotNil(t, response.Status)
+	assert.Contains(t, response.Status.Message(), "invalid kubeConfig")
+
+	deleteResponse := rt.Delete(context.Background(), &runtime.DeleteRequest{Resource: plan})
+	require.Nil(t, deleteResponse.Status)
+	_, err = other.client.(*fake.FakeDynamicClient).Tracker().Get(gvr, "default", "foo")
+	assert.Error(t, err)
+	_, err = rt.client.(*fake.FakeDynamicClient).Tracker().Get(gvr, "default", "foo")
+	assert.NoError(t, err)
+}
+
+func TestCreateThreeWayPatch(t *testing.T) {
+	original := []byte(`{"spec":{"template":{"spec":{"containers":[{"name":"foo","image":"nginx:1"}]}}}}`)
+	modified := []byte(`{"spec":{"template":{"spec":{"containers":[{"name":"foo","image":"nginx:2"}]}}}}`)
+	current := []byte(`{"spec":{"template":{"spec":{"containers":[{"name":"foo","image":"nginx:1","terminationMessagePath":"/dev/termination-log"}]}}}}`)
+
+	patch, patchType, err := createThreeWayPatch(schema.GroupVersionKind{Group: "apps", Version: "v1", Kind: "Deployment"}, original, modified, current)
+	require.NoError(t, err)
+	assert.Equal(t, types.StrategicMergePatchType, patchType)
+	assert.JSONEq(t, `{"spec":{"template":{"spec":{"$setElementOrder/containers":[{"name":"foo"}],"containers":[{"image":"nginx:2","name":"foo"}]}}}}`, string(patch))
+
+	// fall back to the JSON merge patch for the unknown types
+	patch, patchType, err = createThreeWayPatch(schema.GroupVersionKind{Group: "example.com", Version: "v1", Kind: "Foo"}, original, modified, current)
+	require.NoError(t, err)
+	assert.Equal(t, types.MergePatchType, patchType)
+	assert.JSONEq(t, `{"spec":{"template":{"spec":{"containers":[{"name":"foo","image":"nginx:2"}]}}}}`, string(patch))
+}
+
+func TestLastAppliedAttributes(t *testing.T) {
+	prior := newConfigMapResource(map[string]interface{}{"key": "v1"})
+	assert.Equal(t, prior.Attributes, lastAppliedAttributes(prior))
+
+	lastApplied := map[string]interface{}{"kind": "ConfigMap"}
+	prior.Extensions[apiv1.ResourceExtensionLastApplied] = lastApplied
+	assert.Equal(t, lastApplied, lastAppliedAttributes(prior))
+}
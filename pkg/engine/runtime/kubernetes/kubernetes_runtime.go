@@ -5,10 +5,11 @@ import (
 	"errors"
 	"fmt"
 	"os"
+	"path/filepath"
 	"strings"
+	"sync"
 	"time"
 
-	jsonpatch "github.com/evanphx/json-patch"
 	yamlv2 "gopkg.in/yaml.v2"
 	appsv1 "k8s.io/api/apps/v1"
 	batchv1 "k8s.io/api/batch/v1"
@@ -22,8 +23,6 @@ import (
 	k8sruntime "k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	k8syaml "k8s.io/apimachinery/pkg/runtime/serializer/yaml"
-	"k8s.io/apimachinery/pkg/types"
-	"k8s.io/apimachinery/pkg/util/jsonmergepatch"
 	k8swatch "k8s.io/apimachinery/pkg/watch"
 	"k8s.io/client-go/dynamic"
 	"k8s.io/client-go/rest"
@@ -46,6 +45,19 @@ var _ runtime.Runtime = (*KubernetesRuntime)(nil)
 type KubernetesRuntime struct {
 	client dynamic.Interface
 	mapper meta.RESTMapper
+
+	// newClusterClient builds the client of the cluster of the kubeConfig in the resource extensions,
+	// and the resources are applied to the default cluster only if it is nil.
+	newClusterClient func(kubeConfig string) (dynamic.Interface, meta.RESTMapper, error)
+	// clusters caches the runtimes of the clusters by the kubeConfig path.
+	clusters map[string]*cluster
+	mu       sync.Mutex
+}
+
+// cluster is the runtime of a cluster, or the error to build its client.
+type cluster struct {
+	runtime *KubernetesRuntime
+	err     error
 }
 
 // KubernetesWatchEvent is a wrapper of k8swatch.Event
@@ -62,11 +74,54 @@ func NewKubernetesRuntime(spec apiv1.Spec) (runtime.Runtime, error) {
 	}
 
 	return &KubernetesRuntime{
-		client: client,
-		mapper: mapper,
+		client:           client,
+		mapper:           mapper,
+		newClusterClient: newKubernetesClientFromPath,
 	}, nil
 }
 
+// forResource returns the runtime of the cluster which the resource belongs to. The resources with the
+// kubeConfig extension are applied to the cluster of the kubeConfig, which is not overridden by the
+// KUBECONFIG environment variable, and the others are applied to the default cluster, e.g. the one of
+// the workspace.
+func (k *KubernetesRuntime) forResource(resource *apiv1.Resource) (*KubernetesRuntime, error) {
+	if k.newClusterClient == nil {
+		return k, nil
+	}
+	kubeConfig, ok := resource.KubeConfigPath()
+	if !ok {
+		// the kubeConfig which is not a path fails the resource rather than applying it to the default cluster
+		if invalid := resource.Extensions[apiv1.ResourceExtensionKubeConfig]; invalid != nil {
+			return nil, fmt.Errorf("invalid kubeConfig %v in resource %s, expected a file path", invalid, resource.ID)
+		}
+		return k, nil
+	}
+	if abs, err := filepath.Abs(kubeConfig); err == nil {
+		kubeConfig = abs
+	}
+
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	c, ok := k.clusters[kubeConfig]
+	if !ok {
+		c = &cluster{}
+		client, mapper, err := k.newClusterClient(kubeConfig)
+		if err != nil {
+			c.err = err
+		} else {
+			c.runtime = &KubernetesRuntime{client: client, mapper: mapper}
+		}
+		if k.clusters == nil {
+			k.clusters = map[string]*cluster{}
+		}
+		k.clusters[kubeConfig] = c
+	}
+	if c.err != nil {
+		return nil, fmt.Errorf("failed to build the client of kubeConfig %s for resource %s: %w", kubeConfig, resource.ID, c.err)
+	}
+	return c.runtime, nil
+}
+
 // Apply kubernetes Resource by client-go
 func (k *KubernetesRuntime) Apply(ctx context.Context, request *runtime.ApplyRequest) *runtime.ApplyResponse {
 	planState := request.PlanResource
@@ -77,6 +132,12 @@ func (k *KubernetesRuntime) Apply(ctx context.Context, request *runtime.ApplyReq
 		return &runtime.ApplyResponse{Status: v1.NewErrorStatus(errors.New("plan state is nil"))}
 	}
 
+	// Apply to the cluster of the resource
+	k, err := k.forResource(planState)
+	if err != nil {
+		return &runtime.ApplyResponse{Status: v1.NewErrorStatus(err)}
+	}
+
 	// Get kubernetes Resource interface from plan state
 	planObj, resource, err := k.buildKubernetesResourceByState(planState)
 	if err != nil {
@@ -91,9 +152,11 @@ func (k *KubernetesRuntime) Apply(ctx context.Context, request *runtime.ApplyReq
 	liveState := response.Resource
 
 	// Original equals to last-applied from annotation, kusion store it in kusion_state.json
+	var lastApplied map[string]interface{}
 	original := ""
 	if priorState != nil {
-		original = jsonutil.MustMarshal2String(priorState.Attributes)
+		lastApplied = lastAppliedAttributes(priorState)
+		original = jsonutil.MustMarshal2String(lastApplied)
 	}
 	// Modified equals to input content
 	modified := jsonutil.MustMarshal2String(planState.Attributes)
@@ -103,8 +166,9 @@ func (k *KubernetesRuntime) Apply(ctx context.Context, request *runtime.ApplyReq
 		current = jsonutil.MustMarshal2String(liveState.Attributes)
 	}
 
-	// Create 3-way merge patch body
-	patchBody, err := jsonmergepatch.CreateThreeWayJSONMergePatch([]byte(original), []byte(modified), []byte(current))
+	// Create 3-way merge patch body, strategic merge patch for the built-in types
+	gvk := planObj.GroupVersionKind()
+	patchBody, patchType, err := createThreeWayPatch(gvk, []byte(original), []byte(modified), []byte(current))
 	if err != nil {
 		return &runtime.ApplyResponse{Status: v1.NewErrorStatus(err)}
 	}
@@ -131,43 +195,48 @@ func (k *KubernetesRuntime) Apply(ctx context.Context, request *runtime.ApplyReq
 			patchOptions := metav1.PatchOptions{
 				DryRun: []string{metav1.DryRunAll},
 			}
-			if patchedObj, err := resource.Patch(ctx, planObj.GetName(), types.MergePatchType, patchBody, patchOptions); err == nil {
+			if patchedObj, err := resource.Patch(ctx, planObj.GetName(), patchType, patchBody, patchOptions); err == nil {
 				res = patchedObj
 			} else {
 				// Fall back to ClientSideDryRun
 				log.Errorf("ServerSideDryRun patch %s failed, fall back to ClientSideDryRun; err: %v", planState.ID, err)
 
-				// Merge 3-way patch
-				mergedPatch, err := jsonpatch.MergePatch([]byte(current), patchBody)
+				// Merge 3-way
+				merged, err := threeWayMerge(gvk, lastApplied, planState.Attributes, liveState.Attributes)
 				if err != nil {
 					return &runtime.ApplyResponse{Status: v1.NewErrorStatus(err)}
 				}
-
-				// Unmarshall and return
-				res = &unstructured.Unstructured{}
-				if err = res.UnmarshalJSON(mergedPatch); err != nil {
-					return &runtime.ApplyResponse{Status: v1.NewErrorStatus(err)}
-				}
+				res = &unstructured.Unstructured{Object: merged}
 			}
 		}
 	} else {
 		if liveState == nil {
 			// LiveState is nil, fall back to create planObj
-			_, err = resource.Create(ctx, planObj, metav1.CreateOptions{})
+			res, err = resource.Create(ctx, planObj, metav1.CreateOptions{})
 		} else {
 			// LiveState isn't nil, continue to patch liveObj
-			_, err = resource.Patch(ctx, planObj.GetName(), types.MergePatchType, patchBody, metav1.PatchOptions{FieldManager: "kusion"})
+			res, err = resource.Patch(ctx, planObj.GetName(), patchType, patchBody, metav1.PatchOptions{FieldManager: "kusion"})
 		}
 		if err != nil {
 			return &runtime.ApplyResponse{Status: v1.NewErrorStatus(err)}
 		}
-		// Save modified
-		res = planObj
 	}
 
-	// Ignore the redundant fields automatically added by the K8s server for a
-	// more concise and clean resource object.
-	normalizeServerSideFields(res)
+	extensions := planState.Extensions
+	if request.DryRun {
+		// Ignore the redundant fields automatically added by the K8s server for a
+		// more concise and clean resource object.
+		normalizeServerSideFields(res)
+	} else {
+		// Save the live object returned by the server with the generated fields such as uid and
+		// resourceVersion, and record the modified as the original of the next 3-way merge.
+		unstructured.RemoveNestedField(res.Object, "metadata", "managedFields")
+		extensions = make(map[string]interface{}, len(planState.Extensions)+1)
+		for k, v := range planState.Extensions {
+			extensions[k] = v
+		}
+		extensions[apiv1.ResourceExtensionLastApplied] = planState.Attributes
+	}
 
 	// Extract the watch channel from the context.
 	watchCh, _ := ctx.Value(engine.WatchChannel).(chan string)
@@ -181,10 +250,19 @@ func (k *KubernetesRuntime) Apply(ctx context.Context, request *runtime.ApplyReq
 		Type:       planState.Type,
 		Attributes: res.Object,
 		DependsOn:  planState.DependsOn,
-		Extensions: planState.Extensions,
+		Extensions: extensions,
 	}}
 }
 
+// lastAppliedAttributes returns the attributes last applied by Kusion of the State resource, which
+// are the attributes of the State resource itself if it was saved before the live object is saved.
+func lastAppliedAttributes(priorState *apiv1.Resource) map[string]interface{} {
+	if attributes, ok := priorState.Extensions[apiv1.ResourceExtensionLastApplied].(map[string]interface{}); ok {
+		return attributes
+	}
+	return priorState.Attributes
+}
+
 // Read kubernetes Resource by client-go
 func (k *KubernetesRuntime) Read(ctx context.Context, request *runtime.ReadRequest) *runtime.ReadResponse {
 	requestResource := request.PlanResource
@@ -195,6 +273,10 @@ func (k *KubernetesRuntime) Read(ctx context.Context, request *runtime.ReadReque
 	if requestResource == nil {
 		return &runtime.ReadResponse{Status: v1.NewErrorStatus(errors.New("can not read k8s resource with empty body"))}
 	}
+	k, err := k.forResource(requestResource)
+	if err != nil {
+		return &runtime.ReadResponse{Status: v1.NewErrorStatus(err)}
+	}
 
 	// Get resource by attribute
 	obj, resource, err := k.buildKubernetesResourceByState(requestResource)
@@ -306,6 +388,10 @@ func (k *KubernetesRuntime) Delete(ctx context.Context, request *runtime.DeleteR
 	if requestResource == nil {
 		return &runtime.DeleteResponse{Status: v1.NewErrorStatus(errors.New("requestResource is nil"))}
 	}
+	k, err := k.forResource(requestResource)
+	if err != nil {
+		return &runtime.DeleteResponse{Status: v1.NewErrorStatus(err)}
+	}
 
 	// Get Resource by attribute
 	obj, resource, err := k.buildKubernetesResourceByState(requestResource)
@@ -331,6 +417,10 @@ func (k *KubernetesRuntime) Watch(ctx context.Context, request *runtime.WatchReq
 	if request == nil || request.Resource == nil {
 		return &runtime.WatchResponse{Status: v1.NewErrorStatus(errors.New("requestResource is nil"))}
 	}
+	k, err := k.forResource(request.Resource)
+	if err != nil {
+		return &runtime.WatchResponse{Status: v1.NewErrorStatus(err)}
+	}
 
 	reqObj, resource, err := k.buildKubernetesResourceByState(request.Resource)
 	if err != nil {
@@ -483,6 +573,20 @@ func getKubernetesClient(spec apiv1.Spec) (dynamic.Interface, meta.RESTMapper, e
 		}
 	}
 
+	return newKubernetesClient(cfg)
+}
+
+// newKubernetesClientFromPath get kubernetes client of the kubeConfig file
+func newKubernetesClientFromPath(kubeConfig string) (dynamic.Interface, meta.RESTMapper, error) {
+	cfg, err := clientcmd.BuildConfigFromFlags("", kubeConfig)
+	if err != nil {
+		return nil, nil, err
+	}
+	return newKubernetesClient(cfg)
+}
+
+// newKubernetesClient get kubernetes client of the rest config
+func newKubernetesClient(cfg *rest.Config) (dynamic.Interface, meta.RESTMapper, error) {
 	// DynamicRESTMapper can discover resource types at runtime dynamically
 	client, err := rest.HTTPClientFor(cfg)
 	if err != nil {
@@ -0,0 +1,82 @@
+package kubernetes
+
+import (
+	"encoding/json"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	k8sruntime "k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/jsonmergepatch"
+	"k8s.io/apimachinery/pkg/util/strategicpatch"
+	"k8s.io/client-go/kubernetes/scheme"
+
+	"kusionstack.io/kusion/pkg/util/merge"
+)
+
+// newTypedObject returns the empty typed object of the GVK if it is a built-in Kubernetes type, whose
+// patch metadata, such as the merge keys of the lists, is declared in the struct tags.
+func newTypedObject(gvk schema.GroupVersionKind) (k8sruntime.Object, bool) {
+	obj, err := scheme.Scheme.New(gvk)
+	if err != nil {
+		return nil, false
+	}
+	return obj, true
+}
+
+// createThreeWayPatch creates the 3-way patch of the original, modified and current JSON objects. The
+// strategic merge patch is used for the built-in Kubernetes types, so that the lists with merge keys,
+// e.g. the containers and env, are merged by key instead of replaced. The JSON merge patch is used for
+// the unknown types, such as the custom resources.
+func createThreeWayPatch(gvk schema.GroupVersionKind, original, modified, current []byte) ([]byte, types.PatchType, error) {
+	obj, ok := newTypedObject(gvk)
+	if !ok {
+		patch, err := jsonmergepatch.CreateThreeWayJSONMergePatch(original, modified, current)
+		return patch, types.MergePatchType, err
+	}
+
+	lookupPatchMeta, err := strategicpatch.NewPatchMetaFromStruct(obj)
+	if err != nil {
+		return nil, "", err
+	}
+	patch, err := strategicpatch.CreateThreeWayMergePatch(original, modified, current, lookupPatchMeta, true)
+	return patch, types.StrategicMergePatchType, err
+}
+
+// threeWayMerge merges the modified object into the live one on the client side, where the original
+// is the object last applied, in the same way as the patch created by createThreeWayPatch. The unknown
+// types are merged with the default Kubernetes merge keys, so that the Pod templates embedded in the
+// custom resources, e.g. the containers and env of a workload, are merged by key instead of replaced.
+func threeWayMerge(gvk schema.GroupVersionKind, original, modified, live map[string]interface{}) (map[string]interface{}, error) {
+	obj, ok := newTypedObject(gvk)
+	if !ok {
+		return merge.ThreeWayMerge(original, modified, live, merge.WithMergeKeys(merge.DefaultKubernetesMergeKeys))
+	}
+
+	lookupPatchMeta, err := strategicpatch.NewPatchMetaFromStruct(obj)
+	if err != nil {
+		return nil, err
+	}
+	if original == nil {
+		original = map[string]interface{}{}
+	}
+	var data [3][]byte
+	for i, m := range []map[string]interface{}{original, modified, live} {
+		if data[i], err = json.Marshal(m); err != nil {
+			return nil, err
+		}
+	}
+	patch, err := strategicpatch.CreateThreeWayMergePatch(data[0], data[1], data[2], lookupPatchMeta, true)
+	if err != nil {
+		return nil, err
+	}
+	merged, err := strategicpatch.StrategicMergePatchUsingLookupPatchMeta(data[2], patch, lookupPatchMeta)
+	if err != nil {
+		return nil, err
+	}
+	result := &unstructured.Unstructured{}
+	if err = result.UnmarshalJSON(merged); err != nil {
+		return nil, err
+	}
+	return result.Object, nil
+}
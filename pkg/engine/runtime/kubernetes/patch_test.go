@@ -0,0 +1,71 @@
+package kubernetes
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+func newPodTemplateObject(apiVersion, kind string, containers ...interface{}) map[string]interface{} {
+	return map[string]interface{}{
+		"apiVersion": apiVersion,
+		"kind":       kind,
+		"metadata":   map[string]interface{}{"name": "nginx", "namespace": "default"},
+		"spec": map[string]interface{}{
+			"template": map[string]interface{}{
+				"spec": map[string]interface{}{
+					"containers": containers,
+				},
+			},
+		},
+	}
+}
+
+func TestThreeWayMerge(t *testing.T) {
+	nginx := func(image string) map[string]interface{} {
+		return map[string]interface{}{
+			"name":  "nginx",
+			"image": image,
+			"env":   []interface{}{map[string]interface{}{"name": "LOG_LEVEL", "value": "info"}},
+		}
+	}
+	// the sidecar injected by the server, which is not in the desired object
+	sidecar := map[string]interface{}{"name": "sidecar", "image": "envoy:1"}
+	liveNginx := nginx("nginx:1")
+	liveNginx["env"] = []interface{}{
+		map[string]interface{}{"name": "LOG_LEVEL", "value": "info"},
+		map[string]interface{}{"name": "POD_IP", "value": "10.0.0.1"},
+	}
+
+	testcases := []struct {
+		name string
+		gvk  schema.GroupVersionKind
+	}{
+		{
+			name: "merge built-in type by strategic merge patch",
+			gvk:  schema.GroupVersionKind{Group: "apps", Version: "v1", Kind: "Deployment"},
+		},
+		{
+			name: "merge custom resource by default kubernetes merge keys",
+			gvk:  schema.GroupVersionKind{Group: "apps.kusionstack.io", Version: "v1alpha1", Kind: "CollaSet"},
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			apiVersion := tc.gvk.GroupVersion().String()
+			original := newPodTemplateObject(apiVersion, tc.gvk.Kind, nginx("nginx:1"))
+			modified := newPodTemplateObject(apiVersion, tc.gvk.Kind, nginx("nginx:2"))
+			live := newPodTemplateObject(apiVersion, tc.gvk.Kind, liveNginx, sidecar)
+
+			merged, err := threeWayMerge(tc.gvk, original, modified, live)
+			assert.NoError(t, err)
+
+			// the containers and env are merged by name, which keeps the ones set by the server
+			expectedNginx := nginx("nginx:2")
+			expectedNginx["env"] = liveNginx["env"]
+			assert.Equal(t, newPodTemplateObject(apiVersion, tc.gvk.Kind, expectedNginx, sidecar), merged)
+		})
+	}
+}
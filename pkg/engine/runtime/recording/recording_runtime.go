@@ -0,0 +1,96 @@
+package recording
+
+import (
+	"context"
+	"sync"
+
+	apiv1 "kusionstack.io/kusion/pkg/apis/api.kusion.io/v1"
+	"kusionstack.io/kusion/pkg/engine/runtime"
+	"kusionstack.io/kusion/pkg/log"
+)
+
+// OperationType is the type of the operation recorded by the RecordingRuntime.
+type OperationType string
+
+const (
+	Apply  OperationType = "Apply"
+	Delete OperationType = "Delete"
+	Import OperationType = "Import"
+)
+
+// Operation is an intended operation on a resource, which is recorded instead of being performed.
+type Operation struct {
+	Type         OperationType
+	ResourceID   string
+	ResourceType apiv1.Type
+}
+
+var _ runtime.Runtime = (*RecordingRuntime)(nil)
+
+// RecordingRuntime is a fake runtime used by the dry-run apply, which records the intended operations
+// in order without performing them in the actual infra. The prior resource is regarded as the live
+// resource, and the planned resource is returned as the applied result.
+type RecordingRuntime struct {
+	mu         sync.Mutex
+	operations []Operation
+}
+
+// NewRecordingRuntime returns a RecordingRuntime with no recorded operation.
+func NewRecordingRuntime() *RecordingRuntime {
+	return &RecordingRuntime{}
+}
+
+// Operations returns the recorded operations in the order they were requested.
+func (r *RecordingRuntime) Operations() []Operation {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	operations := make([]Operation, len(r.operations))
+	copy(operations, r.operations)
+	return operations
+}
+
+func (r *RecordingRuntime) record(t OperationType, resource *apiv1.Resource) {
+	if resource == nil {
+		return
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	log.Infof("dry run: %s resource %s", t, resource.ResourceKey())
+	r.operations = append(r.operations, Operation{
+		Type:         t,
+		ResourceID:   resource.ResourceKey(),
+		ResourceType: resource.Type,
+	})
+}
+
+// Apply records the apply operation unless it is a dry-run request, and returns the planned resource.
+func (r *RecordingRuntime) Apply(_ context.Context, request *runtime.ApplyRequest) *runtime.ApplyResponse {
+	if !request.DryRun {
+		r.record(Apply, request.PlanResource)
+	}
+	return &runtime.ApplyResponse{Resource: request.PlanResource}
+}
+
+// Read returns the prior resource as the live resource.
+func (r *RecordingRuntime) Read(_ context.Context, request *runtime.ReadRequest) *runtime.ReadResponse {
+	return &runtime.ReadResponse{Resource: request.PriorResource}
+}
+
+// Import records the import operation, and returns the planned resource.
+func (r *RecordingRuntime) Import(_ context.Context, request *runtime.ImportRequest) *runtime.ImportResponse {
+	r.record(Import, request.PlanResource)
+	return &runtime.ImportResponse{Resource: request.PlanResource}
+}
+
+// Delete records the delete operation.
+func (r *RecordingRuntime) Delete(_ context.Context, request *runtime.DeleteRequest) *runtime.DeleteResponse {
+	r.record(Delete, request.Resource)
+	return &runtime.DeleteResponse{}
+}
+
+// Watch returns no watcher, for nothing is changed in the actual infra.
+func (r *RecordingRuntime) Watch(_ context.Context, _ *runtime.WatchRequest) *runtime.WatchResponse {
+	return &runtime.WatchResponse{}
+}
@@ -0,0 +1,42 @@
+package recording
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	apiv1 "kusionstack.io/kusion/pkg/apis/api.kusion.io/v1"
+	"kusionstack.io/kusion/pkg/engine/runtime"
+)
+
+func TestRecordingRuntime(t *testing.T) {
+	ctx := context.TODO()
+	prior := &apiv1.Resource{ID: "v1:Service:default:svc", Type: runtime.Kubernetes, Attributes: map[string]interface{}{"a": "b"}}
+	plan := &apiv1.Resource{ID: "v1:Service:default:svc", Type: runtime.Kubernetes, Attributes: map[string]interface{}{"a": "c"}}
+	imported := &apiv1.Resource{ID: "aliyun:alicloud:alicloud_vpc:vpc", Type: runtime.Terraform}
+	r := NewRecordingRuntime()
+
+	// read returns the prior resource, and dry-run apply returns the planned resource without recording
+	readRsp := r.Read(ctx, &runtime.ReadRequest{PriorResource: prior, PlanResource: plan})
+	assert.Equal(t, prior, readRsp.Resource)
+	applyRsp := r.Apply(ctx, &runtime.ApplyRequest{PriorResource: prior, PlanResource: plan, DryRun: true})
+	assert.Equal(t, plan, applyRsp.Resource)
+	assert.Empty(t, r.Operations())
+
+	applyRsp = r.Apply(ctx, &runtime.ApplyRequest{PriorResource: prior, PlanResource: plan})
+	assert.Equal(t, plan, applyRsp.Resource)
+	assert.Nil(t, applyRsp.Status)
+	importRsp := r.Import(ctx, &runtime.ImportRequest{PlanResource: imported})
+	assert.Equal(t, imported, importRsp.Resource)
+	deleteRsp := r.Delete(ctx, &runtime.DeleteRequest{Resource: prior})
+	assert.Nil(t, deleteRsp.Status)
+	watchRsp := r.Watch(ctx, &runtime.WatchRequest{Resource: plan})
+	assert.Nil(t, watchRsp.Watchers)
+
+	assert.Equal(t, []Operation{
+		{Type: Apply, ResourceID: plan.ID, ResourceType: runtime.Kubernetes},
+		{Type: Import, ResourceID: imported.ID, ResourceType: runtime.Terraform},
+		{Type: Delete, ResourceID: prior.ID, ResourceType: runtime.Kubernetes},
+	}, r.Operations())
+}
@@ -26,14 +26,16 @@ var _ runtime.Runtime = &Runtime{}
 var tfEvents = cache.New(cache.NoExpiration, cache.NoExpiration)
 
 type Runtime struct {
-	mutex   *sync.Mutex
-	context apiv1.GenericConfig
+	mutex       *sync.Mutex
+	context     apiv1.GenericConfig
+	secretStore *apiv1.SecretStore
 }
 
 func NewTerraformRuntime(spec apiv1.Spec) (runtime.Runtime, error) {
 	TFRuntime := &Runtime{
-		mutex:   &sync.Mutex{},
-		context: spec.Context,
+		mutex:       &sync.Mutex{},
+		context:     spec.Context,
+		secretStore: spec.SecretStore,
 	}
 	return TFRuntime, nil
 }
@@ -45,6 +47,7 @@ func (t *Runtime) Apply(ctx context.Context, request *runtime.ApplyRequest) *run
 	key := plan.ResourceKey()
 	tfCacheDir := buildTFCacheDir(stackPath, key)
 	ws := tfops.NewWorkSpace(plan, stackPath, tfCacheDir, t.mutex, t.context)
+	ws.SetSecretStore(t.secretStore)
 
 	if err := ws.WriteHCL(); err != nil {
 		return &runtime.ApplyResponse{Resource: nil, Status: v1.NewErrorStatus(err)}
@@ -218,6 +221,7 @@ func (t *Runtime) Read(ctx context.Context, request *runtime.ReadRequest) *runti
 	tfCacheDir := buildTFCacheDir(stackPath, planResource.ResourceKey())
 
 	ws := tfops.NewWorkSpace(planResource, stackPath, tfCacheDir, t.mutex, t.context)
+	ws.SetSecretStore(t.secretStore)
 	if err := ws.WriteHCL(); err != nil {
 		return &runtime.ReadResponse{Resource: nil, Status: v1.NewErrorStatus(err)}
 	}
@@ -318,6 +322,7 @@ func (t *Runtime) Delete(ctx context.Context, request *runtime.DeleteRequest) (r
 	tfCacheDir := buildTFCacheDir(stackPath, request.Resource.ResourceKey())
 
 	ws := tfops.NewWorkSpace(request.Resource, stackPath, tfCacheDir, t.mutex, t.context)
+	ws.SetSecretStore(t.secretStore)
 	if err := ws.Destroy(ctx); err != nil {
 		return &runtime.DeleteResponse{Status: v1.NewErrorStatus(err)}
 	}
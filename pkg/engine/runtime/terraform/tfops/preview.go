@@ -0,0 +1,118 @@
+package tfops
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"kusionstack.io/kusion/pkg/engine/operation/models"
+)
+
+const (
+	actionNoOp   = "no-op"
+	actionCreate = "create"
+	actionRead   = "read"
+	actionUpdate = "update"
+	actionDelete = "delete"
+
+	managedResourceMode = "managed"
+)
+
+// ParsePlanChangeOrder parses the output of `terraform show -json <plan>`, and builds the preview change
+// order of the managed resources, whose keys are the resource ids in the format of
+// providerNamespace:providerName:resourceType:resourceName.
+func ParsePlanChangeOrder(data []byte) (*models.ChangeOrder, error) {
+	plan := &PlanRepresentation{}
+	if err := json.Unmarshal(data, plan); err != nil {
+		return nil, fmt.Errorf("json unmarshal terraform plan failed: %w", err)
+	}
+	return plan.ChangeOrder()
+}
+
+// ChangeOrder maps the resource changes of the plan into the preview change order. The replacement of a
+// resource is mapped to Update, and the no-op and read changes are mapped to UnChanged. The changes of
+// data sources and deposed objects are ignored.
+func (p *PlanRepresentation) ChangeOrder() (*models.ChangeOrder, error) {
+	order := &models.ChangeOrder{
+		StepKeys:    []string{},
+		ChangeSteps: map[string]*models.ChangeStep{},
+	}
+	for _, rc := range p.ResourceChanges {
+		if rc.Mode != managedResourceMode || rc.Deposed != "" {
+			continue
+		}
+		id, err := rc.ResourceID()
+		if err != nil {
+			return nil, err
+		}
+		action, err := convertActions(rc.Change.Actions)
+		if err != nil {
+			return nil, fmt.Errorf("resource %s: %w", id, err)
+		}
+		from, err := unmarshalChangeValue(rc.Change.Before)
+		if err != nil {
+			return nil, fmt.Errorf("resource %s: %w", id, err)
+		}
+		to, err := unmarshalChangeValue(rc.Change.After)
+		if err != nil {
+			return nil, fmt.Errorf("resource %s: %w", id, err)
+		}
+
+		if _, ok := order.ChangeSteps[id]; !ok {
+			order.StepKeys = append(order.StepKeys, id)
+		}
+		order.ChangeSteps[id] = models.NewChangeStep(id, action, from, to)
+	}
+	return order, nil
+}
+
+// ResourceID returns the Kusion resource id of the changed resource, e.g. the resource aws_s3_bucket.foo
+// of the provider registry.terraform.io/hashicorp/aws is hashicorp:aws:aws_s3_bucket:foo. The index is
+// appended to the name for the resource with count or for_each, e.g. foo[0].
+func (rc *ResourceChange) ResourceID() (string, error) {
+	providerParts := strings.Split(rc.ProviderName, "/")
+	if len(providerParts) < 2 || rc.Type == "" || rc.Name == "" {
+		return "", fmt.Errorf("invalid terraform resource change %s of provider %s", rc.Address, rc.ProviderName)
+	}
+	name := rc.Name
+	if len(rc.Index) != 0 {
+		name = fmt.Sprintf("%s[%s]", name, string(rc.Index))
+	}
+	return strings.Join([]string{
+		providerParts[len(providerParts)-2],
+		providerParts[len(providerParts)-1],
+		rc.Type,
+		name,
+	}, ":"), nil
+}
+
+func convertActions(actions []string) (models.ActionType, error) {
+	switch {
+	case len(actions) == 1 && (actions[0] == actionNoOp || actions[0] == actionRead):
+		return models.UnChanged, nil
+	case len(actions) == 1 && actions[0] == actionCreate:
+		return models.Create, nil
+	case len(actions) == 1 && actions[0] == actionUpdate:
+		return models.Update, nil
+	case len(actions) == 1 && actions[0] == actionDelete:
+		return models.Delete, nil
+	case len(actions) == 2 && actions[0] != actions[1] &&
+		(actions[0] == actionDelete || actions[0] == actionCreate) &&
+		(actions[1] == actionDelete || actions[1] == actionCreate):
+		// replace
+		return models.Update, nil
+	default:
+		return models.Undefined, fmt.Errorf("unsupported terraform change actions %v", actions)
+	}
+}
+
+func unmarshalChangeValue(raw json.RawMessage) (interface{}, error) {
+	if len(raw) == 0 || string(raw) == "null" {
+		return nil, nil
+	}
+	var value interface{}
+	if err := json.Unmarshal(raw, &value); err != nil {
+		return nil, fmt.Errorf("json unmarshal change value failed: %w", err)
+	}
+	return value, nil
+}
@@ -0,0 +1,127 @@
+package tfops
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"kusionstack.io/kusion/pkg/engine/operation/models"
+)
+
+var samplePlanJSON = `{
+  "format_version": "1.2",
+  "terraform_version": "1.5.7",
+  "resource_changes": [
+    {
+      "address": "aws_s3_bucket.created",
+      "mode": "managed",
+      "type": "aws_s3_bucket",
+      "name": "created",
+      "provider_name": "registry.terraform.io/hashicorp/aws",
+      "change": {"actions": ["create"], "before": null, "after": {"bucket": "created"}}
+    },
+    {
+      "address": "aws_s3_bucket.updated",
+      "mode": "managed",
+      "type": "aws_s3_bucket",
+      "name": "updated",
+      "provider_name": "registry.terraform.io/hashicorp/aws",
+      "change": {"actions": ["update"], "before": {"tags": {"env": "dev"}}, "after": {"tags": {"env": "prod"}}}
+    },
+    {
+      "address": "aws_db_instance.replaced",
+      "mode": "managed",
+      "type": "aws_db_instance",
+      "name": "replaced",
+      "provider_name": "registry.terraform.io/hashicorp/aws",
+      "change": {"actions": ["delete", "create"], "before": {"engine": "mysql"}, "after": {"engine": "postgres"}}
+    },
+    {
+      "address": "aws_s3_bucket.deleted",
+      "mode": "managed",
+      "type": "aws_s3_bucket",
+      "name": "deleted",
+      "provider_name": "registry.terraform.io/hashicorp/aws",
+      "change": {"actions": ["delete"], "before": {"bucket": "deleted"}, "after": null}
+    },
+    {
+      "address": "local_file.unchanged[0]",
+      "mode": "managed",
+      "type": "local_file",
+      "name": "unchanged",
+      "index": 0,
+      "provider_name": "registry.terraform.io/hashicorp/local",
+      "change": {"actions": ["no-op"], "before": {"content": "kusion"}, "after": {"content": "kusion"}}
+    },
+    {
+      "address": "data.aws_caller_identity.current",
+      "mode": "data",
+      "type": "aws_caller_identity",
+      "name": "current",
+      "provider_name": "registry.terraform.io/hashicorp/aws",
+      "change": {"actions": ["read"], "before": null, "after": {}}
+    }
+  ]
+}`
+
+func TestParsePlanChangeOrder(t *testing.T) {
+	order, err := ParsePlanChangeOrder([]byte(samplePlanJSON))
+	assert.NoError(t, err)
+
+	expectedActions := map[string]models.ActionType{
+		"hashicorp:aws:aws_s3_bucket:created":     models.Create,
+		"hashicorp:aws:aws_s3_bucket:updated":     models.Update,
+		"hashicorp:aws:aws_db_instance:replaced":  models.Update,
+		"hashicorp:aws:aws_s3_bucket:deleted":     models.Delete,
+		"hashicorp:local:local_file:unchanged[0]": models.UnChanged,
+	}
+	assert.Equal(t, []string{
+		"hashicorp:aws:aws_s3_bucket:created",
+		"hashicorp:aws:aws_s3_bucket:updated",
+		"hashicorp:aws:aws_db_instance:replaced",
+		"hashicorp:aws:aws_s3_bucket:deleted",
+		"hashicorp:local:local_file:unchanged[0]",
+	}, order.StepKeys)
+	assert.Len(t, order.ChangeSteps, len(expectedActions))
+	for id, action := range expectedActions {
+		step, ok := order.ChangeSteps[id]
+		if assert.True(t, ok, id) {
+			assert.Equal(t, id, step.ID)
+			assert.Equal(t, action, step.Action, id)
+		}
+	}
+
+	created := order.ChangeSteps["hashicorp:aws:aws_s3_bucket:created"]
+	assert.Nil(t, created.From)
+	assert.Equal(t, map[string]interface{}{"bucket": "created"}, created.To)
+	deleted := order.ChangeSteps["hashicorp:aws:aws_s3_bucket:deleted"]
+	assert.Equal(t, map[string]interface{}{"bucket": "deleted"}, deleted.From)
+	assert.Nil(t, deleted.To)
+}
+
+func TestParsePlanChangeOrderFailed(t *testing.T) {
+	testcases := []struct {
+		name string
+		plan string
+	}{
+		{
+			name: "invalid json",
+			plan: `{"resource_changes": [`,
+		},
+		{
+			name: "invalid provider name",
+			plan: `{"resource_changes": [{"mode": "managed", "type": "local_file", "name": "foo", "provider_name": "local", "change": {"actions": ["create"]}}]}`,
+		},
+		{
+			name: "unsupported actions",
+			plan: `{"resource_changes": [{"mode": "managed", "type": "local_file", "name": "foo", "provider_name": "hashicorp/local", "change": {"actions": ["delete", "delete"]}}]}`,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := ParsePlanChangeOrder([]byte(tc.plan))
+			assert.Error(t, err)
+		})
+	}
+}
@@ -0,0 +1,114 @@
+package tfops
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+
+	v1 "kusionstack.io/kusion/pkg/apis/api.kusion.io/v1"
+	"kusionstack.io/kusion/pkg/secrets"
+)
+
+// providerSecretRefPattern matches the secret reference in the provider config, in the format of
+// ${secret:name.property}, where the property is optional.
+var providerSecretRefPattern = regexp.MustCompile(`\$\{secret:([^}]+)}`)
+
+var ErrUnresolvedSecretRef = errors.New("unresolved secret reference in provider config")
+
+// ResolveProviderSecretRefs returns a copy of the provider config whose secret references are replaced
+// by the secret values retrieved from the secret store, so that the credentials are not required to be
+// kept in plaintext in the Spec. The reference is in the format of ${secret:name.property}, e.g.
+// ${secret:aws-credentials.accessKey}. An error is returned if any reference can not be resolved.
+func ResolveProviderSecretRefs(ctx context.Context, config interface{}, secretStore *v1.SecretStore) (interface{}, error) {
+	var store secrets.SecretStore
+	getSecret := func(ref string) (string, error) {
+		if store == nil {
+			if secretStore == nil {
+				return "", fmt.Errorf("%w: %s, no secret store configured in workspace", ErrUnresolvedSecretRef, ref)
+			}
+			provider, exist := secrets.GetProvider(secretStore.Provider)
+			if !exist {
+				return "", fmt.Errorf("%w: %s, no matched secret store found, please check workspace yaml", ErrUnresolvedSecretRef, ref)
+			}
+			var err error
+			if store, err = provider.NewSecretStore(secretStore); err != nil {
+				return "", err
+			}
+		}
+
+		data, err := secrets.GetSecret(ctx, store, ParseProviderSecretRef(ref))
+		if err != nil {
+			return "", fmt.Errorf("%w: %s, %v", ErrUnresolvedSecretRef, ref, err)
+		}
+		return string(data), nil
+	}
+	return resolveSecretRefs(config, getSecret)
+}
+
+// ParseProviderSecretRef parses the reference in the provider config without the ${secret:} wrapper,
+// e.g. aws-credentials.accessKey, into the external secret ref.
+func ParseProviderSecretRef(ref string) v1.ExternalSecretRef {
+	externalSecretRef := v1.ExternalSecretRef{Name: ref}
+	if name, property, ok := strings.Cut(ref, "."); ok {
+		externalSecretRef.Name = name
+		externalSecretRef.Property = property
+	}
+	return externalSecretRef
+}
+
+// FindProviderSecretRefs returns the references in the string in the format of ${secret:name.property},
+// without the ${secret:} wrapper.
+func FindProviderSecretRefs(s string) []string {
+	var refs []string
+	for _, match := range providerSecretRefPattern.FindAllStringSubmatch(s, -1) {
+		refs = append(refs, match[1])
+	}
+	return refs
+}
+
+func resolveSecretRefs(value interface{}, getSecret func(ref string) (string, error)) (interface{}, error) {
+	switch v := value.(type) {
+	case string:
+		var resolveErr error
+		resolved := providerSecretRefPattern.ReplaceAllStringFunc(v, func(match string) string {
+			if resolveErr != nil {
+				return match
+			}
+			secret, err := getSecret(providerSecretRefPattern.FindStringSubmatch(match)[1])
+			if err != nil {
+				resolveErr = err
+			}
+			return secret
+		})
+		if resolveErr != nil {
+			return nil, resolveErr
+		}
+		return resolved, nil
+	case map[string]interface{}:
+		resolved := make(map[string]interface{}, len(v))
+		for k, item := range v {
+			resolvedItem, err := resolveSecretRefs(item, getSecret)
+			if err != nil {
+				return nil, err
+			}
+			resolved[k] = resolvedItem
+		}
+		return resolved, nil
+	case v1.GenericConfig:
+		return resolveSecretRefs(map[string]interface{}(v), getSecret)
+	case []interface{}:
+		resolved := make([]interface{}, len(v))
+		for i, item := range v {
+			resolvedItem, err := resolveSecretRefs(item, getSecret)
+			if err != nil {
+				return nil, err
+			}
+			resolved[i] = resolvedItem
+		}
+		return resolved, nil
+	default:
+		return value, nil
+	}
+}
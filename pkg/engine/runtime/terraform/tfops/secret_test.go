@@ -0,0 +1,123 @@
+package tfops
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	v1 "kusionstack.io/kusion/pkg/apis/api.kusion.io/v1"
+	_ "kusionstack.io/kusion/pkg/secrets/providers/fake"
+)
+
+func mockFakeSecretStore() *v1.SecretStore {
+	return &v1.SecretStore{
+		Provider: &v1.ProviderSpec{
+			Fake: &v1.FakeProvider{
+				Data: []v1.FakeProviderData{
+					{
+						Key:   "aws-credentials",
+						Value: `{"accessKey": "fake-access-key", "secretKey": "fake-secret-key"}`,
+					},
+					{
+						Key:   "region",
+						Value: "us-east-1",
+					},
+				},
+			},
+		},
+	}
+}
+
+func TestResolveProviderSecretRefs(t *testing.T) {
+	testcases := []struct {
+		name        string
+		success     bool
+		config      interface{}
+		secretStore *v1.SecretStore
+		expected    interface{}
+	}{
+		{
+			name:    "resolve access key from fake provider",
+			success: true,
+			config: map[string]interface{}{
+				"access_key": "${secret:aws-credentials.accessKey}",
+				"secret_key": "${secret:aws-credentials.secretKey}",
+				"region":     "${secret:region}",
+				"assume_role": []interface{}{
+					map[string]interface{}{
+						"role_arn": "arn:aws:iam::123456789012:role/kusion",
+					},
+				},
+			},
+			secretStore: mockFakeSecretStore(),
+			expected: map[string]interface{}{
+				"access_key": "fake-access-key",
+				"secret_key": "fake-secret-key",
+				"region":     "us-east-1",
+				"assume_role": []interface{}{
+					map[string]interface{}{
+						"role_arn": "arn:aws:iam::123456789012:role/kusion",
+					},
+				},
+			},
+		},
+		{
+			name:    "resolve secret ref inside value",
+			success: true,
+			config: v1.GenericConfig{
+				"endpoint": "https://${secret:region}.example.com",
+			},
+			secretStore: mockFakeSecretStore(),
+			expected: map[string]interface{}{
+				"endpoint": "https://us-east-1.example.com",
+			},
+		},
+		{
+			name:        "no secret ref without secret store",
+			success:     true,
+			config:      map[string]interface{}{"region": "us-east-1"},
+			secretStore: nil,
+			expected:    map[string]interface{}{"region": "us-east-1"},
+		},
+		{
+			name:        "nil provider config",
+			success:     true,
+			config:      nil,
+			secretStore: mockFakeSecretStore(),
+			expected:    nil,
+		},
+		{
+			name:        "unresolved secret ref not exist",
+			success:     false,
+			config:      map[string]interface{}{"access_key": "${secret:not-exist.accessKey}"},
+			secretStore: mockFakeSecretStore(),
+		},
+		{
+			name:        "unresolved secret ref without secret store",
+			success:     false,
+			config:      map[string]interface{}{"access_key": "${secret:aws-credentials.accessKey}"},
+			secretStore: nil,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			resolved, err := ResolveProviderSecretRefs(context.TODO(), tc.config, tc.secretStore)
+			assert.Equal(t, tc.success, err == nil)
+			if tc.success {
+				assert.Equal(t, tc.expected, resolved)
+			} else {
+				assert.ErrorIs(t, err, ErrUnresolvedSecretRef)
+			}
+		})
+	}
+}
+
+func TestFindProviderSecretRefs(t *testing.T) {
+	refs := FindProviderSecretRefs("${secret:aws-credentials.accessKey}:${secret:token}")
+	assert.Equal(t, []string{"aws-credentials.accessKey", "token"}, refs)
+	assert.Equal(t, v1.ExternalSecretRef{Name: "aws-credentials", Property: "accessKey"}, ParseProviderSecretRef(refs[0]))
+	assert.Equal(t, v1.ExternalSecretRef{Name: "token"}, ParseProviderSecretRef(refs[1]))
+	assert.Nil(t, FindProviderSecretRefs("plain"))
+}
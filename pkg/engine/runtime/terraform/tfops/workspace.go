@@ -8,6 +8,7 @@ import (
 	"os"
 	"os/exec"
 	"os/user"
+	"path"
 	"path/filepath"
 	"strings"
 	"sync"
@@ -26,6 +27,9 @@ import (
 
 const (
 	ImportIDKey = "kusionstack.io/import-id"
+
+	// ProviderMetaKey is the key of the provider config in the extensions of the Terraform resources.
+	ProviderMetaKey = "providerMeta"
 )
 
 const (
@@ -46,6 +50,22 @@ const (
 	pluginCache                = "plugin-cache"
 )
 
+// backendStateKeys are the config fields of the Terraform backends which locate the state. As each
+// resource is applied in its own Terraform workspace, the resource path is appended to the configured
+// value to keep the states of the resources apart.
+var backendStateKeys = map[string]struct {
+	field  string
+	suffix string
+}{
+	"s3":      {field: "key", suffix: ".tfstate"},
+	"azurerm": {field: "key", suffix: ".tfstate"},
+	"oss":     {field: "key", suffix: ".tfstate"},
+	"cos":     {field: "key", suffix: ".tfstate"},
+	"local":   {field: "path", suffix: ".tfstate"},
+	"gcs":     {field: "prefix"},
+	"consul":  {field: "path"},
+}
+
 var (
 	envTFLog                              = fmt.Sprintf("%s=%s", envLog, tfDebugLOG)
 	envPluginCacheBreakDependencyLockFile = fmt.Sprintf("%s=%s", envBreakDependencyLockFile, "true")
@@ -59,6 +79,8 @@ type WorkSpace struct {
 	mutex *sync.Mutex
 	// context passed from TF runtime.
 	context v1.GenericConfig
+	// secretStore passed from TF runtime, which is used to resolve the secret refs in the provider config.
+	secretStore *v1.SecretStore
 }
 
 func NewWorkSpace(resource *v1.Resource, stackDir string, tfCacheDir string, mutex *sync.Mutex, context v1.GenericConfig) *WorkSpace {
@@ -75,6 +97,11 @@ func (w *WorkSpace) SetStackDir(stackDir string) {
 	w.stackDir = stackDir
 }
 
+// SetSecretStore set the secret store to resolve the secret refs in the provider config.
+func (w *WorkSpace) SetSecretStore(secretStore *v1.SecretStore) {
+	w.secretStore = secretStore
+}
+
 // SetCacheDir set tf cache work directory.
 func (w *WorkSpace) SetCacheDir(cacheDir string) {
 	w.tfCacheDir = cacheDir
@@ -91,17 +118,35 @@ func (w *WorkSpace) WriteHCL() error {
 			"Resource id format: providerNamespace:providerName:resourceType:resourceName", w.resource.ResourceKey())
 	}
 
-	m := map[string]interface{}{
-		"terraform": map[string]interface{}{
-			"required_providers": map[string]interface{}{
-				provider[len(provider)-2]: map[string]string{
-					"source":  strings.Join(provider[:len(provider)-1], "/"),
-					"version": provider[len(provider)-1],
-				},
+	terraformBlock := map[string]interface{}{
+		"required_providers": map[string]interface{}{
+			provider[len(provider)-2]: map[string]string{
+				"source":  strings.Join(provider[:len(provider)-1], "/"),
+				"version": provider[len(provider)-1],
 			},
 		},
+	}
+	providerMeta, err := ResolveProviderSecretRefs(context.Background(), w.resource.Extensions[ProviderMetaKey], w.secretStore)
+	if err != nil {
+		return err
+	}
+	backend, err := workspace.GetTerraformBackend(w.context)
+	if err != nil {
+		return err
+	}
+	if backend != nil {
+		if err = workspace.ValidateTerraformBackend(backend); err != nil {
+			return err
+		}
+		terraformBlock["backend"] = map[string]interface{}{
+			backend.Type: backendConfig(backend, w.resource.ResourceKey()),
+		}
+	}
+
+	m := map[string]interface{}{
+		"terraform": terraformBlock,
 		"provider": map[string]interface{}{
-			provider[len(provider)-2]: w.resource.Extensions["providerMeta"],
+			provider[len(provider)-2]: providerMeta,
 		},
 		"resource": map[string]interface{}{
 			resourceType: map[string]interface{}{
@@ -119,7 +164,7 @@ func (w *WorkSpace) WriteHCL() error {
 
 	hclMain := jsonutil.Marshal2PrettyString(m)
 
-	_, err := os.Stat(w.tfCacheDir)
+	_, err = os.Stat(w.tfCacheDir)
 	if err != nil {
 		if os.IsNotExist(err) {
 			if err := os.MkdirAll(w.tfCacheDir, os.ModePerm); err != nil {
@@ -137,6 +182,23 @@ func (w *WorkSpace) WriteHCL() error {
 	return nil
 }
 
+// backendConfig returns the config of the backend block for the resource, where the state key field is
+// appended with the resource path, e.g. the s3 key "kusion" is rendered as
+// "kusion/hashicorp/aws/aws_db_instance/wordpress.tfstate".
+func backendConfig(backend *v1.TerraformBackend, resourceID string) map[string]interface{} {
+	config := make(map[string]interface{}, len(backend.Config)+1)
+	for k, v := range backend.Config {
+		config[k] = v
+	}
+	stateKey, ok := backendStateKeys[backend.Type]
+	if !ok {
+		return config
+	}
+	prefix, _ := config[stateKey.field].(string)
+	config[stateKey.field] = path.Join(prefix, strings.ReplaceAll(resourceID, ":", "/")) + stateKey.suffix
+	return config
+}
+
 // ImportResource imports the resource state into the temporary terraform cache directory under the stack.
 func (w *WorkSpace) ImportResource(ctx context.Context, id string) error {
 	resourceType := w.resource.Extensions["resourceType"].(string)
@@ -106,6 +106,26 @@ func TestWorkspaceSuite(t *testing.T) {
 					mainTF: "{\n  \"provider\": {\n    \"local\": null\n  },\n  \"resource\": {\n    \"local_file\": {\n      \"kusion_example\": {\n        \"content\": \"kusion\",\n        \"filename\": \"test.txt\"\n      }\n    }\n  },\n  \"terraform\": {\n    \"required_providers\": {\n      \"local\": {\n        \"source\": \"registry.terraform.io/hashicorp/local\",\n        \"version\": \"2.2.3\"\n      }\n    }\n  }\n}",
 				},
 			},
+			"writeWithS3Backend": {
+				args: args{
+					w: &WorkSpace{
+						mutex: &sync.Mutex{},
+						context: apiv1.GenericConfig{
+							apiv1.ContextKeyTerraformBackend: map[string]interface{}{
+								"type": "s3",
+								"config": map[string]interface{}{
+									"bucket": "kusion-state",
+									"region": "us-east-1",
+									"key":    "kusion",
+								},
+							},
+						},
+					},
+				},
+				want: want{
+					mainTF: "{\n  \"provider\": {\n    \"local\": null\n  },\n  \"resource\": {\n    \"local_file\": {\n      \"kusion_example\": {\n        \"content\": \"kusion\",\n        \"filename\": \"test.txt\"\n      }\n    }\n  },\n  \"terraform\": {\n    \"backend\": {\n      \"s3\": {\n        \"bucket\": \"kusion-state\",\n        \"key\": \"kusion/hashicorp/local/local_file/kusion_example.tfstate\",\n        \"region\": \"us-east-1\"\n      }\n    },\n    \"required_providers\": {\n      \"local\": {\n        \"source\": \"registry.terraform.io/hashicorp/local\",\n        \"version\": \"2.2.3\"\n      }\n    }\n  }\n}",
+				},
+			},
 		}
 
 		for name, tt := range cases {
@@ -0,0 +1,121 @@
+package generators
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	v1 "kusionstack.io/kusion/pkg/apis/api.kusion.io/v1"
+)
+
+// mockStackResources returns the resources of a representative stack with n apps, each of which has a
+// Deployment, a Service selecting it and a ConfigMap, spread in two namespaces.
+func mockStackResources(n int) v1.Resources {
+	resources := make(v1.Resources, 0, 3*n)
+	for i := 0; i < n; i++ {
+		name := fmt.Sprintf("app%d", i)
+		namespace := fmt.Sprintf("ns%d", i%2)
+		labels := map[string]interface{}{"app.kubernetes.io/name": name, "app.kubernetes.io/part-of": namespace}
+		resources = append(resources,
+			v1.Resource{
+				ID:   fmt.Sprintf("apps/v1:Deployment:%s:%s", namespace, name),
+				Type: v1.Kubernetes,
+				Attributes: map[string]interface{}{
+					"apiVersion": "apps/v1",
+					"kind":       "Deployment",
+					"metadata":   map[string]interface{}{"name": name, "namespace": namespace},
+					"spec": map[string]interface{}{
+						"selector": map[string]interface{}{"matchLabels": labels},
+						"template": map[string]interface{}{
+							"metadata": map[string]interface{}{"labels": labels},
+							"spec": map[string]interface{}{
+								"initContainers": []interface{}{
+									map[string]interface{}{"name": "init", "image": "busybox:1.36"},
+								},
+								"containers": []interface{}{
+									map[string]interface{}{"name": name, "image": "nginx:1.25"},
+									map[string]interface{}{"name": "sidecar", "image": "docker.io/envoyproxy/envoy:v1.29"},
+								},
+							},
+						},
+					},
+				},
+			},
+			v1.Resource{
+				ID:   fmt.Sprintf("v1:Service:%s:%s", namespace, name),
+				Type: v1.Kubernetes,
+				Attributes: map[string]interface{}{
+					"apiVersion": "v1",
+					"kind":       "Service",
+					"metadata": map[string]interface{}{
+						"name":        name,
+						"namespace":   namespace,
+						"annotations": map[string]interface{}{"owner": "kusion"},
+					},
+					"spec": map[string]interface{}{
+						"type":     "NodePort",
+						"selector": map[string]interface{}{"app.kubernetes.io/name": name},
+						"ports":    []interface{}{map[string]interface{}{"port": int64(80), "nodePort": int64(30080)}},
+					},
+				},
+			},
+			v1.Resource{
+				ID:   fmt.Sprintf("v1:ConfigMap:%s:%s", namespace, name),
+				Type: v1.Kubernetes,
+				Attributes: map[string]interface{}{
+					"apiVersion": "v1",
+					"kind":       "ConfigMap",
+					"metadata":   map[string]interface{}{"name": name, "namespace": namespace},
+					"data":       map[string]interface{}{"key": "value"},
+				},
+			},
+		)
+	}
+	return resources
+}
+
+var (
+	mockImageExtension    = &v1.KubeImageExtension{Registry: "mirror.example.com"}
+	mockServiceExtension  = &v1.KubeServiceExtension{Type: "ClusterIP", Annotations: map[string]string{"lb": "internal"}}
+	mockMetadataExtension = &v1.KubeMetadataExtension{Annotations: map[string]string{"team": "kusion", "lb": "external"}}
+)
+
+// postProcess runs the helpers customizing and validating the generated resources as the appconfiguration
+// generator and the apps builder do.
+func postProcess(resources v1.Resources) error {
+	if err := ResolveImages(resources, mockImageExtension); err != nil {
+		return err
+	}
+	if err := ApplyServiceExtension(resources, mockServiceExtension, mockMetadataExtension); err != nil {
+		return err
+	}
+	return ValidateServiceSelectors(resources)
+}
+
+// TestPostProcessOutput asserts the post-processed resources are identical to the golden output, which was
+// produced before the helpers were optimized to reduce the allocations.
+func TestPostProcessOutput(t *testing.T) {
+	resources := mockStackResources(4)
+	assert.NoError(t, postProcess(resources))
+
+	actual, err := json.MarshalIndent(resources, "", "  ")
+	assert.NoError(t, err)
+	expected, err := os.ReadFile("testdata/post_process.golden.json")
+	assert.NoError(t, err)
+	assert.Equal(t, string(expected), string(actual)+"\n")
+}
+
+func BenchmarkPostProcess(b *testing.B) {
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		b.StopTimer()
+		resources := mockStackResources(100)
+		b.StartTimer()
+		if err := postProcess(resources); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
@@ -37,6 +37,7 @@ import (
 	v1 "kusionstack.io/kusion/pkg/apis/api.kusion.io/v1"
 	"kusionstack.io/kusion/pkg/engine/runtime/terraform/tfops"
 	"kusionstack.io/kusion/pkg/generators"
+	inferreddeps "kusionstack.io/kusion/pkg/generators/inferreddependencies"
 	"kusionstack.io/kusion/pkg/generators/secret"
 	"kusionstack.io/kusion/pkg/log"
 
@@ -65,6 +66,12 @@ type appConfigurationGenerator struct {
 	app          *v1.AppConfiguration
 	ws           *v1.Workspace
 	dependencies *pkg.Dependencies
+	namePolicy   generators.NamePolicy
+	timings      *generators.TimingReport
+	// moduleOutputs caches the module outputs for the incremental generation, nil to call all the modules.
+	moduleOutputs *generators.ModuleOutputCache
+	// moduleConfigs caches the module configs resolved for the project, nil to resolve them for each app.
+	moduleConfigs *workspace.ModuleConfigCache
 }
 
 func NewAppConfigurationGenerator(
@@ -106,6 +113,7 @@ func NewAppConfigurationGenerator(
 		app:          app,
 		ws:           ws,
 		dependencies: dependencies,
+		namePolicy:   generators.DefaultNamePolicy,
 	}, nil
 }
 
@@ -122,6 +130,45 @@ func NewAppConfigurationGeneratorFunc(
 	}
 }
 
+// SetNamePolicy constructs the names of the namespace and secrets by the policy, defaults to
+// generators.DefaultNamePolicy.
+func (g *appConfigurationGenerator) SetNamePolicy(policy generators.NamePolicy) {
+	g.namePolicy = generators.NamePolicyOrDefault(policy)
+}
+
+// SetTimingReport records the time spent by each built-in generator and module to the report.
+func (g *appConfigurationGenerator) SetTimingReport(report *generators.TimingReport) {
+	g.timings = report
+}
+
+// SetModuleOutputCache reuses the outputs of the modules with unchanged inputs in the cache.
+func (g *appConfigurationGenerator) SetModuleOutputCache(cache *generators.ModuleOutputCache) {
+	g.moduleOutputs = cache
+}
+
+// SetModuleConfigCache reuses the module configs resolved by the other apps of the same generation.
+func (g *appConfigurationGenerator) SetModuleConfigCache(cache *workspace.ModuleConfigCache) {
+	g.moduleConfigs = cache
+}
+
+// WithModuleConfigCache wraps the NewSpecGeneratorFunc so that the module configs are resolved by the
+// cache, which should be shared by the apps of a single generation only.
+func WithModuleConfigCache(cache *workspace.ModuleConfigCache, newGenerator generators.NewSpecGeneratorFunc) generators.NewSpecGeneratorFunc {
+	if cache == nil {
+		return newGenerator
+	}
+	return func() (generators.SpecGenerator, error) {
+		g, err := newGenerator()
+		if err != nil {
+			return nil, err
+		}
+		if acg, ok := g.(*appConfigurationGenerator); ok {
+			acg.SetModuleConfigCache(cache)
+		}
+		return g, nil
+	}
+}
+
 func (g *appConfigurationGenerator) Generate(spec *v1.Spec) error {
 	if spec.Resources == nil {
 		spec.Resources = make(v1.Resources, 0)
@@ -129,7 +176,7 @@ func (g *appConfigurationGenerator) Generate(spec *v1.Spec) error {
 	g.app.Name = g.appName
 
 	// retrieve the module configs of the specified project
-	projectModuleConfigs, err := workspace.GetProjectModuleConfigs(g.ws.Modules, g.project.Name)
+	projectModuleConfigs, err := g.moduleConfigs.GetProjectModuleConfigs(g.ws.Modules, g.project.Name)
 	if err != nil {
 		return err
 	}
@@ -151,20 +198,35 @@ func (g *appConfigurationGenerator) Generate(spec *v1.Spec) error {
 		}
 	}
 
+	// the resources generated by this app, which are appended to the ones of the other apps
+	appResourcesStart := len(spec.Resources)
+
 	// generate built-in resources
-	namespace := g.getNamespaceName()
+	namespace, err := g.getNamespaceName()
+	if err != nil {
+		return err
+	}
 	gfs := []generators.NewSpecGeneratorFunc{
-		ns.NewNamespaceGeneratorFunc(namespace),
+		generators.Timed(g.timings, "namespace", ns.NewNamespaceGeneratorFunc(namespace)),
 	}
 
+	var renamedSecrets map[string]string
 	if g.app.Workload != nil {
 		// todo: refactor secret into a module
-		gfs = append(gfs, secret.NewSecretGeneratorFunc(&secret.GeneratorRequest{
+		secretRequest := &secret.GeneratorRequest{
 			Project:     g.project.Name,
+			Stack:       g.stack.Name,
+			App:         g.appName,
 			Namespace:   namespace,
 			Workload:    g.app.Workload,
 			SecretStore: g.ws.SecretStore,
-		}))
+			Naming:      g.getNamingExtension(),
+			NamePolicy:  g.namePolicy,
+		}
+		if renamedSecrets, err = secret.RenamedSecrets(secretRequest); err != nil {
+			return err
+		}
+		gfs = append(gfs, generators.Timed(g.timings, "secret", secret.NewSecretGeneratorFunc(secretRequest)))
 	}
 
 	if err = generators.CallGenerators(spec, gfs...); err != nil {
@@ -178,6 +240,7 @@ func (g *appConfigurationGenerator) Generate(spec *v1.Spec) error {
 	}
 
 	// append the generated resources to the spec
+	moduleResourcesStart := len(spec.Resources)
 	if wl != nil {
 		spec.Resources = append(spec.Resources, *wl)
 	}
@@ -193,13 +256,55 @@ func (g *appConfigurationGenerator) Generate(spec *v1.Spec) error {
 		}
 	}
 
+	// The modules refer to the secrets by their declared names, which follow the secrets renamed by the
+	// naming extension or the NamePolicy.
+	generators.RenameSecretReferences(spec.Resources[moduleResourcesStart:], renamedSecrets)
+
 	// Patch the imported resource IDs to the resource `extensions` in Spec.
 	if err = patchImportedResources(spec.Resources, projectImportedResources); err != nil {
 		return err
 	}
 
-	// The OrderedResourcesGenerator should be executed after all resources are generated.
-	if err = generators.CallGenerators(spec, orderedres.NewOrderedResourcesGeneratorFunc()); err != nil {
+	// Resolve the container images of the workload and accessories of this app, e.g. to pull from a
+	// registry mirror, where the images of the other apps have been resolved by their own generators.
+	if err = generators.ResolveImages(spec.Resources[appResourcesStart:], g.getImageExtension()); err != nil {
+		return err
+	}
+
+	// Customize the generated Services, e.g. the type and annotations required by the cloud LB controllers.
+	if err = generators.ApplyServiceExtension(spec.Resources, g.getServiceExtension(), g.getMetadataExtension()); err != nil {
+		return err
+	}
+
+	// Override the fields of the generated resources with the merge patches, and then tweak the resources
+	// generated by this app with the JSON Patch operations no other extension covers, which are not
+	// idempotent and must not be applied to the resources of the other apps again.
+	if err = generators.ApplyMergePatchExtension(spec.Resources, g.getMergePatchExtension()); err != nil {
+		return err
+	}
+	if err = generators.ApplyPatchExtension(spec.Resources[appResourcesStart:], g.getPatchExtension()); err != nil {
+		return err
+	}
+
+	// Render the templates in the attribute values of the resources generated by this app against the
+	// context and the resolved module configs.
+	if err = generators.RenderAttributeTemplates(spec.Resources[appResourcesStart:], generators.AttributeTemplateData{
+		Project:   g.project.Name,
+		Stack:     g.stack.Name,
+		Workspace: g.ws.Name,
+		App:       g.appName,
+		Context:   g.ws.Context,
+		Config:    projectModuleConfigs,
+	}); err != nil {
+		return err
+	}
+
+	// The InferredDependenciesGenerator and OrderedResourcesGenerator should be executed after all resources are generated.
+	if err = generators.CallGenerators(
+		spec,
+		generators.Timed(g.timings, "inferredDependencies", inferreddeps.NewInferredDependenciesGeneratorFunc()),
+		generators.Timed(g.timings, "orderedResources", orderedres.NewOrderedResourcesGeneratorFunc()),
+	); err != nil {
 		return err
 	}
 
@@ -291,96 +396,43 @@ func PatchWorkload(workload *v1.Resource, patcher *v1.Patcher) error {
 	}
 	un.SetUnstructuredContent(attributes)
 
+	// NOTE: we implement value-based map removal by agreeing on a specific value
+	// `ops://kusionstack.io/remove` as the `remove` operation index for the label and annotation patchers.
+	// The labels and annotations are merged in place, which are safe to modify for the attributes have
+	// been normalized into new maps above.
+
 	// patch labels
 	if patcher.Labels != nil {
-		objLabels := un.GetLabels()
-		if objLabels == nil {
-			objLabels = make(map[string]string)
-		}
-		// merge labels
-		for k, v := range patcher.Labels {
-			// NOTE: we implement value-based map removal by agreeing on a specific value
-			// `ops://kusionstack.io/remove` as the `remove` operation index for label patcher.
-			if v == removalVal {
-				delete(objLabels, k)
-				continue
-			}
-
-			objLabels[k] = v
+		if err = generators.MergeStringMapField(un.Object, patcher.Labels, removalVal, "metadata", "labels"); err != nil {
+			// the labels not in strings are dropped as GetLabels does
+			unstructured.RemoveNestedField(un.Object, "metadata", "labels")
+			_ = generators.MergeStringMapField(un.Object, patcher.Labels, removalVal, "metadata", "labels")
 		}
-		un.SetLabels(objLabels)
 	}
 
 	// patch pod labels
 	if patcher.PodLabels != nil {
-		podLabels, b, err := unstructured.NestedStringMap(un.Object, "spec", "template", "metadata", "labels")
+		err = generators.MergeStringMapField(un.Object, patcher.PodLabels, removalVal, "spec", "template", "metadata", "labels")
 		if err != nil {
 			return fmt.Errorf("failed to get pod labels from workload:%s. %w", workload.ID, err)
 		}
-		if !b || podLabels == nil {
-			podLabels = make(map[string]string)
-		}
-		// merge labels
-		for k, v := range patcher.PodLabels {
-			// NOTE: we implement value-based map removal by agreeing on a specific value
-			// `ops://kusionstack.io/remove` as the `remove` operation index for pod label patcher.
-			if v == removalVal {
-				delete(podLabels, k)
-				continue
-			}
-
-			podLabels[k] = v
-		}
-		err = unstructured.SetNestedStringMap(un.Object, podLabels, "spec", "template", "metadata", "labels")
-		if err != nil {
-			return err
-		}
 	}
 
 	// patch annotations
 	if patcher.Annotations != nil {
-		objAnnotations := un.GetAnnotations()
-		if objAnnotations == nil {
-			objAnnotations = make(map[string]string)
-		}
-		// merge annotations
-		for k, v := range patcher.Annotations {
-			// NOTE: we implement value-based map removal by agreeing on a specific value
-			// `ops://kusionstack.io/remove` as the `remove` operation index for annotation patcher.
-			if v == removalVal {
-				delete(objAnnotations, k)
-				continue
-			}
-
-			objAnnotations[k] = v
+		if err = generators.MergeStringMapField(un.Object, patcher.Annotations, removalVal, "metadata", "annotations"); err != nil {
+			// the annotations not in strings are dropped as GetAnnotations does
+			unstructured.RemoveNestedField(un.Object, "metadata", "annotations")
+			_ = generators.MergeStringMapField(un.Object, patcher.Annotations, removalVal, "metadata", "annotations")
 		}
-		un.SetAnnotations(objAnnotations)
 	}
 
 	// patch pod annotations
 	if patcher.PodAnnotations != nil {
-		podAnnotations, b, err := unstructured.NestedStringMap(un.Object, "spec", "template", "metadata", "annotations")
+		err = generators.MergeStringMapField(un.Object, patcher.PodAnnotations, removalVal, "spec", "template", "metadata", "annotations")
 		if err != nil {
 			return fmt.Errorf("failed to get pod annotations from workload:%s. %w", workload.ID, err)
 		}
-		if !b || podAnnotations == nil {
-			podAnnotations = make(map[string]string)
-		}
-		// merge annotations
-		for k, v := range patcher.PodAnnotations {
-			// NOTE: we implement value-based map removal by agreeing on a specific value
-			// `ops://kusionstack.io/remove` as the `remove` operation index for pod annotation patcher.
-			if v == removalVal {
-				delete(podAnnotations, k)
-				continue
-			}
-
-			podAnnotations[k] = v
-		}
-		err = unstructured.SetNestedStringMap(un.Object, podAnnotations, "spec", "template", "metadata", "annotations")
-		if err != nil {
-			return err
-		}
 	}
 
 	// patch env
@@ -503,7 +555,11 @@ func (g *appConfigurationGenerator) callModules(projectModuleConfigs map[string]
 
 	// generate customized module resources
 	for t, config := range indexModuleConfig {
-		response, err := g.invokeModule(pluginMap, t, config)
+		var response *proto.GeneratorResponse
+		err = g.timings.Time(t, func() (err error) {
+			response, err = g.invokeModule(pluginMap, t, config)
+			return err
+		})
 		if err != nil {
 			return nil, nil, nil, err
 		}
@@ -519,6 +575,7 @@ func (g *appConfigurationGenerator) callModules(projectModuleConfigs map[string]
 			}
 			// add isWorkload extension to workload to mark workload
 			workload.Extensions[isWorkload] = true
+			workload.SetModule(t)
 			// Add healthPolicy to workload extensions
 			if healthPolicy != nil && workload != nil {
 				patchHealthPolicy(workload, healthPolicy)
@@ -530,6 +587,8 @@ func (g *appConfigurationGenerator) callModules(projectModuleConfigs map[string]
 				if err != nil {
 					return nil, nil, nil, err
 				}
+				// annotate the resource with the module generating it
+				temp.SetModule(t)
 				// filter out workload
 				if workloadKey == t && temp.Extensions[isWorkload] == "true" {
 					workload = temp
@@ -568,6 +627,23 @@ func (g *appConfigurationGenerator) invokeModule(
 	key string,
 	config moduleConfig,
 ) (*proto.GeneratorResponse, error) {
+	// prepare the request
+	protoRequest, err := g.initModuleRequest(config)
+	if err != nil {
+		return nil, err
+	}
+
+	// reuse the output of the previous generation if the inputs of the module are unchanged
+	var outputKey, hash string
+	if g.moduleOutputs != nil {
+		outputKey = g.appName + "/" + key
+		hash = hashModuleRequest(key, protoRequest)
+		if output, ok := g.moduleOutputs.Get(outputKey, hash); ok {
+			log.Infof("reuse the output of module:%s with unchanged inputs", key)
+			return moduleOutputToResponse(output), nil
+		}
+	}
+
 	// init the plugin
 	if pluginMap[key] == nil {
 		plugin, err := module.NewPlugin(key, g.stack.Path)
@@ -581,12 +657,6 @@ func (g *appConfigurationGenerator) invokeModule(
 	}
 	plugin := pluginMap[key]
 
-	// prepare the request
-	protoRequest, err := g.initModuleRequest(config)
-	if err != nil {
-		return nil, err
-	}
-
 	// invoke the plugin
 	log.Infof("invoke module:%s with request:%s", key, protoRequest.String())
 	traceID, _ := uuid.NewUUID()
@@ -598,9 +668,46 @@ func (g *appConfigurationGenerator) invokeModule(
 	if response == nil {
 		return nil, fmt.Errorf("empty response from module %s", key)
 	}
+	if g.moduleOutputs != nil {
+		g.moduleOutputs.Put(outputKey, responseToModuleOutput(hash, response))
+	}
 	return response, nil
 }
 
+// hashModuleRequest returns the hash of the inputs of the module with the key, which includes the version.
+func hashModuleRequest(key string, request *proto.GeneratorRequest) string {
+	return generators.HashModuleInputs(
+		[]byte(key),
+		[]byte(request.Project),
+		[]byte(request.Stack),
+		[]byte(request.App),
+		request.Workload,
+		request.DevConfig,
+		request.PlatformConfig,
+		request.Context,
+		request.SecretStore,
+	)
+}
+
+func responseToModuleOutput(hash string, response *proto.GeneratorResponse) *v1.ModuleOutput {
+	output := &v1.ModuleOutput{Hash: hash, Patcher: string(response.Patcher)}
+	for _, res := range response.Resources {
+		output.Resources = append(output.Resources, string(res))
+	}
+	return output
+}
+
+func moduleOutputToResponse(output *v1.ModuleOutput) *proto.GeneratorResponse {
+	response := &proto.GeneratorResponse{}
+	for _, res := range output.Resources {
+		response.Resources = append(response.Resources, []byte(res))
+	}
+	if output.Patcher != "" {
+		response.Patcher = []byte(output.Patcher)
+	}
+	return response
+}
+
 func (g *appConfigurationGenerator) buildModuleConfigIndex(platformModuleConfigs map[string]v1.GenericConfig) (map[string]moduleConfig, error) {
 	indexModuleConfig := map[string]moduleConfig{}
 
@@ -720,22 +827,112 @@ func (g *appConfigurationGenerator) initModuleRequest(config moduleConfig) (*pro
 
 // getNamespaceName obtains the final namespace name using the following precedence
 // (from lower to higher):
-// - Project name
+// - Name constructed by the NamePolicy, which is the project name by default
+// - Name derived by the namespace strategy (specified in the workspace context)
 // - KubernetesNamespace extensions (specified in corresponding workspace file)
-func (g *appConfigurationGenerator) getNamespaceName() string {
+//
+// An error is returned if the final namespace name is not a valid DNS-1123 label.
+func (g *appConfigurationGenerator) getNamespaceName() (string, error) {
 	extensions := mergeExtensions(g.project, g.stack)
 	if len(extensions) != 0 {
 		for _, extension := range extensions {
 			switch extension.Kind {
 			case v1.KubernetesNamespace:
-				return extension.KubeNamespace.Namespace
+				if err := generators.ValidateNamespaceName(extension.KubeNamespace.Namespace); err != nil {
+					return "", fmt.Errorf("invalid namespace of the %s extension: %w", v1.KubernetesNamespace, err)
+				}
+				return extension.KubeNamespace.Namespace, nil
 			default:
 				// do nothing
 			}
 		}
 	}
 
-	return g.project.Name
+	strategy, err := workspace.GetNamespaceStrategy(g.ws.Context)
+	if err != nil {
+		return "", err
+	}
+	if strategy != nil {
+		return generators.DeriveNamespace(strategy, generators.NamespaceTemplateData{
+			Project:   g.project.Name,
+			Stack:     g.stack.Name,
+			Workspace: g.ws.Name,
+			App:       g.appName,
+			Labels:    g.project.Labels,
+		})
+	}
+
+	namespace := generators.NamePolicyOrDefault(g.namePolicy).Name(generators.RoleNamespace, g.project.Name, g.stack.Name, g.appName)
+	if err = generators.ValidateNamespaceName(namespace); err != nil {
+		return "", fmt.Errorf("invalid namespace constructed by the name policy: %w", err)
+	}
+	return namespace, nil
+}
+
+// getNamingExtension obtains the KubernetesNaming extension of the stack or project, and
+// returns nil if not specified.
+func (g *appConfigurationGenerator) getNamingExtension() *v1.KubeNamingExtension {
+	for _, extension := range mergeExtensions(g.project, g.stack) {
+		if extension.Kind == v1.KubernetesNaming {
+			return &extension.KubeNaming
+		}
+	}
+	return nil
+}
+
+// getImageExtension obtains the KubernetesImage extension of the stack or project, and
+// returns nil if not specified.
+func (g *appConfigurationGenerator) getImageExtension() *v1.KubeImageExtension {
+	for _, extension := range mergeExtensions(g.project, g.stack) {
+		if extension.Kind == v1.KubernetesImage {
+			return &extension.KubeImage
+		}
+	}
+	return nil
+}
+
+// getServiceExtension obtains the KubernetesService extension of the stack or project, and
+// returns nil if not specified.
+func (g *appConfigurationGenerator) getServiceExtension() *v1.KubeServiceExtension {
+	for _, extension := range mergeExtensions(g.project, g.stack) {
+		if extension.Kind == v1.KubernetesService {
+			return &extension.KubeService
+		}
+	}
+	return nil
+}
+
+// getMetadataExtension obtains the KubernetesMetadata extension of the stack or project, and
+// returns nil if not specified.
+func (g *appConfigurationGenerator) getMetadataExtension() *v1.KubeMetadataExtension {
+	for _, extension := range mergeExtensions(g.project, g.stack) {
+		if extension.Kind == v1.KubernetesMetadata {
+			return &extension.KubeMetadata
+		}
+	}
+	return nil
+}
+
+// getPatchExtension obtains the patch extension of the stack or project, and returns nil if not
+// specified.
+func (g *appConfigurationGenerator) getPatchExtension() *v1.KubePatchExtension {
+	for _, extension := range mergeExtensions(g.project, g.stack) {
+		if extension.Kind == v1.KubernetesPatch {
+			return &extension.KubePatch
+		}
+	}
+	return nil
+}
+
+// getMergePatchExtension obtains the merge patch extension of the stack or project, and returns nil if
+// not specified.
+func (g *appConfigurationGenerator) getMergePatchExtension() *v1.KubeMergePatchExtension {
+	for _, extension := range mergeExtensions(g.project, g.stack) {
+		if extension.Kind == v1.KubernetesMergePatch {
+			return &extension.KubeMergePatch
+		}
+	}
+	return nil
 }
 
 func mergeExtensions(project *v1.Project, stack *v1.Stack) []*v1.Extension {
@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"strings"
 	"testing"
 
 	"github.com/bytedance/mockey"
@@ -21,6 +22,7 @@ import (
 	"kusionstack.io/kusion-module-framework/pkg/module"
 	"kusionstack.io/kusion-module-framework/pkg/module/proto"
 	v1 "kusionstack.io/kusion/pkg/apis/api.kusion.io/v1"
+	"kusionstack.io/kusion/pkg/generators"
 	jsonutil "kusionstack.io/kusion/pkg/util/json"
 )
 
@@ -28,7 +30,7 @@ type fakeModule struct{}
 
 func (f *fakeModule) Generate(_ context.Context, _ *proto.GeneratorRequest) (*proto.GeneratorResponse, error) {
 	res := v1.Resource{
-		ID:   "apps.kusionstack.io/v1alpha1:PodTransitionRule:fakeNs:default-dev-foo",
+		ID:   "apps.kusionstack.io/v1alpha1:PodTransitionRule:fake-ns:default-dev-foo",
 		Type: "Kubernetes",
 		Attributes: map[string]interface{}{
 			"apiVersion": "apps.kusionstack.io/v1alpha1",
@@ -36,7 +38,7 @@ func (f *fakeModule) Generate(_ context.Context, _ *proto.GeneratorRequest) (*pr
 			"metadata": map[string]interface{}{
 				"creationTimestamp": interface{}(nil),
 				"name":              "default-dev-foo",
-				"namespace":         "fakeNs",
+				"namespace":         "fake-ns",
 			},
 			"spec": map[string]interface{}{
 				"rules": []interface{}{map[string]interface{}{
@@ -93,7 +95,7 @@ func TestAppConfigurationGenerator_Generate_CustomNamespace(t *testing.T) {
 	kubeNamespaceExt := &v1.Extension{
 		Kind: v1.KubernetesNamespace,
 		KubeNamespace: v1.KubeNamespaceExtension{
-			Namespace: "fakeNs",
+			Namespace: "fake-ns",
 		},
 	}
 	project.Extensions = []*v1.Extension{kubeNamespaceExt}
@@ -127,7 +129,7 @@ func TestAppConfigurationGenerator_Generate_CustomNamespace(t *testing.T) {
 		}
 		actual := mapToUnstructured(res.Attributes)
 		if actual.GetKind() == "Namespace" {
-			assert.Equal(t, "fakeNs", actual.GetName(), "namespace name should be fakeNs")
+			assert.Equal(t, "fake-ns", actual.GetName(), "namespace name should be fake-ns")
 		} else {
 			ns := actual.GetNamespace()
 			if ns == "" {
@@ -136,7 +138,306 @@ func TestAppConfigurationGenerator_Generate_CustomNamespace(t *testing.T) {
 					t.Fatal(err)
 				}
 			}
-			assert.Equal(t, "fakeNs", ns, "namespace name should be fakeNs")
+			assert.Equal(t, "fake-ns", ns, "namespace name should be fake-ns")
+		}
+	}
+}
+
+// containerModule generates a Deployment of the app with a container of the image.
+type containerModule struct {
+	image string
+}
+
+func (m *containerModule) Generate(_ context.Context, req *proto.GeneratorRequest) (*proto.GeneratorResponse, error) {
+	res := v1.Resource{
+		ID:   "apps/v1:Deployment:fake-ns:" + req.App,
+		Type: v1.Kubernetes,
+		Attributes: map[string]interface{}{
+			"apiVersion": "apps/v1",
+			"kind":       "Deployment",
+			"metadata": map[string]interface{}{
+				"name":      req.App,
+				"namespace": "fake-ns",
+			},
+			"spec": map[string]interface{}{
+				"template": map[string]interface{}{
+					"spec": map[string]interface{}{
+						"containers": []interface{}{
+							map[string]interface{}{"name": req.App, "image": m.image},
+						},
+					},
+				},
+			},
+		},
+	}
+	return &proto.GeneratorResponse{
+		Resources: [][]byte{[]byte(jsonutil.Marshal2String(res))},
+	}, nil
+}
+
+func TestAppConfigurationGenerator_Generate_ImagesOfMultipleApps(t *testing.T) {
+	deps := orderedmap.NewOrderedMap[string, pkg.Dependency]()
+	deps.Set("service", pkg.Dependency{
+		Name:    "service",
+		Version: "1.0.0",
+	})
+	dep := &pkg.Dependencies{
+		Deps: deps,
+	}
+
+	project, stack := buildMockProjectAndStack()
+	project.Extensions = []*v1.Extension{
+		{
+			Kind: v1.KubernetesImage,
+			KubeImage: v1.KubeImageExtension{
+				Registry: "mirror.example.com/dockerhub",
+			},
+		},
+	}
+
+	pluginMock := mockey.Mock(module.NewPlugin).To(func(key string) (*module.Plugin, error) {
+		return &module.Plugin{Module: &containerModule{image: "mysql:8.0"}}, nil
+	}).Build()
+	killMock := mockey.Mock((*module.Plugin).KillPluginClient).Return(nil).Build()
+	defer func() {
+		pluginMock.UnPatch()
+		killMock.UnPatch()
+	}()
+
+	// the apps are generated into the same spec one by one
+	spec := &v1.Spec{
+		Resources: []v1.Resource{},
+	}
+	for _, appName := range []string{"foo", "bar"} {
+		_, app := buildMockApp()
+		app.Accessories = nil
+		g := &appConfigurationGenerator{
+			project:      project,
+			stack:        stack,
+			appName:      appName,
+			app:          app,
+			ws:           buildMockWorkspace(),
+			dependencies: dep,
+		}
+		assert.NoError(t, g.Generate(spec))
+	}
+
+	// the images of each app are resolved once
+	var images []string
+	for _, res := range spec.Resources {
+		if res.Type != v1.Kubernetes || mapToUnstructured(res.Attributes).GetKind() != "Deployment" {
+			continue
+		}
+		containers, _, err := unstructured.NestedSlice(res.Attributes, "spec", "template", "spec", "containers")
+		assert.NoError(t, err)
+		images = append(images, containers[0].(map[string]interface{})["image"].(string))
+	}
+	assert.Equal(t, []string{
+		"mirror.example.com/dockerhub/library/mysql:8.0",
+		"mirror.example.com/dockerhub/library/mysql:8.0",
+	}, images)
+}
+
+func TestAppConfigurationGenerator_Generate_PatchesOfMultipleApps(t *testing.T) {
+	deps := orderedmap.NewOrderedMap[string, pkg.Dependency]()
+	deps.Set("service", pkg.Dependency{
+		Name:    "service",
+		Version: "1.0.0",
+	})
+	dep := &pkg.Dependencies{
+		Deps: deps,
+	}
+
+	project, stack := buildMockProjectAndStack()
+	project.Extensions = []*v1.Extension{
+		{
+			Kind: v1.KubernetesPatch,
+			KubePatch: v1.KubePatchExtension{
+				Patches: []v1.KubePatch{
+					{
+						Target: v1.KubePatchTarget{Kind: "Deployment"},
+						Operations: []v1.JSONPatchOperation{
+							{
+								Op:    "add",
+								Path:  "/spec/template/spec/containers/-",
+								Value: map[string]interface{}{"name": "sidecar", "image": "envoy:1.28"},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	pluginMock := mockey.Mock(module.NewPlugin).To(func(key string) (*module.Plugin, error) {
+		return &module.Plugin{Module: &containerModule{image: "mysql:8.0"}}, nil
+	}).Build()
+	killMock := mockey.Mock((*module.Plugin).KillPluginClient).Return(nil).Build()
+	defer func() {
+		pluginMock.UnPatch()
+		killMock.UnPatch()
+	}()
+
+	// the apps are generated into the same spec one by one
+	spec := &v1.Spec{
+		Resources: []v1.Resource{},
+	}
+	for _, appName := range []string{"foo", "bar"} {
+		_, app := buildMockApp()
+		app.Accessories = nil
+		g := &appConfigurationGenerator{
+			project:      project,
+			stack:        stack,
+			appName:      appName,
+			app:          app,
+			ws:           buildMockWorkspace(),
+			dependencies: dep,
+		}
+		assert.NoError(t, g.Generate(spec))
+	}
+
+	// the patch is applied to the Deployment of each app once
+	deployments := 0
+	for _, res := range spec.Resources {
+		if res.Type != v1.Kubernetes || mapToUnstructured(res.Attributes).GetKind() != "Deployment" {
+			continue
+		}
+		deployments++
+		containers, _, err := unstructured.NestedSlice(res.Attributes, "spec", "template", "spec", "containers")
+		assert.NoError(t, err)
+		assert.Len(t, containers, 2, "resource %s", res.ID)
+	}
+	assert.Equal(t, 2, deployments)
+}
+
+func TestAppConfigurationGenerator_getNamespaceName(t *testing.T) {
+	testcases := []struct {
+		name        string
+		projectName string
+		extension   *v1.Extension
+		context     v1.GenericConfig
+		expected    string
+		errMsg      string
+	}{
+		{
+			name:     "project name by default",
+			expected: "testproject",
+		},
+		{
+			name:        "normalized project name by default",
+			projectName: "Test_Project.v2",
+			expected:    "test-project-v2",
+		},
+		{
+			name: "valid namespace extension",
+			extension: &v1.Extension{
+				Kind:          v1.KubernetesNamespace,
+				KubeNamespace: v1.KubeNamespaceExtension{Namespace: "fake-ns"},
+			},
+			expected: "fake-ns",
+		},
+		{
+			name: "uppercase namespace extension",
+			extension: &v1.Extension{
+				Kind:          v1.KubernetesNamespace,
+				KubeNamespace: v1.KubeNamespaceExtension{Namespace: "fakeNs"},
+			},
+			errMsg: `invalid namespace of the kubernetesNamespace extension: invalid namespace name "fakeNs"`,
+		},
+		{
+			name: "too long namespace extension",
+			extension: &v1.Extension{
+				Kind:          v1.KubernetesNamespace,
+				KubeNamespace: v1.KubeNamespaceExtension{Namespace: strings.Repeat("a", 64)},
+			},
+			errMsg: "must be no more than 63 characters",
+		},
+		{
+			name: "namespace derived by strategy",
+			context: v1.GenericConfig{
+				v1.ContextKeyNamespaceStrategy: map[string]any{"type": "project-stack"},
+			},
+			expected: "testproject-test",
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			project, stack := buildMockProjectAndStack()
+			if tc.projectName != "" {
+				project.Name = tc.projectName
+			}
+			if tc.extension != nil {
+				project.Extensions = []*v1.Extension{tc.extension}
+			}
+			ws := buildMockWorkspace()
+			ws.Context = tc.context
+			g := &appConfigurationGenerator{project: project, stack: stack, ws: ws}
+
+			actual, err := g.getNamespaceName()
+			if tc.errMsg != "" {
+				assert.ErrorContains(t, err, tc.errMsg)
+				assert.ErrorIs(t, err, generators.ErrInvalidNamespaceName)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expected, actual)
+		})
+	}
+}
+
+type teamNamePolicy struct{}
+
+func (teamNamePolicy) Name(role, project, stack, app string) string {
+	return fmt.Sprintf("team-%s-%s", project, stack)
+}
+
+func TestAppConfigurationGenerator_Generate_CustomNamePolicy(t *testing.T) {
+	appName, app := buildMockApp()
+	app.Workload["secrets"] = map[string]v1.Secret{
+		"api-token": {
+			Type: "token",
+		},
+	}
+	ws := buildMockWorkspace()
+
+	deps := orderedmap.NewOrderedMap[string, pkg.Dependency]()
+	deps.Set("port", pkg.Dependency{
+		Name:    "port",
+		Version: "1.0.0",
+	})
+	deps.Set("service", pkg.Dependency{
+		Name:    "service",
+		Version: "1.0.0",
+	})
+	dep := &pkg.Dependencies{
+		Deps: deps,
+	}
+
+	project, stack := buildMockProjectAndStack()
+	generator, err := generators.WithNamePolicy(teamNamePolicy{},
+		NewAppConfigurationGeneratorFunc(project, stack, appName, app, ws, dep))()
+	assert.NoError(t, err)
+
+	spec := &v1.Spec{
+		Resources: []v1.Resource{},
+	}
+
+	m1, m2 := mockPlugin()
+	defer func() {
+		m1.UnPatch()
+		m2.UnPatch()
+	}()
+
+	err = generator.Generate(spec)
+	assert.NoError(t, err)
+
+	// every resource generated by the built-in generators uses the custom name policy
+	for _, res := range spec.Resources {
+		actual := mapToUnstructured(res.Attributes)
+		switch actual.GetKind() {
+		case "Namespace", "Secret":
+			assert.Equal(t, "team-testproject-test", actual.GetName())
 		}
 	}
 }
@@ -447,6 +748,14 @@ func TestAppConfigurationGenerator_CallModules(t *testing.T) {
 		assert.NotEmpty(t, wl)
 		assert.NotEmpty(t, resources)
 		assert.Empty(t, patchers)
+
+		// the generated resources are annotated with the module
+		for _, res := range resources {
+			module, ok := res.Module()
+			assert.True(t, ok)
+			assert.Equal(t, "kusionstack/module1@1.0.0", module)
+		}
+		assert.Len(t, v1.Resources(resources).GroupByModule()["kusionstack/module1@1.0.0"], len(resources))
 	})
 
 	t.Run("Failed module call due to missing module in dependencies", func(t *testing.T) {
@@ -477,6 +786,83 @@ func TestAppConfigurationGenerator_CallModules(t *testing.T) {
 	})
 }
 
+// countingModule is the fakeModule counting the calls.
+type countingModule struct {
+	fakeModule
+	calls int
+}
+
+func (m *countingModule) Generate(ctx context.Context, req *proto.GeneratorRequest) (*proto.GeneratorResponse, error) {
+	m.calls++
+	return m.fakeModule.Generate(ctx, req)
+}
+
+func TestAppConfigurationGenerator_CallModulesIncremental(t *testing.T) {
+	deps := orderedmap.NewOrderedMap[string, pkg.Dependency]()
+	deps.Set("port", pkg.Dependency{
+		Version: "1.0.0",
+		Source: downloader.Source{
+			Oci: &downloader.Oci{
+				Repo: "kusionstack/module1",
+			},
+		},
+	})
+	deps.Set("service", pkg.Dependency{
+		Version: "1.0.0",
+		Name:    "service",
+	})
+	_, appConfig := buildMockApp()
+	project, stack := buildMockProjectAndStack()
+	g := &appConfigurationGenerator{
+		project:      project,
+		stack:        stack,
+		appName:      "testapp",
+		app:          appConfig,
+		ws:           buildMockWorkspace(),
+		dependencies: &pkg.Dependencies{Deps: deps},
+	}
+
+	m := &countingModule{}
+	pluginMock := mockey.Mock(module.NewPlugin).To(func(key string) (*module.Plugin, error) {
+		return &module.Plugin{Module: m}, nil
+	}).Build()
+	killMock := mockey.Mock((*module.Plugin).KillPluginClient).Return(nil).Build()
+	defer func() {
+		pluginMock.UnPatch()
+		killMock.UnPatch()
+	}()
+
+	// the first generation calls all the modules, i.e. the workload and the port
+	projectModuleConfigs := map[string]v1.GenericConfig{"port": {"config1": "value1"}}
+	cache := generators.NewModuleOutputCache(nil)
+	g.SetModuleOutputCache(cache)
+	wl, resources, _, err := g.callModules(projectModuleConfigs)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, m.calls)
+	outputs := cache.Outputs()
+	assert.Len(t, outputs, 2)
+	assert.Contains(t, outputs, "testapp/kusionstack/module1@1.0.0")
+
+	// the unchanged modules are reused
+	cache = generators.NewModuleOutputCache(outputs)
+	g.SetModuleOutputCache(cache)
+	reusedWl, reusedResources, _, err := g.callModules(projectModuleConfigs)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, m.calls)
+	assert.Equal(t, wl, reusedWl)
+	assert.Equal(t, resources, reusedResources)
+	assert.Equal(t, outputs, cache.Outputs())
+
+	// only the module with the changed config is regenerated
+	projectModuleConfigs["port"]["config1"] = "value2"
+	cache = generators.NewModuleOutputCache(cache.Outputs())
+	g.SetModuleOutputCache(cache)
+	_, _, _, err = g.callModules(projectModuleConfigs)
+	assert.NoError(t, err)
+	assert.Equal(t, 3, m.calls)
+	assert.NotEqual(t, outputs["testapp/kusionstack/module1@1.0.0"].Hash, cache.Outputs()["testapp/kusionstack/module1@1.0.0"].Hash)
+}
+
 func TestJsonPatch(t *testing.T) {
 	t.Run("ResourcesNil", func(t *testing.T) {
 		err := JSONPatch(nil, &v1.Patcher{})
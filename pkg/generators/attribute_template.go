@@ -0,0 +1,158 @@
+package generators
+
+import (
+	"bytes"
+	"encoding/base64"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"text/template"
+
+	v1 "kusionstack.io/kusion/pkg/apis/api.kusion.io/v1"
+	"kusionstack.io/kusion/pkg/util/kerrors"
+)
+
+// The delimiters of the attribute templates, which differ from the default ones of Go templates so that
+// the attributes containing "{{", e.g. the alerting rules of Prometheus, are left as they are. The left
+// delimiter is escaped by AttributeTemplateEscapedLeftDelim, e.g. "$${{ github.sha }}" is rendered as
+// "${{ github.sha }}" for the GitHub Actions expressions.
+const (
+	AttributeTemplateLeftDelim        = "${{"
+	AttributeTemplateRightDelim       = "}}"
+	AttributeTemplateEscapedLeftDelim = "$" + AttributeTemplateLeftDelim
+)
+
+var ErrInvalidAttributeTemplate = kerrors.New(kerrors.ErrValidation, "invalid attribute template")
+
+// AttributeTemplateData is the data available to the attribute templates.
+type AttributeTemplateData struct {
+	Project   string
+	Stack     string
+	Workspace string
+	App       string
+	// Context is the context of the workspace.
+	Context map[string]any
+	// Config is the resolved module configs of the workspace for the project, whose key is the module name.
+	Config map[string]v1.GenericConfig
+}
+
+// attributeTemplateFuncs is the curated function set available to the attribute templates, which are named
+// and behave like the ones of sprig.
+var attributeTemplateFuncs = template.FuncMap{
+	"upper":      strings.ToUpper,
+	"lower":      strings.ToLower,
+	"trim":       strings.TrimSpace,
+	"trimPrefix": func(prefix, s string) string { return strings.TrimPrefix(s, prefix) },
+	"trimSuffix": func(suffix, s string) string { return strings.TrimSuffix(s, suffix) },
+	"replace":    func(old, new, s string) string { return strings.ReplaceAll(s, old, new) },
+	"contains":   func(substr, s string) bool { return strings.Contains(s, substr) },
+	"join":       func(sep string, elems []string) string { return strings.Join(elems, sep) },
+	"split":      func(sep, s string) []string { return strings.Split(s, sep) },
+	"quote":      strconv.Quote,
+	"default":    defaultValue,
+	"indent":     indent,
+	"nindent":    func(spaces int, s string) string { return "\n" + indent(spaces, s) },
+	"b64enc":     func(s string) string { return base64.StdEncoding.EncodeToString([]byte(s)) },
+	"b64dec": func(s string) (string, error) {
+		data, err := base64.StdEncoding.DecodeString(s)
+		return string(data), err
+	},
+}
+
+// defaultValue returns the given value, or the default one if the given value is empty, i.e. nil, zero,
+// or an empty string, slice or map.
+func defaultValue(defaultVal any, given ...any) any {
+	if len(given) == 0 || given[0] == nil {
+		return defaultVal
+	}
+	v := reflect.ValueOf(given[0])
+	switch v.Kind() {
+	case reflect.String, reflect.Slice, reflect.Map, reflect.Array:
+		if v.Len() == 0 {
+			return defaultVal
+		}
+	default:
+		if v.IsZero() {
+			return defaultVal
+		}
+	}
+	return given[0]
+}
+
+// indent indents each line of the string with the spaces.
+func indent(spaces int, s string) string {
+	pad := strings.Repeat(" ", spaces)
+	return pad + strings.ReplaceAll(s, "\n", "\n"+pad)
+}
+
+// RenderAttributeTemplates renders the string attribute values of the resources which contain templates
+// delimited by AttributeTemplateLeftDelim and AttributeTemplateRightDelim, e.g. ${{ .Project | upper }},
+// against the data, and replaces the values with the rendered results. Only the resources opted in by
+// the extension v1.ResourceExtensionAttributeTemplate are rendered, so that the attributes of the others
+// are kept as they are. An error naming the resource and the attribute path is returned if any template
+// fails to parse or render.
+func RenderAttributeTemplates(resources v1.Resources, data AttributeTemplateData) error {
+	for i := range resources {
+		if !resources[i].AttributeTemplateEnabled() {
+			continue
+		}
+		// the attributes are rendered in place
+		if _, err := renderAttributeTemplates(resources[i].Attributes, "", data); err != nil {
+			return fmt.Errorf("%w of resource %s: %w", ErrInvalidAttributeTemplate, resources[i].ID, err)
+		}
+	}
+	return nil
+}
+
+func renderAttributeTemplates(value any, path string, data AttributeTemplateData) (any, error) {
+	switch v := value.(type) {
+	case map[string]any:
+		for key, item := range v {
+			rendered, err := renderAttributeTemplates(item, joinAttributePath(path, key), data)
+			if err != nil {
+				return nil, err
+			}
+			v[key] = rendered
+		}
+		return v, nil
+	case []any:
+		for i, item := range v {
+			rendered, err := renderAttributeTemplates(item, fmt.Sprintf("%s[%d]", path, i), data)
+			if err != nil {
+				return nil, err
+			}
+			v[i] = rendered
+		}
+		return v, nil
+	case string:
+		if !strings.Contains(v, AttributeTemplateLeftDelim) {
+			return v, nil
+		}
+		// the escaped left delimiter is rendered as the literal one
+		v = strings.ReplaceAll(v, AttributeTemplateEscapedLeftDelim,
+			AttributeTemplateLeftDelim+strconv.Quote(AttributeTemplateLeftDelim)+AttributeTemplateRightDelim)
+		tmpl, err := template.New(path).
+			Delims(AttributeTemplateLeftDelim, AttributeTemplateRightDelim).
+			Option("missingkey=error").
+			Funcs(attributeTemplateFuncs).
+			Parse(v)
+		if err != nil {
+			return nil, fmt.Errorf("attribute %s: %w", path, err)
+		}
+		var buf bytes.Buffer
+		if err = tmpl.Execute(&buf, data); err != nil {
+			return nil, fmt.Errorf("attribute %s: %w", path, err)
+		}
+		return buf.String(), nil
+	default:
+		return v, nil
+	}
+}
+
+func joinAttributePath(path, key string) string {
+	if path == "" {
+		return key
+	}
+	return path + "." + key
+}
@@ -0,0 +1,121 @@
+package generators
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	v1 "kusionstack.io/kusion/pkg/apis/api.kusion.io/v1"
+)
+
+func TestRenderAttributeTemplates(t *testing.T) {
+	data := AttributeTemplateData{
+		Project:   "helloworld",
+		Stack:     "dev",
+		Workspace: "prod",
+		App:       "app",
+		Context:   map[string]any{"region": "us-east-1", "owner": ""},
+		Config:    map[string]v1.GenericConfig{"mysql": {"instanceType": "db.t3.micro"}},
+	}
+
+	testcases := []struct {
+		name       string
+		attributes map[string]any
+		expected   map[string]any
+		errMsg     string
+	}{
+		{
+			name: "render functions",
+			attributes: map[string]any{
+				"metadata": map[string]any{
+					"name": "${{ .Project }}-${{ .App | upper }}",
+					"labels": map[string]any{
+						"owner":  `${{ .Context.owner | default "platform" }}`,
+						"region": `${{ replace "-" "_" .Context.region }}`,
+					},
+				},
+				"data": map[string]any{
+					"config.yaml": "db:\n${{ printf \"instanceType: %s\" .Config.mysql.instanceType | indent 2 }}",
+					"token":       `${{ b64enc .Workspace }}`,
+				},
+				"args": []any{"--stack=${{ .Stack | quote }}", 3},
+			},
+			expected: map[string]any{
+				"metadata": map[string]any{
+					"name": "helloworld-APP",
+					"labels": map[string]any{
+						"owner":  "platform",
+						"region": "us_east_1",
+					},
+				},
+				"data": map[string]any{
+					"config.yaml": "db:\n  instanceType: db.t3.micro",
+					"token":       "cHJvZA==",
+				},
+				"args": []any{`--stack="dev"`, 3},
+			},
+		},
+		{
+			name: "default delimiters are left as they are",
+			attributes: map[string]any{
+				"annotations": map[string]any{"summary": "{{ $labels.instance }} is down"},
+			},
+			expected: map[string]any{
+				"annotations": map[string]any{"summary": "{{ $labels.instance }} is down"},
+			},
+		},
+		{
+			name: "escaped delimiter",
+			attributes: map[string]any{
+				"data": map[string]any{"workflow.yaml": "ref: $${{ github.sha }}\nproject: ${{ .Project }}"},
+			},
+			expected: map[string]any{
+				"data": map[string]any{"workflow.yaml": "ref: ${{ github.sha }}\nproject: helloworld"},
+			},
+		},
+		{
+			name: "missing key",
+			attributes: map[string]any{
+				"spec": map[string]any{"containers": []any{map[string]any{"image": "${{ .Context.image }}"}}},
+			},
+			errMsg: "invalid attribute template of resource v1:ConfigMap:default:foo: attribute spec.containers[0].image",
+		},
+		{
+			name: "unknown function",
+			attributes: map[string]any{
+				"metadata": map[string]any{"name": "${{ .Project | camelcase }}"},
+			},
+			errMsg: "invalid attribute template of resource v1:ConfigMap:default:foo: attribute metadata.name",
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			resources := v1.Resources{{ID: "v1:ConfigMap:default:foo", Type: v1.Kubernetes, Attributes: tc.attributes}}
+			resources[0].EnableAttributeTemplate()
+			err := RenderAttributeTemplates(resources, data)
+			if tc.errMsg != "" {
+				assert.ErrorIs(t, err, ErrInvalidAttributeTemplate)
+				assert.ErrorContains(t, err, tc.errMsg)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expected, resources[0].Attributes)
+		})
+	}
+}
+
+func TestRenderAttributeTemplates_OptIn(t *testing.T) {
+	workflow := "ref: ${{ github.sha }}"
+	resources := v1.Resources{
+		{ID: "v1:ConfigMap:default:workflow", Type: v1.Kubernetes, Attributes: map[string]any{"data": workflow}},
+		{ID: "v1:ConfigMap:default:foo", Type: v1.Kubernetes, Attributes: map[string]any{"data": "${{ .Project }}"}},
+	}
+	resources[1].EnableAttributeTemplate()
+
+	// the resources not opted in are left as they are
+	err := RenderAttributeTemplates(resources, AttributeTemplateData{Project: "helloworld"})
+	assert.NoError(t, err)
+	assert.Equal(t, workflow, resources[0].Attributes["data"])
+	assert.Equal(t, "helloworld", resources[1].Attributes["data"])
+}
@@ -0,0 +1,80 @@
+// Package generatortest provides utilities for testing the Spec generators.
+package generatortest
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	v1 "kusionstack.io/kusion/pkg/apis/api.kusion.io/v1"
+	"kusionstack.io/kusion/pkg/generators"
+)
+
+type options struct {
+	newSpec    func() *v1.Spec
+	beforeEach func()
+}
+
+// Option customizes how the generator runs in CompareGeneratorRuns and RunGeneratorTwice.
+type Option func(*options)
+
+// WithSpec sets the function returning a fresh Spec for each run, whose Resources are the input of
+// the generator. By default, each run starts with an empty Spec.
+func WithSpec(newSpec func() *v1.Spec) Option {
+	return func(o *options) {
+		o.newSpec = newSpec
+	}
+}
+
+// WithBeforeEach sets the function called before each run, which is usually used to seed the source
+// of the randomized values, such as the generated passwords, so that they don't differ between runs.
+func WithBeforeEach(beforeEach func()) Option {
+	return func(o *options) {
+		o.beforeEach = beforeEach
+	}
+}
+
+// CompareGeneratorRuns runs two generators created by newGenerator against two fresh Specs, and
+// returns the diff of the resulting Resources in the format of cmp.Diff, which is empty if the
+// generator is idempotent.
+func CompareGeneratorRuns(newGenerator generators.NewSpecGeneratorFunc, opts ...Option) (string, error) {
+	o := &options{
+		newSpec: func() *v1.Spec { return &v1.Spec{} },
+	}
+	for _, opt := range opts {
+		opt(o)
+	}
+
+	var results [2]v1.Resources
+	for i := range results {
+		if o.beforeEach != nil {
+			o.beforeEach()
+		}
+		g, err := newGenerator()
+		if err != nil {
+			return "", fmt.Errorf("new generator failed in run %d: %w", i+1, err)
+		}
+		spec := o.newSpec()
+		if err = g.Generate(spec); err != nil {
+			return "", fmt.Errorf("generate failed in run %d: %w", i+1, err)
+		}
+		results[i] = spec.Resources
+	}
+
+	return cmp.Diff(results[0], results[1]), nil
+}
+
+// RunGeneratorTwice asserts the generator is idempotent, that is, generating twice yields identical
+// Resources. The test fails with the diff of the Resources on mismatch.
+func RunGeneratorTwice(t testing.TB, newGenerator generators.NewSpecGeneratorFunc, opts ...Option) {
+	t.Helper()
+
+	diff, err := CompareGeneratorRuns(newGenerator, opts...)
+	if err != nil {
+		t.Fatalf("run generator failed: %v", err)
+	}
+	if diff != "" {
+		t.Errorf("generator is not idempotent, resources mismatch (-first +second):\n%s", diff)
+	}
+}
@@ -0,0 +1,86 @@
+package generatortest
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	v1 "kusionstack.io/kusion/pkg/apis/api.kusion.io/v1"
+	"kusionstack.io/kusion/pkg/generators"
+)
+
+// counterGenerator appends a resource whose attribute is the value of the counter, which is
+// nondeterministic unless the counter is reset before each run.
+type counterGenerator struct {
+	counter *int
+}
+
+func (g *counterGenerator) Generate(spec *v1.Spec) error {
+	*g.counter++
+	spec.Resources = append(spec.Resources, v1.Resource{
+		ID:         "v1:ConfigMap:foo:bar",
+		Type:       v1.Kubernetes,
+		Attributes: map[string]interface{}{"value": fmt.Sprint(*g.counter)},
+	})
+	return nil
+}
+
+func newCounterGeneratorFunc(counter *int) generators.NewSpecGeneratorFunc {
+	return func() (generators.SpecGenerator, error) {
+		return &counterGenerator{counter: counter}, nil
+	}
+}
+
+func TestCompareGeneratorRuns(t *testing.T) {
+	counter := 0
+	testcases := []struct {
+		name         string
+		newGenerator generators.NewSpecGeneratorFunc
+		opts         []Option
+		idempotent   bool
+		success      bool
+	}{
+		{
+			name:         "nondeterministic generator",
+			newGenerator: newCounterGeneratorFunc(&counter),
+			idempotent:   false,
+			success:      true,
+		},
+		{
+			name:         "seeded generator",
+			newGenerator: newCounterGeneratorFunc(&counter),
+			opts: []Option{
+				WithBeforeEach(func() { counter = 0 }),
+				WithSpec(func() *v1.Spec {
+					return &v1.Spec{Resources: v1.Resources{{ID: "v1:Namespace:foo", Type: v1.Kubernetes}}}
+				}),
+			},
+			idempotent: true,
+			success:    true,
+		},
+		{
+			name: "failed to new generator",
+			newGenerator: func() (generators.SpecGenerator, error) {
+				return nil, errors.New("invalid config")
+			},
+			success: false,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			diff, err := CompareGeneratorRuns(tc.newGenerator, tc.opts...)
+			assert.Equal(t, tc.success, err == nil)
+			if tc.success {
+				assert.Equal(t, tc.idempotent, diff == "")
+			}
+		})
+	}
+}
+
+func TestRunGeneratorTwice(t *testing.T) {
+	counter := 0
+	RunGeneratorTwice(t, newCounterGeneratorFunc(&counter), WithBeforeEach(func() { counter = 0 }))
+}
@@ -0,0 +1,124 @@
+package generators
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/google/go-containerregistry/pkg/name"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	v1 "kusionstack.io/kusion/pkg/apis/api.kusion.io/v1"
+)
+
+// podSpecPaths are the paths of the pod specs in the Kubernetes workloads, i.e. Pod, CronJob and the
+// workloads with a pod template, such as Deployment, StatefulSet, DaemonSet and Job.
+var podSpecPaths = [][]string{
+	{"spec"},
+	{"spec", "template", "spec"},
+	{"spec", "jobTemplate", "spec", "template", "spec"},
+}
+
+// containerPaths are the paths of the init containers and containers in each pod spec path, built once
+// to avoid building them per resource.
+var containerPaths = func() [][]string {
+	var paths [][]string
+	for _, path := range podSpecPaths {
+		for _, field := range []string{"initContainers", "containers"} {
+			paths = append(paths, append(append([]string{}, path...), field))
+		}
+	}
+	return paths
+}()
+
+// ResolveImage returns the final image by applying the image extension to the given image. An
+// explicit override of the image repository takes precedence over the registry mirror. The images
+// pinned by digest and the ones already on the registry mirror are kept as they are, so that resolving
+// an image more than once doesn't change it.
+func ResolveImage(ext *v1.KubeImageExtension, image string) (string, error) {
+	if ext == nil || image == "" || (ext.Registry == "" && len(ext.Overrides) == 0) {
+		return image, nil
+	}
+
+	ref, err := name.ParseReference(image)
+	if err != nil {
+		return "", fmt.Errorf("invalid image %s: %w", image, err)
+	}
+	if _, ok := ref.(name.Digest); ok {
+		return image, nil
+	}
+	registry := strings.TrimSuffix(ext.Registry, "/")
+	if registry != "" && strings.HasPrefix(image, registry+"/") {
+		return image, nil
+	}
+	repo, suffix := splitImage(image)
+
+	for _, key := range []string{repo, ref.Context().Name()} {
+		if override, ok := ext.Overrides[key]; ok && override != "" {
+			if _, err = name.ParseReference(override); err != nil {
+				return "", fmt.Errorf("invalid override image %s of %s: %w", override, key, err)
+			}
+			return override, nil
+		}
+	}
+
+	if registry == "" {
+		return image, nil
+	}
+	resolved := registry + "/" + ref.Context().RepositoryStr() + suffix
+	if _, err = name.ParseReference(resolved); err != nil {
+		return "", fmt.Errorf("invalid image %s with registry %s: %w", resolved, ext.Registry, err)
+	}
+	return resolved, nil
+}
+
+// ResolveImages resolves the images of the containers and init containers in the generated Kubernetes
+// resources with the image extension in place.
+func ResolveImages(resources v1.Resources, ext *v1.KubeImageExtension) error {
+	if ext == nil {
+		return nil
+	}
+
+	for i := range resources {
+		if resources[i].Type != v1.Kubernetes {
+			continue
+		}
+		for _, path := range containerPaths {
+			// the containers are updated in place, for the attributes may not be deep copyable JSON values
+			value, found, err := unstructured.NestedFieldNoCopy(resources[i].Attributes, path...)
+			if err != nil || !found {
+				continue
+			}
+			containers, ok := value.([]interface{})
+			if !ok {
+				continue
+			}
+			for _, c := range containers {
+				container, ok := c.(map[string]interface{})
+				if !ok {
+					continue
+				}
+				image, ok := container["image"].(string)
+				if !ok {
+					continue
+				}
+				if container["image"], err = ResolveImage(ext, image); err != nil {
+					return fmt.Errorf("failed to resolve image of resource %s: %w", resources[i].ID, err)
+				}
+			}
+		}
+	}
+
+	return nil
+}
+
+// splitImage splits the image into the repository as written and the suffix of the tag or digest,
+// e.g. "localhost:5000/mysql:8.0" into "localhost:5000/mysql" and ":8.0".
+func splitImage(image string) (string, string) {
+	if i := strings.Index(image, "@"); i >= 0 {
+		return image[:i], image[i:]
+	}
+	if i := strings.LastIndex(image, ":"); i > strings.LastIndex(image, "/") {
+		return image[:i], image[i:]
+	}
+	return image, ""
+}
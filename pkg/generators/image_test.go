@@ -0,0 +1,188 @@
+package generators
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	v1 "kusionstack.io/kusion/pkg/apis/api.kusion.io/v1"
+)
+
+const testDigest = "sha256:2e863c44b718727c860746568e1d54afd13b2fa71b160f5cd9058fc436217b30"
+
+func TestResolveImage(t *testing.T) {
+	testcases := []struct {
+		name     string
+		ext      *v1.KubeImageExtension
+		image    string
+		expected string
+		success  bool
+	}{
+		{
+			name:     "nil image extension",
+			ext:      nil,
+			image:    "mysql:8.0",
+			expected: "mysql:8.0",
+			success:  true,
+		},
+		{
+			name:     "registry mirror of docker hub official image",
+			ext:      &v1.KubeImageExtension{Registry: "mirror.example.com/dockerhub/"},
+			image:    "mysql:8.0",
+			expected: "mirror.example.com/dockerhub/library/mysql:8.0",
+			success:  true,
+		},
+		{
+			name:     "registry mirror of image with registry and tag",
+			ext:      &v1.KubeImageExtension{Registry: "mirror.example.com"},
+			image:    "docker.io/bitnami/postgresql:14",
+			expected: "mirror.example.com/bitnami/postgresql:14",
+			success:  true,
+		},
+		{
+			name:     "image pinned by digest not resolved",
+			ext:      &v1.KubeImageExtension{Registry: "mirror.example.com"},
+			image:    "docker.io/bitnami/postgresql@" + testDigest,
+			expected: "docker.io/bitnami/postgresql@" + testDigest,
+			success:  true,
+		},
+		{
+			name: "image on registry mirror not resolved",
+			ext: &v1.KubeImageExtension{
+				Registry:  "mirror.example.com/dockerhub/",
+				Overrides: map[string]string{"mysql": "mirror.example.com/db/mysql:8.0"},
+			},
+			image:    "mirror.example.com/dockerhub/library/mysql:8.0",
+			expected: "mirror.example.com/dockerhub/library/mysql:8.0",
+			success:  true,
+		},
+		{
+			name:     "registry mirror of image without tag",
+			ext:      &v1.KubeImageExtension{Registry: "localhost:5000"},
+			image:    "ghcr.io/kusionstack/kusion",
+			expected: "localhost:5000/kusionstack/kusion",
+			success:  true,
+		},
+		{
+			name: "override by repository as written takes precedence",
+			ext: &v1.KubeImageExtension{
+				Registry:  "mirror.example.com",
+				Overrides: map[string]string{"mysql": "mirror.example.com/db/mysql@" + testDigest},
+			},
+			image:    "mysql:8.0",
+			expected: "mirror.example.com/db/mysql@" + testDigest,
+			success:  true,
+		},
+		{
+			name: "override by fully qualified repository",
+			ext: &v1.KubeImageExtension{
+				Overrides: map[string]string{"index.docker.io/library/postgres": "mirror.example.com/postgres:14.10"},
+			},
+			image:    "docker.io/postgres:14",
+			expected: "mirror.example.com/postgres:14.10",
+			success:  true,
+		},
+		{
+			name: "image not overridden",
+			ext: &v1.KubeImageExtension{
+				Overrides: map[string]string{"mysql": "mirror.example.com/mysql:8.0"},
+			},
+			image:    "postgres:14",
+			expected: "postgres:14",
+			success:  true,
+		},
+		{
+			name: "invalid override",
+			ext: &v1.KubeImageExtension{
+				Overrides: map[string]string{"mysql": "Invalid Image"},
+			},
+			image:   "mysql:8.0",
+			success: false,
+		},
+		{
+			name:    "invalid image",
+			ext:     &v1.KubeImageExtension{Registry: "mirror.example.com"},
+			image:   "mysql:8.0:latest",
+			success: false,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			image, err := ResolveImage(tc.ext, tc.image)
+			assert.Equal(t, tc.success, err == nil)
+			if tc.success {
+				assert.Equal(t, tc.expected, image)
+			}
+		})
+	}
+}
+
+func TestResolveImages(t *testing.T) {
+	resources := v1.Resources{
+		{
+			ID:   "apps/v1:Deployment:default:db",
+			Type: v1.Kubernetes,
+			Attributes: map[string]interface{}{
+				"kind": "Deployment",
+				"spec": map[string]interface{}{
+					"replicas": 1,
+					"template": map[string]interface{}{
+						"spec": map[string]interface{}{
+							"initContainers": []interface{}{
+								map[string]interface{}{"name": "init", "image": "busybox"},
+							},
+							"containers": []interface{}{
+								map[string]interface{}{"name": "mysql", "image": "mysql:8.0"},
+							},
+						},
+					},
+				},
+			},
+		},
+		{
+			ID:   "batch/v1:CronJob:default:backup",
+			Type: v1.Kubernetes,
+			Attributes: map[string]interface{}{
+				"kind": "CronJob",
+				"spec": map[string]interface{}{
+					"jobTemplate": map[string]interface{}{
+						"spec": map[string]interface{}{
+							"template": map[string]interface{}{
+								"spec": map[string]interface{}{
+									"containers": []interface{}{
+										map[string]interface{}{"name": "backup", "image": "bitnami/mysql:8.0"},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+		{
+			ID:   "aliyun:alicloud:alicloud_db_instance:db",
+			Type: v1.Terraform,
+			Attributes: map[string]interface{}{
+				"image": "mysql:8.0",
+			},
+		},
+	}
+	ext := &v1.KubeImageExtension{
+		Registry:  "mirror.example.com",
+		Overrides: map[string]string{"mysql": "mirror.example.com/db/mysql@" + testDigest},
+	}
+
+	assert.NoError(t, ResolveImages(resources, ext))
+	podSpec := resources[0].Attributes["spec"].(map[string]interface{})["template"].(map[string]interface{})["spec"].(map[string]interface{})
+	assert.Equal(t, "mirror.example.com/library/busybox", podSpec["initContainers"].([]interface{})[0].(map[string]interface{})["image"])
+	assert.Equal(t, "mirror.example.com/db/mysql@"+testDigest, podSpec["containers"].([]interface{})[0].(map[string]interface{})["image"])
+	cronPodSpec := resources[1].Attributes["spec"].(map[string]interface{})["jobTemplate"].(map[string]interface{})["spec"].(map[string]interface{})["template"].(map[string]interface{})["spec"].(map[string]interface{})
+	assert.Equal(t, "mirror.example.com/bitnami/mysql:8.0", cronPodSpec["containers"].([]interface{})[0].(map[string]interface{})["image"])
+	assert.Equal(t, "mysql:8.0", resources[2].Attributes["image"])
+
+	resources[0].Attributes["spec"].(map[string]interface{})["template"].(map[string]interface{})["spec"].(map[string]interface{})["containers"] = []interface{}{
+		map[string]interface{}{"name": "invalid", "image": "Invalid Image"},
+	}
+	assert.Error(t, ResolveImages(resources, ext))
+}
@@ -0,0 +1,139 @@
+package inferreddependencies
+
+import (
+	"sort"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	v1 "kusionstack.io/kusion/pkg/apis/api.kusion.io/v1"
+	"kusionstack.io/kusion/pkg/generators"
+)
+
+// refKinds maps the attribute keys which reference other Kubernetes resources to the kind of
+// the referenced resource and the field holding its name.
+var refKinds = map[string]struct {
+	kind      string
+	nameField string
+}{
+	"secretKeyRef":          {kind: "Secret", nameField: "name"},
+	"secretRef":             {kind: "Secret", nameField: "name"},
+	"secret":                {kind: "Secret", nameField: "secretName"},
+	"configMapKeyRef":       {kind: "ConfigMap", nameField: "name"},
+	"configMapRef":          {kind: "ConfigMap", nameField: "name"},
+	"configMap":             {kind: "ConfigMap", nameField: "name"},
+	"persistentVolumeClaim": {kind: "PersistentVolumeClaim", nameField: "claimName"},
+}
+
+const serviceAccountNameField = "serviceAccountName"
+
+// reference is a reference to a Kubernetes resource in the same namespace.
+type reference struct {
+	kind string
+	name string
+}
+
+// inferredDependenciesGenerator is a generator that infers the dependsOn of Kubernetes resources
+// from the references to other resources in their attributes, such as secretKeyRef, configMapRef,
+// volume claim names and serviceAccountName.
+type inferredDependenciesGenerator struct{}
+
+// NewInferredDependenciesGenerator returns a new instance of inferredDependenciesGenerator.
+func NewInferredDependenciesGenerator() (generators.SpecGenerator, error) {
+	return &inferredDependenciesGenerator{}, nil
+}
+
+// NewInferredDependenciesGeneratorFunc returns a function that creates a new inferredDependenciesGenerator.
+func NewInferredDependenciesGeneratorFunc() generators.NewSpecGeneratorFunc {
+	return func() (generators.SpecGenerator, error) {
+		return NewInferredDependenciesGenerator()
+	}
+}
+
+// Generate injects the inferred dependsOn of the Kubernetes resources. Only the references to the
+// resources in the Spec are turned into dependsOn.
+func (g *inferredDependenciesGenerator) Generate(spec *v1.Spec) error {
+	if spec.Resources == nil {
+		spec.Resources = make(v1.Resources, 0)
+	}
+
+	// index the Kubernetes resources by kind, namespace and name
+	index := make(map[string]string)
+	for _, res := range spec.Resources {
+		if res.Type != v1.Kubernetes {
+			continue
+		}
+		obj := &unstructured.Unstructured{Object: res.Attributes}
+		index[indexKey(obj.GetKind(), obj.GetNamespace(), obj.GetName())] = res.ID
+	}
+
+	for i := range spec.Resources {
+		res := &spec.Resources[i]
+		if res.Type != v1.Kubernetes {
+			continue
+		}
+		namespace := (&unstructured.Unstructured{Object: res.Attributes}).GetNamespace()
+
+		refs := make(map[reference]struct{})
+		collectRefs(res.Attributes, refs)
+		var ids []string
+		for ref := range refs {
+			id, ok := index[indexKey(ref.kind, namespace, ref.name)]
+			if !ok || id == res.ID {
+				continue
+			}
+			ids = append(ids, id)
+		}
+
+		// sort the inferred dependencies to keep the generated Spec stable
+		sort.Strings(ids)
+		for _, id := range ids {
+			res.DependsOn = appendIfMissing(res.DependsOn, id)
+		}
+	}
+
+	return nil
+}
+
+// collectRefs walks through the attributes recursively, and collects the names of the referenced
+// resources with their kinds.
+func collectRefs(value any, refs map[reference]struct{}) {
+	switch v := value.(type) {
+	case map[string]any:
+		for key, child := range v {
+			if ref, ok := refKinds[key]; ok {
+				if m, ok := child.(map[string]any); ok {
+					if name, ok := m[ref.nameField].(string); ok && name != "" {
+						refs[reference{kind: ref.kind, name: name}] = struct{}{}
+					}
+				}
+			}
+			if key == serviceAccountNameField {
+				if name, ok := child.(string); ok && name != "" {
+					refs[reference{kind: "ServiceAccount", name: name}] = struct{}{}
+				}
+			}
+			collectRefs(child, refs)
+		}
+	case []any:
+		for _, child := range v {
+			collectRefs(child, refs)
+		}
+	case []map[string]any:
+		for _, child := range v {
+			collectRefs(child, refs)
+		}
+	}
+}
+
+func indexKey(kind, namespace, name string) string {
+	return kind + ":" + namespace + ":" + name
+}
+
+func appendIfMissing(dependsOn []string, id string) []string {
+	for _, d := range dependsOn {
+		if d == id {
+			return dependsOn
+		}
+	}
+	return append(dependsOn, id)
+}
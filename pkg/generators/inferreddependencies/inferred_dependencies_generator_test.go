@@ -0,0 +1,147 @@
+package inferreddependencies
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	v1 "kusionstack.io/kusion/pkg/apis/api.kusion.io/v1"
+	"kusionstack.io/kusion/pkg/generators/generatortest"
+)
+
+var (
+	fakeDeployment = map[string]interface{}{
+		"apiVersion": "apps/v1",
+		"kind":       "Deployment",
+		"metadata": map[string]interface{}{
+			"namespace": "foo",
+			"name":      "bar",
+		},
+		"spec": map[string]interface{}{
+			"template": map[string]interface{}{
+				"spec": map[string]interface{}{
+					"serviceAccountName": "bar-sa",
+					"containers": []interface{}{
+						map[string]interface{}{
+							"image": "foo.bar.com:v1",
+							"name":  "bar",
+							"env": []interface{}{
+								map[string]interface{}{
+									"name": "PASSWORD",
+									"valueFrom": map[string]interface{}{
+										"secretKeyRef": map[string]interface{}{
+											"name": "bar-secret",
+											"key":  "password",
+										},
+									},
+								},
+							},
+							"envFrom": []interface{}{
+								map[string]interface{}{
+									"configMapRef": map[string]interface{}{
+										"name": "external-config",
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+	fakeSecret = map[string]interface{}{
+		"apiVersion": "v1",
+		"kind":       "Secret",
+		"metadata": map[string]interface{}{
+			"namespace": "foo",
+			"name":      "bar-secret",
+		},
+	}
+	fakeServiceAccount = map[string]interface{}{
+		"apiVersion": "v1",
+		"kind":       "ServiceAccount",
+		"metadata": map[string]interface{}{
+			"namespace": "foo",
+			"name":      "bar-sa",
+		},
+	}
+)
+
+func TestInferredDependenciesGenerator_Generate(t *testing.T) {
+	spec := &v1.Spec{
+		Resources: v1.Resources{
+			{
+				ID:         "apps/v1:Deployment:foo:bar",
+				Type:       v1.Kubernetes,
+				Attributes: fakeDeployment,
+			},
+			{
+				ID:         "v1:Secret:foo:bar-secret",
+				Type:       v1.Kubernetes,
+				Attributes: fakeSecret,
+			},
+			{
+				ID:         "v1:ServiceAccount:foo:bar-sa",
+				Type:       v1.Kubernetes,
+				Attributes: fakeServiceAccount,
+				DependsOn:  []string{"v1:Namespace:foo"},
+			},
+		},
+	}
+
+	g, err := NewInferredDependenciesGenerator()
+	assert.NoError(t, err)
+	assert.NoError(t, g.Generate(spec))
+
+	// the referenced ConfigMap is not in the Spec, so no dependsOn is added for it
+	assert.Equal(t, []string{"v1:Secret:foo:bar-secret", "v1:ServiceAccount:foo:bar-sa"}, spec.Resources[0].DependsOn)
+	assert.Nil(t, spec.Resources[1].DependsOn)
+	assert.Equal(t, []string{"v1:Namespace:foo"}, spec.Resources[2].DependsOn)
+}
+
+func TestInferredDependenciesGenerator_GenerateKeepsExistingDependsOn(t *testing.T) {
+	spec := &v1.Spec{
+		Resources: v1.Resources{
+			{
+				ID:         "apps/v1:Deployment:foo:bar",
+				Type:       v1.Kubernetes,
+				Attributes: fakeDeployment,
+				DependsOn:  []string{"v1:Secret:foo:bar-secret"},
+			},
+			{
+				ID:         "v1:Secret:foo:bar-secret",
+				Type:       v1.Kubernetes,
+				Attributes: fakeSecret,
+			},
+		},
+	}
+
+	g, err := NewInferredDependenciesGeneratorFunc()()
+	assert.NoError(t, err)
+	assert.NoError(t, g.Generate(spec))
+	assert.Equal(t, []string{"v1:Secret:foo:bar-secret"}, spec.Resources[0].DependsOn)
+}
+
+func TestInferredDependenciesGenerator_Idempotent(t *testing.T) {
+	generatortest.RunGeneratorTwice(t, NewInferredDependenciesGeneratorFunc(), generatortest.WithSpec(func() *v1.Spec {
+		return &v1.Spec{
+			Resources: v1.Resources{
+				{
+					ID:         "apps/v1:Deployment:foo:bar",
+					Type:       v1.Kubernetes,
+					Attributes: fakeDeployment,
+				},
+				{
+					ID:         "v1:Secret:foo:bar-secret",
+					Type:       v1.Kubernetes,
+					Attributes: fakeSecret,
+				},
+				{
+					ID:         "v1:ServiceAccount:foo:bar-sa",
+					Type:       v1.Kubernetes,
+					Attributes: fakeServiceAccount,
+				},
+			},
+		}
+	}))
+}
@@ -0,0 +1,45 @@
+package generators
+
+import (
+	"fmt"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+// MergeStringMapField merges the entries into the string map at the fields of the object in place, such
+// as the labels and annotations of the metadata and the Pod template, where an entry whose value is the
+// removal deletes the key. The map is created if not found. Unlike unstructured.NestedStringMap and
+// unstructured.SetNestedStringMap, the map is neither copied out nor back, which saves the allocations
+// per resource. An error is returned without modifying the object if the field is not a map of strings.
+func MergeStringMapField(obj map[string]interface{}, entries map[string]string, removal string, fields ...string) error {
+	value, found, err := unstructured.NestedFieldNoCopy(obj, fields...)
+	if err != nil {
+		return err
+	}
+	if !found {
+		m := make(map[string]interface{}, len(entries))
+		mergeStringEntries(m, entries, removal)
+		return unstructured.SetNestedField(obj, m, fields...)
+	}
+
+	m, ok := value.(map[string]interface{})
+	if !ok {
+		return fmt.Errorf("%v accessor error: %v is of the type %T, expected map[string]interface{}", "."+strings.Join(fields, "."), value, value)
+	}
+	if !allStrings(m) {
+		return fmt.Errorf("%v accessor error: contains non-string value in the map", "."+strings.Join(fields, "."))
+	}
+	mergeStringEntries(m, entries, removal)
+	return nil
+}
+
+func mergeStringEntries(m map[string]interface{}, entries map[string]string, removal string) {
+	for k, v := range entries {
+		if v == removal {
+			delete(m, k)
+			continue
+		}
+		m[k] = v
+	}
+}
@@ -0,0 +1,133 @@
+package generators
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+const mockRemoval = "ops://kusionstack.io/remove"
+
+var podLabelsFields = []string{"spec", "template", "metadata", "labels"}
+
+// copyMergeStringMapField is the merge by copying the map out and back, which MergeStringMapField replaces.
+func copyMergeStringMapField(obj map[string]interface{}, entries map[string]string, removal string, fields ...string) error {
+	m, found, err := unstructured.NestedStringMap(obj, fields...)
+	if err != nil {
+		return err
+	}
+	if !found || m == nil {
+		m = make(map[string]string)
+	}
+	for k, v := range entries {
+		if v == removal {
+			delete(m, k)
+			continue
+		}
+		m[k] = v
+	}
+	return unstructured.SetNestedStringMap(obj, m, fields...)
+}
+
+func TestMergeStringMapField(t *testing.T) {
+	entries := map[string]string{
+		"app.kubernetes.io/name":    "nginx",
+		"app.kubernetes.io/part-of": mockRemoval,
+		"team":                      "kusion",
+	}
+
+	testcases := []struct {
+		name    string
+		success bool
+		obj     map[string]interface{}
+		fields  []string
+	}{
+		{
+			name:    "merge into existing labels",
+			success: true,
+			obj:     mockStackResources(1)[0].Attributes,
+			fields:  podLabelsFields,
+		},
+		{
+			name:    "merge into missing labels",
+			success: true,
+			obj:     mockStackResources(1)[0].Attributes,
+			fields:  []string{"metadata", "labels"},
+		},
+		{
+			name:    "merge into missing metadata",
+			success: true,
+			obj:     map[string]interface{}{"kind": "ConfigMap"},
+			fields:  []string{"metadata", "annotations"},
+		},
+		{
+			name:    "failed to merge into non-string labels",
+			success: false,
+			obj: map[string]interface{}{
+				"metadata": map[string]interface{}{"labels": map[string]interface{}{"replicas": int64(1)}},
+			},
+			fields: []string{"metadata", "labels"},
+		},
+		{
+			name:    "failed to merge into null labels",
+			success: false,
+			obj:     map[string]interface{}{"metadata": map[string]interface{}{"labels": nil}},
+			fields:  []string{"metadata", "labels"},
+		},
+		{
+			name:    "failed to merge into non-map metadata",
+			success: false,
+			obj:     map[string]interface{}{"metadata": "invalid"},
+			fields:  []string{"metadata", "labels"},
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			expected := runtime.DeepCopyJSON(tc.obj)
+			expectedErr := copyMergeStringMapField(expected, entries, mockRemoval, tc.fields...)
+			assert.Equal(t, tc.success, expectedErr == nil)
+
+			actual := runtime.DeepCopyJSON(tc.obj)
+			err := MergeStringMapField(actual, entries, mockRemoval, tc.fields...)
+			assert.Equal(t, tc.success, err == nil)
+			if tc.success {
+				assert.Equal(t, expected, actual)
+			} else {
+				assert.Equal(t, tc.obj, actual)
+			}
+		})
+	}
+}
+
+func BenchmarkMergeStringMapField(b *testing.B) {
+	entries := map[string]string{"team": "kusion", "app.kubernetes.io/part-of": mockRemoval}
+	benchmarks := []struct {
+		name  string
+		merge func(map[string]interface{}, map[string]string, string, ...string) error
+	}{
+		{name: "copy", merge: copyMergeStringMapField},
+		{name: "in-place", merge: MergeStringMapField},
+	}
+
+	for _, bm := range benchmarks {
+		b.Run(bm.name, func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				b.StopTimer()
+				resources := mockStackResources(100)
+				b.StartTimer()
+				for _, res := range resources {
+					if res.Attributes["kind"] != "Deployment" {
+						continue
+					}
+					if err := bm.merge(res.Attributes, entries, mockRemoval, podLabelsFields...); err != nil {
+						b.Fatal(err)
+					}
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,108 @@
+package generators
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"sync"
+
+	v1 "kusionstack.io/kusion/pkg/apis/api.kusion.io/v1"
+)
+
+// ModuleOutputCache caches the outputs of the modules for the incremental generation, where the output of
+// the previous generation is reused if the hash of the module inputs is unchanged, and the module is called
+// to regenerate on a cache miss. A nil ModuleOutputCache caches nothing.
+type ModuleOutputCache struct {
+	mu       sync.Mutex
+	previous map[string]*v1.ModuleOutput
+	current  map[string]*v1.ModuleOutput
+}
+
+// NewModuleOutputCache returns a ModuleOutputCache with the module outputs of the previous generation,
+// e.g. persisted in the last Release.
+func NewModuleOutputCache(previous map[string]*v1.ModuleOutput) *ModuleOutputCache {
+	return &ModuleOutputCache{
+		previous: previous,
+		current:  make(map[string]*v1.ModuleOutput),
+	}
+}
+
+// Get returns the output of the module with the key generated previously, if the hash of the inputs is
+// unchanged.
+func (c *ModuleOutputCache) Get(key, hash string) (*v1.ModuleOutput, bool) {
+	if c == nil {
+		return nil, false
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	output, ok := c.previous[key]
+	if !ok || output == nil || output.Hash != hash {
+		return nil, false
+	}
+	c.current[key] = output
+	return output, true
+}
+
+// Put records the output of the module with the key generated in this generation.
+func (c *ModuleOutputCache) Put(key string, output *v1.ModuleOutput) {
+	if c == nil {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.current[key] = output
+}
+
+// Outputs returns the module outputs used in this generation, either reused or regenerated, which are
+// persisted for the next generation. The outputs of the modules no longer used are dropped.
+func (c *ModuleOutputCache) Outputs() map[string]*v1.ModuleOutput {
+	if c == nil {
+		return nil
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	outputs := make(map[string]*v1.ModuleOutput, len(c.current))
+	for k, v := range c.current {
+		outputs[k] = v
+	}
+	return outputs
+}
+
+// HashModuleInputs returns the hash of the inputs of a module, where each input is prefixed by its length
+// so that the inputs are never ambiguous when concatenated.
+func HashModuleInputs(inputs ...[]byte) string {
+	h := sha256.New()
+	var size [8]byte
+	for _, input := range inputs {
+		binary.BigEndian.PutUint64(size[:], uint64(len(input)))
+		h.Write(size[:])
+		h.Write(input)
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// WithModuleOutputCache wraps the NewSpecGeneratorFunc so that the cache is set to the returned
+// SpecGenerator if it supports the incremental generation. The NewSpecGeneratorFunc is returned as is
+// if the cache is nil.
+func WithModuleOutputCache(cache *ModuleOutputCache, newGenerator NewSpecGeneratorFunc) NewSpecGeneratorFunc {
+	if cache == nil {
+		return newGenerator
+	}
+	return func() (SpecGenerator, error) {
+		g, err := newGenerator()
+		if err != nil {
+			return nil, err
+		}
+		if cg, ok := g.(IncrementalSpecGenerator); ok {
+			cg.SetModuleOutputCache(cache)
+		}
+		return g, nil
+	}
+}
+
+// IncrementalSpecGenerator is a SpecGenerator which reuses the unchanged module outputs in the
+// ModuleOutputCache.
+type IncrementalSpecGenerator interface {
+	SpecGenerator
+	SetModuleOutputCache(cache *ModuleOutputCache)
+}
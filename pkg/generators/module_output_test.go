@@ -0,0 +1,79 @@
+package generators
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	v1 "kusionstack.io/kusion/pkg/apis/api.kusion.io/v1"
+)
+
+type mockIncrementalGenerator struct {
+	mockGenerator
+	cache *ModuleOutputCache
+}
+
+func (m *mockIncrementalGenerator) SetModuleOutputCache(cache *ModuleOutputCache) {
+	m.cache = cache
+}
+
+func TestModuleOutputCache(t *testing.T) {
+	fooHash := HashModuleInputs([]byte("foo@v1"), []byte("replicas: 1"))
+	barHash := HashModuleInputs([]byte("bar@v1"), []byte("port: 80"))
+	previous := map[string]*v1.ModuleOutput{
+		"app/foo@v1": {Hash: fooHash, Resources: []string{"id: foo"}},
+		"app/bar@v1": {Hash: barHash, Resources: []string{"id: bar"}},
+		"app/baz@v1": {Hash: "removed", Resources: []string{"id: baz"}},
+	}
+	cache := NewModuleOutputCache(previous)
+
+	// reused if the inputs are unchanged
+	output, ok := cache.Get("app/foo@v1", fooHash)
+	assert.True(t, ok)
+	assert.Equal(t, []string{"id: foo"}, output.Resources)
+
+	// missed if the inputs are changed
+	changedHash := HashModuleInputs([]byte("bar@v1"), []byte("port: 8080"))
+	assert.NotEqual(t, barHash, changedHash)
+	_, ok = cache.Get("app/bar@v1", changedHash)
+	assert.False(t, ok)
+	cache.Put("app/bar@v1", &v1.ModuleOutput{Hash: changedHash, Resources: []string{"id: bar2"}})
+
+	// missed if never generated
+	_, ok = cache.Get("app/qux@v1", "hash")
+	assert.False(t, ok)
+
+	// the module outputs not used in this generation are dropped
+	assert.Equal(t, map[string]*v1.ModuleOutput{
+		"app/foo@v1": {Hash: fooHash, Resources: []string{"id: foo"}},
+		"app/bar@v1": {Hash: changedHash, Resources: []string{"id: bar2"}},
+	}, cache.Outputs())
+}
+
+func TestHashModuleInputs(t *testing.T) {
+	assert.Equal(t, HashModuleInputs([]byte("a"), []byte("b")), HashModuleInputs([]byte("a"), []byte("b")))
+	assert.NotEqual(t, HashModuleInputs([]byte("ab"), []byte("")), HashModuleInputs([]byte("a"), []byte("b")))
+	assert.NotEqual(t, HashModuleInputs([]byte("a"), nil), HashModuleInputs([]byte("a")))
+}
+
+func TestWithModuleOutputCache(t *testing.T) {
+	g := &mockIncrementalGenerator{}
+	gf := func() (SpecGenerator, error) { return g, nil }
+
+	actual, err := WithModuleOutputCache(nil, gf)()
+	assert.NoError(t, err)
+	assert.Same(t, g, actual)
+	assert.Nil(t, g.cache)
+
+	cache := NewModuleOutputCache(nil)
+	actual, err = WithModuleOutputCache(cache, gf)()
+	assert.NoError(t, err)
+	assert.Same(t, g, actual)
+	assert.Same(t, cache, g.cache)
+
+	var nilCache *ModuleOutputCache
+	_, ok := nilCache.Get("foo", "hash")
+	assert.False(t, ok)
+	nilCache.Put("foo", &v1.ModuleOutput{})
+	assert.Nil(t, nilCache.Outputs())
+}
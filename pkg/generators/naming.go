@@ -0,0 +1,292 @@
+package generators
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"text/template"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	v1 "kusionstack.io/kusion/pkg/apis/api.kusion.io/v1"
+)
+
+// MaxNameLength is the maximum length of the name of a generated Kubernetes resource,
+// which is limited by the DNS-1123 label standard.
+const MaxNameLength = 63
+
+const (
+	// RoleNamespace is the resource role of the generated namespace.
+	RoleNamespace = "namespace"
+
+	secretRolePrefix = "secret:"
+)
+
+// SecretRole returns the resource role of the secret with the given name, which is
+// used as the key of KubeNamingExtension.Overrides.
+func SecretRole(name string) string {
+	return secretRolePrefix + name
+}
+
+// NamePolicy is an interface for constructing the names of the resources generated by
+// the built-in generators, which allows different naming conventions such as team prefixes
+// or hash suffixes. It constructs the names of the namespace and the secrets, and the references
+// to the secrets in the workloads generated by the modules follow, see RenameSecretReferences.
+// It is set to the generators by WithNamePolicy, or configured in the workspace context by
+// v1.NamePolicyConfig.
+type NamePolicy interface {
+	// Name returns the name of the resource with the specified role in the given project,
+	// stack and app.
+	Name(role, project, stack, app string) string
+}
+
+// DefaultNamePolicy is the default NamePolicy, which names the namespace after the project
+// normalized by NormalizeNamespaceName, and the secrets after their declared names in the workload.
+var DefaultNamePolicy NamePolicy = defaultNamePolicy{}
+
+type defaultNamePolicy struct{}
+
+func (defaultNamePolicy) Name(role, project, _, _ string) string {
+	if role == RoleNamespace {
+		return NormalizeNamespaceName(project)
+	}
+	if name, ok := strings.CutPrefix(role, secretRolePrefix); ok {
+		return name
+	}
+	return role
+}
+
+// NameTemplateData is the data available to the template of TemplateNamePolicy.
+type NameTemplateData struct {
+	// Role is the role of the resource, such as RoleNamespace and SecretRole(name).
+	Role string
+	// Name is the name of the resource constructed by DefaultNamePolicy.
+	Name    string
+	Project string
+	Stack   string
+	App     string
+}
+
+// TemplateNamePolicy is the NamePolicy constructing the names by rendering a Go template against
+// NameTemplateData, which is configured by the workspace context, see v1.NamePolicyConfig.
+type TemplateNamePolicy struct {
+	tmpl *template.Template
+}
+
+// NewTemplateNamePolicy returns the TemplateNamePolicy of the template. An error is returned if the
+// template fails to parse, or to render with the sample data, e.g. it refers to an unknown field.
+func NewTemplateNamePolicy(text string) (*TemplateNamePolicy, error) {
+	tmpl, err := template.New("name").Option("missingkey=error").Parse(text)
+	if err != nil {
+		return nil, fmt.Errorf("invalid template of the name policy: %w", err)
+	}
+	policy := &TemplateNamePolicy{tmpl: tmpl}
+	if _, err = policy.render(RoleNamespace, "project", "stack", "app"); err != nil {
+		return nil, fmt.Errorf("invalid template of the name policy: %w", err)
+	}
+	return policy, nil
+}
+
+// Name returns the rendered name, or the name constructed by DefaultNamePolicy if the template fails
+// to render, which is not expected for the template is checked by NewTemplateNamePolicy.
+func (p *TemplateNamePolicy) Name(role, project, stack, app string) string {
+	name, err := p.render(role, project, stack, app)
+	if err != nil {
+		return DefaultNamePolicy.Name(role, project, stack, app)
+	}
+	return name
+}
+
+func (p *TemplateNamePolicy) render(role, project, stack, app string) (string, error) {
+	var buf bytes.Buffer
+	err := p.tmpl.Execute(&buf, NameTemplateData{
+		Role:    role,
+		Name:    DefaultNamePolicy.Name(role, project, stack, app),
+		Project: project,
+		Stack:   stack,
+		App:     app,
+	})
+	return strings.TrimSpace(buf.String()), err
+}
+
+// NamePolicyOrDefault returns the given NamePolicy, or DefaultNamePolicy if it is nil.
+func NamePolicyOrDefault(policy NamePolicy) NamePolicy {
+	if policy == nil {
+		return DefaultNamePolicy
+	}
+	return policy
+}
+
+// NamedSpecGenerator is a SpecGenerator which constructs the names of the generated resources by the
+// NamePolicy.
+type NamedSpecGenerator interface {
+	SpecGenerator
+	SetNamePolicy(policy NamePolicy)
+}
+
+// WithNamePolicy wraps the NewSpecGeneratorFunc so that the NamePolicy is set to the returned
+// SpecGenerator if it supports. The NewSpecGeneratorFunc is returned as is if the policy is nil.
+func WithNamePolicy(policy NamePolicy, newGenerator NewSpecGeneratorFunc) NewSpecGeneratorFunc {
+	if policy == nil {
+		return newGenerator
+	}
+	return func() (SpecGenerator, error) {
+		g, err := newGenerator()
+		if err != nil {
+			return nil, err
+		}
+		if ng, ok := g.(NamedSpecGenerator); ok {
+			ng.SetNamePolicy(policy)
+		}
+		return g, nil
+	}
+}
+
+// ApplyNamingStrategy returns the final name of a generated resource with the specified
+// role by applying the naming extension to the given name. An explicit override of the role
+// takes precedence over the prefix and suffix. An error is returned if the final name is
+// longer than MaxNameLength.
+func ApplyNamingStrategy(naming *v1.KubeNamingExtension, role, name string) (string, error) {
+	if naming != nil {
+		if override, ok := naming.Overrides[role]; ok && override != "" {
+			name = override
+		} else {
+			name = naming.Prefix + name + naming.Suffix
+		}
+	}
+
+	if len(name) > MaxNameLength {
+		return "", fmt.Errorf("the name %s of resource role %s is %d characters long, which exceeds the limit of %d characters",
+			name, role, len(name), MaxNameLength)
+	}
+	return name, nil
+}
+
+// SecretName returns the final name of the secret declared with the given name in the workload of the
+// app, which is constructed by the NamePolicy and then customized by the naming extension.
+func SecretName(naming *v1.KubeNamingExtension, policy NamePolicy, project, stack, app, name string) (string, error) {
+	role := SecretRole(name)
+	return ApplyNamingStrategy(naming, role, NamePolicyOrDefault(policy).Name(role, project, stack, app))
+}
+
+// RenameSecretReferences rewrites the references to the secrets in the pod specs of the generated
+// Kubernetes resources in place, i.e. the env, envFrom, volumes and imagePullSecrets, where the keys of
+// names are the declared names of the secrets and the values are their final names. The workload modules
+// refer to the secrets by the declared names, so the references must follow the secrets once renamed.
+func RenameSecretReferences(resources v1.Resources, names map[string]string) {
+	if len(names) == 0 {
+		return
+	}
+
+	for i := range resources {
+		if resources[i].Type != v1.Kubernetes {
+			continue
+		}
+		for _, path := range podSpecPaths {
+			value, found, err := unstructured.NestedFieldNoCopy(resources[i].Attributes, path...)
+			if err != nil || !found {
+				continue
+			}
+			podSpec, ok := value.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			renameSecretReferencesInPodSpec(podSpec, names)
+		}
+	}
+}
+
+func renameSecretReferencesInPodSpec(podSpec map[string]interface{}, names map[string]string) {
+	for _, field := range []string{"initContainers", "containers"} {
+		forEachObject(podSpec, field, func(container map[string]interface{}) {
+			forEachObject(container, "env", func(env map[string]interface{}) {
+				renameSecretReference(env, names, "valueFrom", "secretKeyRef", "name")
+			})
+			forEachObject(container, "envFrom", func(envFrom map[string]interface{}) {
+				renameSecretReference(envFrom, names, "secretRef", "name")
+			})
+		})
+	}
+	forEachObject(podSpec, "volumes", func(volume map[string]interface{}) {
+		renameSecretReference(volume, names, "secret", "secretName")
+		value, found, err := unstructured.NestedFieldNoCopy(volume, "projected")
+		if err != nil || !found {
+			return
+		}
+		if projected, ok := value.(map[string]interface{}); ok {
+			forEachObject(projected, "sources", func(source map[string]interface{}) {
+				renameSecretReference(source, names, "secret", "name")
+			})
+		}
+	})
+	forEachObject(podSpec, "imagePullSecrets", func(ref map[string]interface{}) {
+		renameSecretReference(ref, names, "name")
+	})
+}
+
+// renameSecretReference renames the secret referred by the string field of the object in place.
+func renameSecretReference(obj map[string]interface{}, names map[string]string, fields ...string) {
+	name, found, err := unstructured.NestedString(obj, fields...)
+	if err != nil || !found {
+		return
+	}
+	if renamed, ok := names[name]; ok {
+		_ = unstructured.SetNestedField(obj, renamed, fields...)
+	}
+}
+
+// forEachObject calls f with each object in the array field of the object, skipping the non-object items.
+func forEachObject(obj map[string]interface{}, field string, f func(map[string]interface{})) {
+	items, ok := obj[field].([]interface{})
+	if !ok {
+		return
+	}
+	for _, item := range items {
+		if m, ok := item.(map[string]interface{}); ok {
+			f(m)
+		}
+	}
+}
+
+// NamespaceTemplateData is the data available to the template of the template namespace strategy.
+type NamespaceTemplateData struct {
+	Project   string
+	Stack     string
+	Workspace string
+	App       string
+	// Labels are the labels of the project.
+	Labels map[string]string
+}
+
+// DeriveNamespace returns the namespace derived by the strategy from the given data. The namespaces
+// derived from the names of the project, stack or workspace are normalized by NormalizeNamespaceName,
+// while the ones rendered by the template are not. An error is returned if the strategy is unknown, the
+// template fails to render, or the derived namespace is not a valid DNS-1123 label.
+func DeriveNamespace(strategy *v1.NamespaceStrategy, data NamespaceTemplateData) (string, error) {
+	var namespace string
+	switch strategy.Type {
+	case v1.NamespaceStrategyProject:
+		namespace = NormalizeNamespaceName(data.Project)
+	case v1.NamespaceStrategyProjectStack:
+		namespace = NormalizeNamespaceName(data.Project + "-" + data.Stack)
+	case v1.NamespaceStrategyWorkspace:
+		namespace = NormalizeNamespaceName(data.Workspace)
+	case v1.NamespaceStrategyTemplate:
+		tmpl, err := template.New("namespace").Option("missingkey=error").Parse(strategy.Template)
+		if err != nil {
+			return "", fmt.Errorf("invalid template of the namespace strategy: %w", err)
+		}
+		var buf bytes.Buffer
+		if err = tmpl.Execute(&buf, data); err != nil {
+			return "", fmt.Errorf("failed to render the template of the namespace strategy: %w", err)
+		}
+		namespace = strings.TrimSpace(buf.String())
+	default:
+		return "", fmt.Errorf("unknown namespace strategy type: %s", strategy.Type)
+	}
+
+	if err := ValidateNamespaceName(namespace); err != nil {
+		return "", fmt.Errorf("the namespace derived by the %s strategy is invalid: %w", strategy.Type, err)
+	}
+	return namespace, nil
+}
@@ -0,0 +1,289 @@
+package generators
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	v1 "kusionstack.io/kusion/pkg/apis/api.kusion.io/v1"
+)
+
+func TestDefaultNamePolicy(t *testing.T) {
+	assert.Equal(t, "helloworld", DefaultNamePolicy.Name(RoleNamespace, "helloworld", "dev", "app"))
+	assert.Equal(t, "hello-world-v2", DefaultNamePolicy.Name(RoleNamespace, "Hello_World.v2", "dev", "app"))
+	assert.Equal(t, "db", DefaultNamePolicy.Name(SecretRole("db"), "helloworld", "dev", "app"))
+	assert.Equal(t, DefaultNamePolicy, NamePolicyOrDefault(nil))
+}
+
+func TestTemplateNamePolicy(t *testing.T) {
+	policy, err := NewTemplateNamePolicy("{{ .Project }}-{{ .Stack }}-{{ .Name }}")
+	assert.NoError(t, err)
+	assert.Equal(t, "helloworld-dev-helloworld", policy.Name(RoleNamespace, "helloworld", "dev", "app"))
+	assert.Equal(t, "helloworld-dev-db", policy.Name(SecretRole("db"), "helloworld", "dev", "app"))
+
+	_, err = NewTemplateNamePolicy("{{ .Project")
+	assert.Error(t, err)
+	_, err = NewTemplateNamePolicy("{{ .Team }}-{{ .Name }}")
+	assert.Error(t, err)
+}
+
+type fakeNamedGenerator struct {
+	policy NamePolicy
+}
+
+func (g *fakeNamedGenerator) Generate(*v1.Spec) error { return nil }
+
+func (g *fakeNamedGenerator) SetNamePolicy(policy NamePolicy) { g.policy = policy }
+
+func TestWithNamePolicy(t *testing.T) {
+	policy, err := NewTemplateNamePolicy("team-{{ .Name }}")
+	assert.NoError(t, err)
+
+	g, err := WithNamePolicy(policy, func() (SpecGenerator, error) { return &fakeNamedGenerator{}, nil })()
+	assert.NoError(t, err)
+	assert.Equal(t, policy, g.(*fakeNamedGenerator).policy)
+
+	g, err = WithNamePolicy(nil, func() (SpecGenerator, error) { return &fakeNamedGenerator{}, nil })()
+	assert.NoError(t, err)
+	assert.Nil(t, g.(*fakeNamedGenerator).policy)
+}
+
+func TestApplyNamingStrategy(t *testing.T) {
+	testcases := []struct {
+		name         string
+		naming       *v1.KubeNamingExtension
+		role         string
+		resourceName string
+		expected     string
+		success      bool
+	}{
+		{
+			name:         "nil naming extension",
+			naming:       nil,
+			role:         SecretRole("db"),
+			resourceName: "db",
+			expected:     "db",
+			success:      true,
+		},
+		{
+			name: "apply prefix and suffix",
+			naming: &v1.KubeNamingExtension{
+				Prefix: "team-",
+				Suffix: "-prod",
+			},
+			role:         SecretRole("db"),
+			resourceName: "db",
+			expected:     "team-db-prod",
+			success:      true,
+		},
+		{
+			name: "override takes precedence",
+			naming: &v1.KubeNamingExtension{
+				Prefix: "team-",
+				Overrides: map[string]string{
+					SecretRole("db"): "custom-db",
+				},
+			},
+			role:         SecretRole("db"),
+			resourceName: "db",
+			expected:     "custom-db",
+			success:      true,
+		},
+		{
+			name: "name of 63 characters",
+			naming: &v1.KubeNamingExtension{
+				Prefix: strings.Repeat("a", 60),
+			},
+			role:         SecretRole("db"),
+			resourceName: "db",
+			expected:     strings.Repeat("a", 60) + "db",
+			success:      true,
+		},
+		{
+			name: "name exceeds 63 characters",
+			naming: &v1.KubeNamingExtension{
+				Prefix: strings.Repeat("a", 60),
+				Suffix: "-prod",
+			},
+			role:         SecretRole("db"),
+			resourceName: "db",
+			success:      false,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			name, err := ApplyNamingStrategy(tc.naming, tc.role, tc.resourceName)
+			assert.Equal(t, tc.success, err == nil)
+			if tc.success {
+				assert.Equal(t, tc.expected, name)
+			} else {
+				assert.Contains(t, err.Error(), "exceeds the limit of 63 characters")
+			}
+		})
+	}
+}
+
+func TestSecretName(t *testing.T) {
+	naming := &v1.KubeNamingExtension{Prefix: "team-"}
+	name, err := SecretName(naming, nil, "helloworld", "dev", "web", "db")
+	assert.NoError(t, err)
+	assert.Equal(t, "team-db", name)
+}
+
+func TestRenameSecretReferences(t *testing.T) {
+	podSpec := func(secretName string) map[string]interface{} {
+		return map[string]interface{}{
+			"containers": []interface{}{
+				map[string]interface{}{
+					"name":  "web",
+					"image": "nginx",
+					"env": []interface{}{
+						map[string]interface{}{"name": "PLAIN", "value": "db"},
+						map[string]interface{}{"name": "PASSWORD", "valueFrom": map[string]interface{}{
+							"secretKeyRef": map[string]interface{}{"name": secretName, "key": "password"},
+						}},
+					},
+					"envFrom": []interface{}{
+						map[string]interface{}{"secretRef": map[string]interface{}{"name": secretName}},
+					},
+				},
+			},
+			"volumes": []interface{}{
+				map[string]interface{}{"name": "db", "secret": map[string]interface{}{"secretName": secretName}},
+				map[string]interface{}{"name": "projected", "projected": map[string]interface{}{
+					"sources": []interface{}{
+						map[string]interface{}{"secret": map[string]interface{}{"name": secretName}},
+					},
+				}},
+				map[string]interface{}{"name": "other", "secret": map[string]interface{}{"secretName": "other"}},
+			},
+			"imagePullSecrets": []interface{}{
+				map[string]interface{}{"name": secretName},
+			},
+		}
+	}
+	resources := v1.Resources{
+		{
+			ID:   "apps/v1:Deployment:default:web",
+			Type: v1.Kubernetes,
+			Attributes: map[string]interface{}{
+				"kind": "Deployment",
+				"spec": map[string]interface{}{"template": map[string]interface{}{"spec": podSpec("db")}},
+			},
+		},
+		{
+			ID:         "v1:Pod:default:web",
+			Type:       v1.Kubernetes,
+			Attributes: map[string]interface{}{"kind": "Pod", "spec": podSpec("db")},
+		},
+		{
+			ID:         "aws:db",
+			Type:       v1.Terraform,
+			Attributes: map[string]interface{}{"spec": podSpec("db")},
+		},
+	}
+
+	RenameSecretReferences(resources, map[string]string{"db": "team-db"})
+	assert.Equal(t, map[string]interface{}{"template": map[string]interface{}{"spec": podSpec("team-db")}},
+		resources[0].Attributes["spec"])
+	assert.Equal(t, podSpec("team-db"), resources[1].Attributes["spec"])
+	assert.Equal(t, podSpec("db"), resources[2].Attributes["spec"], "non-Kubernetes resources are left as they are")
+}
+
+func TestDeriveNamespace(t *testing.T) {
+	data := NamespaceTemplateData{
+		Project:   "helloworld",
+		Stack:     "dev",
+		Workspace: "prod-cluster",
+		App:       "web",
+		Labels:    map[string]string{"team": "payment"},
+	}
+	testcases := []struct {
+		name     string
+		strategy *v1.NamespaceStrategy
+		data     NamespaceTemplateData
+		expected string
+		errMsg   string
+	}{
+		{
+			name:     "project strategy",
+			strategy: &v1.NamespaceStrategy{Type: v1.NamespaceStrategyProject},
+			data:     data,
+			expected: "helloworld",
+		},
+		{
+			name:     "project-stack strategy",
+			strategy: &v1.NamespaceStrategy{Type: v1.NamespaceStrategyProjectStack},
+			data:     data,
+			expected: "helloworld-dev",
+		},
+		{
+			name:     "workspace strategy",
+			strategy: &v1.NamespaceStrategy{Type: v1.NamespaceStrategyWorkspace},
+			data:     data,
+			expected: "prod-cluster",
+		},
+		{
+			name:     "template strategy",
+			strategy: &v1.NamespaceStrategy{Type: v1.NamespaceStrategyTemplate, Template: "{{ .Labels.team }}-{{ .Stack }}"},
+			data:     data,
+			expected: "payment-dev",
+		},
+		{
+			name:     "template strategy with missing label",
+			strategy: &v1.NamespaceStrategy{Type: v1.NamespaceStrategyTemplate, Template: "{{ .Labels.owner }}-{{ .Stack }}"},
+			data:     data,
+			errMsg:   "failed to render the template of the namespace strategy",
+		},
+		{
+			name:     "normalized derived namespace",
+			strategy: &v1.NamespaceStrategy{Type: v1.NamespaceStrategyProjectStack},
+			data:     NamespaceTemplateData{Project: "Hello_World", Stack: "dev"},
+			expected: "hello-world-dev",
+		},
+		{
+			name:     "truncated derived namespace",
+			strategy: &v1.NamespaceStrategy{Type: v1.NamespaceStrategyProjectStack},
+			data:     NamespaceTemplateData{Project: strings.Repeat("a", 60), Stack: "dev"},
+			expected: strings.Repeat("a", 60) + "-de",
+		},
+		{
+			name:     "invalid derived namespace",
+			strategy: &v1.NamespaceStrategy{Type: v1.NamespaceStrategyWorkspace},
+			data:     NamespaceTemplateData{Workspace: "__"},
+			errMsg:   `the namespace derived by the workspace strategy is invalid: invalid namespace name ""`,
+		},
+		{
+			name:     "invalid rendered namespace",
+			strategy: &v1.NamespaceStrategy{Type: v1.NamespaceStrategyTemplate, Template: "{{ .Project }}"},
+			data:     NamespaceTemplateData{Project: "Hello_World"},
+			errMsg:   `the namespace derived by the template strategy is invalid: invalid namespace name "Hello_World"`,
+		},
+		{
+			name:     "too long rendered namespace",
+			strategy: &v1.NamespaceStrategy{Type: v1.NamespaceStrategyTemplate, Template: "{{ .Project }}-{{ .Stack }}"},
+			data:     NamespaceTemplateData{Project: strings.Repeat("a", 60), Stack: "dev"},
+			errMsg:   "must be no more than 63 characters",
+		},
+		{
+			name:     "unknown strategy",
+			strategy: &v1.NamespaceStrategy{Type: "label"},
+			data:     data,
+			errMsg:   "unknown namespace strategy type: label",
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			actual, err := DeriveNamespace(tc.strategy, tc.data)
+			if tc.errMsg != "" {
+				assert.ErrorContains(t, err, tc.errMsg)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expected, actual)
+		})
+	}
+}
@@ -0,0 +1,149 @@
+package generators
+
+import (
+	"encoding/json"
+	"fmt"
+
+	jsonpatch "github.com/evanphx/json-patch/v5"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/util/strategicpatch"
+	"k8s.io/client-go/kubernetes/scheme"
+
+	v1 "kusionstack.io/kusion/pkg/apis/api.kusion.io/v1"
+	"kusionstack.io/kusion/pkg/log"
+	jsonutil "kusionstack.io/kusion/pkg/util/json"
+)
+
+// ApplyPatchExtension applies the JSON Patch operations of the patch extension to the matching generated
+// Kubernetes resources in place, in the order of the patches. An operation on an invalid path fails the
+// patch with the target resource named, and a patch matching no resource is skipped with a warning.
+func ApplyPatchExtension(resources v1.Resources, ext *v1.KubePatchExtension) error {
+	if ext == nil {
+		return nil
+	}
+
+	for i, p := range ext.Patches {
+		if p.Target.Kind == "" {
+			return fmt.Errorf("target kind of patch %d is empty", i)
+		}
+		operations, err := json.Marshal(p.Operations)
+		if err != nil {
+			return err
+		}
+		patch, err := jsonpatch.DecodePatch(operations)
+		if err != nil {
+			return fmt.Errorf("invalid operations of patch %d: %w", i, err)
+		}
+
+		matched := false
+		for j := range resources {
+			if resources[j].Type != v1.Kubernetes {
+				continue
+			}
+			obj := &unstructured.Unstructured{Object: resources[j].Attributes}
+			if !matchPatchTarget(obj, p.Target) {
+				continue
+			}
+			matched = true
+
+			doc, err := json.Marshal(resources[j].Attributes)
+			if err != nil {
+				return err
+			}
+			modified, err := patch.Apply(doc)
+			if err != nil {
+				return fmt.Errorf("failed to apply patch %d to resource %s: %w", i, resources[j].ID, err)
+			}
+			attributes := make(map[string]interface{})
+			if err = json.Unmarshal(modified, &attributes); err != nil {
+				return err
+			}
+			resources[j].Attributes = jsonutil.NormalizeNumbers(attributes).(map[string]interface{})
+		}
+		if !matched {
+			log.Warnf("no resource matches the target %s %s %s of patch %d, skipped", p.Target.APIVersion, p.Target.Kind, p.Target.Name, i)
+		}
+	}
+
+	return nil
+}
+
+// ApplyMergePatchExtension applies the merge patches of the merge patch extension to the matching generated
+// Kubernetes resources in place, in the order of the patches. The strategic merge patch is used for the
+// built-in Kubernetes types, so that the lists with merge keys, e.g. the containers, are merged by key
+// instead of replaced, and the JSON merge patch is used for the other types, such as the custom resources.
+func ApplyMergePatchExtension(resources v1.Resources, ext *v1.KubeMergePatchExtension) error {
+	if ext == nil {
+		return nil
+	}
+
+	for i, p := range ext.Patches {
+		if p.Target.Kind == "" {
+			return fmt.Errorf("target kind of merge patch %d is empty", i)
+		}
+		patch, err := json.Marshal(p.Patch)
+		if err != nil {
+			return fmt.Errorf("invalid merge patch %d: %w", i, err)
+		}
+
+		matched := false
+		for j := range resources {
+			if resources[j].Type != v1.Kubernetes {
+				continue
+			}
+			obj := &unstructured.Unstructured{Object: resources[j].Attributes}
+			if !matchPatchTarget(obj, p.Target) {
+				continue
+			}
+			matched = true
+
+			modified, err := mergePatch(obj, patch)
+			if err != nil {
+				return fmt.Errorf("failed to apply merge patch %d to resource %s: %w", i, resources[j].ID, err)
+			}
+			resources[j].Attributes = jsonutil.NormalizeNumbers(modified).(map[string]interface{})
+		}
+		if !matched {
+			log.Warnf("no resource matches the target %s %s %s of merge patch %d, skipped", p.Target.APIVersion, p.Target.Kind, p.Target.Name, i)
+		}
+	}
+
+	return nil
+}
+
+// mergePatch applies the patch to the object with the strategic merge patch if the object is of a built-in
+// type, otherwise with the JSON merge patch.
+func mergePatch(obj *unstructured.Unstructured, patch []byte) (map[string]interface{}, error) {
+	doc, err := json.Marshal(obj.Object)
+	if err != nil {
+		return nil, err
+	}
+	var modified []byte
+	if typed, err := scheme.Scheme.New(obj.GroupVersionKind()); err == nil {
+		modified, err = strategicpatch.StrategicMergePatch(doc, patch, typed)
+		if err != nil {
+			return nil, err
+		}
+	} else {
+		modified, err = jsonpatch.MergePatch(doc, patch)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	attributes := make(map[string]interface{})
+	if err = json.Unmarshal(modified, &attributes); err != nil {
+		return nil, err
+	}
+	return attributes, nil
+}
+
+func matchPatchTarget(obj *unstructured.Unstructured, target v1.KubePatchTarget) bool {
+	if obj.GetKind() != target.Kind {
+		return false
+	}
+	if target.APIVersion != "" && obj.GetAPIVersion() != target.APIVersion {
+		return false
+	}
+	return target.Name == "" || obj.GetName() == target.Name
+}
@@ -0,0 +1,289 @@
+package generators
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	v1 "kusionstack.io/kusion/pkg/apis/api.kusion.io/v1"
+)
+
+func newTestDeployment(name string) v1.Resource {
+	return v1.Resource{
+		ID:   "apps/v1:Deployment:default:" + name,
+		Type: v1.Kubernetes,
+		Attributes: map[string]interface{}{
+			"apiVersion": "apps/v1",
+			"kind":       "Deployment",
+			"metadata": map[string]interface{}{
+				"name":      name,
+				"namespace": "default",
+				"labels":    map[string]interface{}{"app": name},
+			},
+			"spec": map[string]interface{}{
+				"replicas":                int64(1),
+				"progressDeadlineSeconds": int64(600),
+			},
+		},
+	}
+}
+
+func TestApplyPatchExtension(t *testing.T) {
+	testcases := []struct {
+		name     string
+		patch    v1.KubePatch
+		expected map[string]interface{}
+		err      string
+	}{
+		{
+			name: "add",
+			patch: v1.KubePatch{
+				Target:     v1.KubePatchTarget{APIVersion: "apps/v1", Kind: "Deployment", Name: "foo"},
+				Operations: []v1.JSONPatchOperation{{Op: "add", Path: "/metadata/labels/team", Value: "infra"}},
+			},
+			expected: map[string]interface{}{
+				"metadata": map[string]interface{}{
+					"name":      "foo",
+					"namespace": "default",
+					"labels":    map[string]interface{}{"app": "foo", "team": "infra"},
+				},
+				"spec": map[string]interface{}{"replicas": int64(1), "progressDeadlineSeconds": int64(600)},
+			},
+		},
+		{
+			name: "replace",
+			patch: v1.KubePatch{
+				Target:     v1.KubePatchTarget{Kind: "Deployment"},
+				Operations: []v1.JSONPatchOperation{{Op: "replace", Path: "/spec/replicas", Value: 3}},
+			},
+			expected: map[string]interface{}{
+				"metadata": map[string]interface{}{
+					"name":      "foo",
+					"namespace": "default",
+					"labels":    map[string]interface{}{"app": "foo"},
+				},
+				"spec": map[string]interface{}{"replicas": int64(3), "progressDeadlineSeconds": int64(600)},
+			},
+		},
+		{
+			name: "remove",
+			patch: v1.KubePatch{
+				Target:     v1.KubePatchTarget{Kind: "Deployment", Name: "foo"},
+				Operations: []v1.JSONPatchOperation{{Op: "remove", Path: "/spec/progressDeadlineSeconds"}},
+			},
+			expected: map[string]interface{}{
+				"metadata": map[string]interface{}{
+					"name":      "foo",
+					"namespace": "default",
+					"labels":    map[string]interface{}{"app": "foo"},
+				},
+				"spec": map[string]interface{}{"replicas": int64(1)},
+			},
+		},
+		{
+			name: "no matched resource",
+			patch: v1.KubePatch{
+				Target:     v1.KubePatchTarget{Kind: "Deployment", Name: "bar"},
+				Operations: []v1.JSONPatchOperation{{Op: "remove", Path: "/spec/replicas"}},
+			},
+			expected: map[string]interface{}{
+				"metadata": map[string]interface{}{
+					"name":      "foo",
+					"namespace": "default",
+					"labels":    map[string]interface{}{"app": "foo"},
+				},
+				"spec": map[string]interface{}{"replicas": int64(1), "progressDeadlineSeconds": int64(600)},
+			},
+		},
+		{
+			name: "invalid path",
+			patch: v1.KubePatch{
+				Target:     v1.KubePatchTarget{Kind: "Deployment"},
+				Operations: []v1.JSONPatchOperation{{Op: "replace", Path: "/spec/template/spec/hostNetwork", Value: true}},
+			},
+			err: "failed to apply patch 0 to resource apps/v1:Deployment:default:foo",
+		},
+		{
+			name: "invalid operation",
+			patch: v1.KubePatch{
+				Target:     v1.KubePatchTarget{Kind: "Deployment"},
+				Operations: []v1.JSONPatchOperation{{Op: "merge", Path: "/spec"}},
+			},
+			err: "invalid operations of patch 0",
+		},
+		{
+			name: "empty target kind",
+			patch: v1.KubePatch{
+				Operations: []v1.JSONPatchOperation{{Op: "remove", Path: "/spec/replicas"}},
+			},
+			err: "target kind of patch 0 is empty",
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			resources := v1.Resources{newTestDeployment("foo")}
+			err := ApplyPatchExtension(resources, &v1.KubePatchExtension{Patches: []v1.KubePatch{tc.patch}})
+			if tc.err != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tc.err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected["metadata"], resources[0].Attributes["metadata"])
+			assert.Equal(t, tc.expected["spec"], resources[0].Attributes["spec"])
+		})
+	}
+}
+
+func TestApplyPatchExtensionTargets(t *testing.T) {
+	resources := v1.Resources{newTestDeployment("foo"), newTestDeployment("bar"), newTestService("ClusterIP", nil)}
+	ext := &v1.KubePatchExtension{Patches: []v1.KubePatch{
+		{
+			Target:     v1.KubePatchTarget{Kind: "Deployment"},
+			Operations: []v1.JSONPatchOperation{{Op: "add", Path: "/metadata/labels/team", Value: "infra"}},
+		},
+		{
+			Target:     v1.KubePatchTarget{Kind: "Deployment", Name: "bar"},
+			Operations: []v1.JSONPatchOperation{{Op: "replace", Path: "/spec/replicas", Value: 2}},
+		},
+		{
+			Target:     v1.KubePatchTarget{APIVersion: "apps/v1beta1", Kind: "Deployment"},
+			Operations: []v1.JSONPatchOperation{{Op: "remove", Path: "/spec"}},
+		},
+	}}
+	require.NoError(t, ApplyPatchExtension(resources, ext))
+
+	for _, r := range resources[:2] {
+		labels := r.Attributes["metadata"].(map[string]interface{})["labels"]
+		assert.Equal(t, "infra", labels.(map[string]interface{})["team"], r.ID)
+	}
+	assert.Equal(t, int64(1), resources[0].Attributes["spec"].(map[string]interface{})["replicas"])
+	assert.Equal(t, int64(2), resources[1].Attributes["spec"].(map[string]interface{})["replicas"])
+	assert.NotContains(t, resources[2].Attributes["metadata"], "labels")
+}
+
+func newTestDeploymentWithContainers() v1.Resource {
+	r := newTestDeployment("foo")
+	r.Attributes["spec"] = map[string]interface{}{
+		"replicas": int64(1),
+		"template": map[string]interface{}{
+			"spec": map[string]interface{}{
+				"containers": []interface{}{
+					map[string]interface{}{
+						"name":      "app",
+						"image":     "nginx:1.25",
+						"resources": map[string]interface{}{"limits": map[string]interface{}{"cpu": "500m"}},
+					},
+					map[string]interface{}{"name": "sidecar", "image": "envoy:1.29"},
+				},
+			},
+		},
+	}
+	return r
+}
+
+func TestApplyMergePatchExtension(t *testing.T) {
+	resources := v1.Resources{newTestDeploymentWithContainers()}
+	ext := &v1.KubeMergePatchExtension{Patches: []v1.KubeMergePatch{
+		{
+			Target: v1.KubePatchTarget{APIVersion: "apps/v1", Kind: "Deployment", Name: "foo"},
+			Patch: map[string]interface{}{
+				"metadata": map[string]interface{}{
+					"annotations": map[string]interface{}{"owner": "infra"},
+				},
+				"spec": map[string]interface{}{
+					"template": map[string]interface{}{
+						"spec": map[string]interface{}{
+							"containers": []interface{}{
+								map[string]interface{}{
+									"name": "app",
+									"resources": map[string]interface{}{
+										"limits":   map[string]interface{}{"cpu": "1", "memory": "1Gi"},
+										"requests": map[string]interface{}{"cpu": "500m"},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}}
+	require.NoError(t, ApplyMergePatchExtension(resources, ext))
+
+	metadata := resources[0].Attributes["metadata"].(map[string]interface{})
+	assert.Equal(t, map[string]interface{}{"owner": "infra"}, metadata["annotations"])
+	assert.Equal(t, map[string]interface{}{"app": "foo"}, metadata["labels"])
+
+	// the containers are merged by name instead of replaced
+	spec := resources[0].Attributes["spec"].(map[string]interface{})
+	assert.Equal(t, int64(1), spec["replicas"])
+	containers := spec["template"].(map[string]interface{})["spec"].(map[string]interface{})["containers"]
+	assert.Equal(t, []interface{}{
+		map[string]interface{}{
+			"name":  "app",
+			"image": "nginx:1.25",
+			"resources": map[string]interface{}{
+				"limits":   map[string]interface{}{"cpu": "1", "memory": "1Gi"},
+				"requests": map[string]interface{}{"cpu": "500m"},
+			},
+		},
+		map[string]interface{}{"name": "sidecar", "image": "envoy:1.29"},
+	}, containers)
+}
+
+func TestApplyMergePatchExtensionCustomResource(t *testing.T) {
+	resources := v1.Resources{{
+		ID:   "example.com/v1:Foo:default:foo",
+		Type: v1.Kubernetes,
+		Attributes: map[string]interface{}{
+			"apiVersion": "example.com/v1",
+			"kind":       "Foo",
+			"metadata":   map[string]interface{}{"name": "foo", "namespace": "default"},
+			"spec": map[string]interface{}{
+				"items":  []interface{}{map[string]interface{}{"name": "a"}, map[string]interface{}{"name": "b"}},
+				"size":   int64(1),
+				"paused": true,
+			},
+		},
+	}}
+	ext := &v1.KubeMergePatchExtension{Patches: []v1.KubeMergePatch{
+		{
+			Target: v1.KubePatchTarget{Kind: "Foo"},
+			Patch: map[string]interface{}{
+				"spec": map[string]interface{}{
+					"items":  []interface{}{map[string]interface{}{"name": "c"}},
+					"size":   3,
+					"paused": nil,
+				},
+			},
+		},
+	}}
+	require.NoError(t, ApplyMergePatchExtension(resources, ext))
+
+	// the lists of the custom resources are replaced with the JSON merge patch
+	assert.Equal(t, map[string]interface{}{
+		"items": []interface{}{map[string]interface{}{"name": "c"}},
+		"size":  int64(3),
+	}, resources[0].Attributes["spec"])
+}
+
+func TestApplyMergePatchExtensionError(t *testing.T) {
+	resources := v1.Resources{newTestDeploymentWithContainers()}
+	err := ApplyMergePatchExtension(resources, &v1.KubeMergePatchExtension{Patches: []v1.KubeMergePatch{
+		{
+			Target: v1.KubePatchTarget{Kind: "Deployment"},
+			// the container without the merge key
+			Patch: map[string]interface{}{"spec": map[string]interface{}{"template": map[string]interface{}{
+				"spec": map[string]interface{}{"containers": []interface{}{map[string]interface{}{"image": "nginx"}}},
+			}}},
+		},
+	}})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "failed to apply merge patch 0 to resource apps/v1:Deployment:default:foo")
+
+	err = ApplyMergePatchExtension(resources, &v1.KubeMergePatchExtension{Patches: []v1.KubeMergePatch{{}}})
+	assert.EqualError(t, err, "target kind of merge patch 0 is empty")
+}
@@ -1,6 +1,7 @@
 package secret
 
 import (
+	"io"
 	"math/rand"
 	"sync"
 	"time"
@@ -50,3 +51,24 @@ func GenerateRandomString(n int) string {
 
 	return *(*string)(unsafe.Pointer(&b))
 }
+
+// generateRandomStringFrom generates a random alphanumeric string, without vowels and which is
+// n characters long, with the random bytes read from r. The string is deterministic if r is,
+// e.g. a seeded math/rand.Rand.
+func generateRandomStringFrom(r io.Reader, n int) (string, error) {
+	b := make([]byte, 0, n)
+	buf := make([]byte, n)
+	for len(b) < n {
+		if _, err := io.ReadFull(r, buf); err != nil {
+			return "", err
+		}
+		// Bytes indexing out of alphanums are dropped to keep the characters uniformly distributed.
+		for _, c := range buf {
+			if idx := int(c & alphanumsIdxMask); idx < len(alphanums) && len(b) < n {
+				b = append(b, alphanums[idx])
+			}
+		}
+	}
+
+	return string(b), nil
+}
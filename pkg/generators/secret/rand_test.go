@@ -1,6 +1,7 @@
 package secret
 
 import (
+	"math/rand"
 	"strings"
 	"testing"
 )
@@ -20,6 +21,32 @@ func TestGenerateRandomString(t *testing.T) {
 	}
 }
 
+func TestGenerateRandomStringFrom(t *testing.T) {
+	valid := "bcdfghjklmnpqrstvwxzBCDFGHJKLMNPQRSTVWXZ2456789"
+	for _, l := range []int{0, 1, 2, 10, 52} {
+		s, err := generateRandomStringFrom(rand.New(rand.NewSource(1)), l)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(s) != l {
+			t.Errorf("expected random string of size %d, actually got %q", l, s)
+		}
+		for _, c := range s {
+			if !strings.ContainsRune(valid, c) {
+				t.Errorf("expected valid characters, got %v", c)
+			}
+		}
+		same, _ := generateRandomStringFrom(rand.New(rand.NewSource(1)), l)
+		if s != same {
+			t.Errorf("expected identical strings for identical seed, got %q and %q", s, same)
+		}
+	}
+
+	if _, err := generateRandomStringFrom(strings.NewReader(""), 10); err == nil {
+		t.Errorf("expected error for exhausted random source")
+	}
+}
+
 func BenchmarkRandomStringGeneration(b *testing.B) {
 	b.ResetTimer()
 	var s string
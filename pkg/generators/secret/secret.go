@@ -1,8 +1,11 @@
 package secret
 
 import (
+	"crypto/rand"
 	"errors"
 	"fmt"
+	"io"
+	"sort"
 
 	"golang.org/x/exp/maps"
 	"gopkg.in/yaml.v3"
@@ -17,20 +20,36 @@ import (
 
 type secretGenerator struct {
 	project     string
+	stack       string
+	app         string
 	namespace   string
 	secrets     map[string]v1.Secret
 	secretStore *v1.SecretStore
+	naming      *v1.KubeNamingExtension
+	namePolicy  generators.NamePolicy
+	rand        io.Reader
 }
 
 type GeneratorRequest struct {
 	// Project represents the Project name
 	Project string
+	// Stack represents the Stack name
+	Stack string
+	// App represents the App name
+	App string
 	// Namespace represents the K8s Namespace
 	Namespace string
 	// Workload represents the Workload configuration
 	Workload v1.Accessory
 	// SecretStore contains configuration to describe target secret store.
 	SecretStore *v1.SecretStore
+	// Naming customizes the names of the generated secrets.
+	Naming *v1.KubeNamingExtension
+	// NamePolicy constructs the names of the generated secrets, defaults to generators.DefaultNamePolicy.
+	NamePolicy generators.NamePolicy
+	// Rand is the source of randomness for the generated passwords and tokens, defaults to crypto/rand.Reader.
+	// Set it to a seeded source, e.g. a math/rand.Rand, for reproducible secrets.
+	Rand io.Reader
 }
 
 func NewSecretGenerator(request *GeneratorRequest) (generators.SpecGenerator, error) {
@@ -38,32 +57,76 @@ func NewSecretGenerator(request *GeneratorRequest) (generators.SpecGenerator, er
 		return nil, fmt.Errorf("project name must not be empty")
 	}
 
-	secretMap := make(map[string]v1.Secret)
-
 	if request.Workload == nil {
 		log.Infof("workload is missing, no secret will be generated")
 		return &secretGenerator{}, nil
 	}
-	secrets := request.Workload["secrets"]
-	if secrets != nil {
-		out, err := yaml.Marshal(secrets)
-		if err != nil {
-			return nil, err
-		}
-		err = yaml.Unmarshal(out, &secretMap)
-		if err != nil {
-			return nil, err
-		}
+	secretMap, err := parseSecrets(request.Workload)
+	if err != nil {
+		return nil, err
 	}
 
 	return &secretGenerator{
 		project:     request.Project,
+		stack:       request.Stack,
+		app:         request.App,
 		secrets:     secretMap,
 		namespace:   request.Namespace,
 		secretStore: request.SecretStore,
+		naming:      request.Naming,
+		namePolicy:  generators.NamePolicyOrDefault(request.NamePolicy),
+		rand:        randOrDefault(request.Rand),
 	}, nil
 }
 
+// parseSecrets parses the secrets declared in the workload, whose keys are the declared names.
+func parseSecrets(workload v1.Accessory) (map[string]v1.Secret, error) {
+	secretMap := make(map[string]v1.Secret)
+	secrets := workload["secrets"]
+	if secrets == nil {
+		return secretMap, nil
+	}
+	out, err := yaml.Marshal(secrets)
+	if err != nil {
+		return nil, err
+	}
+	if err = yaml.Unmarshal(out, &secretMap); err != nil {
+		return nil, err
+	}
+	return secretMap, nil
+}
+
+// RenamedSecrets returns the final names of the secrets to be generated for the request which differ from
+// their declared names in the workload, keyed by the declared names, see generators.RenameSecretReferences.
+func RenamedSecrets(request *GeneratorRequest) (map[string]string, error) {
+	if request.Workload == nil {
+		return nil, nil
+	}
+	secretMap, err := parseSecrets(request.Workload)
+	if err != nil {
+		return nil, err
+	}
+
+	renamed := make(map[string]string)
+	for secretName := range secretMap {
+		name, err := generators.SecretName(request.Naming, request.NamePolicy, request.Project, request.Stack, request.App, secretName)
+		if err != nil {
+			return nil, err
+		}
+		if name != secretName {
+			renamed[secretName] = name
+		}
+	}
+	return renamed, nil
+}
+
+func randOrDefault(r io.Reader) io.Reader {
+	if r == nil {
+		return rand.Reader
+	}
+	return r
+}
+
 func NewSecretGeneratorFunc(request *GeneratorRequest) generators.NewSpecGeneratorFunc {
 	return func() (generators.SpecGenerator, error) {
 		return NewSecretGenerator(request)
@@ -75,8 +138,16 @@ func (g *secretGenerator) Generate(spec *v1.Spec) error {
 		spec.Resources = make(v1.Resources, 0)
 	}
 
-	for secretName, secretRef := range g.secrets {
-		secret, err := g.generateSecret(secretName, secretRef)
+	// iterate the secrets in name order to generate deterministically
+	secretNames := maps.Keys(g.secrets)
+	sort.Strings(secretNames)
+	for _, secretName := range secretNames {
+		secretRef := g.secrets[secretName]
+		name, err := generators.SecretName(g.naming, g.namePolicy, g.project, g.stack, g.app, secretName)
+		if err != nil {
+			return err
+		}
+		secret, err := g.generateSecret(name, secretRef)
 		if err != nil {
 			return err
 		}
@@ -124,7 +195,10 @@ func (g *secretGenerator) generateBasic(secretName string, secretRef v1.Secret)
 
 	for _, key := range []string{corev1.BasicAuthUsernameKey, corev1.BasicAuthPasswordKey} {
 		if len(secret.Data[key]) == 0 {
-			v := GenerateRandomString(54)
+			v, err := generateRandomStringFrom(g.rand, 54)
+			if err != nil {
+				return nil, fmt.Errorf("failed to generate %s of secret %s: %w", key, secretName, err)
+			}
 			secret.Data[key] = []byte(v)
 		}
 	}
@@ -139,7 +213,10 @@ func (g *secretGenerator) generateToken(secretName string, secretRef v1.Secret)
 	secret.Data = grabData(secretRef.Data, "token")
 
 	if len(secret.Data["token"]) == 0 {
-		v := GenerateRandomString(54)
+		v, err := generateRandomStringFrom(g.rand, 54)
+		if err != nil {
+			return nil, fmt.Errorf("failed to generate token of secret %s: %w", secretName, err)
+		}
 		secret.Data["token"] = []byte(v)
 	}
 
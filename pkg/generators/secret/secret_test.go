@@ -1,11 +1,14 @@
 package secret
 
 import (
+	"math/rand"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/require"
 
 	v1 "kusionstack.io/kusion/pkg/apis/api.kusion.io/v1"
+	"kusionstack.io/kusion/pkg/generators/generatortest"
 	// ensure we can get correct secret store provider
 	_ "kusionstack.io/kusion/pkg/secrets/providers/register"
 )
@@ -168,3 +171,118 @@ func TestGenerateSecretWithExternalRef(t *testing.T) {
 		})
 	}
 }
+
+func TestGenerateSecretWithNaming(t *testing.T) {
+	secrets := map[string]v1.Secret{
+		"api-auth": {
+			Type: "opaque",
+			Data: map[string]string{"accessKey": "dHJ1ZQ=="},
+		},
+		"api-token": {
+			Type: "token",
+		},
+	}
+	context := initGeneratorRequest(testProject, secrets, nil)
+	context.Naming = &v1.KubeNamingExtension{
+		Prefix: "team-",
+		Overrides: map[string]string{
+			"secret:api-token": "custom-token",
+		},
+	}
+	generator, err := NewSecretGenerator(context)
+	require.NoError(t, err)
+
+	spec := &v1.Spec{}
+	require.NoError(t, generator.Generate(spec))
+	require.Len(t, spec.Resources, 2)
+	index := spec.Resources.Index()
+	require.Contains(t, index, "v1:Secret:helloworld:team-api-auth")
+	require.Contains(t, index, "v1:Secret:helloworld:custom-token")
+
+	renamed, err := RenamedSecrets(context)
+	require.NoError(t, err)
+	require.Equal(t, map[string]string{"api-auth": "team-api-auth", "api-token": "custom-token"}, renamed)
+}
+
+type hashSuffixNamePolicy struct{}
+
+func (hashSuffixNamePolicy) Name(role, project, stack, app string) string {
+	return strings.Join([]string{app, strings.TrimPrefix(role, "secret:"), "a1b2c3"}, "-")
+}
+
+func TestGenerateSecretWithNamePolicy(t *testing.T) {
+	secrets := map[string]v1.Secret{
+		"api-auth": {
+			Type: "opaque",
+			Data: map[string]string{"accessKey": "dHJ1ZQ=="},
+		},
+		"api-token": {
+			Type: "token",
+		},
+	}
+	context := initGeneratorRequest(testProject, secrets, nil)
+	context.Stack = "dev"
+	context.App = "app1"
+	context.NamePolicy = hashSuffixNamePolicy{}
+	generator, err := NewSecretGenerator(context)
+	require.NoError(t, err)
+
+	spec := &v1.Spec{}
+	require.NoError(t, generator.Generate(spec))
+	require.Len(t, spec.Resources, 2)
+	for _, res := range spec.Resources {
+		name := res.Attributes["metadata"].(map[string]any)["name"].(string)
+		require.True(t, strings.HasPrefix(name, "app1-"))
+		require.True(t, strings.HasSuffix(name, "-a1b2c3"))
+	}
+}
+
+func TestGenerateSecretIdempotent(t *testing.T) {
+	secrets := map[string]v1.Secret{
+		"db-auth": {
+			Type: "basic",
+		},
+		"api-token": {
+			Type: "token",
+		},
+		"api-auth": {
+			Type: "opaque",
+			Data: map[string]string{"accessKey": "dHJ1ZQ=="},
+		},
+	}
+	request := initGeneratorRequest(testProject, secrets, nil)
+
+	// the generated passwords and tokens are deterministic with the seeded random source
+	generatortest.RunGeneratorTwice(t, NewSecretGeneratorFunc(request), generatortest.WithBeforeEach(func() {
+		request.Rand = rand.New(rand.NewSource(1))
+	}))
+}
+
+func TestGenerateSecretWithSeededRand(t *testing.T) {
+	secrets := map[string]v1.Secret{
+		"db-auth": {
+			Type: "basic",
+			Data: map[string]string{"username": "admin"},
+		},
+	}
+	generatePassword := func(seed int64) string {
+		request := initGeneratorRequest(testProject, secrets, nil)
+		request.Rand = rand.New(rand.NewSource(seed))
+		generator, err := NewSecretGenerator(request)
+		require.NoError(t, err)
+
+		spec := &v1.Spec{}
+		require.NoError(t, generator.Generate(spec))
+		require.Len(t, spec.Resources, 1)
+		data, ok := spec.Resources[0].Attributes["data"].(map[string]interface{})
+		require.True(t, ok)
+		require.Equal(t, "YWRtaW4=", data["username"])
+		password, ok := data["password"].(string)
+		require.True(t, ok)
+		require.NotEmpty(t, password)
+		return password
+	}
+
+	require.Equal(t, generatePassword(42), generatePassword(42))
+	require.NotEqual(t, generatePassword(42), generatePassword(43))
+}
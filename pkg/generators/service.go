@@ -0,0 +1,158 @@
+package generators
+
+import (
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	v1 "kusionstack.io/kusion/pkg/apis/api.kusion.io/v1"
+)
+
+// supportedServiceTypes are the Service types which can be set by KubeServiceExtension.
+var supportedServiceTypes = map[string]struct{}{
+	"ClusterIP":    {},
+	"NodePort":     {},
+	"LoadBalancer": {},
+}
+
+// loadBalancerFields are the fields of the Service spec only valid for the NodePort or LoadBalancer type,
+// which are removed from the headless Services.
+var loadBalancerFields = []string{
+	"loadBalancerIP",
+	"loadBalancerClass",
+	"loadBalancerSourceRanges",
+	"allocateLoadBalancerNodePorts",
+	"externalTrafficPolicy",
+	"healthCheckNodePort",
+}
+
+// ApplyServiceExtension customizes the generated Kubernetes Services in place. The annotations of the
+// metadata extension and then the service extension are merged into the annotations of each Service,
+// and the later one takes precedence. The type of each Service is replaced by the type of the service
+// extension if specified, and the Service is made headless if requested, with the name unchanged.
+func ApplyServiceExtension(resources v1.Resources, service *v1.KubeServiceExtension, metadata *v1.KubeMetadataExtension) error {
+	// merge the annotations of the extensions once rather than per Service
+	annotations := make(map[string]string)
+	if metadata != nil {
+		for k, v := range metadata.Annotations {
+			annotations[k] = v
+		}
+	}
+	var serviceType string
+	var headless bool
+	if service != nil {
+		for k, v := range service.Annotations {
+			annotations[k] = v
+		}
+		serviceType = service.Type
+		headless = service.Headless
+	}
+	if len(annotations) == 0 && serviceType == "" && !headless {
+		return nil
+	}
+	if _, ok := supportedServiceTypes[serviceType]; serviceType != "" && !ok {
+		return fmt.Errorf("unsupported service type %s, must be one of ClusterIP, NodePort and LoadBalancer", serviceType)
+	}
+	if headless {
+		if serviceType != "" && serviceType != "ClusterIP" {
+			return fmt.Errorf("headless service must be of type ClusterIP, got %s", serviceType)
+		}
+		serviceType = "ClusterIP"
+	}
+
+	for i := range resources {
+		if resources[i].Type != v1.Kubernetes {
+			continue
+		}
+		obj := &unstructured.Unstructured{Object: resources[i].Attributes}
+		if obj.GetKind() != "Service" {
+			continue
+		}
+
+		if len(annotations) != 0 {
+			mergeAnnotations(obj, annotations)
+		}
+
+		if serviceType != "" {
+			if err := setServiceType(obj, serviceType); err != nil {
+				return fmt.Errorf("failed to set type of service %s: %w", resources[i].ID, err)
+			}
+		}
+
+		if headless {
+			if err := setHeadless(obj); err != nil {
+				return fmt.Errorf("failed to make service %s headless: %w", resources[i].ID, err)
+			}
+		}
+	}
+
+	return nil
+}
+
+// mergeAnnotations merges the annotations into the annotations of the object. The annotations of the object
+// are updated in place if they are all strings, to avoid converting them to map[string]string and back.
+func mergeAnnotations(obj *unstructured.Unstructured, annotations map[string]string) {
+	if value, found, _ := unstructured.NestedFieldNoCopy(obj.Object, "metadata", "annotations"); found {
+		if objAnnotations, ok := value.(map[string]interface{}); ok && allStrings(objAnnotations) {
+			for k, v := range annotations {
+				objAnnotations[k] = v
+			}
+			return
+		}
+	}
+
+	// the annotations not in strings are dropped as GetAnnotations does
+	objAnnotations := obj.GetAnnotations()
+	if objAnnotations == nil {
+		objAnnotations = make(map[string]string, len(annotations))
+	}
+	for k, v := range annotations {
+		objAnnotations[k] = v
+	}
+	obj.SetAnnotations(objAnnotations)
+}
+
+func allStrings(m map[string]interface{}) bool {
+	for _, v := range m {
+		if _, ok := v.(string); !ok {
+			return false
+		}
+	}
+	return true
+}
+
+// setServiceType sets the type of the Service, and removes the node ports of a ClusterIP Service,
+// which are rejected by the API server.
+func setServiceType(obj *unstructured.Unstructured, serviceType string) error {
+	if err := unstructured.SetNestedField(obj.Object, serviceType, "spec", "type"); err != nil {
+		return err
+	}
+	if serviceType != "ClusterIP" {
+		return nil
+	}
+
+	value, found, err := unstructured.NestedFieldNoCopy(obj.Object, "spec", "ports")
+	if err != nil || !found {
+		return err
+	}
+	ports, ok := value.([]interface{})
+	if !ok {
+		return nil
+	}
+	for _, p := range ports {
+		if port, ok := p.(map[string]interface{}); ok {
+			delete(port, "nodePort")
+		}
+	}
+	return nil
+}
+
+// setHeadless sets the cluster IP of the ClusterIP Service to None, and removes the fields of the
+// cluster IPs and load balancers.
+func setHeadless(obj *unstructured.Unstructured) error {
+	unstructured.RemoveNestedField(obj.Object, "spec", "clusterIPs")
+	for _, field := range loadBalancerFields {
+		unstructured.RemoveNestedField(obj.Object, "spec", field)
+	}
+	return unstructured.SetNestedField(obj.Object, "None", "spec", "clusterIP")
+}
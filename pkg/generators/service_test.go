@@ -0,0 +1,150 @@
+package generators
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	v1 "kusionstack.io/kusion/pkg/apis/api.kusion.io/v1"
+)
+
+func newTestService(serviceType string, annotations map[string]interface{}) v1.Resource {
+	metadata := map[string]interface{}{
+		"name":      "db",
+		"namespace": "default",
+	}
+	if annotations != nil {
+		metadata["annotations"] = annotations
+	}
+	return v1.Resource{
+		ID:   "v1:Service:default:db",
+		Type: v1.Kubernetes,
+		Attributes: map[string]interface{}{
+			"apiVersion": "v1",
+			"kind":       "Service",
+			"metadata":   metadata,
+			"spec": map[string]interface{}{
+				"type": serviceType,
+				"ports": []interface{}{
+					map[string]interface{}{"port": int64(3306), "nodePort": int64(30306)},
+				},
+			},
+		},
+	}
+}
+
+func TestApplyServiceExtension(t *testing.T) {
+	testcases := []struct {
+		name                string
+		service             *v1.KubeServiceExtension
+		metadata            *v1.KubeMetadataExtension
+		expectedType        string
+		expectedAnnotations map[string]interface{}
+		expectedNodePort    bool
+		success             bool
+	}{
+		{
+			name:                "no extension",
+			expectedType:        "NodePort",
+			expectedAnnotations: map[string]interface{}{"generated": "true"},
+			expectedNodePort:    true,
+			success:             true,
+		},
+		{
+			name: "propagate annotations",
+			service: &v1.KubeServiceExtension{
+				Annotations: map[string]string{
+					"service.beta.kubernetes.io/alibaba-cloud-loadbalancer-address-type": "intranet",
+					"owner": "service",
+				},
+			},
+			metadata: &v1.KubeMetadataExtension{
+				Labels:      map[string]string{"team": "db"},
+				Annotations: map[string]string{"owner": "metadata", "team": "db"},
+			},
+			expectedType: "NodePort",
+			expectedAnnotations: map[string]interface{}{
+				"generated": "true",
+				"service.beta.kubernetes.io/alibaba-cloud-loadbalancer-address-type": "intranet",
+				"owner": "service",
+				"team":  "db",
+			},
+			expectedNodePort: true,
+			success:          true,
+		},
+		{
+			name:                "select load balancer type",
+			service:             &v1.KubeServiceExtension{Type: "LoadBalancer"},
+			expectedType:        "LoadBalancer",
+			expectedAnnotations: map[string]interface{}{"generated": "true"},
+			expectedNodePort:    true,
+			success:             true,
+		},
+		{
+			name:                "select cluster ip type",
+			service:             &v1.KubeServiceExtension{Type: "ClusterIP"},
+			expectedType:        "ClusterIP",
+			expectedAnnotations: map[string]interface{}{"generated": "true"},
+			expectedNodePort:    false,
+			success:             true,
+		},
+		{
+			name:    "unsupported type",
+			service: &v1.KubeServiceExtension{Type: "Ingress"},
+			success: false,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			resources := v1.Resources{
+				newTestService("NodePort", map[string]interface{}{"generated": "true"}),
+				{
+					ID:         "apps/v1:Deployment:default:db",
+					Type:       v1.Kubernetes,
+					Attributes: map[string]interface{}{"kind": "Deployment"},
+				},
+			}
+
+			err := ApplyServiceExtension(resources, tc.service, tc.metadata)
+			assert.Equal(t, tc.success, err == nil)
+			if !tc.success {
+				return
+			}
+
+			spec := resources[0].Attributes["spec"].(map[string]interface{})
+			assert.Equal(t, tc.expectedType, spec["type"])
+			assert.Equal(t, tc.expectedAnnotations, resources[0].Attributes["metadata"].(map[string]interface{})["annotations"])
+			_, found := spec["ports"].([]interface{})[0].(map[string]interface{})["nodePort"]
+			assert.Equal(t, tc.expectedNodePort, found)
+			assert.Equal(t, map[string]interface{}{"kind": "Deployment"}, resources[1].Attributes)
+		})
+	}
+}
+
+func TestApplyServiceExtension_WithoutAnnotations(t *testing.T) {
+	resources := v1.Resources{newTestService("ClusterIP", nil)}
+	err := ApplyServiceExtension(resources, nil, &v1.KubeMetadataExtension{Annotations: map[string]string{"team": "db"}})
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{"team": "db"}, resources[0].Attributes["metadata"].(map[string]interface{})["annotations"])
+}
+
+func TestApplyServiceExtension_Headless(t *testing.T) {
+	resources := v1.Resources{newTestService("LoadBalancer", nil)}
+	spec := resources[0].Attributes["spec"].(map[string]interface{})
+	spec["clusterIP"] = "10.0.0.1"
+	spec["loadBalancerIP"] = "47.0.0.1"
+	spec["externalTrafficPolicy"] = "Local"
+
+	err := ApplyServiceExtension(resources, &v1.KubeServiceExtension{Headless: true}, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, "None", spec["clusterIP"])
+	assert.Equal(t, "ClusterIP", spec["type"])
+	assert.NotContains(t, spec, "loadBalancerIP")
+	assert.NotContains(t, spec, "externalTrafficPolicy")
+	assert.NotContains(t, spec["ports"].([]interface{})[0], "nodePort")
+	assert.Equal(t, "db", resources[0].Attributes["metadata"].(map[string]interface{})["name"])
+
+	err = ApplyServiceExtension(resources, &v1.KubeServiceExtension{Headless: true, Type: "LoadBalancer"}, nil)
+	assert.Error(t, err)
+}
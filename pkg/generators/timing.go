@@ -0,0 +1,104 @@
+package generators
+
+import (
+	"sync"
+	"time"
+
+	v1 "kusionstack.io/kusion/pkg/apis/api.kusion.io/v1"
+)
+
+// Observer observes the time spent by each generator or module during the generation, e.g. to export
+// it as the metrics.
+type Observer interface {
+	ObserveGeneration(name string, duration time.Duration, err error)
+}
+
+// GenerationTiming is the time spent by a generator or module during the generation.
+type GenerationTiming struct {
+	// Name is the name of the generator, or the key of the module in the form of "repo@version".
+	Name string
+	// Duration is the time spent.
+	Duration time.Duration
+	// Failed reports whether the generator or module failed.
+	Failed bool
+}
+
+// TimingReport records the time spent by each generator and module during the generation in the order
+// of their completion, and passes each of them to the Observer if set. A nil TimingReport records
+// nothing, so that the generation has no overhead if the timings are not required.
+type TimingReport struct {
+	mu       sync.Mutex
+	observer Observer
+	timings  []GenerationTiming
+}
+
+// NewTimingReport returns an empty TimingReport, where the observer is optional.
+func NewTimingReport(observer Observer) *TimingReport {
+	return &TimingReport{observer: observer}
+}
+
+// Time calls f and records the time spent by it with the name.
+func (r *TimingReport) Time(name string, f func() error) error {
+	if r == nil {
+		return f()
+	}
+
+	start := time.Now()
+	err := f()
+	timing := GenerationTiming{Name: name, Duration: time.Since(start), Failed: err != nil}
+
+	r.mu.Lock()
+	r.timings = append(r.timings, timing)
+	r.mu.Unlock()
+	if r.observer != nil {
+		r.observer.ObserveGeneration(name, timing.Duration, err)
+	}
+	return err
+}
+
+// Timings returns a copy of the recorded timings.
+func (r *TimingReport) Timings() []GenerationTiming {
+	if r == nil {
+		return nil
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]GenerationTiming(nil), r.timings...)
+}
+
+// TimedSpecGenerator is a SpecGenerator which records the time spent by its own steps, e.g. the modules
+// it calls, to the TimingReport.
+type TimedSpecGenerator interface {
+	SpecGenerator
+	SetTimingReport(report *TimingReport)
+}
+
+// Timed wraps the NewSpecGeneratorFunc so that the Generate of the returned SpecGenerator is recorded
+// to the report with the name. The NewSpecGeneratorFunc is returned as is if the report is nil.
+func Timed(report *TimingReport, name string, newGenerator NewSpecGeneratorFunc) NewSpecGeneratorFunc {
+	if report == nil {
+		return newGenerator
+	}
+	return func() (SpecGenerator, error) {
+		g, err := newGenerator()
+		if err != nil {
+			return nil, err
+		}
+		if tg, ok := g.(TimedSpecGenerator); ok {
+			tg.SetTimingReport(report)
+		}
+		return &timedGenerator{report: report, name: name, generator: g}, nil
+	}
+}
+
+type timedGenerator struct {
+	report    *TimingReport
+	name      string
+	generator SpecGenerator
+}
+
+func (g *timedGenerator) Generate(spec *v1.Spec) error {
+	return g.report.Time(g.name, func() error {
+		return g.generator.Generate(spec)
+	})
+}
@@ -0,0 +1,91 @@
+package generators
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	v1 "kusionstack.io/kusion/pkg/apis/api.kusion.io/v1"
+)
+
+type mockObserver struct {
+	observed map[string]error
+}
+
+func (o *mockObserver) ObserveGeneration(name string, _ time.Duration, err error) {
+	o.observed[name] = err
+}
+
+type mockTimedGenerator struct {
+	mockGenerator
+	report *TimingReport
+}
+
+func (m *mockTimedGenerator) SetTimingReport(report *TimingReport) {
+	m.report = report
+}
+
+func sleepingGenerator(d time.Duration, err error) NewSpecGeneratorFunc {
+	return func() (SpecGenerator, error) {
+		return &mockGenerator{GenerateFunc: func(*v1.Spec) error {
+			time.Sleep(d)
+			return err
+		}}, nil
+	}
+}
+
+func TestTimed(t *testing.T) {
+	observer := &mockObserver{observed: map[string]error{}}
+	report := NewTimingReport(observer)
+
+	err := CallGenerators(
+		&v1.Spec{},
+		Timed(report, "foo", sleepingGenerator(10*time.Millisecond, nil)),
+		Timed(report, "bar", sleepingGenerator(20*time.Millisecond, nil)),
+	)
+	assert.NoError(t, err)
+	err = CallGenerators(&v1.Spec{}, Timed(report, "baz", sleepingGenerator(0, assert.AnError)))
+	assert.ErrorIs(t, err, assert.AnError)
+
+	timings := report.Timings()
+	assert.Len(t, timings, 3)
+	assert.Equal(t, "foo", timings[0].Name)
+	assert.GreaterOrEqual(t, timings[0].Duration, 10*time.Millisecond)
+	assert.False(t, timings[0].Failed)
+	assert.Equal(t, "bar", timings[1].Name)
+	assert.GreaterOrEqual(t, timings[1].Duration, 20*time.Millisecond)
+	assert.False(t, timings[1].Failed)
+	assert.Equal(t, "baz", timings[2].Name)
+	assert.True(t, timings[2].Failed)
+	assert.Equal(t, map[string]error{"foo": nil, "bar": nil, "baz": assert.AnError}, observer.observed)
+}
+
+func TestTimedSetsTimingReport(t *testing.T) {
+	report := NewTimingReport(nil)
+	g := &mockTimedGenerator{mockGenerator: mockGenerator{GenerateFunc: func(*v1.Spec) error {
+		return nil
+	}}}
+	gf := func() (SpecGenerator, error) { return g, nil }
+
+	assert.NoError(t, CallGenerators(&v1.Spec{}, Timed(report, "foo", gf)))
+	assert.Same(t, report, g.report)
+	assert.Len(t, report.Timings(), 1)
+}
+
+func TestNilTimingReport(t *testing.T) {
+	var report *TimingReport
+	called := false
+	err := report.Time("foo", func() error {
+		called = true
+		return nil
+	})
+	assert.NoError(t, err)
+	assert.True(t, called)
+	assert.Nil(t, report.Timings())
+
+	gf := sleepingGenerator(0, nil)
+	g, err := Timed(report, "foo", gf)()
+	assert.NoError(t, err)
+	assert.IsType(t, &mockGenerator{}, g)
+}
@@ -38,6 +38,17 @@ func CallGenerators(i *v1.Spec, newGenerators ...NewSpecGeneratorFunc) error {
 	return nil
 }
 
+// NormalizeSpec is the final step of generating the Spec, which sorts the Spec resources by ID stably,
+// so that the generated Spec is identical no matter in which order the generators emit the resources.
+// The slice order of the resources is not semantically meaningful, and the apply order is derived from
+// the DependsOn of the resources.
+func NormalizeSpec(i *v1.Spec) {
+	if i == nil {
+		return
+	}
+	sort.Stable(i.Resources)
+}
+
 // ForeachOrdered executes the given function on each
 // item in the map in order of their keys.
 func ForeachOrdered[T any](m map[string]T, f func(key string, value T) error) error {
@@ -65,7 +76,7 @@ func AppendToSpec(resourceType v1.Type, resourceID string, i *v1.Spec, resource
 		return errors.New("AppendToSpec is only used for Kubernetes resources")
 	}
 
-	gvk := resource.(runtime.Object).GetObjectKind().GroupVersionKind().String()
+	gvk := resource.(runtime.Object).GetObjectKind().GroupVersionKind()
 	// fixme: this function converts int to int64 by default
 	unstructured, err := runtime.DefaultUnstructuredConverter.ToUnstructured(resource)
 	if err != nil {
@@ -76,10 +87,8 @@ func AppendToSpec(resourceType v1.Type, resourceID string, i *v1.Spec, resource
 		Type:       resourceType,
 		Attributes: unstructured,
 		DependsOn:  nil,
-		Extensions: map[string]any{
-			v1.ResourceExtensionGVK: gvk,
-		},
 	}
+	r.SetGVK(gvk)
 	i.Resources = append(i.Resources, r)
 	return nil
 }
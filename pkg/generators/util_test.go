@@ -4,6 +4,7 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"gopkg.in/yaml.v3"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
@@ -76,6 +77,48 @@ func TestForeachOrdered(t *testing.T) {
 	assert.Equal(t, "abc", result)
 }
 
+func TestNormalizeSpec(t *testing.T) {
+	namespaces := map[string]*corev1.Namespace{}
+	for _, name := range []string{"foo", "bar", "baz", "qux"} {
+		namespaces[name] = &corev1.Namespace{
+			TypeMeta:   metav1.TypeMeta{Kind: "Namespace", APIVersion: "v1"},
+			ObjectMeta: metav1.ObjectMeta{Name: name},
+		}
+	}
+	// the generator emits the resources in the random order of the map iteration
+	newGenerator := func() (SpecGenerator, error) {
+		return &mockGenerator{
+			GenerateFunc: func(i *v1.Spec) error {
+				for name, ns := range namespaces {
+					if err := AppendToSpec(v1.Kubernetes, "v1:Namespace:"+name, i, ns); err != nil {
+						return err
+					}
+				}
+				return nil
+			},
+		}, nil
+	}
+
+	var generated [][]byte
+	for n := 0; n < 5; n++ {
+		i := &v1.Spec{}
+		assert.NoError(t, CallGenerators(i, newGenerator))
+		NormalizeSpec(i)
+		data, err := yaml.Marshal(i)
+		assert.NoError(t, err)
+		generated = append(generated, data)
+	}
+	for _, data := range generated[1:] {
+		assert.Equal(t, string(generated[0]), string(data))
+	}
+
+	i := &v1.Spec{}
+	assert.NoError(t, CallGenerators(i, newGenerator))
+	NormalizeSpec(i)
+	assert.Equal(t, "v1:Namespace:bar", i.Resources[0].ID)
+	assert.Equal(t, "v1:Namespace:qux", i.Resources[3].ID)
+}
+
 func TestAppendToSpec(t *testing.T) {
 	i := &v1.Spec{}
 	resource := &v1.Resource{
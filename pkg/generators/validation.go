@@ -0,0 +1,143 @@
+package generators
+
+import (
+	"fmt"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/util/validation"
+
+	v1 "kusionstack.io/kusion/pkg/apis/api.kusion.io/v1"
+	"kusionstack.io/kusion/pkg/util/kerrors"
+)
+
+var (
+	ErrServiceSelectorMismatch = kerrors.New(kerrors.ErrValidation, "service selector matches no generated workload")
+	ErrInvalidNamespaceName    = kerrors.New(kerrors.ErrValidation, "invalid namespace name")
+)
+
+// ValidateNamespaceName validates the namespace name conforms to the DNS-1123 label standard, i.e. at
+// most 63 lowercase alphanumeric characters or '-', starting and ending with an alphanumeric character,
+// so that an invalid namespace fails at generation rather than at apply.
+func ValidateNamespaceName(name string) error {
+	if errs := validation.IsDNS1123Label(name); len(errs) != 0 {
+		return fmt.Errorf("%w %q: %s", ErrInvalidNamespaceName, name, strings.Join(errs, "; "))
+	}
+	return nil
+}
+
+// NormalizeNamespaceName converts the namespace derived from the names of the project, stack or
+// workspace, which allow the characters out of the DNS-1123 label standard, into a valid one by
+// lowercasing it, replacing the illegal characters with '-', trimming the leading and trailing '-',
+// and truncating it to MaxNameLength. The explicitly specified namespaces are not normalized but
+// validated by ValidateNamespaceName.
+func NormalizeNamespaceName(name string) string {
+	var b strings.Builder
+	for _, c := range strings.ToLower(name) {
+		if (c >= 'a' && c <= 'z') || (c >= '0' && c <= '9') {
+			b.WriteRune(c)
+		} else {
+			b.WriteByte('-')
+		}
+	}
+	namespace := strings.Trim(b.String(), "-")
+	if len(namespace) > MaxNameLength {
+		namespace = strings.TrimRight(namespace[:MaxNameLength], "-")
+	}
+	return namespace
+}
+
+// podLabelsPaths are the paths of the pod labels in the workloads with a pod template, i.e. the Deployment,
+// StatefulSet, DaemonSet, Job and so on, and the CronJob.
+var podLabelsPaths = [][]string{
+	{"spec", "template", "metadata", "labels"},
+	{"spec", "jobTemplate", "spec", "template", "metadata", "labels"},
+}
+
+// ValidateServiceSelectors validates that the selector of each generated Kubernetes Service matches
+// the pod labels of at least one generated Pod or workload in the same namespace, which catches the broken
+// wiring between Services and workloads caused by user overrides. Services without selector are skipped.
+// The resources must be all the generated ones, for a Service may select the pods of another app.
+func ValidateServiceSelectors(resources v1.Resources) error {
+	var services []*unstructured.Unstructured
+	var workloads []*selectableWorkload
+	for i := range resources {
+		if resources[i].Type != v1.Kubernetes {
+			continue
+		}
+		obj := &unstructured.Unstructured{Object: resources[i].Attributes}
+		if obj.GetKind() == "Service" {
+			services = append(services, obj)
+			continue
+		}
+		if obj.GetKind() == "Pod" {
+			workloads = append(workloads, &selectableWorkload{obj: obj, namespace: obj.GetNamespace(), path: []string{"metadata", "labels"}})
+			continue
+		}
+		for _, path := range podLabelsPaths {
+			if value, found, _ := unstructured.NestedFieldNoCopy(obj.Object, path...); found {
+				if _, ok := value.(map[string]interface{}); ok {
+					workloads = append(workloads, &selectableWorkload{obj: obj, namespace: obj.GetNamespace(), path: path})
+					break
+				}
+			}
+		}
+	}
+
+	for _, svc := range services {
+		selector, found, err := unstructured.NestedStringMap(svc.Object, "spec", "selector")
+		if err != nil {
+			return fmt.Errorf("failed to get selector of service %s: %w", svc.GetName(), err)
+		}
+		if !found || len(selector) == 0 {
+			continue
+		}
+
+		namespace := svc.GetNamespace()
+		labelSelector := labels.SelectorFromSet(selector)
+		matched := false
+		for _, wl := range workloads {
+			if wl.namespace != namespace {
+				continue
+			}
+			podLabels, err := wl.podLabels()
+			if err != nil {
+				return err
+			}
+			if labelSelector.Matches(podLabels) {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			return fmt.Errorf("%w, service: %s, namespace: %s, selector: %v",
+				ErrServiceSelectorMismatch, svc.GetName(), namespace, selector)
+		}
+	}
+
+	return nil
+}
+
+// selectableWorkload is a Pod or a workload with a pod template, whose pod labels at the path are read
+// at most once when matched against the Service selectors.
+type selectableWorkload struct {
+	obj       *unstructured.Unstructured
+	namespace string
+	path      []string
+	labels    labels.Set
+	err       error
+	read      bool
+}
+
+func (w *selectableWorkload) podLabels() (labels.Set, error) {
+	if !w.read {
+		w.read = true
+		podLabels, _, err := unstructured.NestedStringMap(w.obj.Object, w.path...)
+		if err != nil {
+			w.err = fmt.Errorf("failed to get pod labels of workload %s: %w", w.obj.GetName(), err)
+		}
+		w.labels = podLabels
+	}
+	return w.labels, w.err
+}
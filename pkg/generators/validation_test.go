@@ -0,0 +1,222 @@
+package generators
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	v1 "kusionstack.io/kusion/pkg/apis/api.kusion.io/v1"
+)
+
+func buildDeployment(namespace string, podLabels map[string]any) v1.Resource {
+	return v1.Resource{
+		ID:   "apps/v1:Deployment:" + namespace + ":foo",
+		Type: v1.Kubernetes,
+		Attributes: map[string]any{
+			"apiVersion": "apps/v1",
+			"kind":       "Deployment",
+			"metadata": map[string]any{
+				"name":      "foo",
+				"namespace": namespace,
+			},
+			"spec": map[string]any{
+				"template": map[string]any{
+					"metadata": map[string]any{
+						"labels": podLabels,
+					},
+				},
+			},
+		},
+	}
+}
+
+func buildPod(namespace string, podLabels map[string]any) v1.Resource {
+	return v1.Resource{
+		ID:   "v1:Pod:" + namespace + ":foo",
+		Type: v1.Kubernetes,
+		Attributes: map[string]any{
+			"apiVersion": "v1",
+			"kind":       "Pod",
+			"metadata": map[string]any{
+				"name":      "foo",
+				"namespace": namespace,
+				"labels":    podLabels,
+			},
+		},
+	}
+}
+
+func buildCronJob(namespace string, podLabels map[string]any) v1.Resource {
+	return v1.Resource{
+		ID:   "batch/v1:CronJob:" + namespace + ":foo",
+		Type: v1.Kubernetes,
+		Attributes: map[string]any{
+			"apiVersion": "batch/v1",
+			"kind":       "CronJob",
+			"metadata": map[string]any{
+				"name":      "foo",
+				"namespace": namespace,
+			},
+			"spec": map[string]any{
+				"jobTemplate": map[string]any{
+					"spec": map[string]any{
+						"template": map[string]any{
+							"metadata": map[string]any{
+								"labels": podLabels,
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func buildService(namespace string, selector map[string]any) v1.Resource {
+	spec := map[string]any{}
+	if selector != nil {
+		spec["selector"] = selector
+	}
+	return v1.Resource{
+		ID:   "v1:Service:" + namespace + ":foo-private",
+		Type: v1.Kubernetes,
+		Attributes: map[string]any{
+			"apiVersion": "v1",
+			"kind":       "Service",
+			"metadata": map[string]any{
+				"name":      "foo-private",
+				"namespace": namespace,
+			},
+			"spec": spec,
+		},
+	}
+}
+
+func TestValidateServiceSelectors(t *testing.T) {
+	podLabels := map[string]any{
+		"app.kubernetes.io/name":    "foo",
+		"app.kubernetes.io/part-of": "helloworld",
+	}
+
+	testcases := []struct {
+		name      string
+		resources v1.Resources
+		success   bool
+	}{
+		{
+			name: "matched selector",
+			resources: v1.Resources{
+				buildDeployment("helloworld", podLabels),
+				buildService("helloworld", map[string]any{"app.kubernetes.io/name": "foo"}),
+			},
+			success: true,
+		},
+		{
+			name: "selector matching bare pod",
+			resources: v1.Resources{
+				buildService("helloworld", map[string]any{"app.kubernetes.io/name": "foo"}),
+				buildPod("helloworld", podLabels),
+			},
+			success: true,
+		},
+		{
+			name: "selector matching cronjob pod template",
+			resources: v1.Resources{
+				buildService("helloworld", map[string]any{"app.kubernetes.io/name": "foo"}),
+				buildCronJob("helloworld", podLabels),
+			},
+			success: true,
+		},
+		{
+			name: "service without selector",
+			resources: v1.Resources{
+				buildService("helloworld", nil),
+			},
+			success: true,
+		},
+		{
+			name: "mismatched selector",
+			resources: v1.Resources{
+				buildDeployment("helloworld", podLabels),
+				buildService("helloworld", map[string]any{"app.kubernetes.io/name": "bar"}),
+			},
+			success: false,
+		},
+		{
+			name: "workload in another namespace",
+			resources: v1.Resources{
+				buildDeployment("other", podLabels),
+				buildService("helloworld", map[string]any{"app.kubernetes.io/name": "foo"}),
+			},
+			success: false,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := ValidateServiceSelectors(tc.resources)
+			assert.Equal(t, tc.success, err == nil)
+			if !tc.success {
+				assert.ErrorIs(t, err, ErrServiceSelectorMismatch)
+			}
+		})
+	}
+}
+
+func TestValidateNamespaceName(t *testing.T) {
+	testcases := []struct {
+		name      string
+		namespace string
+		success   bool
+	}{
+		{name: "valid name", namespace: "helloworld", success: true},
+		{name: "valid name with hyphen and digits", namespace: "team-1-dev", success: true},
+		{name: "valid name of max length", namespace: strings.Repeat("a", 63), success: true},
+		{name: "empty name", namespace: "", success: false},
+		{name: "uppercase", namespace: "HelloWorld", success: false},
+		{name: "underscore", namespace: "hello_world", success: false},
+		{name: "dot", namespace: "hello.world", success: false},
+		{name: "leading hyphen", namespace: "-hello", success: false},
+		{name: "trailing hyphen", namespace: "hello-", success: false},
+		{name: "too long", namespace: strings.Repeat("a", 64), success: false},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := ValidateNamespaceName(tc.namespace)
+			if tc.success {
+				assert.NoError(t, err)
+				return
+			}
+			assert.ErrorIs(t, err, ErrInvalidNamespaceName)
+			assert.ErrorContains(t, err, fmt.Sprintf("%q", tc.namespace))
+		})
+	}
+}
+
+func TestNormalizeNamespaceName(t *testing.T) {
+	testcases := []struct {
+		name      string
+		input     string
+		namespace string
+	}{
+		{name: "valid name", input: "helloworld", namespace: "helloworld"},
+		{name: "uppercase", input: "HelloWorld", namespace: "helloworld"},
+		{name: "underscore and dot", input: "hello_world.v2", namespace: "hello-world-v2"},
+		{name: "leading and trailing illegal characters", input: "_hello.", namespace: "hello"},
+		{name: "too long", input: strings.Repeat("a", 62) + "_b", namespace: strings.Repeat("a", 62)},
+		{name: "no alphanumeric characters", input: "__", namespace: ""},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			namespace := NormalizeNamespaceName(tc.input)
+			assert.Equal(t, tc.namespace, namespace)
+			if namespace != "" {
+				assert.NoError(t, ValidateNamespaceName(namespace))
+			}
+		})
+	}
+}
@@ -0,0 +1,108 @@
+package project
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	v1 "kusionstack.io/kusion/pkg/apis/api.kusion.io/v1"
+)
+
+// DiscoverProjects walks the directory tree of the given repo root and returns all the projects with
+// their stacks attached. A stack belongs to the closest project above it, so the stacks of a nested
+// project are not attached to the outer one. Directories without project.yaml or stack.yaml are
+// walked through but not returned, and hidden directories are skipped. Symbolic links to directories
+// are followed, and each real directory is visited at most once to guard against symlink loops.
+func DiscoverProjects(root string) ([]*v1.Project, error) {
+	absRoot, err := filepath.Abs(root)
+	if err != nil {
+		return nil, err
+	}
+	f, err := os.Stat(absRoot)
+	if err != nil {
+		return nil, err
+	}
+	if !f.IsDir() {
+		return nil, fmt.Errorf("%s is not a directory", root)
+	}
+
+	d := &discoverer{visited: make(map[string]bool)}
+	if err = d.walk(absRoot, nil); err != nil {
+		return nil, err
+	}
+	return d.projects, nil
+}
+
+type discoverer struct {
+	// visited records the real paths of the walked directories.
+	visited  map[string]bool
+	projects []*v1.Project
+}
+
+// walk discovers the projects and stacks under dir, where current is the closest project above dir.
+func (d *discoverer) walk(dir string, current *v1.Project) error {
+	realPath, err := filepath.EvalSymlinks(dir)
+	if err != nil {
+		return err
+	}
+	if d.visited[realPath] {
+		return nil
+	}
+	d.visited[realPath] = true
+
+	if isProject(dir) {
+		project, err := parseProjectYamlFile(dir)
+		if err != nil {
+			return fmt.Errorf("parse project.yaml in %s failed. %w", dir, err)
+		}
+		project.Path = resolveDefinitionPath(dir, project.Path)
+		project.Stacks = nil
+		d.projects = append(d.projects, project)
+		current = project
+	}
+	if current != nil && IsStack(dir) {
+		stack, err := parseStackYamlFile(dir)
+		if err != nil {
+			return fmt.Errorf("parse stack.yaml in %s failed. %w", dir, err)
+		}
+		stack.Path = resolveDefinitionPath(dir, stack.Path)
+		current.Stacks = append(current.Stacks, stack)
+	}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return err
+	}
+	for _, entry := range entries {
+		if strings.HasPrefix(entry.Name(), ".") {
+			continue
+		}
+		p := filepath.Join(dir, entry.Name())
+		if entry.Type()&os.ModeSymlink != 0 {
+			// follow the symbolic link only if it points to a directory.
+			f, err := os.Stat(p)
+			if err != nil || !f.IsDir() {
+				continue
+			}
+		} else if !entry.IsDir() {
+			continue
+		}
+		if err = d.walk(p, current); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// resolveDefinitionPath returns the path declared in the definition file in the dir. An empty path
+// defaults to the dir, and a relative path is relative to the dir.
+func resolveDefinitionPath(dir, path string) string {
+	if path == "" {
+		return dir
+	}
+	if filepath.IsAbs(path) {
+		return filepath.Clean(path)
+	}
+	return filepath.Join(dir, path)
+}
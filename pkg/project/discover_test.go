@@ -0,0 +1,85 @@
+package project
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	v1 "kusionstack.io/kusion/pkg/apis/api.kusion.io/v1"
+)
+
+func TestDiscoverProjects(t *testing.T) {
+	root := filepath.Join(TestCurrentDir, "testdata", "discover")
+	wordpress := filepath.Join(root, "apps", "wordpress")
+	mysql := filepath.Join(wordpress, "components", "mysql")
+
+	testcases := []struct {
+		name     string
+		root     string
+		expected []*v1.Project
+		success  bool
+	}{
+		{
+			name: "discover nested projects from repo root",
+			root: "./testdata/discover",
+			expected: []*v1.Project{
+				{
+					Name: "wordpress",
+					Path: wordpress,
+					Stacks: []*v1.Stack{
+						{Name: "dev", Path: filepath.Join(wordpress, "dev")},
+						{Name: "prod", Path: filepath.Join(wordpress, "prod")},
+					},
+				},
+				{
+					Name: "mysql",
+					Path: mysql,
+					Stacks: []*v1.Stack{
+						{Name: "dev", Path: filepath.Join(mysql, "dev")},
+					},
+				},
+			},
+			success: true,
+		},
+		{
+			name:     "discover no project from non-project directory",
+			root:     "./testdata/discover/docs",
+			expected: nil,
+			success:  true,
+		},
+		{
+			name:    "discover from not existed directory",
+			root:    "./testdata/not-exist",
+			success: false,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			projects, err := DiscoverProjects(tc.root)
+			assert.Equal(t, tc.success, err == nil)
+			assert.Equal(t, tc.expected, projects)
+		})
+	}
+}
+
+func TestDiscoverProjectsWithSymlinkLoop(t *testing.T) {
+	root := t.TempDir()
+	projectDir := filepath.Join(root, "app")
+	stackDir := filepath.Join(projectDir, "dev")
+	require.NoError(t, os.MkdirAll(stackDir, 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(projectDir, ProjectFile), []byte("name: app\n"), 0o644))
+	require.NoError(t, os.WriteFile(filepath.Join(stackDir, StackFile), []byte("name: dev\n"), 0o644))
+	// the symbolic links point back to the root and the project, which form loops.
+	require.NoError(t, os.Symlink(root, filepath.Join(stackDir, "loop")))
+	require.NoError(t, os.Symlink(projectDir, filepath.Join(root, "app-link")))
+
+	projects, err := DiscoverProjects(root)
+	require.NoError(t, err)
+	require.Len(t, projects, 1)
+	assert.Equal(t, "app", projects[0].Name)
+	assert.Equal(t, []*v1.Stack{{Name: "dev", Path: stackDir}}, projects[0].Stacks)
+}
@@ -0,0 +1,60 @@
+package project
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	v1 "kusionstack.io/kusion/pkg/apis/api.kusion.io/v1"
+)
+
+var (
+	ErrEmptyRepoRoot       = errors.New("empty repo root")
+	ErrPathEscapeRepo      = errors.New("path escapes the repo root")
+	ErrEmptyProjectOrStack = errors.New("empty project or stack")
+)
+
+// ResolvePath resolves the path of the project, which is relative to the Git repo root, to an absolute
+// filesystem path under the repo root.
+func ResolvePath(repoRoot string, p *v1.Project) (string, error) {
+	if p == nil {
+		return "", ErrEmptyProjectOrStack
+	}
+	return ResolveRepoPath(repoRoot, p.Path)
+}
+
+// ResolveStackPath resolves the path of the stack, which is relative to the Git repo root, to an absolute
+// filesystem path under the repo root.
+func ResolveStackPath(repoRoot string, s *v1.Stack) (string, error) {
+	if s == nil {
+		return "", ErrEmptyProjectOrStack
+	}
+	return ResolveRepoPath(repoRoot, s.Path)
+}
+
+// ResolveRepoPath clean-joins the path relative to the Git repo root with the repo root, and returns the
+// absolute path. An error is returned if the result is outside the repo root, e.g. "../other". The
+// absolute path is returned unchanged, which is already resolved, e.g. the one of DiscoverProjects.
+func ResolveRepoPath(repoRoot, path string) (string, error) {
+	if repoRoot == "" {
+		return "", ErrEmptyRepoRoot
+	}
+	if filepath.IsAbs(path) {
+		return path, nil
+	}
+	root, err := filepath.Abs(repoRoot)
+	if err != nil {
+		return "", err
+	}
+
+	resolved := filepath.Join(root, path)
+	rel, err := filepath.Rel(root, resolved)
+	if err != nil {
+		return "", err
+	}
+	if rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return "", fmt.Errorf("%w: %s", ErrPathEscapeRepo, path)
+	}
+	return resolved, nil
+}
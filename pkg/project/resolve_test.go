@@ -0,0 +1,120 @@
+package project
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	v1 "kusionstack.io/kusion/pkg/apis/api.kusion.io/v1"
+)
+
+func TestResolvePath(t *testing.T) {
+	repoRoot := filepath.Join(TestCurrentDir, "testdata")
+
+	testcases := []struct {
+		name         string
+		repoRoot     string
+		project      *v1.Project
+		expectedPath string
+		expectedErr  error
+	}{
+		{
+			name:         "resolve project path",
+			repoRoot:     repoRoot,
+			project:      &v1.Project{Name: TestProjectA, Path: "appops/http-echo"},
+			expectedPath: filepath.Join(repoRoot, "appops", "http-echo"),
+		},
+		{
+			name:         "resolve unclean project path",
+			repoRoot:     repoRoot + "/",
+			project:      &v1.Project{Name: TestProjectA, Path: "./appops//nginx-example/../http-echo/"},
+			expectedPath: filepath.Join(repoRoot, "appops", "http-echo"),
+		},
+		{
+			name:         "resolve empty project path to repo root",
+			repoRoot:     repoRoot,
+			project:      &v1.Project{Name: TestProjectA},
+			expectedPath: repoRoot,
+		},
+		{
+			name:         "resolve relative repo root",
+			repoRoot:     "testdata",
+			project:      &v1.Project{Name: TestProjectA, Path: "appops/http-echo"},
+			expectedPath: filepath.Join(repoRoot, "appops", "http-echo"),
+		},
+		{
+			name:         "resolve absolute project path unchanged",
+			repoRoot:     repoRoot,
+			project:      &v1.Project{Name: TestProjectA, Path: filepath.Join(repoRoot, "appops", "http-echo")},
+			expectedPath: filepath.Join(repoRoot, "appops", "http-echo"),
+		},
+		{
+			name:        "path traversal out of repo root",
+			repoRoot:    repoRoot,
+			project:     &v1.Project{Name: TestProjectA, Path: "appops/../../paths.go"},
+			expectedErr: ErrPathEscapeRepo,
+		},
+		{
+			name:        "path traversal to repo root parent",
+			repoRoot:    repoRoot,
+			project:     &v1.Project{Name: TestProjectA, Path: ".."},
+			expectedErr: ErrPathEscapeRepo,
+		},
+		{
+			name:        "empty repo root",
+			project:     &v1.Project{Name: TestProjectA, Path: "appops/http-echo"},
+			expectedErr: ErrEmptyRepoRoot,
+		},
+		{
+			name:        "nil project",
+			repoRoot:    repoRoot,
+			expectedErr: ErrEmptyProjectOrStack,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			path, err := ResolvePath(tc.repoRoot, tc.project)
+			if tc.expectedErr != nil {
+				assert.ErrorIs(t, err, tc.expectedErr)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expectedPath, path)
+		})
+	}
+}
+
+func TestResolveStackPath(t *testing.T) {
+	repoRoot := filepath.Join(TestCurrentDir, "testdata")
+
+	path, err := ResolveStackPath(repoRoot, &v1.Stack{Name: TestStackA, Path: "appops/http-echo/dev"})
+	assert.NoError(t, err)
+	assert.Equal(t, filepath.Join(repoRoot, "appops", "http-echo", "dev"), path)
+
+	_, err = ResolveStackPath(repoRoot, &v1.Stack{Name: TestStackA, Path: "../../dev"})
+	assert.ErrorIs(t, err, ErrPathEscapeRepo)
+
+	// a sibling directory sharing the prefix of the repo root is outside the repo root.
+	_, err = ResolveStackPath(repoRoot, &v1.Stack{Name: TestStackA, Path: "../testdata-other/dev"})
+	assert.ErrorIs(t, err, ErrPathEscapeRepo)
+}
+
+func TestResolveDiscoveredProjects(t *testing.T) {
+	repoRoot := filepath.Join(TestCurrentDir, "testdata", "discover")
+	projects, err := DiscoverProjects(repoRoot)
+	assert.NoError(t, err)
+	assert.NotEmpty(t, projects)
+
+	for _, p := range projects {
+		path, err := ResolvePath(repoRoot, p)
+		assert.NoError(t, err)
+		assert.Equal(t, p.Path, path)
+		for _, s := range p.Stacks {
+			path, err = ResolveStackPath(repoRoot, s)
+			assert.NoError(t, err)
+			assert.Equal(t, s.Path, path)
+		}
+	}
+}
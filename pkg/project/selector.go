@@ -0,0 +1,96 @@
+package project
+
+import (
+	"fmt"
+	"path"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/labels"
+
+	v1 "kusionstack.io/kusion/pkg/apis/api.kusion.io/v1"
+)
+
+// ProjectStack is a stack selected by the StackSelector, along with the project it belongs to.
+type ProjectStack struct {
+	Project *v1.Project
+	Stack   *v1.Stack
+}
+
+// StackSelector selects the stacks across projects by labels and name globs.
+type StackSelector struct {
+	labelSelector labels.Selector
+	namePatterns  []string
+}
+
+// NewStackSelector returns a StackSelector with the label selector and name globs. The label selector
+// uses the Kubernetes label selector syntax, which supports equality-based requirements such as
+// "env=prod" and set-based requirements such as "env in (prod,staging)", "tier notin (db)" and
+// "critical". A name glob containing "/" is matched against "<project>/<stack>", otherwise it is
+// matched against the stack name. A stack is selected if it matches the label selector and any of
+// the name globs, where an empty label selector or empty name globs match all the stacks.
+func NewStackSelector(labelSelector string, namePatterns ...string) (*StackSelector, error) {
+	selector, err := labels.Parse(labelSelector)
+	if err != nil {
+		return nil, fmt.Errorf("invalid label selector %q: %w", labelSelector, err)
+	}
+	for _, pattern := range namePatterns {
+		if _, err = path.Match(pattern, ""); err != nil {
+			return nil, fmt.Errorf("invalid name pattern %q: %w", pattern, err)
+		}
+	}
+	return &StackSelector{
+		labelSelector: selector,
+		namePatterns:  namePatterns,
+	}, nil
+}
+
+// Select returns the matching stacks of the projects, in the order of the projects and their stacks.
+// The labels of a stack are matched along with the labels of its project, and the stack labels take
+// precedence when the keys conflict.
+func (s *StackSelector) Select(projects []*v1.Project) []ProjectStack {
+	var selected []ProjectStack
+	for _, project := range projects {
+		if project == nil {
+			continue
+		}
+		for _, stack := range project.Stacks {
+			if stack == nil {
+				continue
+			}
+			if s.matchLabels(project, stack) && s.matchName(project, stack) {
+				selected = append(selected, ProjectStack{Project: project, Stack: stack})
+			}
+		}
+	}
+	return selected
+}
+
+func (s *StackSelector) matchLabels(project *v1.Project, stack *v1.Stack) bool {
+	if s.labelSelector.Empty() {
+		return true
+	}
+	set := make(labels.Set, len(project.Labels)+len(stack.Labels))
+	for k, v := range project.Labels {
+		set[k] = v
+	}
+	for k, v := range stack.Labels {
+		set[k] = v
+	}
+	return s.labelSelector.Matches(set)
+}
+
+func (s *StackSelector) matchName(project *v1.Project, stack *v1.Stack) bool {
+	if len(s.namePatterns) == 0 {
+		return true
+	}
+	for _, pattern := range s.namePatterns {
+		name := stack.Name
+		if strings.Contains(pattern, "/") {
+			name = project.Name + "/" + stack.Name
+		}
+		if matched, _ := path.Match(pattern, name); matched {
+			return true
+		}
+	}
+	return false
+}
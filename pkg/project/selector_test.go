@@ -0,0 +1,152 @@
+package project
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	v1 "kusionstack.io/kusion/pkg/apis/api.kusion.io/v1"
+)
+
+func mockSelectorProjects() []*v1.Project {
+	return []*v1.Project{
+		{
+			Name:   "wordpress",
+			Labels: map[string]string{"team": "web"},
+			Stacks: []*v1.Stack{
+				{Name: "dev", Labels: map[string]string{"env": "dev"}},
+				{Name: "prod", Labels: map[string]string{"env": "prod", "critical": "true"}},
+			},
+		},
+		{
+			Name:   "mysql",
+			Labels: map[string]string{"team": "db"},
+			Stacks: []*v1.Stack{
+				{Name: "staging", Labels: map[string]string{"env": "staging"}},
+				{Name: "prod", Labels: map[string]string{"env": "prod"}},
+				{Name: "prod-backup", Labels: map[string]string{"env": "prod", "team": "ops"}},
+			},
+		},
+	}
+}
+
+func TestStackSelector_Select(t *testing.T) {
+	testcases := []struct {
+		name          string
+		labelSelector string
+		namePatterns  []string
+		expected      []string
+	}{
+		{
+			name:     "select all stacks",
+			expected: []string{"wordpress/dev", "wordpress/prod", "mysql/staging", "mysql/prod", "mysql/prod-backup"},
+		},
+		{
+			name:          "label equality",
+			labelSelector: "env=prod",
+			expected:      []string{"wordpress/prod", "mysql/prod", "mysql/prod-backup"},
+		},
+		{
+			name:          "label equality with project labels",
+			labelSelector: "env=prod,team=db",
+			expected:      []string{"mysql/prod"},
+		},
+		{
+			name:          "label inequality",
+			labelSelector: "env!=prod",
+			expected:      []string{"wordpress/dev", "mysql/staging"},
+		},
+		{
+			name:          "set-based in",
+			labelSelector: "env in (staging,dev)",
+			expected:      []string{"wordpress/dev", "mysql/staging"},
+		},
+		{
+			name:          "set-based notin",
+			labelSelector: "env=prod,team notin (web)",
+			expected:      []string{"mysql/prod", "mysql/prod-backup"},
+		},
+		{
+			name:          "set-based exists",
+			labelSelector: "critical",
+			expected:      []string{"wordpress/prod"},
+		},
+		{
+			name:          "set-based not exists",
+			labelSelector: "!critical,env=prod",
+			expected:      []string{"mysql/prod", "mysql/prod-backup"},
+		},
+		{
+			name:         "stack name glob",
+			namePatterns: []string{"prod*"},
+			expected:     []string{"wordpress/prod", "mysql/prod", "mysql/prod-backup"},
+		},
+		{
+			name:         "project and stack name glob",
+			namePatterns: []string{"mysql/*"},
+			expected:     []string{"mysql/staging", "mysql/prod", "mysql/prod-backup"},
+		},
+		{
+			name:         "multiple name globs",
+			namePatterns: []string{"dev", "*/staging"},
+			expected:     []string{"wordpress/dev", "mysql/staging"},
+		},
+		{
+			name:          "label selector and name glob",
+			labelSelector: "env=prod",
+			namePatterns:  []string{"*-backup"},
+			expected:      []string{"mysql/prod-backup"},
+		},
+		{
+			name:          "select no stack",
+			labelSelector: "env=test",
+			expected:      nil,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			selector, err := NewStackSelector(tc.labelSelector, tc.namePatterns...)
+			assert.NoError(t, err)
+
+			var selected []string
+			for _, s := range selector.Select(mockSelectorProjects()) {
+				selected = append(selected, s.Project.Name+"/"+s.Stack.Name)
+			}
+			assert.Equal(t, tc.expected, selected)
+		})
+	}
+}
+
+func TestNewStackSelector(t *testing.T) {
+	testcases := []struct {
+		name          string
+		labelSelector string
+		namePatterns  []string
+		success       bool
+	}{
+		{
+			name:          "valid selector",
+			labelSelector: "env in (prod),critical",
+			namePatterns:  []string{"*/prod"},
+			success:       true,
+		},
+		{
+			name:          "invalid label selector",
+			labelSelector: "env in prod",
+			success:       false,
+		},
+		{
+			name:         "invalid name pattern",
+			namePatterns: []string{"[prod"},
+			success:      false,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := NewStackSelector(tc.labelSelector, tc.namePatterns...)
+			assert.Equal(t, tc.success, err == nil)
+		})
+	}
+}
@@ -0,0 +1,130 @@
+package secrets
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"golang.org/x/time/rate"
+
+	v1 "kusionstack.io/kusion/pkg/apis/api.kusion.io/v1"
+)
+
+// DefaultFetchConcurrency is the default max number of the secrets fetched concurrently by the Fetcher.
+const DefaultFetchConcurrency = 8
+
+// FetchResult is the result of fetching a secret ref.
+type FetchResult struct {
+	Data []byte
+	Err  error
+}
+
+// Fetcher fetches the secrets from the secret store concurrently, with a bounded concurrency and an
+// optional rate limit, to avoid hitting the rate limit of the secret store provider when many resources
+// reference secrets.
+type Fetcher struct {
+	store          SecretStore
+	maxConcurrency int
+	limiter        *rate.Limiter
+}
+
+// FetcherOption sets the optional configs of the Fetcher.
+type FetcherOption func(*Fetcher)
+
+// WithMaxConcurrency sets the max number of the secrets fetched concurrently, defaults to
+// DefaultFetchConcurrency. The non-positive n is ignored.
+func WithMaxConcurrency(n int) FetcherOption {
+	return func(f *Fetcher) {
+		if n > 0 {
+			f.maxConcurrency = n
+		}
+	}
+}
+
+// WithRateLimit limits the fetches to qps per second on average with a token bucket, which allows burst
+// fetches at most. The fetches are not rate limited by default.
+func WithRateLimit(qps float64, burst int) FetcherOption {
+	return func(f *Fetcher) {
+		if burst < 1 {
+			burst = 1
+		}
+		f.limiter = rate.NewLimiter(rate.Limit(qps), burst)
+	}
+}
+
+// NewFetcher returns a Fetcher fetching the secrets from the store.
+func NewFetcher(store SecretStore, opts ...FetcherOption) *Fetcher {
+	f := &Fetcher{
+		store:          store,
+		maxConcurrency: DefaultFetchConcurrency,
+	}
+	for _, opt := range opts {
+		opt(f)
+	}
+	return f
+}
+
+// Fetch fetches the secret refs, and returns the results keyed by the refs, where the duplicate refs are
+// fetched once, and the error of each ref is in its result. Once the context is canceled, the refs not yet
+// fetched are not started and have the error of the context, the context is passed to the secret store to
+// stop the in-flight fetches, and the error of the context is returned along with the results. The refs
+// can't get a token of the rate limiter before the deadline of the context are not started either.
+func (f *Fetcher) Fetch(ctx context.Context, refs []v1.ExternalSecretRef) (map[v1.ExternalSecretRef]*FetchResult, error) {
+	results := make(map[v1.ExternalSecretRef]*FetchResult, len(refs))
+	var acquireErr error
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, f.maxConcurrency)
+	for _, ref := range refs {
+		if _, ok := results[ref]; ok {
+			continue
+		}
+		result := &FetchResult{}
+		results[ref] = result
+
+		if err := f.acquire(ctx, sem); err != nil {
+			result.Err = err
+			if acquireErr == nil {
+				acquireErr = err
+			}
+			continue
+		}
+		wg.Add(1)
+		go func(ref v1.ExternalSecretRef) {
+			defer func() {
+				<-sem
+				wg.Done()
+			}()
+			result.Data, result.Err = GetSecret(ctx, f.store, ref)
+		}(ref)
+	}
+	wg.Wait()
+
+	if err := ctx.Err(); err != nil {
+		return results, err
+	}
+	return results, acquireErr
+}
+
+// acquire waits for a free slot of the concurrency and a token of the rate limiter.
+func (f *Fetcher) acquire(ctx context.Context, sem chan struct{}) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	select {
+	case sem <- struct{}{}:
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+	if f.limiter == nil {
+		return nil
+	}
+	if err := f.limiter.Wait(ctx); err != nil {
+		<-sem
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		// the limiter fails immediately if the token is not available before the deadline
+		return fmt.Errorf("%w: %v", context.DeadlineExceeded, err)
+	}
+	return nil
+}
@@ -0,0 +1,124 @@
+package secrets
+
+import (
+	"context"
+	"fmt"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"kusionstack.io/kusion/pkg/apis/api.kusion.io/v1"
+)
+
+// slowSecretStore is a SecretStore taking the delay to return the name of the ref, which records the max
+// number of the concurrent calls, and blocks until the context is done if the delay is negative.
+type slowSecretStore struct {
+	delay    time.Duration
+	calls    atomic.Int32
+	inFlight atomic.Int32
+	max      atomic.Int32
+}
+
+func (s *slowSecretStore) GetSecret(ctx context.Context, ref v1.ExternalSecretRef) ([]byte, error) {
+	s.calls.Add(1)
+	n := s.inFlight.Add(1)
+	defer s.inFlight.Add(-1)
+	for {
+		m := s.max.Load()
+		if n <= m || s.max.CompareAndSwap(m, n) {
+			break
+		}
+	}
+
+	if s.delay < 0 {
+		<-ctx.Done()
+		return nil, ctx.Err()
+	}
+	select {
+	case <-time.After(s.delay):
+		return []byte(ref.Name), nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+func mockSecretRefs(n int) []v1.ExternalSecretRef {
+	refs := make([]v1.ExternalSecretRef, 0, n)
+	for i := 0; i < n; i++ {
+		refs = append(refs, v1.ExternalSecretRef{Name: fmt.Sprintf("secret-%d", i)})
+	}
+	return refs
+}
+
+func TestFetcherConcurrency(t *testing.T) {
+	store := &slowSecretStore{delay: 20 * time.Millisecond}
+	refs := append(mockSecretRefs(12), v1.ExternalSecretRef{Name: "secret-0"})
+
+	results, err := NewFetcher(store, WithMaxConcurrency(3)).Fetch(context.TODO(), refs)
+	assert.NoError(t, err)
+	assert.Len(t, results, 12)
+	for _, ref := range refs {
+		assert.NoError(t, results[ref].Err)
+		assert.Equal(t, []byte(ref.Name), results[ref].Data)
+	}
+	assert.Equal(t, int32(12), store.calls.Load(), "the duplicate ref should be fetched once")
+	assert.Equal(t, int32(3), store.max.Load())
+}
+
+func TestFetcherRateLimit(t *testing.T) {
+	store := &slowSecretStore{}
+	start := time.Now()
+	results, err := NewFetcher(store, WithRateLimit(50, 1)).Fetch(context.TODO(), mockSecretRefs(5))
+	assert.NoError(t, err)
+	assert.Len(t, results, 5)
+	// the first token is available immediately, and the next 4 are refilled every 20ms
+	assert.GreaterOrEqual(t, time.Since(start), 70*time.Millisecond)
+}
+
+func TestFetcherCancellation(t *testing.T) {
+	store := &slowSecretStore{delay: -1}
+	ctx, cancel := context.WithCancel(context.TODO())
+	go func() {
+		for store.inFlight.Load() < 2 {
+			time.Sleep(time.Millisecond)
+		}
+		cancel()
+	}()
+
+	refs := mockSecretRefs(10)
+	done := make(chan struct{})
+	var results map[v1.ExternalSecretRef]*FetchResult
+	var err error
+	go func() {
+		results, err = NewFetcher(store, WithMaxConcurrency(2)).Fetch(ctx, refs)
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("fetch is not stopped by the cancellation")
+	}
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.Equal(t, int32(2), store.calls.Load(), "no fetch should be started after the cancellation")
+	assert.Equal(t, int32(0), store.inFlight.Load())
+	assert.Len(t, results, 10)
+	for _, ref := range refs {
+		assert.ErrorIs(t, results[ref].Err, context.Canceled)
+	}
+}
+
+func TestFetcherRateLimitCancellation(t *testing.T) {
+	store := &slowSecretStore{}
+	ctx, cancel := context.WithTimeout(context.TODO(), 50*time.Millisecond)
+	defer cancel()
+
+	results, err := NewFetcher(store, WithRateLimit(1, 1)).Fetch(ctx, mockSecretRefs(3))
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.Equal(t, int32(1), store.calls.Load())
+	assert.NoError(t, results[v1.ExternalSecretRef{Name: "secret-0"}].Err)
+	assert.ErrorIs(t, results[v1.ExternalSecretRef{Name: "secret-1"}].Err, context.DeadlineExceeded)
+	assert.ErrorIs(t, results[v1.ExternalSecretRef{Name: "secret-2"}].Err, context.DeadlineExceeded)
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 
 	v1 "kusionstack.io/kusion/pkg/apis/api.kusion.io/v1"
+	"kusionstack.io/kusion/pkg/util/kerrors"
 )
 
 // SecretStore provides the interface to interact with various cloud secret manager.
@@ -12,6 +13,20 @@ type SecretStore interface {
 	GetSecret(ctx context.Context, ref v1.ExternalSecretRef) ([]byte, error)
 }
 
+// ConnectionChecker is implemented by the secret stores which can check the connection to the secret
+// manager cheaply, without reading any secret.
+type ConnectionChecker interface {
+	// CheckConnection checks the secret manager is reachable with the configured credentials.
+	CheckConnection(ctx context.Context) error
+}
+
+// SecretWriter is implemented by the secret stores supporting writing the secrets back.
+type SecretWriter interface {
+	// SetSecret creates or updates the ref secret with the value. The value is set as the property
+	// of the secret if the property is not empty, and the other properties are kept.
+	SetSecret(ctx context.Context, ref v1.ExternalSecretRef, value []byte) error
+}
+
 // SecretStoreProvider is a factory type for secret store.
 type SecretStoreProvider interface {
 	// NewSecretStore constructs a usable secret store with specific provider spec.
@@ -27,3 +42,8 @@ type NoSecretError struct{}
 func (NoSecretError) Error() string {
 	return "Secret does not exist"
 }
+
+// Is makes NoSecretError match kerrors.ErrNotFound.
+func (NoSecretError) Is(target error) bool {
+	return target == kerrors.ErrNotFound
+}
@@ -0,0 +1,118 @@
+package keyprovider
+
+import (
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+
+	v1 "kusionstack.io/kusion/pkg/apis/api.kusion.io/v1"
+
+	"kusionstack.io/kusion/pkg/util/kerrors"
+)
+
+// dataKeySize is the size of the data key, which uses AES-256.
+const dataKeySize = 32
+
+var (
+	ErrKeyNotFound   = kerrors.New(kerrors.ErrNotFound, "key not found")
+	ErrDecryptFailed = errors.New("decrypt failed")
+)
+
+// KeyProvider provides the key to encrypt and decrypt the data keys of envelope encryption, which can
+// be backed by local keys or an external key management service.
+type KeyProvider interface {
+	// KeyID returns the id of the key currently used to encrypt.
+	KeyID() string
+
+	// Encrypt encrypts the plaintext with the current key.
+	Encrypt(ctx context.Context, plaintext []byte) ([]byte, error)
+
+	// Decrypt decrypts the ciphertext with the key of the specified key id.
+	Decrypt(ctx context.Context, keyID string, ciphertext []byte) ([]byte, error)
+}
+
+// Seal encrypts the plaintext by envelope encryption: the plaintext is encrypted by a random data key
+// with AES-GCM, and the data key is encrypted by the KeyProvider.
+func Seal(ctx context.Context, provider KeyProvider, plaintext []byte) (*v1.EncryptedData, error) {
+	dataKey := make([]byte, dataKeySize)
+	if _, err := io.ReadFull(rand.Reader, dataKey); err != nil {
+		return nil, fmt.Errorf("generate data key failed: %w", err)
+	}
+
+	ciphertext, err := AESGCMEncrypt(dataKey, plaintext)
+	if err != nil {
+		return nil, err
+	}
+	encryptedKey, err := provider.Encrypt(ctx, dataKey)
+	if err != nil {
+		return nil, fmt.Errorf("encrypt data key failed: %w", err)
+	}
+
+	return &v1.EncryptedData{
+		KeyID:        provider.KeyID(),
+		EncryptedKey: base64.StdEncoding.EncodeToString(encryptedKey),
+		Ciphertext:   base64.StdEncoding.EncodeToString(ciphertext),
+	}, nil
+}
+
+// Open decrypts the data sealed by Seal.
+func Open(ctx context.Context, provider KeyProvider, data *v1.EncryptedData) ([]byte, error) {
+	encryptedKey, err := base64.StdEncoding.DecodeString(data.EncryptedKey)
+	if err != nil {
+		return nil, fmt.Errorf("%w, invalid encrypted key: %v", ErrDecryptFailed, err)
+	}
+	ciphertext, err := base64.StdEncoding.DecodeString(data.Ciphertext)
+	if err != nil {
+		return nil, fmt.Errorf("%w, invalid ciphertext: %v", ErrDecryptFailed, err)
+	}
+
+	dataKey, err := provider.Decrypt(ctx, data.KeyID, encryptedKey)
+	if err != nil {
+		return nil, fmt.Errorf("decrypt data key failed: %w", err)
+	}
+	return AESGCMDecrypt(dataKey, ciphertext)
+}
+
+// AESGCMEncrypt encrypts the plaintext with AES-GCM, and the random nonce is prepended to the ciphertext.
+// The length of the key must be 16, 24 or 32 bytes.
+func AESGCMEncrypt(key, plaintext []byte) ([]byte, error) {
+	gcm, err := newGCM(key)
+	if err != nil {
+		return nil, err
+	}
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err = io.ReadFull(rand.Reader, nonce); err != nil {
+		return nil, fmt.Errorf("generate nonce failed: %w", err)
+	}
+	return gcm.Seal(nonce, nonce, plaintext, nil), nil
+}
+
+// AESGCMDecrypt decrypts the ciphertext encrypted by AESGCMEncrypt.
+func AESGCMDecrypt(key, ciphertext []byte) ([]byte, error) {
+	gcm, err := newGCM(key)
+	if err != nil {
+		return nil, err
+	}
+	if len(ciphertext) < gcm.NonceSize() {
+		return nil, fmt.Errorf("%w, ciphertext is too short", ErrDecryptFailed)
+	}
+	nonce, sealed := ciphertext[:gcm.NonceSize()], ciphertext[gcm.NonceSize():]
+	plaintext, err := gcm.Open(nil, nonce, sealed, nil)
+	if err != nil {
+		return nil, fmt.Errorf("%w, %v", ErrDecryptFailed, err)
+	}
+	return plaintext, nil
+}
+
+func newGCM(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, fmt.Errorf("invalid aes key: %w", err)
+	}
+	return cipher.NewGCM(block)
+}
@@ -0,0 +1,156 @@
+package keyprovider
+
+import (
+	"context"
+	"encoding/base64"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func mockKey(b byte) []byte {
+	key := make([]byte, 32)
+	for i := range key {
+		key[i] = b
+	}
+	return key
+}
+
+func TestNewLocalProvider(t *testing.T) {
+	testcases := []struct {
+		name    string
+		success bool
+		keyID   string
+		keys    map[string][]byte
+	}{
+		{
+			name:    "new local provider successfully",
+			success: true,
+			keyID:   "key-1",
+			keys:    map[string][]byte{"key-1": mockKey(1)},
+		},
+		{
+			name:    "failed to new local provider current key not found",
+			success: false,
+			keyID:   "key-2",
+			keys:    map[string][]byte{"key-1": mockKey(1)},
+		},
+		{
+			name:    "failed to new local provider invalid key length",
+			success: false,
+			keyID:   "key-1",
+			keys:    map[string][]byte{"key-1": []byte("short")},
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := NewLocalProvider(tc.keyID, tc.keys)
+			assert.Equal(t, tc.success, err == nil)
+		})
+	}
+}
+
+func TestParseLocalKeys(t *testing.T) {
+	encoded1 := base64.StdEncoding.EncodeToString(mockKey(1))
+	encoded2 := base64.StdEncoding.EncodeToString(mockKey(2))
+	testcases := []struct {
+		name     string
+		success  bool
+		text     string
+		expected map[string][]byte
+	}{
+		{
+			name:     "parse multiple keys successfully",
+			success:  true,
+			text:     "key-1=" + encoded1 + ", key-2=" + encoded2,
+			expected: map[string][]byte{"key-1": mockKey(1), "key-2": mockKey(2)},
+		},
+		{
+			name:     "parse empty keys successfully",
+			success:  true,
+			text:     "",
+			expected: map[string][]byte{},
+		},
+		{
+			name:    "failed to parse keys without key id",
+			success: false,
+			text:    "=" + encoded1,
+		},
+		{
+			name:    "failed to parse keys not base64 encoded",
+			success: false,
+			text:    "key-1=not-base64!",
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			keys, err := ParseLocalKeys(tc.text)
+			assert.Equal(t, tc.success, err == nil)
+			if tc.success {
+				assert.Equal(t, tc.expected, keys)
+			}
+		})
+	}
+}
+
+func TestSealAndOpen(t *testing.T) {
+	plaintext := []byte("password: kusion")
+	provider, err := NewLocalProvider("key-1", map[string][]byte{"key-1": mockKey(1)})
+	require.NoError(t, err)
+	data, err := Seal(context.Background(), provider, plaintext)
+	require.NoError(t, err)
+	assert.Equal(t, "key-1", data.KeyID)
+	assert.NotContains(t, data.Ciphertext, string(plaintext))
+
+	testcases := []struct {
+		name     string
+		success  bool
+		keyID    string
+		keys     map[string][]byte
+		expected error
+	}{
+		{
+			name:    "open successfully",
+			success: true,
+			keyID:   "key-1",
+			keys:    map[string][]byte{"key-1": mockKey(1)},
+		},
+		{
+			name:    "open successfully after key rotation",
+			success: true,
+			keyID:   "key-2",
+			keys:    map[string][]byte{"key-1": mockKey(1), "key-2": mockKey(2)},
+		},
+		{
+			name:     "failed to open key not found",
+			success:  false,
+			keyID:    "key-2",
+			keys:     map[string][]byte{"key-2": mockKey(2)},
+			expected: ErrKeyNotFound,
+		},
+		{
+			name:     "failed to open wrong key",
+			success:  false,
+			keyID:    "key-1",
+			keys:     map[string][]byte{"key-1": mockKey(2)},
+			expected: ErrDecryptFailed,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			p, err := NewLocalProvider(tc.keyID, tc.keys)
+			require.NoError(t, err)
+			opened, err := Open(context.Background(), p, data)
+			assert.Equal(t, tc.success, err == nil)
+			if tc.success {
+				assert.Equal(t, plaintext, opened)
+			} else {
+				assert.ErrorIs(t, err, tc.expected)
+			}
+		})
+	}
+}
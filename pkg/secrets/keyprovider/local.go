@@ -0,0 +1,68 @@
+package keyprovider
+
+import (
+	"context"
+	"crypto/aes"
+	"encoding/base64"
+	"fmt"
+	"strings"
+)
+
+// LocalProvider is a KeyProvider with local AES keys, which is mainly used for testing. The retired
+// keys can be kept to decrypt the data encrypted before key rotation.
+type LocalProvider struct {
+	keyID string
+	keys  map[string][]byte
+}
+
+// NewLocalProvider news a LocalProvider, which encrypts with the key of the specified keyID, and
+// decrypts with any key in keys. The length of the keys must be 16, 24 or 32 bytes.
+func NewLocalProvider(keyID string, keys map[string][]byte) (*LocalProvider, error) {
+	if _, ok := keys[keyID]; !ok {
+		return nil, fmt.Errorf("%w: %s", ErrKeyNotFound, keyID)
+	}
+	for id, key := range keys {
+		if _, err := aes.NewCipher(key); err != nil {
+			return nil, fmt.Errorf("invalid key %s: %w", id, err)
+		}
+	}
+	return &LocalProvider{keyID: keyID, keys: keys}, nil
+}
+
+func (p *LocalProvider) KeyID() string {
+	return p.keyID
+}
+
+func (p *LocalProvider) Encrypt(_ context.Context, plaintext []byte) ([]byte, error) {
+	return AESGCMEncrypt(p.keys[p.keyID], plaintext)
+}
+
+func (p *LocalProvider) Decrypt(_ context.Context, keyID string, ciphertext []byte) ([]byte, error) {
+	key, ok := p.keys[keyID]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrKeyNotFound, keyID)
+	}
+	return AESGCMDecrypt(key, ciphertext)
+}
+
+// ParseLocalKeys parses the keys of LocalProvider from the text in the format of
+// "<keyID>=<base64 encoded key>,<keyID>=<base64 encoded key>", where the key id must not be empty.
+func ParseLocalKeys(text string) (map[string][]byte, error) {
+	keys := make(map[string][]byte)
+	for _, item := range strings.Split(text, ",") {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+		keyID, encoded, ok := strings.Cut(item, "=")
+		if !ok || keyID == "" {
+			return nil, fmt.Errorf("invalid key %q, should be in the format of <keyID>=<base64 encoded key>", item)
+		}
+		key, err := base64.StdEncoding.DecodeString(encoded)
+		if err != nil {
+			return nil, fmt.Errorf("invalid key %s: %w", keyID, err)
+		}
+		keys[keyID] = key
+	}
+	return keys, nil
+}
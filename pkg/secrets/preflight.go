@@ -0,0 +1,90 @@
+package secrets
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	v1 "kusionstack.io/kusion/pkg/apis/api.kusion.io/v1"
+	"kusionstack.io/kusion/pkg/util/kerrors"
+)
+
+// ErrSecretPreflightFailed is returned by PreflightSecrets if any secret can not be resolved.
+var ErrSecretPreflightFailed = kerrors.New(kerrors.ErrValidation, "secret preflight check failed")
+
+// SecretRefFailure is a secret ref which fails to resolve with the error.
+type SecretRefFailure struct {
+	Ref v1.ExternalSecretRef
+	Err error
+}
+
+// PreflightReport is the result of checking the existence of the secrets, where the refs are grouped
+// by the reason of failing to resolve.
+type PreflightReport struct {
+	NotFound         []v1.ExternalSecretRef
+	PermissionDenied []v1.ExternalSecretRef
+	Failed           []SecretRefFailure
+}
+
+// OK returns whether all the secrets are resolved.
+func (r *PreflightReport) OK() bool {
+	return len(r.NotFound) == 0 && len(r.PermissionDenied) == 0 && len(r.Failed) == 0
+}
+
+// PreflightError is the consolidated error of the secrets failed to resolve.
+type PreflightError struct {
+	Report *PreflightReport
+}
+
+func (e *PreflightError) Error() string {
+	var lines []string
+	for _, ref := range e.Report.NotFound {
+		lines = append(lines, fmt.Sprintf("  not found: %s", formatSecretRef(ref)))
+	}
+	for _, ref := range e.Report.PermissionDenied {
+		lines = append(lines, fmt.Sprintf("  permission denied: %s", formatSecretRef(ref)))
+	}
+	for _, failure := range e.Report.Failed {
+		lines = append(lines, fmt.Sprintf("  failed: %s, %v", formatSecretRef(failure.Ref), failure.Err))
+	}
+	return fmt.Sprintf("%s, %d secret(s) can not be resolved:\n%s", ErrSecretPreflightFailed, len(lines), strings.Join(lines, "\n"))
+}
+
+func (e *PreflightError) Unwrap() error {
+	return ErrSecretPreflightFailed
+}
+
+// PreflightSecrets verifies that each secret ref resolves in the secret store before applying, and the
+// secret values are discarded. All the refs are checked, and a PreflightError with the consolidated
+// list of the refs failed to resolve is returned, which distinguishes not found from permission denied.
+// A secret store returning nil data without error is regarded as not found.
+func PreflightSecrets(ctx context.Context, store SecretStore, refs []v1.ExternalSecretRef) error {
+	report := &PreflightReport{}
+	for _, ref := range refs {
+		data, err := GetSecret(ctx, store, ref)
+		switch {
+		case kerrors.IsNotFound(err), err == nil && data == nil:
+			report.NotFound = append(report.NotFound, ref)
+		case kerrors.IsPermissionDenied(err):
+			report.PermissionDenied = append(report.PermissionDenied, ref)
+		case err != nil:
+			report.Failed = append(report.Failed, SecretRefFailure{Ref: ref, Err: err})
+		}
+	}
+	if report.OK() {
+		return nil
+	}
+	return &PreflightError{Report: report}
+}
+
+// formatSecretRef formats the secret ref in the form of name/property?version=version.
+func formatSecretRef(ref v1.ExternalSecretRef) string {
+	s := ref.Name
+	if ref.Property != "" {
+		s += "/" + ref.Property
+	}
+	if ref.Version != "" {
+		s += "?version=" + ref.Version
+	}
+	return s
+}
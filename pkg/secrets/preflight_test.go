@@ -0,0 +1,78 @@
+package secrets
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"kusionstack.io/kusion/pkg/apis/api.kusion.io/v1"
+	"kusionstack.io/kusion/pkg/util/kerrors"
+)
+
+// mapSecretStore is a SecretStore with the secrets of the map, and the errors of the refs.
+type mapSecretStore struct {
+	secrets map[string][]byte
+	errs    map[string]error
+}
+
+func (s *mapSecretStore) GetSecret(_ context.Context, ref v1.ExternalSecretRef) ([]byte, error) {
+	if err, ok := s.errs[ref.Name]; ok {
+		return nil, err
+	}
+	if data, ok := s.secrets[ref.Name]; ok {
+		return data, nil
+	}
+	return nil, NoSecretErr
+}
+
+func TestPreflightSecrets(t *testing.T) {
+	store := &mapSecretStore{
+		secrets: map[string][]byte{
+			"db-credentials": []byte(`{"username":"admin"}`),
+			"api-token":      []byte("token"),
+		},
+		errs: map[string]error{
+			"aws-credentials": kerrors.Wrap(kerrors.ErrPermissionDenied, errors.New("AccessDeniedException")),
+			"kubeconfig":      errors.New("connection refused"),
+			"empty":           nil,
+		},
+	}
+
+	t.Run("all present", func(t *testing.T) {
+		err := PreflightSecrets(context.TODO(), store, []v1.ExternalSecretRef{
+			{Name: "db-credentials", Property: "username"},
+			{Name: "api-token"},
+		})
+		assert.NoError(t, err)
+	})
+
+	t.Run("some missing", func(t *testing.T) {
+		err := PreflightSecrets(context.TODO(), store, []v1.ExternalSecretRef{
+			{Name: "db-credentials", Property: "username"},
+			{Name: "db-password", Version: "2"},
+			{Name: "aws-credentials", Property: "accessKey"},
+			{Name: "kubeconfig"},
+			{Name: "empty"},
+		})
+		require.Error(t, err)
+		assert.ErrorIs(t, err, ErrSecretPreflightFailed)
+		assert.True(t, kerrors.IsValidation(err))
+
+		var preflightErr *PreflightError
+		require.True(t, errors.As(err, &preflightErr))
+		assert.Equal(t, []v1.ExternalSecretRef{{Name: "db-password", Version: "2"}, {Name: "empty"}}, preflightErr.Report.NotFound)
+		assert.Equal(t, []v1.ExternalSecretRef{{Name: "aws-credentials", Property: "accessKey"}}, preflightErr.Report.PermissionDenied)
+		require.Len(t, preflightErr.Report.Failed, 1)
+		assert.Equal(t, v1.ExternalSecretRef{Name: "kubeconfig"}, preflightErr.Report.Failed[0].Ref)
+
+		assert.Contains(t, err.Error(), "4 secret(s) can not be resolved")
+		assert.Contains(t, err.Error(), "not found: db-password?version=2")
+		assert.Contains(t, err.Error(), "permission denied: aws-credentials/accessKey")
+		assert.Contains(t, err.Error(), "failed: kubeconfig, connection refused")
+		// the secret values are not included
+		assert.NotContains(t, err.Error(), "admin")
+	})
+}
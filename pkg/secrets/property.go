@@ -0,0 +1,116 @@
+package secrets
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/tidwall/gjson"
+
+	"kusionstack.io/kusion/pkg/util/kerrors"
+)
+
+// ErrPropertyNotFound is returned by ExtractProperty if the property does not resolve in the secret.
+var ErrPropertyNotFound = kerrors.New(kerrors.ErrNotFound, "property not found")
+
+// ExtractProperty extracts the value of the property from the JSON secret payload, which is shared by
+// the secret store providers to resolve ExternalSecretRef.Property. The property is resolved as:
+//   - the top-level key equal to the property, so that the keys containing dots or slashes are allowed;
+//   - a JSON pointer if the property contains slashes, e.g. /db/credentials/password, where the leading
+//     slash is optional and ~0 and ~1 are unescaped to ~ and / respectively;
+//   - a dotted path otherwise, e.g. db.credentials.password.
+//
+// The string value is returned as it is, and the other values are returned in JSON. The whole payload is
+// returned if the property is empty. An error of ErrPropertyNotFound is returned if the property does not
+// resolve, rather than the whole payload.
+func ExtractProperty(payload []byte, property string) ([]byte, error) {
+	if property == "" {
+		return payload, nil
+	}
+	if !gjson.ValidBytes(payload) {
+		return nil, fmt.Errorf("%w: %s, secret is not in JSON format", ErrPropertyNotFound, property)
+	}
+
+	// the top-level key with the special characters escaped
+	if val := gjson.GetBytes(payload, escapeGJSONPath(property)); val.Exists() {
+		return []byte(val.String()), nil
+	}
+
+	if strings.Contains(property, "/") {
+		if val, ok := resolveJSONPointer(payload, property); ok {
+			return val, nil
+		}
+	} else if val := gjson.GetBytes(payload, property); val.Exists() {
+		return []byte(val.String()), nil
+	}
+	return nil, fmt.Errorf("%w: %s", ErrPropertyNotFound, property)
+}
+
+// SetProperty sets the value as the top-level property of the JSON object payload, which is shared by the
+// secret store providers to write ExternalSecretRef.Property. The other properties are kept, and an empty
+// payload is regarded as an empty object. The value is returned as it is if the property is empty.
+func SetProperty(payload []byte, property string, value []byte) ([]byte, error) {
+	if property == "" {
+		return value, nil
+	}
+	object := make(map[string]interface{})
+	if len(payload) > 0 {
+		if err := json.Unmarshal(payload, &object); err != nil {
+			return nil, fmt.Errorf("failed to set property %s, secret is not a JSON object: %w", property, err)
+		}
+	}
+	object[property] = string(value)
+	return json.Marshal(object)
+}
+
+// resolveJSONPointer resolves the JSON pointer in the payload, whose leading slash is optional.
+func resolveJSONPointer(payload []byte, pointer string) ([]byte, bool) {
+	var current interface{}
+	if err := json.Unmarshal(payload, &current); err != nil {
+		return nil, false
+	}
+
+	for _, token := range strings.Split(strings.TrimPrefix(pointer, "/"), "/") {
+		token = strings.ReplaceAll(strings.ReplaceAll(token, "~1", "/"), "~0", "~")
+		switch v := current.(type) {
+		case map[string]interface{}:
+			next, ok := v[token]
+			if !ok {
+				return nil, false
+			}
+			current = next
+		case []interface{}:
+			idx, err := strconv.Atoi(token)
+			if err != nil || idx < 0 || idx >= len(v) {
+				return nil, false
+			}
+			current = v[idx]
+		default:
+			return nil, false
+		}
+	}
+
+	if s, ok := current.(string); ok {
+		return []byte(s), true
+	}
+	data, err := json.Marshal(current)
+	if err != nil {
+		return nil, false
+	}
+	return data, true
+}
+
+// escapeGJSONPath escapes the special characters of the gjson path, so that the path matches the
+// top-level key exactly.
+func escapeGJSONPath(path string) string {
+	var sb strings.Builder
+	for _, c := range path {
+		switch c {
+		case '.', '*', '?', '|', '#', '@', '!', '\\':
+			sb.WriteRune('\\')
+		}
+		sb.WriteRune(c)
+	}
+	return sb.String()
+}
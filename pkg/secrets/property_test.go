@@ -0,0 +1,142 @@
+package secrets
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"kusionstack.io/kusion/pkg/util/kerrors"
+)
+
+func TestExtractProperty(t *testing.T) {
+	payload := []byte(`{
+		"db": {"credentials": {"username": "admin", "password": "t0p-Secret", "port": 5432}},
+		"hosts": ["primary", "replica"],
+		"dotted.key": "dotted-value",
+		"slashed/key": "slashed-value",
+		"escaped": {"a/b": {"c~d": "escaped-value"}}
+	}`)
+
+	testcases := []struct {
+		name     string
+		payload  []byte
+		property string
+		success  bool
+		expected string
+	}{
+		{
+			name:     "empty property",
+			payload:  []byte("plain"),
+			property: "",
+			success:  true,
+			expected: "plain",
+		},
+		{
+			name:     "json pointer",
+			payload:  payload,
+			property: "/db/credentials/password",
+			success:  true,
+			expected: "t0p-Secret",
+		},
+		{
+			name:     "json pointer without leading slash",
+			payload:  payload,
+			property: "db/credentials/username",
+			success:  true,
+			expected: "admin",
+		},
+		{
+			name:     "json pointer of number",
+			payload:  payload,
+			property: "db/credentials/port",
+			success:  true,
+			expected: "5432",
+		},
+		{
+			name:     "json pointer of array item",
+			payload:  payload,
+			property: "/hosts/1",
+			success:  true,
+			expected: "replica",
+		},
+		{
+			name:     "json pointer of object",
+			payload:  payload,
+			property: "/db/credentials",
+			success:  true,
+			expected: `{"password":"t0p-Secret","port":5432,"username":"admin"}`,
+		},
+		{
+			name:     "json pointer with escaped tokens",
+			payload:  payload,
+			property: "/escaped/a~1b/c~0d",
+			success:  true,
+			expected: "escaped-value",
+		},
+		{
+			name:     "dotted path",
+			payload:  payload,
+			property: "db.credentials.password",
+			success:  true,
+			expected: "t0p-Secret",
+		},
+		{
+			name:     "key with dots",
+			payload:  payload,
+			property: "dotted.key",
+			success:  true,
+			expected: "dotted-value",
+		},
+		{
+			name:     "key with slashes",
+			payload:  payload,
+			property: "slashed/key",
+			success:  true,
+			expected: "slashed-value",
+		},
+		{
+			name:     "missing json pointer",
+			payload:  payload,
+			property: "/db/credentials/token",
+			success:  false,
+		},
+		{
+			name:     "json pointer through string",
+			payload:  payload,
+			property: "/db/credentials/password/value",
+			success:  false,
+		},
+		{
+			name:     "json pointer out of array range",
+			payload:  payload,
+			property: "/hosts/2",
+			success:  false,
+		},
+		{
+			name:     "missing dotted path",
+			payload:  payload,
+			property: "db.credentials.token",
+			success:  false,
+		},
+		{
+			name:     "not json",
+			payload:  []byte("plain"),
+			property: "password",
+			success:  false,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			val, err := ExtractProperty(tc.payload, tc.property)
+			if tc.success {
+				assert.NoError(t, err)
+				assert.Equal(t, tc.expected, string(val))
+			} else {
+				assert.ErrorIs(t, err, ErrPropertyNotFound)
+				assert.True(t, kerrors.IsNotFound(err))
+				assert.Nil(t, val)
+			}
+		})
+	}
+}
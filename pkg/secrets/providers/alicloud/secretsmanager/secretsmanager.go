@@ -3,22 +3,30 @@ package secretsmanager
 import (
 	"context"
 	"fmt"
+	"net/url"
 	"os"
 	"strings"
 
 	"kusionstack.io/kusion/pkg/apis/api.kusion.io/v1"
 	"kusionstack.io/kusion/pkg/secrets"
 
+	alisdk "github.com/aliyun/alibaba-cloud-sdk-go/sdk"
+	"github.com/aliyun/alibaba-cloud-sdk-go/sdk/auth"
+	"github.com/aliyun/alibaba-cloud-sdk-go/sdk/auth/credentials"
+	"github.com/aliyun/alibaba-cloud-sdk-go/sdk/requests"
+	"github.com/aliyun/alibaba-cloud-sdk-go/services/kms"
 	"github.com/aliyun/aliyun-secretsmanager-client-go/sdk"
 	"github.com/aliyun/aliyun-secretsmanager-client-go/sdk/models"
 	"github.com/aliyun/aliyun-secretsmanager-client-go/sdk/service"
-	"github.com/tidwall/gjson"
 )
 
 const (
 	errMissingProviderSpec     = "store spec is missing provider"
 	errMissingAlicloudProvider = "invalid provider spec. Missing Alicloud field in store provider spec"
 	errFailedToCreateClient    = "failed to create Alicloud Secrets Manager client: %w"
+	errInvalidEndpoint         = "invalid endpoint %s: %v"
+
+	defaultRoleSessionName = "kusion"
 )
 
 var (
@@ -50,7 +58,11 @@ func (p *DefaultSecretStoreProvider) NewSecretStore(spec *v1.SecretStore) (secre
 		return nil, fmt.Errorf(errMissingAlicloudProvider)
 	}
 
-	client, err := getAlicloudClient(providerSpec.Alicloud.Region)
+	scheme, host, err := parseEndpoint(providerSpec.Alicloud.Endpoint)
+	if err != nil {
+		return nil, fmt.Errorf(errFailedToCreateClient, err)
+	}
+	client, err := getAlicloudClient(providerSpec.Alicloud.Region, scheme, host, getCredential(providerSpec.Alicloud))
 	if err != nil {
 		return nil, fmt.Errorf(errFailedToCreateClient, err)
 	}
@@ -60,13 +72,89 @@ func (p *DefaultSecretStoreProvider) NewSecretStore(spec *v1.SecretStore) (secre
 	}, nil
 }
 
-// getAlicloudClient returns an Alicloud Secrets Manager client with the specified region.
+// getAlicloudClient returns an Alicloud Secrets Manager client in the region, which calls the custom endpoint
+// if the host is not empty, otherwise the endpoint derived from the region. The endpoint client is used for
+// the custom endpoint in http, for the default client always calls the endpoint in https.
 // Ref: https://github.com/aliyun/aliyun-secretsmanager-client-go/blob/v1.1.4/README.md
-func getAlicloudClient(region string) (*sdk.SecretManagerCacheClient, error) {
-	return sdk.NewSecretCacheClientBuilder(
-		service.NewDefaultSecretManagerClientBuilder().Standard().WithAccessKey(
-			accessKeyID, accessKeySecret,
-		).WithRegion(region).Build()).Build()
+func getAlicloudClient(region, scheme, host string, credential auth.Credential) (*sdk.SecretManagerCacheClient, error) {
+	var client service.SecretManagerClient
+	if host != "" && scheme != requests.HTTPS {
+		client = &endpointClient{region: region, scheme: scheme, host: host, credential: credential}
+	} else {
+		client = service.NewDefaultSecretManagerClientBuilder().Standard().WithCredentials(
+			credential,
+		).AddRegionInfo(models.NewRegionInfoWithEndpoint(region, host)).Build()
+	}
+	return sdk.NewSecretCacheClientBuilder(client).Build()
+}
+
+// endpointClient is the service.SecretManagerClient calling the custom endpoint in its scheme.
+type endpointClient struct {
+	region     string
+	scheme     string
+	host       string
+	credential auth.Credential
+
+	client *kms.Client
+}
+
+func (c *endpointClient) Init() error {
+	client, err := kms.NewClientWithOptions(c.region, alisdk.NewConfig(), c.credential)
+	if err != nil {
+		return err
+	}
+	client.Domain = c.host
+	c.client = client
+	return nil
+}
+
+func (c *endpointClient) GetSecretValue(req *kms.GetSecretValueRequest) (*kms.GetSecretValueResponse, error) {
+	req.Scheme = c.scheme
+	return c.client.GetSecretValue(req)
+}
+
+func (c *endpointClient) Close() error {
+	c.client.Shutdown()
+	return nil
+}
+
+// getCredential returns the access key credential from the environment, or the RAM role credential assumed
+// with it if the RoleARN is specified. The STS token of the RAM role credential is got from the STS in the
+// region of the provider by the signer of the client on the first request, and refreshed before it expires.
+func getCredential(spec *v1.AlicloudProvider) auth.Credential {
+	if spec.RoleARN == "" {
+		return credentials.NewAccessKeyCredential(accessKeyID, accessKeySecret)
+	}
+	roleSessionName := spec.RoleSessionName
+	if roleSessionName == "" {
+		roleSessionName = defaultRoleSessionName
+	}
+	credential := credentials.NewRamRoleArnCredential(accessKeyID, accessKeySecret, spec.RoleARN, roleSessionName, 0)
+	credential.StsRegion = spec.Region
+	return credential
+}
+
+// parseEndpoint returns the scheme and host of the custom endpoint, where the scheme defaults to https if
+// the endpoint is a host. Both are empty if the endpoint is not specified.
+func parseEndpoint(endpoint string) (string, string, error) {
+	if endpoint == "" {
+		return "", "", nil
+	}
+	if !strings.Contains(endpoint, "://") {
+		return requests.HTTPS, endpoint, nil
+	}
+	u, err := url.Parse(endpoint)
+	if err != nil {
+		return "", "", fmt.Errorf(errInvalidEndpoint, endpoint, err)
+	}
+	scheme := strings.ToUpper(u.Scheme)
+	if scheme != requests.HTTP && scheme != requests.HTTPS {
+		return "", "", fmt.Errorf(errInvalidEndpoint, endpoint, "unsupported scheme "+u.Scheme)
+	}
+	if u.Host == "" {
+		return "", "", fmt.Errorf(errInvalidEndpoint, endpoint, "empty host")
+	}
+	return scheme, u.Host, nil
 }
 
 // GetSecret retrieves ref secret value from Alicloud Secrets Manager.
@@ -84,34 +172,19 @@ func (s *smSecretStore) GetSecret(ctx context.Context, ref v1.ExternalSecretRef)
 		}
 		return nil, fmt.Errorf("invalid secret data. no secret value string nor binary for key: %s", ref.Name)
 	}
-	val := s.convertSecretToGjson(secretInfo, ref.Property)
-	if !val.Exists() {
-		return nil, fmt.Errorf("key %s does not exist in secret %s", ref.Property, ref.Name)
+	val, err := secrets.ExtractProperty(secretPayload(secretInfo), ref.Property)
+	if err != nil {
+		return nil, fmt.Errorf("%w in secret %s", err, ref.Name)
 	}
-	return []byte(val.String()), nil
+	return val, nil
 }
 
-func (s *smSecretStore) convertSecretToGjson(secretInfo *models.SecretInfo, refProperty string) gjson.Result {
-	var payload string
-	if secretInfo.SecretValue != "" {
-		payload = secretInfo.SecretValue
-	}
+// secretPayload returns the secret value string or binary of the secret info.
+func secretPayload(secretInfo *models.SecretInfo) []byte {
 	if secretInfo.SecretValueByteBuffer != nil {
-		payload = string(secretInfo.SecretValueByteBuffer)
+		return secretInfo.SecretValueByteBuffer
 	}
-
-	// We need to search if a given key with a . exists before using gjson operations.
-	idx := strings.Index(refProperty, ".")
-	currentRefProperty := refProperty
-	if idx > -1 {
-		currentRefProperty = strings.ReplaceAll(refProperty, ".", "\\.")
-		val := gjson.Get(payload, currentRefProperty)
-		if !val.Exists() {
-			currentRefProperty = refProperty
-		}
-	}
-
-	return gjson.Get(payload, currentRefProperty)
+	return []byte(secretInfo.SecretValue)
 }
 
 func init() {
@@ -4,12 +4,21 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
 	"reflect"
 	"testing"
 
+	"github.com/aliyun/alibaba-cloud-sdk-go/sdk"
+	"github.com/aliyun/alibaba-cloud-sdk-go/sdk/auth"
+	"github.com/aliyun/alibaba-cloud-sdk-go/sdk/auth/credentials"
+	"github.com/aliyun/alibaba-cloud-sdk-go/sdk/requests"
+	"github.com/aliyun/alibaba-cloud-sdk-go/sdk/responses"
 	"github.com/google/go-cmp/cmp"
 
 	"kusionstack.io/kusion/pkg/apis/api.kusion.io/v1"
+	"kusionstack.io/kusion/pkg/secrets"
 	"kusionstack.io/kusion/pkg/secrets/providers/alicloud/secretsmanager/fake"
 )
 
@@ -88,7 +97,7 @@ func TestGetSecret(t *testing.T) {
 			name:        "/beep",
 			property:    "foobar.baz",
 			expected:    nil,
-			expectedErr: fmt.Errorf("key foobar.baz does not exist in secret /beep"),
+			expectedErr: fmt.Errorf("%w in secret /beep", fmt.Errorf("%w: foobar.baz", secrets.ErrPropertyNotFound)),
 		},
 	}
 
@@ -148,6 +157,187 @@ func TestNewSecretStore(t *testing.T) {
 	}
 }
 
+func TestParseEndpoint(t *testing.T) {
+	testCases := map[string]struct {
+		endpoint       string
+		expectedScheme string
+		expectedHost   string
+		expectedErr    error
+	}{
+		"RegionEndpoint": {
+			endpoint: "",
+		},
+		"CustomEndpointHost": {
+			endpoint:       "kms-vpc.cn-beijing.aliyuncs.com",
+			expectedScheme: "HTTPS",
+			expectedHost:   "kms-vpc.cn-beijing.aliyuncs.com",
+		},
+		"CustomEndpointURL": {
+			endpoint:       "https://kms-vpc.cn-beijing.aliyuncs.com/",
+			expectedScheme: "HTTPS",
+			expectedHost:   "kms-vpc.cn-beijing.aliyuncs.com",
+		},
+		"CustomEndpointURLInHTTP": {
+			endpoint:       "http://kms-vpc.cn-beijing.aliyuncs.com:8080",
+			expectedScheme: "HTTP",
+			expectedHost:   "kms-vpc.cn-beijing.aliyuncs.com:8080",
+		},
+		"InvalidEndpointURL": {
+			endpoint:    "https://",
+			expectedErr: fmt.Errorf(errInvalidEndpoint, "https://", "empty host"),
+		},
+		"UnsupportedEndpointScheme": {
+			endpoint:    "ftp://kms-vpc.cn-beijing.aliyuncs.com",
+			expectedErr: fmt.Errorf(errInvalidEndpoint, "ftp://kms-vpc.cn-beijing.aliyuncs.com", "unsupported scheme ftp"),
+		},
+	}
+
+	for name, tc := range testCases {
+		scheme, host, err := parseEndpoint(tc.endpoint)
+		if diff := cmp.Diff(err, tc.expectedErr, EquateErrors()); diff != "" {
+			t.Errorf("\n%s\ngot unexpected error: \n%s", name, diff)
+		}
+		if scheme != tc.expectedScheme || host != tc.expectedHost {
+			t.Errorf("\n%s\ngot unexpected endpoint: %s://%s", name, scheme, host)
+		}
+	}
+}
+
+func TestGetSecretFromHTTPEndpoint(t *testing.T) {
+	var action string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_ = r.ParseForm()
+		action = r.Form.Get("Action")
+		_, _ = w.Write([]byte(`{"RequestId":"req","SecretName":"db","VersionId":"v1","SecretData":"password","SecretDataType":"text","VersionStages":{"VersionStage":["ACSCurrent"]}}`))
+	}))
+	defer server.Close()
+
+	factory := DefaultSecretStoreProvider{}
+	store, err := factory.NewSecretStore(&v1.SecretStore{
+		Provider: &v1.ProviderSpec{
+			Alicloud: &v1.AlicloudProvider{Region: "cn-beijing", Endpoint: server.URL},
+		},
+	})
+	if err != nil {
+		t.Fatalf("got unexpected error: %v", err)
+	}
+	actual, err := store.GetSecret(context.Background(), v1.ExternalSecretRef{Name: "db"})
+	if err != nil {
+		t.Fatalf("got unexpected error: %v", err)
+	}
+	if string(actual) != "password" || action != "GetSecretValue" {
+		t.Errorf("got unexpected secret %s of action %s from the custom endpoint", actual, action)
+	}
+}
+
+func TestGetCredential(t *testing.T) {
+	testCases := map[string]struct {
+		spec     *v1.AlicloudProvider
+		expected auth.Credential
+	}{
+		"WithoutRole": {
+			spec:     &v1.AlicloudProvider{Region: "cn-beijing"},
+			expected: credentials.NewAccessKeyCredential(accessKeyID, accessKeySecret),
+		},
+		"AssumeRoleWithDefaultSessionName": {
+			spec: &v1.AlicloudProvider{Region: "cn-beijing", RoleARN: "acs:ram::123456789012:role/kusion"},
+			expected: &credentials.RamRoleArnCredential{
+				AccessKeyId:     accessKeyID,
+				AccessKeySecret: accessKeySecret,
+				RoleArn:         "acs:ram::123456789012:role/kusion",
+				RoleSessionName: defaultRoleSessionName,
+				StsRegion:       "cn-beijing",
+			},
+		},
+		"AssumeRoleWithSessionName": {
+			spec: &v1.AlicloudProvider{
+				Region:          "cn-hangzhou",
+				RoleARN:         "acs:ram::123456789012:role/kusion",
+				RoleSessionName: "ci",
+			},
+			expected: &credentials.RamRoleArnCredential{
+				AccessKeyId:     accessKeyID,
+				AccessKeySecret: accessKeySecret,
+				RoleArn:         "acs:ram::123456789012:role/kusion",
+				RoleSessionName: "ci",
+				StsRegion:       "cn-hangzhou",
+			},
+		},
+	}
+
+	for name, tc := range testCases {
+		actual := getCredential(tc.spec)
+		if diff := cmp.Diff(actual, tc.expected); diff != "" {
+			t.Errorf("\n%s\ngot unexpected credential: \n%s", name, diff)
+		}
+	}
+}
+
+func TestAssumeRole(t *testing.T) {
+	var query url.Values
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_ = r.ParseForm()
+		query = r.Form
+		if r.Form.Get("RoleArn") == "acs:ram::123456789012:role/denied" {
+			w.WriteHeader(http.StatusForbidden)
+			_, _ = w.Write([]byte(`{"RequestId":"req","Code":"NoPermission","Message":"denied"}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"RequestId":"req","Credentials":{"AccessKeyId":"STS.id","AccessKeySecret":"sts-secret","SecurityToken":"sts-token","Expiration":"2030-01-01T00:00:00Z"}}`))
+	}))
+	defer server.Close()
+	serverURL, _ := url.Parse(server.URL)
+
+	// the AssumeRole requests of the signer are sent to the mocked STS instead of the STS in the region
+	stsClient, err := sdk.NewClientWithAccessKey("cn-beijing", accessKeyID, accessKeySecret)
+	if err != nil {
+		t.Fatal(err)
+	}
+	commonAPI := func(request *requests.CommonRequest, signer interface{}) (*responses.CommonResponse, error) {
+		if request.Domain != "sts.cn-beijing.aliyuncs.com" {
+			return nil, fmt.Errorf("unexpected STS domain %s", request.Domain)
+		}
+		request.Scheme, request.Domain = serverURL.Scheme, serverURL.Host
+		return stsClient.ProcessCommonRequestWithSigner(request, signer)
+	}
+
+	testCases := map[string]struct {
+		roleARN   string
+		expectErr bool
+	}{
+		"AssumeRole": {
+			roleARN: "acs:ram::123456789012:role/kusion",
+		},
+		"AssumeRoleDenied": {
+			roleARN:   "acs:ram::123456789012:role/denied",
+			expectErr: true,
+		},
+	}
+
+	for name, tc := range testCases {
+		query = nil
+		credential := getCredential(&v1.AlicloudProvider{Region: "cn-beijing", RoleARN: tc.roleARN})
+		signer, err := auth.NewSignerWithCredential(credential, commonAPI)
+		if err != nil {
+			t.Fatalf("\n%s\ngot unexpected error: %v", name, err)
+		}
+		stsAccessKeyID, err := signer.GetAccessKeyId()
+		if (err != nil) != tc.expectErr {
+			t.Errorf("\n%s\ngot unexpected error: %v", name, err)
+		}
+		if tc.expectErr {
+			continue
+		}
+		if stsAccessKeyID != "STS.id" || signer.GetExtraParam()["SecurityToken"] != "sts-token" {
+			t.Errorf("\n%s\ngot unexpected STS token of access key %s", name, stsAccessKeyID)
+		}
+		if query.Get("Action") != "AssumeRole" || query.Get("RoleArn") != tc.roleARN ||
+			query.Get("RoleSessionName") != defaultRoleSessionName {
+			t.Errorf("\n%s\ngot unexpected AssumeRole request: %v", name, query)
+		}
+	}
+}
+
 // EquateErrors returns true if the supplied errors are of the same type and
 // produce same error message.
 func EquateErrors() cmp.Option {
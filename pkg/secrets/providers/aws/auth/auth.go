@@ -5,6 +5,8 @@ import (
 
 	awsv2 "github.com/aws/aws-sdk-go-v2/aws"
 	awsv2cfg "github.com/aws/aws-sdk-go-v2/config"
+	awsv1 "github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/session"
 )
 
 // NewV2Config returns an aws.Config for AWS SDK v2, using the default options.
@@ -19,3 +21,16 @@ func NewV2Config(ctx context.Context, region, profile string) (awsv2.Config, err
 
 	return awsv2cfg.LoadDefaultConfig(ctx, optFns...)
 }
+
+// NewV1Session returns a session.Session for AWS SDK v1 with the same region and profile options
+// as NewV2Config, for the services which are only available in AWS SDK v1.
+func NewV1Session(region, profile string) (*session.Session, error) {
+	opts := session.Options{
+		SharedConfigState: session.SharedConfigEnable,
+		Profile:           profile,
+	}
+	if region != "" {
+		opts.Config.Region = awsv1.String(region)
+	}
+	return session.NewSessionWithOptions(opts)
+}
@@ -0,0 +1,44 @@
+package fake
+
+import (
+	"bytes"
+	"context"
+	"errors"
+
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/service/kms"
+)
+
+// KMSClient is a fake AWS KMS client with a single key, whose encrypted data key is the plaintext
+// data key prefixed with the key ARN. The calls are counted to verify the caching.
+type KMSClient struct {
+	KeyARN string
+
+	GenerateDataKeyCalls int
+	DecryptCalls         int
+}
+
+func (c *KMSClient) GenerateDataKeyWithContext(_ context.Context, input *kms.GenerateDataKeyInput, _ ...request.Option) (*kms.GenerateDataKeyOutput, error) {
+	c.GenerateDataKeyCalls++
+	if *input.KeyId != c.KeyARN {
+		return nil, &kms.NotFoundException{}
+	}
+	plaintext := bytes.Repeat([]byte{byte(c.GenerateDataKeyCalls)}, 32)
+	return &kms.GenerateDataKeyOutput{
+		KeyId:          input.KeyId,
+		Plaintext:      plaintext,
+		CiphertextBlob: append([]byte(c.KeyARN), plaintext...),
+	}, nil
+}
+
+func (c *KMSClient) DecryptWithContext(_ context.Context, input *kms.DecryptInput, _ ...request.Option) (*kms.DecryptOutput, error) {
+	c.DecryptCalls++
+	plaintext, ok := bytes.CutPrefix(input.CiphertextBlob, []byte(c.KeyARN))
+	if *input.KeyId != c.KeyARN || !ok {
+		return nil, errors.New("InvalidCiphertextException")
+	}
+	return &kms.DecryptOutput{
+		KeyId:     input.KeyId,
+		Plaintext: plaintext,
+	}, nil
+}
@@ -0,0 +1,146 @@
+package kms
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/kms"
+
+	"kusionstack.io/kusion/pkg/apis/api.kusion.io/v1"
+	"kusionstack.io/kusion/pkg/secrets/keyprovider"
+	"kusionstack.io/kusion/pkg/secrets/providers/aws/auth"
+)
+
+const (
+	errMissingAWSProvider    = "invalid provider spec. Missing AWS provider spec"
+	errEmptyKeyARN           = "empty ARN of AWS KMS key"
+	errFailedToCreateSession = "failed to create usable AWS session: %w"
+	errGenerateDataKey       = "failed to generate data key with AWS KMS key %s: %w"
+	errDecryptDataKey        = "failed to decrypt data key with AWS KMS key %s: %w"
+)
+
+// KeyProvider should implement the keyprovider.KeyProvider interface
+var _ keyprovider.KeyProvider = &KeyProvider{}
+
+// KeyProvider is a keyprovider.KeyProvider backed by AWS KMS. To limit the KMS calls, a data key is
+// generated by the KMS key once and cached, which encrypts the plaintexts locally with AES-GCM, and the
+// decrypted data keys are cached as well. The KeyProvider is supposed to be constructed per operation,
+// e.g. an apply, so that the cached data keys are not kept for long.
+type KeyProvider struct {
+	keyARN string
+	client Client
+
+	mu sync.Mutex
+	// the data key to encrypt, with the plaintext and the ciphertext encrypted by the KMS key
+	plainDataKey, encryptedDataKey []byte
+	// the decrypted data keys, whose key is the ciphertext of the data key
+	decryptedDataKeys map[string][]byte
+}
+
+// NewKeyProvider constructs an AWS KMS based key provider with the region and profile of the AWS
+// provider spec, and the ARN of the KMS key.
+func NewKeyProvider(spec *v1.AWSProvider, keyARN string) (*KeyProvider, error) {
+	if spec == nil {
+		return nil, errors.New(errMissingAWSProvider)
+	}
+	if keyARN == "" {
+		return nil, errors.New(errEmptyKeyARN)
+	}
+
+	sess, err := auth.NewV1Session(spec.Region, spec.Profile)
+	if err != nil {
+		return nil, fmt.Errorf(errFailedToCreateSession, err)
+	}
+	return newKeyProvider(kms.New(sess), keyARN), nil
+}
+
+func newKeyProvider(client Client, keyARN string) *KeyProvider {
+	return &KeyProvider{
+		keyARN:            keyARN,
+		client:            client,
+		decryptedDataKeys: map[string][]byte{},
+	}
+}
+
+func (p *KeyProvider) KeyID() string {
+	return p.keyARN
+}
+
+// Encrypt encrypts the plaintext with the cached data key, and the returned ciphertext is composed of
+// the length of the encrypted data key in two bytes, the encrypted data key, and the encrypted plaintext.
+func (p *KeyProvider) Encrypt(ctx context.Context, plaintext []byte) ([]byte, error) {
+	plainDataKey, encryptedDataKey, err := p.dataKey(ctx)
+	if err != nil {
+		return nil, err
+	}
+	encrypted, err := keyprovider.AESGCMEncrypt(plainDataKey, plaintext)
+	if err != nil {
+		return nil, err
+	}
+
+	ciphertext := binary.BigEndian.AppendUint16(nil, uint16(len(encryptedDataKey)))
+	ciphertext = append(ciphertext, encryptedDataKey...)
+	return append(ciphertext, encrypted...), nil
+}
+
+// Decrypt decrypts the ciphertext returned by Encrypt, where the data key is decrypted by the KMS key
+// of the specified key id if not cached.
+func (p *KeyProvider) Decrypt(ctx context.Context, keyID string, ciphertext []byte) ([]byte, error) {
+	if len(ciphertext) < 2 {
+		return nil, fmt.Errorf("%w, ciphertext is too short", keyprovider.ErrDecryptFailed)
+	}
+	keyLen := int(binary.BigEndian.Uint16(ciphertext))
+	if len(ciphertext) < 2+keyLen {
+		return nil, fmt.Errorf("%w, ciphertext is too short", keyprovider.ErrDecryptFailed)
+	}
+	encryptedDataKey, encrypted := ciphertext[2:2+keyLen], ciphertext[2+keyLen:]
+
+	plainDataKey, err := p.decryptDataKey(ctx, keyID, encryptedDataKey)
+	if err != nil {
+		return nil, err
+	}
+	return keyprovider.AESGCMDecrypt(plainDataKey, encrypted)
+}
+
+// dataKey returns the cached data key, and generates one by the KMS key if not cached.
+func (p *KeyProvider) dataKey(ctx context.Context) ([]byte, []byte, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.plainDataKey == nil {
+		output, err := p.client.GenerateDataKeyWithContext(ctx, &kms.GenerateDataKeyInput{
+			KeyId:   aws.String(p.keyARN),
+			KeySpec: aws.String(kms.DataKeySpecAes256),
+		})
+		if err != nil {
+			return nil, nil, fmt.Errorf(errGenerateDataKey, p.keyARN, err)
+		}
+		p.plainDataKey, p.encryptedDataKey = output.Plaintext, output.CiphertextBlob
+		p.decryptedDataKeys[string(output.CiphertextBlob)] = output.Plaintext
+	}
+	return p.plainDataKey, p.encryptedDataKey, nil
+}
+
+// decryptDataKey returns the plaintext of the encrypted data key, which is decrypted by the KMS key if
+// not cached.
+func (p *KeyProvider) decryptDataKey(ctx context.Context, keyID string, encryptedDataKey []byte) ([]byte, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if plainDataKey, ok := p.decryptedDataKeys[string(encryptedDataKey)]; ok {
+		return plainDataKey, nil
+	}
+	output, err := p.client.DecryptWithContext(ctx, &kms.DecryptInput{
+		KeyId:          aws.String(keyID),
+		CiphertextBlob: encryptedDataKey,
+	})
+	if err != nil {
+		return nil, fmt.Errorf(errDecryptDataKey, keyID, fmt.Errorf("%w, %v", keyprovider.ErrDecryptFailed, err))
+	}
+	p.decryptedDataKeys[string(encryptedDataKey)] = output.Plaintext
+	return output.Plaintext, nil
+}
@@ -0,0 +1,92 @@
+package kms
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"kusionstack.io/kusion/pkg/apis/api.kusion.io/v1"
+	"kusionstack.io/kusion/pkg/secrets/keyprovider"
+	"kusionstack.io/kusion/pkg/secrets/providers/aws/kms/fake"
+)
+
+const mockKeyARN = "arn:aws:kms:us-east-1:123456789012:key/kusion"
+
+func TestNewKeyProvider(t *testing.T) {
+	testCases := map[string]struct {
+		spec      *v1.AWSProvider
+		keyARN    string
+		expectErr bool
+	}{
+		"NewKeyProvider": {
+			spec:   &v1.AWSProvider{Region: "us-east-1"},
+			keyARN: mockKeyARN,
+		},
+		"NewKeyProvider_Missing_Spec": {
+			keyARN:    mockKeyARN,
+			expectErr: true,
+		},
+		"NewKeyProvider_Empty_KeyARN": {
+			spec:      &v1.AWSProvider{Region: "us-east-1"},
+			expectErr: true,
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			p, err := NewKeyProvider(tc.spec, tc.keyARN)
+			assert.Equal(t, tc.expectErr, err != nil)
+			if !tc.expectErr {
+				assert.Equal(t, tc.keyARN, p.KeyID())
+			}
+		})
+	}
+}
+
+func TestKeyProvider(t *testing.T) {
+	t.Run("seal and open with cached data key", func(t *testing.T) {
+		client := &fake.KMSClient{KeyARN: mockKeyARN}
+		p := newKeyProvider(client, mockKeyARN)
+
+		var sealed []*v1.EncryptedData
+		for _, plaintext := range []string{"password-1", "password-2", "password-3"} {
+			data, err := keyprovider.Seal(context.Background(), p, []byte(plaintext))
+			require.NoError(t, err)
+			assert.Equal(t, mockKeyARN, data.KeyID)
+			sealed = append(sealed, data)
+		}
+		for i, data := range sealed {
+			plaintext, err := keyprovider.Open(context.Background(), p, data)
+			require.NoError(t, err)
+			assert.Equal(t, []string{"password-1", "password-2", "password-3"}[i], string(plaintext))
+		}
+		assert.Equal(t, 1, client.GenerateDataKeyCalls)
+		assert.Equal(t, 0, client.DecryptCalls)
+
+		// a new key provider decrypts the data key by KMS only once
+		another := newKeyProvider(client, mockKeyARN)
+		for _, data := range sealed {
+			_, err := keyprovider.Open(context.Background(), another, data)
+			require.NoError(t, err)
+		}
+		assert.Equal(t, 1, client.DecryptCalls)
+	})
+
+	t.Run("failed to decrypt with wrong key", func(t *testing.T) {
+		p := newKeyProvider(&fake.KMSClient{KeyARN: mockKeyARN}, mockKeyARN)
+		data, err := keyprovider.Seal(context.Background(), p, []byte("password"))
+		require.NoError(t, err)
+
+		wrong := newKeyProvider(&fake.KMSClient{KeyARN: "arn:aws:kms:us-east-1:123456789012:key/other"}, "arn:aws:kms:us-east-1:123456789012:key/other")
+		_, err = keyprovider.Open(context.Background(), wrong, data)
+		assert.ErrorIs(t, err, keyprovider.ErrDecryptFailed)
+	})
+
+	t.Run("failed to generate data key with unknown key", func(t *testing.T) {
+		p := newKeyProvider(&fake.KMSClient{KeyARN: mockKeyARN}, "arn:aws:kms:us-east-1:123456789012:key/other")
+		_, err := p.Encrypt(context.Background(), []byte("password"))
+		assert.Error(t, err)
+	})
+}
@@ -9,8 +9,12 @@ import (
 
 type (
 	GetSecretValueFn     func(ctx context.Context, params *secretsmanager.GetSecretValueInput, optFns ...func(*secretsmanager.Options)) (*secretsmanager.GetSecretValueOutput, error)
+	PutSecretValueFn     func(ctx context.Context, params *secretsmanager.PutSecretValueInput, optFns ...func(*secretsmanager.Options)) (*secretsmanager.PutSecretValueOutput, error)
+	CreateSecretFn       func(ctx context.Context, params *secretsmanager.CreateSecretInput, optFns ...func(*secretsmanager.Options)) (*secretsmanager.CreateSecretOutput, error)
 	SecretsManagerClient struct {
 		GetSecretValueFn GetSecretValueFn
+		PutSecretValueFn PutSecretValueFn
+		CreateSecretFn   CreateSecretFn
 	}
 )
 
@@ -35,3 +39,11 @@ func NewGetSecretValueFn(secretData interface{}, dataType string, err error) Get
 func (sc *SecretsManagerClient) GetSecretValue(ctx context.Context, params *secretsmanager.GetSecretValueInput, optFns ...func(*secretsmanager.Options)) (*secretsmanager.GetSecretValueOutput, error) {
 	return sc.GetSecretValueFn(ctx, params, optFns...)
 }
+
+func (sc *SecretsManagerClient) PutSecretValue(ctx context.Context, params *secretsmanager.PutSecretValueInput, optFns ...func(*secretsmanager.Options)) (*secretsmanager.PutSecretValueOutput, error) {
+	return sc.PutSecretValueFn(ctx, params, optFns...)
+}
+
+func (sc *SecretsManagerClient) CreateSecret(ctx context.Context, params *secretsmanager.CreateSecretInput, optFns ...func(*secretsmanager.Options)) (*secretsmanager.CreateSecretOutput, error) {
+	return sc.CreateSecretFn(ctx, params, optFns...)
+}
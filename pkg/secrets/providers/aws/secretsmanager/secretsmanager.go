@@ -8,11 +8,12 @@ import (
 
 	"github.com/aws/aws-sdk-go-v2/service/secretsmanager"
 	"github.com/aws/aws-sdk-go-v2/service/secretsmanager/types"
-	"github.com/tidwall/gjson"
+	"github.com/aws/smithy-go"
 
 	"kusionstack.io/kusion/pkg/apis/api.kusion.io/v1"
 	"kusionstack.io/kusion/pkg/secrets"
 	"kusionstack.io/kusion/pkg/secrets/providers/aws/auth"
+	"kusionstack.io/kusion/pkg/util/kerrors"
 )
 
 const (
@@ -27,6 +28,9 @@ var _ secrets.SecretStoreProvider = &DefaultSecretStoreProvider{}
 // smSecretStore should implement the secrets.SecretStore interface
 var _ secrets.SecretStore = &smSecretStore{}
 
+// smSecretStore should implement the secrets.SecretWriter interface
+var _ secrets.SecretWriter = &smSecretStore{}
+
 type DefaultSecretStoreProvider struct{}
 
 // NewSecretStore constructs a Vault based secret store with specific secret store spec.
@@ -61,6 +65,10 @@ func (s *smSecretStore) GetSecret(ctx context.Context, ref v1.ExternalSecretRef)
 	if errors.As(err, &nf) {
 		return nil, nil
 	}
+	var apiErr smithy.APIError
+	if errors.As(err, &apiErr) && apiErr.ErrorCode() == "AccessDeniedException" {
+		return nil, kerrors.Wrap(kerrors.ErrPermissionDenied, err)
+	}
 	if err != nil {
 		return nil, err
 	}
@@ -73,11 +81,45 @@ func (s *smSecretStore) GetSecret(ctx context.Context, ref v1.ExternalSecretRef)
 		}
 		return nil, fmt.Errorf("invalid secret data. no secret string nor binary for key: %s", ref.Name)
 	}
-	val := s.convertSecretToGjson(secretValueOutput, ref.Property)
-	if !val.Exists() {
-		return nil, fmt.Errorf("key %s does not exist in secret %s", ref.Property, ref.Name)
+	val, err := secrets.ExtractProperty(secretPayload(secretValueOutput), ref.Property)
+	if err != nil {
+		return nil, fmt.Errorf("%w in secret %s", err, ref.Name)
+	}
+	return val, nil
+}
+
+// SetSecret writes ref secret value to AWS Secrets Manager as the current version, where the secret is
+// created if not found. The value is set as the key of the property in the current secret string if the
+// property is not empty.
+func (s *smSecretStore) SetSecret(ctx context.Context, ref v1.ExternalSecretRef, value []byte) error {
+	payload := value
+	if ref.Property != "" {
+		current, err := s.GetSecret(ctx, v1.ExternalSecretRef{Name: ref.Name})
+		if err != nil {
+			return err
+		}
+		if payload, err = secrets.SetProperty(current, ref.Property, value); err != nil {
+			return err
+		}
+	}
+
+	secretString := string(payload)
+	_, err := s.client.PutSecretValue(ctx, &secretsmanager.PutSecretValueInput{
+		SecretId:     &ref.Name,
+		SecretString: &secretString,
+	})
+	var nf *types.ResourceNotFoundException
+	if errors.As(err, &nf) {
+		_, err = s.client.CreateSecret(ctx, &secretsmanager.CreateSecretInput{
+			Name:         &ref.Name,
+			SecretString: &secretString,
+		})
+	}
+	var apiErr smithy.APIError
+	if errors.As(err, &apiErr) && apiErr.ErrorCode() == "AccessDeniedException" {
+		return kerrors.Wrap(kerrors.ErrPermissionDenied, err)
 	}
-	return []byte(val.String()), nil
+	return err
 }
 
 // buildGetSecretValueInput constructs target GetSecretValueInput request with specific external secret ref.
@@ -102,27 +144,15 @@ func (s *smSecretStore) buildGetSecretValueInput(ref v1.ExternalSecretRef) *secr
 	return getSecretValueInput
 }
 
-func (s *smSecretStore) convertSecretToGjson(secretValueOutput *secretsmanager.GetSecretValueOutput, refProperty string) gjson.Result {
-	var payload string
-	if secretValueOutput.SecretString != nil {
-		payload = *secretValueOutput.SecretString
-	}
+// secretPayload returns the secret string or binary of the secret value.
+func secretPayload(secretValueOutput *secretsmanager.GetSecretValueOutput) []byte {
 	if secretValueOutput.SecretBinary != nil {
-		payload = string(secretValueOutput.SecretBinary)
+		return secretValueOutput.SecretBinary
 	}
-
-	// We need to search if a given key with a . exists before using gjson operations.
-	idx := strings.Index(refProperty, ".")
-	currentRefProperty := refProperty
-	if idx > -1 {
-		currentRefProperty = strings.ReplaceAll(refProperty, ".", "\\.")
-		val := gjson.Get(payload, currentRefProperty)
-		if !val.Exists() {
-			currentRefProperty = refProperty
-		}
+	if secretValueOutput.SecretString != nil {
+		return []byte(*secretValueOutput.SecretString)
 	}
-
-	return gjson.Get(payload, currentRefProperty)
+	return nil
 }
 
 func init() {
@@ -7,10 +7,15 @@ import (
 	"reflect"
 	"testing"
 
+	"github.com/aws/aws-sdk-go-v2/service/secretsmanager"
+	"github.com/aws/aws-sdk-go-v2/service/secretsmanager/types"
+	"github.com/aws/smithy-go"
 	"github.com/google/go-cmp/cmp"
 
 	"kusionstack.io/kusion/pkg/apis/api.kusion.io/v1"
+	"kusionstack.io/kusion/pkg/secrets"
 	"kusionstack.io/kusion/pkg/secrets/providers/aws/secretsmanager/fake"
+	"kusionstack.io/kusion/pkg/util/kerrors"
 )
 
 func TestGetSecret(t *testing.T) {
@@ -107,7 +112,7 @@ func TestGetSecret(t *testing.T) {
 			name:      "/beep",
 			property:  "foobar.baz",
 			expected:  nil,
-			expectErr: fmt.Errorf("key foobar.baz does not exist in secret /beep"),
+			expectErr: fmt.Errorf("%w in secret /beep", fmt.Errorf("%w: foobar.baz", secrets.ErrPropertyNotFound)),
 		},
 	}
 
@@ -132,6 +137,60 @@ func TestGetSecret(t *testing.T) {
 	}
 }
 
+func TestGetSecretPermissionDenied(t *testing.T) {
+	store := &smSecretStore{client: &fake.SecretsManagerClient{
+		GetSecretValueFn: fake.NewGetSecretValueFn("t0p-Secret", "string", &smithy.GenericAPIError{
+			Code:    "AccessDeniedException",
+			Message: "not authorized to perform secretsmanager:GetSecretValue",
+		}),
+	}}
+	_, err := store.GetSecret(context.TODO(), v1.ExternalSecretRef{Name: "/beep"})
+	if !kerrors.IsPermissionDenied(err) {
+		t.Errorf("expected permission denied error, got: %v", err)
+	}
+}
+
+func TestSetSecret(t *testing.T) {
+	var created, put *string
+	client := &fake.SecretsManagerClient{
+		GetSecretValueFn: fake.NewGetSecretValueFn(`{"username":"admin"}`, "string", nil),
+		PutSecretValueFn: func(_ context.Context, params *secretsmanager.PutSecretValueInput, _ ...func(*secretsmanager.Options)) (*secretsmanager.PutSecretValueOutput, error) {
+			if *params.SecretId == "/missing" {
+				return nil, &types.ResourceNotFoundException{}
+			}
+			put = params.SecretString
+			return &secretsmanager.PutSecretValueOutput{}, nil
+		},
+		CreateSecretFn: func(_ context.Context, params *secretsmanager.CreateSecretInput, _ ...func(*secretsmanager.Options)) (*secretsmanager.CreateSecretOutput, error) {
+			created = params.SecretString
+			return &secretsmanager.CreateSecretOutput{}, nil
+		},
+	}
+	store := &smSecretStore{client: client}
+
+	if err := store.SetSecret(context.TODO(), v1.ExternalSecretRef{Name: "/beep", Property: "password"}, []byte("t0p-Secret")); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if put == nil || *put != `{"password":"t0p-Secret","username":"admin"}` {
+		t.Errorf("unexpected secret put: %v", put)
+	}
+
+	if err := store.SetSecret(context.TODO(), v1.ExternalSecretRef{Name: "/missing"}, []byte("t0p-Secret")); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if created == nil || *created != "t0p-Secret" {
+		t.Errorf("unexpected secret created: %v", created)
+	}
+
+	client.PutSecretValueFn = func(_ context.Context, _ *secretsmanager.PutSecretValueInput, _ ...func(*secretsmanager.Options)) (*secretsmanager.PutSecretValueOutput, error) {
+		return nil, &smithy.GenericAPIError{Code: "AccessDeniedException"}
+	}
+	err := store.SetSecret(context.TODO(), v1.ExternalSecretRef{Name: "/beep"}, []byte("t0p-Secret"))
+	if !kerrors.IsPermissionDenied(err) {
+		t.Errorf("expected permission denied error, got: %v", err)
+	}
+}
+
 func TestNewSecretStore(t *testing.T) {
 	testCases := map[string]struct {
 		spec        v1.SecretStore
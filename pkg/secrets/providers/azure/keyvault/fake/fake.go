@@ -6,6 +6,7 @@ import (
 	"os"
 
 	"github.com/Azure/azure-sdk-for-go/profiles/latest/keyvault/keyvault"
+	"github.com/Azure/go-autorest/autorest"
 )
 
 type (
@@ -75,3 +76,42 @@ func newJSONWebKey(b []byte) *keyvault.JSONWebKey {
 	}
 	return &key
 }
+
+// CredentialFactory records the last authorizer built and its arguments, whose key is the argument name.
+type CredentialFactory struct {
+	AuthType string
+	Args     map[string]string
+}
+
+func (cf *CredentialFactory) ClientSecretAuthorizer(clientID, clientSecret, tenantID, resource, aadEndpoint string) (autorest.Authorizer, error) {
+	cf.AuthType = "ClientSecret"
+	cf.Args = map[string]string{
+		"clientID":     clientID,
+		"clientSecret": clientSecret,
+		"tenantID":     tenantID,
+		"resource":     resource,
+		"aadEndpoint":  aadEndpoint,
+	}
+	return autorest.NullAuthorizer{}, nil
+}
+
+func (cf *CredentialFactory) ManagedIdentityAuthorizer(clientID, resource string) (autorest.Authorizer, error) {
+	cf.AuthType = "ManagedIdentity"
+	cf.Args = map[string]string{
+		"clientID": clientID,
+		"resource": resource,
+	}
+	return autorest.NullAuthorizer{}, nil
+}
+
+func (cf *CredentialFactory) WorkloadIdentityAuthorizer(clientID, tenantID, tokenFile, resource, aadEndpoint string) (autorest.Authorizer, error) {
+	cf.AuthType = "WorkloadIdentity"
+	cf.Args = map[string]string{
+		"clientID":    clientID,
+		"tenantID":    tenantID,
+		"tokenFile":   tokenFile,
+		"resource":    resource,
+		"aadEndpoint": aadEndpoint,
+	}
+	return autorest.NullAuthorizer{}, nil
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 
 	"github.com/Azure/azure-sdk-for-go/profiles/latest/keyvault/keyvault"
+	"github.com/Azure/go-autorest/autorest"
 )
 
 // SecretClient is a testable interface for making operations call for Azure KeyVault.
@@ -12,3 +13,10 @@ type SecretClient interface {
 	GetKey(ctx context.Context, vaultBaseURL string, keyName string, keyVersion string) (result keyvault.KeyBundle, err error)
 	GetCertificate(ctx context.Context, vaultBaseURL string, certificateName string, certificateVersion string) (result keyvault.CertificateBundle, err error)
 }
+
+// CredentialFactory is a testable interface for building the authorizers of each auth type to access Azure.
+type CredentialFactory interface {
+	ClientSecretAuthorizer(clientID, clientSecret, tenantID, resource, aadEndpoint string) (autorest.Authorizer, error)
+	ManagedIdentityAuthorizer(clientID, resource string) (autorest.Authorizer, error)
+	WorkloadIdentityAuthorizer(clientID, tenantID, tokenFile, resource, aadEndpoint string) (autorest.Authorizer, error)
+}
@@ -3,30 +3,38 @@ package keyvault
 import (
 	"context"
 	"encoding/json"
+	"encoding/pem"
 	"fmt"
+	"net/http"
 	"os"
 	"strings"
 
 	"github.com/Azure/azure-sdk-for-go/profiles/latest/keyvault/keyvault"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/cloud"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/policy"
+	"github.com/Azure/azure-sdk-for-go/sdk/azidentity"
 	"github.com/Azure/go-autorest/autorest"
 	"github.com/Azure/go-autorest/autorest/azure"
 	"github.com/Azure/go-autorest/autorest/azure/auth"
-	"github.com/tidwall/gjson"
 
 	"kusionstack.io/kusion/pkg/apis/api.kusion.io/v1"
 	"kusionstack.io/kusion/pkg/secrets"
 )
 
 const (
-	defaultObjType           = "secret"
-	objectTypeCert           = "cert"
-	objectTypeKey            = "key"
-	errMissingProviderSpec   = "store spec is missing provider"
-	errMissingAzureProvider  = "invalid provider spec. Missing Azure field in store provider spec"
-	errMissingTenant         = "missing tenantID in store provider spec"
-	errMissingClientIDSecret = "cannot read clientID/clientSecret from environment variables"
-	errPropertyNotExist      = "property %s does not exist in key %s"
-	errUnknownObjectType     = "unknown Azure KeyVault object Type for %s"
+	defaultObjType              = "secret"
+	objectTypeCert              = "cert"
+	objectTypeCertificate       = "certificate"
+	objectTypeKey               = "key"
+	errMissingProviderSpec      = "store spec is missing provider"
+	errMissingAzureProvider     = "invalid provider spec. Missing Azure field in store provider spec"
+	errMissingTenant            = "missing tenantID in store provider spec"
+	errMissingClientIDSecret    = "cannot read clientID/clientSecret from environment variables"
+	errMissingClientIDTokenFile = "cannot read clientID/tokenFile of the workload identity from store provider spec or environment variables"
+	errUnknownAuthType          = "unknown Azure auth type %s"
+	errUnknownObjectType        = "unknown Azure KeyVault object Type for %s"
+	errEmptyCertificate         = "empty content of Azure KeyVault certificate %s"
 )
 
 // DefaultSecretStoreProvider should implement the secrets.SecretStoreProvider interface
@@ -35,7 +43,10 @@ var _ secrets.SecretStoreProvider = &DefaultSecretStoreProvider{}
 // kvSecretStore should implement the secrets.SecretStore interface
 var _ secrets.SecretStore = &kvSecretStore{}
 
-type DefaultSecretStoreProvider struct{}
+type DefaultSecretStoreProvider struct {
+	// credentials builds the authorizers to access Azure, defaults to defaultCredentialFactory.
+	credentials CredentialFactory
+}
 
 // NewSecretStore constructs an Azure KeyVault based secret store with specific secret store spec.
 func (p *DefaultSecretStoreProvider) NewSecretStore(spec *v1.SecretStore) (secrets.SecretStore, error) {
@@ -47,15 +58,19 @@ func (p *DefaultSecretStoreProvider) NewSecretStore(spec *v1.SecretStore) (secre
 		return nil, fmt.Errorf(errMissingAzureProvider)
 	}
 
-	secretClient, err := getSecretClient(providerSpec.Azure)
+	credentials := p.credentials
+	if credentials == nil {
+		credentials = defaultCredentialFactory{}
+	}
+	secretClient, err := getSecretClient(providerSpec.Azure, credentials)
 	if err != nil {
 		return nil, err
 	}
 	return &kvSecretStore{secretClient, providerSpec.Azure}, nil
 }
 
-func getSecretClient(spec *v1.AzureKVProvider) (SecretClient, error) {
-	authorizer, err := authorizerForServicePrincipal(spec)
+func getSecretClient(spec *v1.AzureKVProvider, credentials CredentialFactory) (SecretClient, error) {
+	authorizer, err := newAuthorizer(spec, credentials)
 	if err != nil {
 		return nil, err
 	}
@@ -64,26 +79,126 @@ func getSecretClient(spec *v1.AzureKVProvider) (SecretClient, error) {
 	return client, nil
 }
 
+// newAuthorizer returns the authorizer used by clients to access to Azure, selected by the auth type of the
+// spec. The EnvironmentType drives the AAD endpoint and the KeyVault resource of all the auth types.
+func newAuthorizer(spec *v1.AzureKVProvider, credentials CredentialFactory) (autorest.Authorizer, error) {
+	auth := spec.Auth
+	if auth == nil {
+		auth = &v1.AzureKVAuth{}
+	}
+	clientID := auth.ClientID
+	if clientID == "" {
+		clientID = os.Getenv("AZURE_CLIENT_ID")
+	}
+	resource := kvResourceForProviderConfig(spec.EnvironmentType)
+	aadEndpoint := adEndpointForEnvironmentType(spec.EnvironmentType)
+
+	switch auth.Type {
+	case "", v1.AzureAuthClientSecret:
+		return authorizerForServicePrincipal(spec, clientID, credentials)
+	case v1.AzureAuthManagedIdentity:
+		return credentials.ManagedIdentityAuthorizer(clientID, resource)
+	case v1.AzureAuthWorkloadIdentity:
+		if spec.TenantID == nil {
+			return nil, fmt.Errorf(errMissingTenant)
+		}
+		tokenFile := auth.TokenFile
+		if tokenFile == "" {
+			tokenFile = os.Getenv("AZURE_FEDERATED_TOKEN_FILE")
+		}
+		if clientID == "" || tokenFile == "" {
+			return nil, fmt.Errorf(errMissingClientIDTokenFile)
+		}
+		return credentials.WorkloadIdentityAuthorizer(clientID, *spec.TenantID, tokenFile, resource, aadEndpoint)
+	default:
+		return nil, fmt.Errorf(errUnknownAuthType, auth.Type)
+	}
+}
+
 // authorizerForServicePrincipal returns a service principal based authorizer used by clients to access to Azure.
 // By-default it uses credentials from the environment;
 // See https://docs.microsoft.com/en-us/go/azure/azure-sdk-go-authorization#use-environment-based-authentication.
-func authorizerForServicePrincipal(spec *v1.AzureKVProvider) (autorest.Authorizer, error) {
+func authorizerForServicePrincipal(spec *v1.AzureKVProvider, clientID string, credentials CredentialFactory) (autorest.Authorizer, error) {
 	if spec.TenantID == nil {
 		return nil, fmt.Errorf(errMissingTenant)
 	}
 
-	clientID := os.Getenv("AZURE_CLIENT_ID")
 	clientSecret := os.Getenv("AZURE_CLIENT_SECRET")
 	if clientID == "" || clientSecret == "" {
 		return nil, fmt.Errorf(errMissingClientIDSecret)
 	}
 
-	clientCredentialsConfig := auth.NewClientCredentialsConfig(clientID, clientSecret, *spec.TenantID)
-	clientCredentialsConfig.Resource = kvResourceForProviderConfig(spec.EnvironmentType)
-	clientCredentialsConfig.AADEndpoint = adEndpointForEnvironmentType(spec.EnvironmentType)
+	return credentials.ClientSecretAuthorizer(clientID, clientSecret, *spec.TenantID,
+		kvResourceForProviderConfig(spec.EnvironmentType), adEndpointForEnvironmentType(spec.EnvironmentType))
+}
+
+// defaultCredentialFactory builds the authorizers with the Azure SDK.
+type defaultCredentialFactory struct{}
+
+func (defaultCredentialFactory) ClientSecretAuthorizer(clientID, clientSecret, tenantID, resource, aadEndpoint string) (autorest.Authorizer, error) {
+	clientCredentialsConfig := auth.NewClientCredentialsConfig(clientID, clientSecret, tenantID)
+	clientCredentialsConfig.Resource = resource
+	clientCredentialsConfig.AADEndpoint = aadEndpoint
 	return clientCredentialsConfig.Authorizer()
 }
 
+func (defaultCredentialFactory) ManagedIdentityAuthorizer(clientID, resource string) (autorest.Authorizer, error) {
+	msiConfig := auth.NewMSIConfig()
+	msiConfig.Resource = resource
+	msiConfig.ClientID = clientID
+	return msiConfig.Authorizer()
+}
+
+// WorkloadIdentityAuthorizer exchanges the federated token in the token file for the access token with
+// the workload identity credential, which re-reads the token file rotated by AKS and refreshes the access
+// token before it expires.
+func (defaultCredentialFactory) WorkloadIdentityAuthorizer(clientID, tenantID, tokenFile, resource, aadEndpoint string) (autorest.Authorizer, error) {
+	credential, err := azidentity.NewWorkloadIdentityCredential(&azidentity.WorkloadIdentityCredentialOptions{
+		ClientOptions: azcore.ClientOptions{
+			Cloud: cloud.Configuration{ActiveDirectoryAuthorityHost: aadEndpoint},
+		},
+		ClientID:      clientID,
+		TenantID:      tenantID,
+		TokenFilePath: tokenFile,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return newTokenCredentialAuthorizer(credential, resource), nil
+}
+
+// tokenCredentialAuthorizer authorizes the requests of the autorest clients with the access tokens of an
+// azcore.TokenCredential, which caches the token and refreshes it before it expires.
+type tokenCredentialAuthorizer struct {
+	credential azcore.TokenCredential
+	scopes     []string
+}
+
+func newTokenCredentialAuthorizer(credential azcore.TokenCredential, resource string) *tokenCredentialAuthorizer {
+	return &tokenCredentialAuthorizer{
+		credential: credential,
+		scopes:     []string{resource + "/.default"},
+	}
+}
+
+// WithAuthorization returns a PrepareDecorator that adds the bearer token got from the credential on each
+// request.
+func (a *tokenCredentialAuthorizer) WithAuthorization() autorest.PrepareDecorator {
+	return func(p autorest.Preparer) autorest.Preparer {
+		return autorest.PreparerFunc(func(r *http.Request) (*http.Request, error) {
+			r, err := p.Prepare(r)
+			if err != nil {
+				return r, err
+			}
+			token, err := a.credential.GetToken(r.Context(), policy.TokenRequestOptions{Scopes: a.scopes})
+			if err != nil {
+				return r, fmt.Errorf("failed to get the access token of the workload identity: %w", err)
+			}
+			return autorest.Prepare(r, autorest.WithBearerAuthorization(token.Token))
+		})
+	}
+}
+
 func adEndpointForEnvironmentType(t v1.AzureEnvironmentType) string {
 	switch t {
 	case v1.AzureEnvironmentPublicCloud:
@@ -123,7 +238,7 @@ type kvSecretStore struct {
 
 // GetSecret retrieves ref secret value from Azure KeyVault.
 func (k *kvSecretStore) GetSecret(ctx context.Context, ref v1.ExternalSecretRef) ([]byte, error) {
-	objectType, secretName := getObjType(ref)
+	objectType, secretName := getObjType(ref, k.provider.ObjectType)
 
 	switch objectType {
 	case defaultObjType:
@@ -134,14 +249,21 @@ func (k *kvSecretStore) GetSecret(ctx context.Context, ref v1.ExternalSecretRef)
 			return nil, err
 		}
 		return getProperty(*secretResp.Value, ref.Property, ref.Name)
-	case objectTypeCert:
-		// returns a CertBundle. We return CER contents of x509 certificate
+	case objectTypeCert, objectTypeCertificate:
+		// returns a CertBundle. We return CER contents of x509 certificate for the cert type, and the
+		// PEM encoded certificate for the certificate type.
 		// see: https://pkg.go.dev/github.com/Azure/azure-sdk-for-go/services/keyvault/v7.0/keyvault#CertificateBundle
 		certResp, err := k.secretClient.GetCertificate(ctx, *k.provider.VaultURL, secretName, ref.Version)
 		if err != nil {
 			return nil, err
 		}
-		return *certResp.Cer, nil
+		if certResp.Cer == nil {
+			return nil, fmt.Errorf(errEmptyCertificate, secretName)
+		}
+		if objectType == objectTypeCert {
+			return *certResp.Cer, nil
+		}
+		return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: *certResp.Cer}), nil
 	case objectTypeKey:
 		// returns a KeyBundle that contains a WebKey
 		// see: https://pkg.go.dev/github.com/Azure/azure-sdk-for-go/services/keyvault/v7.0/keyvault#KeyBundle
@@ -157,27 +279,20 @@ func (k *kvSecretStore) GetSecret(ctx context.Context, ref v1.ExternalSecretRef)
 
 // Retrieves a property value if specified and the secret value if not.
 func getProperty(secret, property, key string) ([]byte, error) {
-	if property == "" {
-		return []byte(secret), nil
-	}
-	res := gjson.Get(secret, property)
-	if !res.Exists() {
-		idx := strings.Index(property, ".")
-		if idx < 0 {
-			return nil, fmt.Errorf(errPropertyNotExist, property, key)
-		}
-		escaped := strings.ReplaceAll(property, ".", "\\.")
-		jValue := gjson.Get(secret, escaped)
-		if jValue.Exists() {
-			return []byte(jValue.String()), nil
-		}
-		return nil, fmt.Errorf(errPropertyNotExist, property, key)
+	val, err := secrets.ExtractProperty([]byte(secret), property)
+	if err != nil {
+		return nil, fmt.Errorf("%w in key %s", err, key)
 	}
-	return []byte(res.String()), nil
+	return val, nil
 }
 
-func getObjType(ref v1.ExternalSecretRef) (string, string) {
+// getObjType returns the object type and name of the ref, where the type prefixed to the name takes
+// precedence over the default type of the provider.
+func getObjType(ref v1.ExternalSecretRef, defaultType v1.AzureKVObjectType) (string, string) {
 	objectType := defaultObjType
+	if defaultType != "" {
+		objectType = string(defaultType)
+	}
 
 	secretName := ref.Name
 	nameSlice := strings.Split(ref.Name, "/")
@@ -4,12 +4,18 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"net/http"
+	"path/filepath"
 	"reflect"
 	"testing"
 
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/policy"
+	"github.com/Azure/go-autorest/autorest"
 	"github.com/google/go-cmp/cmp"
 
 	"kusionstack.io/kusion/pkg/apis/api.kusion.io/v1"
+	"kusionstack.io/kusion/pkg/secrets"
 	"kusionstack.io/kusion/pkg/secrets/providers/azure/keyvault/fake"
 )
 
@@ -53,7 +59,7 @@ func TestGetSecret(t *testing.T) {
 			},
 			name:      "test-secret",
 			property:  "barr",
-			expectErr: fmt.Errorf(errPropertyNotExist, "barr", "test-secret"),
+			expectErr: fmt.Errorf("%w in key test-secret", fmt.Errorf("%w: barr", secrets.ErrPropertyNotFound)),
 		},
 		"GetKey": {
 			client: &fake.SecretClient{
@@ -181,3 +187,240 @@ func EquateErrors() cmp.Option {
 		return a.Error() == b.Error()
 	})
 }
+
+func TestNewSecretStoreCredentials(t *testing.T) {
+	testCases := map[string]struct {
+		provider     v1.AzureKVProvider
+		env          map[string]string
+		expectedType string
+		expectedArgs map[string]string
+		expectedErr  error
+	}{
+		"DefaultClientSecret": {
+			provider:     v1.AzureKVProvider{VaultURL: &fakeVaultURL, TenantID: &fakeTenantID},
+			env:          map[string]string{"AZURE_CLIENT_ID": "env_client_id", "AZURE_CLIENT_SECRET": "env_client_secret"},
+			expectedType: "ClientSecret",
+			expectedArgs: map[string]string{
+				"clientID":     "env_client_id",
+				"clientSecret": "env_client_secret",
+				"tenantID":     fakeTenantID,
+				"resource":     "https://vault.azure.net",
+				"aadEndpoint":  "https://login.microsoftonline.com/",
+			},
+		},
+		"ClientSecretInChinaCloud": {
+			provider: v1.AzureKVProvider{
+				VaultURL:        &fakeVaultURL,
+				TenantID:        &fakeTenantID,
+				EnvironmentType: v1.AzureEnvironmentChinaCloud,
+				Auth:            &v1.AzureKVAuth{Type: v1.AzureAuthClientSecret, ClientID: "spec_client_id"},
+			},
+			env:          map[string]string{"AZURE_CLIENT_ID": "env_client_id", "AZURE_CLIENT_SECRET": "env_client_secret"},
+			expectedType: "ClientSecret",
+			expectedArgs: map[string]string{
+				"clientID":     "spec_client_id",
+				"clientSecret": "env_client_secret",
+				"tenantID":     fakeTenantID,
+				"resource":     "https://vault.azure.cn",
+				"aadEndpoint":  "https://login.chinacloudapi.cn/",
+			},
+		},
+		"ManagedIdentity": {
+			provider: v1.AzureKVProvider{
+				VaultURL: &fakeVaultURL,
+				Auth:     &v1.AzureKVAuth{Type: v1.AzureAuthManagedIdentity, ClientID: "identity_client_id"},
+			},
+			expectedType: "ManagedIdentity",
+			expectedArgs: map[string]string{
+				"clientID": "identity_client_id",
+				"resource": "https://vault.azure.net",
+			},
+		},
+		"WorkloadIdentityFromEnv": {
+			provider: v1.AzureKVProvider{
+				VaultURL:        &fakeVaultURL,
+				TenantID:        &fakeTenantID,
+				EnvironmentType: v1.AzureEnvironmentUSGovernmentCloud,
+				Auth:            &v1.AzureKVAuth{Type: v1.AzureAuthWorkloadIdentity},
+			},
+			env: map[string]string{
+				"AZURE_CLIENT_ID":            "env_client_id",
+				"AZURE_FEDERATED_TOKEN_FILE": "/var/run/secrets/azure/tokens/azure-identity-token",
+			},
+			expectedType: "WorkloadIdentity",
+			expectedArgs: map[string]string{
+				"clientID":    "env_client_id",
+				"tenantID":    fakeTenantID,
+				"tokenFile":   "/var/run/secrets/azure/tokens/azure-identity-token",
+				"resource":    "https://vault.usgovcloudapi.net",
+				"aadEndpoint": "https://login.microsoftonline.us/",
+			},
+		},
+		"WorkloadIdentityFromSpec": {
+			provider: v1.AzureKVProvider{
+				VaultURL: &fakeVaultURL,
+				TenantID: &fakeTenantID,
+				Auth: &v1.AzureKVAuth{
+					Type:      v1.AzureAuthWorkloadIdentity,
+					ClientID:  "spec_client_id",
+					TokenFile: "/tmp/token",
+				},
+			},
+			expectedType: "WorkloadIdentity",
+			expectedArgs: map[string]string{
+				"clientID":    "spec_client_id",
+				"tenantID":    fakeTenantID,
+				"tokenFile":   "/tmp/token",
+				"resource":    "https://vault.azure.net",
+				"aadEndpoint": "https://login.microsoftonline.com/",
+			},
+		},
+		"WorkloadIdentityMissingTokenFile": {
+			provider: v1.AzureKVProvider{
+				VaultURL: &fakeVaultURL,
+				TenantID: &fakeTenantID,
+				Auth:     &v1.AzureKVAuth{Type: v1.AzureAuthWorkloadIdentity, ClientID: "spec_client_id"},
+			},
+			expectedErr: errors.New(errMissingClientIDTokenFile),
+		},
+		"UnknownAuthType": {
+			provider: v1.AzureKVProvider{
+				VaultURL: &fakeVaultURL,
+				TenantID: &fakeTenantID,
+				Auth:     &v1.AzureKVAuth{Type: "Certificate"},
+			},
+			expectedErr: fmt.Errorf(errUnknownAuthType, "Certificate"),
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			for _, key := range []string{"AZURE_CLIENT_ID", "AZURE_CLIENT_SECRET", "AZURE_FEDERATED_TOKEN_FILE"} {
+				t.Setenv(key, tc.env[key])
+			}
+			credentials := &fake.CredentialFactory{}
+			factory := DefaultSecretStoreProvider{credentials: credentials}
+			_, err := factory.NewSecretStore(&v1.SecretStore{
+				Provider: &v1.ProviderSpec{Azure: &tc.provider},
+			})
+			if diff := cmp.Diff(err, tc.expectedErr, EquateErrors()); diff != "" {
+				t.Errorf("\n%s\ngot unexpected error:\n%s", name, diff)
+			}
+			if diff := cmp.Diff(credentials.AuthType, tc.expectedType); diff != "" {
+				t.Errorf("\n%s\ngot unexpected auth type:\n%s", name, diff)
+			}
+			if diff := cmp.Diff(credentials.Args, tc.expectedArgs); diff != "" {
+				t.Errorf("\n%s\ngot unexpected credential args:\n%s", name, diff)
+			}
+		})
+	}
+}
+
+// countingCredential returns a new access token on each call, as a credential refreshing the token does.
+type countingCredential struct {
+	calls  int
+	scopes []string
+}
+
+func (c *countingCredential) GetToken(_ context.Context, options policy.TokenRequestOptions) (azcore.AccessToken, error) {
+	c.calls++
+	c.scopes = options.Scopes
+	return azcore.AccessToken{Token: fmt.Sprintf("token-%d", c.calls)}, nil
+}
+
+func TestTokenCredentialAuthorizer(t *testing.T) {
+	credential := &countingCredential{}
+	authorizer := newTokenCredentialAuthorizer(credential, "https://vault.azure.net")
+
+	for _, expected := range []string{"Bearer token-1", "Bearer token-2"} {
+		req, err := autorest.Prepare(&http.Request{Header: http.Header{}}, authorizer.WithAuthorization())
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if diff := cmp.Diff(req.Header.Get("Authorization"), expected); diff != "" {
+			t.Errorf("got unexpected authorization header:\n%s", diff)
+		}
+	}
+	if diff := cmp.Diff(credential.scopes, []string{"https://vault.azure.net/.default"}); diff != "" {
+		t.Errorf("got unexpected scopes:\n%s", diff)
+	}
+}
+
+func TestWorkloadIdentityAuthorizer(t *testing.T) {
+	// the token file is read on getting the token rather than building the authorizer, so that the token
+	// rotated by AKS is picked up
+	tokenFile := filepath.Join(t.TempDir(), "token")
+	authorizer, err := defaultCredentialFactory{}.WorkloadIdentityAuthorizer("client_id", fakeTenantID, tokenFile,
+		"https://vault.azure.net", "https://login.microsoftonline.com/")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, ok := authorizer.(*tokenCredentialAuthorizer); !ok {
+		t.Errorf("expected the token credential authorizer, got %T", authorizer)
+	}
+}
+
+func TestGetSecretObjectType(t *testing.T) {
+	client := &fake.SecretClient{
+		GetSecretFn:      fake.NewGetSecretFn("t0p-Secret"),
+		GetKeyFn:         fake.NewGetKeyFn(jwkPubRSA),
+		GetCertificateFn: fake.NewGetCertificateFn("certificate_value"),
+	}
+	pemCertificate := "-----BEGIN CERTIFICATE-----\nY2VydGlmaWNhdGVfdmFsdWU=\n-----END CERTIFICATE-----\n"
+
+	testCases := map[string]struct {
+		objectType v1.AzureKVObjectType
+		name       string
+		expected   []byte
+		expectErr  error
+	}{
+		"DefaultSecret": {
+			name:     "test-secret",
+			expected: []byte("t0p-Secret"),
+		},
+		"DefaultCertificate": {
+			objectType: v1.AzureKVObjectCertificate,
+			name:       "test-cert",
+			expected:   []byte(pemCertificate),
+		},
+		"DefaultKey": {
+			objectType: v1.AzureKVObjectKey,
+			name:       "test-key",
+			expected:   []byte(jwkPubRSA),
+		},
+		"CertificatePrefix": {
+			name:     "certificate/test-cert",
+			expected: []byte(pemCertificate),
+		},
+		"SecretPrefixOverridesDefault": {
+			objectType: v1.AzureKVObjectCertificate,
+			name:       "secret/test-secret",
+			expected:   []byte("t0p-Secret"),
+		},
+		"UnknownObjectType": {
+			objectType: "blob",
+			name:       "test-blob",
+			expectErr:  fmt.Errorf(errUnknownObjectType, "test-blob"),
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			store := &kvSecretStore{
+				secretClient: client,
+				provider: &v1.AzureKVProvider{
+					VaultURL:   &fakeVaultURL,
+					TenantID:   &fakeTenantID,
+					ObjectType: tc.objectType,
+				},
+			}
+			actual, err := store.GetSecret(context.TODO(), v1.ExternalSecretRef{Name: tc.name})
+			if diff := cmp.Diff(err, tc.expectErr, EquateErrors()); diff != "" {
+				t.Errorf("\n%s\ngot unexpected error:\n%s", name, diff)
+			}
+			if diff := cmp.Diff(string(actual), string(tc.expected)); diff != "" {
+				t.Errorf("\n%s\nget unexpected data: \n%s", name, diff)
+			}
+		})
+	}
+}
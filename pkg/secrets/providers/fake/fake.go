@@ -4,8 +4,6 @@ import (
 	"context"
 	"fmt"
 
-	"github.com/tidwall/gjson"
-
 	v1 "kusionstack.io/kusion/pkg/apis/api.kusion.io/v1"
 	"kusionstack.io/kusion/pkg/secrets"
 )
@@ -27,6 +25,12 @@ var _ secrets.SecretStoreProvider = &DefaultSecretStoreProvider{}
 // smSecretStore should implement the secrets.SecretStore interface
 var _ secrets.SecretStore = &fakeSecretStore{}
 
+// fakeSecretStore should implement the secrets.ConnectionChecker interface
+var _ secrets.ConnectionChecker = &fakeSecretStore{}
+
+// fakeSecretStore should implement the secrets.SecretWriter interface
+var _ secrets.SecretWriter = &fakeSecretStore{}
+
 type DefaultSecretStoreProvider struct{}
 
 // NewSecretStore constructs a fake secret store instance.
@@ -65,16 +69,30 @@ func (f *fakeSecretStore) GetSecret(_ context.Context, ref v1.ExternalSecretRef)
 		return nil, secrets.NoSecretErr
 	}
 
-	if ref.Property != "" {
-		val := gjson.Get(data.Value, ref.Property)
-		if !val.Exists() {
-			return nil, secrets.NoSecretErr
-		}
+	return secrets.ExtractProperty([]byte(data.Value), ref.Property)
+}
 
-		return []byte(val.String()), nil
+// SetSecret writes ref secret value to backend data map.
+func (f *fakeSecretStore) SetSecret(_ context.Context, ref v1.ExternalSecretRef, value []byte) error {
+	key := mapKey(ref.Name, ref.Version)
+	var payload []byte
+	if data, ok := f.dataMap[key]; ok {
+		payload = []byte(data.Value)
+	}
+	payload, err := secrets.SetProperty(payload, ref.Property, value)
+	if err != nil {
+		return err
 	}
+	f.dataMap[key] = &SecretData{
+		Value:   string(payload),
+		Version: ref.Version,
+	}
+	return nil
+}
 
-	return []byte(data.Value), nil
+// CheckConnection always succeeds, as the fake secret store has no backend.
+func (f *fakeSecretStore) CheckConnection(_ context.Context) error {
+	return nil
 }
 
 func mapKey(key, version string) string {
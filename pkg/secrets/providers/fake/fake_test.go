@@ -146,6 +146,84 @@ func TestNewSecretStore(t *testing.T) {
 	}
 }
 
+func TestSetSecret(t *testing.T) {
+	p := &DefaultSecretStoreProvider{}
+	store, err := p.NewSecretStore(&v1.SecretStore{
+		Provider: &v1.ProviderSpec{
+			Fake: &v1.FakeProvider{
+				Data: []v1.FakeProviderData{
+					{
+						Key:   "mysql",
+						Value: `{"username":"admin"}`,
+					},
+				},
+			},
+		},
+		AllowPush: true,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	testCases := []struct {
+		name     string
+		ref      v1.ExternalSecretRef
+		value    string
+		expected string
+	}{
+		{
+			name:     "create secret",
+			ref:      v1.ExternalSecretRef{Name: "api-token"},
+			value:    "t0ken",
+			expected: "t0ken",
+		},
+		{
+			name:     "overwrite secret",
+			ref:      v1.ExternalSecretRef{Name: "api-token"},
+			value:    "t0ken-2",
+			expected: "t0ken-2",
+		},
+		{
+			name:     "create secret version",
+			ref:      v1.ExternalSecretRef{Name: "api-token", Version: "v2"},
+			value:    "t0ken-v2",
+			expected: "t0ken-v2",
+		},
+		{
+			name:     "set property of existing secret",
+			ref:      v1.ExternalSecretRef{Name: "mysql", Property: "password"},
+			value:    "t0p-Secret",
+			expected: "t0p-Secret",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if err := secrets.PushSecret(context.Background(), &v1.SecretStore{AllowPush: true}, store, tc.ref, []byte(tc.value)); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			actual, err := store.GetSecret(context.Background(), tc.ref)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if diff := cmp.Diff(string(actual), tc.expected); diff != "" {
+				t.Errorf("got unexpected data:\n%s", diff)
+			}
+		})
+	}
+
+	// the other properties are kept
+	actual, err := store.GetSecret(context.Background(), v1.ExternalSecretRef{Name: "mysql", Property: "username"})
+	if err != nil || string(actual) != "admin" {
+		t.Errorf("expected the username kept, got %q, %v", actual, err)
+	}
+	// the other versions are kept
+	actual, err = store.GetSecret(context.Background(), v1.ExternalSecretRef{Name: "api-token"})
+	if err != nil || string(actual) != "t0ken-2" {
+		t.Errorf("expected the unversioned secret kept, got %q, %v", actual, err)
+	}
+}
+
 // EquateErrors returns true if the supplied errors are of the same type and
 // produce same error message.
 func EquateErrors() cmp.Option {
@@ -9,8 +9,10 @@ import (
 
 type (
 	ReadWithDataWithContextFn func(ctx context.Context, path string, data map[string][]string) (*vault.Secret, error)
+	WriteWithContextFn        func(ctx context.Context, path string, data map[string]interface{}) (*vault.Secret, error)
 	Logical                   struct {
 		ReadWithDataWithContextFn ReadWithDataWithContextFn
+		WriteWithContextFn        WriteWithContextFn
 	}
 )
 
@@ -30,6 +32,27 @@ func (f Logical) ReadWithDataWithContext(ctx context.Context, path string, data
 	return f.ReadWithDataWithContextFn(ctx, path, data)
 }
 
+type (
+	HealthWithContextFn func(ctx context.Context) (*vault.HealthResponse, error)
+	Sys                 struct {
+		HealthWithContextFn HealthWithContextFn
+	}
+)
+
+func NewHealthWithContextFn(health *vault.HealthResponse, err error) HealthWithContextFn {
+	return func(ctx context.Context) (*vault.HealthResponse, error) {
+		return health, err
+	}
+}
+
+func (f Sys) HealthWithContext(ctx context.Context) (*vault.HealthResponse, error) {
+	return f.HealthWithContextFn(ctx)
+}
+
+func (f Logical) WriteWithContext(ctx context.Context, path string, data map[string]interface{}) (*vault.Secret, error) {
+	return f.WriteWithContextFn(ctx, path, data)
+}
+
 func SetTokenInEnv() func() {
 	oldTokenVal := os.Getenv("VAULT_SERVER_TOKEN")
 	os.Setenv("VAULT_SERVER_TOKEN", "fake_token")
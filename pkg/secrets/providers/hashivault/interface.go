@@ -9,4 +9,10 @@ import (
 // Logical is a testable interface for performing logical backend operations on Vault.
 type Logical interface {
 	ReadWithDataWithContext(ctx context.Context, path string, data map[string][]string) (*vault.Secret, error)
+	WriteWithContext(ctx context.Context, path string, data map[string]interface{}) (*vault.Secret, error)
+}
+
+// Sys is a testable interface for performing system backend operations on Vault.
+type Sys interface {
+	HealthWithContext(ctx context.Context) (*vault.HealthResponse, error)
 }
@@ -0,0 +1,110 @@
+package hashivault
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"path"
+
+	vault "github.com/hashicorp/vault/api"
+
+	"kusionstack.io/kusion/pkg/secrets/keyprovider"
+)
+
+const (
+	defaultTransitMountPath = "transit"
+
+	errEmptyTransitKeyName = "empty key name of Vault Transit"
+	errTransitEncrypt      = "failed to encrypt with Vault Transit key %s: %w"
+	errTransitDecrypt      = "failed to decrypt with Vault Transit key %s: %w"
+	errTransitResponse     = "unexpected response of Vault Transit, missing field %s"
+)
+
+// TransitKeyProvider should implement the keyprovider.KeyProvider interface
+var _ keyprovider.KeyProvider = &TransitKeyProvider{}
+
+// TransitLogical is a testable interface for performing the Transit operations on Vault.
+type TransitLogical interface {
+	WriteWithContext(ctx context.Context, path string, data map[string]interface{}) (*vault.Secret, error)
+}
+
+// TransitKeyProvider is a keyprovider.KeyProvider backed by the Transit secrets engine of Vault, which
+// encrypts and decrypts with the named key through the encrypt and decrypt endpoints. The key id is the
+// name of the Transit key, and the key version is kept in the Vault ciphertext.
+type TransitKeyProvider struct {
+	mountPath string
+	keyName   string
+	logical   TransitLogical
+}
+
+// NewTransitKeyProvider constructs a Vault Transit based key provider. The token is read from the
+// environment variables VAULT_SERVER_TOKEN or VAULT_TOKEN, the same as the Vault secret store. The
+// mountPath defaults to "transit" if empty.
+func NewTransitKeyProvider(server, mountPath, keyName string) (*TransitKeyProvider, error) {
+	if keyName == "" {
+		return nil, errors.New(errEmptyTransitKeyName)
+	}
+	if mountPath == "" {
+		mountPath = defaultTransitMountPath
+	}
+
+	client, err := getVaultClient(server)
+	if err != nil {
+		return nil, err
+	}
+	return &TransitKeyProvider{
+		mountPath: mountPath,
+		keyName:   keyName,
+		logical:   client.Logical(),
+	}, nil
+}
+
+func (p *TransitKeyProvider) KeyID() string {
+	return p.keyName
+}
+
+// Encrypt encrypts the plaintext with the Transit key, and returns the Vault ciphertext, e.g. "vault:v1:xxx".
+func (p *TransitKeyProvider) Encrypt(ctx context.Context, plaintext []byte) ([]byte, error) {
+	secret, err := p.logical.WriteWithContext(ctx, path.Join(p.mountPath, "encrypt", p.keyName), map[string]interface{}{
+		"plaintext": base64.StdEncoding.EncodeToString(plaintext),
+	})
+	if err != nil {
+		return nil, fmt.Errorf(errTransitEncrypt, p.keyName, err)
+	}
+	ciphertext, err := getTransitField(secret, "ciphertext")
+	if err != nil {
+		return nil, fmt.Errorf(errTransitEncrypt, p.keyName, err)
+	}
+	return []byte(ciphertext), nil
+}
+
+// Decrypt decrypts the Vault ciphertext with the Transit key named keyID.
+func (p *TransitKeyProvider) Decrypt(ctx context.Context, keyID string, ciphertext []byte) ([]byte, error) {
+	secret, err := p.logical.WriteWithContext(ctx, path.Join(p.mountPath, "decrypt", keyID), map[string]interface{}{
+		"ciphertext": string(ciphertext),
+	})
+	if err != nil {
+		return nil, fmt.Errorf(errTransitDecrypt, keyID, fmt.Errorf("%w, %v", keyprovider.ErrDecryptFailed, err))
+	}
+	encoded, err := getTransitField(secret, "plaintext")
+	if err != nil {
+		return nil, fmt.Errorf(errTransitDecrypt, keyID, err)
+	}
+	plaintext, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, fmt.Errorf(errTransitDecrypt, keyID, err)
+	}
+	return plaintext, nil
+}
+
+func getTransitField(secret *vault.Secret, field string) (string, error) {
+	if secret == nil || secret.Data == nil {
+		return "", fmt.Errorf(errTransitResponse, field)
+	}
+	value, ok := secret.Data[field].(string)
+	if !ok {
+		return "", fmt.Errorf(errTransitResponse, field)
+	}
+	return value, nil
+}
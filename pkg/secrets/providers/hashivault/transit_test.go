@@ -0,0 +1,123 @@
+package hashivault
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"kusionstack.io/kusion/pkg/secrets/keyprovider"
+)
+
+const mockTransitToken = "fake-token"
+
+// newMockTransitServer mocks the encrypt and decrypt endpoints of Vault Transit with the key
+// "kusion", whose ciphertext is the base64 encoded plaintext with the prefix "vault:v1:".
+func newMockTransitServer(t *testing.T) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-Vault-Token") != mockTransitToken {
+			w.WriteHeader(http.StatusForbidden)
+			_, _ = w.Write([]byte(`{"errors":["permission denied"]}`))
+			return
+		}
+		body := map[string]string{}
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+
+		var data map[string]string
+		switch r.URL.Path {
+		case "/v1/transit/encrypt/kusion":
+			data = map[string]string{"ciphertext": "vault:v1:" + body["plaintext"]}
+		case "/v1/transit/decrypt/kusion":
+			plaintext, ok := strings.CutPrefix(body["ciphertext"], "vault:v1:")
+			if !ok {
+				w.WriteHeader(http.StatusBadRequest)
+				_, _ = w.Write([]byte(`{"errors":["invalid ciphertext"]}`))
+				return
+			}
+			data = map[string]string{"plaintext": plaintext}
+		default:
+			w.WriteHeader(http.StatusBadRequest)
+			_, _ = w.Write([]byte(`{"errors":["encryption key not found"]}`))
+			return
+		}
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{"data": data})
+	}))
+}
+
+func TestNewTransitKeyProvider(t *testing.T) {
+	testcases := []struct {
+		name      string
+		success   bool
+		mountPath string
+		keyName   string
+		expected  string
+	}{
+		{
+			name:     "new transit key provider with default mount path",
+			success:  true,
+			keyName:  "kusion",
+			expected: defaultTransitMountPath,
+		},
+		{
+			name:      "new transit key provider with custom mount path",
+			success:   true,
+			mountPath: "kusion-transit",
+			keyName:   "kusion",
+			expected:  "kusion-transit",
+		},
+		{
+			name:    "failed to new transit key provider empty key name",
+			success: false,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			p, err := NewTransitKeyProvider("http://127.0.0.1:8200", tc.mountPath, tc.keyName)
+			assert.Equal(t, tc.success, err == nil)
+			if tc.success {
+				assert.Equal(t, tc.expected, p.mountPath)
+				assert.Equal(t, tc.keyName, p.KeyID())
+			}
+		})
+	}
+}
+
+func TestTransitKeyProvider(t *testing.T) {
+	server := newMockTransitServer(t)
+	defer server.Close()
+	t.Setenv("VAULT_SERVER_TOKEN", mockTransitToken)
+
+	p, err := NewTransitKeyProvider(server.URL, "", "kusion")
+	require.NoError(t, err)
+
+	t.Run("seal and open successfully", func(t *testing.T) {
+		data, err := keyprovider.Seal(context.Background(), p, []byte("t0p-Secret"))
+		require.NoError(t, err)
+		assert.Equal(t, "kusion", data.KeyID)
+
+		plaintext, err := keyprovider.Open(context.Background(), p, data)
+		require.NoError(t, err)
+		assert.Equal(t, []byte("t0p-Secret"), plaintext)
+	})
+
+	t.Run("failed to decrypt with unknown key", func(t *testing.T) {
+		ciphertext, err := p.Encrypt(context.Background(), []byte("t0p-Secret"))
+		require.NoError(t, err)
+		_, err = p.Decrypt(context.Background(), "unknown", ciphertext)
+		assert.ErrorIs(t, err, keyprovider.ErrDecryptFailed)
+	})
+
+	t.Run("failed to encrypt without permission", func(t *testing.T) {
+		t.Setenv("VAULT_SERVER_TOKEN", "wrong-token")
+		wrong, err := NewTransitKeyProvider(server.URL, "", "kusion")
+		require.NoError(t, err)
+		_, err = wrong.Encrypt(context.Background(), []byte("t0p-Secret"))
+		assert.Error(t, err)
+	})
+}
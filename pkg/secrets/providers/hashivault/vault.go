@@ -11,7 +11,6 @@ import (
 	"strings"
 
 	vault "github.com/hashicorp/vault/api"
-	"github.com/tidwall/gjson"
 
 	"kusionstack.io/kusion/pkg/apis/api.kusion.io/v1"
 	"kusionstack.io/kusion/pkg/secrets"
@@ -24,8 +23,12 @@ const (
 	errJSONUnmarshall          = "failed to unmarshall JSON"
 	errUnexpectedKey           = "unexpected key in secret data: %s"
 	errDataPropertyFormat      = "unexpected data format %s for property field: %s"
-	errSecretFormat            = "cannot find property %s in secret data"
 	errBuildVaultClient        = "failed to new Vault client: %w"
+	errCheckVaultHealth        = "failed to check Vault health: %w"
+	errWriteSecret             = "failed to write secret data to Vault: %w"
+	errSecretNotJSONObject     = "secret data written to Vault must be a JSON object without property: %w"
+	errVaultNotInitialized     = "Vault is not initialized"
+	errVaultSealed             = "Vault is sealed"
 )
 
 // DefaultSecretStoreProvider should implement the secrets.SecretStoreProvider interface
@@ -34,6 +37,12 @@ var _ secrets.SecretStoreProvider = &DefaultSecretStoreProvider{}
 // vaultSecretStore should implement the secrets.SecretStore interface
 var _ secrets.SecretStore = &vaultSecretStore{}
 
+// vaultSecretStore should implement the secrets.ConnectionChecker interface
+var _ secrets.ConnectionChecker = &vaultSecretStore{}
+
+// vaultSecretStore should implement the secrets.SecretWriter interface
+var _ secrets.SecretWriter = &vaultSecretStore{}
+
 type DefaultSecretStoreProvider struct{}
 
 // NewSecretStore constructs a Vault based secret store with specific secret store spec.
@@ -52,6 +61,7 @@ func (p *DefaultSecretStoreProvider) NewSecretStore(spec *v1.SecretStore) (secre
 	store := vaultSecretStore{
 		provider: vaultSpec,
 		logical:  client.Logical(),
+		sys:      client.Sys(),
 	}
 	return &store, nil
 }
@@ -90,6 +100,23 @@ func getVaultToken() string {
 type vaultSecretStore struct {
 	provider *v1.VaultProvider
 	logical  Logical
+	sys      Sys
+}
+
+// CheckConnection checks the Vault server is reachable, initialized and unsealed by its health endpoint,
+// which reads no secret.
+func (v *vaultSecretStore) CheckConnection(ctx context.Context) error {
+	health, err := v.sys.HealthWithContext(ctx)
+	if err != nil {
+		return fmt.Errorf(errCheckVaultHealth, err)
+	}
+	if !health.Initialized {
+		return errors.New(errVaultNotInitialized)
+	}
+	if health.Sealed {
+		return errors.New(errVaultSealed)
+	}
+	return nil
 }
 
 // GetSecret retrieves ref secret value from Vault server.
@@ -112,12 +139,43 @@ func (v *vaultSecretStore) GetSecret(ctx context.Context, ref v1.ExternalSecretR
 		return getTypedKey(secretData, ref.Property)
 	}
 
-	// Then extract key from secret using gjson lib
-	val := gjson.Get(string(jsonStr), ref.Property)
-	if !val.Exists() {
-		return nil, fmt.Errorf(errSecretFormat, ref.Property)
+	// Then extract key from secret by the nested path
+	val, err := secrets.ExtractProperty(jsonStr, ref.Property)
+	if err != nil {
+		return nil, fmt.Errorf("%w in secret %s", err, ref.Name)
+	}
+	return val, nil
+}
+
+// SetSecret writes ref secret value to Vault server, where the value is set as the key of the property in
+// the existing secret data, or must be a JSON object to replace the secret data if the property is empty.
+func (v *vaultSecretStore) SetSecret(ctx context.Context, ref v1.ExternalSecretRef, value []byte) error {
+	var secretData map[string]interface{}
+	if ref.Property == "" {
+		if err := json.Unmarshal(value, &secretData); err != nil {
+			return fmt.Errorf(errSecretNotJSONObject, err)
+		}
+	} else {
+		existing, err := v.readSecret(ctx, ref.Name, "")
+		if err != nil {
+			return err
+		}
+		secretData = make(map[string]interface{}, len(existing)+1)
+		for key, val := range existing {
+			secretData[key] = val
+		}
+		secretData[ref.Property] = string(value)
+	}
+
+	if v.provider.Version == v1.VaultKVStoreV2 {
+		// Vault KV2 has data embedded within sub-field
+		// Ref: https://developer.hashicorp.com/vault/api-docs/secret/kv/kv-v2#create-update-secret
+		secretData = map[string]interface{}{"data": secretData}
+	}
+	if _, err := v.logical.WriteWithContext(ctx, v.buildPath(ref.Name), secretData); err != nil {
+		return fmt.Errorf(errWriteSecret, err)
 	}
-	return []byte(val.String()), nil
+	return nil
 }
 
 func (v *vaultSecretStore) readSecret(ctx context.Context, path, version string) (map[string]interface{}, error) {
@@ -9,6 +9,7 @@ import (
 	"testing"
 
 	"github.com/google/go-cmp/cmp"
+	vault "github.com/hashicorp/vault/api"
 
 	"kusionstack.io/kusion/pkg/apis/api.kusion.io/v1"
 	"kusionstack.io/kusion/pkg/secrets/providers/hashivault/fake"
@@ -306,6 +307,89 @@ func TestNewSecretStore(t *testing.T) {
 	}
 }
 
+func TestSetSecret(t *testing.T) {
+	var writtenPath string
+	var writtenData map[string]interface{}
+	logical := &fake.Logical{
+		ReadWithDataWithContextFn: fake.NewReadWithContextFn(map[string]interface{}{
+			"data": map[string]interface{}{"username": "admin"},
+		}, nil),
+		WriteWithContextFn: func(_ context.Context, path string, data map[string]interface{}) (*vault.Secret, error) {
+			writtenPath, writtenData = path, data
+			return nil, nil
+		},
+	}
+	store := makeValidVaultSecretStore(v1.VaultKVStoreV2)
+	store.logical = logical
+
+	err := store.SetSecret(context.Background(), makeExternalSecretRef("mysql", "password", ""), []byte("t0p-Secret"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected := map[string]interface{}{
+		"data": map[string]interface{}{"username": "admin", "password": "t0p-Secret"},
+	}
+	if writtenPath != "secret/data/mysql" {
+		t.Errorf("unexpected path written: %s", writtenPath)
+	}
+	if diff := cmp.Diff(writtenData, expected); diff != "" {
+		t.Errorf("got unexpected data written:\n%s", diff)
+	}
+
+	err = store.SetSecret(context.Background(), makeExternalSecretRef("mysql", "", ""), []byte(`{"password":"t0p-Secret"}`))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if diff := cmp.Diff(writtenData, map[string]interface{}{"data": map[string]interface{}{"password": "t0p-Secret"}}); diff != "" {
+		t.Errorf("got unexpected data written:\n%s", diff)
+	}
+
+	err = store.SetSecret(context.Background(), makeExternalSecretRef("mysql", "", ""), []byte("t0p-Secret"))
+	if err == nil {
+		t.Errorf("expected error of the secret data not a JSON object")
+	}
+}
+
+func TestCheckConnection(t *testing.T) {
+	testCases := map[string]struct {
+		sys         Sys
+		expectedErr error
+	}{
+		"Reachable": {
+			sys: &fake.Sys{
+				HealthWithContextFn: fake.NewHealthWithContextFn(&vault.HealthResponse{Initialized: true}, nil),
+			},
+			expectedErr: nil,
+		},
+		"Unreachable": {
+			sys: &fake.Sys{
+				HealthWithContextFn: fake.NewHealthWithContextFn(nil, errors.New("connection refused")),
+			},
+			expectedErr: fmt.Errorf(errCheckVaultHealth, errors.New("connection refused")),
+		},
+		"NotInitialized": {
+			sys: &fake.Sys{
+				HealthWithContextFn: fake.NewHealthWithContextFn(&vault.HealthResponse{}, nil),
+			},
+			expectedErr: errors.New(errVaultNotInitialized),
+		},
+		"Sealed": {
+			sys: &fake.Sys{
+				HealthWithContextFn: fake.NewHealthWithContextFn(&vault.HealthResponse{Initialized: true, Sealed: true}, nil),
+			},
+			expectedErr: errors.New(errVaultSealed),
+		},
+	}
+
+	for name, tc := range testCases {
+		store := &vaultSecretStore{sys: tc.sys}
+		err := store.CheckConnection(context.Background())
+		if diff := cmp.Diff(err, tc.expectedErr, EquateErrors()); diff != "" {
+			t.Errorf("\n%s\ngot unexpected error:\n%s", name, diff)
+		}
+	}
+}
+
 func TestGetVaultToken(t *testing.T) {
 	t.Run("Test Current Token Env Var", func(t *testing.T) {
 		cleanup := fake.SetTokenInEnv()
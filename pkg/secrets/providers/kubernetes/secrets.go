@@ -0,0 +1,113 @@
+package kubernetes
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	corev1client "k8s.io/client-go/kubernetes/typed/core/v1"
+	"k8s.io/client-go/tools/clientcmd"
+
+	v1 "kusionstack.io/kusion/pkg/apis/api.kusion.io/v1"
+	"kusionstack.io/kusion/pkg/secrets"
+	"kusionstack.io/kusion/pkg/util/kerrors"
+)
+
+const (
+	errMissingProviderSpec       = "store spec is missing provider"
+	errMissingKubernetesProvider = "invalid provider spec. Missing Kubernetes field in store provider spec"
+	errMissingNamespace          = "namespace of the Kubernetes provider must not be empty"
+	errFailedToCreateClient      = "failed to create Kubernetes client: %w"
+)
+
+// DefaultSecretStoreProvider should implement the secrets.SecretStoreProvider interface.
+var _ secrets.SecretStoreProvider = &DefaultSecretStoreProvider{}
+
+// k8sSecretStore should implement the secrets.SecretStore interface.
+var _ secrets.SecretStore = &k8sSecretStore{}
+
+// DefaultSecretStoreProvider implements the secrets.SecretStoreProvider interface.
+type DefaultSecretStoreProvider struct{}
+
+// k8sSecretStore implements the secrets.SecretStore interface.
+type k8sSecretStore struct {
+	client corev1client.SecretInterface
+}
+
+// NewSecretStore constructs a Kubernetes Secrets based secret store with specific secret store spec.
+func (p *DefaultSecretStoreProvider) NewSecretStore(spec *v1.SecretStore) (secrets.SecretStore, error) {
+	providerSpec := spec.Provider
+	if providerSpec == nil {
+		return nil, fmt.Errorf(errMissingProviderSpec)
+	}
+	if providerSpec.Kubernetes == nil {
+		return nil, fmt.Errorf(errMissingKubernetesProvider)
+	}
+	if providerSpec.Kubernetes.Namespace == "" {
+		return nil, fmt.Errorf(errMissingNamespace)
+	}
+
+	clientset, err := getKubernetesClient(providerSpec.Kubernetes.KubeConfig)
+	if err != nil {
+		return nil, fmt.Errorf(errFailedToCreateClient, err)
+	}
+	return &k8sSecretStore{
+		client: clientset.CoreV1().Secrets(providerSpec.Kubernetes.Namespace),
+	}, nil
+}
+
+// getKubernetesClient returns the clientset of the kubeconfig, or of the in-cluster config and the
+// default kubeconfig if empty.
+func getKubernetesClient(kubeConfig string) (kubernetes.Interface, error) {
+	loadingRules := clientcmd.NewDefaultClientConfigLoadingRules()
+	loadingRules.ExplicitPath = kubeConfig
+	cfg, err := clientcmd.NewNonInteractiveDeferredLoadingClientConfig(loadingRules, &clientcmd.ConfigOverrides{}).ClientConfig()
+	if err != nil {
+		return nil, err
+	}
+	return kubernetes.NewForConfig(cfg)
+}
+
+// GetSecret retrieves ref secret value from the Kubernetes Secret, whose data is base64-decoded. The data
+// of the key equal to the property is returned, and the whole data is returned in JSON if the property
+// is empty.
+func (k *k8sSecretStore) GetSecret(ctx context.Context, ref v1.ExternalSecretRef) ([]byte, error) {
+	secret, err := k.client.Get(ctx, ref.Name, metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		return nil, secrets.NoSecretErr
+	}
+	if apierrors.IsForbidden(err) || apierrors.IsUnauthorized(err) {
+		return nil, kerrors.Wrap(kerrors.ErrPermissionDenied, err)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if ref.Property != "" {
+		if val, ok := secret.Data[ref.Property]; ok {
+			return val, nil
+		}
+	}
+	data := make(map[string]string, len(secret.Data))
+	for key, val := range secret.Data {
+		data[key] = string(val)
+	}
+	payload, err := json.Marshal(data)
+	if err != nil {
+		return nil, err
+	}
+	val, err := secrets.ExtractProperty(payload, ref.Property)
+	if err != nil {
+		return nil, fmt.Errorf("%w in secret %s", err, ref.Name)
+	}
+	return val, nil
+}
+
+func init() {
+	secrets.Register(&DefaultSecretStoreProvider{}, &v1.ProviderSpec{
+		Kubernetes: &v1.KubernetesProvider{},
+	})
+}
@@ -0,0 +1,103 @@
+package kubernetes
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+
+	v1 "kusionstack.io/kusion/pkg/apis/api.kusion.io/v1"
+	"kusionstack.io/kusion/pkg/secrets"
+	"kusionstack.io/kusion/pkg/util/kerrors"
+)
+
+func TestGetSecret(t *testing.T) {
+	clientset := fake.NewSimpleClientset(&corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "mysql", Namespace: "secrets"},
+		Data: map[string][]byte{
+			"username":    []byte("admin"),
+			"password":    []byte("t0p-Secret"),
+			"config.json": []byte(`{"port":3306}`),
+		},
+	})
+	store := &k8sSecretStore{client: clientset.CoreV1().Secrets("secrets")}
+
+	testcases := []struct {
+		name     string
+		ref      v1.ExternalSecretRef
+		success  bool
+		expected string
+	}{
+		{
+			name:     "data key",
+			ref:      v1.ExternalSecretRef{Name: "mysql", Property: "password"},
+			success:  true,
+			expected: "t0p-Secret",
+		},
+		{
+			name:     "data key with dots",
+			ref:      v1.ExternalSecretRef{Name: "mysql", Property: "config.json"},
+			success:  true,
+			expected: `{"port":3306}`,
+		},
+		{
+			name:     "whole data",
+			ref:      v1.ExternalSecretRef{Name: "mysql"},
+			success:  true,
+			expected: `{"config.json":"{\"port\":3306}","password":"t0p-Secret","username":"admin"}`,
+		},
+		{
+			name:    "data key not found",
+			ref:     v1.ExternalSecretRef{Name: "mysql", Property: "token"},
+			success: false,
+		},
+		{
+			name:    "secret not found",
+			ref:     v1.ExternalSecretRef{Name: "redis", Property: "password"},
+			success: false,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			actual, err := store.GetSecret(context.Background(), tc.ref)
+			assert.Equal(t, tc.success, err == nil)
+			if tc.success {
+				assert.Equal(t, tc.expected, string(actual))
+			}
+		})
+	}
+
+	_, err := store.GetSecret(context.Background(), v1.ExternalSecretRef{Name: "redis"})
+	assert.ErrorIs(t, err, secrets.NoSecretErr)
+	_, err = store.GetSecret(context.Background(), v1.ExternalSecretRef{Name: "mysql", Property: "token"})
+	assert.ErrorIs(t, err, secrets.ErrPropertyNotFound)
+}
+
+func TestGetSecretForbidden(t *testing.T) {
+	clientset := fake.NewSimpleClientset()
+	clientset.PrependReactor("get", "secrets", func(k8stesting.Action) (bool, runtime.Object, error) {
+		return true, nil, apierrors.NewForbidden(corev1.Resource("secrets"), "mysql", errors.New("rbac"))
+	})
+	store := &k8sSecretStore{client: clientset.CoreV1().Secrets("secrets")}
+	_, err := store.GetSecret(context.Background(), v1.ExternalSecretRef{Name: "mysql"})
+	assert.True(t, kerrors.IsPermissionDenied(err))
+}
+
+func TestNewSecretStore(t *testing.T) {
+	p := &DefaultSecretStoreProvider{}
+
+	_, err := p.NewSecretStore(&v1.SecretStore{})
+	assert.EqualError(t, err, errMissingProviderSpec)
+	_, err = p.NewSecretStore(&v1.SecretStore{Provider: &v1.ProviderSpec{}})
+	assert.EqualError(t, err, errMissingKubernetesProvider)
+	_, err = p.NewSecretStore(&v1.SecretStore{Provider: &v1.ProviderSpec{Kubernetes: &v1.KubernetesProvider{}}})
+	assert.EqualError(t, err, errMissingNamespace)
+}
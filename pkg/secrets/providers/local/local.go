@@ -0,0 +1,191 @@
+package local
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync/atomic"
+
+	v1 "kusionstack.io/kusion/pkg/apis/api.kusion.io/v1"
+	"kusionstack.io/kusion/pkg/secrets"
+)
+
+const (
+	errMissingProviderSpec  = "store spec is missing provider"
+	errMissingLocalProvider = "invalid provider spec. Missing Local field in store provider spec"
+	errMissingSource        = "either dir or envPrefix of the Local provider must be provided"
+	errNonDevEnvironment    = "the Local provider is for local development only, but %s is %s"
+	errNotAllowed           = "the Local provider is for local development only, set %s=true to allow it"
+	errServerMode           = "the Local provider is for local development only, and not allowed in the Kusion server"
+	errInvalidSecretName    = "invalid secret name %s, which is out of the secret directory"
+)
+
+const (
+	// EnvKusionEnv is the environment variable of the environment kusion runs in, where the Local
+	// provider is refused unless it is empty or one of devEnvironments.
+	EnvKusionEnv = "KUSION_ENV"
+
+	// EnvAllowLocalSecrets is the environment variable to opt in the Local provider, which must be true
+	// to read the secrets from the local files or environment variables.
+	EnvAllowLocalSecrets = "KUSION_ALLOW_LOCAL_SECRETS"
+)
+
+// serverMode refuses the Local provider in the Kusion server, whose secrets must not be read from the
+// files or environment variables of the host.
+var serverMode atomic.Bool
+
+// SetServerMode refuses the Local provider in the process, which is called by the Kusion server on start.
+func SetServerMode() {
+	serverMode.Store(true)
+}
+
+var devEnvironments = map[string]bool{
+	"dev":         true,
+	"development": true,
+	"local":       true,
+}
+
+// DefaultSecretStoreProvider should implement the secrets.SecretStoreProvider interface.
+var _ secrets.SecretStoreProvider = &DefaultSecretStoreProvider{}
+
+// localSecretStore should implement the secrets.SecretStore interface.
+var _ secrets.SecretStore = &localSecretStore{}
+
+// DefaultSecretStoreProvider implements the secrets.SecretStoreProvider interface.
+type DefaultSecretStoreProvider struct{}
+
+// localSecretStore implements the secrets.SecretStore interface.
+type localSecretStore struct {
+	dir       string
+	envPrefix string
+}
+
+// NewSecretStore constructs a local secret store with specific secret store spec, which is refused in
+// the Kusion server and the non-dev environments, and unless opted in by EnvAllowLocalSecrets.
+func (p *DefaultSecretStoreProvider) NewSecretStore(spec *v1.SecretStore) (secrets.SecretStore, error) {
+	providerSpec := spec.Provider
+	if providerSpec == nil {
+		return nil, fmt.Errorf(errMissingProviderSpec)
+	}
+	if providerSpec.Local == nil {
+		return nil, fmt.Errorf(errMissingLocalProvider)
+	}
+	if serverMode.Load() {
+		return nil, errors.New(errServerMode)
+	}
+	if allowed, _ := strconv.ParseBool(os.Getenv(EnvAllowLocalSecrets)); !allowed {
+		return nil, fmt.Errorf(errNotAllowed, EnvAllowLocalSecrets)
+	}
+	if env := os.Getenv(EnvKusionEnv); env != "" && !devEnvironments[strings.ToLower(env)] {
+		return nil, fmt.Errorf(errNonDevEnvironment, EnvKusionEnv, env)
+	}
+	if providerSpec.Local.Dir == "" && providerSpec.Local.EnvPrefix == "" {
+		return nil, fmt.Errorf(errMissingSource)
+	}
+
+	return &localSecretStore{
+		dir:       providerSpec.Local.Dir,
+		envPrefix: providerSpec.Local.EnvPrefix,
+	}, nil
+}
+
+// GetSecret retrieves ref secret value from the file of the secret name in the directory, or the
+// environment variable of the secret name if the file does not exist. The property is resolved in the
+// JSON content, or the dotenv content otherwise.
+func (l *localSecretStore) GetSecret(_ context.Context, ref v1.ExternalSecretRef) ([]byte, error) {
+	data, err := l.readSecret(ref.Name)
+	if err != nil {
+		return nil, err
+	}
+	if ref.Property == "" || json.Valid(data) {
+		val, err := secrets.ExtractProperty(data, ref.Property)
+		if err != nil {
+			return nil, fmt.Errorf("%w in secret %s", err, ref.Name)
+		}
+		return val, nil
+	}
+
+	val, ok := parseDotenv(data)[ref.Property]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s in secret %s", secrets.ErrPropertyNotFound, ref.Property, ref.Name)
+	}
+	return []byte(val), nil
+}
+
+func (l *localSecretStore) readSecret(name string) ([]byte, error) {
+	if l.dir != "" {
+		path := filepath.Join(l.dir, filepath.FromSlash(name))
+		if rel, err := filepath.Rel(l.dir, path); err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+			return nil, fmt.Errorf(errInvalidSecretName, name)
+		}
+		data, err := os.ReadFile(path)
+		if err == nil {
+			return data, nil
+		}
+		if !errors.Is(err, fs.ErrNotExist) {
+			return nil, err
+		}
+	}
+	if l.envPrefix != "" {
+		if val, ok := os.LookupEnv(envName(l.envPrefix, name)); ok {
+			return []byte(val), nil
+		}
+	}
+	return nil, secrets.NoSecretErr
+}
+
+// envName returns the name of the environment variable of the secret, which is the prefix followed by
+// the secret name in upper case, with the characters other than letters and digits replaced by "_".
+func envName(prefix, name string) string {
+	return prefix + strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z':
+			return r - 'a' + 'A'
+		case r >= 'A' && r <= 'Z', r >= '0' && r <= '9':
+			return r
+		default:
+			return '_'
+		}
+	}, name)
+}
+
+// parseDotenv parses the KEY=VALUE lines of the dotenv content, where the blank lines and comments are
+// skipped, the export keyword is allowed, and the quoted values are unquoted.
+func parseDotenv(data []byte) map[string]string {
+	values := make(map[string]string)
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		key, val, ok := strings.Cut(strings.TrimPrefix(line, "export "), "=")
+		if !ok {
+			continue
+		}
+		key, val = strings.TrimSpace(key), strings.TrimSpace(val)
+		if len(val) >= 2 && (val[0] == '"' || val[0] == '\'') && val[len(val)-1] == val[0] {
+			if unquoted, err := strconv.Unquote(val); err == nil && val[0] == '"' {
+				val = unquoted
+			} else {
+				val = val[1 : len(val)-1]
+			}
+		}
+		values[key] = val
+	}
+	return values
+}
+
+func init() {
+	secrets.Register(&DefaultSecretStoreProvider{}, &v1.ProviderSpec{
+		Local: &v1.LocalProvider{},
+	})
+}
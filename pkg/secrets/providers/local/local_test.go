@@ -0,0 +1,148 @@
+package local
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	v1 "kusionstack.io/kusion/pkg/apis/api.kusion.io/v1"
+	"kusionstack.io/kusion/pkg/secrets"
+)
+
+func TestGetSecretFromFiles(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "api-token"), []byte("t0ken"), 0o600))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "mysql.json"), []byte(`{"db":{"password":"t0p-Secret"}}`), 0o600))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "mysql.env"), []byte(`# mysql
+export USERNAME=admin
+PASSWORD="t0p-Secret"
+`), 0o600))
+	store := &localSecretStore{dir: dir}
+
+	testcases := []struct {
+		name     string
+		ref      v1.ExternalSecretRef
+		success  bool
+		expected string
+	}{
+		{
+			name:     "whole file",
+			ref:      v1.ExternalSecretRef{Name: "api-token"},
+			success:  true,
+			expected: "t0ken",
+		},
+		{
+			name:     "json key",
+			ref:      v1.ExternalSecretRef{Name: "mysql.json", Property: "db.password"},
+			success:  true,
+			expected: "t0p-Secret",
+		},
+		{
+			name:     "dotenv key",
+			ref:      v1.ExternalSecretRef{Name: "mysql.env", Property: "USERNAME"},
+			success:  true,
+			expected: "admin",
+		},
+		{
+			name:     "quoted dotenv key",
+			ref:      v1.ExternalSecretRef{Name: "mysql.env", Property: "PASSWORD"},
+			success:  true,
+			expected: "t0p-Secret",
+		},
+		{
+			name:    "key not found",
+			ref:     v1.ExternalSecretRef{Name: "mysql.env", Property: "TOKEN"},
+			success: false,
+		},
+		{
+			name:    "file not found",
+			ref:     v1.ExternalSecretRef{Name: "redis"},
+			success: false,
+		},
+		{
+			name:    "out of the directory",
+			ref:     v1.ExternalSecretRef{Name: "../api-token"},
+			success: false,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			actual, err := store.GetSecret(context.Background(), tc.ref)
+			assert.Equal(t, tc.success, err == nil)
+			if tc.success {
+				assert.Equal(t, tc.expected, string(actual))
+			}
+		})
+	}
+
+	_, err := store.GetSecret(context.Background(), v1.ExternalSecretRef{Name: "redis"})
+	assert.ErrorIs(t, err, secrets.NoSecretErr)
+}
+
+func TestGetSecretFromEnv(t *testing.T) {
+	t.Setenv("SECRET_API_TOKEN", "t0ken")
+	t.Setenv("SECRET_MYSQL", `{"password":"t0p-Secret"}`)
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "api-token"), []byte("file-t0ken"), 0o600))
+
+	store := &localSecretStore{envPrefix: "SECRET_"}
+	actual, err := store.GetSecret(context.Background(), v1.ExternalSecretRef{Name: "api-token"})
+	assert.NoError(t, err)
+	assert.Equal(t, "t0ken", string(actual))
+
+	actual, err = store.GetSecret(context.Background(), v1.ExternalSecretRef{Name: "mysql", Property: "password"})
+	assert.NoError(t, err)
+	assert.Equal(t, "t0p-Secret", string(actual))
+
+	_, err = store.GetSecret(context.Background(), v1.ExternalSecretRef{Name: "redis"})
+	assert.ErrorIs(t, err, secrets.NoSecretErr)
+
+	// the file takes precedence over the environment variable
+	store = &localSecretStore{dir: dir, envPrefix: "SECRET_"}
+	actual, err = store.GetSecret(context.Background(), v1.ExternalSecretRef{Name: "api-token"})
+	assert.NoError(t, err)
+	assert.Equal(t, "file-t0ken", string(actual))
+	actual, err = store.GetSecret(context.Background(), v1.ExternalSecretRef{Name: "mysql", Property: "password"})
+	assert.NoError(t, err)
+	assert.Equal(t, "t0p-Secret", string(actual))
+}
+
+func TestNewSecretStore(t *testing.T) {
+	p := &DefaultSecretStoreProvider{}
+	spec := &v1.SecretStore{Provider: &v1.ProviderSpec{Local: &v1.LocalProvider{Dir: ".secrets"}}}
+
+	// the Local provider must be opted in
+	_, err := p.NewSecretStore(spec)
+	assert.EqualError(t, err, "the Local provider is for local development only, set KUSION_ALLOW_LOCAL_SECRETS=true to allow it")
+	t.Setenv(EnvAllowLocalSecrets, "false")
+	_, err = p.NewSecretStore(spec)
+	assert.Error(t, err)
+	t.Setenv(EnvAllowLocalSecrets, "true")
+
+	_, err = p.NewSecretStore(&v1.SecretStore{Provider: &v1.ProviderSpec{Local: &v1.LocalProvider{}}})
+	assert.EqualError(t, err, errMissingSource)
+
+	for _, env := range []string{"", "dev", "Local"} {
+		t.Setenv(EnvKusionEnv, env)
+		_, err = p.NewSecretStore(spec)
+		assert.NoError(t, err)
+	}
+
+	t.Setenv(EnvKusionEnv, "prod")
+	_, err = p.NewSecretStore(spec)
+	assert.EqualError(t, err, "the Local provider is for local development only, but KUSION_ENV is prod")
+
+	// the Local provider is refused in the Kusion server even if opted in
+	t.Setenv(EnvKusionEnv, "")
+	SetServerMode()
+	t.Cleanup(func() {
+		serverMode.Store(false)
+	})
+	_, err = p.NewSecretStore(spec)
+	assert.EqualError(t, err, errServerMode)
+}
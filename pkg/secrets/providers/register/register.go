@@ -8,5 +8,7 @@ import (
 	_ "kusionstack.io/kusion/pkg/secrets/providers/azure/keyvault"
 	_ "kusionstack.io/kusion/pkg/secrets/providers/fake"
 	_ "kusionstack.io/kusion/pkg/secrets/providers/hashivault"
+	_ "kusionstack.io/kusion/pkg/secrets/providers/kubernetes"
+	_ "kusionstack.io/kusion/pkg/secrets/providers/local"
 	_ "kusionstack.io/kusion/pkg/secrets/providers/viettelcloud/secretsmanager"
 )
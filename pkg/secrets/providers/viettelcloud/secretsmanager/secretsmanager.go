@@ -5,9 +5,6 @@ import (
 	"encoding/json"
 	"fmt"
 	"os"
-	"strings"
-
-	"github.com/tidwall/gjson"
 
 	"github.com/google/uuid"
 
@@ -108,36 +105,15 @@ func (s *smSecretStore) GetSecret(ctx context.Context, ref v1.ExternalSecretRef)
 	if secretResponse.JSON403 != nil {
 		return nil, fmt.Errorf("error response: %s", secretResponse.JSON403.Union)
 	}
-	val := s.convertSecretToGjson(secretResponse.JSON200, ref.Property)
-	if !val.Exists() {
-		return nil, fmt.Errorf("key %s does not exist in secret %s", ref.Property, ref.Name)
-	}
-	return []byte(val.String()), nil
-}
-
-func (s *smSecretStore) convertSecretToGjson(secretInfo *vclient.SecretRetrieve, refProperty string) gjson.Result {
-	var payload string
-	if secretInfo.Secret != nil {
-		// todo: handle nested secrets
-		data, err := json.Marshal(*secretInfo.Secret)
-		if err != nil {
-			return gjson.Result{}
-		}
-		payload = string(data)
+	payload, err := json.Marshal(*secretResponse.JSON200.Secret)
+	if err != nil {
+		return nil, err
 	}
-
-	// We need to search if a given key with a . exists before using gjson operations.
-	idx := strings.Index(refProperty, ".")
-	currentRefProperty := refProperty
-	if idx > -1 {
-		currentRefProperty = strings.ReplaceAll(refProperty, ".", "\\.")
-		val := gjson.Get(payload, currentRefProperty)
-		if !val.Exists() {
-			currentRefProperty = refProperty
-		}
+	val, err := secrets.ExtractProperty(payload, ref.Property)
+	if err != nil {
+		return nil, fmt.Errorf("%w in secret %s", err, ref.Name)
 	}
-
-	return gjson.Get(payload, currentRefProperty)
+	return val, nil
 }
 
 func init() {
@@ -9,6 +9,7 @@ import (
 
 	"github.com/google/go-cmp/cmp"
 	v1 "kusionstack.io/kusion/pkg/apis/api.kusion.io/v1"
+	"kusionstack.io/kusion/pkg/secrets"
 	"kusionstack.io/kusion/pkg/secrets/providers/viettelcloud/secretsmanager/fake"
 )
 
@@ -89,7 +90,7 @@ func TestGetSecret(t *testing.T) {
 			name:      "beep",
 			property:  "notfound",
 			expected:  []byte(``),
-			expectErr: fmt.Errorf("key notfound does not exist in secret beep"),
+			expectErr: fmt.Errorf("%w in secret beep", fmt.Errorf("%w: notfound", secrets.ErrPropertyNotFound)),
 		},
 		"GetSecret_With_NestedProperty_Error": {
 			client: &fake.SecretsManagerClient{
@@ -103,7 +104,7 @@ func TestGetSecret(t *testing.T) {
 			name:      "beep",
 			property:  "foo.baz",
 			expected:  []byte(``),
-			expectErr: fmt.Errorf("key foobar.baz does not exist in secret beep"),
+			expectErr: fmt.Errorf("%w in secret beep", fmt.Errorf("%w: foobar.baz", secrets.ErrPropertyNotFound)),
 		},
 	}
 
@@ -0,0 +1,31 @@
+package secrets
+
+import (
+	"context"
+	"fmt"
+
+	v1 "kusionstack.io/kusion/pkg/apis/api.kusion.io/v1"
+	"kusionstack.io/kusion/pkg/util/kerrors"
+)
+
+var (
+	ErrPushNotAllowed   = kerrors.New(kerrors.ErrValidation, "pushing secrets is not allowed, set allowPush of the secret store to opt in")
+	ErrPushNotSupported = kerrors.New(kerrors.ErrNotSupported, "the secret store does not support pushing secrets")
+)
+
+// PushSecret writes the value of the ref secret back to the secret store, which must be opted in by
+// AllowPush of the secret store spec. An error of ErrPushNotSupported is returned if the secret store
+// does not implement SecretWriter.
+func PushSecret(ctx context.Context, spec *v1.SecretStore, store SecretStore, ref v1.ExternalSecretRef, value []byte) error {
+	if spec == nil || !spec.AllowPush {
+		return ErrPushNotAllowed
+	}
+	writer, ok := store.(SecretWriter)
+	if !ok {
+		return ErrPushNotSupported
+	}
+	if err := writer.SetSecret(ctx, ref, value); err != nil {
+		return fmt.Errorf("failed to push secret %s: %w", ref.Name, err)
+	}
+	return nil
+}
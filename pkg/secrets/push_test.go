@@ -0,0 +1,60 @@
+package secrets
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"kusionstack.io/kusion/pkg/apis/api.kusion.io/v1"
+	"kusionstack.io/kusion/pkg/util/kerrors"
+)
+
+// writableSecretStore is a mapSecretStore supporting writing the secrets.
+type writableSecretStore struct {
+	mapSecretStore
+}
+
+func (s *writableSecretStore) SetSecret(_ context.Context, ref v1.ExternalSecretRef, value []byte) error {
+	payload, err := SetProperty(s.secrets[ref.Name], ref.Property, value)
+	if err != nil {
+		return err
+	}
+	s.secrets[ref.Name] = payload
+	return nil
+}
+
+func TestPushSecret(t *testing.T) {
+	allowed := &v1.SecretStore{AllowPush: true}
+	ref := v1.ExternalSecretRef{Name: "db-password"}
+
+	store := &writableSecretStore{mapSecretStore{secrets: map[string][]byte{}}}
+	err := PushSecret(context.Background(), &v1.SecretStore{}, store, ref, []byte("t0p-Secret"))
+	assert.ErrorIs(t, err, ErrPushNotAllowed)
+	assert.Empty(t, store.secrets)
+
+	err = PushSecret(context.Background(), allowed, store, ref, []byte("t0p-Secret"))
+	assert.NoError(t, err)
+	assert.Equal(t, "t0p-Secret", string(store.secrets["db-password"]))
+
+	err = PushSecret(context.Background(), allowed, &mapSecretStore{}, ref, []byte("t0p-Secret"))
+	assert.ErrorIs(t, err, ErrPushNotSupported)
+	assert.True(t, kerrors.IsNotSupported(err))
+}
+
+func TestSetProperty(t *testing.T) {
+	actual, err := SetProperty(nil, "", []byte("plain"))
+	assert.NoError(t, err)
+	assert.Equal(t, "plain", string(actual))
+
+	actual, err = SetProperty(nil, "password", []byte("t0p-Secret"))
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{"password":"t0p-Secret"}`, string(actual))
+
+	actual, err = SetProperty([]byte(`{"username":"admin","password":"old"}`), "password", []byte("t0p-Secret"))
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{"username":"admin","password":"t0p-Secret"}`, string(actual))
+
+	_, err = SetProperty([]byte("plain"), "password", []byte("t0p-Secret"))
+	assert.Error(t, err)
+}
@@ -0,0 +1,66 @@
+package secrets
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"text/template"
+
+	v1 "kusionstack.io/kusion/pkg/apis/api.kusion.io/v1"
+)
+
+var (
+	ErrInvalidSecretTemplate = errors.New("invalid secret template")
+	ErrUnresolvedTemplateRef = errors.New("unresolved secret ref of the template")
+)
+
+// RenderTemplatedSecret resolves all the secret refs of the templated secret, and renders the template
+// with the resolved secrets keyed by their names. The template is not rendered if any ref fails to
+// resolve, and an error naming each of the unresolved refs is returned instead. Referencing a name not
+// in the data is an error as well.
+func RenderTemplatedSecret(ctx context.Context, store SecretStore, secret *v1.TemplatedSecret) ([]byte, error) {
+	if secret == nil {
+		return nil, nil
+	}
+	tmpl, err := template.New("secret").Option("missingkey=error").Parse(secret.Template)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidSecretTemplate, err)
+	}
+
+	names := make([]string, 0, len(secret.Data))
+	refs := make([]v1.ExternalSecretRef, 0, len(secret.Data))
+	for name, ref := range secret.Data {
+		names = append(names, name)
+		refs = append(refs, ref)
+	}
+	sort.Strings(names)
+	results, err := NewFetcher(store).Fetch(ctx, refs)
+	if err != nil {
+		return nil, err
+	}
+
+	values := make(map[string]string, len(secret.Data))
+	var errs []error
+	for _, name := range names {
+		result := results[secret.Data[name]]
+		switch {
+		case result.Err != nil:
+			errs = append(errs, fmt.Errorf("%w: %s, %v", ErrUnresolvedTemplateRef, name, result.Err))
+		case result.Data == nil:
+			errs = append(errs, fmt.Errorf("%w: %s, %v", ErrUnresolvedTemplateRef, name, NoSecretErr))
+		default:
+			values[name] = string(result.Data)
+		}
+	}
+	if len(errs) > 0 {
+		return nil, errors.Join(errs...)
+	}
+
+	var buf bytes.Buffer
+	if err = tmpl.Execute(&buf, values); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidSecretTemplate, err)
+	}
+	return buf.Bytes(), nil
+}
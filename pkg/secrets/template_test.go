@@ -0,0 +1,96 @@
+package secrets
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"kusionstack.io/kusion/pkg/apis/api.kusion.io/v1"
+)
+
+func TestRenderTemplatedSecret(t *testing.T) {
+	store := &mapSecretStore{
+		secrets: map[string][]byte{
+			"mysql-host":     []byte("mysql.default.svc"),
+			"mysql-user":     []byte("root"),
+			"mysql-password": []byte("dDBwLVNlY3JldA=="),
+		},
+		errs: map[string]error{
+			"mysql-denied": errors.New("AccessDeniedException"),
+		},
+	}
+	dsnRefs := map[string]v1.ExternalSecretRef{
+		"host":     {Name: "mysql-host"},
+		"user":     {Name: "mysql-user"},
+		"password": {Name: "mysql-password", Transform: "base64decode"},
+	}
+
+	testcases := []struct {
+		name     string
+		secret   *v1.TemplatedSecret
+		success  bool
+		expected string
+		errMsg   string
+	}{
+		{
+			name: "render dsn from three refs",
+			secret: &v1.TemplatedSecret{
+				Template: "{{ .user }}:{{ .password }}@tcp({{ .host }}:3306)/app",
+				Data:     dsnRefs,
+			},
+			success:  true,
+			expected: "root:t0p-Secret@tcp(mysql.default.svc:3306)/app",
+		},
+		{
+			name:     "nil templated secret",
+			secret:   nil,
+			success:  true,
+			expected: "",
+		},
+		{
+			name: "missing refs",
+			secret: &v1.TemplatedSecret{
+				Template: "{{ .user }}:{{ .password }}@tcp({{ .host }}:3306)/app",
+				Data: map[string]v1.ExternalSecretRef{
+					"host":     {Name: "mysql-host"},
+					"user":     {Name: "mysql-denied"},
+					"password": {Name: "mysql-missing"},
+				},
+			},
+			success: false,
+			errMsg: "unresolved secret ref of the template: password, Secret does not exist\n" +
+				"unresolved secret ref of the template: user, AccessDeniedException",
+		},
+		{
+			name: "name not in data",
+			secret: &v1.TemplatedSecret{
+				Template: "{{ .user }}@{{ .port }}",
+				Data:     dsnRefs,
+			},
+			success: false,
+		},
+		{
+			name: "invalid template",
+			secret: &v1.TemplatedSecret{
+				Template: "{{ .user ",
+				Data:     dsnRefs,
+			},
+			success: false,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			actual, err := RenderTemplatedSecret(context.Background(), store, tc.secret)
+			assert.Equal(t, tc.success, err == nil)
+			if tc.success {
+				assert.Equal(t, tc.expected, string(actual))
+			}
+			if tc.errMsg != "" {
+				assert.EqualError(t, err, tc.errMsg)
+			}
+		})
+	}
+}
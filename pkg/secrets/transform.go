@@ -0,0 +1,118 @@
+package secrets
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	v1 "kusionstack.io/kusion/pkg/apis/api.kusion.io/v1"
+)
+
+const (
+	TransformBase64Decode  = "base64decode"
+	TransformTrim          = "trim"
+	TransformJSONUnmarshal = "jsonUnmarshal"
+
+	// transformSeparator separates the transforms in the pipeline.
+	transformSeparator = "|"
+	// transformArgSeparator separates the transform and its argument, e.g. jsonUnmarshal:db.password.
+	transformArgSeparator = ":"
+)
+
+var (
+	ErrInvalidTransform = errors.New("invalid secret transform")
+	ErrTransformFailed  = errors.New("secret transform failed")
+)
+
+// SecretTransform is a step of the transform pipeline of ExternalSecretRef.Transform.
+type SecretTransform struct {
+	// Type is the type of the transform, one of base64decode, trim and jsonUnmarshal.
+	Type string
+	// Arg is the argument of the transform, which is the property to extract for jsonUnmarshal.
+	Arg string
+}
+
+// ParseTransforms parses the transform pipeline, whose steps are separated by "|" and an argument is
+// given after ":". An error of ErrInvalidTransform is returned for the unknown transform types.
+func ParseTransforms(pipeline string) ([]SecretTransform, error) {
+	if strings.TrimSpace(pipeline) == "" {
+		return nil, nil
+	}
+	var transforms []SecretTransform
+	for i, step := range strings.Split(pipeline, transformSeparator) {
+		name, arg, _ := strings.Cut(strings.TrimSpace(step), transformArgSeparator)
+		transform := SecretTransform{Type: name, Arg: arg}
+		switch name {
+		case TransformBase64Decode, TransformTrim:
+			if arg != "" {
+				return nil, fmt.Errorf("%w: step %d %s takes no argument, got %s", ErrInvalidTransform, i, name, arg)
+			}
+		case TransformJSONUnmarshal:
+		case "":
+			return nil, fmt.Errorf("%w: step %d is empty", ErrInvalidTransform, i)
+		default:
+			return nil, fmt.Errorf("%w: step %d has unknown type %s", ErrInvalidTransform, i, name)
+		}
+		transforms = append(transforms, transform)
+	}
+	return transforms, nil
+}
+
+// ApplyTransforms applies the transform pipeline to the secret data in the declared order, and returns
+// an error naming the failed step rather than the partially transformed data.
+func ApplyTransforms(data []byte, pipeline string) ([]byte, error) {
+	transforms, err := ParseTransforms(pipeline)
+	if err != nil {
+		return nil, err
+	}
+	for i, transform := range transforms {
+		if data, err = applyTransform(data, transform); err != nil {
+			return nil, fmt.Errorf("%w: step %d %s: %v", ErrTransformFailed, i, transform.Type, err)
+		}
+	}
+	return data, nil
+}
+
+func applyTransform(data []byte, transform SecretTransform) ([]byte, error) {
+	switch transform.Type {
+	case TransformBase64Decode:
+		trimmed := bytes.TrimSpace(data)
+		decoded := make([]byte, base64.StdEncoding.DecodedLen(len(trimmed)))
+		n, err := base64.StdEncoding.Decode(decoded, trimmed)
+		if err != nil {
+			return nil, err
+		}
+		return decoded[:n], nil
+	case TransformTrim:
+		return bytes.TrimSpace(data), nil
+	case TransformJSONUnmarshal:
+		if !json.Valid(data) {
+			return nil, errors.New("secret is not in JSON format")
+		}
+		if transform.Arg == "" {
+			var s string
+			if err := json.Unmarshal(data, &s); err == nil {
+				return []byte(s), nil
+			}
+			return data, nil
+		}
+		return ExtractProperty(data, transform.Arg)
+	default:
+		return nil, fmt.Errorf("%w: unknown type %s", ErrInvalidTransform, transform.Type)
+	}
+}
+
+// GetSecret retrieves the secret of the ref from the secret store, and applies the transform pipeline of
+// the ref to the fetched data, which is shared by the consumers resolving the secret refs. The nil data
+// is returned as it is, which is regarded as not found.
+func GetSecret(ctx context.Context, store SecretStore, ref v1.ExternalSecretRef) ([]byte, error) {
+	data, err := store.GetSecret(ctx, ref)
+	if err != nil || data == nil || ref.Transform == "" {
+		return data, err
+	}
+	return ApplyTransforms(data, ref.Transform)
+}
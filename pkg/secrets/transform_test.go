@@ -0,0 +1,126 @@
+package secrets
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"kusionstack.io/kusion/pkg/apis/api.kusion.io/v1"
+)
+
+func TestApplyTransforms(t *testing.T) {
+	testcases := []struct {
+		name      string
+		data      []byte
+		transform string
+		success   bool
+		expected  string
+	}{
+		{
+			name:      "no transform",
+			data:      []byte(" plain "),
+			transform: "",
+			success:   true,
+			expected:  " plain ",
+		},
+		{
+			name:      "base64decode",
+			data:      []byte("dDBwLVNlY3JldA==\n"),
+			transform: "base64decode",
+			success:   true,
+			expected:  "t0p-Secret",
+		},
+		{
+			name:      "trim",
+			data:      []byte(" t0p-Secret\n"),
+			transform: "trim",
+			success:   true,
+			expected:  "t0p-Secret",
+		},
+		{
+			name:      "jsonUnmarshal string",
+			data:      []byte(`"t0p-Secret"`),
+			transform: "jsonUnmarshal",
+			success:   true,
+			expected:  "t0p-Secret",
+		},
+		{
+			// base64 of {"db": {"password": " t0p-Secret "}}
+			name:      "chained transforms",
+			data:      []byte("eyJkYiI6IHsicGFzc3dvcmQiOiAiIHQwcC1TZWNyZXQgIn19"),
+			transform: "base64decode | jsonUnmarshal:db.password | trim",
+			success:   true,
+			expected:  "t0p-Secret",
+		},
+		{
+			name:      "invalid base64",
+			data:      []byte("not base64!"),
+			transform: "base64decode",
+			success:   false,
+		},
+		{
+			name:      "invalid json",
+			data:      []byte("plain"),
+			transform: "trim|jsonUnmarshal:password",
+			success:   false,
+		},
+		{
+			name:      "property not found",
+			data:      []byte(`{"username":"admin"}`),
+			transform: "jsonUnmarshal:password",
+			success:   false,
+		},
+		{
+			name:      "unknown transform",
+			data:      []byte("plain"),
+			transform: "base32decode",
+			success:   false,
+		},
+		{
+			name:      "empty step",
+			data:      []byte("plain"),
+			transform: "trim||trim",
+			success:   false,
+		},
+		{
+			name:      "unexpected argument",
+			data:      []byte("plain"),
+			transform: "trim:all",
+			success:   false,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			actual, err := ApplyTransforms(tc.data, tc.transform)
+			assert.Equal(t, tc.success, err == nil)
+			if tc.success {
+				assert.Equal(t, tc.expected, string(actual))
+			}
+		})
+	}
+
+	_, err := ApplyTransforms([]byte("plain"), "trim|base64decode")
+	assert.ErrorIs(t, err, ErrTransformFailed)
+	assert.ErrorContains(t, err, "step 1 base64decode")
+	_, err = ApplyTransforms([]byte("plain"), "base32decode")
+	assert.ErrorIs(t, err, ErrInvalidTransform)
+}
+
+func TestGetSecret(t *testing.T) {
+	store := &mapSecretStore{
+		secrets: map[string][]byte{"api-token": []byte("dDBrZW4=")},
+	}
+
+	data, err := GetSecret(context.Background(), store, v1.ExternalSecretRef{Name: "api-token"})
+	assert.NoError(t, err)
+	assert.Equal(t, "dDBrZW4=", string(data))
+
+	data, err = GetSecret(context.Background(), store, v1.ExternalSecretRef{Name: "api-token", Transform: "base64decode"})
+	assert.NoError(t, err)
+	assert.Equal(t, "t0ken", string(data))
+
+	_, err = GetSecret(context.Background(), store, v1.ExternalSecretRef{Name: "missing", Transform: "base64decode"})
+	assert.ErrorIs(t, err, NoSecretErr)
+}
@@ -0,0 +1,208 @@
+package health
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	v1 "kusionstack.io/kusion/pkg/apis/api.kusion.io/v1"
+	"kusionstack.io/kusion/pkg/backend"
+	"kusionstack.io/kusion/pkg/secrets"
+	"kusionstack.io/kusion/pkg/util/kerrors"
+)
+
+const (
+	// DefaultCacheTTL is the default duration to cache the health status, so that the frequent probes
+	// do not overload the backend and secret store.
+	DefaultCacheTTL = 5 * time.Second
+
+	// DefaultCheckTimeout is the default timeout of checking each component.
+	DefaultCheckTimeout = 3 * time.Second
+
+	ComponentBackend     = "backend"
+	ComponentSecretStore = "secretStore"
+
+	// probeProject and probeWorkspace locate the releases read to probe the backend, which are not
+	// required to exist.
+	probeProject   = "kusion-health-probe"
+	probeWorkspace = "default"
+)
+
+var (
+	ErrSecretStoreProviderNotFound = errors.New("no matched secret store provider found")
+
+	// ErrUnverified is returned by the checkers which can not verify the component, e.g. the secret
+	// store without a connection check, and the component is regarded as healthy but unverified.
+	ErrUnverified = errors.New("unverified")
+)
+
+// Checker checks the health of a component which Kusion depends on.
+type Checker interface {
+	// Name returns the name of the component.
+	Name() string
+
+	// Check returns an error if the component is unhealthy.
+	Check(ctx context.Context) error
+}
+
+// ComponentStatus is the health status of a component.
+type ComponentStatus struct {
+	Name    string `json:"name"`
+	Healthy bool   `json:"healthy"`
+	Message string `json:"message,omitempty"`
+	// Latency is the duration of checking the component.
+	Latency time.Duration `json:"latency"`
+}
+
+// Status is the aggregated health status, which is healthy only if all the components are healthy.
+type Status struct {
+	Healthy    bool              `json:"healthy"`
+	Components []ComponentStatus `json:"components"`
+	CheckedAt  time.Time         `json:"checkedAt"`
+}
+
+// Prober checks the health of the components and caches the result for a short duration.
+type Prober struct {
+	checkers []Checker
+	cacheTTL time.Duration
+	timeout  time.Duration
+
+	mu     sync.Mutex
+	cached *Status
+	now    func() time.Time
+}
+
+// NewProber news a Prober with the checkers. The cacheTTL and timeout use the default values if not
+// positive.
+func NewProber(cacheTTL, timeout time.Duration, checkers ...Checker) *Prober {
+	if cacheTTL <= 0 {
+		cacheTTL = DefaultCacheTTL
+	}
+	if timeout <= 0 {
+		timeout = DefaultCheckTimeout
+	}
+	return &Prober{
+		checkers: checkers,
+		cacheTTL: cacheTTL,
+		timeout:  timeout,
+		now:      time.Now,
+	}
+}
+
+// Health returns the aggregated health status of the components. The components are checked
+// concurrently, and the result is cached for the cacheTTL.
+func (p *Prober) Health(ctx context.Context) *Status {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.cached != nil && p.now().Sub(p.cached.CheckedAt) < p.cacheTTL {
+		return p.cached
+	}
+
+	components := make([]ComponentStatus, len(p.checkers))
+	wg := sync.WaitGroup{}
+	for i, checker := range p.checkers {
+		wg.Add(1)
+		go func(i int, checker Checker) {
+			defer wg.Done()
+			components[i] = p.check(ctx, checker)
+		}(i, checker)
+	}
+	wg.Wait()
+
+	status := &Status{Healthy: true, Components: components, CheckedAt: p.now()}
+	for _, c := range components {
+		if !c.Healthy {
+			status.Healthy = false
+		}
+	}
+	p.cached = status
+	return status
+}
+
+func (p *Prober) check(ctx context.Context, checker Checker) ComponentStatus {
+	ctx, cancel := context.WithTimeout(ctx, p.timeout)
+	defer cancel()
+
+	start := time.Now()
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- checker.Check(ctx)
+	}()
+
+	var err error
+	select {
+	case err = <-errCh:
+	case <-ctx.Done():
+		err = fmt.Errorf("check timeout: %w", ctx.Err())
+	}
+
+	status := ComponentStatus{Name: checker.Name(), Healthy: err == nil || errors.Is(err, ErrUnverified), Latency: time.Since(start)}
+	if err != nil {
+		status.Message = err.Error()
+	}
+	return status
+}
+
+// CheckFunc adapts a function to a Checker.
+type CheckFunc struct {
+	ComponentName string
+	Func          func(ctx context.Context) error
+}
+
+func (c *CheckFunc) Name() string {
+	return c.ComponentName
+}
+
+func (c *CheckFunc) Check(ctx context.Context) error {
+	return c.Func(ctx)
+}
+
+// NewBackendChecker returns a Checker which pings the release backend by reading the releases metadata
+// of a probe project, which is what the release storage reads before any operation.
+func NewBackendChecker(b backend.Backend) Checker {
+	return &CheckFunc{
+		ComponentName: ComponentBackend,
+		Func: func(_ context.Context) error {
+			_, err := b.ReleaseStorage(probeProject, probeWorkspace)
+			return err
+		},
+	}
+}
+
+// NewSecretStoreChecker returns a Checker which pings the secret store by constructing it and checking
+// the connection if the secret store implements secrets.ConnectionChecker. If the probe is not nil, the
+// referred secret is also read, where the secret not found is regarded as healthy. The secret store
+// neither checked nor probed is reported as unverified, see ErrUnverified.
+func NewSecretStoreChecker(spec *v1.SecretStore, probe *v1.ExternalSecretRef) Checker {
+	return &CheckFunc{
+		ComponentName: ComponentSecretStore,
+		Func: func(ctx context.Context) error {
+			provider, exist := secrets.GetProvider(spec.Provider)
+			if !exist {
+				return ErrSecretStoreProviderNotFound
+			}
+			store, err := provider.NewSecretStore(spec)
+			if err != nil {
+				return err
+			}
+			checker, checkable := store.(secrets.ConnectionChecker)
+			if checkable {
+				if err = checker.CheckConnection(ctx); err != nil {
+					return fmt.Errorf("secret store is unreachable: %w", err)
+				}
+			}
+			if probe == nil {
+				if !checkable {
+					return fmt.Errorf("%w: the secret store has no connection check", ErrUnverified)
+				}
+				return nil
+			}
+			if _, err = store.GetSecret(ctx, *probe); err != nil && !kerrors.IsNotFound(err) {
+				return err
+			}
+			return nil
+		},
+	}
+}
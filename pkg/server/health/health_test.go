@@ -0,0 +1,298 @@
+package health
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	v1 "kusionstack.io/kusion/pkg/apis/api.kusion.io/v1"
+	"kusionstack.io/kusion/pkg/backend"
+	"kusionstack.io/kusion/pkg/backend/storages"
+	"kusionstack.io/kusion/pkg/engine/release"
+	"kusionstack.io/kusion/pkg/engine/resource/graph"
+	"kusionstack.io/kusion/pkg/secrets"
+	_ "kusionstack.io/kusion/pkg/secrets/providers/fake"
+	"kusionstack.io/kusion/pkg/workspace"
+)
+
+var _ backend.Backend = (*fakeBackend)(nil)
+
+type fakeBackend struct {
+	err error
+}
+
+func (f *fakeBackend) WorkspaceStorage() (workspace.Storage, error) {
+	return nil, f.err
+}
+
+func (f *fakeBackend) ReleaseStorage(project, workspace string) (release.Storage, error) {
+	return nil, f.err
+}
+
+func (f *fakeBackend) StateStorageWithPath(path string) (release.Storage, error) {
+	return nil, f.err
+}
+
+func (f *fakeBackend) GraphStorage(project, workspace string) (graph.Storage, error) {
+	return nil, f.err
+}
+
+func (f *fakeBackend) ProjectStorage() (map[string][]string, error) {
+	return nil, f.err
+}
+
+func mockFakeSecretStore() *v1.SecretStore {
+	return &v1.SecretStore{
+		Provider: &v1.ProviderSpec{
+			Fake: &v1.FakeProvider{
+				Data: []v1.FakeProviderData{{Key: "db-password", Value: "mock-password"}},
+			},
+		},
+	}
+}
+
+func TestNewBackendChecker(t *testing.T) {
+	newLocalBackend := func(path string) backend.Backend {
+		return storages.NewLocalStorage(&v1.BackendLocalConfig{Path: path})
+	}
+	file := filepath.Join(t.TempDir(), "file")
+	require.NoError(t, os.WriteFile(file, []byte{}, 0o600))
+
+	testcases := []struct {
+		name    string
+		backend backend.Backend
+		healthy bool
+	}{
+		{
+			name:    "release storage readable",
+			backend: newLocalBackend(t.TempDir()),
+			healthy: true,
+		},
+		{
+			name:    "release storage not readable",
+			backend: newLocalBackend(file),
+			healthy: false,
+		},
+		{
+			name:    "failing backend",
+			backend: &fakeBackend{err: errors.New("connection refused")},
+			healthy: false,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := NewBackendChecker(tc.backend).Check(context.Background())
+			assert.Equal(t, tc.healthy, err == nil)
+		})
+	}
+}
+
+// uncheckedProvider provides the secret stores without a connection check.
+type uncheckedProvider struct{}
+
+func (p *uncheckedProvider) NewSecretStore(_ *v1.SecretStore) (secrets.SecretStore, error) {
+	return &uncheckedSecretStore{}, nil
+}
+
+type uncheckedSecretStore struct{}
+
+func (s *uncheckedSecretStore) GetSecret(_ context.Context, ref v1.ExternalSecretRef) ([]byte, error) {
+	if ref.Name == "db-password" {
+		return []byte("mock-password"), nil
+	}
+	return nil, errors.New("permission denied")
+}
+
+// unreachableProvider provides the secret stores whose connection check fails.
+type unreachableProvider struct{}
+
+func (p *unreachableProvider) NewSecretStore(_ *v1.SecretStore) (secrets.SecretStore, error) {
+	return &unreachableSecretStore{}, nil
+}
+
+type unreachableSecretStore struct {
+	uncheckedSecretStore
+}
+
+func (s *unreachableSecretStore) CheckConnection(_ context.Context) error {
+	return errors.New("connection refused")
+}
+
+func TestNewSecretStoreChecker(t *testing.T) {
+	uncheckedSpec := &v1.SecretStore{
+		Provider: &v1.ProviderSpec{OnPremises: &v1.OnPremisesProvider{Name: "unchecked"}},
+	}
+	secrets.Register(&uncheckedProvider{}, uncheckedSpec.Provider)
+	unreachableSpec := &v1.SecretStore{
+		Provider: &v1.ProviderSpec{OnPremises: &v1.OnPremisesProvider{Name: "unreachable"}},
+	}
+	secrets.Register(&unreachableProvider{}, unreachableSpec.Provider)
+
+	testcases := []struct {
+		name            string
+		spec            *v1.SecretStore
+		probe           *v1.ExternalSecretRef
+		expectedHealthy bool
+		expectedMessage string
+	}{
+		{
+			name:            "connection checked",
+			spec:            mockFakeSecretStore(),
+			expectedHealthy: true,
+		},
+		{
+			name:            "connection check failed",
+			spec:            unreachableSpec,
+			probe:           &v1.ExternalSecretRef{Name: "db-password"},
+			expectedHealthy: false,
+			expectedMessage: "secret store is unreachable",
+		},
+		{
+			name:            "unverified without connection check",
+			spec:            uncheckedSpec,
+			expectedHealthy: true,
+			expectedMessage: "unverified",
+		},
+		{
+			name:            "probed without connection check",
+			spec:            uncheckedSpec,
+			probe:           &v1.ExternalSecretRef{Name: "db-password"},
+			expectedHealthy: true,
+		},
+		{
+			name:            "probe failed without connection check",
+			spec:            uncheckedSpec,
+			probe:           &v1.ExternalSecretRef{Name: "api-token"},
+			expectedHealthy: false,
+			expectedMessage: "permission denied",
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			status := NewProber(0, time.Second, NewSecretStoreChecker(tc.spec, tc.probe)).Health(context.Background())
+			require.Len(t, status.Components, 1)
+			assert.Equal(t, tc.expectedHealthy, status.Healthy)
+			assert.Equal(t, tc.expectedHealthy, status.Components[0].Healthy)
+			if tc.expectedMessage == "" {
+				assert.Empty(t, status.Components[0].Message)
+			} else {
+				assert.Contains(t, status.Components[0].Message, tc.expectedMessage)
+			}
+		})
+	}
+}
+
+func TestProber_Health(t *testing.T) {
+	testcases := []struct {
+		name              string
+		checkers          []Checker
+		expectedHealthy   bool
+		expectedUnhealthy []string
+	}{
+		{
+			name: "all healthy",
+			checkers: []Checker{
+				&CheckFunc{ComponentName: ComponentBackend, Func: func(context.Context) error { return nil }},
+				NewSecretStoreChecker(mockFakeSecretStore(), &v1.ExternalSecretRef{Name: "not-exist"}),
+			},
+			expectedHealthy: true,
+		},
+		{
+			name: "failing backend",
+			checkers: []Checker{
+				NewBackendChecker(&fakeBackend{err: errors.New("connection refused")}),
+				NewSecretStoreChecker(mockFakeSecretStore(), nil),
+			},
+			expectedHealthy:   false,
+			expectedUnhealthy: []string{ComponentBackend},
+		},
+		{
+			name: "secret store provider not found",
+			checkers: []Checker{
+				NewSecretStoreChecker(&v1.SecretStore{}, nil),
+			},
+			expectedHealthy:   false,
+			expectedUnhealthy: []string{ComponentSecretStore},
+		},
+		{
+			name: "check timeout",
+			checkers: []Checker{
+				&CheckFunc{ComponentName: ComponentBackend, Func: func(ctx context.Context) error {
+					time.Sleep(time.Second)
+					return nil
+				}},
+			},
+			expectedHealthy:   false,
+			expectedUnhealthy: []string{ComponentBackend},
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			status := NewProber(0, 100*time.Millisecond, tc.checkers...).Health(context.Background())
+			assert.Equal(t, tc.expectedHealthy, status.Healthy)
+			require.Len(t, status.Components, len(tc.checkers))
+
+			var unhealthy []string
+			for _, c := range status.Components {
+				if !c.Healthy {
+					assert.NotEmpty(t, c.Message)
+					unhealthy = append(unhealthy, c.Name)
+				}
+			}
+			assert.Equal(t, tc.expectedUnhealthy, unhealthy)
+		})
+	}
+}
+
+func TestProber_HealthCache(t *testing.T) {
+	var count int32
+	checker := &CheckFunc{ComponentName: ComponentBackend, Func: func(context.Context) error {
+		atomic.AddInt32(&count, 1)
+		return nil
+	}}
+	p := NewProber(time.Minute, 0, checker)
+	now := time.Now()
+	p.now = func() time.Time { return now }
+
+	first := p.Health(context.Background())
+	second := p.Health(context.Background())
+	assert.Same(t, first, second)
+	assert.Equal(t, int32(1), atomic.LoadInt32(&count))
+
+	// the cache expires
+	now = now.Add(time.Minute)
+	third := p.Health(context.Background())
+	assert.NotSame(t, first, third)
+	assert.Equal(t, int32(2), atomic.LoadInt32(&count))
+}
+
+func TestProber_ReadinessHandler(t *testing.T) {
+	p := NewProber(0, 0, NewBackendChecker(&fakeBackend{err: errors.New("connection refused")}))
+	recorder := httptest.NewRecorder()
+	p.ReadinessHandler()(recorder, httptest.NewRequest(http.MethodGet, "/readyz", nil))
+	assert.Equal(t, http.StatusServiceUnavailable, recorder.Code)
+
+	status := &Status{}
+	require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), status))
+	assert.False(t, status.Healthy)
+	require.Len(t, status.Components, 1)
+	assert.Equal(t, ComponentBackend, status.Components[0].Name)
+	assert.Contains(t, status.Components[0].Message, "connection refused")
+
+	recorder = httptest.NewRecorder()
+	LivenessHandler()(recorder, httptest.NewRequest(http.MethodGet, "/livez", nil))
+	assert.Equal(t, http.StatusOK, recorder.Code)
+}
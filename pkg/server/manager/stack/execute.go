@@ -257,7 +257,7 @@ func (m *StackManager) ApplyStack(ctx context.Context, params *StackRequestParam
 				m.stackRepo.Update(ctx, stackEntity)
 				return
 			}
-			release.UpdateReleasePhase(rel, apiv1.ReleasePhaseFailed, relLock)
+			release.UpdateReleaseFailed(rel, err.Error(), relLock)
 			_ = release.UpdateApplyRelease(storage, rel, params.ExecuteParams.Dryrun, relLock)
 		} else {
 			release.UpdateReleasePhase(rel, apiv1.ReleasePhaseSucceeded, relLock)
@@ -306,7 +306,7 @@ func (m *StackManager) ApplyStack(ctx context.Context, params *StackRequestParam
 	}
 	// Allow force unlock of the release
 	if params.ExecuteParams.Unlock {
-		err = unlockRelease(ctx, storage)
+		err = unlockRelease(ctx, storage, params.Operator)
 		if err != nil {
 			return err
 		}
@@ -320,13 +320,13 @@ func (m *StackManager) ApplyStack(ctx context.Context, params *StackRequestParam
 		priorState = &apiv1.State{}
 	}
 	// Create new release
-	rel, err = release.NewApplyRelease(storage, project.Name, stackEntity.Name, ws.Name)
+	rel, err = release.NewApplyRelease(storage, project.Name, stackEntity.Name, ws.Name, release.WithOperator(params.Operator))
 	if err != nil {
 		return err
 	}
 
 	if !params.ExecuteParams.Dryrun {
-		if err = storage.Create(rel); err != nil {
+		if err = release.CreateRelease(storage, rel); err != nil {
 			return err
 		}
 		releaseCreated = true
@@ -488,11 +488,12 @@ func (m *StackManager) DestroyStack(ctx context.Context, params *StackRequestPar
 				m.stackRepo.Update(ctx, stackEntity)
 				return
 			}
-			rel.Phase = apiv1.ReleasePhaseFailed
+			_ = release.SetPhase(rel, apiv1.ReleasePhaseFailed)
 			_ = release.UpdateDestroyRelease(storage, rel)
 		} else {
-			rel.Phase = apiv1.ReleasePhaseSucceeded
-			err = release.UpdateDestroyRelease(storage, rel)
+			if err = release.SetPhase(rel, apiv1.ReleasePhaseSucceeded); err == nil {
+				err = release.UpdateDestroyRelease(storage, rel)
+			}
 			// Update LastSyncTimestamp to current time and set stack syncState to synced
 			if !params.ExecuteParams.Dryrun {
 				stackEntity.SyncState = constant.StackStateDestroySucceeded
@@ -532,13 +533,13 @@ func (m *StackManager) DestroyStack(ctx context.Context, params *StackRequestPar
 	}
 	// Allow force unlock of the release
 	if params.ExecuteParams.Unlock {
-		err = unlockRelease(ctx, storage)
+		err = unlockRelease(ctx, storage, params.Operator)
 		if err != nil {
 			return err
 		}
 	}
 	// Create destroy release
-	rel, err = release.CreateDestroyRelease(storage, project.Name, stack.Name, ws.Name)
+	rel, err = release.CreateDestroyRelease(storage, project.Name, stack.Name, ws.Name, release.WithOperator(params.Operator))
 	if err != nil {
 		return
 	}
@@ -570,7 +571,9 @@ func (m *StackManager) DestroyStack(ctx context.Context, params *StackRequestPar
 	}
 
 	// update release phase to destroying
-	rel.Phase = apiv1.ReleasePhaseDestroying
+	if err = release.SetPhase(rel, apiv1.ReleasePhaseDestroying); err != nil {
+		return
+	}
 	if err = release.UpdateDestroyRelease(storage, rel); err != nil {
 		return
 	}
@@ -3,6 +3,7 @@ package stack
 import (
 	"context"
 	"net/url"
+	"path/filepath"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -242,6 +243,49 @@ func TestBuildValidStackPath(t *testing.T) {
 	}
 }
 
+func TestStackManager_GetWorkdirAndDirectory(t *testing.T) {
+	repoRoot := t.TempDir()
+	t.Setenv("KUSION_SERVER_REPO_CACHE", repoRoot)
+	m := &StackManager{}
+	params := &StackRequestParams{}
+
+	testcases := []struct {
+		name            string
+		success         bool
+		stackPath       string
+		expectedWorkDir string
+	}{
+		{
+			name:            "resolve stack path relative to repo root",
+			success:         true,
+			stackPath:       "myproject/mystack",
+			expectedWorkDir: filepath.Join(repoRoot, "myproject", "mystack"),
+		},
+		{
+			name:            "resolve absolute stack path unchanged",
+			success:         true,
+			stackPath:       filepath.Join(repoRoot, "myproject", "mystack"),
+			expectedWorkDir: filepath.Join(repoRoot, "myproject", "mystack"),
+		},
+		{
+			name:      "stack path escaping repo root",
+			success:   false,
+			stackPath: "../myproject/mystack",
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			directory, workDir, err := m.GetWorkdirAndDirectory(context.Background(), params, &entity.Stack{Path: tc.stackPath})
+			assert.Equal(t, tc.success, err == nil)
+			if tc.success {
+				assert.Equal(t, repoRoot, directory)
+				assert.Equal(t, tc.expectedWorkDir, workDir)
+			}
+		})
+	}
+}
+
 func TestBuildStackFilter(t *testing.T) {
 	m := &StackManager{
 		projectRepo: &mockProjectRepository{},
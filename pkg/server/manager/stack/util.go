@@ -7,7 +7,6 @@ import (
 	"net/http"
 	"net/url"
 	"os"
-	"path/filepath"
 	"regexp"
 	"strconv"
 	"strings"
@@ -24,6 +23,7 @@ import (
 	"kusionstack.io/kusion/pkg/engine/operation/models"
 	"kusionstack.io/kusion/pkg/engine/release"
 	"kusionstack.io/kusion/pkg/engine/runtime/terraform/tfops"
+	projectutil "kusionstack.io/kusion/pkg/project"
 	workspacemanager "kusionstack.io/kusion/pkg/server/manager/workspace"
 	logutil "kusionstack.io/kusion/pkg/server/util/logging"
 	"kusionstack.io/kusion/pkg/util/diff"
@@ -61,7 +61,10 @@ func GetWorkDirFromSource(ctx context.Context, stack *entity.Stack, project *ent
 			return "", "", err
 		}
 		logger.Info("config pulled from source successfully", "directory", directory)
-		workDir = filepath.Join(directory, stack.Path)
+		workDir, err = projectutil.ResolveRepoPath(directory, stack.Path)
+		if err != nil {
+			return "", "", err
+		}
 	}
 	return directory, workDir, nil
 }
@@ -88,7 +91,10 @@ func (m *StackManager) GetWorkdirAndDirectory(ctx context.Context, params *Stack
 		if repoCacheEnv != "" {
 			logger.Info("Repo cache found in env var. Using cached directory...")
 			directory = repoCacheEnv
-			workDir = fmt.Sprintf("%s/%s", directory, stackEntity.Path)
+			workDir, err = projectutil.ResolveRepoPath(directory, stackEntity.Path)
+			if err != nil {
+				return "", "", err
+			}
 		} else {
 			// No env var found, check if stack is in cache
 			logger.Info("No repo cache found in env var. Checking cache...")
@@ -497,29 +503,20 @@ func isInRelease(release *v1.Release, id string) bool {
 	return false
 }
 
-func unlockRelease(ctx context.Context, storage release.Storage) error {
+func unlockRelease(ctx context.Context, storage release.Storage, operator string) error {
 	logger := logutil.GetLogger(ctx)
-	logger.Info("Getting workdir from stack source...")
-	// Get the latest release.
-	r, err := release.GetLatestRelease(storage)
-	if err != nil {
-		return err
+	if operator == "" {
+		operator = "kusion-server"
 	}
-	if r == nil {
-		logger.Info("No release file found for given stack")
+	// Update the phase of the latest release to 'failed', if it was not succeeded or failed.
+	r, err := release.ForceUnlock(storage, &release.UnlockOptions{Force: true, Operator: operator})
+	if errors.Is(err, release.ErrReleaseNotLocked) {
+		logger.Info("No release in progress to unlock for given stack")
 		return nil
 	}
-
-	// Update the phase to 'failed', if it was not succeeded or failed.
-	if r.Phase != v1.ReleasePhaseSucceeded && r.Phase != v1.ReleasePhaseFailed {
-		r.Phase = v1.ReleasePhaseFailed
-		if err := storage.Update(r); err != nil {
-			return err
-		}
-		logger.Info("Successfully update release phase!")
-		return nil
-	} else {
-		logger.Info("No need to update the release phase, current phase: ", "phase", r.Phase)
+	if err != nil {
+		return err
 	}
+	logger.Info("Successfully update release phase!", "revision", r.Revision, "operator", operator)
 	return nil
 }
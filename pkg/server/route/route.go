@@ -11,6 +11,8 @@ import (
 	"github.com/go-chi/cors"
 	httpswagger "github.com/swaggo/http-swagger"
 	docs "kusionstack.io/kusion/api/openapispec"
+	v1 "kusionstack.io/kusion/pkg/apis/api.kusion.io/v1"
+	kusionbackend "kusionstack.io/kusion/pkg/backend"
 	"kusionstack.io/kusion/pkg/infra/persistence"
 	"kusionstack.io/kusion/pkg/server"
 	"kusionstack.io/kusion/pkg/server/handler/backend"
@@ -22,6 +24,7 @@ import (
 	"kusionstack.io/kusion/pkg/server/handler/source"
 	"kusionstack.io/kusion/pkg/server/handler/stack"
 	"kusionstack.io/kusion/pkg/server/handler/workspace"
+	"kusionstack.io/kusion/pkg/server/health"
 	backendmanager "kusionstack.io/kusion/pkg/server/manager/backend"
 	modulemanager "kusionstack.io/kusion/pkg/server/manager/module"
 	organizationmanager "kusionstack.io/kusion/pkg/server/manager/organization"
@@ -84,6 +87,11 @@ func NewCoreRoute(config *server.Config) (*chi.Mux, error) {
 	// Endpoint to list all available endpoints in the router.
 	router.Get("/server-configs", expvar.Handler().ServeHTTP)
 
+	// Endpoints of the liveness and readiness probes, where the readiness reflects the connectivity
+	// of the default backend.
+	router.Get("/livez", health.LivenessHandler())
+	router.Get("/readyz", newHealthProber(config).ReadinessHandler())
+
 	logger := logutil.GetLogger(context.TODO())
 	logger.Info(fmt.Sprintf("Listening on :%d", config.Port))
 	http.ListenAndServe(fmt.Sprintf(":%d", config.Port), router)
@@ -92,6 +100,41 @@ func NewCoreRoute(config *server.Config) (*chi.Mux, error) {
 	return router, nil
 }
 
+// newHealthProber creates the health prober of the components which the server depends on, i.e. the
+// default backend, and the secret store of its current workspace if configured.
+func newHealthProber(config *server.Config) *health.Prober {
+	defaultBackend, err := workspacemanager.NewBackendFromEntity(config.DefaultBackend)
+	if err != nil {
+		return health.NewProber(health.DefaultCacheTTL, health.DefaultCheckTimeout, &health.CheckFunc{
+			ComponentName: health.ComponentBackend,
+			Func:          func(context.Context) error { return err },
+		})
+	}
+
+	checkers := []health.Checker{health.NewBackendChecker(defaultBackend)}
+	if secretStore := currentSecretStore(defaultBackend); secretStore != nil {
+		checkers = append(checkers, health.NewSecretStoreChecker(secretStore, nil))
+	}
+	return health.NewProber(health.DefaultCacheTTL, health.DefaultCheckTimeout, checkers...)
+}
+
+// currentSecretStore returns the secret store of the current workspace of the backend, and returns nil
+// if the workspace can not be read or has no secret store.
+func currentSecretStore(b kusionbackend.Backend) *v1.SecretStore {
+	logger := logutil.GetLogger(context.TODO())
+	storage, err := b.WorkspaceStorage()
+	if err != nil {
+		logger.Warn("Failed to get the workspace storage for the secret store health check", "error", err)
+		return nil
+	}
+	ws, err := storage.Get("")
+	if err != nil {
+		logger.Warn("Failed to get the current workspace for the secret store health check", "error", err)
+		return nil
+	}
+	return ws.SecretStore
+}
+
 // setupRestAPIV1 configures routing for the API version 1, grouping routes by
 // resource type and setting up proper handlers.
 func setupRestAPIV1(
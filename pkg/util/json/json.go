@@ -2,6 +2,7 @@ package json
 
 import (
 	"encoding/json"
+	"math"
 
 	"kusionstack.io/kusion/pkg/util"
 )
@@ -60,6 +61,83 @@ func RemoveListFields(config, live []interface{}) []interface{} {
 	return result
 }
 
+// maxExactInteger is the max integer which float64 represents exactly.
+const maxExactInteger = 1 << 53
+
+// NormalizeNumbers coerces the whole-number float64 values back to int64, as the integers become
+// float64 after round-tripping through JSON, so that they compare equal with the live integer fields.
+// The values with fractions, and the ones beyond the range where float64 represents the integers
+// exactly, are kept as float64. The other integer types are coerced to int64 as well. The maps and
+// slices are normalized in place.
+//
+// As the whole-number floating fields are coerced as well, it only suits the values whose live objects
+// decode every whole number as int64, such as the Kubernetes objects decoded by the apimachinery. Use
+// NormalizeNumbersAs for the others, where the integer fields are told by a reference.
+func NormalizeNumbers(v interface{}) interface{} {
+	switch t := v.(type) {
+	case map[string]interface{}:
+		for k, e := range t {
+			t[k] = NormalizeNumbers(e)
+		}
+		return t
+	case []interface{}:
+		for i, e := range t {
+			t[i] = NormalizeNumbers(e)
+		}
+		return t
+	case float64:
+		if t == math.Trunc(t) && math.Abs(t) <= maxExactInteger {
+			return int64(t)
+		}
+		return t
+	case int:
+		return int64(t)
+	case int32:
+		return int64(t)
+	default:
+		return v
+	}
+}
+
+// NormalizeNumbersAs coerces the whole-number float64 values in v back to int64 only where the value at
+// the same path of the reference is an integer, e.g. the live object whose fields tell the schema, so
+// that the genuinely floating fields are kept as float64. The values without the counterparts in the
+// reference are kept as they are. The maps and slices are normalized in place.
+func NormalizeNumbersAs(v, reference interface{}) interface{} {
+	switch t := v.(type) {
+	case map[string]interface{}:
+		ref, ok := reference.(map[string]interface{})
+		if !ok {
+			return t
+		}
+		for k, e := range t {
+			if r, ok := ref[k]; ok {
+				t[k] = NormalizeNumbersAs(e, r)
+			}
+		}
+		return t
+	case []interface{}:
+		ref, ok := reference.([]interface{})
+		if !ok {
+			return t
+		}
+		for i := 0; i < len(t) && i < len(ref); i++ {
+			t[i] = NormalizeNumbersAs(t[i], ref[i])
+		}
+		return t
+	case float64:
+		switch reference.(type) {
+		case int, int32, int64:
+			if t == math.Trunc(t) && math.Abs(t) <= maxExactInteger {
+				return int64(t)
+			}
+		}
+		return t
+	default:
+		return v
+	}
+}
+
 // Marshal2String marshal to string
 func Marshal2String(v interface{}) string {
 	r, err := json.Marshal(v)
@@ -132,3 +132,120 @@ func TestMustMarshal2PrettyString(t *testing.T) {
 		})
 	}
 }
+
+func TestNormalizeNumbers(t *testing.T) {
+	tests := []struct {
+		name string
+		v    interface{}
+		want interface{}
+	}{
+		{
+			name: "whole number",
+			v:    float64(3),
+			want: int64(3),
+		},
+		{
+			name: "negative whole number",
+			v:    float64(-1),
+			want: int64(-1),
+		},
+		{
+			name: "fraction",
+			v:    0.5,
+			want: 0.5,
+		},
+		{
+			name: "beyond exact range",
+			v:    float64(1 << 60),
+			want: float64(1 << 60),
+		},
+		{
+			name: "int",
+			v:    3,
+			want: int64(3),
+		},
+		{
+			name: "nested",
+			v: map[string]interface{}{
+				"replicas": float64(3),
+				"ports":    []interface{}{map[string]interface{}{"port": float64(80)}},
+				"cpu":      1.5,
+				"name":     "foo",
+			},
+			want: map[string]interface{}{
+				"replicas": int64(3),
+				"ports":    []interface{}{map[string]interface{}{"port": int64(80)}},
+				"cpu":      1.5,
+				"name":     "foo",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := NormalizeNumbers(tt.v); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("NormalizeNumbers() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNormalizeNumbersAs(t *testing.T) {
+	tests := []struct {
+		name      string
+		v         interface{}
+		reference interface{}
+		want      interface{}
+	}{
+		{
+			name:      "integer field",
+			v:         float64(3),
+			reference: int64(3),
+			want:      int64(3),
+		},
+		{
+			name:      "floating field",
+			v:         float64(2),
+			reference: float64(2),
+			want:      float64(2),
+		},
+		{
+			name:      "no reference",
+			v:         float64(2),
+			reference: nil,
+			want:      float64(2),
+		},
+		{
+			name:      "fraction",
+			v:         0.5,
+			reference: int64(1),
+			want:      0.5,
+		},
+		{
+			name: "nested",
+			v: map[string]interface{}{
+				"replicas": float64(3),
+				"ports":    []interface{}{map[string]interface{}{"port": float64(80)}, map[string]interface{}{"port": float64(443)}},
+				"ratio":    float64(1),
+				"timeout":  float64(30),
+			},
+			reference: map[string]interface{}{
+				"replicas": int64(2),
+				"ports":    []interface{}{map[string]interface{}{"port": int64(8080)}},
+				"ratio":    0.5,
+			},
+			want: map[string]interface{}{
+				"replicas": int64(3),
+				"ports":    []interface{}{map[string]interface{}{"port": int64(80)}, map[string]interface{}{"port": float64(443)}},
+				"ratio":    float64(1),
+				"timeout":  float64(30),
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := NormalizeNumbersAs(tt.v, tt.reference); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("NormalizeNumbersAs() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
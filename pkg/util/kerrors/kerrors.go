@@ -0,0 +1,89 @@
+// Package kerrors defines the kinds of errors shared across Kusion. The domain specific errors, e.g.
+// the release not exist error of the release storage, are defined with one of the kinds by New, so
+// that the callers can branch on the kind by errors.Is, regardless of the storage or provider.
+package kerrors
+
+import "errors"
+
+var (
+	ErrNotFound         = errors.New("not found")
+	ErrConflict         = errors.New("conflict")
+	ErrAlreadyExists    = errors.New("already exists")
+	ErrNotSupported     = errors.New("not supported")
+	ErrValidation       = errors.New("validation failed")
+	ErrPermissionDenied = errors.New("permission denied")
+)
+
+// kindError is an error with the message, which matches the kind by errors.Is.
+type kindError struct {
+	kind error
+	msg  string
+}
+
+func (e *kindError) Error() string {
+	return e.msg
+}
+
+func (e *kindError) Unwrap() error {
+	return e.kind
+}
+
+// New returns an error with the message, which matches the kind by errors.Is. The returned error is
+// used as a sentinel error, and the kind is not included in the message.
+func New(kind error, msg string) error {
+	return &kindError{kind: kind, msg: msg}
+}
+
+// wrappedError is an error which matches both the wrapped error and the kind by errors.Is.
+type wrappedError struct {
+	kind error
+	err  error
+}
+
+func (e *wrappedError) Error() string {
+	return e.err.Error()
+}
+
+func (e *wrappedError) Unwrap() []error {
+	return []error{e.err, e.kind}
+}
+
+// Wrap marks the err with the kind, and the message of the err is kept. It is used at the boundaries
+// of the storages and providers to classify the errors returned by the underlying clients. Wrap
+// returns nil if err is nil.
+func Wrap(kind, err error) error {
+	if err == nil {
+		return nil
+	}
+	return &wrappedError{kind: kind, err: err}
+}
+
+// IsNotFound returns whether the err is of the kind ErrNotFound.
+func IsNotFound(err error) bool {
+	return errors.Is(err, ErrNotFound)
+}
+
+// IsConflict returns whether the err is of the kind ErrConflict.
+func IsConflict(err error) bool {
+	return errors.Is(err, ErrConflict)
+}
+
+// IsAlreadyExists returns whether the err is of the kind ErrAlreadyExists.
+func IsAlreadyExists(err error) bool {
+	return errors.Is(err, ErrAlreadyExists)
+}
+
+// IsNotSupported returns whether the err is of the kind ErrNotSupported.
+func IsNotSupported(err error) bool {
+	return errors.Is(err, ErrNotSupported)
+}
+
+// IsValidation returns whether the err is of the kind ErrValidation.
+func IsValidation(err error) bool {
+	return errors.Is(err, ErrValidation)
+}
+
+// IsPermissionDenied returns whether the err is of the kind ErrPermissionDenied.
+func IsPermissionDenied(err error) bool {
+	return errors.Is(err, ErrPermissionDenied)
+}
@@ -0,0 +1,71 @@
+package kerrors
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNew(t *testing.T) {
+	errReleaseNotExist := New(ErrNotFound, "release does not exist")
+	wrapped := fmt.Errorf("get release of project test failed: %w", fmt.Errorf("%w, revision 1", errReleaseNotExist))
+
+	assert.Equal(t, "release does not exist", errReleaseNotExist.Error())
+	assert.Equal(t, "get release of project test failed: release does not exist, revision 1", wrapped.Error())
+	assert.True(t, errors.Is(wrapped, errReleaseNotExist))
+	assert.True(t, IsNotFound(wrapped))
+	assert.False(t, IsAlreadyExists(wrapped))
+	assert.False(t, errors.Is(wrapped, New(ErrNotFound, "release does not exist")))
+}
+
+func TestWrap(t *testing.T) {
+	errClient := errors.New("connection refused")
+	testcases := []struct {
+		name     string
+		kind     error
+		err      error
+		expected []error
+	}{
+		{
+			name:     "nil error",
+			kind:     ErrConflict,
+			err:      nil,
+			expected: nil,
+		},
+		{
+			name:     "wrap client error",
+			kind:     ErrNotSupported,
+			err:      errClient,
+			expected: []error{errClient, ErrNotSupported},
+		},
+		{
+			name:     "wrap permission denied error",
+			kind:     ErrPermissionDenied,
+			err:      errClient,
+			expected: []error{errClient, ErrPermissionDenied},
+		},
+		{
+			name:     "wrap classified error",
+			kind:     ErrConflict,
+			err:      fmt.Errorf("create release failed: %w", New(ErrAlreadyExists, "release has already existed")),
+			expected: []error{ErrAlreadyExists, ErrConflict},
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := Wrap(tc.kind, tc.err)
+			if tc.err == nil {
+				assert.Nil(t, err)
+				return
+			}
+			assert.Equal(t, tc.err.Error(), err.Error())
+			for _, target := range tc.expected {
+				assert.True(t, errors.Is(fmt.Errorf("%w", err), target))
+			}
+			assert.False(t, IsValidation(err))
+		})
+	}
+}
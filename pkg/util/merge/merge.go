@@ -0,0 +1,285 @@
+// Package merge provides the 3-way merge of the structured objects, such as the unstructured
+// Kubernetes objects and the attributes of the resources.
+package merge
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+)
+
+// ArrayStrategy is the strategy to merge the arrays.
+type ArrayStrategy string
+
+const (
+	// ArrayReplace replaces the live array with the desired one as a whole, which is the same as the
+	// JSON merge patch.
+	ArrayReplace ArrayStrategy = "replace"
+	// ArrayMergeByKey merges the elements of the arrays of objects with the same merge key, such as
+	// the containers and env in Kubernetes, see WithMergeKey.
+	ArrayMergeByKey ArrayStrategy = "merge-by-key"
+)
+
+// DefaultMergeKey is the default merge key of the ArrayMergeByKey strategy.
+const DefaultMergeKey = "name"
+
+var (
+	ErrUnsupportedArrayStrategy = errors.New("unsupported array strategy")
+	ErrDuplicateMergeKey        = errors.New("duplicate merge key")
+)
+
+// DefaultKubernetesMergeKeys is the merge keys of the common arrays in the Kubernetes objects, such
+// as the containers, env, ports and volumeMounts of the Pod templates in the workloads, which can be
+// used by WithMergeKeys.
+var DefaultKubernetesMergeKeys = defaultKubernetesMergeKeys()
+
+func defaultKubernetesMergeKeys() map[string]string {
+	keys := map[string]string{
+		"metadata.ownerReferences": "uid",
+		// the ports of the Service
+		"spec.ports": "port",
+	}
+	podSpecKeys := map[string]string{
+		"containers":       "name",
+		"initContainers":   "name",
+		"volumes":          "name",
+		"imagePullSecrets": "name",
+		"hostAliases":      "ip",
+	}
+	containerKeys := map[string]string{
+		"env":           "name",
+		"ports":         "containerPort",
+		"volumeMounts":  "mountPath",
+		"volumeDevices": "devicePath",
+	}
+	// the Pod, the workloads with the Pod templates, and the CronJob
+	for _, podSpec := range []string{"spec", "spec.template.spec", "spec.jobTemplate.spec.template.spec"} {
+		for field, key := range podSpecKeys {
+			keys[podSpec+"."+field] = key
+		}
+		for _, containers := range []string{"containers", "initContainers"} {
+			for field, key := range containerKeys {
+				keys[podSpec+"."+containers+"[]."+field] = key
+			}
+		}
+	}
+	return keys
+}
+
+type options struct {
+	arrayStrategy ArrayStrategy
+	mergeKey      string
+	mergeKeys     map[string]string
+}
+
+// Option customizes how ThreeWayMerge merges the objects.
+type Option func(*options)
+
+// WithArrayStrategy sets the strategy to merge the arrays, defaults to ArrayReplace.
+func WithArrayStrategy(strategy ArrayStrategy) Option {
+	return func(o *options) {
+		o.arrayStrategy = strategy
+	}
+}
+
+// WithMergeKey sets the field identifying the elements of the arrays merged by ArrayMergeByKey,
+// defaults to DefaultMergeKey.
+func WithMergeKey(key string) Option {
+	return func(o *options) {
+		o.mergeKey = key
+	}
+}
+
+// WithMergeKeys sets the merge keys of the arrays by their paths, which are merged by key regardless
+// of the array strategy, e.g. {"spec.containers[].env": "name"} merges the env of each container by
+// name. A path is the field names joined by ".", where "[]" follows the name of an array whose elements
+// the path goes into. The paths not set follow the array strategy. See DefaultKubernetesMergeKeys for
+// the Kubernetes objects.
+func WithMergeKeys(keys map[string]string) Option {
+	return func(o *options) {
+		o.mergeKeys = keys
+	}
+}
+
+// ThreeWayMerge merges the desired object into the live one, where the base is the desired object
+// last applied. The changes of the desired object are applied over the live object, the fields in
+// the base but not in the desired object are deleted, and the fields only in the live object, which
+// are usually set by the server, are kept. A null field in the desired object is deleted, the same
+// as the JSON merge patch.
+//
+// The arrays are replaced as a whole by default, and can be merged by key with the ArrayMergeByKey
+// strategy or the merge keys of their paths set by WithMergeKeys, which falls back to replace if any
+// element is not an object with the merge key. None of the inputs is modified, and the result shares
+// no maps or arrays with them.
+func ThreeWayMerge(base, desired, live map[string]interface{}, opts ...Option) (map[string]interface{}, error) {
+	o := &options{arrayStrategy: ArrayReplace, mergeKey: DefaultMergeKey}
+	for _, opt := range opts {
+		opt(o)
+	}
+	if o.arrayStrategy != ArrayReplace && o.arrayStrategy != ArrayMergeByKey {
+		return nil, fmt.Errorf("%w: %s", ErrUnsupportedArrayStrategy, o.arrayStrategy)
+	}
+
+	if live == nil {
+		live = map[string]interface{}{}
+	}
+	return o.mergeMap(base, desired, live, "", "")
+}
+
+// The path locates the field in the errors, which identifies the array elements by their merge keys,
+// and the fieldPath is the path looked up in the merge keys set by WithMergeKeys.
+func (o *options) mergeMap(base, desired, live map[string]interface{}, path, fieldPath string) (map[string]interface{}, error) {
+	result := make(map[string]interface{}, len(live))
+	for k, v := range live {
+		result[k] = deepCopy(v)
+	}
+
+	// delete the fields removed from the desired object since last applied
+	for k := range base {
+		if _, ok := desired[k]; !ok {
+			delete(result, k)
+		}
+	}
+
+	for k, d := range desired {
+		if d == nil {
+			delete(result, k)
+			continue
+		}
+		l, ok := live[k]
+		if !ok {
+			result[k] = deepCopy(d)
+			continue
+		}
+		merged, err := o.merge(base[k], d, l, path+"."+k, joinFieldPath(fieldPath, k))
+		if err != nil {
+			return nil, err
+		}
+		result[k] = merged
+	}
+	return result, nil
+}
+
+func (o *options) merge(base, desired, live interface{}, path, fieldPath string) (interface{}, error) {
+	switch d := desired.(type) {
+	case map[string]interface{}:
+		l, ok := live.(map[string]interface{})
+		if !ok {
+			return deepCopy(d), nil
+		}
+		b, _ := base.(map[string]interface{})
+		return o.mergeMap(b, d, l, path, fieldPath)
+	case []interface{}:
+		l, ok := live.([]interface{})
+		if !ok {
+			return deepCopy(d), nil
+		}
+		key, ok := o.mergeKeys[fieldPath]
+		if !ok {
+			if o.arrayStrategy != ArrayMergeByKey {
+				return deepCopy(d), nil
+			}
+			key = o.mergeKey
+		}
+		b, _ := base.([]interface{})
+		return o.mergeArrayByKey(b, d, l, key, path, fieldPath+"[]")
+	default:
+		return deepCopy(d), nil
+	}
+}
+
+// mergeArrayByKey merges the elements with the same merge key in the order of the desired array,
+// followed by the elements only in the live array. The elements in the base array but not in the
+// desired one are deleted.
+func (o *options) mergeArrayByKey(base, desired, live []interface{}, mergeKey, path, fieldPath string) (interface{}, error) {
+	desiredByKey, ok, err := indexByKey(desired, mergeKey, path)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return deepCopy(desired), nil
+	}
+	liveByKey, ok, err := indexByKey(live, mergeKey, path)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return deepCopy(desired), nil
+	}
+	// the base array doesn't affect the result if it can't be indexed, which means nothing is deleted
+	baseByKey, _, _ := indexByKey(base, mergeKey, path)
+
+	result := make([]interface{}, 0, len(desired)+len(live))
+	for _, elem := range desired {
+		d := elem.(map[string]interface{})
+		key := d[mergeKey]
+		l, ok := liveByKey[key]
+		if !ok {
+			result = append(result, deepCopy(d))
+			continue
+		}
+		merged, err := o.mergeMap(baseByKey[key], d, l, fmt.Sprintf("%s[%s=%v]", path, mergeKey, key), fieldPath)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, merged)
+	}
+	for _, elem := range live {
+		key := elem.(map[string]interface{})[mergeKey]
+		if _, ok := desiredByKey[key]; ok {
+			continue
+		}
+		if _, ok := baseByKey[key]; ok {
+			continue
+		}
+		result = append(result, deepCopy(elem))
+	}
+	return result, nil
+}
+
+// indexByKey indexes the elements of the array by the merge key, and returns false if any element
+// is not an object with a comparable merge key.
+func indexByKey(array []interface{}, mergeKey, path string) (map[interface{}]map[string]interface{}, bool, error) {
+	index := make(map[interface{}]map[string]interface{}, len(array))
+	for _, elem := range array {
+		m, ok := elem.(map[string]interface{})
+		if !ok {
+			return nil, false, nil
+		}
+		key, ok := m[mergeKey]
+		if !ok || key == nil || !reflect.TypeOf(key).Comparable() {
+			return nil, false, nil
+		}
+		if _, ok = index[key]; ok {
+			return nil, false, fmt.Errorf("%w: %s=%v in %s", ErrDuplicateMergeKey, mergeKey, key, path)
+		}
+		index[key] = m
+	}
+	return index, true, nil
+}
+
+func joinFieldPath(fieldPath, field string) string {
+	if fieldPath == "" {
+		return field
+	}
+	return fieldPath + "." + field
+}
+
+func deepCopy(v interface{}) interface{} {
+	switch t := v.(type) {
+	case map[string]interface{}:
+		m := make(map[string]interface{}, len(t))
+		for k, e := range t {
+			m[k] = deepCopy(e)
+		}
+		return m
+	case []interface{}:
+		s := make([]interface{}, len(t))
+		for i, e := range t {
+			s[i] = deepCopy(e)
+		}
+		return s
+	default:
+		return v
+	}
+}
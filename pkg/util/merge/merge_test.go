@@ -0,0 +1,240 @@
+package merge
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func container(name, image string, extra ...string) map[string]interface{} {
+	c := map[string]interface{}{"name": name, "image": image}
+	for i := 0; i+1 < len(extra); i += 2 {
+		c[extra[i]] = extra[i+1]
+	}
+	return c
+}
+
+func envVar(name, value string) map[string]interface{} {
+	return map[string]interface{}{"name": name, "value": value}
+}
+
+func podTemplate(containers ...interface{}) map[string]interface{} {
+	return map[string]interface{}{"spec": map[string]interface{}{"template": map[string]interface{}{
+		"spec": map[string]interface{}{"containers": containers},
+	}}}
+}
+
+func withEnv(c map[string]interface{}, env ...interface{}) map[string]interface{} {
+	c["env"] = env
+	return c
+}
+
+func TestThreeWayMerge(t *testing.T) {
+	testcases := []struct {
+		name     string
+		base     map[string]interface{}
+		desired  map[string]interface{}
+		live     map[string]interface{}
+		opts     []Option
+		expected map[string]interface{}
+		err      error
+	}{
+		{
+			name:     "create without base and live",
+			desired:  map[string]interface{}{"a": "1", "b": map[string]interface{}{"c": "2"}},
+			expected: map[string]interface{}{"a": "1", "b": map[string]interface{}{"c": "2"}},
+		},
+		{
+			name:     "add field",
+			base:     map[string]interface{}{"a": "1"},
+			desired:  map[string]interface{}{"a": "1", "b": "2"},
+			live:     map[string]interface{}{"a": "1"},
+			expected: map[string]interface{}{"a": "1", "b": "2"},
+		},
+		{
+			name:     "change field",
+			base:     map[string]interface{}{"a": map[string]interface{}{"b": "1"}},
+			desired:  map[string]interface{}{"a": map[string]interface{}{"b": "2"}},
+			live:     map[string]interface{}{"a": map[string]interface{}{"b": "3", "c": "4"}},
+			expected: map[string]interface{}{"a": map[string]interface{}{"b": "2", "c": "4"}},
+		},
+		{
+			name:     "delete field in base",
+			base:     map[string]interface{}{"a": "1", "b": map[string]interface{}{"c": "2", "d": "3"}},
+			desired:  map[string]interface{}{"b": map[string]interface{}{"c": "2"}},
+			live:     map[string]interface{}{"a": "1", "b": map[string]interface{}{"c": "2", "d": "3"}},
+			expected: map[string]interface{}{"b": map[string]interface{}{"c": "2"}},
+		},
+		{
+			name:     "keep live only field",
+			base:     map[string]interface{}{"a": "1"},
+			desired:  map[string]interface{}{"a": "1"},
+			live:     map[string]interface{}{"a": "1", "status": map[string]interface{}{"ready": true}},
+			expected: map[string]interface{}{"a": "1", "status": map[string]interface{}{"ready": true}},
+		},
+		{
+			name:     "delete null field",
+			desired:  map[string]interface{}{"a": nil},
+			live:     map[string]interface{}{"a": "1", "b": "2"},
+			expected: map[string]interface{}{"b": "2"},
+		},
+		{
+			name:     "replace mismatched types",
+			desired:  map[string]interface{}{"a": map[string]interface{}{"b": "1"}},
+			live:     map[string]interface{}{"a": "1"},
+			expected: map[string]interface{}{"a": map[string]interface{}{"b": "1"}},
+		},
+		{
+			name:    "replace array by default",
+			base:    map[string]interface{}{"containers": []interface{}{container("foo", "nginx:1")}},
+			desired: map[string]interface{}{"containers": []interface{}{container("foo", "nginx:2")}},
+			live: map[string]interface{}{"containers": []interface{}{
+				container("foo", "nginx:1", "imagePullPolicy", "Always"),
+				container("sidecar", "envoy"),
+			}},
+			expected: map[string]interface{}{"containers": []interface{}{container("foo", "nginx:2")}},
+		},
+		{
+			name:    "merge array by key",
+			base:    map[string]interface{}{"containers": []interface{}{container("foo", "nginx:1"), container("bar", "redis")}},
+			desired: map[string]interface{}{"containers": []interface{}{container("baz", "mysql"), container("foo", "nginx:2")}},
+			live: map[string]interface{}{"containers": []interface{}{
+				container("foo", "nginx:1", "imagePullPolicy", "Always"),
+				container("bar", "redis"),
+				container("sidecar", "envoy"),
+			}},
+			opts: []Option{WithArrayStrategy(ArrayMergeByKey)},
+			expected: map[string]interface{}{"containers": []interface{}{
+				container("baz", "mysql"),
+				container("foo", "nginx:2", "imagePullPolicy", "Always"),
+				container("sidecar", "envoy"),
+			}},
+		},
+		{
+			name:    "merge array by custom key",
+			desired: map[string]interface{}{"ports": []interface{}{map[string]interface{}{"port": int64(80), "protocol": "TCP"}}},
+			live: map[string]interface{}{"ports": []interface{}{
+				map[string]interface{}{"port": int64(80), "targetPort": int64(8080)},
+				map[string]interface{}{"port": int64(443)},
+			}},
+			opts: []Option{WithArrayStrategy(ArrayMergeByKey), WithMergeKey("port")},
+			expected: map[string]interface{}{"ports": []interface{}{
+				map[string]interface{}{"port": int64(80), "protocol": "TCP", "targetPort": int64(8080)},
+				map[string]interface{}{"port": int64(443)},
+			}},
+		},
+		{
+			name:     "replace array without merge key",
+			desired:  map[string]interface{}{"args": []interface{}{"-v", "-d"}},
+			live:     map[string]interface{}{"args": []interface{}{"-v"}},
+			opts:     []Option{WithArrayStrategy(ArrayMergeByKey)},
+			expected: map[string]interface{}{"args": []interface{}{"-v", "-d"}},
+		},
+		{
+			name:    "duplicate merge key",
+			desired: map[string]interface{}{"containers": []interface{}{container("foo", "nginx:1"), container("foo", "nginx:2")}},
+			live:    map[string]interface{}{"containers": []interface{}{container("foo", "nginx:1")}},
+			opts:    []Option{WithArrayStrategy(ArrayMergeByKey)},
+			err:     ErrDuplicateMergeKey,
+		},
+		{
+			name: "merge env by name with merge keys",
+			base: podTemplate(withEnv(container("foo", "nginx:1"), envVar("A", "1"), envVar("B", "2"))),
+			desired: podTemplate(withEnv(container("foo", "nginx:2"),
+				envVar("C", "3"), envVar("A", "10"))),
+			live: podTemplate(withEnv(container("foo", "nginx:1", "imagePullPolicy", "Always"),
+				envVar("A", "1"), envVar("B", "2"), envVar("INJECTED", "true"))),
+			opts: []Option{WithMergeKeys(DefaultKubernetesMergeKeys)},
+			expected: podTemplate(withEnv(container("foo", "nginx:2", "imagePullPolicy", "Always"),
+				envVar("C", "3"), envVar("A", "10"), envVar("INJECTED", "true"))),
+		},
+		{
+			name: "merge keys override array strategy",
+			desired: map[string]interface{}{"spec": map[string]interface{}{
+				"ports": []interface{}{map[string]interface{}{"port": int64(80), "protocol": "TCP"}},
+				"args":  []interface{}{map[string]interface{}{"name": "-v"}},
+			}},
+			live: map[string]interface{}{"spec": map[string]interface{}{
+				"ports": []interface{}{
+					map[string]interface{}{"port": int64(80), "targetPort": int64(8080)},
+					map[string]interface{}{"port": int64(443)},
+				},
+				"args": []interface{}{map[string]interface{}{"name": "-d"}},
+			}},
+			opts: []Option{WithArrayStrategy(ArrayReplace), WithMergeKeys(map[string]string{"spec.ports": "port"})},
+			expected: map[string]interface{}{"spec": map[string]interface{}{
+				"ports": []interface{}{
+					map[string]interface{}{"port": int64(80), "protocol": "TCP", "targetPort": int64(8080)},
+					map[string]interface{}{"port": int64(443)},
+				},
+				"args": []interface{}{map[string]interface{}{"name": "-v"}},
+			}},
+		},
+		{
+			name:    "replace env without merge keys",
+			desired: podTemplate(withEnv(container("foo", "nginx:1"), envVar("A", "1"))),
+			live:    podTemplate(withEnv(container("foo", "nginx:1"), envVar("B", "2"))),
+			opts: []Option{WithMergeKeys(map[string]string{
+				"spec.template.spec.containers": "name",
+			})},
+			expected: podTemplate(withEnv(container("foo", "nginx:1"), envVar("A", "1"))),
+		},
+		{
+			name: "duplicate merge key in nested array",
+			desired: podTemplate(withEnv(container("foo", "nginx:1"),
+				envVar("A", "1"), envVar("A", "2"))),
+			live: podTemplate(withEnv(container("foo", "nginx:1"), envVar("A", "1"))),
+			opts: []Option{WithMergeKeys(DefaultKubernetesMergeKeys)},
+			err:  ErrDuplicateMergeKey,
+		},
+		{
+			name:    "unsupported array strategy",
+			desired: map[string]interface{}{"a": "1"},
+			opts:    []Option{WithArrayStrategy("append")},
+			err:     ErrUnsupportedArrayStrategy,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			actual, err := ThreeWayMerge(tc.base, tc.desired, tc.live, tc.opts...)
+			if tc.err != nil {
+				assert.True(t, errors.Is(err, tc.err), "unexpected error: %v", err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expected, actual)
+		})
+	}
+}
+
+func TestThreeWayMergeDoesNotModifyInputs(t *testing.T) {
+	base := map[string]interface{}{"spec": map[string]interface{}{"replicas": int64(1), "paused": true}}
+	desired := map[string]interface{}{"spec": map[string]interface{}{"replicas": int64(2)}}
+	live := map[string]interface{}{"spec": map[string]interface{}{"replicas": int64(1), "paused": true}}
+
+	actual, err := ThreeWayMerge(base, desired, live)
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{"spec": map[string]interface{}{"replicas": int64(2)}}, actual)
+
+	actual["spec"].(map[string]interface{})["replicas"] = int64(3)
+	assert.Equal(t, map[string]interface{}{"spec": map[string]interface{}{"replicas": int64(1), "paused": true}}, base)
+	assert.Equal(t, map[string]interface{}{"spec": map[string]interface{}{"replicas": int64(2)}}, desired)
+	assert.Equal(t, map[string]interface{}{"spec": map[string]interface{}{"replicas": int64(1), "paused": true}}, live)
+}
+
+func TestDefaultKubernetesMergeKeys(t *testing.T) {
+	for path, key := range map[string]string{
+		"spec.containers":                                      "name",
+		"spec.containers[].env":                                "name",
+		"spec.template.spec.containers[].env":                  "name",
+		"spec.template.spec.containers[].ports":                "containerPort",
+		"spec.template.spec.initContainers[].volumeMounts":     "mountPath",
+		"spec.template.spec.volumes":                           "name",
+		"spec.jobTemplate.spec.template.spec.containers[].env": "name",
+		"spec.ports": "port",
+	} {
+		assert.Equal(t, key, DefaultKubernetesMergeKeys[path], path)
+	}
+}
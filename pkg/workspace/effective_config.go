@@ -0,0 +1,120 @@
+package workspace
+
+import (
+	"fmt"
+	"strings"
+
+	v1 "kusionstack.io/kusion/pkg/apis/api.kusion.io/v1"
+)
+
+// The prefixes of the secret references in the configs, which are resolved from the secret store at
+// apply time, such as ref://db/password and ${secret:db.password}.
+const (
+	secretRefPrefix          = "ref://"
+	secretInterpolatedPrefix = "${secret:"
+)
+
+// SecretRefPlaceholder formats the placeholder shown in place of a secret reference in the effective
+// config, so that it is never mistaken for a resolved value.
+const SecretRefPlaceholder = "<secret %s>"
+
+// WorkspaceEffectiveConfig is the config of a workspace resolved for a project, which shows what the
+// modules actually get when generating the project, for debugging.
+type WorkspaceEffectiveConfig struct {
+	// Workspace is the name of the workspace.
+	Workspace string `yaml:"workspace" json:"workspace"`
+
+	// Project is the name of the project the config is resolved for.
+	Project string `yaml:"project" json:"project"`
+
+	// Modules are the effective module configs, whose key is the module name.
+	Modules map[string]*EffectiveModuleConfig `yaml:"modules,omitempty" json:"modules,omitempty"`
+}
+
+// EffectiveModuleConfig is the config of a module resolved for a project.
+type EffectiveModuleConfig struct {
+	// Path is the path of the module.
+	Path string `yaml:"path,omitempty" json:"path,omitempty"`
+
+	// Version is the version of the module.
+	Version string `yaml:"version,omitempty" json:"version,omitempty"`
+
+	// Patcher is the name of the patcher block applied to the default config, empty if no patcher
+	// block selects the project.
+	Patcher string `yaml:"patcher,omitempty" json:"patcher,omitempty"`
+
+	// Config is the fully resolved config, where the config references are expanded and the secret
+	// references are shown as placeholders.
+	Config v1.GenericConfig `yaml:"config,omitempty" json:"config,omitempty"`
+}
+
+// EffectiveConfig returns the module configs of the workspace resolved for the project, by merging the
+// default block and the patcher block selecting the project and expanding the config references, as
+// the modules get them in generation. The secret references are shown as placeholders rather than
+// resolved.
+func EffectiveConfig(workspace *v1.Workspace, project string) (*WorkspaceEffectiveConfig, error) {
+	if project == "" {
+		return nil, ErrEmptyProjectName
+	}
+	effective := &WorkspaceEffectiveConfig{
+		Workspace: workspace.Name,
+		Project:   project,
+	}
+	if len(workspace.Modules) == 0 {
+		return effective, nil
+	}
+
+	effective.Modules = make(map[string]*EffectiveModuleConfig, len(workspace.Modules))
+	for name, moduleConfig := range workspace.Modules {
+		if moduleConfig == nil {
+			continue
+		}
+		config, err := getProjectModuleConfig(moduleConfig, project)
+		if err != nil {
+			return nil, fmt.Errorf("%w, module name: %s", err, name)
+		}
+		patcher, _ := matchPatcherConfig(moduleConfig, project)
+		effective.Modules[name] = &EffectiveModuleConfig{
+			Path:    moduleConfig.Path,
+			Version: moduleConfig.Version,
+			Patcher: patcher,
+			Config:  maskSecretRefs(config).(v1.GenericConfig),
+		}
+	}
+	return effective, nil
+}
+
+// maskSecretRefs returns a copy of the value where the strings containing secret references are replaced
+// by the placeholders.
+func maskSecretRefs(value any) any {
+	switch v := value.(type) {
+	case v1.GenericConfig:
+		if v == nil {
+			return v
+		}
+		masked := make(v1.GenericConfig, len(v))
+		for k, item := range v {
+			masked[k] = maskSecretRefs(item)
+		}
+		return masked
+	case map[string]any:
+		masked := make(map[string]any, len(v))
+		for k, item := range v {
+			masked[k] = maskSecretRefs(item)
+		}
+		return masked
+	case []any:
+		masked := make([]any, len(v))
+		for i, item := range v {
+			masked[i] = maskSecretRefs(item)
+		}
+		return masked
+	case string:
+		if strings.Contains(v, secretRefPrefix) || strings.Contains(v, secretInterpolatedPrefix) {
+			return fmt.Sprintf(SecretRefPlaceholder, v)
+		}
+		return v
+	default:
+		return v
+	}
+}
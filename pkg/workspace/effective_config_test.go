@@ -0,0 +1,106 @@
+package workspace
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/yaml.v3"
+
+	v1 "kusionstack.io/kusion/pkg/apis/api.kusion.io/v1"
+)
+
+func mockEffectiveConfigWorkspace() *v1.Workspace {
+	ws := mockValidWorkspace("dev")
+	ws.Modules["mysql"].Configs.Default["username"] = "root"
+	ws.Modules["mysql"].Configs.Default["password"] = "ref://mysql/password"
+	ws.Modules["mysql"].Configs.Default["dsn"] = "${config.username}:${config.password}@tcp(mysql:3306)"
+	ws.Modules["mysql"].Configs.Default["endpoint"] = "https://${secret:mysql.host}:3306"
+	ws.Modules["mysql"].Configs.Default["labels"] = map[string]any{"tier": "${config.instanceType}"}
+	return ws
+}
+
+func TestEffectiveConfig(t *testing.T) {
+	testcases := []struct {
+		name     string
+		project  string
+		expected *WorkspaceEffectiveConfig
+	}{
+		{
+			name:    "project with matching patcher",
+			project: "foo",
+			expected: &WorkspaceEffectiveConfig{
+				Workspace: "dev",
+				Project:   "foo",
+				Modules: map[string]*EffectiveModuleConfig{
+					"mysql": {
+						Path:    "ghcr.io/kusionstack/mysql",
+						Version: "0.1.0",
+						Patcher: "smallClass",
+						Config: v1.GenericConfig{
+							"type":         "aws",
+							"version":      "5.7",
+							"instanceType": "db.t3.small",
+							"username":     "root",
+							"password":     "<secret ref://mysql/password>",
+							"dsn":          "<secret root:ref://mysql/password@tcp(mysql:3306)>",
+							"endpoint":     "<secret https://${secret:mysql.host}:3306>",
+							"labels":       map[string]any{"tier": "db.t3.small"},
+						},
+					},
+					"network": {
+						Path:    "ghcr.io/kusionstack/network",
+						Version: "0.1.0",
+						Config:  v1.GenericConfig{"type": "aws"},
+					},
+				},
+			},
+		},
+		{
+			name:    "project without matching patcher",
+			project: "qux",
+			expected: &WorkspaceEffectiveConfig{
+				Workspace: "dev",
+				Project:   "qux",
+				Modules: map[string]*EffectiveModuleConfig{
+					"mysql": {
+						Path:    "ghcr.io/kusionstack/mysql",
+						Version: "0.1.0",
+						Config: v1.GenericConfig{
+							"type":         "aws",
+							"version":      "5.7",
+							"instanceType": "db.t3.micro",
+							"username":     "root",
+							"password":     "<secret ref://mysql/password>",
+							"dsn":          "<secret root:ref://mysql/password@tcp(mysql:3306)>",
+							"endpoint":     "<secret https://${secret:mysql.host}:3306>",
+							"labels":       map[string]any{"tier": "db.t3.micro"},
+						},
+					},
+					"network": {
+						Path:    "ghcr.io/kusionstack/network",
+						Version: "0.1.0",
+						Config:  v1.GenericConfig{"type": "aws"},
+					},
+				},
+			},
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			ws := mockEffectiveConfigWorkspace()
+			actual, err := EffectiveConfig(ws, tc.project)
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expected, actual)
+			// the workspace is left unchanged
+			assert.Equal(t, mockEffectiveConfigWorkspace(), ws)
+
+			// the effective config is serializable
+			_, err = yaml.Marshal(actual)
+			assert.NoError(t, err)
+		})
+	}
+
+	_, err := EffectiveConfig(mockEffectiveConfigWorkspace(), "")
+	assert.ErrorIs(t, err, ErrEmptyProjectName)
+}
@@ -0,0 +1,190 @@
+package workspace
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gofrs/flock"
+)
+
+// cacheLockSuffix is the suffix of the lock file beside each cached module, whose shared lock marks the
+// module in use, and exclusive lock is taken to evict the module.
+const cacheLockSuffix = ".lock"
+
+// ModuleCacheLocks marks the cached modules in use with the shared locks of their lock files, so that
+// they are never evicted by CleanCache until released. The zero value is ready to use.
+type ModuleCacheLocks struct {
+	mu    sync.Mutex
+	locks map[string]*flock.Flock
+}
+
+// Acquire marks the cached module in the dir in use until the locks are closed.
+func (l *ModuleCacheLocks) Acquire(dir string) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if _, ok := l.locks[dir]; ok {
+		return nil
+	}
+	if err := os.MkdirAll(filepath.Dir(dir), 0o755); err != nil {
+		return err
+	}
+	lock := flock.New(dir + cacheLockSuffix)
+	if err := lock.RLock(); err != nil {
+		return fmt.Errorf("failed to lock module cache %s: %w", dir, err)
+	}
+	if l.locks == nil {
+		l.locks = make(map[string]*flock.Flock)
+	}
+	l.locks[dir] = lock
+	return nil
+}
+
+// Close releases the cached modules, which can be evicted by CleanCache afterward.
+func (l *ModuleCacheLocks) Close() error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	var errs []error
+	for dir, lock := range l.locks {
+		errs = append(errs, lock.Close())
+		delete(l.locks, dir)
+	}
+	return errors.Join(errs...)
+}
+
+// CleanCacheOptions are the options of CleanCache.
+type CleanCacheOptions struct {
+	// MaxSize is the max total size in bytes of the cached modules, and the least recently used ones are
+	// evicted until the total size is within it. Zero means no limit.
+	MaxSize int64
+	// MaxAge is the max duration since the cached modules were last used, and the older ones are evicted.
+	// Zero means no limit.
+	MaxAge time.Duration
+}
+
+// CleanCacheResult is the result of CleanCache.
+type CleanCacheResult struct {
+	// Entries is the number of the evicted modules.
+	Entries int
+	// Bytes is the total size in bytes of the evicted modules.
+	Bytes int64
+}
+
+type cacheEntry struct {
+	dir        string
+	size       int64
+	accessTime time.Time
+}
+
+// CleanCache evicts the cached modules in cacheDir beyond the max age first, and then the least recently
+// used ones beyond the max size. The modules in use, i.e. resolved by a resolver not yet closed in any
+// process, are never evicted.
+func CleanCache(cacheDir string, opts CleanCacheOptions) (*CleanCacheResult, error) {
+	entries, err := listCacheEntries(cacheDir)
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].accessTime.Before(entries[j].accessTime)
+	})
+
+	var total int64
+	for _, entry := range entries {
+		total += entry.size
+	}
+
+	result := &CleanCacheResult{}
+	now := time.Now()
+	for _, entry := range entries {
+		expired := opts.MaxAge > 0 && now.Sub(entry.accessTime) > opts.MaxAge
+		oversize := opts.MaxSize > 0 && total > opts.MaxSize
+		if !expired && !oversize {
+			continue
+		}
+		evicted, err := evictCacheEntry(entry)
+		if err != nil {
+			return result, err
+		}
+		if evicted {
+			result.Entries++
+			result.Bytes += entry.size
+			total -= entry.size
+		}
+	}
+
+	return result, nil
+}
+
+// listCacheEntries lists the cached modules, skipping the temporary directories of the ongoing clones.
+func listCacheEntries(cacheDir string) ([]*cacheEntry, error) {
+	dirEntries, err := os.ReadDir(cacheDir)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var entries []*cacheEntry
+	for _, dirEntry := range dirEntries {
+		if !dirEntry.IsDir() || strings.HasPrefix(dirEntry.Name(), ".") {
+			continue
+		}
+		info, err := dirEntry.Info()
+		if err != nil {
+			return nil, err
+		}
+		dir := filepath.Join(cacheDir, dirEntry.Name())
+		size, err := dirSize(dir)
+		if err != nil {
+			return nil, err
+		}
+		entries = append(entries, &cacheEntry{dir: dir, size: size, accessTime: info.ModTime()})
+	}
+	return entries, nil
+}
+
+// evictCacheEntry removes the cached module unless it is in use, and returns whether it is evicted.
+func evictCacheEntry(entry *cacheEntry) (bool, error) {
+	lock := flock.New(entry.dir + cacheLockSuffix)
+	locked, err := lock.TryLock()
+	if err != nil {
+		return false, fmt.Errorf("failed to lock module cache %s: %w", entry.dir, err)
+	}
+	if !locked {
+		return false, nil
+	}
+	defer lock.Close()
+
+	// The empty lock file is kept, for removing it races with the resolvers waiting for its lock.
+	if err = os.RemoveAll(entry.dir); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+func dirSize(dir string) (int64, error) {
+	var size int64
+	err := filepath.WalkDir(dir, func(_ string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.Type().IsRegular() {
+			info, err := d.Info()
+			if err != nil {
+				return err
+			}
+			size += info.Size()
+		}
+		return nil
+	})
+	return size, err
+}
@@ -0,0 +1,117 @@
+package workspace
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/gofrs/flock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newCacheEntry creates a cached module of the given size, which was last used the given duration ago.
+func newCacheEntry(t *testing.T, cacheDir, name string, size int, age time.Duration) string {
+	dir := filepath.Join(cacheDir, name)
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, "modules"), 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "modules", "main.k"), make([]byte, size), 0o644))
+	accessTime := time.Now().Add(-age)
+	require.NoError(t, os.Chtimes(dir, accessTime, accessTime))
+	return dir
+}
+
+func TestCleanCache(t *testing.T) {
+	testcases := []struct {
+		name            string
+		opts            CleanCacheOptions
+		inUse           []string
+		expectedResult  *CleanCacheResult
+		expectedEntries []string
+	}{
+		{
+			name:            "no limit",
+			opts:            CleanCacheOptions{},
+			expectedResult:  &CleanCacheResult{},
+			expectedEntries: []string{"a", "b", "c"},
+		},
+		{
+			name:            "evict by max age",
+			opts:            CleanCacheOptions{MaxAge: 90 * time.Minute},
+			expectedResult:  &CleanCacheResult{Entries: 2, Bytes: 300},
+			expectedEntries: []string{"c"},
+		},
+		{
+			name:            "evict least recently used by max size",
+			opts:            CleanCacheOptions{MaxSize: 350},
+			expectedResult:  &CleanCacheResult{Entries: 1, Bytes: 100},
+			expectedEntries: []string{"b", "c"},
+		},
+		{
+			name:            "evict by max age and max size",
+			opts:            CleanCacheOptions{MaxAge: 150 * time.Minute, MaxSize: 150},
+			expectedResult:  &CleanCacheResult{Entries: 2, Bytes: 300},
+			expectedEntries: []string{"c"},
+		},
+		{
+			name:            "never evict modules in use",
+			opts:            CleanCacheOptions{MaxSize: 1},
+			inUse:           []string{"a"},
+			expectedResult:  &CleanCacheResult{Entries: 2, Bytes: 350},
+			expectedEntries: []string{"a"},
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			cacheDir := t.TempDir()
+			newCacheEntry(t, cacheDir, "a", 100, 3*time.Hour)
+			newCacheEntry(t, cacheDir, "b", 200, 2*time.Hour)
+			newCacheEntry(t, cacheDir, "c", 150, time.Hour)
+			newCacheEntry(t, cacheDir, ".clone-123", 1000, 4*time.Hour)
+			for _, name := range tc.inUse {
+				lock := flock.New(filepath.Join(cacheDir, name) + cacheLockSuffix)
+				require.NoError(t, lock.RLock())
+				defer lock.Close()
+			}
+
+			result, err := CleanCache(cacheDir, tc.opts)
+			require.NoError(t, err)
+			assert.Equal(t, tc.expectedResult, result)
+
+			entries, err := listCacheEntries(cacheDir)
+			require.NoError(t, err)
+			var names []string
+			for _, entry := range entries {
+				names = append(names, filepath.Base(entry.dir))
+			}
+			assert.ElementsMatch(t, tc.expectedEntries, names)
+			assert.DirExists(t, filepath.Join(cacheDir, ".clone-123"))
+		})
+	}
+}
+
+func TestCleanCache_NotExist(t *testing.T) {
+	result, err := CleanCache(filepath.Join(t.TempDir(), "not-exist"), CleanCacheOptions{MaxSize: 1})
+	assert.NoError(t, err)
+	assert.Equal(t, &CleanCacheResult{}, result)
+}
+
+func TestModuleCacheLocks(t *testing.T) {
+	cacheDir := t.TempDir()
+	dir := newCacheEntry(t, cacheDir, "mysql_0.1.0", 1024, 2*time.Hour)
+
+	inUse := &ModuleCacheLocks{}
+	require.NoError(t, inUse.Acquire(dir))
+	require.NoError(t, inUse.Acquire(dir))
+	result, err := CleanCache(cacheDir, CleanCacheOptions{MaxAge: time.Hour})
+	require.NoError(t, err)
+	assert.Equal(t, 0, result.Entries)
+	assert.DirExists(t, dir)
+
+	require.NoError(t, inUse.Close())
+	result, err = CleanCache(cacheDir, CleanCacheOptions{MaxAge: time.Hour})
+	require.NoError(t, err)
+	assert.Equal(t, 1, result.Entries)
+	assert.NoDirExists(t, dir)
+}
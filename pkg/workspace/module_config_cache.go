@@ -0,0 +1,297 @@
+package workspace
+
+import (
+	"container/list"
+	"fmt"
+	"hash/fnv"
+	"sort"
+	"strconv"
+	"sync"
+
+	v1 "kusionstack.io/kusion/pkg/apis/api.kusion.io/v1"
+)
+
+// DefaultModuleConfigCacheSize is the default max number of the resolved module configs kept by the
+// ModuleConfigCache.
+const DefaultModuleConfigCacheSize = 256
+
+// moduleConfigKey is the key of the resolved module config, where the hash is of the module config in the
+// workspace, so that the entry is never hit once the workspace changes.
+type moduleConfigKey struct {
+	module  string
+	project string
+	hash    uint64
+}
+
+type moduleConfigEntry struct {
+	key    moduleConfigKey
+	config v1.GenericConfig
+}
+
+// ModuleConfigCache is an LRU cache of the module configs resolved for the projects, i.e. the default
+// config merged with the patcher config selecting the project, with the references resolved. It's safe
+// for concurrent use, and a nil ModuleConfigCache resolves the module configs without caching.
+type ModuleConfigCache struct {
+	mu      sync.Mutex
+	size    int
+	entries *list.List
+	index   map[moduleConfigKey]*list.Element
+}
+
+// NewModuleConfigCache returns a ModuleConfigCache keeping at most size resolved module configs, where
+// the non-positive size is DefaultModuleConfigCacheSize.
+func NewModuleConfigCache(size int) *ModuleConfigCache {
+	if size <= 0 {
+		size = DefaultModuleConfigCacheSize
+	}
+	return &ModuleConfigCache{
+		size:    size,
+		entries: list.New(),
+		index:   make(map[moduleConfigKey]*list.Element),
+	}
+}
+
+// GetProjectModuleConfigs is the cached GetProjectModuleConfigs.
+func (c *ModuleConfigCache) GetProjectModuleConfigs(configs v1.ModuleConfigs, projectName string) (map[string]v1.GenericConfig, error) {
+	if c == nil {
+		return GetProjectModuleConfigs(configs, projectName)
+	}
+	if len(configs) == 0 {
+		return nil, nil
+	}
+	if projectName == "" {
+		return nil, ErrEmptyProjectName
+	}
+
+	projectConfigs := make(map[string]v1.GenericConfig)
+	for name, cfg := range configs {
+		moduleConfig, err := c.GetProjectModuleConfig(name, cfg, projectName)
+		if err != nil {
+			return nil, fmt.Errorf("%w, module name: %s", err, name)
+		}
+		if len(moduleConfig) != 0 {
+			projectConfigs[name] = moduleConfig
+		}
+	}
+	return projectConfigs, nil
+}
+
+// GetProjectModuleConfig is the cached GetProjectModuleConfig of the module with the name. The returned
+// config is a deep copy, which is free to modify.
+func (c *ModuleConfigCache) GetProjectModuleConfig(name string, config *v1.ModuleConfig, projectName string) (v1.GenericConfig, error) {
+	if c == nil || config == nil {
+		return GetProjectModuleConfig(config, projectName)
+	}
+	if projectName == "" {
+		return nil, ErrEmptyProjectName
+	}
+
+	hash, err := hashModuleConfig(config)
+	if err != nil {
+		// the module config with the values unable to hash is resolved without caching
+		return getProjectModuleConfig(config, projectName)
+	}
+	key := moduleConfigKey{module: name, project: projectName, hash: hash}
+	if cached, ok := c.get(key); ok {
+		return copyGenericConfig(cached), nil
+	}
+
+	resolved, err := getProjectModuleConfig(config, projectName)
+	if err != nil {
+		return nil, err
+	}
+	c.add(key, copyGenericConfig(resolved))
+	return resolved, nil
+}
+
+// Purge removes all the cached module configs.
+func (c *ModuleConfigCache) Purge() {
+	if c == nil {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.entries.Init()
+	c.index = make(map[moduleConfigKey]*list.Element)
+}
+
+// Len returns the number of the cached module configs.
+func (c *ModuleConfigCache) Len() int {
+	if c == nil {
+		return 0
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.entries.Len()
+}
+
+func (c *ModuleConfigCache) get(key moduleConfigKey) (v1.GenericConfig, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	e, ok := c.index[key]
+	if !ok {
+		return nil, false
+	}
+	c.entries.MoveToFront(e)
+	return e.Value.(*moduleConfigEntry).config, true
+}
+
+func (c *ModuleConfigCache) add(key moduleConfigKey, config v1.GenericConfig) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if e, ok := c.index[key]; ok {
+		e.Value.(*moduleConfigEntry).config = config
+		c.entries.MoveToFront(e)
+		return
+	}
+	c.index[key] = c.entries.PushFront(&moduleConfigEntry{key: key, config: config})
+	for c.entries.Len() > c.size {
+		oldest := c.entries.Back()
+		c.entries.Remove(oldest)
+		delete(c.index, oldest.Value.(*moduleConfigEntry).key)
+	}
+}
+
+// hashModuleConfig returns the hash of the module config, where the map keys are hashed in order.
+func hashModuleConfig(config *v1.ModuleConfig) (uint64, error) {
+	h := &configHasher{}
+	h.writeString(config.Path)
+	h.writeString(config.Version)
+	if err := h.writeValue(config.Configs.Default); err != nil {
+		return 0, err
+	}
+	names := make([]string, 0, len(config.Configs.ModulePatcherConfigs))
+	for name := range config.Configs.ModulePatcherConfigs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		h.writeString(name)
+		patcher := config.Configs.ModulePatcherConfigs[name]
+		if patcher == nil {
+			continue
+		}
+		if err := h.writeValue(patcher.ProjectSelector); err != nil {
+			return 0, err
+		}
+		if err := h.writeValue(patcher.GenericConfig); err != nil {
+			return 0, err
+		}
+	}
+	f := fnv.New64a()
+	_, _ = f.Write(h.buf)
+	return f.Sum64(), nil
+}
+
+// configHasher encodes the config values into buf, where each value is prefixed by its type, and each
+// string by its length, so that the different values never share the encoding.
+type configHasher struct {
+	buf []byte
+}
+
+func (h *configHasher) writeString(s string) {
+	h.buf = strconv.AppendInt(h.buf, int64(len(s)), 10)
+	h.buf = append(h.buf, ':')
+	h.buf = append(h.buf, s...)
+}
+
+func (h *configHasher) writeValue(v any) error {
+	switch t := v.(type) {
+	case v1.GenericConfig:
+		return h.writeMap(t)
+	case map[string]any:
+		return h.writeMap(t)
+	case []any:
+		h.buf = append(h.buf, 'a')
+		h.buf = strconv.AppendInt(h.buf, int64(len(t)), 10)
+		for _, e := range t {
+			if err := h.writeValue(e); err != nil {
+				return err
+			}
+		}
+	case []string:
+		h.buf = append(h.buf, 'a')
+		h.buf = strconv.AppendInt(h.buf, int64(len(t)), 10)
+		for _, e := range t {
+			h.buf = append(h.buf, 's')
+			h.writeString(e)
+		}
+	case nil:
+		h.buf = append(h.buf, 'n')
+	case string:
+		h.buf = append(h.buf, 's')
+		h.writeString(t)
+	case bool:
+		h.buf = append(h.buf, 'b')
+		h.buf = strconv.AppendBool(h.buf, t)
+	case int:
+		h.buf = append(h.buf, 'i')
+		h.buf = strconv.AppendInt(h.buf, int64(t), 10)
+	case int32:
+		h.buf = append(h.buf, 'i')
+		h.buf = strconv.AppendInt(h.buf, int64(t), 10)
+	case int64:
+		h.buf = append(h.buf, 'i')
+		h.buf = strconv.AppendInt(h.buf, t, 10)
+	case float64:
+		h.buf = append(h.buf, 'f')
+		h.buf = strconv.AppendFloat(h.buf, t, 'g', -1, 64)
+	default:
+		return fmt.Errorf("unsupported config value type %T", v)
+	}
+	h.buf = append(h.buf, ';')
+	return nil
+}
+
+func (h *configHasher) writeMap(m map[string]any) error {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	h.buf = append(h.buf, 'm')
+	h.buf = strconv.AppendInt(h.buf, int64(len(keys)), 10)
+	for _, k := range keys {
+		h.writeString(k)
+		if err := h.writeValue(m[k]); err != nil {
+			return err
+		}
+	}
+	h.buf = append(h.buf, ';')
+	return nil
+}
+
+// copyGenericConfig returns a deep copy of the config, where the nested maps and slices are copied.
+func copyGenericConfig(config v1.GenericConfig) v1.GenericConfig {
+	if config == nil {
+		return nil
+	}
+	return copyConfigMap(config)
+}
+
+func copyConfigMap(m map[string]any) map[string]any {
+	copied := make(map[string]any, len(m))
+	for k, v := range m {
+		copied[k] = copyConfigValue(v)
+	}
+	return copied
+}
+
+func copyConfigValue(v any) any {
+	switch t := v.(type) {
+	case v1.GenericConfig:
+		return copyGenericConfig(t)
+	case map[string]any:
+		return copyConfigMap(t)
+	case []any:
+		copied := make([]any, len(t))
+		for i, e := range t {
+			copied[i] = copyConfigValue(e)
+		}
+		return copied
+	case []string:
+		return append([]string(nil), t...)
+	default:
+		return v
+	}
+}
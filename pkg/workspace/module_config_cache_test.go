@@ -0,0 +1,139 @@
+package workspace
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	v1 "kusionstack.io/kusion/pkg/apis/api.kusion.io/v1"
+)
+
+func TestModuleConfigCache(t *testing.T) {
+	cache := NewModuleConfigCache(0)
+
+	for _, project := range []string{"foo", "bar", "baz", "foo"} {
+		expected, err := GetProjectModuleConfigs(mockValidModuleConfigs(), project)
+		assert.NoError(t, err)
+		for i := 0; i < 2; i++ {
+			actual, err := cache.GetProjectModuleConfigs(mockValidModuleConfigs(), project)
+			assert.NoError(t, err)
+			assert.Equal(t, expected, actual, "project %s", project)
+		}
+	}
+	// mysql and network of the projects foo, bar and baz
+	assert.Equal(t, 6, cache.Len())
+
+	// the returned config is a copy
+	actual, err := cache.GetProjectModuleConfigs(mockValidModuleConfigs(), "foo")
+	assert.NoError(t, err)
+	actual["mysql"]["instanceType"] = "db.t3.large"
+	actual, err = cache.GetProjectModuleConfigs(mockValidModuleConfigs(), "foo")
+	assert.NoError(t, err)
+	assert.Equal(t, "db.t3.small", actual["mysql"]["instanceType"])
+
+	// the changed workspace misses the cache
+	configs := mockValidModuleConfigs()
+	configs["mysql"].Configs.ModulePatcherConfigs["smallClass"].GenericConfig["instanceType"] = "db.t3.medium"
+	actual, err = cache.GetProjectModuleConfigs(configs, "foo")
+	assert.NoError(t, err)
+	assert.Equal(t, "db.t3.medium", actual["mysql"]["instanceType"])
+	assert.Equal(t, 7, cache.Len())
+
+	cache.Purge()
+	assert.Equal(t, 0, cache.Len())
+
+	_, err = cache.GetProjectModuleConfigs(mockValidModuleConfigs(), "")
+	assert.ErrorIs(t, err, ErrEmptyProjectName)
+}
+
+func TestModuleConfigCacheDeepCopy(t *testing.T) {
+	cache := NewModuleConfigCache(0)
+	configs := mockLargeModuleConfigs(1)
+
+	actual, err := cache.GetProjectModuleConfigs(configs, "foo")
+	assert.NoError(t, err)
+	actual["module0"]["labels"].(map[string]any)["team"] = "other"
+
+	actual, err = cache.GetProjectModuleConfigs(configs, "foo")
+	assert.NoError(t, err)
+	assert.Equal(t, "kusion", actual["module0"]["labels"].(map[string]any)["team"])
+	// modifying the cached config doesn't modify the workspace either
+	actual["module0"]["labels"].(map[string]any)["tier"] = "frontend"
+	assert.Equal(t, "backend", configs["module0"].Configs.Default["labels"].(map[string]any)["tier"])
+}
+
+func TestModuleConfigCacheEviction(t *testing.T) {
+	cache := NewModuleConfigCache(2)
+	config := mockValidModuleConfigs()["network"]
+
+	for _, project := range []string{"foo", "bar", "foo", "baz"} {
+		_, err := cache.GetProjectModuleConfig("network", config, project)
+		assert.NoError(t, err)
+	}
+	assert.Equal(t, 2, cache.Len())
+
+	hash, err := hashModuleConfig(config)
+	assert.NoError(t, err)
+	_, ok := cache.get(moduleConfigKey{module: "network", project: "foo", hash: hash})
+	assert.True(t, ok)
+	_, ok = cache.get(moduleConfigKey{module: "network", project: "bar", hash: hash})
+	assert.False(t, ok, "the least recently used entry should be evicted")
+}
+
+func TestNilModuleConfigCache(t *testing.T) {
+	var cache *ModuleConfigCache
+	actual, err := cache.GetProjectModuleConfigs(mockValidModuleConfigs(), "foo")
+	assert.NoError(t, err)
+	expected, err := GetProjectModuleConfigs(mockValidModuleConfigs(), "foo")
+	assert.NoError(t, err)
+	assert.Equal(t, expected, actual)
+	assert.Equal(t, 0, cache.Len())
+}
+
+func mockLargeModuleConfigs(n int) v1.ModuleConfigs {
+	configs := v1.ModuleConfigs{}
+	for i := 0; i < n; i++ {
+		configs[fmt.Sprintf("module%d", i)] = &v1.ModuleConfig{
+			Path:    fmt.Sprintf("ghcr.io/kusionstack/module%d", i),
+			Version: "0.1.0",
+			Configs: v1.Configs{
+				Default: v1.GenericConfig{
+					"type":     "aws",
+					"replicas": 2,
+					"labels":   map[string]any{"team": "kusion", "tier": "backend"},
+					"endpoint": "${type}.example.com",
+				},
+				ModulePatcherConfigs: v1.ModulePatcherConfigs{
+					"large": {
+						GenericConfig:   v1.GenericConfig{"replicas": 5},
+						ProjectSelector: []string{"foo"},
+					},
+				},
+			},
+		}
+	}
+	return configs
+}
+
+func BenchmarkGetProjectModuleConfigs(b *testing.B) {
+	configs := mockLargeModuleConfigs(20)
+
+	b.Run("uncached", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			if _, err := GetProjectModuleConfigs(configs, "foo"); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+	b.Run("cached", func(b *testing.B) {
+		cache := NewModuleConfigCache(0)
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			if _, err := cache.GetProjectModuleConfigs(configs, "foo"); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+}
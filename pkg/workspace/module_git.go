@@ -0,0 +1,264 @@
+package workspace
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"os/exec"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"kusionstack.io/kusion/pkg/clipath"
+)
+
+const gitPrefix = "git::"
+
+var (
+	ErrInvalidGitModulePath = errors.New("invalid git module path")
+	ErrModuleCacheMiss      = errors.New("module not found in the cache")
+)
+
+// GitModuleSource is the source of the module in a Git repo, which is parsed from the module path in
+// the form of "git::https://github.com/org/repo.git//subdir?ref=v1".
+type GitModuleSource struct {
+	// Repo is the URL of the Git repo.
+	Repo string
+	// Subdir is the directory of the module in the Git repo, and empty means the repo root.
+	Subdir string
+	// Ref is the branch, tag or commit to check out, and empty means the default branch.
+	Ref string
+}
+
+// GitModulePath returns the Git module path of the module in the root of the repo at the ref, which is
+// the form of the Git dependencies in kcl.mod.
+func GitModulePath(repo, ref string) string {
+	modulePath := gitPrefix + strings.TrimPrefix(repo, gitPrefix)
+	if ref != "" {
+		modulePath += "?ref=" + url.QueryEscape(ref)
+	}
+	return modulePath
+}
+
+// ParseGitModulePath parses the Git module path, where the optional "git::" prefix forces the Git
+// scheme, "//" separates the subdir from the repo, and the query "ref" specifies the ref to check out.
+func ParseGitModulePath(modulePath string) (*GitModuleSource, error) {
+	p := strings.TrimPrefix(modulePath, gitPrefix)
+
+	var ref string
+	if i := strings.LastIndex(p, "?"); i >= 0 {
+		query, err := url.ParseQuery(p[i+1:])
+		if err != nil {
+			return nil, fmt.Errorf("%w: %s, %v", ErrInvalidGitModulePath, modulePath, err)
+		}
+		ref = query.Get("ref")
+		p = p[:i]
+	}
+
+	start := 0
+	if i := strings.Index(p, "://"); i >= 0 {
+		start = i + len("://")
+	}
+	repo, subdir := p, ""
+	if i := strings.Index(p[start:], "//"); i >= 0 {
+		repo, subdir = p[:start+i], p[start+i+len("//"):]
+	}
+	if repo == "" {
+		return nil, fmt.Errorf("%w: %s, empty repo", ErrInvalidGitModulePath, modulePath)
+	}
+
+	if subdir != "" {
+		subdir = path.Clean(subdir)
+		if subdir == ".." || strings.HasPrefix(subdir, "../") || path.IsAbs(subdir) {
+			return nil, fmt.Errorf("%w: %s, subdir escapes the repo", ErrInvalidGitModulePath, modulePath)
+		}
+		if subdir == "." {
+			subdir = ""
+		}
+	}
+
+	return &GitModuleSource{Repo: repo, Subdir: subdir, Ref: ref}, nil
+}
+
+var _ ModuleResolver = (*GitModuleResolver)(nil)
+
+// GitModuleResolver resolves the Git module by shallow cloning the repo at the given ref into the module
+// cache. The system Git, along with its credential helpers, is used to access the repo, and the token
+// is sent as the HTTP basic credentials if specified.
+//
+// The resolved modules are marked in use until the resolver is closed, so that they are never evicted
+// by CleanCache during an operation.
+type GitModuleResolver struct {
+	cacheDir string
+	token    string
+	offline  bool
+
+	inUse ModuleCacheLocks
+}
+
+const (
+	// EnvGitModuleToken is the environment variable of the token to access the Git repos of the modules.
+	EnvGitModuleToken = "KUSION_GIT_MODULE_TOKEN"
+	// EnvModuleOffline is the environment variable to resolve the modules in the offline mode if true,
+	// see WithOffline.
+	EnvModuleOffline = "KUSION_MODULE_OFFLINE"
+)
+
+// ModuleOfflineFromEnv returns whether the modules are resolved in the offline mode by EnvModuleOffline.
+func ModuleOfflineFromEnv() bool {
+	offline, _ := strconv.ParseBool(os.Getenv(EnvModuleOffline))
+	return offline
+}
+
+// DefaultGitModuleCacheDir returns the default directory of the cached Git modules, which is the
+// "modules/git" directory in the Kusion cache path.
+func DefaultGitModuleCacheDir() (string, error) {
+	return clipath.CachePath("modules", "git")
+}
+
+// GitModuleResolverOption customizes the GitModuleResolver.
+type GitModuleResolverOption func(*GitModuleResolver)
+
+// WithOffline sets the resolver in the offline mode, which never accesses the network, and only
+// resolves the modules in the cache. A cache miss fails with ErrModuleCacheMiss.
+func WithOffline(offline bool) GitModuleResolverOption {
+	return func(r *GitModuleResolver) {
+		r.offline = offline
+	}
+}
+
+// NewGitModuleResolver returns a GitModuleResolver which caches the cloned repos in cacheDir, defaults
+// to DefaultGitModuleCacheDir.
+func NewGitModuleResolver(cacheDir, token string, opts ...GitModuleResolverOption) (*GitModuleResolver, error) {
+	if cacheDir == "" {
+		var err error
+		if cacheDir, err = DefaultGitModuleCacheDir(); err != nil {
+			return nil, err
+		}
+	}
+	r := &GitModuleResolver{cacheDir: cacheDir, token: token}
+	for _, opt := range opts {
+		opt(r)
+	}
+	return r, nil
+}
+
+// Resolve clones the repo of the Git module path unless it is cached, and returns the local directory
+// of the module. The cached repo is keyed by the repo and ref, so a branch ref is not refreshed until
+// the cache is cleaned.
+func (r *GitModuleResolver) Resolve(ctx context.Context, modulePath string) (string, error) {
+	src, err := ParseGitModulePath(modulePath)
+	if err != nil {
+		return "", err
+	}
+
+	sum := sha256.Sum256([]byte(src.Repo + "@" + src.Ref))
+	repoDir := filepath.Join(r.cacheDir, hex.EncodeToString(sum[:])[:16])
+	if err = r.inUse.Acquire(repoDir); err != nil {
+		return "", err
+	}
+	if _, err = os.Stat(repoDir); os.IsNotExist(err) {
+		if r.offline {
+			return "", fmt.Errorf("%w: git repo %s is not cached in %s and can't be fetched in offline mode",
+				ErrModuleCacheMiss, modulePath, r.cacheDir)
+		}
+		if err = r.clone(ctx, src, repoDir); err != nil {
+			return "", err
+		}
+	} else if err != nil {
+		return "", err
+	} else {
+		// refresh the modification time as the access time for the LRU eviction
+		now := time.Now()
+		if err = os.Chtimes(repoDir, now, now); err != nil {
+			return "", err
+		}
+	}
+
+	moduleDir := filepath.Join(repoDir, filepath.FromSlash(src.Subdir))
+	info, err := os.Stat(moduleDir)
+	if err != nil {
+		return "", fmt.Errorf("module directory %s not found in git repo %s: %w", src.Subdir, src.Repo, err)
+	}
+	if !info.IsDir() {
+		return "", fmt.Errorf("module path %s in git repo %s is not a directory", src.Subdir, src.Repo)
+	}
+	return moduleDir, nil
+}
+
+// Close releases the modules resolved by the resolver, which can be evicted by CleanCache afterward.
+func (r *GitModuleResolver) Close() error {
+	return r.inUse.Close()
+}
+
+// clone fetches the ref of the repo with depth 1 into a temporary directory, which is renamed to the
+// repo directory on success, so that an interrupted clone never leaves a broken cache.
+func (r *GitModuleResolver) clone(ctx context.Context, src *GitModuleSource, repoDir string) error {
+	tmpDir, err := os.MkdirTemp(r.cacheDir, ".clone-")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(tmpDir)
+
+	ref := src.Ref
+	if ref == "" {
+		ref = "HEAD"
+	}
+	for _, args := range [][]string{
+		{"init", "--quiet"},
+		{"remote", "add", "origin", src.Repo},
+		{"fetch", "--quiet", "--depth", "1", "origin", ref},
+		{"checkout", "--quiet", "FETCH_HEAD"},
+	} {
+		if err = r.git(ctx, tmpDir, args...); err != nil {
+			return fmt.Errorf("failed to clone git repo %s at ref %s: %w", src.Repo, ref, err)
+		}
+	}
+
+	if err = os.Rename(tmpDir, repoDir); err != nil {
+		// the repo may be cloned concurrently by another process
+		if _, statErr := os.Stat(repoDir); statErr == nil {
+			return nil
+		}
+		return err
+	}
+	return nil
+}
+
+func (r *GitModuleResolver) git(ctx context.Context, dir string, args ...string) error {
+	cmd := r.gitCommand(ctx, dir, args...)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("git %s: %w, %s", args[0], err, strings.TrimSpace(stderr.String()))
+	}
+	return nil
+}
+
+// gitCommand returns the git command, where the token is passed by the environment variables of the
+// git config rather than the arguments, which are visible to the other processes.
+func (r *GitModuleResolver) gitCommand(ctx context.Context, dir string, args ...string) *exec.Cmd {
+	cmd := exec.CommandContext(ctx, "git", args...)
+	cmd.Dir = dir
+	// fail instead of prompting for the credentials
+	cmd.Env = append(os.Environ(), "GIT_TERMINAL_PROMPT=0")
+	if r.token != "" {
+		credentials := base64.StdEncoding.EncodeToString([]byte("x-access-token:" + r.token))
+		// append to the git config passed by the environment variables of the user, if any
+		n, _ := strconv.Atoi(os.Getenv("GIT_CONFIG_COUNT"))
+		cmd.Env = append(cmd.Env,
+			fmt.Sprintf("GIT_CONFIG_COUNT=%d", n+1),
+			fmt.Sprintf("GIT_CONFIG_KEY_%d=http.extraHeader", n),
+			fmt.Sprintf("GIT_CONFIG_VALUE_%d=Authorization: Basic %s", n, credentials),
+		)
+	}
+	return cmd
+}
@@ -0,0 +1,220 @@
+package workspace
+
+import (
+	"context"
+	"encoding/base64"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseGitModulePath(t *testing.T) {
+	testcases := []struct {
+		name        string
+		path        string
+		expected    *GitModuleSource
+		expectedErr error
+	}{
+		{
+			name:     "https repo with subdir and ref",
+			path:     "git::https://github.com/KusionStack/catalog.git//modules/mysql?ref=v1",
+			expected: &GitModuleSource{Repo: "https://github.com/KusionStack/catalog.git", Subdir: "modules/mysql", Ref: "v1"},
+		},
+		{
+			name:     "ssh repo without subdir",
+			path:     "git::git@github.com:KusionStack/catalog.git?ref=main",
+			expected: &GitModuleSource{Repo: "git@github.com:KusionStack/catalog.git", Ref: "main"},
+		},
+		{
+			name:     "file repo with unclean subdir",
+			path:     "file:///tmp/catalog.git//modules/../mysql/",
+			expected: &GitModuleSource{Repo: "file:///tmp/catalog.git", Subdir: "mysql"},
+		},
+		{
+			name:        "subdir escapes repo",
+			path:        "git::https://github.com/KusionStack/catalog.git//../mysql",
+			expectedErr: ErrInvalidGitModulePath,
+		},
+		{
+			name:        "empty repo",
+			path:        "git::?ref=v1",
+			expectedErr: ErrInvalidGitModulePath,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			src, err := ParseGitModulePath(tc.path)
+			if tc.expectedErr != nil {
+				assert.ErrorIs(t, err, tc.expectedErr)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expected, src)
+		})
+	}
+}
+
+// newBareRepo creates a bare Git repo with the mysql module in v1 and v2 tags, and returns the repo URL.
+func newBareRepo(t *testing.T) string {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+
+	root := t.TempDir()
+	work := filepath.Join(root, "work")
+	bare := filepath.Join(root, "catalog.git")
+	run := func(dir string, args ...string) {
+		cmd := exec.Command("git", args...)
+		cmd.Dir = dir
+		cmd.Env = append(os.Environ(),
+			"GIT_AUTHOR_NAME=kusion", "GIT_AUTHOR_EMAIL=kusion@example.com",
+			"GIT_COMMITTER_NAME=kusion", "GIT_COMMITTER_EMAIL=kusion@example.com")
+		out, err := cmd.CombinedOutput()
+		require.NoError(t, err, string(out))
+	}
+	writeModule := func(version string) {
+		dir := filepath.Join(work, "modules", "mysql")
+		require.NoError(t, os.MkdirAll(dir, 0o755))
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "kcl.mod"), []byte("version = \""+version+"\"\n"), 0o644))
+	}
+
+	require.NoError(t, os.MkdirAll(work, 0o755))
+	run(work, "init", "--quiet")
+	writeModule("0.1.0")
+	run(work, "add", ".")
+	run(work, "commit", "--quiet", "-m", "v1")
+	run(work, "tag", "v1")
+	writeModule("0.2.0")
+	run(work, "commit", "--quiet", "-am", "v2")
+	run(work, "tag", "v2")
+	run(root, "clone", "--quiet", "--bare", work, bare)
+
+	return "file://" + filepath.ToSlash(bare)
+}
+
+func TestGitModuleResolver_Resolve(t *testing.T) {
+	repo := newBareRepo(t)
+	resolver, err := NewGitModuleResolver(t.TempDir(), "")
+	require.NoError(t, err)
+	resolvers := map[PathScheme]ModuleResolver{PathSchemeGit: resolver}
+
+	for ref, version := range map[string]string{"?ref=v1": "0.1.0", "?ref=v2": "0.2.0", "": "0.2.0"} {
+		dir, err := ResolveModule(context.Background(), "git::"+repo+"//modules/mysql"+ref, resolvers)
+		require.NoError(t, err)
+		content, err := os.ReadFile(filepath.Join(dir, "kcl.mod"))
+		require.NoError(t, err)
+		assert.Equal(t, "version = \""+version+"\"\n", string(content))
+	}
+
+	// the cached repo is reused
+	first, err := resolver.Resolve(context.Background(), "git::"+repo+"//modules/mysql?ref=v1")
+	require.NoError(t, err)
+	second, err := resolver.Resolve(context.Background(), "git::"+repo+"//modules/mysql?ref=v1")
+	require.NoError(t, err)
+	assert.Equal(t, first, second)
+
+	_, err = resolver.Resolve(context.Background(), "git::"+repo+"//modules/postgres?ref=v1")
+	assert.Error(t, err)
+	_, err = resolver.Resolve(context.Background(), "git::"+repo+"//modules/mysql?ref=v3")
+	assert.Error(t, err)
+	_, err = ResolveModule(context.Background(), "oci://ghcr.io/kusionstack/mysql", resolvers)
+	assert.ErrorIs(t, err, ErrUnsupportedModulePath)
+}
+
+func TestGitModulePath(t *testing.T) {
+	assert.Equal(t, "git::https://github.com/KusionStack/mysql.git?ref=v0.1.0",
+		GitModulePath("https://github.com/KusionStack/mysql.git", "v0.1.0"))
+	assert.Equal(t, "git::https://github.com/KusionStack/mysql.git",
+		GitModulePath("git::https://github.com/KusionStack/mysql.git", ""))
+
+	src, err := ParseGitModulePath(GitModulePath("https://github.com/KusionStack/mysql.git", "feature/a&b"))
+	assert.NoError(t, err)
+	assert.Equal(t, &GitModuleSource{Repo: "https://github.com/KusionStack/mysql.git", Ref: "feature/a&b"}, src)
+}
+
+func TestGitModuleResolver_Token(t *testing.T) {
+	t.Setenv("GIT_CONFIG_COUNT", "1")
+	resolver, err := NewGitModuleResolver(t.TempDir(), "secret-token")
+	require.NoError(t, err)
+
+	cmd := resolver.gitCommand(context.Background(), t.TempDir(), "fetch", "origin")
+	// the token is never passed by the arguments
+	for _, arg := range cmd.Args {
+		assert.NotContains(t, arg, "Authorization")
+	}
+	credentials := base64.StdEncoding.EncodeToString([]byte("x-access-token:secret-token"))
+	assert.Contains(t, cmd.Env, "GIT_CONFIG_COUNT=2")
+	assert.Contains(t, cmd.Env, "GIT_CONFIG_KEY_1=http.extraHeader")
+	assert.Contains(t, cmd.Env, "GIT_CONFIG_VALUE_1=Authorization: Basic "+credentials)
+}
+
+func TestGitModuleResolver_InUse(t *testing.T) {
+	repo := newBareRepo(t)
+	cacheDir := t.TempDir()
+	resolver, err := NewGitModuleResolver(cacheDir, "")
+	require.NoError(t, err)
+
+	dir, err := resolver.Resolve(context.Background(), "git::"+repo+"//modules/mysql?ref=v1")
+	require.NoError(t, err)
+
+	// the module in use is not evicted until the resolver is closed
+	result, err := CleanCache(cacheDir, CleanCacheOptions{MaxSize: 1})
+	require.NoError(t, err)
+	assert.Equal(t, 0, result.Entries)
+	assert.DirExists(t, dir)
+
+	require.NoError(t, resolver.Close())
+	result, err = CleanCache(cacheDir, CleanCacheOptions{MaxSize: 1})
+	require.NoError(t, err)
+	assert.Equal(t, 1, result.Entries)
+	assert.NoDirExists(t, dir)
+}
+
+func TestGitModuleResolver_Offline(t *testing.T) {
+	repo := newBareRepo(t)
+	cacheDir := t.TempDir()
+	offline, err := NewGitModuleResolver(cacheDir, "", WithOffline(true))
+	require.NoError(t, err)
+	defer offline.Close()
+
+	// cache miss
+	_, err = offline.Resolve(context.Background(), "git::"+repo+"//modules/mysql?ref=v1")
+	assert.ErrorIs(t, err, ErrModuleCacheMiss)
+	entries, err := listCacheEntries(cacheDir)
+	require.NoError(t, err)
+	assert.Empty(t, entries)
+
+	online, err := NewGitModuleResolver(cacheDir, "")
+	require.NoError(t, err)
+	_, err = online.Resolve(context.Background(), "git::"+repo+"//modules/mysql?ref=v1")
+	require.NoError(t, err)
+	require.NoError(t, online.Close())
+
+	// cache hit without the network, even if the repo is gone
+	require.NoError(t, os.RemoveAll(strings.TrimPrefix(repo, "file://")))
+	dir, err := offline.Resolve(context.Background(), "git::"+repo+"//modules/mysql?ref=v1")
+	require.NoError(t, err)
+	content, err := os.ReadFile(filepath.Join(dir, "kcl.mod"))
+	require.NoError(t, err)
+	assert.Equal(t, "version = \"0.1.0\"\n", string(content))
+}
+
+func TestGitModuleResolver_OfflineFromEnv(t *testing.T) {
+	t.Setenv(EnvModuleOffline, "true")
+	resolver, err := NewGitModuleResolver(t.TempDir(), "", WithOffline(ModuleOfflineFromEnv()))
+	require.NoError(t, err)
+	defer resolver.Close()
+
+	// the network fetch is refused rather than attempted
+	_, err = resolver.Resolve(context.Background(), "git::https://github.com/KusionStack/catalog.git//modules/mysql?ref=v1")
+	assert.ErrorIs(t, err, ErrModuleCacheMiss)
+
+	t.Setenv(EnvModuleOffline, "invalid")
+	assert.False(t, ModuleOfflineFromEnv())
+}
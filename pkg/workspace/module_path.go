@@ -0,0 +1,105 @@
+package workspace
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"strings"
+)
+
+// PathScheme is the scheme of the module path, which indicates where the module is fetched from.
+type PathScheme string
+
+const (
+	PathSchemeLocal PathScheme = "local"
+	PathSchemeHTTP  PathScheme = "http"
+	PathSchemeOCI   PathScheme = "oci"
+	PathSchemeGit   PathScheme = "git"
+)
+
+const ociPrefix = "oci://"
+
+var (
+	ErrEmptyModuleRegistry   = errors.New("empty module registry to resolve the module path")
+	ErrUnsupportedModulePath = errors.New("unsupported module path")
+)
+
+// DetectPathScheme detects the scheme of the module path by its form:
+//   - "oci://ghcr.io/kusionstack/mysql", or "ghcr.io/kusionstack/mysql" starting with a registry host: OCI;
+//   - "git::https://github.com/org/repo//subdir?ref=v1", "git://", "ssh://", "git@github.com:org/repo",
+//     or an HTTP URL of the repo ending with ".git": Git;
+//   - "http://", "https://": HTTP;
+//   - "file://", an absolute path, or a path starting with "." or "~": Local.
+//
+// An ambiguous path without a registry host, e.g. "mysql" or "kusionstack/mysql", is regarded as
+// an OCI path in the default module registry, see ResolveModulePath.
+func DetectPathScheme(path string) PathScheme {
+	lower := strings.ToLower(path)
+	switch {
+	case strings.HasPrefix(lower, ociPrefix):
+		return PathSchemeOCI
+	case strings.HasPrefix(lower, gitPrefix), strings.HasPrefix(lower, "git://"), strings.HasPrefix(lower, "ssh://"),
+		strings.HasPrefix(lower, "git+"), strings.HasPrefix(lower, "git@"):
+		return PathSchemeGit
+	case strings.HasPrefix(lower, "http://"), strings.HasPrefix(lower, "https://"):
+		repo, _, _ := strings.Cut(lower, "?")
+		if strings.HasSuffix(strings.TrimSuffix(repo, "/"), ".git") || strings.Contains(repo, ".git//") {
+			return PathSchemeGit
+		}
+		return PathSchemeHTTP
+	case strings.HasPrefix(lower, "file://"), filepath.IsAbs(path), strings.HasPrefix(path, "/"),
+		strings.HasPrefix(path, "."), strings.HasPrefix(path, "~"):
+		return PathSchemeLocal
+	default:
+		return PathSchemeOCI
+	}
+}
+
+// ResolveModulePath resolves the module path to the form that can be dispatched by its scheme. The
+// OCI path is prefixed with "oci://", and the ambiguous one without a registry host is placed in the
+// given default registry, e.g. "mysql" in registry "ghcr.io/kusionstack" is resolved to
+// "oci://ghcr.io/kusionstack/mysql". The paths of the other schemes are returned unchanged.
+func ResolveModulePath(path, registry string) (string, error) {
+	if DetectPathScheme(path) != PathSchemeOCI {
+		return path, nil
+	}
+	if strings.HasPrefix(strings.ToLower(path), ociPrefix) {
+		return ociPrefix + path[len(ociPrefix):], nil
+	}
+	if hasRegistryHost(path) {
+		return ociPrefix + path, nil
+	}
+
+	registry = strings.TrimSuffix(strings.TrimPrefix(registry, ociPrefix), "/")
+	if registry == "" {
+		return "", fmt.Errorf("%w: %s", ErrEmptyModuleRegistry, path)
+	}
+	return ociPrefix + registry + "/" + path, nil
+}
+
+// hasRegistryHost checks whether the first segment of the path is a registry host, which contains
+// a dot or port, e.g. "ghcr.io" and "localhost:5000", or is "localhost".
+func hasRegistryHost(path string) bool {
+	host, _, found := strings.Cut(path, "/")
+	if !found {
+		return false
+	}
+	return strings.ContainsAny(host, ".:") || host == "localhost"
+}
+
+// ModuleResolver fetches the module of the path, and returns the local directory of the module.
+type ModuleResolver interface {
+	Resolve(ctx context.Context, path string) (string, error)
+}
+
+// ResolveModule dispatches the module path to the resolver of its scheme detected by DetectPathScheme,
+// and returns the local directory of the module.
+func ResolveModule(ctx context.Context, path string, resolvers map[PathScheme]ModuleResolver) (string, error) {
+	scheme := DetectPathScheme(path)
+	resolver, ok := resolvers[scheme]
+	if !ok || resolver == nil {
+		return "", fmt.Errorf("%w: no resolver for %s path %s", ErrUnsupportedModulePath, scheme, path)
+	}
+	return resolver.Resolve(ctx, path)
+}
@@ -0,0 +1,105 @@
+package workspace
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDetectPathScheme(t *testing.T) {
+	testcases := []struct {
+		path     string
+		expected PathScheme
+	}{
+		{path: "oci://ghcr.io/kusionstack/mysql", expected: PathSchemeOCI},
+		{path: "OCI://ghcr.io/kusionstack/mysql", expected: PathSchemeOCI},
+		{path: "ghcr.io/kusionstack/mysql", expected: PathSchemeOCI},
+		{path: "localhost:5000/mysql", expected: PathSchemeOCI},
+		{path: "https://github.com/KusionStack/catalog", expected: PathSchemeHTTP},
+		{path: "http://example.com/modules/mysql.tar.gz", expected: PathSchemeHTTP},
+		{path: "https://github.com/KusionStack/catalog.git", expected: PathSchemeGit},
+		{path: "git://github.com/KusionStack/catalog", expected: PathSchemeGit},
+		{path: "ssh://git@github.com/KusionStack/catalog", expected: PathSchemeGit},
+		{path: "git@github.com:KusionStack/catalog.git", expected: PathSchemeGit},
+		{path: "git+https://github.com/KusionStack/catalog", expected: PathSchemeGit},
+		{path: "git::https://github.com/KusionStack/catalog//modules/mysql?ref=v1", expected: PathSchemeGit},
+		{path: "https://github.com/KusionStack/catalog.git//modules/mysql?ref=v1", expected: PathSchemeGit},
+		{path: "file:///modules/mysql", expected: PathSchemeLocal},
+		{path: "/modules/mysql", expected: PathSchemeLocal},
+		{path: "./modules/mysql", expected: PathSchemeLocal},
+		{path: "../mysql", expected: PathSchemeLocal},
+		{path: "~/modules/mysql", expected: PathSchemeLocal},
+		{path: "mysql", expected: PathSchemeOCI},
+		{path: "kusionstack/mysql", expected: PathSchemeOCI},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.path, func(t *testing.T) {
+			assert.Equal(t, tc.expected, DetectPathScheme(tc.path))
+		})
+	}
+}
+
+func TestResolveModulePath(t *testing.T) {
+	testcases := []struct {
+		name        string
+		path        string
+		registry    string
+		expected    string
+		expectedErr error
+	}{
+		{
+			name:     "oci path",
+			path:     "oci://ghcr.io/kusionstack/mysql",
+			registry: "docker.io/kusionstack",
+			expected: "oci://ghcr.io/kusionstack/mysql",
+		},
+		{
+			name:     "oci path without scheme",
+			path:     "ghcr.io/kusionstack/mysql",
+			registry: "docker.io/kusionstack",
+			expected: "oci://ghcr.io/kusionstack/mysql",
+		},
+		{
+			name:     "ambiguous path defaults to oci in registry",
+			path:     "mysql",
+			registry: "ghcr.io/kusionstack/",
+			expected: "oci://ghcr.io/kusionstack/mysql",
+		},
+		{
+			name:     "ambiguous path defaults to oci in registry with scheme",
+			path:     "kusionstack/mysql",
+			registry: "oci://ghcr.io",
+			expected: "oci://ghcr.io/kusionstack/mysql",
+		},
+		{
+			name:        "ambiguous path without registry",
+			path:        "mysql",
+			expectedErr: ErrEmptyModuleRegistry,
+		},
+		{
+			name:     "local path unchanged",
+			path:     "./modules/mysql",
+			registry: "ghcr.io/kusionstack",
+			expected: "./modules/mysql",
+		},
+		{
+			name:     "git path unchanged",
+			path:     "https://github.com/KusionStack/catalog.git",
+			registry: "ghcr.io/kusionstack",
+			expected: "https://github.com/KusionStack/catalog.git",
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			path, err := ResolveModulePath(tc.path, tc.registry)
+			if tc.expectedErr != nil {
+				assert.ErrorIs(t, err, tc.expectedErr)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expected, path)
+		})
+	}
+}
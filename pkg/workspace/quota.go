@@ -0,0 +1,153 @@
+package workspace
+
+import (
+	"errors"
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/api/resource"
+
+	v1 "kusionstack.io/kusion/pkg/apis/api.kusion.io/v1"
+)
+
+var (
+	ErrInvalidQuota  = errors.New("invalid workspace quota")
+	ErrQuotaExceeded = errors.New("workspace quota exceeded")
+)
+
+// replicatedWorkloadKinds are the kinds of the Kubernetes workloads counted by the MaxTotalReplicas quota,
+// whose replicas defaults to 1 if not set.
+var replicatedWorkloadKinds = map[string]bool{
+	"Deployment":            true,
+	"StatefulSet":           true,
+	"ReplicaSet":            true,
+	"ReplicationController": true,
+}
+
+// ValidateQuotas validates the quotas are non-negative, and the MaxPVCSize is a valid quantity.
+func ValidateQuotas(quotas *v1.Quotas) error {
+	if quotas == nil {
+		return nil
+	}
+	if quotas.MaxResources < 0 {
+		return fmt.Errorf("%w: maxResources must not be negative, got %d", ErrInvalidQuota, quotas.MaxResources)
+	}
+	if quotas.MaxTotalReplicas < 0 {
+		return fmt.Errorf("%w: maxTotalReplicas must not be negative, got %d", ErrInvalidQuota, quotas.MaxTotalReplicas)
+	}
+	if quotas.MaxPVCSize != "" {
+		if _, err := resource.ParseQuantity(quotas.MaxPVCSize); err != nil {
+			return fmt.Errorf("%w: maxPVCSize %s: %v", ErrInvalidQuota, quotas.MaxPVCSize, err)
+		}
+	}
+	return nil
+}
+
+// CheckQuotas checks the generated Spec against the quotas of the workspace, and returns an error naming
+// each exceeded quota with its limit and the current value. No quota is checked if quotas is nil.
+func CheckQuotas(quotas *v1.Quotas, spec *v1.Spec) error {
+	if quotas == nil || spec == nil {
+		return nil
+	}
+	if err := ValidateQuotas(quotas); err != nil {
+		return err
+	}
+
+	var errs []error
+	if quotas.MaxResources > 0 && len(spec.Resources) > quotas.MaxResources {
+		errs = append(errs, fmt.Errorf("%w: maxResources is %d, but the stack has %d resources",
+			ErrQuotaExceeded, quotas.MaxResources, len(spec.Resources)))
+	}
+	if quotas.MaxTotalReplicas > 0 {
+		if replicas := totalReplicas(spec.Resources); replicas > int64(quotas.MaxTotalReplicas) {
+			errs = append(errs, fmt.Errorf("%w: maxTotalReplicas is %d, but the stack has %d replicas",
+				ErrQuotaExceeded, quotas.MaxTotalReplicas, replicas))
+		}
+	}
+	if quotas.MaxPVCSize != "" {
+		maxSize := resource.MustParse(quotas.MaxPVCSize)
+		for _, res := range spec.Resources {
+			for _, size := range pvcSizes(res) {
+				if size.Cmp(maxSize) > 0 {
+					errs = append(errs, fmt.Errorf("%w: maxPVCSize is %s, but %s requests %s",
+						ErrQuotaExceeded, quotas.MaxPVCSize, res.ID, size.String()))
+				}
+			}
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// totalReplicas returns the sum of the replicas of the Kubernetes workloads in the resources.
+func totalReplicas(resources v1.Resources) int64 {
+	var total int64
+	for _, res := range resources {
+		if res.Type != v1.Kubernetes || !replicatedWorkloadKinds[kindOf(res)] {
+			continue
+		}
+		spec, _ := res.Attributes["spec"].(map[string]interface{})
+		replicas, ok := toInt64(spec["replicas"])
+		if !ok {
+			replicas = 1
+		}
+		total += replicas
+	}
+	return total
+}
+
+// pvcSizes returns the storage requested by the PersistentVolumeClaim, or by the volumeClaimTemplates of
+// the StatefulSet. The requests which are not valid quantities are skipped.
+func pvcSizes(res v1.Resource) []resource.Quantity {
+	if res.Type != v1.Kubernetes {
+		return nil
+	}
+	spec, _ := res.Attributes["spec"].(map[string]interface{})
+	var claimSpecs []interface{}
+	switch kindOf(res) {
+	case "PersistentVolumeClaim":
+		claimSpecs = append(claimSpecs, spec)
+	case "StatefulSet":
+		templates, _ := spec["volumeClaimTemplates"].([]interface{})
+		for _, template := range templates {
+			if t, ok := template.(map[string]interface{}); ok {
+				claimSpecs = append(claimSpecs, t["spec"])
+			}
+		}
+	default:
+		return nil
+	}
+
+	var sizes []resource.Quantity
+	for _, claimSpec := range claimSpecs {
+		cs, _ := claimSpec.(map[string]interface{})
+		resources, _ := cs["resources"].(map[string]interface{})
+		requests, _ := resources["requests"].(map[string]interface{})
+		storage, ok := requests["storage"].(string)
+		if !ok {
+			continue
+		}
+		if size, err := resource.ParseQuantity(storage); err == nil {
+			sizes = append(sizes, size)
+		}
+	}
+	return sizes
+}
+
+func kindOf(res v1.Resource) string {
+	kind, _ := res.Attributes["kind"].(string)
+	return kind
+}
+
+func toInt64(v interface{}) (int64, bool) {
+	switch n := v.(type) {
+	case int:
+		return int64(n), true
+	case int32:
+		return int64(n), true
+	case int64:
+		return n, true
+	case float64:
+		return int64(n), true
+	default:
+		return 0, false
+	}
+}
@@ -0,0 +1,171 @@
+package workspace
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	v1 "kusionstack.io/kusion/pkg/apis/api.kusion.io/v1"
+)
+
+func mockQuotaWorkload(kind, name string, replicas interface{}) v1.Resource {
+	spec := map[string]interface{}{}
+	if replicas != nil {
+		spec["replicas"] = replicas
+	}
+	return v1.Resource{
+		ID:   "apps/v1:" + kind + ":default:" + name,
+		Type: v1.Kubernetes,
+		Attributes: map[string]interface{}{
+			"apiVersion": "apps/v1",
+			"kind":       kind,
+			"metadata":   map[string]interface{}{"name": name, "namespace": "default"},
+			"spec":       spec,
+		},
+	}
+}
+
+func mockQuotaPVC(name, storage string) v1.Resource {
+	return v1.Resource{
+		ID:   "v1:PersistentVolumeClaim:default:" + name,
+		Type: v1.Kubernetes,
+		Attributes: map[string]interface{}{
+			"apiVersion": "v1",
+			"kind":       "PersistentVolumeClaim",
+			"metadata":   map[string]interface{}{"name": name, "namespace": "default"},
+			"spec": map[string]interface{}{
+				"resources": map[string]interface{}{
+					"requests": map[string]interface{}{"storage": storage},
+				},
+			},
+		},
+	}
+}
+
+func mockQuotaStatefulSet(name, storage string) v1.Resource {
+	res := mockQuotaWorkload("StatefulSet", name, int64(1))
+	res.Attributes["spec"].(map[string]interface{})["volumeClaimTemplates"] = []interface{}{
+		map[string]interface{}{
+			"metadata": map[string]interface{}{"name": "data"},
+			"spec": map[string]interface{}{
+				"resources": map[string]interface{}{
+					"requests": map[string]interface{}{"storage": storage},
+				},
+			},
+		},
+	}
+	return res
+}
+
+func TestCheckQuotas(t *testing.T) {
+	testcases := []struct {
+		name      string
+		quotas    *v1.Quotas
+		resources v1.Resources
+		errMsg    string
+	}{
+		{
+			name:      "no quotas",
+			quotas:    nil,
+			resources: v1.Resources{mockQuotaWorkload("Deployment", "foo", 100)},
+		},
+		{
+			name:   "under max resources",
+			quotas: &v1.Quotas{MaxResources: 2},
+			resources: v1.Resources{
+				mockQuotaWorkload("Deployment", "foo", nil),
+				mockQuotaPVC("foo", "1Gi"),
+			},
+		},
+		{
+			name:   "over max resources",
+			quotas: &v1.Quotas{MaxResources: 1},
+			resources: v1.Resources{
+				mockQuotaWorkload("Deployment", "foo", nil),
+				mockQuotaPVC("foo", "1Gi"),
+			},
+			errMsg: "workspace quota exceeded: maxResources is 1, but the stack has 2 resources",
+		},
+		{
+			name:   "under max total replicas",
+			quotas: &v1.Quotas{MaxTotalReplicas: 6},
+			resources: v1.Resources{
+				mockQuotaWorkload("Deployment", "foo", 3),
+				mockQuotaWorkload("StatefulSet", "bar", float64(2)),
+				mockQuotaWorkload("ReplicaSet", "baz", nil),
+				mockQuotaWorkload("DaemonSet", "qux", nil),
+			},
+		},
+		{
+			name:   "over max total replicas",
+			quotas: &v1.Quotas{MaxTotalReplicas: 5},
+			resources: v1.Resources{
+				mockQuotaWorkload("Deployment", "foo", 3),
+				mockQuotaWorkload("StatefulSet", "bar", int64(2)),
+				mockQuotaWorkload("ReplicaSet", "baz", nil),
+			},
+			errMsg: "workspace quota exceeded: maxTotalReplicas is 5, but the stack has 6 replicas",
+		},
+		{
+			name:   "under max pvc size",
+			quotas: &v1.Quotas{MaxPVCSize: "10Gi"},
+			resources: v1.Resources{
+				mockQuotaPVC("foo", "10Gi"),
+				mockQuotaStatefulSet("bar", "5Gi"),
+			},
+		},
+		{
+			name:   "over max pvc size",
+			quotas: &v1.Quotas{MaxPVCSize: "10Gi"},
+			resources: v1.Resources{
+				mockQuotaPVC("foo", "20Gi"),
+				mockQuotaPVC("bar", "1Gi"),
+			},
+			errMsg: "workspace quota exceeded: maxPVCSize is 10Gi, but v1:PersistentVolumeClaim:default:foo requests 20Gi",
+		},
+		{
+			name:      "over max pvc size in volume claim templates",
+			quotas:    &v1.Quotas{MaxPVCSize: "10Gi"},
+			resources: v1.Resources{mockQuotaStatefulSet("bar", "1Ti")},
+			errMsg:    "workspace quota exceeded: maxPVCSize is 10Gi, but apps/v1:StatefulSet:default:bar requests 1Ti",
+		},
+		{
+			name:   "over multiple quotas",
+			quotas: &v1.Quotas{MaxResources: 1, MaxTotalReplicas: 1},
+			resources: v1.Resources{
+				mockQuotaWorkload("Deployment", "foo", 1),
+				mockQuotaWorkload("Deployment", "bar", 1),
+			},
+			errMsg: "workspace quota exceeded: maxResources is 1, but the stack has 2 resources\n" +
+				"workspace quota exceeded: maxTotalReplicas is 1, but the stack has 2 replicas",
+		},
+		{
+			name:      "invalid max pvc size",
+			quotas:    &v1.Quotas{MaxPVCSize: "ten"},
+			resources: v1.Resources{mockQuotaPVC("foo", "1Gi")},
+			errMsg:    "invalid workspace quota: maxPVCSize ten: quantities must match the regular expression '^([+-]?[0-9.]+)([eEinumkKMGTP]*[-+]?[0-9]*)$'",
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := CheckQuotas(tc.quotas, &v1.Spec{Resources: tc.resources})
+			if tc.errMsg == "" {
+				assert.NoError(t, err)
+				return
+			}
+			assert.EqualError(t, err, tc.errMsg)
+		})
+	}
+
+	err := CheckQuotas(&v1.Quotas{MaxResources: 1}, &v1.Spec{Resources: v1.Resources{{}, {}}})
+	assert.ErrorIs(t, err, ErrQuotaExceeded)
+}
+
+func TestValidateQuotas(t *testing.T) {
+	assert.NoError(t, ValidateQuotas(nil))
+	assert.NoError(t, ValidateQuotas(&v1.Quotas{MaxResources: 10, MaxTotalReplicas: 10, MaxPVCSize: "100Gi"}))
+	assert.ErrorIs(t, ValidateQuotas(&v1.Quotas{MaxResources: -1}), ErrInvalidQuota)
+	assert.ErrorIs(t, ValidateQuotas(&v1.Quotas{MaxTotalReplicas: -1}), ErrInvalidQuota)
+	assert.ErrorIs(t, ValidateQuotas(&v1.Quotas{MaxPVCSize: "ten"}), ErrInvalidQuota)
+}
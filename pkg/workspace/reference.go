@@ -0,0 +1,175 @@
+package workspace
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+
+	v1 "kusionstack.io/kusion/pkg/apis/api.kusion.io/v1"
+)
+
+var (
+	ErrConfigReferenceCycle    = errors.New("config reference cycle")
+	ErrConfigReferenceNotFound = errors.New("config reference not found")
+	ErrConfigReferenceNotValue = errors.New("config reference is not a scalar value")
+)
+
+// configReferenceRegex matches the references to other keys of the same config, such as ${config.name}
+// or ${config.database.port}.
+var configReferenceRegex = regexp.MustCompile(`\$\{config\.([^}]+)\}`)
+
+// ResolveConfigReferences expands the ${config.path} references in the string values of the config,
+// where the path is a dot-separated key path into the same config. A string consisting of a single
+// reference is replaced by the referenced value as is, otherwise the scalar referenced values are
+// interpolated into the string. References are resolved transitively, and a reference cycle results
+// in an error. The given config is not modified.
+func ResolveConfigReferences(config v1.GenericConfig) (v1.GenericConfig, error) {
+	if len(config) == 0 {
+		return config, nil
+	}
+
+	r := &configResolver{
+		config:    config,
+		resolved:  make(map[string]any),
+		resolving: make(map[string]bool),
+	}
+	result := make(v1.GenericConfig, len(config))
+	for k := range config {
+		v, err := r.resolvePath(k)
+		if err != nil {
+			return nil, err
+		}
+		result[k] = v
+	}
+	return result, nil
+}
+
+type configResolver struct {
+	config v1.GenericConfig
+	// resolved caches the resolved values of the paths.
+	resolved map[string]any
+	// resolving and stack record the paths being resolved, used to detect reference cycles.
+	resolving map[string]bool
+	stack     []string
+}
+
+// resolvePath returns the resolved value of the path in the config.
+func (r *configResolver) resolvePath(path string) (any, error) {
+	if v, ok := r.resolved[path]; ok {
+		return v, nil
+	}
+	if r.resolving[path] {
+		chain := append(slices.Clone(r.stack[slices.Index(r.stack, path):]), path)
+		return nil, fmt.Errorf("%w: %s", ErrConfigReferenceCycle, strings.Join(chain, " -> "))
+	}
+	raw, ok := lookupConfigPath(r.config, path)
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrConfigReferenceNotFound, path)
+	}
+
+	r.resolving[path] = true
+	r.stack = append(r.stack, path)
+	v, err := r.resolveValue(raw)
+	r.stack = r.stack[:len(r.stack)-1]
+	delete(r.resolving, path)
+	if err != nil {
+		return nil, err
+	}
+	r.resolved[path] = v
+	return v, nil
+}
+
+// resolveValue resolves the references in the value, and returns a resolved copy if it is a map or slice.
+func (r *configResolver) resolveValue(value any) (any, error) {
+	switch v := value.(type) {
+	case string:
+		return r.resolveString(v)
+	case v1.GenericConfig:
+		return r.resolveMap(v)
+	case map[string]any:
+		m, err := r.resolveMap(v)
+		if err != nil {
+			return nil, err
+		}
+		return map[string]any(m), nil
+	case []any:
+		s := make([]any, len(v))
+		for i, item := range v {
+			resolved, err := r.resolveValue(item)
+			if err != nil {
+				return nil, err
+			}
+			s[i] = resolved
+		}
+		return s, nil
+	default:
+		return value, nil
+	}
+}
+
+func (r *configResolver) resolveMap(m map[string]any) (v1.GenericConfig, error) {
+	result := make(v1.GenericConfig, len(m))
+	for k, item := range m {
+		resolved, err := r.resolveValue(item)
+		if err != nil {
+			return nil, err
+		}
+		result[k] = resolved
+	}
+	return result, nil
+}
+
+func (r *configResolver) resolveString(s string) (any, error) {
+	matches := configReferenceRegex.FindAllStringSubmatchIndex(s, -1)
+	if len(matches) == 0 {
+		return s, nil
+	}
+
+	// the whole string is a single reference, keep the type of the referenced value.
+	if len(matches) == 1 && matches[0][0] == 0 && matches[0][1] == len(s) {
+		return r.resolvePath(s[matches[0][2]:matches[0][3]])
+	}
+
+	var b strings.Builder
+	last := 0
+	for _, m := range matches {
+		path := s[m[2]:m[3]]
+		v, err := r.resolvePath(path)
+		if err != nil {
+			return nil, err
+		}
+		switch v.(type) {
+		case v1.GenericConfig, map[string]any, []any:
+			return nil, fmt.Errorf("%w: %s", ErrConfigReferenceNotValue, path)
+		}
+		b.WriteString(s[last:m[0]])
+		b.WriteString(fmt.Sprint(v))
+		last = m[1]
+	}
+	b.WriteString(s[last:])
+	return b.String(), nil
+}
+
+// lookupConfigPath returns the raw value of the dot-separated path in the config.
+func lookupConfigPath(config v1.GenericConfig, path string) (any, bool) {
+	var current any = config
+	for _, key := range strings.Split(path, ".") {
+		var m map[string]any
+		switch c := current.(type) {
+		case v1.GenericConfig:
+			m = c
+		case map[string]any:
+			m = c
+		default:
+			return nil, false
+		}
+		v, ok := m[key]
+		if !ok {
+			return nil, false
+		}
+		current = v
+	}
+	return current, true
+}
@@ -0,0 +1,125 @@
+package workspace
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	v1 "kusionstack.io/kusion/pkg/apis/api.kusion.io/v1"
+)
+
+func Test_ResolveConfigReferences(t *testing.T) {
+	testcases := []struct {
+		name           string
+		config         v1.GenericConfig
+		expectedConfig v1.GenericConfig
+		expectedErr    error
+	}{
+		{
+			name: "no reference",
+			config: v1.GenericConfig{
+				"name": "mysql",
+				"port": 3306,
+			},
+			expectedConfig: v1.GenericConfig{
+				"name": "mysql",
+				"port": 3306,
+			},
+		},
+		{
+			name: "simple reference",
+			config: v1.GenericConfig{
+				"name": "mysql",
+				"host": "${config.name}.example.com",
+				"port": 3306,
+				"url":  "${config.name}:${config.port}",
+				"db": v1.GenericConfig{
+					"port": "${config.port}",
+				},
+			},
+			expectedConfig: v1.GenericConfig{
+				"name": "mysql",
+				"host": "mysql.example.com",
+				"port": 3306,
+				"url":  "mysql:3306",
+				"db": v1.GenericConfig{
+					"port": 3306,
+				},
+			},
+		},
+		{
+			name: "transitive reference chain",
+			config: v1.GenericConfig{
+				"name":     "mysql",
+				"host":     "${config.name}.example.com",
+				"endpoint": "${config.db.address}",
+				"db": v1.GenericConfig{
+					"address": "${config.host}:3306",
+				},
+			},
+			expectedConfig: v1.GenericConfig{
+				"name":     "mysql",
+				"host":     "mysql.example.com",
+				"endpoint": "mysql.example.com:3306",
+				"db": v1.GenericConfig{
+					"address": "mysql.example.com:3306",
+				},
+			},
+		},
+		{
+			name: "reference cycle",
+			config: v1.GenericConfig{
+				"a": "${config.b}",
+				"b": "prefix-${config.c}",
+				"c": "${config.a}",
+			},
+			expectedErr: ErrConfigReferenceCycle,
+		},
+		{
+			name: "self reference",
+			config: v1.GenericConfig{
+				"a": "${config.a}",
+			},
+			expectedErr: ErrConfigReferenceCycle,
+		},
+		{
+			name: "reference not found",
+			config: v1.GenericConfig{
+				"host": "${config.name}.example.com",
+			},
+			expectedErr: ErrConfigReferenceNotFound,
+		},
+		{
+			name: "interpolate map value",
+			config: v1.GenericConfig{
+				"db":   v1.GenericConfig{"port": 3306},
+				"host": "host-${config.db}",
+			},
+			expectedErr: ErrConfigReferenceNotValue,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			cfg, err := ResolveConfigReferences(tc.config)
+			if tc.expectedErr != nil {
+				assert.ErrorIs(t, err, tc.expectedErr)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expectedConfig, cfg)
+		})
+	}
+}
+
+func Test_ResolveConfigReferences_CycleMessage(t *testing.T) {
+	_, err := ResolveConfigReferences(v1.GenericConfig{
+		"a": "${config.b}",
+		"b": "${config.a}",
+	})
+	assert.ErrorIs(t, err, ErrConfigReferenceCycle)
+	assert.Contains(t, []string{
+		"config reference cycle: a -> b -> a",
+		"config reference cycle: b -> a -> b",
+	}, err.Error())
+}
@@ -1,6 +1,10 @@
 package workspace
 
 import (
+	"fmt"
+
+	"gopkg.in/yaml.v3"
+
 	v1 "kusionstack.io/kusion/pkg/apis/api.kusion.io/v1"
 )
 
@@ -29,3 +33,13 @@ type Storage interface {
 	// SetCurrent sets the specified workspace as the current workspace.
 	SetCurrent(name string) error
 }
+
+// UnmarshalWorkspace unmarshals the workspace content, and sets the workspace name with its identifier in
+// the storage, for the name is not serialized.
+func UnmarshalWorkspace(content []byte, name string) (*v1.Workspace, error) {
+	ws := &v1.Workspace{}
+	if err := yaml.Unmarshal(content, ws); err != nil {
+		return nil, fmt.Errorf("yaml unmarshal workspace failed: %w", err)
+	}
+	return ws.WithName(name), nil
+}
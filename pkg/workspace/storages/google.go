@@ -10,6 +10,7 @@ import (
 	googlestorage "cloud.google.com/go/storage"
 
 	v1 "kusionstack.io/kusion/pkg/apis/api.kusion.io/v1"
+	"kusionstack.io/kusion/pkg/workspace"
 )
 
 // GoogleStorage is an implementation of workspace.Storage which uses google cloud as storage.
@@ -53,15 +54,13 @@ func (s *GoogleStorage) Get(name string) (*v1.Workspace, error) {
 		return nil, fmt.Errorf("read workspace failed: %w", err)
 	}
 
-	ws := &v1.Workspace{}
-	if err = yaml.Unmarshal(content, ws); err != nil {
-		return nil, fmt.Errorf("yaml unmarshal workspace failed: %w", err)
-	}
-	ws.Name = name
-	return ws, nil
+	return workspace.UnmarshalWorkspace(content, name)
 }
 
 func (s *GoogleStorage) Create(ws *v1.Workspace) error {
+	if ws.GetName() == "" {
+		return workspace.ErrEmptyWorkspaceName
+	}
 	if checkWorkspaceExistence(s.meta, ws.Name) {
 		return ErrWorkspaceAlreadyExist
 	}
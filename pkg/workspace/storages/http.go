@@ -0,0 +1,219 @@
+package storages
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+
+	v1 "kusionstack.io/kusion/pkg/apis/api.kusion.io/v1"
+	"kusionstack.io/kusion/pkg/workspace"
+)
+
+const httpMetadataPath = "metadata"
+
+// defaultHTTPTimeout is the timeout of the requests if the http client is not specified.
+const defaultHTTPTimeout = 30 * time.Second
+
+// HTTPStorage is an implementation of workspace.Storage which uses a remote HTTP service as storage.
+// The workspaces and their metadata are read and written in yaml through the REST APIs shown as below,
+// where the url is generated by GenHTTPWorkspaceURL:
+//   - GET    {url}/metadata: get the workspaces metadata.
+//   - PUT    {url}/metadata: update the workspaces metadata.
+//   - GET    {url}/{name}: get the workspace of the name.
+//   - PUT    {url}/{name}: create or update the workspace of the name.
+//   - DELETE {url}/{name}: delete the workspace of the name.
+type HTTPStorage struct {
+	client *http.Client
+
+	// The url of the workspaces.
+	url string
+
+	// The bearer token to access the workspace service.
+	token string
+
+	meta *workspacesMetaData
+}
+
+// NewHTTPStorage news http workspace storage and init default workspace. If the client is nil, a client
+// with the default timeout is used.
+func NewHTTPStorage(client *http.Client, url, token string) (*HTTPStorage, error) {
+	if client == nil {
+		client = &http.Client{Timeout: defaultHTTPTimeout}
+	}
+	s := &HTTPStorage{
+		client: client,
+		url:    strings.TrimSuffix(url, "/"),
+		token:  token,
+	}
+	if err := s.readMeta(); err != nil {
+		return nil, err
+	}
+	return s, s.initDefaultWorkspaceIf()
+}
+
+func (s *HTTPStorage) Get(name string) (*v1.Workspace, error) {
+	if name == "" {
+		name = s.meta.Current
+	}
+	if !checkWorkspaceExistence(s.meta, name) {
+		return nil, ErrWorkspaceNotExist
+	}
+
+	content, err := s.do(http.MethodGet, s.url+"/"+name, nil)
+	if err != nil {
+		return nil, fmt.Errorf("get workspace failed: %w", err)
+	}
+	return workspace.UnmarshalWorkspace(content, name)
+}
+
+func (s *HTTPStorage) Create(ws *v1.Workspace) error {
+	if ws.GetName() == "" {
+		return workspace.ErrEmptyWorkspaceName
+	}
+	if checkWorkspaceExistence(s.meta, ws.Name) {
+		return ErrWorkspaceAlreadyExist
+	}
+
+	if err := s.writeWorkspace(ws); err != nil {
+		return err
+	}
+
+	addAvailableWorkspaces(s.meta, ws.Name)
+	return s.writeMeta()
+}
+
+func (s *HTTPStorage) Update(ws *v1.Workspace) error {
+	if ws.Name == "" {
+		ws.Name = s.meta.Current
+	}
+	if !checkWorkspaceExistence(s.meta, ws.Name) {
+		return ErrWorkspaceNotExist
+	}
+
+	return s.writeWorkspace(ws)
+}
+
+func (s *HTTPStorage) Delete(name string) error {
+	if !checkWorkspaceExistence(s.meta, name) {
+		return nil
+	}
+
+	_, err := s.do(http.MethodDelete, s.url+"/"+name, nil)
+	if err != nil && !errors.Is(err, ErrWorkspaceNotExist) {
+		return fmt.Errorf("delete workspace failed: %w", err)
+	}
+
+	removeAvailableWorkspaces(s.meta, name)
+	return s.writeMeta()
+}
+
+func (s *HTTPStorage) GetNames() ([]string, error) {
+	return s.meta.AvailableWorkspaces, nil
+}
+
+func (s *HTTPStorage) GetCurrent() (string, error) {
+	return s.meta.Current, nil
+}
+
+func (s *HTTPStorage) SetCurrent(name string) error {
+	if !checkWorkspaceExistence(s.meta, name) {
+		return ErrWorkspaceNotExist
+	}
+	s.meta.Current = name
+	return s.writeMeta()
+}
+
+func (s *HTTPStorage) initDefaultWorkspaceIf() error {
+	if !checkWorkspaceExistence(s.meta, DefaultWorkspace) {
+		// if there is no default workspace, create one with empty workspace.
+		if err := s.writeWorkspace(&v1.Workspace{Name: DefaultWorkspace}); err != nil {
+			return err
+		}
+		addAvailableWorkspaces(s.meta, DefaultWorkspace)
+	}
+
+	if s.meta.Current == "" {
+		s.meta.Current = DefaultWorkspace
+	}
+	return s.writeMeta()
+}
+
+func (s *HTTPStorage) readMeta() error {
+	content, err := s.do(http.MethodGet, s.url+"/"+httpMetadataPath, nil)
+	if errors.Is(err, ErrWorkspaceNotExist) {
+		s.meta = &workspacesMetaData{}
+		return nil
+	} else if err != nil {
+		return fmt.Errorf("get workspaces metadata failed: %w", err)
+	}
+
+	meta := &workspacesMetaData{}
+	if err = yaml.Unmarshal(content, meta); err != nil {
+		return fmt.Errorf("yaml unmarshal workspaces metadata failed: %w", err)
+	}
+	s.meta = meta
+	return nil
+}
+
+func (s *HTTPStorage) writeMeta() error {
+	content, err := yaml.Marshal(s.meta)
+	if err != nil {
+		return fmt.Errorf("yaml marshal workspaces metadata failed: %w", err)
+	}
+	if _, err = s.do(http.MethodPut, s.url+"/"+httpMetadataPath, content); err != nil {
+		return fmt.Errorf("put workspaces metadata failed: %w", err)
+	}
+	return nil
+}
+
+func (s *HTTPStorage) writeWorkspace(ws *v1.Workspace) error {
+	content, err := yaml.Marshal(ws)
+	if err != nil {
+		return fmt.Errorf("yaml marshal workspace failed: %w", err)
+	}
+	if _, err = s.do(http.MethodPut, s.url+"/"+ws.Name, content); err != nil {
+		return fmt.Errorf("put workspace failed: %w", err)
+	}
+	return nil
+}
+
+// do sends the request with the yaml body, and returns the content of the response. The status code 404
+// is converted to ErrWorkspaceNotExist.
+func (s *HTTPStorage) do(method, url string, body []byte) ([]byte, error) {
+	req, err := http.NewRequest(method, url, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/yaml")
+	}
+	if s.token != "" {
+		req.Header.Set("Authorization", "Bearer "+s.token)
+	}
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+
+	content, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("read response body failed: %w", err)
+	}
+	switch {
+	case resp.StatusCode == http.StatusNotFound:
+		return nil, ErrWorkspaceNotExist
+	case resp.StatusCode < http.StatusOK || resp.StatusCode >= http.StatusMultipleChoices:
+		return nil, fmt.Errorf("unexpected status code %d: %s", resp.StatusCode, string(content))
+	}
+	return content, nil
+}
@@ -0,0 +1,171 @@
+package storages
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
+)
+
+const mockHTTPToken = "fake-token"
+
+// mockWorkspaceServer is an in-memory HTTP workspace service for testing.
+type mockWorkspaceServer struct {
+	mu         sync.Mutex
+	workspaces map[string][]byte
+	meta       []byte
+}
+
+func (m *mockWorkspaceServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if r.Header.Get("Authorization") != "Bearer "+mockHTTPToken {
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+
+	prefix := "/" + workspacesPrefix + "/"
+	if !strings.HasPrefix(r.URL.Path, prefix) {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+	path := strings.TrimPrefix(r.URL.Path, prefix)
+
+	switch {
+	case r.Method == http.MethodGet && path == httpMetadataPath:
+		if m.meta == nil {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		_, _ = w.Write(m.meta)
+	case r.Method == http.MethodPut && path == httpMetadataPath:
+		m.meta, _ = io.ReadAll(r.Body)
+	case r.Method == http.MethodGet:
+		content, ok := m.workspaces[path]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		_, _ = w.Write(content)
+	case r.Method == http.MethodPut:
+		m.workspaces[path], _ = io.ReadAll(r.Body)
+	case r.Method == http.MethodDelete:
+		if _, ok := m.workspaces[path]; !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		delete(m.workspaces, path)
+	default:
+		w.WriteHeader(http.StatusMethodNotAllowed)
+	}
+}
+
+func newMockWorkspaceServer() (*httptest.Server, *mockWorkspaceServer) {
+	m := &mockWorkspaceServer{workspaces: map[string][]byte{}}
+	return httptest.NewServer(m), m
+}
+
+func TestNewHTTPStorage(t *testing.T) {
+	testcases := []struct {
+		name         string
+		success      bool
+		token        string
+		meta         *workspacesMetaData
+		expectedMeta *workspacesMetaData
+	}{
+		{
+			name:    "new http storage and init default workspace",
+			success: true,
+			token:   mockHTTPToken,
+			expectedMeta: &workspacesMetaData{
+				Current:             DefaultWorkspace,
+				AvailableWorkspaces: []string{DefaultWorkspace},
+			},
+		},
+		{
+			name:         "new http storage with exist workspaces",
+			success:      true,
+			token:        mockHTTPToken,
+			meta:         mockWorkspacesMetaData(),
+			expectedMeta: mockWorkspacesMetaData(),
+		},
+		{
+			name:    "failed to new http storage unauthorized",
+			success: false,
+			token:   "invalid-token",
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			server, m := newMockWorkspaceServer()
+			defer server.Close()
+			if tc.meta != nil {
+				m.meta, _ = yaml.Marshal(tc.meta)
+			}
+
+			s, err := NewHTTPStorage(server.Client(), GenHTTPWorkspaceURL(server.URL), tc.token)
+			assert.Equal(t, tc.success, err == nil)
+			if tc.success {
+				assert.Equal(t, tc.expectedMeta, s.meta)
+			}
+		})
+	}
+}
+
+func TestHTTPStorage(t *testing.T) {
+	server, m := newMockWorkspaceServer()
+	defer server.Close()
+
+	s, err := NewHTTPStorage(server.Client(), GenHTTPWorkspaceURL(server.URL), mockHTTPToken)
+	require.NoError(t, err)
+
+	// create and get the workspace
+	err = s.Create(mockWorkspace("dev"))
+	assert.NoError(t, err)
+	err = s.Create(mockWorkspace("dev"))
+	assert.ErrorIs(t, err, ErrWorkspaceAlreadyExist)
+	ws, err := s.Get("dev")
+	assert.NoError(t, err)
+	assert.Equal(t, mockWorkspace("dev"), ws)
+
+	// update the workspace
+	updated := mockWorkspace("dev")
+	updated.Context = map[string]any{"foo": "bar"}
+	assert.NoError(t, s.Update(updated))
+	ws, err = s.Get("dev")
+	assert.NoError(t, err)
+	assert.Equal(t, updated, ws)
+	assert.ErrorIs(t, s.Update(mockWorkspace("prod")), ErrWorkspaceNotExist)
+
+	// set the current workspace, which is kept in the metadata of the service
+	assert.NoError(t, s.SetCurrent("dev"))
+	assert.ErrorIs(t, s.SetCurrent("prod"), ErrWorkspaceNotExist)
+	current, err := s.GetCurrent()
+	assert.NoError(t, err)
+	assert.Equal(t, "dev", current)
+	reloaded, err := NewHTTPStorage(server.Client(), GenHTTPWorkspaceURL(server.URL), mockHTTPToken)
+	require.NoError(t, err)
+	names, err := reloaded.GetNames()
+	assert.NoError(t, err)
+	assert.Equal(t, []string{DefaultWorkspace, "dev"}, names)
+	current, err = reloaded.GetCurrent()
+	assert.NoError(t, err)
+	assert.Equal(t, "dev", current)
+
+	// delete the current workspace, which resets the current to default
+	assert.NoError(t, s.Delete("dev"))
+	_, err = s.Get("dev")
+	assert.ErrorIs(t, err, ErrWorkspaceNotExist)
+	assert.NotContains(t, m.workspaces, "dev")
+	current, err = s.GetCurrent()
+	assert.NoError(t, err)
+	assert.Equal(t, DefaultWorkspace, current)
+}
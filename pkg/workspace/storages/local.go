@@ -8,6 +8,7 @@ import (
 	"gopkg.in/yaml.v3"
 
 	v1 "kusionstack.io/kusion/pkg/apis/api.kusion.io/v1"
+	"kusionstack.io/kusion/pkg/workspace"
 )
 
 // LocalStorage is an implementation of workspace.Storage which uses local filesystem as storage.
@@ -47,15 +48,13 @@ func (s *LocalStorage) Get(name string) (*v1.Workspace, error) {
 		return nil, fmt.Errorf("read workspace file failed: %w", err)
 	}
 
-	ws := &v1.Workspace{}
-	if err = yaml.Unmarshal(content, ws); err != nil {
-		return nil, fmt.Errorf("yaml unmarshal workspace failed: %w", err)
-	}
-	ws.Name = name
-	return ws, nil
+	return workspace.UnmarshalWorkspace(content, name)
 }
 
 func (s *LocalStorage) Create(ws *v1.Workspace) error {
+	if ws.GetName() == "" {
+		return workspace.ErrEmptyWorkspaceName
+	}
 	if checkWorkspaceExistence(s.meta, ws.Name) {
 		return ErrWorkspaceAlreadyExist
 	}
@@ -156,12 +155,18 @@ func (s *LocalStorage) writeMeta() error {
 }
 
 func (s *LocalStorage) writeWorkspace(ws *v1.Workspace) error {
-	content, err := yaml.Marshal(ws)
+	path := filepath.Join(s.path, ws.Name+yamlSuffix)
+	// the previous content is used to preserve the anchors and aliases of the workspace file.
+	previous, err := os.ReadFile(path)
+	if err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("read workspace file failed: %w", err)
+	}
+	content, err := marshalWorkspace(ws, previous)
 	if err != nil {
-		return fmt.Errorf("yaml marshal workspace failed: %w", err)
+		return err
 	}
 
-	if err = os.WriteFile(filepath.Join(s.path, ws.Name+yamlSuffix), content, os.ModePerm); err != nil {
+	if err = os.WriteFile(path, content, os.ModePerm); err != nil {
 		return fmt.Errorf("write workspace file failed: %w", err)
 	}
 	return nil
@@ -141,6 +141,12 @@ func TestLocalStorage_Get(t *testing.T) {
 			wsName:            "dev",
 			expectedWorkspace: mockWorkspace("dev"),
 		},
+		{
+			name:              "get current workspace successfully",
+			success:           true,
+			wsName:            "",
+			expectedWorkspace: mockWorkspace("dev"),
+		},
 		{
 			name:              "get workspace failed not exist",
 			success:           false,
@@ -180,6 +186,13 @@ func TestLocalStorage_Create(t *testing.T) {
 				AvailableWorkspaces: []string{"default", "dev"},
 			},
 		},
+		{
+			name:         "create workspace failed empty name",
+			success:      false,
+			path:         testDataFolder("for_create_workspaces"),
+			workspace:    mockWorkspace(""),
+			expectedMeta: nil,
+		},
 		{
 			name:         "create workspace failed already exist",
 			success:      false,
@@ -240,6 +253,35 @@ func TestLocalStorage_Update(t *testing.T) {
 	}
 }
 
+func TestLocalStorage_UpdateAnchoredWorkspace(t *testing.T) {
+	dir := t.TempDir()
+	content, err := os.ReadFile(testDataFolder("anchored_workspace.yaml"))
+	assert.NoError(t, err)
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "dev.yaml"), content, os.ModePerm))
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, metadataFile), []byte("current: dev\navailableWorkspaces:\n  - dev\n"), os.ModePerm))
+
+	s, err := NewLocalStorage(dir)
+	assert.NoError(t, err)
+	ws, err := s.Get("dev")
+	assert.NoError(t, err)
+	assert.Equal(t, mockAnchoredWorkspace("dev"), ws)
+
+	// updating with the unchanged workspace keeps the file as it is.
+	assert.NoError(t, s.Update(ws))
+	updated, err := os.ReadFile(filepath.Join(dir, "dev.yaml"))
+	assert.NoError(t, err)
+	assert.Equal(t, string(content), string(updated))
+
+	ws.Modules["postgres"].Configs.Default["instanceType"] = "db.t3.large"
+	assert.NoError(t, s.Update(ws))
+	updated, err = os.ReadFile(filepath.Join(dir, "dev.yaml"))
+	assert.NoError(t, err)
+	assert.Contains(t, string(updated), "<<: *database")
+	updatedWs, err := s.Get("dev")
+	assert.NoError(t, err)
+	assert.Equal(t, ws, updatedWs)
+}
+
 func TestLocalStorage_Delete(t *testing.T) {
 	testcases := []struct {
 		name         string
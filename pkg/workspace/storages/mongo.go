@@ -0,0 +1,239 @@
+package storages
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"gopkg.in/yaml.v3"
+
+	v1 "kusionstack.io/kusion/pkg/apis/api.kusion.io/v1"
+	"kusionstack.io/kusion/pkg/workspace"
+)
+
+const (
+	mongoWorkspaceCollection = "workspaces"
+
+	// DefaultMongoTimeout is the default timeout of each operation on MongoDB.
+	DefaultMongoTimeout = 30 * time.Second
+)
+
+// MongoStorage is an implementation of workspace.Storage which uses MongoDB as storage. The workspaces
+// are stored in the collection workspaces whose _id is the workspace name, and the current workspace is
+// the one whose current is true.
+type MongoStorage struct {
+	workspaces *mongo.Collection
+
+	// The timeout of each operation on MongoDB.
+	timeout time.Duration
+
+	meta *workspacesMetaData
+}
+
+// mongoWorkspace is the document of a workspace stored in MongoDB, where the workspace is kept as its
+// yaml content, which is the same as the other storages.
+type mongoWorkspace struct {
+	Name    string `bson:"_id"`
+	Content string `bson:"content,omitempty"`
+	Current bool   `bson:"current"`
+}
+
+// NewMongoStorage news mongo workspace storage and init default workspace. Each operation on MongoDB is
+// bounded by the timeout, and DefaultMongoTimeout is used if the timeout is not positive.
+func NewMongoStorage(db *mongo.Database, timeout time.Duration) (*MongoStorage, error) {
+	if timeout <= 0 {
+		timeout = DefaultMongoTimeout
+	}
+	s := &MongoStorage{
+		workspaces: db.Collection(mongoWorkspaceCollection),
+		timeout:    timeout,
+	}
+	if err := s.readMeta(); err != nil {
+		return nil, err
+	}
+	return s, s.initDefaultWorkspaceIf()
+}
+
+func (s *MongoStorage) Get(name string) (*v1.Workspace, error) {
+	if name == "" {
+		name = s.meta.Current
+	}
+	if !checkWorkspaceExistence(s.meta, name) {
+		return nil, ErrWorkspaceNotExist
+	}
+
+	ctx, cancel := s.newContext()
+	defer cancel()
+	doc := &mongoWorkspace{}
+	err := s.workspaces.FindOne(ctx, bson.M{"_id": name}).Decode(doc)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, ErrWorkspaceNotExist
+	} else if err != nil {
+		return nil, fmt.Errorf("find workspace failed: %w", err)
+	}
+
+	return workspace.UnmarshalWorkspace([]byte(doc.Content), name)
+}
+
+func (s *MongoStorage) Create(ws *v1.Workspace) error {
+	if ws.GetName() == "" {
+		return workspace.ErrEmptyWorkspaceName
+	}
+	if checkWorkspaceExistence(s.meta, ws.Name) {
+		return ErrWorkspaceAlreadyExist
+	}
+
+	if err := s.insertWorkspace(ws); err != nil {
+		return err
+	}
+
+	addAvailableWorkspaces(s.meta, ws.Name)
+	return nil
+}
+
+func (s *MongoStorage) Update(ws *v1.Workspace) error {
+	if ws.Name == "" {
+		ws.Name = s.meta.Current
+	}
+	if !checkWorkspaceExistence(s.meta, ws.Name) {
+		return ErrWorkspaceNotExist
+	}
+
+	content, err := yaml.Marshal(ws)
+	if err != nil {
+		return fmt.Errorf("yaml marshal workspace failed: %w", err)
+	}
+
+	ctx, cancel := s.newContext()
+	defer cancel()
+	result, err := s.workspaces.UpdateOne(ctx, bson.M{"_id": ws.Name}, bson.M{"$set": bson.M{"content": string(content)}})
+	if err != nil {
+		return fmt.Errorf("update workspace failed: %w", err)
+	}
+	if result.MatchedCount == 0 {
+		return ErrWorkspaceNotExist
+	}
+	return nil
+}
+
+func (s *MongoStorage) Delete(name string) error {
+	if !checkWorkspaceExistence(s.meta, name) {
+		return nil
+	}
+
+	ctx, cancel := s.newContext()
+	defer cancel()
+	if _, err := s.workspaces.DeleteOne(ctx, bson.M{"_id": name}); err != nil {
+		return fmt.Errorf("delete workspace failed: %w", err)
+	}
+
+	current := s.meta.Current
+	removeAvailableWorkspaces(s.meta, name)
+	if s.meta.Current != current {
+		return s.writeCurrent(s.meta.Current)
+	}
+	return nil
+}
+
+func (s *MongoStorage) GetNames() ([]string, error) {
+	return s.meta.AvailableWorkspaces, nil
+}
+
+func (s *MongoStorage) GetCurrent() (string, error) {
+	return s.meta.Current, nil
+}
+
+func (s *MongoStorage) SetCurrent(name string) error {
+	if !checkWorkspaceExistence(s.meta, name) {
+		return ErrWorkspaceNotExist
+	}
+	if err := s.writeCurrent(name); err != nil {
+		return err
+	}
+	s.meta.Current = name
+	return nil
+}
+
+func (s *MongoStorage) initDefaultWorkspaceIf() error {
+	if !checkWorkspaceExistence(s.meta, DefaultWorkspace) {
+		// if there is no default workspace, create one with empty workspace.
+		if err := s.insertWorkspace(&v1.Workspace{Name: DefaultWorkspace}); err != nil {
+			return err
+		}
+		addAvailableWorkspaces(s.meta, DefaultWorkspace)
+	}
+
+	if s.meta.Current == "" {
+		return s.SetCurrent(DefaultWorkspace)
+	}
+	return nil
+}
+
+func (s *MongoStorage) readMeta() error {
+	ctx, cancel := s.newContext()
+	defer cancel()
+	cursor, err := s.workspaces.Find(
+		ctx,
+		bson.M{},
+		options.Find().
+			SetProjection(bson.M{"_id": 1, "current": 1}).
+			SetSort(bson.D{{Key: "_id", Value: 1}}),
+	)
+	if err != nil {
+		return fmt.Errorf("find workspaces metadata failed: %w", err)
+	}
+
+	var docs []mongoWorkspace
+	if err = cursor.All(ctx, &docs); err != nil {
+		return fmt.Errorf("decode workspaces metadata failed: %w", err)
+	}
+
+	meta := &workspacesMetaData{}
+	for _, doc := range docs {
+		addAvailableWorkspaces(meta, doc.Name)
+		if doc.Current {
+			meta.Current = doc.Name
+		}
+	}
+	s.meta = meta
+	return nil
+}
+
+func (s *MongoStorage) insertWorkspace(ws *v1.Workspace) error {
+	content, err := yaml.Marshal(ws)
+	if err != nil {
+		return fmt.Errorf("yaml marshal workspace failed: %w", err)
+	}
+
+	ctx, cancel := s.newContext()
+	defer cancel()
+	if _, err = s.workspaces.InsertOne(ctx, &mongoWorkspace{Name: ws.Name, Content: string(content)}); err != nil {
+		if mongo.IsDuplicateKeyError(err) {
+			return ErrWorkspaceAlreadyExist
+		}
+		return fmt.Errorf("insert workspace failed: %w", err)
+	}
+	return nil
+}
+
+// writeCurrent marks the workspace as the current one and unmarks the others in a single update.
+func (s *MongoStorage) writeCurrent(name string) error {
+	ctx, cancel := s.newContext()
+	defer cancel()
+	_, err := s.workspaces.UpdateMany(ctx, bson.M{}, mongo.Pipeline{
+		{{Key: "$set", Value: bson.M{"current": bson.M{"$eq": bson.A{"$_id", name}}}}},
+	})
+	if err != nil {
+		return fmt.Errorf("update current workspace failed: %w", err)
+	}
+	return nil
+}
+
+// newContext returns the context of a single operation on MongoDB, which is bounded by the timeout.
+func (s *MongoStorage) newContext() (context.Context, context.CancelFunc) {
+	return context.WithTimeout(context.Background(), s.timeout)
+}
@@ -0,0 +1,165 @@
+package storages
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/integration/mtest"
+)
+
+const mockMongoNamespace = "kusion.workspaces"
+
+func mockMongoMetaDocs() []bson.D {
+	return []bson.D{
+		{{Key: "_id", Value: "default"}, {Key: "current", Value: false}},
+		{{Key: "_id", Value: "dev"}, {Key: "current", Value: true}},
+		{{Key: "_id", Value: "prod"}, {Key: "current", Value: false}},
+	}
+}
+
+// newMockMongoStorage news a mongo storage on the mocked deployment, with the workspaces default, dev and
+// prod, where dev is the current workspace.
+func newMockMongoStorage(mt *mtest.T) *MongoStorage {
+	mt.AddMockResponses(mtest.CreateCursorResponse(0, mockMongoNamespace, mtest.FirstBatch, mockMongoMetaDocs()...))
+	s, err := NewMongoStorage(mt.DB, time.Second)
+	require.NoError(mt, err)
+	return s
+}
+
+func TestNewMongoStorage(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+
+	mt.Run("new mongo storage and init default workspace", func(mt *mtest.T) {
+		mt.AddMockResponses(
+			mtest.CreateCursorResponse(0, mockMongoNamespace, mtest.FirstBatch),
+			mtest.CreateSuccessResponse(),
+			mtest.CreateSuccessResponse(bson.E{Key: "n", Value: 1}, bson.E{Key: "nModified", Value: 1}),
+		)
+		s, err := NewMongoStorage(mt.DB, time.Second)
+		assert.NoError(mt, err)
+		assert.Equal(mt, &workspacesMetaData{
+			Current:             DefaultWorkspace,
+			AvailableWorkspaces: []string{DefaultWorkspace},
+		}, s.meta)
+	})
+
+	mt.Run("new mongo storage with exist workspaces", func(mt *mtest.T) {
+		s := newMockMongoStorage(mt)
+		assert.Equal(mt, mockWorkspacesMetaData(), s.meta)
+	})
+
+	mt.Run("failed to new mongo storage find failed", func(mt *mtest.T) {
+		mt.AddMockResponses(mtest.CreateCommandErrorResponse(mtest.CommandError{Code: 13, Message: "unauthorized"}))
+		_, err := NewMongoStorage(mt.DB, time.Second)
+		assert.Error(mt, err)
+	})
+}
+
+func TestMongoStorage_Get(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+
+	mt.Run("get workspace successfully", func(mt *mtest.T) {
+		s := newMockMongoStorage(mt)
+		mt.AddMockResponses(mtest.CreateCursorResponse(0, mockMongoNamespace, mtest.FirstBatch, bson.D{
+			{Key: "_id", Value: "dev"},
+			{Key: "content", Value: mockWorkspaceContent()},
+			{Key: "current", Value: true},
+		}))
+
+		ws, err := s.Get("")
+		assert.NoError(mt, err)
+		assert.Equal(mt, mockWorkspace("dev"), ws)
+	})
+
+	mt.Run("failed to get workspace not exist", func(mt *mtest.T) {
+		s := newMockMongoStorage(mt)
+		_, err := s.Get("staging")
+		assert.ErrorIs(mt, err, ErrWorkspaceNotExist)
+	})
+}
+
+func TestMongoStorage_Create(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+
+	mt.Run("create workspace successfully", func(mt *mtest.T) {
+		s := newMockMongoStorage(mt)
+		mt.AddMockResponses(mtest.CreateSuccessResponse())
+
+		err := s.Create(mockWorkspace("staging"))
+		assert.NoError(mt, err)
+		assert.Contains(mt, s.meta.AvailableWorkspaces, "staging")
+	})
+
+	mt.Run("failed to create workspace already exist in metadata", func(mt *mtest.T) {
+		s := newMockMongoStorage(mt)
+		err := s.Create(mockWorkspace("dev"))
+		assert.ErrorIs(mt, err, ErrWorkspaceAlreadyExist)
+	})
+
+	mt.Run("failed to create workspace inserted concurrently", func(mt *mtest.T) {
+		s := newMockMongoStorage(mt)
+		mt.AddMockResponses(mtest.CreateWriteErrorsResponse(mtest.WriteError{Index: 0, Code: 11000, Message: "duplicate key"}))
+
+		err := s.Create(mockWorkspace("staging"))
+		assert.ErrorIs(mt, err, ErrWorkspaceAlreadyExist)
+		assert.NotContains(mt, s.meta.AvailableWorkspaces, "staging")
+	})
+}
+
+func TestMongoStorage_Update(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+
+	mt.Run("update workspace successfully", func(mt *mtest.T) {
+		s := newMockMongoStorage(mt)
+		mt.AddMockResponses(mtest.CreateSuccessResponse(bson.E{Key: "n", Value: 1}, bson.E{Key: "nModified", Value: 1}))
+
+		err := s.Update(mockWorkspace("dev"))
+		assert.NoError(mt, err)
+	})
+
+	mt.Run("failed to update workspace deleted by others", func(mt *mtest.T) {
+		s := newMockMongoStorage(mt)
+		mt.AddMockResponses(mtest.CreateSuccessResponse(bson.E{Key: "n", Value: 0}, bson.E{Key: "nModified", Value: 0}))
+
+		err := s.Update(mockWorkspace("prod"))
+		assert.ErrorIs(mt, err, ErrWorkspaceNotExist)
+	})
+}
+
+func TestMongoStorage_DeleteAndSetCurrent(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+
+	mt.Run("delete current workspace", func(mt *mtest.T) {
+		s := newMockMongoStorage(mt)
+		mt.AddMockResponses(
+			mtest.CreateSuccessResponse(bson.E{Key: "n", Value: 3}, bson.E{Key: "nModified", Value: 2}),
+			mtest.CreateSuccessResponse(bson.E{Key: "n", Value: 1}),
+			mtest.CreateSuccessResponse(bson.E{Key: "n", Value: 2}, bson.E{Key: "nModified", Value: 2}),
+		)
+
+		assert.NoError(mt, s.SetCurrent("prod"))
+		assert.ErrorIs(mt, s.SetCurrent("staging"), ErrWorkspaceNotExist)
+
+		// deleting the current workspace sets the default workspace as the current one
+		assert.NoError(mt, s.Delete("prod"))
+		names, err := s.GetNames()
+		assert.NoError(mt, err)
+		assert.Equal(mt, []string{"default", "dev"}, names)
+		current, err := s.GetCurrent()
+		assert.NoError(mt, err)
+		assert.Equal(mt, DefaultWorkspace, current)
+
+		// the current workspace is written by an update of all the workspaces
+		var updates []bson.Raw
+		for _, event := range mt.GetAllStartedEvents() {
+			if event.CommandName == "update" {
+				updates = append(updates, event.Command)
+			}
+		}
+		require.Len(mt, updates, 2)
+		assert.Contains(mt, updates[1].Lookup("updates").String(), `"default"`)
+	})
+}
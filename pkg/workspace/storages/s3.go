@@ -11,6 +11,7 @@ import (
 	"gopkg.in/yaml.v3"
 
 	v1 "kusionstack.io/kusion/pkg/apis/api.kusion.io/v1"
+	"kusionstack.io/kusion/pkg/workspace"
 )
 
 // S3Storage is an implementation of workspace.Storage which uses s3 as storage.
@@ -62,15 +63,13 @@ func (s *S3Storage) Get(name string) (*v1.Workspace, error) {
 		return nil, fmt.Errorf("read workspace failed: %w", err)
 	}
 
-	ws := &v1.Workspace{}
-	if err = yaml.Unmarshal(content, ws); err != nil {
-		return nil, fmt.Errorf("yaml unmarshal workspace failed: %w", err)
-	}
-	ws.Name = name
-	return ws, nil
+	return workspace.UnmarshalWorkspace(content, name)
 }
 
 func (s *S3Storage) Create(ws *v1.Workspace) error {
+	if ws.GetName() == "" {
+		return workspace.ErrEmptyWorkspaceName
+	}
 	if checkWorkspaceExistence(s.meta, ws.Name) {
 		return ErrWorkspaceAlreadyExist
 	}
@@ -0,0 +1,275 @@
+package storages
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	v1 "kusionstack.io/kusion/pkg/apis/api.kusion.io/v1"
+	"kusionstack.io/kusion/pkg/workspace"
+)
+
+const (
+	// SQLDialectMysql is the dialect of MySQL, whose placeholder of the bind variables is "?".
+	SQLDialectMysql = "mysql"
+
+	// SQLDialectPostgres is the dialect of Postgres, whose placeholder of the bind variables is "$N".
+	SQLDialectPostgres = "postgres"
+
+	sqlWorkspaceTable = "workspaces"
+)
+
+// SQLStorage is an implementation of workspace.Storage which uses a relational database as storage.
+// The workspaces are stored in the table workspaces whose primary key is the workspace name, and the
+// current workspace is the one whose is_current is true.
+type SQLStorage struct {
+	db *sql.DB
+
+	// The dialect of the database, which decides the placeholder of the bind variables.
+	dialect string
+
+	meta *workspacesMetaData
+}
+
+// NewSQLStorage news sql workspace storage, creates the workspaces table if not exists, and init default
+// workspace. The db should be opened with a driver of the specified dialect.
+func NewSQLStorage(db *sql.DB, dialect string) (*SQLStorage, error) {
+	if dialect != SQLDialectMysql && dialect != SQLDialectPostgres {
+		return nil, fmt.Errorf("unsupported sql dialect %s, only %s and %s are supported", dialect, SQLDialectMysql, SQLDialectPostgres)
+	}
+	s := &SQLStorage{
+		db:      db,
+		dialect: dialect,
+	}
+
+	// create the workspaces table
+	if _, err := s.db.Exec(s.createTableStatement()); err != nil {
+		return nil, fmt.Errorf("create workspaces table failed: %w", err)
+	}
+	// read workspaces metadata
+	if err := s.readMeta(); err != nil {
+		return nil, err
+	}
+
+	return s, s.initDefaultWorkspaceIf()
+}
+
+func (s *SQLStorage) Get(name string) (*v1.Workspace, error) {
+	if name == "" {
+		name = s.meta.Current
+	}
+	if !checkWorkspaceExistence(s.meta, name) {
+		return nil, ErrWorkspaceNotExist
+	}
+
+	var content string
+	err := s.db.QueryRow(
+		s.rebind(fmt.Sprintf("SELECT content FROM %s WHERE name = ?", sqlWorkspaceTable)),
+		name,
+	).Scan(&content)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrWorkspaceNotExist
+	} else if err != nil {
+		return nil, fmt.Errorf("query workspace failed: %w", err)
+	}
+
+	return workspace.UnmarshalWorkspace([]byte(content), name)
+}
+
+func (s *SQLStorage) Create(ws *v1.Workspace) error {
+	if ws.GetName() == "" {
+		return workspace.ErrEmptyWorkspaceName
+	}
+	if checkWorkspaceExistence(s.meta, ws.Name) {
+		return ErrWorkspaceAlreadyExist
+	}
+
+	if err := s.insertWorkspace(ws); err != nil {
+		return err
+	}
+
+	addAvailableWorkspaces(s.meta, ws.Name)
+	return nil
+}
+
+func (s *SQLStorage) Update(ws *v1.Workspace) error {
+	if ws.Name == "" {
+		ws.Name = s.meta.Current
+	}
+	if !checkWorkspaceExistence(s.meta, ws.Name) {
+		return ErrWorkspaceNotExist
+	}
+
+	content, err := yaml.Marshal(ws)
+	if err != nil {
+		return fmt.Errorf("yaml marshal workspace failed: %w", err)
+	}
+
+	result, err := s.db.Exec(
+		s.rebind(fmt.Sprintf("UPDATE %s SET content = ? WHERE name = ?", sqlWorkspaceTable)),
+		string(content), ws.Name,
+	)
+	if err != nil {
+		return fmt.Errorf("update workspace failed: %w", err)
+	}
+	if affected, err := result.RowsAffected(); err == nil && affected == 0 {
+		return ErrWorkspaceNotExist
+	}
+	return nil
+}
+
+func (s *SQLStorage) Delete(name string) error {
+	if !checkWorkspaceExistence(s.meta, name) {
+		return nil
+	}
+
+	_, err := s.db.Exec(s.rebind(fmt.Sprintf("DELETE FROM %s WHERE name = ?", sqlWorkspaceTable)), name)
+	if err != nil {
+		return fmt.Errorf("delete workspace failed: %w", err)
+	}
+
+	current := s.meta.Current
+	removeAvailableWorkspaces(s.meta, name)
+	if s.meta.Current != current {
+		return s.writeCurrent(s.meta.Current)
+	}
+	return nil
+}
+
+func (s *SQLStorage) GetNames() ([]string, error) {
+	return s.meta.AvailableWorkspaces, nil
+}
+
+func (s *SQLStorage) GetCurrent() (string, error) {
+	return s.meta.Current, nil
+}
+
+func (s *SQLStorage) SetCurrent(name string) error {
+	if !checkWorkspaceExistence(s.meta, name) {
+		return ErrWorkspaceNotExist
+	}
+	if err := s.writeCurrent(name); err != nil {
+		return err
+	}
+	s.meta.Current = name
+	return nil
+}
+
+func (s *SQLStorage) initDefaultWorkspaceIf() error {
+	if !checkWorkspaceExistence(s.meta, DefaultWorkspace) {
+		// if there is no default workspace, create one with empty workspace.
+		if err := s.insertWorkspace(&v1.Workspace{Name: DefaultWorkspace}); err != nil {
+			return err
+		}
+		addAvailableWorkspaces(s.meta, DefaultWorkspace)
+	}
+
+	if s.meta.Current == "" {
+		return s.SetCurrent(DefaultWorkspace)
+	}
+	return nil
+}
+
+func (s *SQLStorage) readMeta() error {
+	rows, err := s.db.Query(fmt.Sprintf("SELECT name, is_current FROM %s ORDER BY name", sqlWorkspaceTable))
+	if err != nil {
+		return fmt.Errorf("query workspaces metadata failed: %w", err)
+	}
+	defer func() {
+		_ = rows.Close()
+	}()
+
+	meta := &workspacesMetaData{}
+	for rows.Next() {
+		var name string
+		var current bool
+		if err = rows.Scan(&name, &current); err != nil {
+			return fmt.Errorf("scan workspaces metadata failed: %w", err)
+		}
+		addAvailableWorkspaces(meta, name)
+		if current {
+			meta.Current = name
+		}
+	}
+	if err = rows.Err(); err != nil {
+		return fmt.Errorf("query workspaces metadata failed: %w", err)
+	}
+	s.meta = meta
+	return nil
+}
+
+func (s *SQLStorage) insertWorkspace(ws *v1.Workspace) error {
+	content, err := yaml.Marshal(ws)
+	if err != nil {
+		return fmt.Errorf("yaml marshal workspace failed: %w", err)
+	}
+
+	_, err = s.db.Exec(
+		s.rebind(fmt.Sprintf("INSERT INTO %s (name, content, is_current) VALUES (?, ?, ?)", sqlWorkspaceTable)),
+		ws.Name, string(content), false,
+	)
+	if err != nil {
+		// the insertion violating the primary key means the workspace has been created by others
+		exist, existErr := s.exist(ws.Name)
+		if existErr == nil && exist {
+			return ErrWorkspaceAlreadyExist
+		}
+		return fmt.Errorf("insert workspace failed: %w", err)
+	}
+	return nil
+}
+
+// writeCurrent marks the workspace as the current one and unmarks the others in a single statement.
+func (s *SQLStorage) writeCurrent(name string) error {
+	_, err := s.db.Exec(s.rebind(fmt.Sprintf("UPDATE %s SET is_current = (name = ?)", sqlWorkspaceTable)), name)
+	if err != nil {
+		return fmt.Errorf("update current workspace failed: %w", err)
+	}
+	return nil
+}
+
+func (s *SQLStorage) exist(name string) (bool, error) {
+	var count int
+	err := s.db.QueryRow(
+		s.rebind(fmt.Sprintf("SELECT COUNT(*) FROM %s WHERE name = ?", sqlWorkspaceTable)),
+		name,
+	).Scan(&count)
+	if err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
+
+func (s *SQLStorage) createTableStatement() string {
+	contentType := "TEXT"
+	if s.dialect == SQLDialectMysql {
+		contentType = "LONGTEXT"
+	}
+	return fmt.Sprintf(`CREATE TABLE IF NOT EXISTS %s (
+	name VARCHAR(255) NOT NULL,
+	content %s NOT NULL,
+	is_current BOOLEAN NOT NULL DEFAULT FALSE,
+	PRIMARY KEY (name)
+)`, sqlWorkspaceTable, contentType)
+}
+
+// rebind replaces the placeholders "?" in the query with "$N" for Postgres.
+func (s *SQLStorage) rebind(query string) string {
+	if s.dialect != SQLDialectPostgres {
+		return query
+	}
+	var b strings.Builder
+	n := 0
+	for _, c := range query {
+		if c == '?' {
+			n++
+			fmt.Fprintf(&b, "$%d", n)
+			continue
+		}
+		b.WriteRune(c)
+	}
+	return b.String()
+}
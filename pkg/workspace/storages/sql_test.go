@@ -0,0 +1,268 @@
+package storages
+
+import (
+	"errors"
+	"regexp"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	v1 "kusionstack.io/kusion/pkg/apis/api.kusion.io/v1"
+)
+
+func mockSQLMetaRows() *sqlmock.Rows {
+	return sqlmock.NewRows([]string{"name", "is_current"}).
+		AddRow("default", false).
+		AddRow("dev", true).
+		AddRow("prod", false)
+}
+
+func newMockSQLStorage(t *testing.T, dialect string) (*SQLStorage, sqlmock.Sqlmock) {
+	db, sqlMock, err := sqlmock.New()
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		_ = db.Close()
+	})
+
+	sqlMock.ExpectExec("CREATE TABLE IF NOT EXISTS workspaces").WillReturnResult(sqlmock.NewResult(0, 0))
+	sqlMock.ExpectQuery("SELECT name, is_current FROM workspaces").WillReturnRows(mockSQLMetaRows())
+	s, err := NewSQLStorage(db, dialect)
+	require.NoError(t, err)
+	return s, sqlMock
+}
+
+func TestNewSQLStorage(t *testing.T) {
+	testcases := []struct {
+		name         string
+		success      bool
+		dialect      string
+		metaRows     *sqlmock.Rows
+		expectedMeta *workspacesMetaData
+	}{
+		{
+			name:     "new sql storage and init default workspace",
+			success:  true,
+			dialect:  SQLDialectPostgres,
+			metaRows: sqlmock.NewRows([]string{"name", "is_current"}),
+			expectedMeta: &workspacesMetaData{
+				Current:             DefaultWorkspace,
+				AvailableWorkspaces: []string{DefaultWorkspace},
+			},
+		},
+		{
+			name:         "new sql storage with exist workspaces",
+			success:      true,
+			dialect:      SQLDialectMysql,
+			metaRows:     mockSQLMetaRows(),
+			expectedMeta: mockWorkspacesMetaData(),
+		},
+		{
+			name:    "failed to new sql storage with unsupported dialect",
+			success: false,
+			dialect: "sqlserver",
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			db, sqlMock, err := sqlmock.New()
+			require.NoError(t, err)
+			defer db.Close()
+			if tc.metaRows != nil {
+				sqlMock.ExpectExec("CREATE TABLE IF NOT EXISTS workspaces").WillReturnResult(sqlmock.NewResult(0, 0))
+				sqlMock.ExpectQuery("SELECT name, is_current FROM workspaces").WillReturnRows(tc.metaRows)
+			}
+			if tc.expectedMeta != nil && tc.expectedMeta.Current == DefaultWorkspace {
+				sqlMock.ExpectExec(regexp.QuoteMeta("INSERT INTO workspaces (name, content, is_current) VALUES ($1, $2, $3)")).
+					WithArgs(DefaultWorkspace, sqlmock.AnyArg(), false).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				sqlMock.ExpectExec(regexp.QuoteMeta("UPDATE workspaces SET is_current = (name = $1)")).
+					WithArgs(DefaultWorkspace).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+			}
+
+			s, err := NewSQLStorage(db, tc.dialect)
+			assert.Equal(t, tc.success, err == nil)
+			if tc.success {
+				assert.Equal(t, tc.expectedMeta, s.meta)
+			}
+			assert.NoError(t, sqlMock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestSQLStorage_Get(t *testing.T) {
+	testcases := []struct {
+		name              string
+		success           bool
+		wsName            string
+		content           string
+		expectedWorkspace *v1.Workspace
+	}{
+		{
+			name:              "get workspace successfully",
+			success:           true,
+			wsName:            "dev",
+			content:           mockWorkspaceContent(),
+			expectedWorkspace: mockWorkspace("dev"),
+		},
+		{
+			name:              "get current workspace successfully",
+			success:           true,
+			wsName:            "",
+			content:           mockWorkspaceContent(),
+			expectedWorkspace: mockWorkspace("dev"),
+		},
+		{
+			name:    "failed to get workspace not exist",
+			success: false,
+			wsName:  "staging",
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			s, sqlMock := newMockSQLStorage(t, SQLDialectMysql)
+			if tc.content != "" {
+				sqlMock.ExpectQuery(regexp.QuoteMeta("SELECT content FROM workspaces WHERE name = ?")).
+					WithArgs("dev").
+					WillReturnRows(sqlmock.NewRows([]string{"content"}).AddRow(tc.content))
+			}
+
+			ws, err := s.Get(tc.wsName)
+			assert.Equal(t, tc.success, err == nil)
+			assert.Equal(t, tc.expectedWorkspace, ws)
+			assert.NoError(t, sqlMock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestSQLStorage_Create(t *testing.T) {
+	testcases := []struct {
+		name        string
+		success     bool
+		wsName      string
+		insertErr   error
+		existCount  int
+		expectedErr error
+	}{
+		{
+			name:    "create workspace successfully",
+			success: true,
+			wsName:  "staging",
+		},
+		{
+			name:        "failed to create workspace already exist in metadata",
+			success:     false,
+			wsName:      "dev",
+			expectedErr: ErrWorkspaceAlreadyExist,
+		},
+		{
+			name:        "failed to create workspace created concurrently",
+			success:     false,
+			wsName:      "staging",
+			insertErr:   errors.New("duplicate key"),
+			existCount:  1,
+			expectedErr: ErrWorkspaceAlreadyExist,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			s, sqlMock := newMockSQLStorage(t, SQLDialectMysql)
+			if tc.wsName == "staging" {
+				insert := sqlMock.ExpectExec(regexp.QuoteMeta("INSERT INTO workspaces (name, content, is_current) VALUES (?, ?, ?)")).
+					WithArgs(tc.wsName, sqlmock.AnyArg(), false)
+				if tc.insertErr != nil {
+					insert.WillReturnError(tc.insertErr)
+					sqlMock.ExpectQuery(regexp.QuoteMeta("SELECT COUNT(*) FROM workspaces WHERE name = ?")).
+						WithArgs(tc.wsName).
+						WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(tc.existCount))
+				} else {
+					insert.WillReturnResult(sqlmock.NewResult(0, 1))
+				}
+			}
+
+			err := s.Create(mockWorkspace(tc.wsName))
+			assert.Equal(t, tc.success, err == nil)
+			if tc.expectedErr != nil {
+				assert.ErrorIs(t, err, tc.expectedErr)
+			}
+			if tc.success {
+				assert.Contains(t, s.meta.AvailableWorkspaces, tc.wsName)
+			}
+			assert.NoError(t, sqlMock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestSQLStorage_Update(t *testing.T) {
+	testcases := []struct {
+		name     string
+		success  bool
+		wsName   string
+		affected int64
+	}{
+		{
+			name:     "update workspace successfully",
+			success:  true,
+			wsName:   "dev",
+			affected: 1,
+		},
+		{
+			name:    "failed to update workspace not exist in metadata",
+			success: false,
+			wsName:  "staging",
+		},
+		{
+			name:     "failed to update workspace deleted by others",
+			success:  false,
+			wsName:   "prod",
+			affected: 0,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			s, sqlMock := newMockSQLStorage(t, SQLDialectPostgres)
+			if tc.wsName != "staging" {
+				sqlMock.ExpectExec(regexp.QuoteMeta("UPDATE workspaces SET content = $1 WHERE name = $2")).
+					WithArgs(sqlmock.AnyArg(), tc.wsName).
+					WillReturnResult(sqlmock.NewResult(0, tc.affected))
+			}
+
+			err := s.Update(mockWorkspace(tc.wsName))
+			assert.Equal(t, tc.success, err == nil)
+			assert.NoError(t, sqlMock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestSQLStorage_DeleteAndSetCurrent(t *testing.T) {
+	s, sqlMock := newMockSQLStorage(t, SQLDialectMysql)
+
+	sqlMock.ExpectExec(regexp.QuoteMeta("UPDATE workspaces SET is_current = (name = ?)")).
+		WithArgs("prod").
+		WillReturnResult(sqlmock.NewResult(0, 3))
+	assert.NoError(t, s.SetCurrent("prod"))
+	assert.ErrorIs(t, s.SetCurrent("staging"), ErrWorkspaceNotExist)
+
+	// deleting the current workspace sets the default workspace as the current one
+	sqlMock.ExpectExec(regexp.QuoteMeta("DELETE FROM workspaces WHERE name = ?")).
+		WithArgs("prod").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	sqlMock.ExpectExec(regexp.QuoteMeta("UPDATE workspaces SET is_current = (name = ?)")).
+		WithArgs(DefaultWorkspace).
+		WillReturnResult(sqlmock.NewResult(0, 2))
+	assert.NoError(t, s.Delete("prod"))
+
+	names, err := s.GetNames()
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"default", "dev"}, names)
+	current, err := s.GetCurrent()
+	assert.NoError(t, err)
+	assert.Equal(t, DefaultWorkspace, current)
+	assert.NoError(t, sqlMock.ExpectationsWereMet())
+}
@@ -1,10 +1,16 @@
 package storages
 
 import (
-	"errors"
 	"fmt"
 	"path/filepath"
+	"reflect"
 	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	v1 "kusionstack.io/kusion/pkg/apis/api.kusion.io/v1"
+	"kusionstack.io/kusion/pkg/util/kerrors"
+	"kusionstack.io/kusion/pkg/workspace"
 )
 
 const (
@@ -16,8 +22,8 @@ const (
 )
 
 var (
-	ErrWorkspaceNotExist     = errors.New("workspace does not exist")
-	ErrWorkspaceAlreadyExist = errors.New("workspace has already existed")
+	ErrWorkspaceNotExist     = kerrors.New(kerrors.ErrNotFound, "workspace does not exist")
+	ErrWorkspaceAlreadyExist = kerrors.New(kerrors.ErrAlreadyExists, "workspace has already existed")
 )
 
 // GenWorkspaceDirPath generates the workspace directory path, which is used for LocalStorage.
@@ -34,6 +40,164 @@ func GenGenericOssWorkspacePrefixKey(prefix string) string {
 	return fmt.Sprintf("%s%s", prefix, workspacesPrefix)
 }
 
+// GenHTTPWorkspaceURL generates the url of the workspaces, which is used for HTTPStorage.
+func GenHTTPWorkspaceURL(baseURL string) string {
+	return fmt.Sprintf("%s/%s", strings.TrimSuffix(baseURL, "/"), workspacesPrefix)
+}
+
+// marshalWorkspace marshals the workspace, and preserves the anchors, aliases, merge keys, comments and key
+// order of the previous content of the workspace where the values they carry are unchanged, so that
+// updating a hand-written workspace file does not expand its anchors. The previous content is returned
+// as it is if it holds the same workspace. If the previous content is empty or invalid, or the changed
+// values cannot be expressed by the previous anchors, the canonical content by yaml.Marshal is returned,
+// which is stable for the map keys are sorted.
+func marshalWorkspace(ws *v1.Workspace, previous []byte) ([]byte, error) {
+	content, err := yaml.Marshal(ws)
+	if err != nil {
+		return nil, fmt.Errorf("yaml marshal workspace failed: %w", err)
+	}
+	if len(previous) == 0 {
+		return content, nil
+	}
+
+	expected, err := workspace.UnmarshalWorkspace(content, ws.Name)
+	if err != nil {
+		return nil, err
+	}
+	if sameWorkspaceContent(previous, expected) {
+		return previous, nil
+	}
+
+	prevNode, nextNode := &yaml.Node{}, &yaml.Node{}
+	if err = yaml.Unmarshal(previous, prevNode); err != nil {
+		return content, nil
+	}
+	if err = yaml.Unmarshal(content, nextNode); err != nil {
+		return nil, fmt.Errorf("yaml unmarshal workspace failed: %w", err)
+	}
+	mergedNode := mergeYAMLNode(prevNode, nextNode)
+	tidyYAMLNode(mergedNode, collectYAMLAnchors(mergedNode, map[*yaml.Node]bool{}))
+	merged, err := yaml.Marshal(mergedNode)
+	if err != nil || !sameWorkspaceContent(merged, expected) {
+		return content, nil
+	}
+	return merged, nil
+}
+
+// sameWorkspaceContent returns the content holds the expected workspace or not.
+func sameWorkspaceContent(content []byte, expected *v1.Workspace) bool {
+	ws, err := workspace.UnmarshalWorkspace(content, expected.Name)
+	return err == nil && reflect.DeepEqual(ws, expected)
+}
+
+// mergeYAMLNode returns the previous node if it carries the same value as the next one, otherwise merges
+// the unchanged children of the previous node into the next one. The anchor of a changed node is dropped,
+// for its aliases carry the previous value.
+func mergeYAMLNode(prev, next *yaml.Node) *yaml.Node {
+	if sameYAMLValue(prev, next) {
+		return prev
+	}
+	if prev.Kind != next.Kind || prev.Kind == yaml.AliasNode {
+		return next
+	}
+
+	merged := *next
+	merged.HeadComment, merged.LineComment, merged.FootComment = prev.HeadComment, prev.LineComment, prev.FootComment
+	switch prev.Kind {
+	case yaml.DocumentNode:
+		if len(prev.Content) == 1 && len(next.Content) == 1 {
+			merged.Content = []*yaml.Node{mergeYAMLNode(prev.Content[0], next.Content[0])}
+		}
+	case yaml.SequenceNode:
+		if len(prev.Content) == len(next.Content) {
+			merged.Content = make([]*yaml.Node, len(next.Content))
+			for i := range next.Content {
+				merged.Content[i] = mergeYAMLNode(prev.Content[i], next.Content[i])
+			}
+		}
+	case yaml.MappingNode:
+		if hasYAMLMergeKey(prev) {
+			// the values of the merge keys cannot be split from the overridden ones
+			return next
+		}
+		merged.Content = mergeYAMLMapping(prev, next)
+	}
+	return &merged
+}
+
+// mergeYAMLMapping merges the key-value pairs of the mapping nodes in the key order of the previous node,
+// and appends the keys only in the next node.
+func mergeYAMLMapping(prev, next *yaml.Node) []*yaml.Node {
+	nextValues := make(map[string]*yaml.Node, len(next.Content)/2)
+	for i := 0; i+1 < len(next.Content); i += 2 {
+		nextValues[next.Content[i].Value] = next.Content[i+1]
+	}
+
+	content := make([]*yaml.Node, 0, len(next.Content))
+	merged := make(map[string]bool, len(nextValues))
+	for i := 0; i+1 < len(prev.Content); i += 2 {
+		key := prev.Content[i]
+		value, ok := nextValues[key.Value]
+		if !ok {
+			continue
+		}
+		content = append(content, key, mergeYAMLNode(prev.Content[i+1], value))
+		merged[key.Value] = true
+	}
+	for i := 0; i+1 < len(next.Content); i += 2 {
+		if !merged[next.Content[i].Value] {
+			content = append(content, next.Content[i], next.Content[i+1])
+		}
+	}
+	return content
+}
+
+func hasYAMLMergeKey(node *yaml.Node) bool {
+	for i := 0; i < len(node.Content); i += 2 {
+		if node.Content[i].Tag == "!!merge" {
+			return true
+		}
+	}
+	return false
+}
+
+// collectYAMLAnchors collects the anchored nodes of the node tree.
+func collectYAMLAnchors(node *yaml.Node, anchors map[*yaml.Node]bool) map[*yaml.Node]bool {
+	if node.Anchor != "" {
+		anchors[node] = true
+	}
+	for _, child := range node.Content {
+		collectYAMLAnchors(child, anchors)
+	}
+	return anchors
+}
+
+// tidyYAMLNode expands the aliases whose anchored nodes are dropped by mergeYAMLNode, and clears the tags
+// of the merge keys, which are emitted as "!!merge <<" otherwise.
+func tidyYAMLNode(node *yaml.Node, anchors map[*yaml.Node]bool) {
+	for i, child := range node.Content {
+		if child.Kind == yaml.AliasNode && !anchors[child.Alias] {
+			expanded := *child.Alias
+			expanded.Anchor = ""
+			child = &expanded
+			node.Content[i] = child
+		}
+		if child.Kind == yaml.ScalarNode && child.Tag == "!!merge" {
+			child.Tag = ""
+		}
+		tidyYAMLNode(child, anchors)
+	}
+}
+
+// sameYAMLValue returns the nodes decode to the same value or not, where the aliases are resolved.
+func sameYAMLValue(prev, next *yaml.Node) bool {
+	var prevValue, nextValue any
+	if prev.Decode(&prevValue) != nil || next.Decode(&nextValue) != nil {
+		return false
+	}
+	return reflect.DeepEqual(prevValue, nextValue)
+}
+
 // workspacesMetaData contains the name of current workspace and all workspaces, whose serialization
 // result contains in the metadataFile for LocalStorage, OssStorage and S3Storage.
 type workspacesMetaData struct {
@@ -1,9 +1,14 @@
 package storages
 
 import (
+	"os"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"gopkg.in/yaml.v3"
+
+	v1 "kusionstack.io/kusion/pkg/apis/api.kusion.io/v1"
+	"kusionstack.io/kusion/pkg/workspace"
 )
 
 func mockWorkspacesMetaData() *workspacesMetaData {
@@ -144,3 +149,204 @@ func TestRemoveAvailableWorkspaces(t *testing.T) {
 		})
 	}
 }
+
+func TestUnmarshalWorkspace(t *testing.T) {
+	testcases := []struct {
+		name              string
+		success           bool
+		content           string
+		wsName            string
+		expectedWorkspace *v1.Workspace
+	}{
+		{
+			name:              "unmarshal workspace with name successfully",
+			success:           true,
+			content:           mockWorkspaceContent(),
+			wsName:            "dev",
+			expectedWorkspace: mockWorkspace("dev"),
+		},
+		{
+			name:              "name field in content is ignored",
+			success:           true,
+			content:           "name: prod\n" + mockWorkspaceContent(),
+			wsName:            "dev",
+			expectedWorkspace: mockWorkspace("dev"),
+		},
+		{
+			name:              "unmarshal workspace failed invalid content",
+			success:           false,
+			content:           "modules: [",
+			wsName:            "dev",
+			expectedWorkspace: nil,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			ws, err := workspace.UnmarshalWorkspace([]byte(tc.content), tc.wsName)
+			assert.Equal(t, tc.success, err == nil)
+			assert.Equal(t, tc.expectedWorkspace, ws)
+		})
+	}
+}
+
+func mockAnchoredWorkspace(name string) *v1.Workspace {
+	return &v1.Workspace{
+		Name: name,
+		Modules: map[string]*v1.ModuleConfig{
+			"mysql": {
+				Path:    "ghcr.io/kusionstack/mysql",
+				Version: "0.1.0",
+				Configs: v1.Configs{
+					Default: v1.GenericConfig{
+						"type":         "aws",
+						"instanceType": "db.t3.micro",
+					},
+					ModulePatcherConfigs: v1.ModulePatcherConfigs{
+						"smallClass": {
+							GenericConfig: v1.GenericConfig{
+								"type":         "aws",
+								"instanceType": "db.t3.small",
+							},
+							ProjectSelector: []string{"foo", "bar"},
+						},
+					},
+				},
+			},
+			"postgres": {
+				Path:    "ghcr.io/kusionstack/postgres",
+				Version: "0.1.0",
+				Configs: v1.Configs{
+					Default: v1.GenericConfig{
+						"type":         "aws",
+						"instanceType": "db.t3.micro",
+					},
+				},
+			},
+		},
+		Context: v1.GenericConfig{
+			"kubernetes": v1.GenericConfig{
+				"config": "/etc/kubeconfig.yaml",
+			},
+		},
+	}
+}
+
+func TestUnmarshalWorkspace_Anchors(t *testing.T) {
+	content, err := os.ReadFile(testDataFolder("anchored_workspace.yaml"))
+	assert.NoError(t, err)
+
+	ws, err := workspace.UnmarshalWorkspace(content, "dev")
+	assert.NoError(t, err)
+	assert.Equal(t, mockAnchoredWorkspace("dev"), ws)
+
+	// the values of the aliases are independent of the anchored ones.
+	ws.Modules["postgres"].Configs.Default["instanceType"] = "db.t3.large"
+	assert.Equal(t, "db.t3.micro", ws.Modules["mysql"].Configs.Default["instanceType"])
+}
+
+func TestMarshalWorkspace(t *testing.T) {
+	previous, err := os.ReadFile(testDataFolder("anchored_workspace.yaml"))
+	assert.NoError(t, err)
+
+	testcases := []struct {
+		name             string
+		workspace        func() *v1.Workspace
+		previous         []byte
+		expectedContains []string
+		expectedMissing  []string
+	}{
+		{
+			name:      "marshal workspace without previous content",
+			workspace: func() *v1.Workspace { return mockAnchoredWorkspace("dev") },
+			previous:  nil,
+			expectedMissing: []string{
+				"&version",
+				"*database",
+			},
+		},
+		{
+			name:      "marshal workspace with invalid previous content",
+			workspace: func() *v1.Workspace { return mockAnchoredWorkspace("dev") },
+			previous:  []byte("modules: ["),
+			expectedMissing: []string{
+				"&version",
+				"*database",
+			},
+		},
+		{
+			name: "preserve anchors of unchanged values",
+			workspace: func() *v1.Workspace {
+				ws := mockAnchoredWorkspace("dev")
+				ws.Modules["postgres"].Configs.Default["instanceType"] = "db.t3.large"
+				return ws
+			},
+			previous: previous,
+			expectedContains: []string{
+				"# the shared configs of the databases",
+				"version: &version 0.1.0",
+				"version: *version",
+				"default: &database",
+				"<<: *database",
+				"instanceType: db.t3.large",
+			},
+			expectedMissing: []string{
+				"default: *database",
+				"!!merge",
+			},
+		},
+		{
+			name: "expand aliases of changed anchored values",
+			workspace: func() *v1.Workspace {
+				ws := mockAnchoredWorkspace("dev")
+				ws.Modules["mysql"].Version = "0.2.0"
+				return ws
+			},
+			previous: previous,
+			expectedContains: []string{
+				"version: 0.2.0",
+				"default: &database",
+				"<<: *database",
+			},
+			expectedMissing: []string{
+				"&version",
+				"*version",
+			},
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			ws := tc.workspace()
+			content, err := marshalWorkspace(ws, tc.previous)
+			assert.NoError(t, err)
+			for _, s := range tc.expectedContains {
+				assert.Contains(t, string(content), s)
+			}
+			for _, s := range tc.expectedMissing {
+				assert.NotContains(t, string(content), s)
+			}
+
+			// the marshaled content holds the workspace, and is stable on round-trip.
+			loaded, err := workspace.UnmarshalWorkspace(content, ws.Name)
+			assert.NoError(t, err)
+			canonical, err := yaml.Marshal(ws)
+			assert.NoError(t, err)
+			expected, err := workspace.UnmarshalWorkspace(canonical, ws.Name)
+			assert.NoError(t, err)
+			assert.Equal(t, expected, loaded)
+			remarshaled, err := marshalWorkspace(loaded, content)
+			assert.NoError(t, err)
+			assert.Equal(t, string(content), string(remarshaled))
+		})
+	}
+}
+
+func TestMarshalWorkspace_Unchanged(t *testing.T) {
+	previous, err := os.ReadFile(testDataFolder("anchored_workspace.yaml"))
+	assert.NoError(t, err)
+
+	content, err := marshalWorkspace(mockAnchoredWorkspace("dev"), previous)
+	assert.NoError(t, err)
+	assert.Equal(t, string(previous), string(content))
+}
@@ -1,6 +1,7 @@
 package workspace
 
 import (
+	"encoding/json"
 	"errors"
 	"fmt"
 
@@ -22,12 +23,12 @@ func GetProjectModuleConfigs(configs v1.ModuleConfigs, projectName string) (map[
 	projectConfigs := make(map[string]v1.GenericConfig)
 	for name, cfg := range configs {
 		moduleConfig, err := getProjectModuleConfig(cfg, projectName)
-		if moduleConfig == nil {
-			continue
-		}
 		if err != nil {
 			return nil, fmt.Errorf("%w, module name: %s", err, name)
 		}
+		if moduleConfig == nil {
+			continue
+		}
 		if len(moduleConfig) != 0 {
 			projectConfigs[name] = moduleConfig
 		}
@@ -50,36 +51,41 @@ func GetProjectModuleConfig(config *v1.ModuleConfig, projectName string) (v1.Gen
 }
 
 // getProjectModuleConfig gets the module config of a specified project without checking the correctness of project name.
+// The ${config.path} references in the config are resolved after merging the default and patcher configs.
 func getProjectModuleConfig(config *v1.ModuleConfig, projectName string) (v1.GenericConfig, error) {
-	projectCfg := config.Configs.Default
-	if len(projectCfg) == 0 {
-		projectCfg = make(v1.GenericConfig)
+	// copy the default config to keep it intact from the patcher configs of the project
+	projectCfg := make(v1.GenericConfig, len(config.Configs.Default))
+	for k, v := range config.Configs.Default {
+		projectCfg[k] = v
+	}
+
+	if _, cfg := matchPatcherConfig(config, projectName); cfg != nil {
+		for k, v := range cfg.GenericConfig {
+			if k == v1.ProjectSelectorField {
+				continue
+			}
+			projectCfg[k] = v
+		}
 	}
 
+	return ResolveConfigReferences(projectCfg)
+}
+
+// matchPatcherConfig returns the name and config of the patcher block whose projectSelector contains the
+// project, or "", nil if no patcher block selects the project.
+func matchPatcherConfig(config *v1.ModuleConfig, projectName string) (string, *v1.ModulePatcherConfig) {
 	for name, cfg := range config.Configs.ModulePatcherConfigs {
-		if name == v1.DefaultBlock {
+		if name == v1.DefaultBlock || cfg == nil {
 			continue
 		}
 		// check the project is assigned in the block or not.
-		var contain bool
 		for _, project := range cfg.ProjectSelector {
 			if projectName == project {
-				contain = true
-				break
-			}
-		}
-		if contain {
-			for k, v := range cfg.GenericConfig {
-				if k == v1.ProjectSelectorField {
-					continue
-				}
-				projectCfg[k] = v
+				return name, cfg
 			}
-			break
 		}
 	}
-
-	return projectCfg, nil
+	return "", nil
 }
 
 // GetInt32PointerFromGenericConfig returns the value of the key in config which should be of type int.
@@ -142,3 +148,56 @@ func GetStringMapFromGenericConfig(config v1.GenericConfig, key string) (map[str
 	}
 	return stringMap, nil
 }
+
+// GetTerraformBackend returns the Terraform state backend configured in the context. If not exist,
+// return nil, nil.
+func GetTerraformBackend(context v1.GenericConfig) (*v1.TerraformBackend, error) {
+	value, ok := context[v1.ContextKeyTerraformBackend]
+	if !ok || value == nil {
+		return nil, nil
+	}
+	data, err := json.Marshal(value)
+	if err != nil {
+		return nil, fmt.Errorf("the value of %s is invalid: %w", v1.ContextKeyTerraformBackend, err)
+	}
+	backend := &v1.TerraformBackend{}
+	if err = json.Unmarshal(data, backend); err != nil {
+		return nil, fmt.Errorf("the value of %s is invalid: %w", v1.ContextKeyTerraformBackend, err)
+	}
+	return backend, nil
+}
+
+// GetNamePolicyConfig returns the name policy configured in the context. If not exist, return nil, nil.
+func GetNamePolicyConfig(context v1.GenericConfig) (*v1.NamePolicyConfig, error) {
+	value, ok := context[v1.ContextKeyNamePolicy]
+	if !ok || value == nil {
+		return nil, nil
+	}
+	data, err := json.Marshal(value)
+	if err != nil {
+		return nil, fmt.Errorf("the value of %s is invalid: %w", v1.ContextKeyNamePolicy, err)
+	}
+	config := &v1.NamePolicyConfig{}
+	if err = json.Unmarshal(data, config); err != nil {
+		return nil, fmt.Errorf("the value of %s is invalid: %w", v1.ContextKeyNamePolicy, err)
+	}
+	return config, nil
+}
+
+// GetNamespaceStrategy returns the namespace strategy configured in the context. If not exist,
+// return nil, nil.
+func GetNamespaceStrategy(context v1.GenericConfig) (*v1.NamespaceStrategy, error) {
+	value, ok := context[v1.ContextKeyNamespaceStrategy]
+	if !ok || value == nil {
+		return nil, nil
+	}
+	data, err := json.Marshal(value)
+	if err != nil {
+		return nil, fmt.Errorf("the value of %s is invalid: %w", v1.ContextKeyNamespaceStrategy, err)
+	}
+	strategy := &v1.NamespaceStrategy{}
+	if err = json.Unmarshal(data, strategy); err != nil {
+		return nil, fmt.Errorf("the value of %s is invalid: %w", v1.ContextKeyNamespaceStrategy, err)
+	}
+	return strategy, nil
+}
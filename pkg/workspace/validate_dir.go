@@ -0,0 +1,77 @@
+package workspace
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// ValidationResult is the result of validating a workspace file.
+type ValidationResult struct {
+	// File is the path of the workspace file.
+	File string `yaml:"file" json:"file"`
+
+	// Workspace is the name of the workspace, which is the file name without the extension.
+	Workspace string `yaml:"workspace" json:"workspace"`
+
+	// Valid is true if the workspace file is loaded and passes the validation.
+	Valid bool `yaml:"valid" json:"valid"`
+
+	// Message is the reason why the workspace file is invalid, empty if valid.
+	Message string `yaml:"message,omitempty" json:"message,omitempty"`
+}
+
+// ValidateWorkspaceDir loads and validates each workspace file in the directory and its subdirectories by
+// ValidateWorkspace, and returns the results in the lexical order of the files. The files without the
+// .yaml or .yml extension, and the hidden files and directories such as the metadata file of the local
+// workspace storage and .github, are skipped. An invalid workspace file does not stop validating the others, and the error is returned
+// only if the directory cannot be read.
+func ValidateWorkspaceDir(dir string) ([]ValidationResult, error) {
+	var results []ValidationResult
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if strings.HasPrefix(d.Name(), ".") && path != dir {
+			if d.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if d.IsDir() {
+			return nil
+		}
+		ext := filepath.Ext(d.Name())
+		if ext != ".yaml" && ext != ".yml" {
+			return nil
+		}
+
+		result := ValidationResult{File: path, Workspace: strings.TrimSuffix(d.Name(), ext)}
+		if err = validateWorkspaceFile(path, result.Workspace); err != nil {
+			result.Message = err.Error()
+		} else {
+			result.Valid = true
+		}
+		results = append(results, result)
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("read workspace directory %s failed: %w", dir, err)
+	}
+	return results, nil
+}
+
+// validateWorkspaceFile loads the workspace of the name from the file, and validates it.
+func validateWorkspaceFile(path, name string) error {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("read workspace file failed: %w", err)
+	}
+	ws, err := UnmarshalWorkspace(content, name)
+	if err != nil {
+		return err
+	}
+	return ValidateWorkspace(ws)
+}
@@ -0,0 +1,39 @@
+package workspace
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateWorkspaceDir(t *testing.T) {
+	dir := filepath.Join("testdata", "validate_dir")
+	results, err := ValidateWorkspaceDir(dir)
+	require.NoError(t, err)
+	require.Len(t, results, 4)
+
+	// the non-YAML and hidden files and directories are skipped, and the invalid files do not stop the others
+	assert.Equal(t, ValidationResult{
+		File:      filepath.Join(dir, "bad_secret_store.yaml"),
+		Workspace: "bad_secret_store",
+		Message:   ErrEmptyAWSRegion.Error(),
+	}, results[0])
+	assert.Equal(t, filepath.Join(dir, "bad_yaml.yaml"), results[1].File)
+	assert.False(t, results[1].Valid)
+	assert.Contains(t, results[1].Message, "yaml unmarshal workspace failed")
+	assert.Equal(t, ValidationResult{
+		File:      filepath.Join(dir, "dev.yaml"),
+		Workspace: "dev",
+		Valid:     true,
+	}, results[2])
+	assert.Equal(t, ValidationResult{
+		File:      filepath.Join(dir, "prod", "prod.yml"),
+		Workspace: "prod",
+		Valid:     true,
+	}, results[3])
+
+	_, err = ValidateWorkspaceDir(filepath.Join("testdata", "not_exist"))
+	assert.Error(t, err)
+}
@@ -3,10 +3,16 @@ package workspace
 import (
 	"errors"
 	"fmt"
+	"net"
+	"net/url"
+	"sort"
+	"strings"
+	"text/template"
 
 	"github.com/google/uuid"
 
 	utilerrors "k8s.io/apimachinery/pkg/util/errors"
+	"k8s.io/apimachinery/pkg/util/validation"
 
 	v1 "kusionstack.io/kusion/pkg/apis/api.kusion.io/v1"
 )
@@ -31,11 +37,26 @@ var (
 	ErrEmptyVaultServer                     = errors.New("server address must be provided when using Hashicorp Vault")
 	ErrEmptyVaultURL                        = errors.New("vault url must be provided when using Azure KeyVault")
 	ErrEmptyTenantID                        = errors.New("azure tenant id must be provided when using Azure KeyVault")
+	ErrInvalidAzureAuthType                 = errors.New("invalid auth type of Azure KeyVault")
+	ErrInvalidAzureObjectType               = errors.New("invalid object type of Azure KeyVault")
 	ErrEmptyAlicloudRegion                  = errors.New("region must be provided when using Alicloud Secrets Manager")
+	ErrInvalidAlicloudEndpoint              = errors.New("endpoint of Alicloud Secrets Manager must be a host or URL")
 	ErrMissingProviderType                  = errors.New("must specify a provider type")
 	ErrInvalidViettelCloudProjectID         = errors.New("invalid format project id for ViettelCloud Secrets Manager")
+	ErrEmptyKubernetesNamespace             = errors.New("namespace must be provided when using Kubernetes Secrets")
+	ErrInvalidKubernetesNamespace           = errors.New("namespace of Kubernetes Secrets must be a DNS-1123 label")
+	ErrEmptyLocalSecretSource               = errors.New("dir or envPrefix must be provided when using Local secrets")
+	ErrEmptyTerraformBackendType            = errors.New("empty terraform backend type")
+	ErrInvalidTerraformBackendType          = errors.New("invalid terraform backend type")
+	ErrStackWorkspaceNotFound               = errors.New("workspace referenced by stack not found")
+	ErrInvalidNamespaceStrategyType         = errors.New("invalid namespace strategy type")
+	ErrEmptyNamespaceStrategyTemplate       = errors.New("empty template of the template namespace strategy")
+	ErrEmptyNamePolicyTemplate              = errors.New("empty template of the name policy")
 )
 
+// TerraformBackendTypes are the supported types of the Terraform state backend.
+var TerraformBackendTypes = []string{"s3", "gcs", "azurerm", "oss", "cos", "consul", "http", "kubernetes", "local"}
+
 // ValidateWorkspace is used to validate the workspace get or set in the storage.
 func ValidateWorkspace(ws *v1.Workspace) error {
 	if ws.Name == "" {
@@ -51,9 +72,106 @@ func ValidateWorkspace(ws *v1.Workspace) error {
 			return utilerrors.NewAggregate(allErrs)
 		}
 	}
+	if err := ValidateQuotas(ws.Quotas); err != nil {
+		return err
+	}
+	strategy, err := GetNamespaceStrategy(ws.Context)
+	if err != nil {
+		return err
+	}
+	if strategy != nil {
+		if err = ValidateNamespaceStrategy(strategy); err != nil {
+			return err
+		}
+	}
+	namePolicy, err := GetNamePolicyConfig(ws.Context)
+	if err != nil {
+		return err
+	}
+	if namePolicy != nil {
+		if err = ValidateNamePolicyConfig(namePolicy); err != nil {
+			return err
+		}
+	}
+	backend, err := GetTerraformBackend(ws.Context)
+	if err != nil {
+		return err
+	}
+	if backend != nil {
+		if err = ValidateTerraformBackend(backend); err != nil {
+			return err
+		}
+	}
 	return nil
 }
 
+// ValidateNamePolicyConfig validates the name policy has a valid template.
+func ValidateNamePolicyConfig(config *v1.NamePolicyConfig) error {
+	if config.Template == "" {
+		return ErrEmptyNamePolicyTemplate
+	}
+	if _, err := template.New("name").Parse(config.Template); err != nil {
+		return fmt.Errorf("invalid template of the name policy: %w", err)
+	}
+	return nil
+}
+
+// ValidateTerraformBackend validates the type of the Terraform state backend is supported.
+func ValidateTerraformBackend(backend *v1.TerraformBackend) error {
+	if backend.Type == "" {
+		return ErrEmptyTerraformBackendType
+	}
+	for _, t := range TerraformBackendTypes {
+		if backend.Type == t {
+			return nil
+		}
+	}
+	return fmt.Errorf("%w: %s, supported types are %v", ErrInvalidTerraformBackendType, backend.Type, TerraformBackendTypes)
+}
+
+// NamespaceStrategyTypes are the supported types of the namespace strategy.
+var NamespaceStrategyTypes = []v1.NamespaceStrategyType{
+	v1.NamespaceStrategyProject, v1.NamespaceStrategyProjectStack, v1.NamespaceStrategyWorkspace, v1.NamespaceStrategyTemplate,
+}
+
+// ValidateNamespaceStrategy validates the type of the namespace strategy is supported, and the template
+// strategy has a valid template.
+func ValidateNamespaceStrategy(strategy *v1.NamespaceStrategy) error {
+	switch strategy.Type {
+	case v1.NamespaceStrategyProject, v1.NamespaceStrategyProjectStack, v1.NamespaceStrategyWorkspace:
+		return nil
+	case v1.NamespaceStrategyTemplate:
+		if strategy.Template == "" {
+			return ErrEmptyNamespaceStrategyTemplate
+		}
+		if _, err := template.New("namespace").Parse(strategy.Template); err != nil {
+			return fmt.Errorf("invalid template of the namespace strategy: %w", err)
+		}
+		return nil
+	default:
+		return fmt.Errorf("%w: %s, supported types are %v", ErrInvalidNamespaceStrategyType, strategy.Type, NamespaceStrategyTypes)
+	}
+}
+
+// ValidateStackWorkspace validates the workspace referenced by the stack exists in the storage, and returns
+// an error with the available workspace names if not. A stack without workspace reference is valid.
+func ValidateStackWorkspace(stack *v1.Stack, storage Storage) error {
+	if stack == nil || stack.Workspace == "" {
+		return nil
+	}
+	names, err := storage.GetNames()
+	if err != nil {
+		return err
+	}
+	for _, name := range names {
+		if name == stack.Workspace {
+			return nil
+		}
+	}
+	sort.Strings(names)
+	return fmt.Errorf("%w: %s, stack: %s, available workspaces are %v", ErrStackWorkspaceNotFound, stack.Workspace, stack.Name, names)
+}
+
 // ValidateModuleConfigs validates the moduleConfigs is valid or not.
 func ValidateModuleConfigs(configs v1.ModuleConfigs) error {
 	for name, cfg := range configs {
@@ -197,6 +315,22 @@ func ValidateSecretStoreConfig(spec *v1.SecretStore) []error {
 			allErrs = append(allErrs, validateViettelCloudSecretStore(spec.Provider.ViettelCloud)...)
 		}
 	}
+	if spec.Provider.Kubernetes != nil {
+		if numProviders > 0 {
+			allErrs = append(allErrs, ErrMultiSecretStoreProviders)
+		} else {
+			numProviders++
+			allErrs = append(allErrs, validateKubernetesSecretStore(spec.Provider.Kubernetes)...)
+		}
+	}
+	if spec.Provider.Local != nil {
+		if numProviders > 0 {
+			allErrs = append(allErrs, ErrMultiSecretStoreProviders)
+		} else {
+			numProviders++
+			allErrs = append(allErrs, validateLocalSecretStore(spec.Provider.Local)...)
+		}
+	}
 
 	if numProviders == 0 {
 		allErrs = append(allErrs, ErrMissingProviderType)
@@ -226,8 +360,26 @@ func validateAzureKeyVaultSecretStore(azureKv *v1.AzureKVProvider) []error {
 	if azureKv.VaultURL == nil || len(*azureKv.VaultURL) == 0 {
 		allErrs = append(allErrs, ErrEmptyVaultURL)
 	}
-	if azureKv.TenantID == nil || len(*azureKv.TenantID) == 0 {
-		allErrs = append(allErrs, ErrEmptyTenantID)
+	authType := v1.AzureAuthClientSecret
+	if azureKv.Auth != nil && azureKv.Auth.Type != "" {
+		authType = azureKv.Auth.Type
+	}
+	switch authType {
+	case v1.AzureAuthClientSecret, v1.AzureAuthWorkloadIdentity:
+		if azureKv.TenantID == nil || len(*azureKv.TenantID) == 0 {
+			allErrs = append(allErrs, ErrEmptyTenantID)
+		}
+	case v1.AzureAuthManagedIdentity:
+		// the tenant of the managed identity is implied
+	default:
+		allErrs = append(allErrs, fmt.Errorf("%w: %s, supported types are %v", ErrInvalidAzureAuthType, authType,
+			[]v1.AzureAuthType{v1.AzureAuthClientSecret, v1.AzureAuthManagedIdentity, v1.AzureAuthWorkloadIdentity}))
+	}
+	switch azureKv.ObjectType {
+	case "", v1.AzureKVObjectSecret, v1.AzureKVObjectCertificate, v1.AzureKVObjectKey:
+	default:
+		allErrs = append(allErrs, fmt.Errorf("%w: %s, supported types are %v", ErrInvalidAzureObjectType, azureKv.ObjectType,
+			[]v1.AzureKVObjectType{v1.AzureKVObjectSecret, v1.AzureKVObjectCertificate, v1.AzureKVObjectKey}))
 	}
 	return allErrs
 }
@@ -237,9 +389,31 @@ func validateAlicloudSecretStore(ac *v1.AlicloudProvider) []error {
 	if len(ac.Region) == 0 {
 		allErrs = append(allErrs, ErrEmptyAlicloudRegion)
 	}
+	if ac.Endpoint != "" && !isHostOrURL(ac.Endpoint) {
+		allErrs = append(allErrs, ErrInvalidAlicloudEndpoint)
+	}
 	return allErrs
 }
 
+// isHostOrURL returns whether the endpoint is a host with an optional port, or a http(s) URL with a host.
+func isHostOrURL(endpoint string) bool {
+	host := endpoint
+	if strings.Contains(endpoint, "://") {
+		u, err := url.Parse(endpoint)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") {
+			return false
+		}
+		host = u.Host
+	}
+	if h, _, err := net.SplitHostPort(host); err == nil {
+		host = h
+	}
+	if net.ParseIP(host) != nil {
+		return true
+	}
+	return len(validation.IsDNS1123Subdomain(host)) == 0
+}
+
 func validateViettelCloudSecretStore(vc *v1.ViettelCloudProvider) []error {
 	var allErrs []error
 	if vc.ProjectID != "" {
@@ -249,3 +423,21 @@ func validateViettelCloudSecretStore(vc *v1.ViettelCloudProvider) []error {
 	}
 	return allErrs
 }
+
+func validateKubernetesSecretStore(k8s *v1.KubernetesProvider) []error {
+	var allErrs []error
+	if len(k8s.Namespace) == 0 {
+		allErrs = append(allErrs, ErrEmptyKubernetesNamespace)
+	} else if len(validation.IsDNS1123Label(k8s.Namespace)) != 0 {
+		allErrs = append(allErrs, ErrInvalidKubernetesNamespace)
+	}
+	return allErrs
+}
+
+func validateLocalSecretStore(local *v1.LocalProvider) []error {
+	var allErrs []error
+	if len(local.Dir) == 0 && len(local.EnvPrefix) == 0 {
+		allErrs = append(allErrs, ErrEmptyLocalSecretSource)
+	}
+	return allErrs
+}
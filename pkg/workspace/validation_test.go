@@ -1,6 +1,7 @@
 package workspace
 
 import (
+	"errors"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -205,6 +206,97 @@ func TestValidateWorkspace(t *testing.T) {
 			success:   false,
 			workspace: &v1.Workspace{},
 		},
+		{
+			name:    "valid workspace with terraform backend",
+			success: true,
+			workspace: func() *v1.Workspace {
+				ws := mockValidWorkspace("dev")
+				ws.Context = v1.GenericConfig{
+					v1.ContextKeyTerraformBackend: map[string]any{
+						"type":   "s3",
+						"config": map[string]any{"bucket": "kusion-state", "region": "us-east-1"},
+					},
+				}
+				return ws
+			}(),
+		},
+		{
+			name:    "valid workspace with name policy",
+			success: true,
+			workspace: func() *v1.Workspace {
+				ws := mockValidWorkspace("dev")
+				ws.Context = v1.GenericConfig{
+					v1.ContextKeyNamePolicy: map[string]any{"template": "{{ .Project }}-{{ .Name }}"},
+				}
+				return ws
+			}(),
+		},
+		{
+			name:    "invalid workspace empty name policy template",
+			success: false,
+			workspace: func() *v1.Workspace {
+				ws := mockValidWorkspace("dev")
+				ws.Context = v1.GenericConfig{
+					v1.ContextKeyNamePolicy: map[string]any{"template": ""},
+				}
+				return ws
+			}(),
+		},
+		{
+			name:    "invalid workspace unknown terraform backend type",
+			success: false,
+			workspace: func() *v1.Workspace {
+				ws := mockValidWorkspace("dev")
+				ws.Context = v1.GenericConfig{
+					v1.ContextKeyTerraformBackend: map[string]any{"type": "s4"},
+				}
+				return ws
+			}(),
+		},
+		{
+			name:    "valid workspace with namespace strategy",
+			success: true,
+			workspace: func() *v1.Workspace {
+				ws := mockValidWorkspace("dev")
+				ws.Context = v1.GenericConfig{
+					v1.ContextKeyNamespaceStrategy: map[string]any{"type": "template", "template": "{{ .Project }}-{{ .Stack }}"},
+				}
+				return ws
+			}(),
+		},
+		{
+			name:    "invalid workspace unknown namespace strategy type",
+			success: false,
+			workspace: func() *v1.Workspace {
+				ws := mockValidWorkspace("dev")
+				ws.Context = v1.GenericConfig{
+					v1.ContextKeyNamespaceStrategy: map[string]any{"type": "label"},
+				}
+				return ws
+			}(),
+		},
+		{
+			name:    "invalid workspace namespace strategy without template",
+			success: false,
+			workspace: func() *v1.Workspace {
+				ws := mockValidWorkspace("dev")
+				ws.Context = v1.GenericConfig{
+					v1.ContextKeyNamespaceStrategy: map[string]any{"type": "template"},
+				}
+				return ws
+			}(),
+		},
+		{
+			name:    "invalid workspace empty terraform backend type",
+			success: false,
+			workspace: func() *v1.Workspace {
+				ws := mockValidWorkspace("dev")
+				ws.Context = v1.GenericConfig{
+					v1.ContextKeyTerraformBackend: map[string]any{"config": map[string]any{}},
+				}
+				return ws
+			}(),
+		},
 	}
 
 	for _, tc := range testcases {
@@ -215,6 +307,65 @@ func TestValidateWorkspace(t *testing.T) {
 	}
 }
 
+// fakeNamesStorage is a workspace storage which only supports getting the workspace names.
+type fakeNamesStorage struct {
+	Storage
+	names []string
+	err   error
+}
+
+func (s *fakeNamesStorage) GetNames() ([]string, error) {
+	return s.names, s.err
+}
+
+func TestValidateStackWorkspace(t *testing.T) {
+	storage := &fakeNamesStorage{names: []string{"prod", "dev"}}
+	testcases := []struct {
+		name        string
+		success     bool
+		stack       *v1.Stack
+		storage     Storage
+		expectedErr string
+	}{
+		{
+			name:    "existing workspace reference",
+			success: true,
+			stack:   &v1.Stack{Name: "dev", Workspace: "dev"},
+			storage: storage,
+		},
+		{
+			name:    "empty workspace reference",
+			success: true,
+			stack:   &v1.Stack{Name: "dev"},
+			storage: storage,
+		},
+		{
+			name:        "missing workspace reference",
+			success:     false,
+			stack:       &v1.Stack{Name: "dev", Workspace: "deev"},
+			storage:     storage,
+			expectedErr: "workspace referenced by stack not found: deev, stack: dev, available workspaces are [dev prod]",
+		},
+		{
+			name:        "failed to get workspace names",
+			success:     false,
+			stack:       &v1.Stack{Name: "dev", Workspace: "dev"},
+			storage:     &fakeNamesStorage{err: errors.New("backend unavailable")},
+			expectedErr: "backend unavailable",
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := ValidateStackWorkspace(tc.stack, tc.storage)
+			assert.Equal(t, tc.success, err == nil)
+			if !tc.success {
+				assert.EqualError(t, err, tc.expectedErr)
+			}
+		})
+	}
+}
+
 func TestValidateModuleConfigs(t *testing.T) {
 	testcases := []struct {
 		name          string
@@ -393,6 +544,26 @@ func TestValidateAzureKeyVaultSecretStore(t *testing.T) {
 			},
 			want: []error{ErrEmptyVaultURL, ErrEmptyTenantID},
 		},
+		{
+			name: "valid Azure KV provider spec with managed identity without tenant",
+			args: args{
+				azureKv: &v1.AzureKVProvider{
+					VaultURL: &vaultURL,
+					Auth:     &v1.AzureKVAuth{Type: v1.AzureAuthManagedIdentity},
+				},
+			},
+			want: nil,
+		},
+		{
+			name: "invalid Azure KV provider spec with workload identity without tenant",
+			args: args{
+				azureKv: &v1.AzureKVProvider{
+					VaultURL: &vaultURL,
+					Auth:     &v1.AzureKVAuth{Type: v1.AzureAuthWorkloadIdentity},
+				},
+			},
+			want: []error{ErrEmptyTenantID},
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -426,6 +597,46 @@ func TestValidateAlicloudSecretStore(t *testing.T) {
 			},
 			want: []error{ErrEmptyAlicloudRegion},
 		},
+		{
+			name: "valid Alicloud provider spec with endpoint host",
+			args: args{
+				ac: &v1.AlicloudProvider{
+					Region:   "cn-beijing",
+					Endpoint: "kms-vpc.cn-beijing.aliyuncs.com",
+				},
+			},
+			want: nil,
+		},
+		{
+			name: "valid Alicloud provider spec with endpoint URL",
+			args: args{
+				ac: &v1.AlicloudProvider{
+					Region:   "cn-beijing",
+					Endpoint: "https://10.0.0.1:443",
+				},
+			},
+			want: nil,
+		},
+		{
+			name: "invalid Alicloud provider spec with endpoint",
+			args: args{
+				ac: &v1.AlicloudProvider{
+					Region:   "cn-beijing",
+					Endpoint: "kms vpc/cn-beijing",
+				},
+			},
+			want: []error{ErrInvalidAlicloudEndpoint},
+		},
+		{
+			name: "invalid Alicloud provider spec with endpoint scheme",
+			args: args{
+				ac: &v1.AlicloudProvider{
+					Region:   "cn-beijing",
+					Endpoint: "ftp://kms.cn-beijing.aliyuncs.com",
+				},
+			},
+			want: []error{ErrInvalidAlicloudEndpoint},
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -434,6 +645,81 @@ func TestValidateAlicloudSecretStore(t *testing.T) {
 	}
 }
 
+func TestValidateKubernetesSecretStore(t *testing.T) {
+	type args struct {
+		k8s *v1.KubernetesProvider
+	}
+	tests := []struct {
+		name string
+		args args
+		want []error
+	}{
+		{
+			name: "valid Kubernetes provider spec",
+			args: args{
+				k8s: &v1.KubernetesProvider{
+					Namespace: "secrets",
+				},
+			},
+			want: nil,
+		},
+		{
+			name: "empty namespace",
+			args: args{
+				k8s: &v1.KubernetesProvider{},
+			},
+			want: []error{ErrEmptyKubernetesNamespace},
+		},
+		{
+			name: "invalid namespace",
+			args: args{
+				k8s: &v1.KubernetesProvider{
+					Namespace: "Secrets",
+				},
+			},
+			want: []error{ErrInvalidKubernetesNamespace},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equalf(t, tt.want, validateKubernetesSecretStore(tt.args.k8s), "validateKubernetesSecretStore(%v)", tt.args.k8s)
+		})
+	}
+}
+
+func TestValidateLocalSecretStore(t *testing.T) {
+	type args struct {
+		local *v1.LocalProvider
+	}
+	tests := []struct {
+		name string
+		args args
+		want []error
+	}{
+		{
+			name: "valid Local provider spec",
+			args: args{
+				local: &v1.LocalProvider{
+					Dir: ".secrets",
+				},
+			},
+			want: nil,
+		},
+		{
+			name: "empty source",
+			args: args{
+				local: &v1.LocalProvider{},
+			},
+			want: []error{ErrEmptyLocalSecretSource},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equalf(t, tt.want, validateLocalSecretStore(tt.args.local), "validateLocalSecretStore(%v)", tt.args.local)
+		})
+	}
+}
+
 func TestValidateSecretStoreConfig(t *testing.T) {
 	type args struct {
 		spec *v1.SecretStore